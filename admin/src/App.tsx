@@ -8,6 +8,8 @@ import { MappingsPage } from './pages/MappingsPage';
 import { MessagesPage } from './pages/MessagesPage';
 import { UsersPage } from './pages/UsersPage';
 import { SessionsPage } from './pages/SessionsPage';
+import { NotificationsPage } from './pages/NotificationsPage';
+import { ChangePasswordPage } from './pages/ChangePasswordPage';
 
 function ProtectedLayout() {
   return (
@@ -30,6 +32,8 @@ export default function App() {
           <Route path="/users" element={<UsersPage />} />
           <Route path="/mappings" element={<MappingsPage />} />
           <Route path="/messages" element={<MessagesPage />} />
+          <Route path="/notifications" element={<NotificationsPage />} />
+          <Route path="/password" element={<ChangePasswordPage />} />
         </Route>
 
         <Route path="*" element={<Navigate to="/" replace />} />
@@ -1,6 +1,6 @@
 import React from 'react';
 import { Link, useLocation, useNavigate } from 'react-router-dom';
-import { LayoutDashboard, Plug, Building2, Users, Link as LinkIcon, MessageSquare, LogOut } from 'lucide-react';
+import { LayoutDashboard, Plug, Building2, Users, Link as LinkIcon, MessageSquare, Bell, KeyRound, LogOut } from 'lucide-react';
 import { cn } from '../lib/utils';
 import { api } from '../lib/api';
 import { Button } from './ui/button';
@@ -29,6 +29,8 @@ export function Layout({ children }: LayoutProps) {
     { href: '/users', label: '포털 관리자', icon: Users },
     { href: '/mappings', label: '연결 관리', icon: LinkIcon },
     { href: '/messages', label: '메시지', icon: MessageSquare },
+    { href: '/notifications', label: '알림', icon: Bell },
+    { href: '/password', label: '비밀번호 변경', icon: KeyRound },
   ];
 
   return (
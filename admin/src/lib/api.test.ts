@@ -50,6 +50,73 @@ describe('Admin API', () => {
     });
   });
 
+  describe('bootstrap', () => {
+    test('should POST setup token and password to /admin/api/bootstrap', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ bootstrapRequired: false, name: 'relay' }), { status: 200 })
+      );
+
+      const result = await api.bootstrap({ setupToken: 'tok', password: 'long enough password', deploymentName: 'relay' });
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/bootstrap');
+      expect(options.method).toBe('POST');
+      expect(JSON.parse(options.body)).toEqual({ setupToken: 'tok', password: 'long enough password', deploymentName: 'relay' });
+      expect(result.bootstrapRequired).toBe(false);
+    });
+  });
+
+  describe('changePassword', () => {
+    test('should POST current and new password to /admin/api/password', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true }), { status: 200 })
+      );
+
+      await api.changePassword({ currentPassword: 'old password 123', newPassword: 'new password 456' });
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/password');
+      expect(options.method).toBe('POST');
+      expect(JSON.parse(options.body)).toEqual({ currentPassword: 'old password 123', newPassword: 'new password 456' });
+    });
+
+    test('should throw on incorrect current password', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ error: 'Current password is incorrect' }), { status: 403 })
+      );
+
+      await expect(
+        api.changePassword({ currentPassword: 'wrong', newPassword: 'new password 456' })
+      ).rejects.toThrow('Current password is incorrect');
+    });
+  });
+
+  describe('admin sessions', () => {
+    test('should list admin sessions', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ items: [{ id: 's1', current: true }], total: 1 }), { status: 200 })
+      );
+
+      const result = await api.getAdminSessions();
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/admin-sessions');
+      expect(result.items[0].current).toBe(true);
+    });
+
+    test('should DELETE an admin session', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true }), { status: 200 })
+      );
+
+      await api.revokeAdminSession('s1');
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/admin-sessions/s1');
+      expect(options.method).toBe('DELETE');
+    });
+  });
+
   describe('getStats', () => {
     test('should call /admin/api/stats', async () => {
       const mockStats = {
@@ -139,12 +206,35 @@ describe('Admin API', () => {
         new Response(JSON.stringify({ success: true }), { status: 200 })
       );
 
-      await api.deleteAccount('1');
+      await api.deleteAccount('1', 'tok');
 
       expect(mockFetch).toHaveBeenCalledTimes(1);
       const [url, options] = mockFetch.mock.calls[0];
       expect(url).toBe('/admin/api/accounts/1');
       expect(options.method).toBe('DELETE');
+      expect(JSON.parse(options.body)).toEqual({ previewToken: 'tok' });
+    });
+  });
+
+  describe('importAccountConfig', () => {
+    test('should PUT the exported config to /admin/api/accounts/:id/config', async () => {
+      const config = {
+        version: 1,
+        exportedAt: '2026-01-01T00:00:00Z',
+        settings: { mode: 'relay' as const, rateLimitPerMinute: 60, kakaoChannelId: null },
+        labels: [{ conversationKey: 'ch:u1', nickname: 'Alice' }],
+      };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ settingsApplied: true, labelsApplied: 1, labelsSkipped: [] }), { status: 200 })
+      );
+
+      const result = await api.importAccountConfig('acc-1', config);
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/accounts/acc-1/config');
+      expect(options.method).toBe('PUT');
+      expect(JSON.parse(options.body)).toEqual(config);
+      expect(result.labelsApplied).toBe(1);
     });
   });
 
@@ -193,6 +283,38 @@ describe('Admin API', () => {
     });
   });
 
+  describe('notifications', () => {
+    test('should call /admin/api/notifications with filters', async () => {
+      const mockResponse = { items: [], total: 0, unreadCount: 2 };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify(mockResponse), { status: 200 })
+      );
+
+      const result = await api.getNotifications(20, 0, { kind: 'job_failure', unread: true });
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/notifications?limit=20&offset=0&kind=job_failure&unread=true');
+      expect(result).toEqual(mockResponse);
+    });
+
+    test('should POST to mark notifications read', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true }), { status: 200 })
+      );
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true, count: 3 }), { status: 200 })
+      );
+
+      await api.markNotificationRead('n1');
+      await api.markAllNotificationsRead();
+
+      expect(mockFetch.mock.calls[0][0]).toBe('/admin/api/notifications/n1/read');
+      expect(mockFetch.mock.calls[0][1].method).toBe('POST');
+      expect(mockFetch.mock.calls[1][0]).toBe('/admin/api/notifications/read-all');
+      expect(mockFetch.mock.calls[1][1].method).toBe('POST');
+    });
+  });
+
   describe('deleteMapping', () => {
     test('should call /admin/api/mappings/:id with DELETE', async () => {
       mockFetch.mockResolvedValueOnce(
@@ -236,6 +358,34 @@ describe('Admin API', () => {
     });
   });
 
+  describe('expireInboundMessages', () => {
+    test('should POST the filter to /admin/api/messages/inbound/expire', async () => {
+      const mockResponse = { expired: 3, byAccount: { account1: 3 }, dryRun: false };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify(mockResponse), { status: 200 })
+      );
+
+      const result = await api.expireInboundMessages({ accountId: 'account1', olderThan: '30m' });
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/messages/inbound/expire');
+      expect(options.method).toBe('POST');
+      expect(JSON.parse(options.body)).toEqual({ accountId: 'account1', olderThan: '30m' });
+      expect(result).toEqual(mockResponse);
+    });
+
+    test('should request a dry run', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ expired: 0, byAccount: {}, dryRun: true }), { status: 200 })
+      );
+
+      await api.expireInboundMessages({ accountId: 'account1' }, true);
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/messages/inbound/expire?dryRun=true');
+    });
+  });
+
   describe('getOutboundMessages', () => {
     test('should call /admin/api/messages/outbound', async () => {
       const mockResponse = { items: [], total: 0 };
@@ -317,6 +467,73 @@ describe('Admin API', () => {
     });
   });
 
+  describe('step-up re-authentication', () => {
+    test('should prompt for the password, re-authenticate and retry', async () => {
+      const originalWindow = globalThis.window;
+      globalThis.window = { location: { href: '' }, prompt: () => 'admin-password' } as any;
+
+      mockFetch
+        .mockResolvedValueOnce(
+          new Response(JSON.stringify({ error: 'Re-authentication required', code: 'REAUTH_REQUIRED' }), { status: 403 })
+        )
+        .mockResolvedValueOnce(new Response(JSON.stringify({ success: true }), { status: 200 }))
+        .mockResolvedValueOnce(new Response(JSON.stringify({ success: true }), { status: 200 }));
+
+      await api.deleteUser('1');
+
+      expect(mockFetch).toHaveBeenCalledTimes(3);
+      const [reauthUrl, reauthOptions] = mockFetch.mock.calls[1];
+      expect(reauthUrl).toBe('/admin/api/reauth');
+      expect(JSON.parse(reauthOptions.body)).toEqual({ password: 'admin-password' });
+      expect(mockFetch.mock.calls[2][0]).toBe('/admin/api/users/1');
+
+      globalThis.window = originalWindow;
+    });
+
+    test('should not retry when the prompt is cancelled', async () => {
+      const originalWindow = globalThis.window;
+      globalThis.window = { location: { href: '' }, prompt: () => null } as any;
+
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ error: 'Re-authentication required', code: 'REAUTH_REQUIRED' }), { status: 403 })
+      );
+
+      await expect(api.regenerateToken('1')).rejects.toThrow('Re-authentication required');
+      expect(mockFetch).toHaveBeenCalledTimes(1);
+
+      globalThis.window = originalWindow;
+    });
+  });
+
+  describe('revokePairingCode', () => {
+    test('should call /admin/api/pairing/codes/:code with DELETE', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true }), { status: 200 })
+      );
+
+      await api.revokePairingCode('ABCD-EFGH');
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/pairing/codes/ABCD-EFGH');
+      expect(options.method).toBe('DELETE');
+    });
+  });
+
+  describe('forcePairSession', () => {
+    test('should POST conversation key to /admin/api/pairing/sessions/:id/pair', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ id: 's1', status: 'paired' }), { status: 200 })
+      );
+
+      await api.forcePairSession('s1', 'ch:user');
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/admin/api/pairing/sessions/s1/pair');
+      expect(options.method).toBe('POST');
+      expect(JSON.parse(options.body)).toEqual({ conversationKey: 'ch:user' });
+    });
+  });
+
   describe('error handling', () => {
     test('should redirect to login on 401', async () => {
       // Mock window.location
@@ -336,5 +553,25 @@ describe('Admin API', () => {
         globalThis.window.location = originalLocation;
       }
     });
+
+    test('should redirect to password change when rotation is required', async () => {
+      const originalLocation = globalThis.window?.location;
+      const mockLocation = { href: '' };
+      globalThis.window = { location: mockLocation } as any;
+
+      mockFetch.mockResolvedValueOnce(
+        new Response(
+          JSON.stringify({ error: 'Password change required', code: 'PASSWORD_CHANGE_REQUIRED' }),
+          { status: 403 }
+        )
+      );
+
+      await expect(api.getStats()).rejects.toThrow('Password change required');
+      expect(mockLocation.href).toBe('/admin/password');
+
+      if (originalLocation) {
+        globalThis.window.location = originalLocation;
+      }
+    });
   });
 });
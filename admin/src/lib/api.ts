@@ -10,6 +10,8 @@ export interface Account {
   rateLimitPerMinute: number;
   createdAt: string;
   updatedAt: string;
+  legalHoldAt?: string;
+  legalHoldReason?: string;
 }
 
 export interface Mapping {
@@ -17,9 +19,13 @@ export interface Mapping {
   accountId: string;
   conversationKey: string;
   kakaoUserId: string;
-  state: 'pending' | 'paired' | 'blocked';
+  state: 'pending' | 'paired' | 'archived' | 'blocked';
   createdAt: string;
   lastSeenAt: string | null;
+  kakaoNickname?: string;
+  kakaoProfileImageUrl?: string;
+  legalHoldAt?: string;
+  legalHoldReason?: string;
 }
 
 export interface InboundMessage {
@@ -33,16 +39,32 @@ export interface InboundMessage {
   deliveredAt: string | null;
 }
 
+export interface InboundExpiryFilter {
+  accountId?: string;
+  conversationKey?: string;
+  /** Go duration, e.g. "30m" */
+  olderThan?: string;
+}
+
+export interface InboundExpiryResult {
+  expired: number;
+  byAccount: Record<string, number>;
+  dryRun: boolean;
+}
+
 export interface OutboundMessage {
   id: string;
   accountId: string;
   conversationKey: string;
   messageType: string;
   content: string;
-  status: 'pending' | 'sent' | 'failed';
+  status: 'pending' | 'sent' | 'failed' | 'cancelled';
   createdAt: string;
   sentAt: string | null;
   errorMessage: string | null;
+  callbackStatus?: number;
+  callbackResponse?: string;
+  callbackTaskId?: string;
 }
 
 export interface PortalUser {
@@ -66,6 +88,114 @@ export interface PluginSession {
   updatedAt: string;
 }
 
+export interface PairingCode {
+  code: string;
+  accountId: string;
+  expiresAt: string;
+  metadata: Record<string, unknown> | null;
+  createdAt: string;
+}
+
+export interface AccountDeletionPreview {
+  accountId: string;
+  conversations: number;
+  inboundMessages: number;
+  outboundMessages: number;
+  pairingCodes: number;
+  sessions: number;
+  portalUsers: number;
+  oauthProviders: string[];
+  legalHold: boolean;
+  previewToken: string;
+  expiresAt: string;
+}
+
+export interface KakaoUserErasure {
+  mode: 'delete' | 'anonymize';
+  dryRun: boolean;
+  conversations: number;
+  accounts: number;
+  inboundMessages: number;
+  outboundMessages: number;
+  portalAccessCodes: number;
+  experimentExposures: number;
+  sessions: number;
+  heldConversations: number;
+}
+
+export interface AccountConfig {
+  version: number;
+  exportedAt: string;
+  settings: {
+    mode: 'direct' | 'relay';
+    rateLimitPerMinute: number;
+    kakaoChannelId: string | null;
+  };
+  labels: { conversationKey: string; nickname?: string; notes?: string }[];
+}
+
+export interface AccountSetting {
+  key: string;
+  value: unknown;
+  default: unknown;
+  isDefault: boolean;
+  // Only admins can change these settings
+  adminOnly: boolean;
+}
+
+export interface ChangeHistoryEntry {
+  id: string;
+  accountId: string | null;
+  entityType: 'account_setting' | 'conversation_state';
+  // Setting key or conversation key
+  entityKey: string;
+  // null: the setting had its default, or the conversation did not exist
+  oldValue: unknown;
+  newValue: unknown;
+  // "system", "admin", "kakao_user", "portal_user:<id>" or "plugin_session:<id>"
+  actor: string;
+  changedAt: string;
+}
+
+export interface AdminNotification {
+  id: string;
+  kind: 'anomaly' | 'job_failure' | 'quota_breach' | 'migration_pending';
+  // Alert name, e.g. "job_failing" or "rate_limit_exceeded"
+  name: string;
+  // What the alert is about (job name, account ID); empty for server-wide alerts
+  subject: string;
+  message: string;
+  details: Record<string, unknown> | null;
+  // Times the alert was raised while the notification was unread
+  occurrences: number;
+  createdAt: string;
+  lastOccurredAt: string;
+  resolvedAt: string | null;
+  readAt: string | null;
+}
+
+export interface AdminSession {
+  id: string;
+  ipAddress: string | null;
+  userAgent: string | null;
+  lastSeenAt: string;
+  expiresAt: string;
+  createdAt: string;
+  current: boolean;
+}
+
+export interface BootstrapStatus {
+  bootstrapRequired: boolean;
+  name?: string;
+  bootstrappedAt?: string;
+}
+
+export interface AccountConfigImportResult {
+  settingsApplied: boolean;
+  labelsApplied: number;
+  labelsSkipped: string[];
+}
+
 function getCSRFToken(): string | null {
   if (typeof document === 'undefined') return null;
   const match = document.cookie.match(/(?:^|; )csrf_token=([^;]*)/);
@@ -100,20 +230,57 @@ export async function fetchApi<T>(endpoint: string, options: RequestInit = {}):
 
   if (!res.ok) {
     let errorMessage = 'An error occurred';
+    let errorCode: string | undefined;
     try {
       const data = await res.json();
+      if (res.status === 403 && data.code === 'PASSWORD_CHANGE_REQUIRED') {
+        window.location.href = '/admin/password';
+      }
       if (typeof data.error === 'string') {
         errorMessage = data.error;
       }
+      if (typeof data.code === 'string') {
+        errorCode = data.code;
+      }
     } catch {
       // JSON 파싱 실패 시 기본 메시지 사용 (raw 텍스트 노출 방지)
     }
-    throw new Error(errorMessage);
+    throw new ApiRequestError(errorMessage, errorCode);
   }
 
   return res.json();
 }
 
+export class ApiRequestError extends Error {
+  code?: string;
+
+  constructor(message: string, code?: string) {
+    super(message);
+    this.name = 'ApiRequestError';
+    this.code = code;
+  }
+}
+
+/**
+ * Destructive actions need a recent password entry. When the server asks for
+ * re-authentication, prompt for the admin password once and retry.
+ */
+export async function withReauth<T>(request: () => Promise<T>): Promise<T> {
+  try {
+    return await request();
+  } catch (err) {
+    if (!(err instanceof ApiRequestError) || err.code !== 'REAUTH_REQUIRED') {
+      throw err;
+    }
+    const password = window.prompt('이 작업을 계속하려면 관리자 비밀번호를 다시 입력하세요.');
+    if (!password) {
+      throw err;
+    }
+    await api.reauth(password);
+    return request();
+  }
+}
+
 export const api = {
   login: (password: string) =>
     fetchApi<{ success: true }>('/admin/api/login', {
@@ -126,6 +293,34 @@ export const api = {
       method: 'POST',
     }),
 
+  reauth: (password: string) =>
+    fetchApi<{ success: true }>('/admin/api/reauth', {
+      method: 'POST',
+      body: JSON.stringify({ password }),
+    }),
+
+  changePassword: (data: { currentPassword: string; newPassword: string }) =>
+    fetchApi<{ success: true }>('/admin/api/password', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    }),
+
+  getAdminSessions: () =>
+    fetchApi<{ items: AdminSession[]; total: number }>('/admin/api/admin-sessions'),
+
+  revokeAdminSession: (id: string) =>
+    fetchApi<{ success: true }>(`/admin/api/admin-sessions/${id}`, {
+      method: 'DELETE',
+    }),
+
+  getBootstrapStatus: () => fetchApi<BootstrapStatus>('/admin/api/bootstrap'),
+
+  bootstrap: (data: { setupToken: string; password: string; deploymentName?: string }) =>
+    fetchApi<BootstrapStatus>('/admin/api/bootstrap', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    }),
+
   getStats: () =>
     fetchApi<{
       accounts: number;
@@ -157,27 +352,129 @@ export const api = {
       body: JSON.stringify(data),
     }),
 
-  deleteAccount: (id: string) =>
-    fetchApi<{ success: true }>(`/admin/api/accounts/${id}`, {
-      method: 'DELETE',
+  getDeletionPreview: (id: string) =>
+    fetchApi<AccountDeletionPreview>(`/admin/api/accounts/${id}/deletion-preview`),
+
+  deleteAccount: (id: string, previewToken: string) =>
+    withReauth(() =>
+      fetchApi<{ success: true }>(`/admin/api/accounts/${id}`, {
+        method: 'DELETE',
+        body: JSON.stringify({ previewToken }),
+      })
+    ),
+
+  setAccountLegalHold: (id: string, reason: string) =>
+    fetchApi<Account>(`/admin/api/accounts/${id}/legal-hold`, {
+      method: 'PUT',
+      body: JSON.stringify({ reason }),
     }),
 
-  regenerateToken: (id: string) =>
-    fetchApi<{ relayToken: string }>(`/admin/api/accounts/${id}/regenerate-token`, {
-      method: 'POST',
+  releaseAccountLegalHold: (id: string) =>
+    withReauth(() =>
+      fetchApi<Account>(`/admin/api/accounts/${id}/legal-hold`, {
+        method: 'DELETE',
+      })
+    ),
+
+  exportAccountConfig: (id: string) =>
+    fetchApi<AccountConfig>(`/admin/api/accounts/${id}/config`),
+
+  importAccountConfig: (id: string, config: AccountConfig) =>
+    withReauth(() =>
+      fetchApi<AccountConfigImportResult>(`/admin/api/accounts/${id}/config`, {
+        method: 'PUT',
+        body: JSON.stringify(config),
+      })
+    ),
+
+  getAccountSettings: (id: string) =>
+    fetchApi<{ settings: AccountSetting[] }>(`/admin/api/accounts/${id}/settings`),
+
+  // A null value resets the setting to its default
+  updateAccountSettings: (id: string, changes: Record<string, unknown>) =>
+    fetchApi<{ settings: AccountSetting[] }>(`/admin/api/accounts/${id}/settings`, {
+      method: 'PATCH',
+      body: JSON.stringify(changes),
     }),
 
+  regenerateToken: (id: string) =>
+    withReauth(() =>
+      fetchApi<{ relayToken: string }>(`/admin/api/accounts/${id}/regenerate-token`, {
+        method: 'POST',
+      })
+    ),
+
+  eraseKakaoUser: (userKey: string, mode: KakaoUserErasure['mode'], dryRun: boolean) => {
+    const params = new URLSearchParams({ mode, dryRun: String(dryRun) });
+    return withReauth(() =>
+      fetchApi<KakaoUserErasure>(`/admin/api/kakao-users/${encodeURIComponent(userKey)}?${params}`, {
+        method: 'DELETE',
+      })
+    );
+  },
+
   getMappings: (limit = 50, offset = 0, accountId?: string) => {
     const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
     if (accountId) params.append('accountId', accountId);
     return fetchApi<{ items: Mapping[]; total: number }>(`/admin/api/mappings?${params}`);
   },
 
+  getChangeHistory: (
+    limit = 50,
+    offset = 0,
+    filter?: { accountId?: string; entityType?: string; entityKey?: string; since?: string; until?: string }
+  ) => {
+    const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
+    for (const [key, value] of Object.entries(filter ?? {})) {
+      if (value) params.append(key, value);
+    }
+    return fetchApi<{ items: ChangeHistoryEntry[]; total: number }>(`/admin/api/history?${params}`);
+  },
+
+  // Notifications
+  getNotifications: (
+    limit = 50,
+    offset = 0,
+    filter?: { kind?: AdminNotification['kind']; unread?: boolean }
+  ) => {
+    const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
+    if (filter?.kind) params.append('kind', filter.kind);
+    if (filter?.unread) params.append('unread', 'true');
+    return fetchApi<{ items: AdminNotification[]; total: number; unreadCount: number }>(
+      `/admin/api/notifications?${params}`
+    );
+  },
+
+  markNotificationRead: (id: string) =>
+    fetchApi<{ success: true }>(`/admin/api/notifications/${id}/read`, {
+      method: 'POST',
+    }),
+
+  markAllNotificationsRead: () =>
+    fetchApi<{ success: true; count: number }>('/admin/api/notifications/read-all', {
+      method: 'POST',
+    }),
+
   deleteMapping: (id: string) =>
-    fetchApi<{ success: true }>(`/admin/api/mappings/${id}`, {
-      method: 'DELETE',
+    withReauth(() =>
+      fetchApi<{ success: true }>(`/admin/api/mappings/${id}`, {
+        method: 'DELETE',
+      })
+    ),
+
+  setMappingLegalHold: (id: string, reason: string) =>
+    fetchApi<Mapping>(`/admin/api/mappings/${id}/legal-hold`, {
+      method: 'PUT',
+      body: JSON.stringify({ reason }),
     }),
 
+  releaseMappingLegalHold: (id: string) =>
+    withReauth(() =>
+      fetchApi<Mapping>(`/admin/api/mappings/${id}/legal-hold`, {
+        method: 'DELETE',
+      })
+    ),
+
   getInboundMessages: (limit = 50, offset = 0, accountId?: string, status?: string) => {
     const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
     if (accountId) params.append('accountId', accountId);
@@ -187,6 +484,14 @@ export const api = {
     );
   },
 
+  expireInboundMessages: (filter: InboundExpiryFilter, dryRun = false) =>
+    withReauth(() =>
+      fetchApi<InboundExpiryResult>(`/admin/api/messages/inbound/expire${dryRun ? '?dryRun=true' : ''}`, {
+        method: 'POST',
+        body: JSON.stringify(filter),
+      })
+    ),
+
   getOutboundMessages: (limit = 50, offset = 0, accountId?: string, status?: string) => {
     const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
     if (accountId) params.append('accountId', accountId);
@@ -211,9 +516,11 @@ export const api = {
     }),
 
   deleteUser: (id: string) =>
-    fetchApi<{ success: true }>(`/admin/api/users/${id}`, {
-      method: 'DELETE',
-    }),
+    withReauth(() =>
+      fetchApi<{ success: true }>(`/admin/api/users/${id}`, {
+        method: 'DELETE',
+      })
+    ),
 
   // Sessions (Plugin Sessions)
   getSessions: (limit = 50, offset = 0, status?: string) => {
@@ -231,4 +538,31 @@ export const api = {
     fetchApi<{ success: true }>(`/admin/api/sessions/${id}/disconnect`, {
       method: 'POST',
     }),
+
+  // Pairing
+  getPairingCodes: (limit = 50, offset = 0) => {
+    const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
+    return fetchApi<{ items: PairingCode[]; total: number }>(`/admin/api/pairing/codes?${params}`);
+  },
+
+  revokePairingCode: (code: string) =>
+    fetchApi<{ success: true }>(`/admin/api/pairing/codes/${encodeURIComponent(code)}`, {
+      method: 'DELETE',
+    }),
+
+  getPendingSessions: (limit = 50, offset = 0) => {
+    const params = new URLSearchParams({ limit: limit.toString(), offset: offset.toString() });
+    return fetchApi<{ items: PluginSession[]; total: number }>(`/admin/api/pairing/sessions?${params}`);
+  },
+
+  revokePendingSession: (id: string) =>
+    fetchApi<{ success: true }>(`/admin/api/pairing/sessions/${id}`, {
+      method: 'DELETE',
+    }),
+
+  forcePairSession: (id: string, conversationKey: string) =>
+    fetchApi<PluginSession>(`/admin/api/pairing/sessions/${id}/pair`, {
+      method: 'POST',
+      body: JSON.stringify({ conversationKey }),
+    }),
 };
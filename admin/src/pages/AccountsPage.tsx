@@ -1,11 +1,11 @@
-import React, { useEffect, useState, useMemo } from 'react';
-import { api } from '../lib/api';
+import React, { useEffect, useState, useMemo, useRef } from 'react';
+import { api, type AccountConfig } from '../lib/api';
 import { Button } from '../components/ui/button';
 import { Input } from '../components/ui/input';
 import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from '../components/ui/table';
 import { Dialog, DialogContent, DialogHeader, DialogTitle, DialogFooter, DialogDescription } from '../components/ui/dialog';
 import { Badge } from '../components/ui/badge';
-import { Plus, Trash2, RefreshCw, Copy, Check, Search } from 'lucide-react';
+import { Plus, Trash2, RefreshCw, Copy, Check, Search, Download, Upload } from 'lucide-react';
 
 export function AccountsPage() {
   const [accounts, setAccounts] = useState<any[]>([]);
@@ -24,6 +24,9 @@ export function AccountsPage() {
   const [tokenDialog, setTokenDialog] = useState<{ open: boolean; token: string }>({ open: false, token: '' });
   const [copied, setCopied] = useState(false);
 
+  const importInputRef = useRef<HTMLInputElement>(null);
+  const [importTarget, setImportTarget] = useState<string | null>(null);
+
   // Client-side filtering
   const filteredAccounts = useMemo(() => {
     return accounts.filter((account) => {
@@ -66,9 +69,22 @@ export function AccountsPage() {
   };
 
   const handleDelete = async (id: string) => {
-    if (!confirm('Are you sure? This will delete all mappings and messages associated with this account.')) return;
     try {
-      await api.deleteAccount(id);
+      const preview = await api.getDeletionPreview(id);
+      if (preview.legalHold) {
+        alert('This account or one of its conversations is under legal hold. Release the hold before deleting.');
+        return;
+      }
+      const summary = [
+        `Conversations unlinked: ${preview.conversations}`,
+        `Messages deleted: ${preview.inboundMessages} inbound, ${preview.outboundMessages} outbound`,
+        `Pairing codes deleted: ${preview.pairingCodes}`,
+        `Plugin sessions detached: ${preview.sessions}`,
+        `Portal users deleted: ${preview.portalUsers}${preview.oauthProviders.length > 0 ? ` (${preview.oauthProviders.join(', ')})` : ''}`,
+      ].join('\n');
+      if (!confirm(`Delete this account?\n\n${summary}`)) return;
+
+      await api.deleteAccount(id, preview.previewToken);
       fetchAccounts();
     } catch (error) {
       alert('Failed to delete account');
@@ -85,6 +101,38 @@ export function AccountsPage() {
     }
   };
 
+  const handleExportConfig = async (id: string) => {
+    try {
+      const config = await api.exportAccountConfig(id);
+      const blob = new Blob([JSON.stringify(config, null, 2)], { type: 'application/json' });
+      const url = URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = `relay-config-${id}.json`;
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (error) {
+      alert('Failed to export configuration');
+    }
+  };
+
+  const handleImportConfig = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    e.target.value = '';
+    if (!file || !importTarget) return;
+    try {
+      const config = JSON.parse(await file.text()) as AccountConfig;
+      if (!confirm(`Import configuration into ${importTarget}? Mode, rate limit and Kakao channel will be overwritten.`)) return;
+      const result = await api.importAccountConfig(importTarget, config);
+      alert(`Imported: ${result.labelsApplied} labels applied, ${result.labelsSkipped.length} skipped`);
+      fetchAccounts();
+    } catch (error) {
+      alert(error instanceof Error ? error.message : 'Failed to import configuration');
+    } finally {
+      setImportTarget(null);
+    }
+  };
+
   const copyToken = () => {
     navigator.clipboard.writeText(tokenDialog.token);
     setCopied(true);
@@ -105,6 +153,14 @@ export function AccountsPage() {
         </Button>
       </div>
 
+      <input
+        ref={importInputRef}
+        type="file"
+        accept="application/json,.json"
+        className="hidden"
+        onChange={handleImportConfig}
+      />
+
       {/* Filters */}
       <div className="flex items-center gap-4">
         <div className="relative flex-1 max-w-sm">
@@ -171,6 +227,20 @@ export function AccountsPage() {
                     <Button variant="ghost" size="icon" onClick={() => handleRegenerateToken(account.id)} title="Regenerate Token">
                       <RefreshCw className="h-4 w-4" />
                     </Button>
+                    <Button variant="ghost" size="icon" onClick={() => handleExportConfig(account.id)} title="Export Config">
+                      <Download className="h-4 w-4" />
+                    </Button>
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      onClick={() => {
+                        setImportTarget(account.id);
+                        importInputRef.current?.click();
+                      }}
+                      title="Import Config"
+                    >
+                      <Upload className="h-4 w-4" />
+                    </Button>
                     <Button variant="ghost" size="icon" className="text-destructive" onClick={() => handleDelete(account.id)} title="Delete">
                       <Trash2 className="h-4 w-4" />
                     </Button>
@@ -0,0 +1,167 @@
+import React, { useEffect, useState } from 'react';
+import { useNavigate } from 'react-router-dom';
+import { Trash2 } from 'lucide-react';
+import { api, AdminSession } from '../lib/api';
+import { Button } from '../components/ui/button';
+import { Input } from '../components/ui/input';
+import { Badge } from '../components/ui/badge';
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from '../components/ui/card';
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from '../components/ui/table';
+
+function formatDate(value: string) {
+  return new Date(value).toLocaleString('ko-KR');
+}
+
+export function ChangePasswordPage() {
+  const [currentPassword, setCurrentPassword] = useState('');
+  const [newPassword, setNewPassword] = useState('');
+  const [confirmPassword, setConfirmPassword] = useState('');
+  const [error, setError] = useState('');
+  const [loading, setLoading] = useState(false);
+  const [sessions, setSessions] = useState<AdminSession[]>([]);
+  const navigate = useNavigate();
+
+  const loadSessions = async () => {
+    try {
+      const data = await api.getAdminSessions();
+      setSessions(data.items);
+    } catch (err) {
+      console.error('Failed to load admin sessions', err);
+    }
+  };
+
+  useEffect(() => {
+    loadSessions();
+  }, []);
+
+  const handleRevoke = async (session: AdminSession) => {
+    const message = session.current
+      ? '현재 세션을 종료하면 로그아웃됩니다. 계속하시겠습니까?'
+      : '이 관리자 세션을 종료하시겠습니까?';
+    if (!confirm(message)) return;
+
+    try {
+      await api.revokeAdminSession(session.id);
+      if (session.current) {
+        navigate('/login');
+        return;
+      }
+      loadSessions();
+    } catch (err) {
+      console.error('Failed to revoke admin session', err);
+    }
+  };
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+
+    if (newPassword !== confirmPassword) {
+      setError('New passwords do not match');
+      return;
+    }
+
+    setLoading(true);
+    try {
+      await api.changePassword({ currentPassword, newPassword });
+      navigate('/');
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Password change failed');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="space-y-6">
+      <h1 className="text-3xl font-bold tracking-tight">비밀번호 변경</h1>
+      <Card className="max-w-md">
+        <CardHeader>
+          <CardTitle>Admin password</CardTitle>
+          <CardDescription>
+            Changing the password signs out every other admin session.
+          </CardDescription>
+        </CardHeader>
+        <form onSubmit={handleSubmit}>
+          <CardContent className="grid gap-4">
+            <Input
+              id="currentPassword"
+              type="password"
+              placeholder="Current password"
+              value={currentPassword}
+              onChange={(e) => setCurrentPassword(e.target.value)}
+              required
+            />
+            <Input
+              id="newPassword"
+              type="password"
+              placeholder="New password (12+ characters)"
+              value={newPassword}
+              onChange={(e) => setNewPassword(e.target.value)}
+              minLength={12}
+              required
+            />
+            <Input
+              id="confirmPassword"
+              type="password"
+              placeholder="Confirm new password"
+              value={confirmPassword}
+              onChange={(e) => setConfirmPassword(e.target.value)}
+              minLength={12}
+              required
+            />
+            {error && <p className="text-sm text-destructive">{error}</p>}
+          </CardContent>
+          <CardFooter>
+            <Button type="submit" disabled={loading}>
+              {loading ? 'Saving...' : 'Change password'}
+            </Button>
+          </CardFooter>
+        </form>
+      </Card>
+
+      <div className="space-y-2">
+        <h2 className="text-xl font-semibold">관리자 세션</h2>
+        <div className="rounded-md border">
+          <Table>
+            <TableHeader>
+              <TableRow>
+                <TableHead>IP</TableHead>
+                <TableHead>User Agent</TableHead>
+                <TableHead>최근 활동</TableHead>
+                <TableHead>만료 시간</TableHead>
+                <TableHead className="text-right">작업</TableHead>
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {sessions.length === 0 ? (
+                <TableRow>
+                  <TableCell colSpan={5} className="text-center h-24">활성 세션이 없습니다.</TableCell>
+                </TableRow>
+              ) : (
+                sessions.map((session) => (
+                  <TableRow key={session.id}>
+                    <TableCell className="font-mono text-xs">
+                      {session.ipAddress ?? '-'}
+                      {session.current && <Badge className="ml-2" variant="secondary">현재</Badge>}
+                    </TableCell>
+                    <TableCell className="text-muted-foreground text-xs max-w-xs truncate">
+                      {session.userAgent ?? '-'}
+                    </TableCell>
+                    <TableCell className="text-muted-foreground text-xs">{formatDate(session.lastSeenAt)}</TableCell>
+                    <TableCell className="text-muted-foreground text-xs">{formatDate(session.expiresAt)}</TableCell>
+                    <TableCell className="text-right">
+                      <Button variant="ghost" size="icon" onClick={() => handleRevoke(session)} title="세션 종료">
+                        <Trash2 className="h-4 w-4" />
+                      </Button>
+                    </TableCell>
+                  </TableRow>
+                ))
+              )}
+            </TableBody>
+          </Table>
+        </div>
+      </div>
+    </div>
+  );
+}
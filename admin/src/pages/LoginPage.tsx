@@ -1,4 +1,4 @@
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { api } from '../lib/api';
 import { Button } from '../components/ui/button';
@@ -9,8 +9,80 @@ export function LoginPage() {
   const [password, setPassword] = useState('');
   const [error, setError] = useState('');
   const [loading, setLoading] = useState(false);
+  const [bootstrapRequired, setBootstrapRequired] = useState(false);
+  const [setupToken, setSetupToken] = useState('');
+  const [deploymentName, setDeploymentName] = useState('');
   const navigate = useNavigate();
 
+  useEffect(() => {
+    api.getBootstrapStatus()
+      .then((status) => setBootstrapRequired(status.bootstrapRequired))
+      .catch(() => setBootstrapRequired(false));
+  }, []);
+
+  const handleBootstrap = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setLoading(true);
+    setError('');
+
+    try {
+      await api.bootstrap({ setupToken, password, deploymentName: deploymentName || undefined });
+      await api.login(password);
+      navigate('/');
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Setup failed');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (bootstrapRequired) {
+    return (
+      <div className="flex min-h-screen items-center justify-center bg-background">
+        <Card className="w-full max-w-sm">
+          <CardHeader>
+            <CardTitle className="text-2xl">Initial Setup</CardTitle>
+            <CardDescription>
+              No admin password is configured. Enter the setup token from the server log and choose an admin password.
+            </CardDescription>
+          </CardHeader>
+          <form onSubmit={handleBootstrap}>
+            <CardContent className="grid gap-4">
+              <Input
+                id="setupToken"
+                placeholder="Setup token"
+                value={setupToken}
+                onChange={(e) => setSetupToken(e.target.value)}
+                required
+              />
+              <Input
+                id="deploymentName"
+                placeholder="Deployment name (optional)"
+                value={deploymentName}
+                onChange={(e) => setDeploymentName(e.target.value)}
+              />
+              <Input
+                id="password"
+                type="password"
+                placeholder="Admin password (12+ characters)"
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                minLength={12}
+                required
+              />
+              {error && <p className="text-sm text-destructive">{error}</p>}
+            </CardContent>
+            <CardFooter>
+              <Button className="w-full" type="submit" disabled={loading}>
+                {loading ? 'Setting up...' : 'Complete setup'}
+              </Button>
+            </CardFooter>
+          </form>
+        </Card>
+      </div>
+    );
+  }
+
   const handleLogin = async (e: React.FormEvent) => {
     e.preventDefault();
     setLoading(true);
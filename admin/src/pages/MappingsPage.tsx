@@ -71,6 +71,7 @@ export function MappingsPage() {
               <TableHead>Account ID</TableHead>
               <TableHead>채널 ID</TableHead>
               <TableHead>OpenClaw User ID</TableHead>
+              <TableHead>카카오 닉네임</TableHead>
               <TableHead>Created At</TableHead>
               <TableHead className="text-right">Actions</TableHead>
             </TableRow>
@@ -78,11 +79,11 @@ export function MappingsPage() {
           <TableBody>
             {loading ? (
               <TableRow>
-                <TableCell colSpan={6} className="text-center h-24">Loading...</TableCell>
+                <TableCell colSpan={7} className="text-center h-24">Loading...</TableCell>
               </TableRow>
             ) : mappings.length === 0 ? (
               <TableRow>
-                <TableCell colSpan={6} className="text-center h-24">No mappings found</TableCell>
+                <TableCell colSpan={7} className="text-center h-24">No mappings found</TableCell>
               </TableRow>
             ) : (
               mappings.map((mapping) => (
@@ -91,6 +92,7 @@ export function MappingsPage() {
                   <TableCell className="font-mono text-xs">{mapping.accountId}</TableCell>
                   <TableCell>{mapping.kakaoChannelId}</TableCell>
                   <TableCell>{mapping.openclawUserId}</TableCell>
+                  <TableCell>{mapping.kakaoNickname ?? '-'}</TableCell>
                   <TableCell className="text-muted-foreground text-xs">
                     {new Date(mapping.createdAt).toLocaleDateString()}
                   </TableCell>
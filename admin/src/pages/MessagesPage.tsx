@@ -40,6 +40,24 @@ export function MessagesPage() {
     fetchMessages();
   }, [offset]);
 
+  // Queued messages of the filtered account; dry run first to confirm the count
+  const handleExpireQueued = async () => {
+    if (!accountId) return;
+    try {
+      const preview = await api.expireInboundMessages({ accountId }, true);
+      if (preview.expired === 0) {
+        alert('No queued messages to expire');
+        return;
+      }
+      if (!confirm(`Expire ${preview.expired} queued message(s) of this account?`)) return;
+      const result = await api.expireInboundMessages({ accountId });
+      alert(`Expired ${result.expired} message(s)`);
+      fetchMessages();
+    } catch (error) {
+      alert(error instanceof Error ? error.message : 'Failed to expire messages');
+    }
+  };
+
   const handleSearch = (e: React.FormEvent) => {
     e.preventDefault();
     setOffset(0);
@@ -96,6 +114,16 @@ export function MessagesPage() {
               <Search className="h-4 w-4" />
             </Button>
           </form>
+          {activeTab === 'inbound' && (
+            <Button
+              variant="destructive"
+              onClick={handleExpireQueued}
+              disabled={!accountId}
+              title="Expire this account's queued messages"
+            >
+              Expire queued
+            </Button>
+          )}
         </div>
 
         <TabsContent value="inbound">
@@ -135,6 +163,9 @@ interface MessageRow {
   content: string;
   status: string;
   createdAt: string;
+  callbackStatus?: number;
+  callbackResponse?: string;
+  callbackTaskId?: string;
 }
 
 function MessageTable({ messages, loading, type }: { messages: MessageRow[], loading: boolean, type: string }) {
@@ -173,6 +204,15 @@ function MessageTable({ messages, loading, type }: { messages: MessageRow[], loa
                   <Badge variant={msg.status === 'failed' ? 'destructive' : 'secondary'}>
                     {msg.status}
                   </Badge>
+                  {msg.callbackStatus !== undefined && (
+                    <div
+                      className="text-muted-foreground text-xs mt-1"
+                      title={msg.callbackResponse}
+                    >
+                      Kakao {msg.callbackStatus}
+                      {msg.callbackTaskId && <span className="font-mono"> · {msg.callbackTaskId}</span>}
+                    </div>
+                  )}
                 </TableCell>
                 <TableCell className="text-muted-foreground text-xs">
                   {new Date(msg.createdAt).toLocaleString()}
@@ -0,0 +1,205 @@
+import React, { useEffect, useState } from 'react';
+import { api, AdminNotification } from '../lib/api';
+import { Button } from '../components/ui/button';
+import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from '../components/ui/table';
+import { Badge } from '../components/ui/badge';
+import { Check, CheckCheck } from 'lucide-react';
+
+const kindColors: Record<AdminNotification['kind'], 'default' | 'secondary' | 'destructive' | 'outline'> = {
+  anomaly: 'secondary',
+  job_failure: 'destructive',
+  quota_breach: 'outline',
+  migration_pending: 'default',
+};
+
+const kindLabels: Record<AdminNotification['kind'], string> = {
+  anomaly: '이상 징후',
+  job_failure: '작업 실패',
+  quota_breach: '한도 초과',
+  migration_pending: '마이그레이션 대기',
+};
+
+export function NotificationsPage() {
+  const [notifications, setNotifications] = useState<AdminNotification[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [offset, setOffset] = useState(0);
+  const [total, setTotal] = useState(0);
+  const [unreadCount, setUnreadCount] = useState(0);
+  const limit = 50;
+
+  const [kindFilter, setKindFilter] = useState<AdminNotification['kind'] | 'all'>('all');
+  const [unreadOnly, setUnreadOnly] = useState(false);
+
+  const fetchNotifications = async () => {
+    setLoading(true);
+    try {
+      const data = await api.getNotifications(limit, offset, {
+        kind: kindFilter === 'all' ? undefined : kindFilter,
+        unread: unreadOnly,
+      });
+      setNotifications(data.items);
+      setTotal(data.total);
+      setUnreadCount(data.unreadCount);
+    } catch (error) {
+      console.error(error);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    fetchNotifications();
+  }, [offset, kindFilter, unreadOnly]);
+
+  const handleMarkRead = async (id: string) => {
+    try {
+      await api.markNotificationRead(id);
+      fetchNotifications();
+    } catch (error) {
+      alert('알림을 읽음 처리하지 못했습니다.');
+    }
+  };
+
+  const handleMarkAllRead = async () => {
+    try {
+      await api.markAllNotificationsRead();
+      fetchNotifications();
+    } catch (error) {
+      alert('알림을 읽음 처리하지 못했습니다.');
+    }
+  };
+
+  const formatDate = (dateStr: string | null) => {
+    if (!dateStr) return '-';
+    return new Date(dateStr).toLocaleString('ko-KR', {
+      year: 'numeric',
+      month: '2-digit',
+      day: '2-digit',
+      hour: '2-digit',
+      minute: '2-digit',
+    });
+  };
+
+  return (
+    <div className="space-y-6">
+      <div className="flex items-start justify-between">
+        <div>
+          <h1 className="text-3xl font-bold tracking-tight">알림</h1>
+          <p className="text-muted-foreground mt-1">
+            서버가 감지한 이상 징후, 작업 실패, 한도 초과, 적용되지 않은 마이그레이션입니다. 운영 웹훅 설정과 관계없이 기록됩니다.
+          </p>
+        </div>
+        <Button variant="outline" onClick={handleMarkAllRead} disabled={unreadCount === 0}>
+          <CheckCheck className="mr-2 h-4 w-4" />
+          모두 읽음 ({unreadCount})
+        </Button>
+      </div>
+
+      {/* Filters */}
+      <div className="flex items-center gap-4">
+        <select
+          className="h-10 rounded-md border border-input bg-background px-3 py-2 text-sm"
+          value={kindFilter}
+          onChange={(e) => { setOffset(0); setKindFilter(e.target.value as AdminNotification['kind'] | 'all'); }}
+        >
+          <option value="all">모든 유형</option>
+          {Object.entries(kindLabels).map(([kind, label]) => (
+            <option key={kind} value={kind}>{label}</option>
+          ))}
+        </select>
+        <label className="flex items-center gap-2 text-sm">
+          <input
+            type="checkbox"
+            checked={unreadOnly}
+            onChange={(e) => { setOffset(0); setUnreadOnly(e.target.checked); }}
+          />
+          읽지 않은 알림만
+        </label>
+      </div>
+
+      <div className="rounded-md border">
+        <Table>
+          <TableHeader>
+            <TableRow>
+              <TableHead>유형</TableHead>
+              <TableHead>내용</TableHead>
+              <TableHead>대상</TableHead>
+              <TableHead>횟수</TableHead>
+              <TableHead>최근 발생</TableHead>
+              <TableHead>해소</TableHead>
+              <TableHead className="text-right">작업</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {loading ? (
+              <TableRow>
+                <TableCell colSpan={7} className="text-center h-24">불러오는 중...</TableCell>
+              </TableRow>
+            ) : notifications.length === 0 ? (
+              <TableRow>
+                <TableCell colSpan={7} className="text-center h-24">알림이 없습니다.</TableCell>
+              </TableRow>
+            ) : (
+              notifications.map((notification) => (
+                <TableRow key={notification.id} className={notification.readAt ? 'text-muted-foreground' : 'font-medium'}>
+                  <TableCell>
+                    <Badge variant={kindColors[notification.kind]}>
+                      {kindLabels[notification.kind]}
+                    </Badge>
+                  </TableCell>
+                  <TableCell>
+                    <div>{notification.message}</div>
+                    {notification.details && (
+                      <div className="font-mono text-xs text-muted-foreground break-all">
+                        {JSON.stringify(notification.details)}
+                      </div>
+                    )}
+                  </TableCell>
+                  <TableCell className="font-mono text-xs">{notification.subject || '-'}</TableCell>
+                  <TableCell>{notification.occurrences}</TableCell>
+                  <TableCell className="text-muted-foreground text-xs">
+                    {formatDate(notification.lastOccurredAt)}
+                  </TableCell>
+                  <TableCell className="text-muted-foreground text-xs">
+                    {formatDate(notification.resolvedAt)}
+                  </TableCell>
+                  <TableCell className="text-right">
+                    {!notification.readAt && (
+                      <Button
+                        variant="ghost"
+                        size="icon"
+                        onClick={() => handleMarkRead(notification.id)}
+                        title="읽음 처리"
+                      >
+                        <Check className="h-4 w-4" />
+                      </Button>
+                    )}
+                  </TableCell>
+                </TableRow>
+              ))
+            )}
+          </TableBody>
+        </Table>
+      </div>
+
+      <div className="flex items-center justify-end space-x-2">
+        <Button
+          variant="outline"
+          size="sm"
+          onClick={() => setOffset(Math.max(0, offset - limit))}
+          disabled={offset === 0}
+        >
+          이전
+        </Button>
+        <Button
+          variant="outline"
+          size="sm"
+          onClick={() => setOffset(offset + limit)}
+          disabled={offset + limit >= total}
+        >
+          다음
+        </Button>
+      </div>
+    </div>
+  );
+}
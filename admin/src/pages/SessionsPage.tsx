@@ -3,7 +3,7 @@ import { api, PluginSession } from '../lib/api';
 import { Button } from '../components/ui/button';
 import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from '../components/ui/table';
 import { Badge } from '../components/ui/badge';
-import { Trash2, Unplug, Search } from 'lucide-react';
+import { Trash2, Unplug, Search, Link2, Ban } from 'lucide-react';
 import { Input } from '../components/ui/input';
 
 const statusColors: Record<PluginSession['status'], 'default' | 'secondary' | 'destructive' | 'outline'> = {
@@ -80,6 +80,27 @@ export function SessionsPage() {
     }
   };
 
+  const handleRevoke = async (id: string) => {
+    if (!confirm('이 세션의 페어링 코드를 폐기하시겠습니까?')) return;
+    try {
+      await api.revokePendingSession(id);
+      fetchSessions();
+    } catch (error) {
+      alert('페어링 코드 폐기에 실패했습니다.');
+    }
+  };
+
+  const handleForcePair = async (id: string) => {
+    const conversationKey = prompt('연결할 대화 키를 입력하세요 (채널ID:사용자키)');
+    if (!conversationKey?.trim()) return;
+    try {
+      await api.forcePairSession(id, conversationKey.trim());
+      fetchSessions();
+    } catch (error) {
+      alert(error instanceof Error ? error.message : '강제 연결에 실패했습니다.');
+    }
+  };
+
   const formatDate = (dateStr: string | null) => {
     if (!dateStr) return '-';
     return new Date(dateStr).toLocaleString('ko-KR', {
@@ -173,6 +194,26 @@ export function SessionsPage() {
                     {formatDate(session.createdAt)}
                   </TableCell>
                   <TableCell className="text-right space-x-2">
+                    {session.status === 'pending_pairing' && (
+                      <>
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          onClick={() => handleForcePair(session.id)}
+                          title="강제 연결"
+                        >
+                          <Link2 className="h-4 w-4" />
+                        </Button>
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          onClick={() => handleRevoke(session.id)}
+                          title="페어링 코드 폐기"
+                        >
+                          <Ban className="h-4 w-4" />
+                        </Button>
+                      </>
+                    )}
                     {session.status === 'paired' && (
                       <Button
                         variant="ghost"
@@ -0,0 +1,384 @@
+// Command loadgen drives a running relay with simulated Kakao conversations
+// and SSE consumers and reports end-to-end latency: from posting a Kakao
+// webhook to the message event arriving on a consumer's stream.
+//
+// Each conversation is paired to its own session first, the way a plugin
+// pairs (POST /v1/sessions/create, then "/pair CODE" from the Kakao user).
+// Consumers connect to GET /v1/events round-robin over those sessions, so
+// with more consumers than conversations a message fans out to several.
+//
+// Point it at a test instance only: it creates sessions, accounts and
+// messages. Raise the per-IP session limits there first, for example
+// IP_RATE_LIMIT_SESSION_CREATE=10000/1m and MAX_PENDING_SESSIONS_PER_IP=1000.
+//
+//	go run ./cmd/loadgen -url http://localhost:8080 -conversations 50 -consumers 50 -messages 20
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+type options struct {
+	baseURL         string
+	conversations   int
+	consumers       int
+	messages        int
+	interval        time.Duration
+	channelID       string
+	signatureSecret string
+	drainTimeout    time.Duration
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.baseURL, "url", "http://localhost:8080", "base URL of the relay")
+	flag.IntVar(&opts.conversations, "conversations", 10, "number of concurrent Kakao conversations")
+	flag.IntVar(&opts.consumers, "consumers", 10, "number of SSE consumers")
+	flag.IntVar(&opts.messages, "messages", 20, "messages sent by each conversation")
+	flag.DurationVar(&opts.interval, "interval", 500*time.Millisecond, "delay between messages of one conversation")
+	flag.StringVar(&opts.channelID, "channel-id", "loadgen", "Kakao bot ID the conversations use")
+	flag.StringVar(&opts.signatureSecret, "signature-secret", os.Getenv("KAKAO_SIGNATURE_SECRET"), "secret for X-Kakao-Signature (empty if the relay does not verify)")
+	flag.DurationVar(&opts.drainTimeout, "drain-timeout", 30*time.Second, "how long to wait for outstanding events after the last message")
+	flag.Parse()
+
+	if opts.conversations < 1 || opts.consumers < 0 || opts.messages < 1 {
+		fmt.Fprintln(os.Stderr, "Error: -conversations and -messages must be at least 1, -consumers at least 0")
+		os.Exit(2)
+	}
+	opts.baseURL = strings.TrimSuffix(opts.baseURL, "/")
+
+	run := newRun(opts)
+	if err := run.pair(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := run.connect(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	started := time.Now()
+	run.send()
+	run.drain()
+	cancel()
+
+	run.report(time.Since(started))
+}
+
+// conversation is one simulated Kakao user, paired to its own session.
+type conversation struct {
+	userKey      string
+	sessionToken string
+	// consumers connected to this conversation's session
+	consumers int
+}
+
+type run struct {
+	opts   options
+	id     string
+	client *http.Client
+	convs  []*conversation
+
+	// tag -> time the webhook carrying it was posted
+	sent     sync.Map
+	expected atomic.Int64
+	received atomic.Int64
+
+	mu              sync.Mutex
+	webhookLatency  []time.Duration
+	deliveryLatency []time.Duration
+	webhookErrors   int
+	streamErrors    int
+}
+
+func newRun(opts options) *run {
+	return &run{
+		opts:   opts,
+		id:     strconv.FormatInt(time.Now().Unix(), 36),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// tagPattern finds the tag loadgen puts in every utterance, whatever the shape
+// of the event data around it.
+var tagPattern = regexp.MustCompile(`lg:[0-9a-z]+:[0-9]+:[0-9]+`)
+
+func (r *run) tag(conv, seq int) string {
+	return fmt.Sprintf("lg:%s:%d:%d", r.id, conv, seq)
+}
+
+// pair creates a session for every conversation and pairs it from Kakao.
+func (r *run) pair() error {
+	fmt.Printf("Pairing %d conversation(s)...\n", r.opts.conversations)
+	for i := range r.opts.conversations {
+		var session struct {
+			SessionToken string `json:"sessionToken"`
+			PairingCode  string `json:"pairingCode"`
+		}
+		if err := r.postJSON("/v1/sessions/create", nil, &session); err != nil {
+			return fmt.Errorf("create session %d: %w", i, err)
+		}
+
+		conv := &conversation{
+			userKey:      fmt.Sprintf("loadgen-%s-%d", r.id, i),
+			sessionToken: session.SessionToken,
+		}
+		if _, err := r.webhook(conv, "/pair "+session.PairingCode); err != nil {
+			return fmt.Errorf("pair conversation %d: %w", i, err)
+		}
+
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err := r.getJSON("/v1/sessions/"+session.SessionToken+"/status", &status); err != nil {
+			return fmt.Errorf("session %d status: %w", i, err)
+		}
+		if status.Status != "paired" {
+			return fmt.Errorf("conversation %d did not pair: session is %q", i, status.Status)
+		}
+		r.convs = append(r.convs, conv)
+	}
+	return nil
+}
+
+// connect opens the SSE consumers and waits until every stream is open.
+func (r *run) connect(ctx context.Context) error {
+	fmt.Printf("Connecting %d consumer(s)...\n", r.opts.consumers)
+	ready := make(chan error, r.opts.consumers)
+	for i := range r.opts.consumers {
+		conv := r.convs[i%len(r.convs)]
+		conv.consumers++
+		go r.consume(ctx, conv, ready)
+	}
+	for range r.opts.consumers {
+		if err := <-ready; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *run) consume(ctx context.Context, conv *conversation, ready chan<- error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.opts.baseURL+"/v1/events", nil)
+	if err != nil {
+		ready <- err
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+conv.sessionToken)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// Streams stay open for the whole run, so no client timeout
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		ready <- fmt.Errorf("open event stream: %w", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		ready <- fmt.Errorf("open event stream: %s", resp.Status)
+		return
+	}
+	ready <- nil
+
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "message":
+			r.delivered([]byte(strings.TrimPrefix(line, "data: ")))
+		case line == "":
+			event = ""
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		r.mu.Lock()
+		r.streamErrors++
+		r.mu.Unlock()
+	}
+}
+
+func (r *run) delivered(data []byte) {
+	tag := tagPattern.Find(data)
+	if tag == nil {
+		return
+	}
+	sentAt, ok := r.sent.Load(string(tag))
+	if !ok {
+		return
+	}
+	latency := time.Since(sentAt.(time.Time))
+	r.received.Add(1)
+
+	r.mu.Lock()
+	r.deliveryLatency = append(r.deliveryLatency, latency)
+	r.mu.Unlock()
+}
+
+// send runs all conversations concurrently until each has sent its messages.
+func (r *run) send() {
+	fmt.Printf("Sending %d message(s) from each conversation...\n", r.opts.messages)
+	var wg sync.WaitGroup
+	for i, conv := range r.convs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seq := range r.opts.messages {
+				if seq > 0 {
+					time.Sleep(r.opts.interval)
+				}
+				tag := r.tag(i, seq)
+				r.sent.Store(tag, time.Now())
+				r.expected.Add(int64(conv.consumers))
+
+				latency, err := r.webhook(conv, "load test "+tag)
+				r.mu.Lock()
+				if err != nil {
+					r.webhookErrors++
+				} else {
+					r.webhookLatency = append(r.webhookLatency, latency)
+				}
+				r.mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// drain waits for outstanding deliveries, up to the drain timeout.
+func (r *run) drain() {
+	deadline := time.Now().Add(r.opts.drainTimeout)
+	for r.received.Load() < r.expected.Load() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (r *run) webhook(conv *conversation, utterance string) (time.Duration, error) {
+	body, err := json.Marshal(map[string]any{
+		"bot": map[string]string{"id": r.opts.channelID},
+		"userRequest": map[string]any{
+			"utterance": utterance,
+			"user": map[string]any{
+				"id":         conv.userKey,
+				"type":       "botUserKey",
+				"properties": map[string]string{"plusfriendUserKey": conv.userKey},
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.opts.baseURL+"/kakao-talkchannel/webhook", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.opts.signatureSecret != "" {
+		req.Header.Set("X-Kakao-Signature", util.HmacSHA256(r.opts.signatureSecret, string(body)))
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	latency := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("webhook: %s", resp.Status)
+	}
+	return latency, nil
+}
+
+func (r *run) postJSON(path string, body any, dest any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Post(r.opts.baseURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, dest)
+}
+
+func (r *run) getJSON(path string, dest any) error {
+	resp, err := r.client.Get(r.opts.baseURL + path)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, dest)
+}
+
+func decodeResponse(resp *http.Response, dest any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+func (r *run) report(elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sent := len(r.webhookLatency) + r.webhookErrors
+	fmt.Println()
+	fmt.Printf("Duration: %s, %d webhook(s) (%.1f/s), %d webhook error(s), %d stream error(s)\n",
+		elapsed.Round(time.Millisecond), sent, float64(sent)/elapsed.Seconds(), r.webhookErrors, r.streamErrors)
+	fmt.Printf("Delivered: %d of %d expected event(s)\n", r.received.Load(), r.expected.Load())
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LATENCY\tCOUNT\tP50\tP90\tP99\tMAX")
+	printLatency(w, "webhook response", r.webhookLatency)
+	printLatency(w, "webhook to event", r.deliveryLatency)
+	w.Flush()
+}
+
+func printLatency(w io.Writer, name string, samples []time.Duration) {
+	if len(samples) == 0 {
+		fmt.Fprintf(w, "%s\t0\t-\t-\t-\t-\n", name)
+		return
+	}
+	slices.Sort(samples)
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", name, len(samples),
+		percentile(samples, 50), percentile(samples, 90), percentile(samples, 99), percentile(samples, 100))
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(10 * time.Microsecond)
+}
@@ -0,0 +1,100 @@
+// Command repair audits relay data for inconsistent states (such as
+// half-completed pairings) and optionally fixes them.
+//
+// It runs in dry-run mode by default and only reports what it would change:
+//
+//	go run ./cmd/repair -database-url "$DATABASE_URL"
+//	go run ./cmd/repair -check half_paired_sessions -dry-run=false
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+func main() {
+	databaseURL := flag.String("database-url", os.Getenv("DATABASE_URL"), "PostgreSQL connection URL")
+	dryRun := flag.Bool("dry-run", true, "report findings without changing any data")
+	checks := flag.String("check", "", "comma-separated checks to run (default: all)")
+	callbackTTL := flag.Duration("callback-ttl", 55*time.Second, "age after which queued messages are stale")
+	flag.Parse()
+
+	if *databaseURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: -database-url or DATABASE_URL is required")
+		os.Exit(2)
+	}
+
+	db, err := database.Connect(*databaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	integrityService := service.NewIntegrityService(repository.NewIntegrityRepository(db.DB), *callbackTTL)
+
+	var only []string
+	if *checks != "" {
+		for _, name := range strings.Split(*checks, ",") {
+			name = strings.TrimSpace(name)
+			if !slices.Contains(integrityService.CheckNames(), name) {
+				fmt.Fprintf(os.Stderr, "Error: unknown check %q (available: %s)\n", name, strings.Join(integrityService.CheckNames(), ", "))
+				os.Exit(2)
+			}
+			only = append(only, name)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.IntegrityCheckTimeout)
+	defer cancel()
+
+	report, err := integrityService.Run(ctx, !*dryRun, only...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	printReport(report)
+}
+
+func printReport(report *model.IntegrityReport) {
+	if report.Repair {
+		fmt.Println("Mode: repair")
+	} else {
+		fmt.Println("Mode: dry run (pass -dry-run=false to apply repairs)")
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tFOUND\tREPAIRED\tSAMPLE IDS")
+	for _, f := range report.Findings {
+		repaired := "-"
+		if !f.Repairable {
+			repaired = "n/a"
+		} else if report.Repair {
+			repaired = fmt.Sprint(f.Repaired)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", f.Check, f.Count, repaired, strings.Join(f.SampleIDs, ","))
+	}
+	w.Flush()
+
+	fmt.Println()
+	for _, f := range report.Findings {
+		if f.Count > 0 {
+			fmt.Printf("%s: %s\n", f.Check, f.Description)
+		}
+	}
+	fmt.Printf("%d issue(s) found\n", report.Issues())
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,12 +14,25 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/openclaw/relay-server-go/drizzle"
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/blob"
 	"github.com/openclaw/relay-server-go/internal/config"
 	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/errreport"
+	relaygrpc "github.com/openclaw/relay-server-go/internal/grpc"
+	"github.com/openclaw/relay-server-go/internal/grpc/relaypb"
 	"github.com/openclaw/relay-server-go/internal/handler"
+	"github.com/openclaw/relay-server-go/internal/httputil"
 	"github.com/openclaw/relay-server-go/internal/jobs"
+	"github.com/openclaw/relay-server-go/internal/logging"
+	"github.com/openclaw/relay-server-go/internal/mail"
 	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 	"github.com/openclaw/relay-server-go/internal/redis"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/service"
@@ -27,7 +41,8 @@ import (
 
 func main() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	// Secrets are masked until the configuration allows otherwise
+	log.Logger = log.Output(logging.NewRedactWriter(zerolog.ConsoleWriter{Out: os.Stderr}))
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -40,6 +55,51 @@ func main() {
 	if err := cfg.Validate(isProduction); err != nil {
 		log.Fatal().Err(err).Msg("invalid configuration")
 	}
+	if cfg.LogFullDebug {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		log.Warn().Msg("LOG_FULL_DEBUG is enabled: pairing codes, portal codes and tokens are logged unmasked")
+	}
+
+	trustedProxies, err := cfg.TrustedProxyCIDRs()
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid trusted proxies")
+	}
+	if isProduction && len(trustedProxies) == 0 {
+		log.Warn().Msg("no trusted proxies configured in production: clients behind the platform proxy share its IP for per-IP limits (set TRUSTED_PROXY_PRESETS, e.g. gcp or fly)")
+	}
+
+	pluginCompat, err := cfg.PluginCompatibility()
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid plugin version configuration")
+	}
+
+	errorReporter := errreport.NewLogReporter()
+	if cfg.SentryDSN != "" {
+		errorReporter, err = errreport.NewSentryReporter(cfg.SentryDSN, cfg.SentryEnvironment)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid error reporting configuration")
+		}
+	}
+
+	alertNotifier := alert.NewLogNotifier()
+	if cfg.OpsAlertWebhookURL != "" {
+		alertNotifier, err = alert.NewWebhookNotifier(cfg.OpsAlertWebhookURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid ops alert configuration")
+		}
+	}
+
+	mailer := mail.NewLogSender()
+	if cfg.SMTPHost != "" {
+		mailer = mail.NewSMTPSender(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	}
 
 	db, err := database.Connect(cfg.DatabaseURL)
 	if err != nil {
@@ -69,64 +129,185 @@ func main() {
 	portalSessionRepo := repository.NewPortalSessionRepository(db.DB)
 	adminSessionRepo := repository.NewAdminSessionRepository(db.DB)
 	inboundMsgRepo := repository.NewInboundMessageRepository(db.DB)
+	blobStore := loadBlobStore(cfg)
+	if blobStore != nil {
+		inboundMsgRepo = repository.NewBlobInboundMessageRepository(inboundMsgRepo, blobStore, cfg.BlobOffloadMinBytes)
+		accountRepo = repository.NewBlobAccountRepository(accountRepo, blobStore)
+	}
 	outboundMsgRepo := repository.NewOutboundMessageRepository(db.DB)
+	messageTimelineRepo := repository.NewMessageTimelineRepository(db.DB)
 	sessionRepo := repository.NewSessionRepository(db.DB)
-
-	broker := sse.NewBroker(redisClient)
+	experimentRepo := repository.NewExperimentRepository(db.DB)
+	accountSetupRepo := repository.NewAccountSetupRepository(db.DB)
+	accountSettingsRepo := repository.NewAccountSettingsRepository(db.DB)
+	changeHistoryRepo := repository.NewChangeHistoryRepository(db.DB)
+	integrityRepo := repository.NewIntegrityRepository(db.DB)
+	erasureRepo := repository.NewErasureRepository(db.DB)
+	deploymentSettingsRepo := repository.NewDeploymentSettingsRepository(db.DB)
+	adminNotificationRepo := repository.NewAdminNotificationRepository(db.DB)
+
+	// Alerts also go to the admin notification center, so deployments without
+	// an ops webhook still see them
+	adminNotificationService := service.NewAdminNotificationService(adminNotificationRepo)
+	alertNotifier = alert.NewMultiNotifier(alertNotifier, adminNotificationService)
+	checkMigrations(repository.NewSchemaMigrationRepository(db.DB), alertNotifier)
+
+	deploymentService := service.NewDeploymentService(deploymentSettingsRepo, cfg.AdminPasswordHash, cfg.AdminPasswordMaxAge())
+	adminSessionSecret, portalSessionSecret := loadDeploymentSettings(deploymentService, cfg)
+
+	var eventHistory sse.EventHistory
+	if cfg.EventHistorySize > 0 {
+		eventHistory = sse.NewRedisEventHistory(redisClient, cfg.EventHistorySize, cfg.EventHistoryTTL)
+	}
+	eventRouter := service.NewEventRouter(redisClient, convRepo)
+	broker := sse.NewBroker(redisClient, sse.BrokerOptions{
+		ClientBufferSize: cfg.SSEClientBufferSize,
+		Policy:           sse.BackpressurePolicy(cfg.SSEBackpressurePolicy),
+		History:          eventHistory,
+		Router:           eventRouter,
+	})
 	defer broker.Close()
 
-	convService := service.NewConversationService(convRepo)
+	sessionEvents := service.NewSessionEvents(broker)
+
+	convLocker := service.NewRedisConversationLocker(redisClient)
+	convService := service.NewConversationService(convRepo, convLocker)
 	pairingService := service.NewPairingService(pairingCodeRepo, convRepo)
-	portalAccessService := service.NewPortalAccessService(portalAccessCodeRepo, convRepo, redisClient)
-	messageService := service.NewMessageService(inboundMsgRepo, outboundMsgRepo)
-	kakaoService := service.NewKakaoService()
+	rateLimiter := ratelimit.NewRedisLimiter(redisClient.Client)
+
+	portalAccessService := service.NewPortalAccessService(portalAccessCodeRepo, convRepo, redisClient, rateLimiter)
+	messageService := service.NewMessageService(
+		inboundMsgRepo, outboundMsgRepo, messageTimelineRepo, accountRepo,
+		service.NewRedisConversationStatsCache(redisClient, service.ConversationStatsTTL),
+	)
+	kakaoService := service.NewKakaoService(cfg.CallbackAllowedHosts)
+	experiments, err := service.LoadExperiments(cfg.ExperimentsFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load experiments")
+	}
+	experimentService := service.NewExperimentService(experimentRepo, experiments)
+	var kakaoProfileFetcher service.KakaoProfileFetcher
+	if cfg.KakaoAdminKey != "" {
+		kakaoProfileFetcher = service.NewKakaoProfileAPI(cfg.KakaoAdminKey)
+	}
+	kakaoProfileService := service.NewKakaoProfileService(kakaoProfileFetcher, convRepo, accountRepo, cfg.KakaoProfileTTL)
 	adminService := service.NewAdminService(
 		db.DB, adminSessionRepo, accountRepo, convRepo,
-		inboundMsgRepo, outboundMsgRepo, portalUserRepo, sessionRepo,
-		cfg.AdminPasswordHash, cfg.AdminSessionSecret,
+		inboundMsgRepo, outboundMsgRepo, portalUserRepo, sessionRepo, experimentRepo,
+		sessionEvents, deploymentService, adminSessionSecret, cfg.AdminSessionMaxAge,
+	)
+	publicStatsService := service.NewPublicStatsService(
+		adminService,
+		service.NewRedisPublicStatsCache(redisClient, service.PublicStatsTTL),
 	)
 	portalService := service.NewPortalService(
-		portalUserRepo, portalSessionRepo, accountRepo,
-		cfg.PortalSessionSecret,
+		portalUserRepo, portalSessionRepo, accountRepo, sessionEvents, mailer,
+		portalSessionSecret,
 	)
-	sessionService := service.NewSessionService(db, sessionRepo, accountRepo, broker)
-	ipRateLimiter := service.NewRateLimiter(redisClient.Client)
+	integrityService := service.NewIntegrityService(integrityRepo, cfg.CallbackTTL())
+	erasureService := service.NewErasureService(erasureRepo, blobStore)
+	if cfg.IntegrityCheckOnStartup {
+		ctx, cancel := context.WithTimeout(context.Background(), config.IntegrityCheckTimeout)
+		if err := integrityService.CheckOnStartup(ctx, cfg.IntegrityAutoRepair, alertNotifier); err != nil {
+			log.Error().Err(err).Msg("integrity check failed")
+		}
+		cancel()
+	}
+	sessionService := service.NewSessionService(db, sessionRepo, accountRepo, convRepo, convLocker, broker, cfg.MaxPendingSessionsPerIP, cfg.KakaoChannelID)
+	accountConfigService := service.NewAccountConfigService(accountRepo, convRepo)
+	accountSettingsService := service.NewAccountSettingsService(accountRepo, accountSettingsRepo)
+	changeHistoryService := service.NewChangeHistoryService(changeHistoryRepo)
 
 	authMiddleware := middleware.NewAuthMiddleware(accountRepo, sessionRepo)
-	rateLimitMiddleware := middleware.NewRedisRateLimitMiddleware(redisClient.Client)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimiter, sessionEvents, adminNotificationService)
 	adminSessionMiddleware := middleware.NewAdminSessionMiddleware(
-		adminSessionRepo, cfg.AdminPasswordHash, cfg.AdminSessionSecret,
+		adminSessionRepo, deploymentService.AdminPasswordHash, adminSessionSecret,
+		middleware.AdminSessionOptions{
+			IdleTimeout:   cfg.AdminSessionIdleTimeout,
+			BindIP:        cfg.AdminSessionBindIP,
+			BindUserAgent: cfg.AdminSessionBindUserAgent,
+		},
 	)
 	kakaoSignatureMiddleware := middleware.NewKakaoSignatureMiddleware(cfg.KakaoSignatureSecret)
 	portalSessionMiddleware := middleware.NewPortalSessionMiddleware(
-		portalSessionRepo, portalUserRepo, cfg.PortalSessionSecret,
+		portalSessionRepo, portalUserRepo, portalSessionSecret,
 	)
-	sessionCreateRateLimit := middleware.NewIPRateLimitMiddleware(ipRateLimiter, 10, 5*time.Minute, "session_create")
-	sessionStatusRateLimit := middleware.NewIPRateLimitMiddleware(ipRateLimiter, 30, 1*time.Minute, "session_status")
+	sessionCreateRateLimit := middleware.NewIPRateLimitMiddleware(
+		rateLimiter, cfg.IPRateLimitSessionCreate, "session_create", cfg.IPRateLimitExemptCIDRs,
+	)
+	sessionStatusRateLimit := middleware.NewIPRateLimitMiddleware(
+		rateLimiter, cfg.IPRateLimitSessionStatus, "session_status", cfg.IPRateLimitExemptCIDRs,
+	)
+	codeLoginRateLimit := middleware.NewIPRateLimitMiddleware(
+		rateLimiter, cfg.IPRateLimitCodeLogin, "code_login", cfg.IPRateLimitExemptCIDRs,
+	)
+
+	loginRateLimiter := middleware.NewLoginRateLimiter(rateLimiter)
+
+	pluginVersionMiddleware := middleware.NewPluginVersionMiddleware(pluginCompat, sessionRepo)
+
+	apiV1Middleware := middleware.NewAPIVersionMiddleware(httputil.APIVersionV1, cfg.APIV1Sunset)
+	apiV2Middleware := middleware.NewAPIVersionMiddleware(httputil.APIVersionV2, time.Time{})
 
+	realIPMiddleware := middleware.NewRealIPMiddleware(trustedProxies)
+	recovererMiddleware := middleware.NewRecovererMiddleware(errorReporter)
 	csrfMiddleware := middleware.NewCSRFMiddleware(isProduction)
 	bodyLimitMiddleware := middleware.NewBodyLimitMiddleware(0)
+	inFlightMiddleware := middleware.NewConcurrencyLimitMiddleware(cfg.MaxInFlightRequests, cfg.InFlightQueueSize, cfg.InFlightQueueTimeout)
 	securityHeadersMiddleware := middleware.NewSecurityHeadersMiddleware(isProduction)
+	localeMiddleware := middleware.NewLocaleMiddleware(cfg.Locale())
+	corsMiddleware := middleware.NewCORSMiddleware(cfg.CORSAllowedOrigins, []string{"/v1", "/v2", "/openclaw"})
 
+	var inboundQueue service.InboundQueue
+	if cfg.KakaoWebhookWorkers > 0 {
+		inboundQueue = service.NewRedisInboundQueue(redisClient, convLocker)
+	}
+	accountUsageService := service.NewAccountUsageService(accountRepo, rateLimiter)
+	onboardingService := service.NewOnboardingService(accountRepo)
+	setupChecklistService := service.NewSetupChecklistService(accountSetupRepo)
+	webhookDeliveryService := service.NewWebhookDeliveryService(
+		inboundMsgRepo, accountRepo, broker, sessionEvents, cfg.EncryptionKey, cfg.WebhookMaxAttempts,
+	)
 	kakaoHandler := handler.NewKakaoHandler(
-		convService, sessionService, messageService, portalAccessService, broker, cfg.CallbackTTL(), cfg.PortalBaseURL,
+		convService, sessionService, messageService, accountUsageService, onboardingService, portalAccessService, experimentService,
+		kakaoProfileService, webhookDeliveryService, kakaoService, rateLimiter, broker, cfg.CallbackTTL(), cfg.PortalBaseURL, cfg.Locale(),
+		service.InboundLimits{
+			MaxBodyBytes:    cfg.KakaoWebhookMaxBodyBytes,
+			MaxPayloadBytes: cfg.InboundPayloadMaxBytes,
+			MaxMessageBytes: cfg.InboundMessageMaxBytes,
+		},
+		inboundQueue,
+		handler.DegradedReply(cfg.KakaoDegradedReply),
+		cfg.ContentConsentPrompt,
 	)
-	eventsHandler := handler.NewEventsHandler(broker, messageService)
-	openclawHandler := handler.NewOpenClawHandler(messageService, kakaoService)
-	adminHandler := handler.NewAdminHandler(adminService, adminSessionMiddleware.Handler, isProduction)
+	eventSigner := loadEventSigner(deploymentService, cfg)
+	eventsHandler := handler.NewEventsHandler(broker, eventHistory, messageService, eventSigner, eventRouter, setupChecklistService)
+	openclawHandler := handler.NewOpenClawHandler(messageService, kakaoService, convService, broker, service.NewRedisReplyStreamBuffer(redisClient), eventRouter, cfg.ReplyOncePerMessage)
+	// Request frames are authenticated again, so a revoked token or an
+	// unpaired session stops working on an open stream, and count against
+	// the rate limit like the requests they replace
+	streamAPI := authMiddleware.Handler(rateLimitMiddleware.Handler(openclawHandler.Routes()))
+	wsHandler := handler.NewWSHandler(eventsHandler, streamAPI)
+	adminHandler := handler.NewAdminHandler(adminService, integrityService, erasureService, pairingService, sessionService, accountConfigService, accountSettingsService, changeHistoryService, adminNotificationService, deploymentService, broker, adminSessionMiddleware.Handler, loginRateLimiter, isProduction)
 	portalHandler := handler.NewPortalHandler(
-		portalService, pairingService, portalAccessService, convService, messageService, adminService, isProduction,
+		portalService, pairingService, portalAccessService, convService, messageService, publicStatsService, accountConfigService, onboardingService, accountSettingsService, changeHistoryService,
+		service.NewTestMessageService(messageService, broker), webhookDeliveryService, setupChecklistService, broker, isProduction,
 	)
 	sessionHandler := handler.NewSessionHandler(sessionService)
+	capabilitiesHandler := handler.NewCapabilitiesHandler(cfg.CallbackTTL(), middleware.DefaultMaxBodySize, pluginCompat, eventSigner, cfg.GRPCPort)
+	jwksHandler := handler.NewJWKSHandler(eventSigner)
 
 	r := chi.NewRouter()
 
 	r.Use(chimiddleware.RequestID)
-	r.Use(chimiddleware.RealIP)
+	r.Use(realIPMiddleware.Handler)
 	r.Use(middleware.RequestLogger)
-	r.Use(chimiddleware.Recoverer)
+	r.Use(recovererMiddleware.Handler)
 	r.Use(chimiddleware.Timeout(config.ServerRequestTimeout))
 	r.Use(bodyLimitMiddleware.Handler)
+	r.Use(corsMiddleware.Handler)
+
+	r.Get(handler.JWKSPath, jwksHandler.ServeHTTP)
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -141,46 +322,91 @@ func main() {
 		http.Redirect(w, r, "/portal/", http.StatusFound)
 	})
 
+	// The in-flight limit covers the routes that hit the database per request,
+	// not the long-lived event streams or the pairing long-poll.
 	r.Route("/kakao-talkchannel", func(r chi.Router) {
+		r.Use(inFlightMiddleware.Handler)
 		r.Use(kakaoSignatureMiddleware.Handler)
 		r.Post("/webhook", kakaoHandler.Webhook)
 	})
 
-	r.Route("/v1", func(r chi.Router) {
-		r.Use(authMiddleware.Handler)
-		r.Use(rateLimitMiddleware.Handler)
-		r.Get("/events", eventsHandler.ServeHTTP)
-	})
+	// Plugin-facing API. v1 keeps its original response format and is
+	// marked deprecated; v2 serves the same handlers with envelopes.
+	r.Group(func(r chi.Router) {
+		r.Use(apiV1Middleware.Handler)
+
+		r.Route("/v1", func(r chi.Router) {
+			r.Use(authMiddleware.Handler)
+			r.Use(pluginVersionMiddleware.Handler)
+			r.Use(rateLimitMiddleware.Handler)
+			r.Get("/events", eventsHandler.ServeHTTP)
+			r.Get("/ws", wsHandler.ServeHTTP)
+			r.With(inFlightMiddleware.Handler).Get("/events/history", eventsHandler.History)
+		})
+
+		r.Route("/openclaw", func(r chi.Router) {
+			r.Use(inFlightMiddleware.Handler)
+			r.Use(authMiddleware.Handler)
+			r.Use(pluginVersionMiddleware.Handler)
+			r.Use(rateLimitMiddleware.Handler)
+			r.Mount("/", openclawHandler.Routes())
+		})
+
+		r.Get("/v1/capabilities", capabilitiesHandler.ServeHTTP)
 
-	r.Route("/openclaw", func(r chi.Router) {
-		r.Use(authMiddleware.Handler)
-		r.Use(rateLimitMiddleware.Handler)
-		r.Mount("/", openclawHandler.Routes())
+		r.Route("/v1/sessions", func(r chi.Router) {
+			r.With(sessionCreateRateLimit.Handler, pluginVersionMiddleware.Handler).Post("/create", sessionHandler.CreateSession)
+			r.With(sessionStatusRateLimit.Handler).Get("/{sessionToken}/status", sessionHandler.GetSessionStatus)
+			r.With(sessionStatusRateLimit.Handler).Get("/{sessionToken}/link", sessionHandler.GetPairingLink)
+			r.With(authMiddleware.Handler, pluginVersionMiddleware.Handler, sessionStatusRateLimit.Handler).Get("/wait", sessionHandler.WaitForPairing)
+		})
 	})
 
-	r.Route("/v1/sessions", func(r chi.Router) {
-		r.With(sessionCreateRateLimit.Handler).Post("/create", sessionHandler.CreateSession)
-		r.With(sessionStatusRateLimit.Handler).Get("/{sessionToken}/status", sessionHandler.GetSessionStatus)
+	r.Route("/v2", func(r chi.Router) {
+		r.Use(apiV2Middleware.Handler)
+
+		r.Get("/capabilities", capabilitiesHandler.ServeHTTP)
+
+		r.Route("/sessions", func(r chi.Router) {
+			r.With(sessionCreateRateLimit.Handler, pluginVersionMiddleware.Handler).Post("/create", sessionHandler.CreateSession)
+			r.With(sessionStatusRateLimit.Handler).Get("/{sessionToken}/status", sessionHandler.GetSessionStatus)
+			r.With(sessionStatusRateLimit.Handler).Get("/{sessionToken}/link", sessionHandler.GetPairingLink)
+			r.With(authMiddleware.Handler, pluginVersionMiddleware.Handler, sessionStatusRateLimit.Handler).Get("/wait", sessionHandler.WaitForPairing)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.Handler)
+			r.Use(pluginVersionMiddleware.Handler)
+			r.Use(rateLimitMiddleware.Handler)
+			r.Get("/events", eventsHandler.ServeHTTP)
+			r.Get("/ws", wsHandler.ServeHTTP)
+			r.With(inFlightMiddleware.Handler).Get("/events/history", eventsHandler.History)
+			r.With(inFlightMiddleware.Handler).Mount("/openclaw", openclawHandler.Routes())
+		})
 	})
 
 	r.Route("/admin", func(r chi.Router) {
 		r.Use(securityHeadersMiddleware.Handler)
+		r.Use(localeMiddleware.Handler)
 		r.Use(csrfMiddleware.Handler)
 		r.Mount("/", adminHandler.Routes())
-		r.NotFound(handler.StaticFileServer("static/admin", "/admin").ServeHTTP)
+		r.NotFound(handler.StaticFileServer(cfg.StaticDir, "admin", "/admin").ServeHTTP)
 	})
 
 	r.Route("/portal", func(r chi.Router) {
 		r.Use(securityHeadersMiddleware.Handler)
+		r.Use(localeMiddleware.Handler)
 		r.Use(csrfMiddleware.Handler)
 
 		// API Routes
 		r.Route("/api", func(r chi.Router) {
 			// Public API
 			r.Get("/stats/public", portalHandler.GetPublicStats)
-			r.Post("/auth/code", portalHandler.LoginWithCode)
+			r.With(codeLoginRateLimit.Handler).Post("/auth/code", portalHandler.LoginWithCode)
 			r.Get("/code/stats", portalHandler.GetCodeStats)
 			r.Get("/code/messages", portalHandler.GetCodeMessages)
+			r.Post("/code/renew", portalHandler.RenewCodeSession)
+			r.Post("/code/logout", portalHandler.LogoutCodeSession)
 
 			// Authenticated API
 			r.Group(func(r chi.Router) {
@@ -188,27 +414,78 @@ func main() {
 				r.Post("/logout", portalHandler.Logout)
 				r.Get("/me", portalHandler.Me)
 				r.Get("/stats", portalHandler.GetStats)
+				r.Get("/dashboard", portalHandler.Dashboard)
+				r.Get("/setup-checklist", portalHandler.GetSetupChecklist)
 				r.Post("/pairing/generate", portalHandler.GeneratePairingCode)
+				r.Get("/pairing/codes", portalHandler.ListPairingCodes)
+				r.Delete("/pairing/codes/{code}", portalHandler.RevokePairingCode)
 				r.Get("/connections", portalHandler.ListConnections)
+				r.Patch("/connections/{conversationKey}", portalHandler.UpdateConnection)
 				r.Post("/connections/{conversationKey}/unpair", portalHandler.UnpairConnection)
 				r.Patch("/connections/{conversationKey}/block", portalHandler.BlockConnection)
+				r.Post("/test-message", portalHandler.SendTestMessage)
 				r.Get("/token", portalHandler.GetToken)
 				r.Post("/token/regenerate", portalHandler.RegenerateToken)
+				r.Get("/account/config", portalHandler.ExportAccountConfig)
+				r.Put("/account/config", portalHandler.ImportAccountConfig)
+				r.Get("/account/timezone", portalHandler.GetAccountTimezone)
+				r.Put("/account/timezone", portalHandler.UpdateAccountTimezone)
+				r.Get("/account/profile-sharing", portalHandler.GetProfileSharing)
+				r.Put("/account/profile-sharing", portalHandler.UpdateProfileSharing)
+				r.Get("/account/onboarding", portalHandler.GetOnboarding)
+				r.Put("/account/onboarding", portalHandler.UpdateOnboarding)
+				r.Get("/account/settings", portalHandler.GetAccountSettings)
+				r.Patch("/account/settings", portalHandler.UpdateAccountSettings)
+				r.Get("/account/history", portalHandler.GetChangeHistory)
+				r.Get("/account/webhook", portalHandler.GetWebhook)
+				r.Put("/account/webhook", portalHandler.UpdateWebhook)
+				r.Delete("/account/webhook", portalHandler.DeleteWebhook)
+				r.Get("/account/deletion-preview", portalHandler.PreviewDeleteAccount)
 				r.Delete("/account", portalHandler.DeleteAccount)
+				r.Get("/account/deletion", portalHandler.GetAccountDeletion)
+				r.Post("/account/deletion/cancel", portalHandler.CancelAccountDeletion)
 				r.Get("/messages", portalHandler.GetMessages)
 			})
 		})
 
-		r.NotFound(handler.StaticFileServer("static/portal", "/portal").ServeHTTP)
+		r.NotFound(handler.StaticFileServer(cfg.StaticDir, "portal", "/portal").ServeHTTP)
 	})
 
 	cleanupJob := jobs.NewCleanupJob(
 		adminSessionRepo, portalSessionRepo, portalAccessCodeRepo, pairingCodeRepo, inboundMsgRepo,
-		sessionRepo, config.CleanupJobInterval,
+		sessionRepo, adminNotificationRepo, config.CleanupJobInterval, alertNotifier, cfg.InboundBacklogAlertAge,
 	)
 	cleanupJob.Start()
 	defer cleanupJob.Stop()
 
+	reconcileJob := jobs.NewReconcileJob(integrityRepo, config.ReconcileJobInterval, alertNotifier)
+	reconcileJob.Start()
+	defer reconcileJob.Stop()
+
+	sessionExpiryJob := jobs.NewSessionExpiryJob(sessionService, config.SessionExpiryJobInterval, alertNotifier)
+	sessionExpiryJob.Start()
+	defer sessionExpiryJob.Stop()
+
+	accountPurgeJob := jobs.NewAccountPurgeJob(portalService, config.AccountPurgeJobInterval, alertNotifier)
+	accountPurgeJob.Start()
+	defer accountPurgeJob.Stop()
+
+	scheduledSendJob := jobs.NewScheduledSendJob(
+		service.NewOutboundScheduler(outboundMsgRepo, kakaoService, sessionEvents), config.ScheduledSendJobInterval, alertNotifier,
+	)
+	scheduledSendJob.Start()
+	defer scheduledSendJob.Stop()
+
+	webhookDeliveryJob := jobs.NewWebhookDeliveryJob(webhookDeliveryService, config.WebhookDeliveryJobInterval, alertNotifier)
+	webhookDeliveryJob.Start()
+	defer webhookDeliveryJob.Stop()
+
+	if inboundQueue != nil {
+		inboundWorker := jobs.NewInboundWorker(inboundQueue, kakaoHandler.ProcessInbound, cfg.KakaoWebhookWorkers)
+		inboundWorker.Start()
+		defer inboundWorker.Stop()
+	}
+
 	server := &http.Server{
 		Addr:         cfg.Addr(),
 		Handler:      r,
@@ -224,6 +501,35 @@ func main() {
 		}
 	}()
 
+	var grpcServer *grpc.Server
+	if addr := cfg.GRPCAddr(); addr != "" {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatal().Err(err).Str("addr", addr).Msg("failed to listen for gRPC")
+		}
+		var opts []grpc.ServerOption
+		if cfg.GRPCTLSCert != "" {
+			creds, err := credentials.NewServerTLSFromFile(cfg.GRPCTLSCert, cfg.GRPCTLSKey)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to load gRPC TLS certificate")
+			}
+			opts = append(opts, grpc.Creds(creds))
+		} else {
+			log.Warn().Str("addr", addr).Msg("serving gRPC without TLS; a proxy in front is expected to terminate it")
+		}
+		grpcServer = grpc.NewServer(opts...)
+		relaypb.RegisterRelayServer(grpcServer, relaygrpc.NewServer(
+			eventsHandler, streamAPI,
+			chi.Chain(authMiddleware.Handler, pluginVersionMiddleware.Handler, rateLimitMiddleware.Handler).Handler,
+		))
+		go func() {
+			log.Info().Str("addr", addr).Msg("starting gRPC server")
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal().Err(err).Msg("gRPC server error")
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -235,10 +541,119 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Error().Err(err).Msg("server forced to shutdown")
 	}
+	if grpcServer != nil {
+		// Streams only end when clients close them
+		grpcServer.Stop()
+	}
 
 	log.Info().Msg("server stopped")
 }
 
+// checkMigrations warns when migrations this server was built with are not
+// applied to the database. It does not stop the server: most migrations only
+// add tables or columns that unrelated features can run without.
+func checkMigrations(repo repository.SchemaMigrationRepository, notifier alert.Notifier) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.MigrationCheckTimeout)
+	defer cancel()
+
+	pending, err := service.CheckMigrations(ctx, repo, drizzle.MigrationNames(), notifier)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to check database migrations")
+		return
+	}
+	if len(pending) > 0 {
+		log.Warn().Strs("pending", pending).Msg("database migrations are not applied: run make db-migrate")
+	}
+}
+
+// loadDeploymentSettings loads the stored deployment settings and resolves
+// the session secrets, generating them on first start when they are not set
+// in the environment.
+func loadDeploymentSettings(deploymentService *service.DeploymentService, cfg *config.Config) (adminSecret, portalSecret string) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.DeploymentSettingsTimeout)
+	defer cancel()
+
+	if err := deploymentService.Load(ctx); err != nil {
+		log.Fatal().Err(err).Msg("failed to load deployment settings")
+	}
+
+	adminSecret, err := deploymentService.SessionSecret(ctx, model.DeploymentSettingAdminSessionSecret, cfg.AdminSessionSecret)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to resolve admin session secret")
+	}
+	portalSecret, err = deploymentService.SessionSecret(ctx, model.DeploymentSettingPortalSessionSecret, cfg.PortalSessionSecret)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to resolve portal session secret")
+	}
+
+	if token := deploymentService.SetupToken(); token != "" {
+		log.Warn().
+			Str("setupToken", token).
+			Msg("no admin password configured: finish setup with POST /admin/api/bootstrap using this setup token")
+	}
+
+	return adminSecret, portalSecret
+}
+
+// loadEventSigner returns the SSE event signer, or nil when signing is
+// disabled. The key seed is generated on first start when not set.
+func loadEventSigner(deploymentService *service.DeploymentService, cfg *config.Config) *sse.Signer {
+	if !cfg.SSESigningEnabled {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.DeploymentSettingsTimeout)
+	defer cancel()
+
+	secret, err := deploymentService.SessionSecret(ctx, model.DeploymentSettingSSESigningKey, cfg.SSESigningKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to resolve SSE signing key")
+	}
+
+	signer := sse.NewSigner(secret)
+	log.Info().Str("kid", signer.KeyID()).Msg("SSE event signing enabled")
+	return signer
+}
+
+// loadBlobStore returns the store large Kakao payloads are offloaded to, or
+// nil when they stay in Postgres.
+func loadBlobStore(cfg *config.Config) blob.Store {
+	var store blob.Store
+	var err error
+	switch cfg.BlobStore {
+	case "":
+		return nil
+	case "local":
+		store, err = blob.NewLocalStore(cfg.BlobDir)
+	case "s3", "gcs":
+		s3cfg := blob.S3Config{
+			Endpoint:        cfg.BlobEndpoint,
+			Region:          cfg.BlobRegion,
+			Bucket:          cfg.BlobBucket,
+			AccessKeyID:     cfg.BlobAccessKeyID,
+			SecretAccessKey: cfg.BlobSecretAccessKey,
+		}
+		if cfg.BlobStore == "gcs" {
+			if s3cfg.Endpoint == "" {
+				s3cfg.Endpoint = blob.GCSEndpoint
+			}
+			if s3cfg.Region == "" {
+				s3cfg.Region = "auto"
+			}
+		}
+		store, err = blob.NewS3Store(s3cfg)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to open blob store")
+	}
+
+	log.Info().
+		Str("backend", cfg.BlobStore).
+		Int("minBytes", cfg.BlobOffloadMinBytes).
+		Msg("offloading large inbound payloads to blob storage")
+	return store
+}
+
 func setLogLevel(level string) {
 	switch level {
 	case "debug":
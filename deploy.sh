@@ -9,7 +9,9 @@ CLOUD_SQL_INSTANCE="${CLOUD_SQL_INSTANCE:?CLOUD_SQL_INSTANCE is required}"
 VPC_CONNECTOR="${VPC_CONNECTOR:?VPC_CONNECTOR is required}"
 IMAGE_NAME="${REGION}-docker.pkg.dev/${PROJECT_ID}/cloud-run-source-deploy/${SERVICE_NAME}"
 
-ENV_VARS="LOG_LEVEL=info,CALLBACK_TTL_SECONDS=55"
+# Cloud Run forwards the client address in X-Forwarded-For; without trusting
+# its proxies every client shares one IP for per-IP rate limits
+ENV_VARS="LOG_LEVEL=info,CALLBACK_TTL_SECONDS=55,TRUSTED_PROXY_PRESETS=gcp"
 if [[ -n "${PORTAL_BASE_URL:-}" ]]; then
   ENV_VARS="${ENV_VARS},PORTAL_BASE_URL=${PORTAL_BASE_URL}"
 fi
@@ -0,0 +1,24 @@
+// Package drizzle embeds the SQL migrations so the server can tell which of
+// them the database has not applied yet.
+package drizzle
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// MigrationNames lists the migrations in the order they are applied, by file
+// name without the .sql extension.
+func MigrationNames() []string {
+	// ReadDir returns the files sorted by name, which is their order
+	entries, _ := fs.ReadDir(migrations, "migrations")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".sql"))
+	}
+	return names
+}
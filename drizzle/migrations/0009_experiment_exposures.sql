@@ -0,0 +1,15 @@
+-- Experiment exposures: which variant of a relay-generated message each conversation saw
+
+CREATE TABLE "experiment_exposures" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"experiment" text NOT NULL,
+	"variant" text NOT NULL,
+	"conversation_key" text NOT NULL,
+	"first_shown_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"last_shown_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"shown_count" integer DEFAULT 1 NOT NULL
+);
+
+-- One exposure row per conversation per experiment
+CREATE UNIQUE INDEX "experiment_exposures_experiment_conv_idx" ON "experiment_exposures" USING btree ("experiment", "conversation_key");
+CREATE INDEX "experiment_exposures_experiment_variant_idx" ON "experiment_exposures" USING btree ("experiment", "variant");
@@ -0,0 +1,5 @@
+-- Track the client IP that created a session, to cap concurrent pending sessions per IP
+
+ALTER TABLE "sessions" ADD COLUMN "client_ip" text;
+
+CREATE INDEX "sessions_pending_client_ip_idx" ON "sessions" USING btree ("client_ip", "created_at") WHERE "status" = 'pending_pairing';
@@ -0,0 +1,3 @@
+-- Record the OpenClaw plugin version reported by each session
+
+ALTER TABLE "sessions" ADD COLUMN "plugin_version" text;
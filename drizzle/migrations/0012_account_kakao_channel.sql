@@ -0,0 +1,3 @@
+-- Per-account Kakao channel used for chat deep links (falls back to KAKAO_CHANNEL_ID)
+
+ALTER TABLE "accounts" ADD COLUMN "kakao_channel_id" text;
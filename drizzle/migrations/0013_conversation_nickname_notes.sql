@@ -0,0 +1,4 @@
+-- User-assigned display nickname and notes for each connection
+
+ALTER TABLE "conversation_mappings" ADD COLUMN "nickname" text;
+ALTER TABLE "conversation_mappings" ADD COLUMN "notes" text;
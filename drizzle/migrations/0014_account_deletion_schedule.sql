@@ -0,0 +1,5 @@
+-- Portal account deletion is scheduled; the purge job removes accounts once deletion_scheduled_at passes
+
+ALTER TABLE "accounts" ADD COLUMN "deletion_requested_at" timestamp with time zone;
+ALTER TABLE "accounts" ADD COLUMN "deletion_scheduled_at" timestamp with time zone;
+CREATE INDEX "accounts_deletion_scheduled_at_idx" ON "accounts" ("deletion_scheduled_at") WHERE "deletion_scheduled_at" IS NOT NULL;
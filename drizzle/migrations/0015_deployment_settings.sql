@@ -0,0 +1,8 @@
+-- Deployment-wide settings written by the first-run bootstrap (admin password hash,
+-- generated session secrets, deployment metadata). Environment variables take precedence.
+
+CREATE TABLE "deployment_settings" (
+	"key" text PRIMARY KEY NOT NULL,
+	"value" text NOT NULL,
+	"updated_at" timestamp with time zone DEFAULT now() NOT NULL
+);
@@ -0,0 +1,5 @@
+-- Admin sessions expire after inactivity (last_seen_at) and may be bound to the creating client
+
+ALTER TABLE "admin_sessions" ADD COLUMN "last_seen_at" timestamp with time zone DEFAULT now() NOT NULL;
+ALTER TABLE "admin_sessions" ADD COLUMN "ip_address" text;
+ALTER TABLE "admin_sessions" ADD COLUMN "user_agent" text;
@@ -0,0 +1,3 @@
+-- Time the admin last entered the password in this session (login or step-up re-authentication)
+
+ALTER TABLE "admin_sessions" ADD COLUMN "authenticated_at" timestamp with time zone DEFAULT now() NOT NULL;
@@ -0,0 +1,3 @@
+-- Per-account timezone used for daily boundaries such as "today" in stats
+
+ALTER TABLE "accounts" ADD COLUMN "timezone" text DEFAULT 'Asia/Seoul' NOT NULL;
@@ -0,0 +1,7 @@
+-- Support the merged message timeline, which orders each direction by
+-- created_at within an account or conversation
+
+CREATE INDEX "inbound_messages_account_created_idx" ON "inbound_messages" USING btree ("account_id", "created_at" DESC);
+CREATE INDEX "outbound_messages_account_created_idx" ON "outbound_messages" USING btree ("account_id", "created_at" DESC);
+CREATE INDEX "inbound_messages_conversation_created_idx" ON "inbound_messages" USING btree ("conversation_key", "created_at" DESC);
+CREATE INDEX "outbound_messages_conversation_created_idx" ON "outbound_messages" USING btree ("conversation_key", "created_at" DESC);
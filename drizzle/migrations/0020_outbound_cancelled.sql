@@ -0,0 +1,3 @@
+-- Pending outbound messages can be cancelled by the plugin before delivery
+
+ALTER TYPE "public"."outbound_message_status" ADD VALUE 'cancelled';
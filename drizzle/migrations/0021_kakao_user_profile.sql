@@ -0,0 +1,7 @@
+-- Kakao user profile fetched with the channel's admin key, and a per-account
+-- switch for sharing it with plugins
+
+ALTER TABLE "conversation_mappings" ADD COLUMN "kakao_nickname" text;
+ALTER TABLE "conversation_mappings" ADD COLUMN "kakao_profile_image_url" text;
+ALTER TABLE "conversation_mappings" ADD COLUMN "kakao_profile_fetched_at" timestamp with time zone;
+ALTER TABLE "accounts" ADD COLUMN "share_kakao_profile" boolean DEFAULT true NOT NULL;
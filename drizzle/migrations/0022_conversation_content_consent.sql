@@ -0,0 +1,5 @@
+-- Whether the Kakao user agreed to having message bodies stored ('granted',
+-- 'denied'; NULL when never asked)
+
+ALTER TABLE "conversation_mappings" ADD COLUMN "content_consent" text;
+ALTER TABLE "conversation_mappings" ADD COLUMN "content_consent_at" timestamp with time zone;
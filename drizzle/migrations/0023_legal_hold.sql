@@ -0,0 +1,7 @@
+-- Legal hold set by an admin. Held accounts and conversations are skipped by
+-- scheduled purges and refused by deletion and erasure until released.
+
+ALTER TABLE "accounts" ADD COLUMN "legal_hold_at" timestamp with time zone;
+ALTER TABLE "accounts" ADD COLUMN "legal_hold_reason" text;
+ALTER TABLE "conversation_mappings" ADD COLUMN "legal_hold_at" timestamp with time zone;
+ALTER TABLE "conversation_mappings" ADD COLUMN "legal_hold_reason" text;
@@ -0,0 +1,4 @@
+-- Per-account welcome sequence sent with a successful pairing; NULL uses the
+-- relay's default
+
+ALTER TABLE "accounts" ADD COLUMN "onboarding" jsonb;
@@ -0,0 +1,4 @@
+-- Archived conversations keep their pairing but are not relayed until the
+-- Kakao user sends another message
+
+ALTER TYPE "public"."pairing_state" ADD VALUE 'archived';
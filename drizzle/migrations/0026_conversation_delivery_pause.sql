@@ -0,0 +1,6 @@
+-- Delivery pause set by the agent. Messages of a paused conversation are
+-- stored but held back from the account until delivery is resumed; the Kakao
+-- user is answered with the auto reply instead.
+
+ALTER TABLE "conversation_mappings" ADD COLUMN "delivery_paused_at" timestamp with time zone;
+ALTER TABLE "conversation_mappings" ADD COLUMN "pause_auto_reply" text;
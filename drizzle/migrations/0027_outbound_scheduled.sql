@@ -0,0 +1,8 @@
+-- Scheduled replies. The callback URL is resolved when the reply is
+-- scheduled and kept until it is sent; dispatched_at marks the message as
+-- claimed by a sender so instances do not send it twice.
+
+ALTER TABLE "outbound_messages" ADD COLUMN "scheduled_at" timestamp with time zone;
+ALTER TABLE "outbound_messages" ADD COLUMN "callback_url" text;
+ALTER TABLE "outbound_messages" ADD COLUMN "dispatched_at" timestamp with time zone;
+CREATE INDEX "outbound_messages_scheduled_idx" ON "outbound_messages" ("scheduled_at") WHERE "status" = 'pending' AND "scheduled_at" IS NOT NULL AND "dispatched_at" IS NULL;
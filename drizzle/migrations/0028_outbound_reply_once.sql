@@ -0,0 +1,7 @@
+-- One successful reply per inbound message. Replies created with reply_once
+-- are unique per inbound message while pending or sent, so a retried reply
+-- is refused instead of posted to Kakao twice; failed and cancelled replies
+-- leave room for another attempt.
+
+ALTER TABLE "outbound_messages" ADD COLUMN "reply_once" boolean DEFAULT false NOT NULL;
+CREATE UNIQUE INDEX "outbound_messages_reply_once_idx" ON "outbound_messages" ("inbound_message_id") WHERE "reply_once" AND "status" IN ('pending', 'sent');
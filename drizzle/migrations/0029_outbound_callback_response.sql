@@ -0,0 +1,6 @@
+-- What Kakao answered when a reply was posted to its callback URL, kept to
+-- debug replies that were sent but never reached the user.
+
+ALTER TABLE "outbound_messages" ADD COLUMN "callback_status" integer;
+ALTER TABLE "outbound_messages" ADD COLUMN "callback_response" text;
+ALTER TABLE "outbound_messages" ADD COLUMN "callback_task_id" text;
@@ -0,0 +1,12 @@
+-- Webhook delivery: accounts with a webhook URL get inbound messages POSTed to
+-- it instead of consuming them over the event stream. The signing secret is
+-- encrypted with ENCRYPTION_KEY when one is configured.
+
+ALTER TABLE "accounts" ADD COLUMN "webhook_url" text;
+ALTER TABLE "accounts" ADD COLUMN "webhook_secret" text;
+
+ALTER TABLE "inbound_messages" ADD COLUMN "webhook_attempts" integer DEFAULT 0 NOT NULL;
+ALTER TABLE "inbound_messages" ADD COLUMN "webhook_next_at" timestamp with time zone;
+ALTER TABLE "inbound_messages" ADD COLUMN "webhook_error" text;
+
+CREATE INDEX "inbound_messages_webhook_due_idx" ON "inbound_messages" ("webhook_next_at") WHERE "status" = 'queued';
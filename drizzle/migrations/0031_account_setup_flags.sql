@@ -0,0 +1,11 @@
+-- Setup checklist steps an account has completed. Most steps are derived from
+-- existing records; a flag is kept once a step is done so it stays done after
+-- those records are cleaned up, and for steps no record shows (the token was
+-- shown, a plugin connected with the account token).
+
+CREATE TABLE "account_setup_flags" (
+	"account_id" uuid NOT NULL REFERENCES "accounts"("id") ON DELETE CASCADE,
+	"step" text NOT NULL,
+	"done_at" timestamp with time zone DEFAULT now() NOT NULL,
+	PRIMARY KEY ("account_id", "step")
+);
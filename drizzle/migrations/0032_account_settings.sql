@@ -0,0 +1,8 @@
+-- Per-account settings as one JSON document keyed by setting name. Settings
+-- without a stored value use the default the server defines for them.
+
+CREATE TABLE "account_settings" (
+	"account_id" uuid PRIMARY KEY NOT NULL REFERENCES "accounts"("id") ON DELETE CASCADE,
+	"settings" jsonb DEFAULT '{}'::jsonb NOT NULL,
+	"updated_at" timestamp with time zone DEFAULT now() NOT NULL
+);
@@ -0,0 +1,17 @@
+-- Who changed what and when, for account settings and conversation states.
+-- Values are JSON; a NULL value means the setting had its default or the
+-- conversation did not exist.
+
+CREATE TABLE "change_history" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"account_id" uuid REFERENCES "accounts"("id") ON DELETE CASCADE,
+	"entity_type" text NOT NULL,
+	"entity_key" text NOT NULL,
+	"old_value" jsonb,
+	"new_value" jsonb,
+	"actor" text NOT NULL,
+	"changed_at" timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX "change_history_account_idx" ON "change_history" ("account_id", "changed_at" DESC);
+CREATE INDEX "change_history_entity_idx" ON "change_history" ("entity_type", "entity_key", "changed_at" DESC);
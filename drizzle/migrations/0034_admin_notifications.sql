@@ -0,0 +1,66 @@
+-- Admin notification center: alerts raised by the server (anomalies, job
+-- failures, quota breaches, pending migrations), kept with read state for
+-- admins whether or not an ops webhook is configured. While a notification
+-- is unread, the same alert for the same subject updates it instead of
+-- adding another.
+
+CREATE TABLE "admin_notifications" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"kind" text NOT NULL,
+	"name" text NOT NULL,
+	"subject" text DEFAULT '' NOT NULL,
+	"message" text NOT NULL,
+	"details" jsonb,
+	"occurrences" integer DEFAULT 1 NOT NULL,
+	"created_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"last_occurred_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"resolved_at" timestamp with time zone,
+	"read_at" timestamp with time zone
+);
+
+CREATE UNIQUE INDEX "admin_notifications_unread_idx" ON "admin_notifications" ("name", "subject") WHERE "read_at" IS NULL;
+CREATE INDEX "admin_notifications_recent_idx" ON "admin_notifications" ("last_occurred_at" DESC);
+
+-- Migrations applied to this database, so the server can report the ones
+-- that are pending. make db-migrate records each file it applies.
+CREATE TABLE "schema_migrations" (
+	"name" text PRIMARY KEY NOT NULL,
+	"applied_at" timestamp with time zone DEFAULT now() NOT NULL
+);
+
+INSERT INTO "schema_migrations" ("name") VALUES
+	('0000_cultured_unicorn'),
+	('0001_pairing_system'),
+	('0002_portal_users'),
+	('0003_portal_sessions'),
+	('0004_admin_sessions'),
+	('0005_oauth'),
+	('0006_sessions'),
+	('0007_portal_access_codes'),
+	('0008_remove_plaintext_tokens'),
+	('0009_experiment_exposures'),
+	('0010_session_client_ip'),
+	('0011_session_plugin_version'),
+	('0012_account_kakao_channel'),
+	('0013_conversation_nickname_notes'),
+	('0014_account_deletion_schedule'),
+	('0015_deployment_settings'),
+	('0016_admin_session_hardening'),
+	('0017_admin_session_reauth'),
+	('0018_account_timezone'),
+	('0019_message_timeline_indexes'),
+	('0020_outbound_cancelled'),
+	('0021_kakao_user_profile'),
+	('0022_conversation_content_consent'),
+	('0023_legal_hold'),
+	('0024_account_onboarding'),
+	('0025_conversation_archived'),
+	('0026_conversation_delivery_pause'),
+	('0027_outbound_scheduled'),
+	('0028_outbound_reply_once'),
+	('0029_outbound_callback_response'),
+	('0030_account_webhook'),
+	('0031_account_setup_flags'),
+	('0032_account_settings'),
+	('0033_change_history'),
+	('0034_admin_notifications');
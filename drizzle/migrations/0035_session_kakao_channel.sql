@@ -0,0 +1,4 @@
+-- Channel of the account whose relay token created a pending session, so its
+-- pairing link points at that channel instead of the deployment's.
+
+ALTER TABLE "sessions" ADD COLUMN "kakao_channel_id" text;
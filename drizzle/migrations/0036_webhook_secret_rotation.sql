@@ -0,0 +1,6 @@
+-- Webhook secret rotation: the secret replaced by a rotation keeps signing
+-- webhook requests next to the new one until webhook_previous_secret_expires_at,
+-- so receivers can switch over without rejecting deliveries.
+
+ALTER TABLE "accounts" ADD COLUMN "webhook_previous_secret" text;
+ALTER TABLE "accounts" ADD COLUMN "webhook_previous_secret_expires_at" timestamp with time zone;
@@ -0,0 +1,10 @@
+-- Webhook failover: after webhook_failures consecutive failed posts the
+-- account's messages are queued for the event stream again
+-- (webhook_degraded_at), and the endpoint is probed at webhook_probe_at until
+-- it answers, which restores webhook delivery.
+
+ALTER TABLE "accounts" ADD COLUMN "webhook_failures" integer DEFAULT 0 NOT NULL;
+ALTER TABLE "accounts" ADD COLUMN "webhook_degraded_at" timestamp with time zone;
+ALTER TABLE "accounts" ADD COLUMN "webhook_probe_at" timestamp with time zone;
+
+CREATE INDEX "accounts_webhook_probe_idx" ON "accounts" ("webhook_probe_at") WHERE "webhook_degraded_at" IS NOT NULL;
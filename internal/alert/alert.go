@@ -0,0 +1,85 @@
+// Package alert notifies operators of conditions that need attention, such as
+// a stuck inbound backlog. The Notifier interface keeps jobs independent of
+// the concrete channel (logs, ops webhook, admin notification center).
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Status is whether a condition started or ended.
+type Status string
+
+const (
+	StatusFiring   Status = "firing"
+	StatusResolved Status = "resolved"
+)
+
+// Kind groups alerts in the admin notification center.
+type Kind string
+
+const (
+	KindAnomaly          Kind = "anomaly"
+	KindJobFailure       Kind = "job_failure"
+	KindQuotaBreach      Kind = "quota_breach"
+	KindMigrationPending Kind = "migration_pending"
+)
+
+// Kinds lists the alert kinds.
+var Kinds = []Kind{KindAnomaly, KindJobFailure, KindQuotaBreach, KindMigrationPending}
+
+// Alert is a condition that started or ended.
+type Alert struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	// What the alert is about when the same alert can fire for several
+	// things at once, such as the failing job or the account
+	Subject string         `json:"subject,omitempty"`
+	Status  Status         `json:"status"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+	At      time.Time      `json:"at"`
+}
+
+// Notifier delivers alerts to operators.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert)
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a Notifier that only logs alerts. It is used when no
+// ops webhook is configured.
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, alert Alert) {
+	logEvent := log.Warn()
+	if alert.Status == StatusResolved {
+		logEvent = log.Info()
+	}
+	logEvent.
+		Str("alert", alert.Name).
+		Str("subject", alert.Subject).
+		Str("status", string(alert.Status)).
+		Fields(alert.Details).
+		Msg(alert.Message)
+}
+
+type multiNotifier []Notifier
+
+// NewMultiNotifier returns a Notifier delivering every alert to each of
+// notifiers in order.
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+func (m multiNotifier) Notify(ctx context.Context, alert Alert) {
+	for _, n := range m {
+		n.Notify(ctx, alert)
+	}
+}
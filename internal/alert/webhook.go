@@ -0,0 +1,63 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const webhookTimeout = 5 * time.Second
+
+// webhookNotifier posts alerts as JSON to an ops webhook. Alerts are also
+// logged, so they are not lost when the webhook is down.
+type webhookNotifier struct {
+	url      string
+	client   *http.Client
+	fallback Notifier
+}
+
+// NewWebhookNotifier creates a Notifier posting to the given http(s) URL.
+func NewWebhookNotifier(webhookURL string) (Notifier, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OPS_ALERT_WEBHOOK_URL: must be an http(s) URL")
+	}
+	return &webhookNotifier{
+		url:      webhookURL,
+		client:   &http.Client{Timeout: webhookTimeout},
+		fallback: NewLogNotifier(),
+	}, nil
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, alert Alert) {
+	n.fallback.Notify(ctx, alert)
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to encode alert")
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to build alert request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		log.Warn().Err(err).Str("alert", alert.Name).Msg("failed to send alert")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Warn().Int("status", resp.StatusCode).Str("alert", alert.Name).Msg("ops webhook rejected alert")
+	}
+}
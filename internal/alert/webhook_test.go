@@ -0,0 +1,58 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookNotifier(t *testing.T) {
+	_, err := NewWebhookNotifier("https://ops.example.com/hooks/relay")
+	assert.NoError(t, err)
+
+	for _, invalid := range []string{"", "ops.example.com", "ftp://ops.example.com", "https://"} {
+		_, err := NewWebhookNotifier(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	received := make(chan Alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var alert Alert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(server.URL)
+	require.NoError(t, err)
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	notifier.Notify(context.Background(), Alert{
+		Name:    "inbound_backlog",
+		Status:  StatusFiring,
+		Message: "stuck",
+		Details: map[string]any{"queued": 3},
+		At:      at,
+	})
+
+	select {
+	case alert := <-received:
+		assert.Equal(t, "inbound_backlog", alert.Name)
+		assert.Equal(t, StatusFiring, alert.Status)
+		assert.Equal(t, float64(3), alert.Details["queued"])
+		assert.True(t, alert.At.Equal(at))
+	default:
+		t.Fatal("alert was not posted")
+	}
+}
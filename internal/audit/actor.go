@@ -0,0 +1,36 @@
+package audit
+
+import "context"
+
+type actorContextKey struct{}
+
+// Actors recorded in the change history. Portal users and plugin sessions
+// are recorded with their ID; see PortalActor and PluginActor.
+const (
+	// Background jobs and anything else not triggered by a request
+	ActorSystem = "system"
+	ActorAdmin  = "admin"
+	// A Kakao user, through a message or chat command
+	ActorKakaoUser = "kakao_user"
+)
+
+// WithActor returns a context whose changes are recorded as made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// Actor returns who the changes made with ctx are recorded as made by.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorSystem
+}
+
+func PortalActor(userID string) string {
+	return "portal_user:" + userID
+}
+
+func PluginActor(sessionID string) string {
+	return "plugin_session:" + sessionID
+}
@@ -7,25 +7,42 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/httputil"
 )
 
 type EventType string
 
 const (
-	EventLoginSuccess    EventType = "login_success"
-	EventLoginFailure    EventType = "login_failure"
-	EventLogout          EventType = "logout"
-	EventTokenRegenerate EventType = "token_regenerate"
-	EventAccountCreate   EventType = "account_create"
-	EventAccountDelete   EventType = "account_delete"
-	EventUserDelete      EventType = "user_delete"
-	EventRateLimitExceed EventType = "rate_limit_exceeded"
-	EventCSRFFailure     EventType = "csrf_failure"
-	EventAuthFailure     EventType = "auth_failure"
-	EventSessionCreate   EventType = "session_create"
-	EventSessionDelete   EventType = "session_delete"
-	EventCodeGenerate    EventType = "code_generate"
-	EventCodeLogin       EventType = "code_login"
+	EventLoginSuccess     EventType = "login_success"
+	EventLoginFailure     EventType = "login_failure"
+	EventLogout           EventType = "logout"
+	EventTokenRegenerate  EventType = "token_regenerate"
+	EventAccountCreate    EventType = "account_create"
+	EventAccountUpdate    EventType = "account_update"
+	EventAccountDelete    EventType = "account_delete"
+	EventUserDelete       EventType = "user_delete"
+	EventRateLimitExceed  EventType = "rate_limit_exceeded"
+	EventCSRFFailure      EventType = "csrf_failure"
+	EventAuthFailure      EventType = "auth_failure"
+	EventSessionCreate    EventType = "session_create"
+	EventSessionDelete    EventType = "session_delete"
+	EventCodeGenerate     EventType = "code_generate"
+	EventCodeLogin        EventType = "code_login"
+	EventCodeRevoke       EventType = "code_revoke"
+	EventSessionRevoke    EventType = "session_revoke"
+	EventForcePair        EventType = "force_pair"
+	EventDeleteSchedule   EventType = "account_delete_schedule"
+	EventDeleteCancel     EventType = "account_delete_cancel"
+	EventConfigImport     EventType = "config_import"
+	EventBootstrap        EventType = "bootstrap"
+	EventPasswordChange   EventType = "password_change"
+	EventKakaoUserErase   EventType = "kakao_user_erase"
+	EventLegalHoldSet     EventType = "legal_hold_set"
+	EventLegalHoldRelease EventType = "legal_hold_release"
+	EventInboundExpire    EventType = "inbound_expire"
+	EventSettingsUpdate   EventType = "account_settings_update"
+	EventWebhookRotate    EventType = "webhook_secret_rotate"
 )
 
 type Event struct {
@@ -80,17 +97,7 @@ func addField(e *zerolog.Event, key string, value interface{}) *zerolog.Event {
 }
 
 func LogFromRequest(r *http.Request, event Event) {
-	event.IP = getClientIP(r)
+	event.IP = httputil.ClientIP(r)
 	event.UserAgent = r.UserAgent()
 	Log(r.Context(), event)
 }
-
-func getClientIP(r *http.Request) string {
-	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-		return forwarded
-	}
-	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-		return realIP
-	}
-	return r.RemoteAddr
-}
@@ -0,0 +1,51 @@
+// Package blob keeps large message payloads in object storage so that only a
+// reference is stored in Postgres. The Store interface keeps callers
+// independent of the backend (local disk, S3, GCS).
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by Get for a key that does not exist.
+var ErrNotFound = errors.New("blob not found")
+
+// Store saves opaque blobs under slash-separated keys. Put overwrites an
+// existing blob; Delete of a missing key is not an error.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every blob below prefix, which must end in a
+	// slash. A prefix without blobs is not an error.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// validateKey rejects keys that could escape the store's root: empty
+// segments, "." and "..", and backslashes.
+func validateKey(key string) error {
+	if key == "" || strings.Contains(key, `\`) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
+
+// validatePrefix accepts a valid key followed by a slash, so that a prefix
+// never matches part of a segment or the whole store.
+func validatePrefix(prefix string) error {
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("invalid blob prefix %q", prefix)
+	}
+	if err := validateKey(strings.TrimSuffix(prefix, "/")); err != nil {
+		return fmt.Errorf("invalid blob prefix %q", prefix)
+	}
+	return nil
+}
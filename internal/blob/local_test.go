@@ -0,0 +1,79 @@
+package blob
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "inbound/acc-1/msg.json", []byte(`{"a":1}`)))
+
+		data, err := store.Get(ctx, "inbound/acc-1/msg.json")
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(data))
+		assert.FileExists(t, filepath.Join(dir, "inbound", "acc-1", "msg.json"))
+	})
+
+	t.Run("put overwrites", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "key", []byte("old")))
+		require.NoError(t, store.Put(ctx, "key", []byte("new")))
+
+		data, err := store.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(data))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			assert.NotContains(t, entry.Name(), ".blob-", "temporary file left behind")
+		}
+	})
+
+	t.Run("missing blobs", func(t *testing.T) {
+		_, err := store.Get(ctx, "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, store.Delete(ctx, "missing"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "gone", []byte("x")))
+		require.NoError(t, store.Delete(ctx, "gone"))
+
+		_, err := store.Get(ctx, "gone")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("delete prefix", func(t *testing.T) {
+		for _, key := range []string{"inbound/acc-1/a.json", "inbound/acc-1/day/b.json", "inbound/acc-10/c.json"} {
+			require.NoError(t, store.Put(ctx, key, []byte("x")))
+		}
+		require.NoError(t, store.DeletePrefix(ctx, "inbound/acc-1/"))
+		require.NoError(t, store.DeletePrefix(ctx, "inbound/acc-2/"))
+
+		_, err := store.Get(ctx, "inbound/acc-1/day/b.json")
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = store.Get(ctx, "inbound/acc-10/c.json")
+		assert.NoError(t, err, "a prefix only matches whole segments")
+
+		for _, prefix := range []string{"", "/", "inbound", "../", "inbound//"} {
+			assert.Error(t, store.DeletePrefix(ctx, prefix), prefix)
+		}
+	})
+
+	t.Run("rejects keys escaping the directory", func(t *testing.T) {
+		for _, key := range []string{"", "../secret", "a/../../b", "/abs", "a//b", `a\b`} {
+			assert.Error(t, store.Put(ctx, key, []byte("x")), key)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 keeps objects in memory and checks that requests are signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260102/auto/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") ||
+		r.Header.Get("X-Amz-Date") != "20260102T030405Z" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") == "2" {
+			f.list(w, r)
+			return
+		}
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list answers ListObjectsV2 one key per page, to exercise continuation.
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Path + "/" + r.URL.Query().Get("prefix")
+	var keys []string
+	for path := range f.objects {
+		if strings.HasPrefix(path, prefix) && path > r.URL.Query().Get("continuation-token") {
+			keys = append(keys, path)
+		}
+	}
+	sort.Strings(keys)
+
+	io.WriteString(w, "<ListBucketResult>")
+	if len(keys) > 0 {
+		key := strings.TrimPrefix(keys[0], r.URL.Path+"/")
+		fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+		if len(keys) > 1 {
+			fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>", keys[0])
+		}
+	}
+	io.WriteString(w, "</ListBucketResult>")
+}
+
+func newTestS3Store(t *testing.T, handler http.Handler) Store {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        server.URL,
+		Region:          "auto",
+		Bucket:          "relay-blobs",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+	store.(*s3Store).now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return store
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeS3{objects: map[string][]byte{}}
+	store := newTestS3Store(t, fake)
+
+	require.NoError(t, store.Put(ctx, "inbound/acc-1/msg.json", []byte(`{"a":1}`)))
+	assert.Contains(t, fake.objects, "/relay-blobs/inbound/acc-1/msg.json")
+
+	data, err := store.Get(ctx, "inbound/acc-1/msg.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+
+	require.NoError(t, store.Delete(ctx, "inbound/acc-1/msg.json"))
+	_, err = store.Get(ctx, "inbound/acc-1/msg.json")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, key := range []string{"inbound/acc-1/a.json", "inbound/acc-1/b.json", "inbound/acc-1/c.json", "inbound/acc-10/d.json"} {
+		require.NoError(t, store.Put(ctx, key, []byte("x")))
+	}
+	require.NoError(t, store.DeletePrefix(ctx, "inbound/acc-1/"))
+	assert.Equal(t, []string{"/relay-blobs/inbound/acc-10/d.json"}, slices.Collect(maps.Keys(fake.objects)))
+}
+
+func TestS3Store_Errors(t *testing.T) {
+	store := newTestS3Store(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "AccessDenied")
+	}))
+
+	err := store.Put(context.Background(), "key", []byte("x"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403: AccessDenied")
+
+	assert.Error(t, store.Put(context.Background(), "../key", []byte("x")))
+}
+
+func TestNewS3Store(t *testing.T) {
+	valid := S3Config{Region: "ap-northeast-2", Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s"}
+	store, err := NewS3Store(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "s3.ap-northeast-2.amazonaws.com", store.(*s3Store).endpoint.Host)
+
+	invalid := valid
+	invalid.Endpoint = "storage.googleapis.com"
+	_, err = NewS3Store(invalid)
+	assert.Error(t, err)
+
+	invalid = valid
+	invalid.SecretAccessKey = ""
+	_, err = NewS3Store(invalid)
+	assert.Error(t, err)
+}
+
+func TestURIEncode(t *testing.T) {
+	assert.Equal(t, "a-b_c.d~e", uriEncode("a-b_c.d~e"))
+	assert.Equal(t, "a%20b%3Ac%2Fd", uriEncode("a b:c/d"))
+	assert.Equal(t, "x/y%3Az", uriEncodePath("x/y:z"))
+}
@@ -2,11 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/util"
 )
 
 var knownWeakSecrets = []string{
@@ -18,6 +23,8 @@ type Config struct {
 	DatabaseURL          string `env:"DATABASE_URL,required"`
 	RedisURL             string `env:"REDIS_URL,required"`
 	KakaoSignatureSecret string `env:"KAKAO_SIGNATURE_SECRET"`
+	KakaoChannelID       string `env:"KAKAO_CHANNEL_ID"`
+	KakaoAdminKey        string `env:"KAKAO_ADMIN_KEY"`
 	AdminPasswordHash    string `env:"ADMIN_PASSWORD_HASH"`
 	AdminSessionSecret   string `env:"ADMIN_SESSION_SECRET"`
 	PortalSessionSecret  string `env:"PORTAL_SESSION_SECRET"`
@@ -26,6 +33,154 @@ type Config struct {
 	CallbackTTLSeconds   int    `env:"CALLBACK_TTL_SECONDS" envDefault:"55"`
 	LogLevel             string `env:"LOG_LEVEL" envDefault:"info"`
 	PortalBaseURL        string `env:"PORTAL_BASE_URL" envDefault:""`
+	ExperimentsFile      string `env:"EXPERIMENTS_FILE"`
+
+	// Logs at debug level with pairing codes, portal codes and tokens
+	// unmasked. Development only: rejected in production.
+	LogFullDebug bool `env:"LOG_FULL_DEBUG" envDefault:"false"`
+
+	// Port of the gRPC plugin API (0 disables it)
+	GRPCPort int `env:"GRPC_PORT" envDefault:"0"`
+	// Certificate and key files the gRPC API serves TLS with. Without them
+	// GRPC_TLS_TERMINATED_BY_PROXY must confirm that a proxy in front of the
+	// port terminates TLS, since tokens travel in the request metadata.
+	GRPCTLSCert              string `env:"GRPC_TLS_CERT"`
+	GRPCTLSKey               string `env:"GRPC_TLS_KEY"`
+	GRPCTLSTerminatedByProxy bool   `env:"GRPC_TLS_TERMINATED_BY_PROXY" envDefault:"false"`
+
+	// Language of chat replies and notifications when the user's language is
+	// unknown or unsupported (ko, en)
+	DefaultLocale string `env:"DEFAULT_LOCALE" envDefault:"ko"`
+
+	// Serve admin/portal UIs from this directory instead of the files embedded
+	// in the binary (development)
+	StaticDir string `env:"STATIC_DIR"`
+
+	// Proxies allowed to set X-Forwarded-For / X-Real-IP
+	TrustedProxies      CIDRList `env:"TRUSTED_PROXIES"`
+	TrustedProxyPresets []string `env:"TRUSTED_PROXY_PRESETS" envSeparator:","`
+
+	// Browser origins allowed to call /v1, /v2 and /openclaw ("*" allows any)
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" envSeparator:","`
+
+	// Per-IP limits for unauthenticated endpoints
+	IPRateLimitSessionCreate RateLimit `env:"IP_RATE_LIMIT_SESSION_CREATE" envDefault:"10/5m"`
+	IPRateLimitSessionStatus RateLimit `env:"IP_RATE_LIMIT_SESSION_STATUS" envDefault:"30/1m"`
+	IPRateLimitCodeLogin     RateLimit `env:"IP_RATE_LIMIT_CODE_LOGIN" envDefault:"20/1m"`
+	IPRateLimitExemptCIDRs   CIDRList  `env:"IP_RATE_LIMIT_EXEMPT_CIDRS"`
+
+	// Maximum concurrent pending plugin sessions per client IP (0 disables)
+	MaxPendingSessionsPerIP int `env:"MAX_PENDING_SESSIONS_PER_IP" envDefault:"5"`
+
+	// Concurrent requests to the webhook and plugin API routes (0 disables).
+	// Requests over the limit wait in a bounded queue, then get 503 SHED.
+	MaxInFlightRequests  int           `env:"MAX_IN_FLIGHT_REQUESTS" envDefault:"50"`
+	InFlightQueueSize    int           `env:"IN_FLIGHT_QUEUE_SIZE" envDefault:"200"`
+	InFlightQueueTimeout time.Duration `env:"IN_FLIGHT_QUEUE_TIMEOUT" envDefault:"2s"`
+
+	// Plugin version compatibility (X-OpenClaw-Plugin-Version)
+	PluginMinVersion           string `env:"PLUGIN_MIN_VERSION"`
+	PluginRecommendedVersion   string `env:"PLUGIN_RECOMMENDED_VERSION"`
+	PluginRejectMissingVersion bool   `env:"PLUGIN_REJECT_MISSING_VERSION" envDefault:"false"`
+
+	// Consistency audit at startup; auto-repair fixes only unambiguous cases
+	IntegrityCheckOnStartup bool `env:"INTEGRITY_CHECK_ON_STARTUP" envDefault:"false"`
+	IntegrityAutoRepair     bool `env:"INTEGRITY_AUTO_REPAIR" envDefault:"false"`
+
+	// Admin password rotation: older passwords must be changed before other
+	// admin actions are allowed (0 disables)
+	AdminPasswordMaxAgeDays int `env:"ADMIN_PASSWORD_MAX_AGE_DAYS" envDefault:"0"`
+
+	// Admin sessions end at the absolute max age or after the idle timeout
+	// without requests (0 disables), and can be bound to the creating client
+	AdminSessionMaxAge        time.Duration `env:"ADMIN_SESSION_MAX_AGE" envDefault:"12h"`
+	AdminSessionIdleTimeout   time.Duration `env:"ADMIN_SESSION_IDLE_TIMEOUT" envDefault:"30m"`
+	AdminSessionBindIP        bool          `env:"ADMIN_SESSION_BIND_IP" envDefault:"false"`
+	AdminSessionBindUserAgent bool          `env:"ADMIN_SESSION_BIND_USER_AGENT" envDefault:"false"`
+
+	// Error reporting (Sentry-compatible DSN; errors are only logged when unset)
+	SentryDSN         string `env:"SENTRY_DSN"`
+	SentryEnvironment string `env:"SENTRY_ENVIRONMENT" envDefault:"production"`
+
+	// Ops alerts are posted to this webhook as JSON (only logged when unset).
+	// The inbound backlog alert fires when the oldest queued message is older
+	// than the given age (0 disables).
+	OpsAlertWebhookURL     string        `env:"OPS_ALERT_WEBHOOK_URL"`
+	InboundBacklogAlertAge time.Duration `env:"INBOUND_BACKLOG_ALERT_AGE" envDefault:"15m"`
+
+	// Outgoing email (notices are only logged when SMTP_HOST is unset)
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+
+	// Size caps for Kakao webhooks: larger bodies are rejected, and stored
+	// payloads / normalized messages are truncated (0 disables a cap)
+	KakaoWebhookMaxBodyBytes int64 `env:"KAKAO_WEBHOOK_MAX_BODY_BYTES" envDefault:"262144"`
+	InboundPayloadMaxBytes   int   `env:"INBOUND_PAYLOAD_MAX_BYTES" envDefault:"32768"`
+	InboundMessageMaxBytes   int   `env:"INBOUND_MESSAGE_MAX_BYTES" envDefault:"8192"`
+
+	// Kakao payloads of at least BLOB_OFFLOAD_MIN_BYTES are kept in blob
+	// storage with only a reference in Postgres: local (BLOB_DIR), s3 or gcs
+	// (BLOB_BUCKET and HMAC keys; BLOB_ENDPOINT for other S3-compatible
+	// services). Unset keeps every payload in Postgres.
+	BlobStore           string `env:"BLOB_STORE"`
+	BlobDir             string `env:"BLOB_DIR"`
+	BlobBucket          string `env:"BLOB_BUCKET"`
+	BlobEndpoint        string `env:"BLOB_ENDPOINT"`
+	BlobRegion          string `env:"BLOB_REGION"`
+	BlobAccessKeyID     string `env:"BLOB_ACCESS_KEY_ID"`
+	BlobSecretAccessKey string `env:"BLOB_SECRET_ACCESS_KEY"`
+	BlobOffloadMinBytes int    `env:"BLOB_OFFLOAD_MIN_BYTES" envDefault:"8192"`
+
+	// Workers recording queued webhooks. Messages of paired conversations
+	// are queued in Redis and answered before they are stored; 0 handles
+	// every webhook inline.
+	KakaoWebhookWorkers int `env:"KAKAO_WEBHOOK_WORKERS" envDefault:"4"`
+
+	// How a webhook is answered when the message cannot be stored: apology
+	// (tell the user, retrying the message when the webhook queue is on) or
+	// silent (answer as if it was accepted)
+	KakaoDegradedReply string `env:"KAKAO_DEGRADED_REPLY" envDefault:"apology"`
+
+	// SSE events buffered per client, and what to do when the buffer is full:
+	// drop_oldest (send an events_dropped notice) or disconnect (close the
+	// stream with a resume cursor)
+	SSEClientBufferSize   int    `env:"SSE_CLIENT_BUFFER_SIZE" envDefault:"100"`
+	SSEBackpressurePolicy string `env:"SSE_BACKPRESSURE_POLICY" envDefault:"drop_oldest"`
+
+	// Recent events kept per account for GET /v1/events/history and SSE
+	// resume with Last-Event-ID (0 disables both)
+	EventHistorySize int           `env:"EVENT_HISTORY_SIZE" envDefault:"500"`
+	EventHistoryTTL  time.Duration `env:"EVENT_HISTORY_TTL" envDefault:"24h"`
+
+	// Ask paired Kakao users whether message content may be stored before
+	// forwarding their first message; without consent only metadata is kept
+	ContentConsentPrompt bool `env:"CONTENT_CONSENT_PROMPT" envDefault:"false"`
+
+	// How long a fetched Kakao user profile is used before fetching it again
+	KakaoProfileTTL time.Duration `env:"KAKAO_PROFILE_TTL" envDefault:"24h"`
+
+	// Hosts replies may be posted to as Kakao callbacks. ".example.com"
+	// matches subdomains, "example.com" only that host.
+	CallbackAllowedHosts []string `env:"CALLBACK_ALLOWED_HOSTS" envSeparator:"," envDefault:".kakao.com,.kakaocdn.net,.kakaoenterprise.com"`
+
+	// Refuse a second reply to an inbound message that already has a pending
+	// or sent one, so agent retries are not posted to Kakao twice
+	ReplyOncePerMessage bool `env:"REPLY_ONCE_PER_MESSAGE" envDefault:"true"`
+
+	// How often a message is posted to an account's webhook before delivery
+	// gives up on it; it then stays queued for the event stream
+	WebhookMaxAttempts int `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+
+	// Sign v2 SSE event envelopes (detached JWS, EdDSA). The key is derived
+	// from SSE_SIGNING_KEY, or generated and stored on first start.
+	SSESigningEnabled bool   `env:"SSE_SIGNING_ENABLED" envDefault:"false"`
+	SSESigningKey     string `env:"SSE_SIGNING_KEY"`
+
+	// Planned removal date of the v1 API, advertised in the Sunset header (RFC 3339)
+	APIV1Sunset time.Time `env:"API_V1_SUNSET"`
 }
 
 func (c *Config) QueueTTL() time.Duration {
@@ -36,11 +191,136 @@ func (c *Config) CallbackTTL() time.Duration {
 	return time.Duration(c.CallbackTTLSeconds) * time.Second
 }
 
+func (c *Config) AdminPasswordMaxAge() time.Duration {
+	return time.Duration(c.AdminPasswordMaxAgeDays) * 24 * time.Hour
+}
+
+// Locale returns the deployment's default locale. Validate rejects
+// unsupported values.
+func (c *Config) Locale() i18n.Locale {
+	if locale, ok := i18n.ParseLocale(c.DefaultLocale); ok {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// validateGRPC checks the gRPC port and that the API is not served in
+// plaintext unless a proxy terminates TLS.
+func (c *Config) validateGRPC() error {
+	if c.GRPCPort < 0 || c.GRPCPort > 65535 || (c.GRPCPort != 0 && c.GRPCPort == c.Port) {
+		return fmt.Errorf("GRPC_PORT must be 0 or a port other than PORT")
+	}
+	if (c.GRPCTLSCert == "") != (c.GRPCTLSKey == "") {
+		return fmt.Errorf("GRPC_TLS_CERT and GRPC_TLS_KEY must be set together")
+	}
+	if c.GRPCPort != 0 && c.GRPCTLSCert == "" && !c.GRPCTLSTerminatedByProxy {
+		return fmt.Errorf("GRPC_PORT requires GRPC_TLS_CERT and GRPC_TLS_KEY, or GRPC_TLS_TERMINATED_BY_PROXY=true behind a TLS-terminating proxy")
+	}
+	return nil
+}
+
+// validateBlobStore checks that the selected blob store has its settings.
+func (c *Config) validateBlobStore() error {
+	switch c.BlobStore {
+	case "":
+		return nil
+	case "local":
+		if c.BlobDir == "" {
+			return fmt.Errorf("BLOB_DIR is required when BLOB_STORE is local")
+		}
+	case "s3", "gcs":
+		if c.BlobBucket == "" || c.BlobAccessKeyID == "" || c.BlobSecretAccessKey == "" {
+			return fmt.Errorf("BLOB_BUCKET, BLOB_ACCESS_KEY_ID and BLOB_SECRET_ACCESS_KEY are required when BLOB_STORE is %s", c.BlobStore)
+		}
+		if c.BlobStore == "s3" && c.BlobRegion == "" {
+			return fmt.Errorf("BLOB_REGION is required when BLOB_STORE is s3")
+		}
+	default:
+		return fmt.Errorf("BLOB_STORE must be one of: local, s3, gcs")
+	}
+	if c.BlobOffloadMinBytes <= 0 {
+		return fmt.Errorf("BLOB_OFFLOAD_MIN_BYTES must be positive")
+	}
+	return nil
+}
+
 func (c *Config) Addr() string {
 	return fmt.Sprintf(":%d", c.Port)
 }
 
+// GRPCAddr is the gRPC listen address, empty when gRPC is disabled.
+func (c *Config) GRPCAddr() string {
+	if c.GRPCPort == 0 {
+		return ""
+	}
+	return fmt.Sprintf(":%d", c.GRPCPort)
+}
+
 func (c *Config) Validate(isProduction bool) error {
+	if _, err := c.TrustedProxyCIDRs(); err != nil {
+		return err
+	}
+	if _, err := c.PluginCompatibility(); err != nil {
+		return err
+	}
+	if c.KakaoChannelID != "" && !util.IsValidKakaoChannelID(c.KakaoChannelID) {
+		return fmt.Errorf("KAKAO_CHANNEL_ID must be a channel public ID such as _xkAbC")
+	}
+	if err := c.validateGRPC(); err != nil {
+		return err
+	}
+	if _, ok := i18n.ParseLocale(c.DefaultLocale); c.DefaultLocale != "" && !ok {
+		return fmt.Errorf("DEFAULT_LOCALE must be one of: ko, en")
+	}
+	if err := validateOrigins(c.CORSAllowedOrigins); err != nil {
+		return err
+	}
+	if c.AdminSessionMaxAge <= 0 {
+		return fmt.Errorf("ADMIN_SESSION_MAX_AGE must be positive")
+	}
+	if c.AdminSessionIdleTimeout < 0 {
+		return fmt.Errorf("ADMIN_SESSION_IDLE_TIMEOUT must not be negative")
+	}
+	if c.KakaoWebhookMaxBodyBytes < 0 || c.InboundPayloadMaxBytes < 0 || c.InboundMessageMaxBytes < 0 {
+		return fmt.Errorf("KAKAO_WEBHOOK_MAX_BODY_BYTES, INBOUND_PAYLOAD_MAX_BYTES and INBOUND_MESSAGE_MAX_BYTES must not be negative")
+	}
+	if err := c.validateBlobStore(); err != nil {
+		return err
+	}
+	if c.KakaoWebhookWorkers < 0 {
+		return fmt.Errorf("KAKAO_WEBHOOK_WORKERS must not be negative")
+	}
+	if c.KakaoDegradedReply != "apology" && c.KakaoDegradedReply != "silent" {
+		return fmt.Errorf("KAKAO_DEGRADED_REPLY must be one of: apology, silent")
+	}
+	if c.MaxInFlightRequests < 0 || c.InFlightQueueSize < 0 {
+		return fmt.Errorf("MAX_IN_FLIGHT_REQUESTS and IN_FLIGHT_QUEUE_SIZE must not be negative")
+	}
+	if c.MaxInFlightRequests > 0 && c.InFlightQueueSize > 0 && c.InFlightQueueTimeout <= 0 {
+		return fmt.Errorf("IN_FLIGHT_QUEUE_TIMEOUT must be positive")
+	}
+	if c.SSEClientBufferSize <= 0 {
+		return fmt.Errorf("SSE_CLIENT_BUFFER_SIZE must be positive")
+	}
+	if c.SSEBackpressurePolicy != "drop_oldest" && c.SSEBackpressurePolicy != "disconnect" {
+		return fmt.Errorf("SSE_BACKPRESSURE_POLICY must be one of: drop_oldest, disconnect")
+	}
+	if c.EventHistorySize < 0 {
+		return fmt.Errorf("EVENT_HISTORY_SIZE must not be negative")
+	}
+	if c.EventHistorySize > 0 && c.EventHistoryTTL <= 0 {
+		return fmt.Errorf("EVENT_HISTORY_TTL must be positive")
+	}
+	if c.KakaoProfileTTL <= 0 {
+		return fmt.Errorf("KAKAO_PROFILE_TTL must be positive")
+	}
+	if err := validateCallbackHosts(c.CallbackAllowedHosts); err != nil {
+		return err
+	}
+	if c.SMTPHost != "" && c.SMTPFrom == "" {
+		return fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+	}
+
 	if c.AdminPasswordHash != "" {
 		if !strings.HasPrefix(c.AdminPasswordHash, "$2a$") &&
 			!strings.HasPrefix(c.AdminPasswordHash, "$2b$") &&
@@ -50,11 +330,26 @@ func (c *Config) Validate(isProduction bool) error {
 	}
 
 	if isProduction {
-		if err := validateSecret("ADMIN_SESSION_SECRET", c.AdminSessionSecret); err != nil {
-			return err
+		if c.LogFullDebug {
+			return fmt.Errorf("LOG_FULL_DEBUG must not be enabled in production")
+		}
+
+		// Unset session secrets are generated and stored in the database on
+		// first start; explicitly set ones must be strong.
+		if c.AdminSessionSecret != "" {
+			if err := validateSecret("ADMIN_SESSION_SECRET", c.AdminSessionSecret); err != nil {
+				return err
+			}
+		}
+		if c.PortalSessionSecret != "" {
+			if err := validateSecret("PORTAL_SESSION_SECRET", c.PortalSessionSecret); err != nil {
+				return err
+			}
 		}
-		if err := validateSecret("PORTAL_SESSION_SECRET", c.PortalSessionSecret); err != nil {
-			return err
+		if c.SSESigningKey != "" {
+			if err := validateSecret("SSE_SIGNING_KEY", c.SSESigningKey); err != nil {
+				return err
+			}
 		}
 
 		if c.KakaoSignatureSecret == "" {
@@ -71,6 +366,30 @@ func (c *Config) Validate(isProduction bool) error {
 	return nil
 }
 
+func validateCallbackHosts(hosts []string) error {
+	for _, host := range hosts {
+		name := strings.TrimPrefix(host, ".")
+		if name == "" || strings.ContainsAny(name, "/:@ ") || net.ParseIP(name) != nil {
+			return fmt.Errorf("CALLBACK_ALLOWED_HOSTS entry %q must be a host name such as .kakao.com", host)
+		}
+	}
+	return nil
+}
+
+func validateOrigins(origins []string) error {
+	for _, origin := range origins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			strings.TrimSuffix(u.Path, "/") != "" || u.RawQuery != "" {
+			return fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q must be an origin such as https://dash.example.com", origin)
+		}
+	}
+	return nil
+}
+
 func validateSecret(name, value string) error {
 	if len(value) < 32 {
 		return fmt.Errorf("%s must be at least 32 characters in production (generate with: openssl rand -base64 32)", name)
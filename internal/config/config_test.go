@@ -1,6 +1,7 @@
 package config
 
 import (
+	"net/netip"
 	"os"
 	"testing"
 	"time"
@@ -98,3 +99,168 @@ func TestLoad(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestLoadRateLimits(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+
+	t.Run("uses defaults", func(t *testing.T) {
+		cfg, err := Load()
+		require.NoError(t, err)
+
+		assert.Equal(t, RateLimit{Limit: 10, Window: 5 * time.Minute}, cfg.IPRateLimitSessionCreate)
+		assert.Empty(t, cfg.IPRateLimitExemptCIDRs)
+	})
+
+	t.Run("parses custom limits and CIDRs", func(t *testing.T) {
+		t.Setenv("IP_RATE_LIMIT_CODE_LOGIN", "0/1m")
+		t.Setenv("IP_RATE_LIMIT_EXEMPT_CIDRS", "10.0.0.0/8, 192.168.1.5")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+
+		assert.False(t, cfg.IPRateLimitCodeLogin.Enabled())
+		require.Len(t, cfg.IPRateLimitExemptCIDRs, 2)
+		assert.True(t, cfg.IPRateLimitExemptCIDRs.Contains(netip.MustParseAddr("10.20.30.40")))
+		assert.True(t, cfg.IPRateLimitExemptCIDRs.Contains(netip.MustParseAddr("192.168.1.5")))
+		assert.False(t, cfg.IPRateLimitExemptCIDRs.Contains(netip.MustParseAddr("192.168.1.6")))
+	})
+
+	t.Run("rejects malformed limit", func(t *testing.T) {
+		t.Setenv("IP_RATE_LIMIT_SESSION_CREATE", "ten per minute")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects malformed CIDR", func(t *testing.T) {
+		t.Setenv("IP_RATE_LIMIT_EXEMPT_CIDRS", "10.0.0.0/99")
+
+		_, err := Load()
+		assert.Error(t, err)
+	})
+}
+
+func TestTrustedProxyCIDRs(t *testing.T) {
+	t.Run("combines explicit CIDRs and presets", func(t *testing.T) {
+		cfg := &Config{
+			TrustedProxies:      CIDRList{netip.MustParsePrefix("192.0.2.0/24")},
+			TrustedProxyPresets: []string{"Cloudflare"},
+		}
+
+		cidrs, err := cfg.TrustedProxyCIDRs()
+		require.NoError(t, err)
+		assert.True(t, cidrs.Contains(netip.MustParseAddr("192.0.2.10")))
+		assert.True(t, cidrs.Contains(netip.MustParseAddr("104.16.1.1")))
+		assert.False(t, cidrs.Contains(netip.MustParseAddr("8.8.8.8")))
+	})
+
+	t.Run("gcp and fly presets", func(t *testing.T) {
+		cfg := &Config{TrustedProxyPresets: []string{"gcp", "fly"}}
+
+		cidrs, err := cfg.TrustedProxyCIDRs()
+		require.NoError(t, err)
+		assert.True(t, cidrs.Contains(netip.MustParseAddr("169.254.1.1")))
+		assert.True(t, cidrs.Contains(netip.MustParseAddr("35.191.10.20")))
+		assert.True(t, cidrs.Contains(netip.MustParseAddr("172.16.5.58")))
+		assert.False(t, cidrs.Contains(netip.MustParseAddr("172.20.0.1")), "fly does not trust all of 172.16.0.0/12")
+	})
+
+	t.Run("rejects unknown preset", func(t *testing.T) {
+		cfg := &Config{TrustedProxyPresets: []string{"akamai"}}
+
+		_, err := cfg.TrustedProxyCIDRs()
+		assert.Error(t, err)
+		assert.Error(t, cfg.Validate(false))
+	})
+}
+
+func TestPluginCompatibility(t *testing.T) {
+	cfg := &Config{PluginMinVersion: "1.2.0", PluginRecommendedVersion: "v1.4"}
+	compat, err := cfg.PluginCompatibility()
+	require.NoError(t, err)
+
+	tests := []struct {
+		version string
+		want    PluginCompatStatus
+	}{
+		{"1.1.9", PluginCompatUnsupported},
+		{"1.2.0", PluginCompatOutdated},
+		{"1.3.5-beta.1", PluginCompatOutdated},
+		{"1.4.0", PluginCompatOK},
+		{"2.0.0", PluginCompatOK},
+		{"", PluginCompatUnknown},
+		{"garbage", PluginCompatUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			result := compat.Check(tt.version)
+			assert.Equal(t, tt.want, result.Status)
+			assert.Equal(t, "1.2.0", result.MinimumVersion)
+			assert.Equal(t, "1.4.0", result.RecommendedVersion)
+		})
+	}
+
+	t.Run("rejects missing version when configured", func(t *testing.T) {
+		cfg := &Config{PluginMinVersion: "1.2.0", PluginRejectMissingVersion: true}
+		compat, err := cfg.PluginCompatibility()
+		require.NoError(t, err)
+		assert.Equal(t, PluginCompatUnsupported, compat.Check("").Status)
+	})
+
+	t.Run("everything is ok without a matrix", func(t *testing.T) {
+		compat, err := (&Config{}).PluginCompatibility()
+		require.NoError(t, err)
+		assert.Equal(t, PluginCompatOK, compat.Check("0.0.1").Status)
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		assert.Error(t, (&Config{PluginMinVersion: "latest"}).Validate(false))
+		assert.Error(t, (&Config{PluginMinVersion: "2.0", PluginRecommendedVersion: "1.0"}).Validate(false))
+	})
+}
+
+func TestValidateLogFullDebug(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+	t.Setenv("LOG_FULL_DEBUG", "true")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.LogFullDebug)
+	assert.NoError(t, cfg.Validate(false))
+	assert.ErrorContains(t, cfg.Validate(true), "LOG_FULL_DEBUG")
+}
+
+func TestValidateOrigins(t *testing.T) {
+	assert.NoError(t, validateOrigins([]string{"*", "https://dash.example.com", "http://localhost:3000"}))
+	assert.Error(t, validateOrigins([]string{"dash.example.com"}))
+	assert.Error(t, validateOrigins([]string{"https://dash.example.com/app"}))
+	assert.Error(t, validateOrigins([]string{"ftp://dash.example.com"}))
+}
+
+func TestValidateCallbackHosts(t *testing.T) {
+	assert.NoError(t, validateCallbackHosts([]string{".kakao.com", "callback.example.com"}))
+	assert.Error(t, validateCallbackHosts([]string{"."}))
+	assert.Error(t, validateCallbackHosts([]string{"https://kakao.com"}))
+	assert.Error(t, validateCallbackHosts([]string{"10.0.0.1"}))
+}
+
+func TestValidateGRPC(t *testing.T) {
+	assert.NoError(t, (&Config{Port: 8080}).validateGRPC())
+	assert.NoError(t, (&Config{Port: 8080, GRPCPort: 9090, GRPCTLSCert: "cert.pem", GRPCTLSKey: "key.pem"}).validateGRPC())
+	assert.NoError(t, (&Config{Port: 8080, GRPCPort: 9090, GRPCTLSTerminatedByProxy: true}).validateGRPC())
+	assert.ErrorContains(t, (&Config{Port: 8080, GRPCPort: 9090}).validateGRPC(), "GRPC_TLS_CERT")
+	assert.Error(t, (&Config{Port: 8080, GRPCPort: 9090, GRPCTLSCert: "cert.pem"}).validateGRPC())
+	assert.Error(t, (&Config{Port: 8080, GRPCPort: 8080, GRPCTLSTerminatedByProxy: true}).validateGRPC())
+}
+
+func TestValidateBlobStore(t *testing.T) {
+	assert.NoError(t, (&Config{}).validateBlobStore())
+	assert.NoError(t, (&Config{BlobStore: "local", BlobDir: "/data/blobs", BlobOffloadMinBytes: 8192}).validateBlobStore())
+	assert.Error(t, (&Config{BlobStore: "local", BlobOffloadMinBytes: 8192}).validateBlobStore())
+	assert.NoError(t, (&Config{BlobStore: "gcs", BlobBucket: "b", BlobAccessKeyID: "k", BlobSecretAccessKey: "s", BlobOffloadMinBytes: 8192}).validateBlobStore())
+	assert.Error(t, (&Config{BlobStore: "s3", BlobBucket: "b", BlobAccessKeyID: "k", BlobSecretAccessKey: "s", BlobOffloadMinBytes: 8192}).validateBlobStore())
+	assert.Error(t, (&Config{BlobStore: "azure"}).validateBlobStore())
+	assert.Error(t, (&Config{BlobStore: "local", BlobDir: "/data/blobs"}).validateBlobStore())
+}
@@ -17,11 +17,32 @@ const (
 	ServerShutdownTimeout = 30 * time.Second
 )
 
+// Session wait (long-poll) timeouts. The maximum stays below
+// ServerRequestTimeout so the response is written before the request times out.
+const (
+	SessionWaitDefaultTimeout = 30 * time.Second
+	SessionWaitMaxTimeout     = 55 * time.Second
+)
+
 // Database ping timeout for health checks
 const DBPingTimeout = 5 * time.Second
 
+// Timeout for loading deployment settings at startup
+const DeploymentSettingsTimeout = 10 * time.Second
+
+// Timeout for the startup data integrity check
+const IntegrityCheckTimeout = 30 * time.Second
+
+// Timeout for the startup check for pending database migrations
+const MigrationCheckTimeout = 10 * time.Second
+
 // Background job intervals
 const CleanupJobInterval = 5 * time.Minute
+const ReconcileJobInterval = 10 * time.Minute
+const SessionExpiryJobInterval = 5 * time.Second
+const AccountPurgeJobInterval = time.Hour
+const ScheduledSendJobInterval = time.Second
+const WebhookDeliveryJobInterval = time.Second
 
 // Default rate limiting
 const DefaultRateLimitPerMin = 60
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+type PluginCompatStatus string
+
+const (
+	// PluginCompatOK means the plugin meets the recommended version.
+	PluginCompatOK PluginCompatStatus = "ok"
+	// PluginCompatOutdated means the plugin still works but should upgrade.
+	PluginCompatOutdated PluginCompatStatus = "outdated"
+	// PluginCompatUnsupported means the plugin is below the minimum version.
+	PluginCompatUnsupported PluginCompatStatus = "unsupported"
+	// PluginCompatUnknown means the plugin sent no (or an unparsable) version.
+	PluginCompatUnknown PluginCompatStatus = "unknown"
+)
+
+type PluginCompatResult struct {
+	Status             PluginCompatStatus `json:"status"`
+	CurrentVersion     string             `json:"currentVersion,omitempty"`
+	MinimumVersion     string             `json:"minimumVersion,omitempty"`
+	RecommendedVersion string             `json:"recommendedVersion,omitempty"`
+}
+
+// PluginCompatibility is the plugin version compatibility matrix: versions
+// below the minimum are rejected, versions below the recommended one are warned.
+type PluginCompatibility struct {
+	minimum       *util.Version
+	recommended   *util.Version
+	rejectMissing bool
+}
+
+// PluginCompatibility builds the matrix from PLUGIN_MIN_VERSION,
+// PLUGIN_RECOMMENDED_VERSION and PLUGIN_REJECT_MISSING_VERSION. Unset versions
+// disable the corresponding check.
+func (c *Config) PluginCompatibility() (*PluginCompatibility, error) {
+	compat := &PluginCompatibility{rejectMissing: c.PluginRejectMissingVersion}
+
+	if c.PluginMinVersion != "" {
+		v, err := util.ParseVersion(c.PluginMinVersion)
+		if err != nil {
+			return nil, fmt.Errorf("PLUGIN_MIN_VERSION: %w", err)
+		}
+		compat.minimum = &v
+	}
+
+	if c.PluginRecommendedVersion != "" {
+		v, err := util.ParseVersion(c.PluginRecommendedVersion)
+		if err != nil {
+			return nil, fmt.Errorf("PLUGIN_RECOMMENDED_VERSION: %w", err)
+		}
+		compat.recommended = &v
+	}
+
+	if compat.minimum != nil && compat.recommended != nil && compat.recommended.Less(*compat.minimum) {
+		return nil, fmt.Errorf("PLUGIN_RECOMMENDED_VERSION must not be lower than PLUGIN_MIN_VERSION")
+	}
+
+	return compat, nil
+}
+
+// Check classifies a plugin version against the matrix. Plugins that send no
+// version are unknown, or unsupported when a minimum is set and missing
+// versions are rejected.
+func (p *PluginCompatibility) Check(version string) PluginCompatResult {
+	result := PluginCompatResult{
+		Status:         PluginCompatOK,
+		CurrentVersion: version,
+	}
+	if p.minimum != nil {
+		result.MinimumVersion = p.minimum.String()
+	}
+	if p.recommended != nil {
+		result.RecommendedVersion = p.recommended.String()
+	}
+
+	current, err := util.ParseVersion(version)
+	if err != nil {
+		result.Status = PluginCompatUnknown
+		if p.rejectMissing && p.minimum != nil {
+			result.Status = PluginCompatUnsupported
+		}
+		return result
+	}
+
+	switch {
+	case p.minimum != nil && current.Less(*p.minimum):
+		result.Status = PluginCompatUnsupported
+	case p.recommended != nil && current.Less(*p.recommended):
+		result.Status = PluginCompatOutdated
+	}
+	return result
+}
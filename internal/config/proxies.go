@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// trustedProxyPresets are well-known proxy ranges that can be trusted by name
+// through TRUSTED_PROXY_PRESETS instead of listing CIDRs by hand.
+var trustedProxyPresets = map[string][]string{
+	// Fly.io's proxy reaches the app from 172.16.0.0/16 or over the private
+	// IPv6 network, not from the rest of 172.16.0.0/12.
+	"fly": {
+		"172.16.0.0/16",
+		"fdaa::/16",
+	},
+	// Cloud Run hands requests to the container from a link-local address;
+	// Google Front End and load balancer proxies connect from 35.191.0.0/16
+	// and 130.211.0.0/22.
+	"gcp": {
+		"169.254.0.0/16",
+		"35.191.0.0/16",
+		"130.211.0.0/22",
+	},
+	// https://www.cloudflare.com/ips/
+	"cloudflare": {
+		"173.245.48.0/20",
+		"103.21.244.0/22",
+		"103.22.200.0/22",
+		"103.31.4.0/22",
+		"141.101.64.0/18",
+		"108.162.192.0/18",
+		"190.93.240.0/20",
+		"188.114.96.0/20",
+		"197.234.240.0/22",
+		"198.41.128.0/17",
+		"162.158.0.0/15",
+		"104.16.0.0/13",
+		"104.24.0.0/14",
+		"172.64.0.0/13",
+		"131.0.72.0/22",
+		"2400:cb00::/32",
+		"2606:4700::/32",
+		"2803:f800::/32",
+		"2405:b500::/32",
+		"2405:8100::/32",
+		"2a06:98c0::/29",
+		"2c0f:f248::/32",
+	},
+	// Loopback, for a reverse proxy on the same host.
+	"local": {
+		"127.0.0.0/8",
+		"::1/128",
+	},
+}
+
+// TrustedProxyCIDRs returns the CIDRs from TRUSTED_PROXIES together with the
+// ranges of every preset named in TRUSTED_PROXY_PRESETS.
+func (c *Config) TrustedProxyCIDRs() (CIDRList, error) {
+	cidrs := append(CIDRList{}, c.TrustedProxies...)
+	for _, name := range c.TrustedProxyPresets {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		ranges, ok := trustedProxyPresets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown TRUSTED_PROXY_PRESETS entry %q", name)
+		}
+		for _, r := range ranges {
+			cidrs = append(cidrs, netip.MustParsePrefix(r))
+		}
+	}
+	return cidrs, nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is a limit of requests per window, written as "<limit>/<window>"
+// (e.g. "10/5m"). A limit of 0 disables the limit.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (r *RateLimit) UnmarshalText(text []byte) error {
+	limitStr, windowStr, ok := strings.Cut(strings.TrimSpace(string(text)), "/")
+	if !ok {
+		return fmt.Errorf("rate limit %q must be <limit>/<window>, e.g. 10/5m", text)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+	if err != nil || limit < 0 {
+		return fmt.Errorf("rate limit %q has an invalid limit", text)
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+	if err != nil || window <= 0 {
+		return fmt.Errorf("rate limit %q has an invalid window", text)
+	}
+
+	r.Limit = limit
+	r.Window = window
+	return nil
+}
+
+func (r RateLimit) Enabled() bool {
+	return r.Limit > 0
+}
+
+// CIDRList is a comma-separated list of CIDR prefixes. Bare IP addresses are
+// accepted as single-host prefixes.
+type CIDRList []netip.Prefix
+
+func (l *CIDRList) UnmarshalText(text []byte) error {
+	var prefixes CIDRList
+	for _, part := range strings.Split(string(text), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		prefix, err := parsePrefix(part)
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	*l = prefixes
+	return nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: %w", s, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP %q: %w", s, err)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Contains reports whether ip falls in any prefix of the list.
+func (l CIDRList) Contains(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range l {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
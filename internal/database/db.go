@@ -5,8 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
 
 	"github.com/openclaw/relay-server-go/internal/config"
 )
@@ -28,9 +29,18 @@ type DB struct {
 	*sqlx.DB
 }
 
+// Connect opens a pool over the pgx driver. Statements are prepared once per
+// connection and cached (pgx's default query mode); behind a transaction
+// pooler such as PgBouncer, add default_query_exec_mode=exec to the URL.
 func Connect(databaseURL string) (*DB, error) {
-	db, err := sqlx.Connect("postgres", databaseURL)
+	connConfig, err := pgx.ParseConfig(databaseURL)
 	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+
+	db := sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx")
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
 		return nil, err
 	}
 
@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+)
+
+// Querier is the subset of DBTX used by repositories.
+type Querier interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// RetryPolicy bounds retries of transient errors. Delays grow exponentially
+// from BaseDelay up to MaxDelay, with full jitter.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   50 * time.Millisecond,
+	MaxDelay:    time.Second,
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay) + 1
+}
+
+// Retry runs fn until it succeeds, returns a non-retryable error, the policy
+// runs out of attempts or ctx is done.
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryable(err) || attempt+1 >= policy.MaxAttempts {
+			return err
+		}
+
+		delay := policy.backoff(attempt)
+		log.Warn().Err(err).Int("attempt", attempt+1).Dur("delay", delay).Msg("retrying transient database error")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Postgres error codes after which the statement is known to have been
+// rolled back, so running it again cannot apply a write twice.
+var retryablePGCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// Postgres error codes of a server that is shutting down, starting up or out
+// of connections.
+var unavailablePGCodes = map[string]bool{
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+	"53300": true, // too_many_connections
+}
+
+// IsRetryable reports whether err is a transient database error after which
+// the statement certainly did not take effect: a serialization failure or
+// deadlock (the statement was rolled back), a failure to connect, or an error
+// pgx reports as raised before the statement was sent. A connection lost
+// mid-statement is not retryable: a write may have been applied even though
+// its result never arrived.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && retryablePGCodes[pgErr.Code] {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	// database/sql only sees ErrBadConn from a connection that was unusable
+	// before the statement was sent
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsTransient reports whether err is a transient database error: a
+// retryable one, or a lost connection, timeout or server restart that may
+// have interrupted the statement. Callers may retry these only when the
+// statement is idempotent.
+func IsTransient(err error) bool {
+	if IsRetryable(err) {
+		return true
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return unavailablePGCodes[pgErr.Code] || strings.HasPrefix(pgErr.Code, "08") // connection_exception
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// ClassifyError maps Postgres errors to AppError codes so handlers can
+// answer with a meaningful status instead of a generic 500. The original
+// error stays reachable through errors.Is/As. Other errors are returned as is.
+func ClassifyError(err error) error {
+	if err == nil || errors.Is(err, sql.ErrNoRows) || apperrors.IsAppError(err) {
+		return err
+	}
+
+	if IsTransient(err) {
+		return apperrors.Wrap(apperrors.ErrCodeUnavailable, "Database temporarily unavailable, please retry", err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case "23505": // unique_violation
+		return apperrors.Wrap(apperrors.ErrCodeAlreadyExists, "Resource already exists", err)
+	case "23503": // foreign_key_violation
+		return apperrors.Wrap(apperrors.ErrCodeConflict, "Referenced resource does not exist or is still in use", err)
+	case "23502", "23514", "22001", "22003", "22P02": // not_null, check, too long, out of range, invalid text
+		return apperrors.Wrap(apperrors.ErrCodeInvalidInput, "Invalid input", err)
+	case "57014": // query_canceled (statement timeout)
+		return apperrors.Wrap(apperrors.ErrCodeUnavailable, "Database query timed out, please retry", err)
+	}
+	return err
+}
+
+type retryQuerier struct {
+	db     Querier
+	policy RetryPolicy
+}
+
+// WithRetry wraps db so that every query is retried on retryable errors and
+// failures are classified with ClassifyError. Only errors after which the
+// statement certainly did not run are retried, so writes are safe to wrap. Do not wrap a transaction: a
+// failed statement aborts it, so the whole transaction has to be retried.
+func WithRetry(db Querier, policy RetryPolicy) Querier {
+	return &retryQuerier{db: db, policy: policy}
+}
+
+func (q *retryQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return ClassifyError(Retry(ctx, q.policy, func() error {
+		return q.db.GetContext(ctx, dest, query, args...)
+	}))
+}
+
+func (q *retryQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return ClassifyError(Retry(ctx, q.policy, func() error {
+		return q.db.SelectContext(ctx, dest, query, args...)
+	}))
+}
+
+func (q *retryQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := Retry(ctx, q.policy, func() error {
+		var err error
+		result, err = q.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, ClassifyError(err)
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+)
+
+var testPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"failure to connect", &pgconn.ConnectError{Config: &pgconn.Config{}}, true},
+		{"bad conn", driver.ErrBadConn, true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"connection reset mid-statement", fmt.Errorf("read: %w", syscall.ECONNRESET), false},
+		{"unexpected EOF", io.ErrUnexpectedEOF, false},
+		{"timeout", &net.OpError{Op: "read", Err: timeoutError{}}, false},
+		{"admin shutdown", &pgconn.PgError{Code: "57P01"}, false},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"no rows", sql.ErrNoRows, false},
+		{"context canceled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransient(t *testing.T) {
+	for _, err := range []error{
+		&pgconn.PgError{Code: "40001"},
+		&pgconn.PgError{Code: "08006"},
+		&pgconn.PgError{Code: "57P01"},
+		fmt.Errorf("read: %w", syscall.ECONNRESET),
+		io.ErrUnexpectedEOF,
+		&net.OpError{Op: "read", Err: timeoutError{}},
+	} {
+		assert.True(t, IsTransient(err), err.Error())
+	}
+	for _, err := range []error{&pgconn.PgError{Code: "23505"}, sql.ErrNoRows, context.DeadlineExceeded} {
+		assert.False(t, IsTransient(err), err.Error())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, testPolicy, func() error {
+			calls++
+			if calls < 3 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, testPolicy, func() error {
+			calls++
+			return &pgconn.PgError{Code: "40P01"}
+		})
+		assert.Error(t, err)
+		assert.Equal(t, testPolicy.MaxAttempts, calls)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, testPolicy, func() error {
+			calls++
+			return &pgconn.PgError{Code: "23505"}
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("does not retry a connection lost mid-statement", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, testPolicy, func() error {
+			calls++
+			return fmt.Errorf("read: %w", syscall.ECONNRESET)
+		})
+		assert.ErrorIs(t, err, syscall.ECONNRESET)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		calls := 0
+		err := Retry(ctx, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second}, func() error {
+			calls++
+			return driver.ErrBadConn
+		})
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code apperrors.ErrorCode
+	}{
+		{"unique violation", &pgconn.PgError{Code: "23505"}, apperrors.ErrCodeAlreadyExists},
+		{"foreign key violation", &pgconn.PgError{Code: "23503"}, apperrors.ErrCodeConflict},
+		{"invalid uuid", &pgconn.PgError{Code: "22P02"}, apperrors.ErrCodeInvalidInput},
+		{"exhausted retries", &pgconn.PgError{Code: "40001"}, apperrors.ErrCodeUnavailable},
+		{"statement timeout", &pgconn.PgError{Code: "57014"}, apperrors.ErrCodeUnavailable},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, apperrors.ErrCodeUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ClassifyError(tt.err)
+			assert.Equal(t, tt.code, apperrors.GetCode(err))
+
+			var pgErr *pgconn.PgError
+			assert.True(t, errors.As(err, &pgErr), "original error must stay reachable")
+		})
+	}
+
+	t.Run("passes through other errors", func(t *testing.T) {
+		assert.Equal(t, sql.ErrNoRows, ClassifyError(sql.ErrNoRows))
+		plain := errors.New("boom")
+		assert.Equal(t, plain, ClassifyError(plain))
+		assert.NoError(t, ClassifyError(nil))
+	})
+}
+
+type flakyQuerier struct {
+	failures int
+	calls    int
+}
+
+func (q *flakyQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	q.calls++
+	if q.calls <= q.failures {
+		return &pgconn.PgError{Code: "40001"}
+	}
+	return nil
+}
+
+func (q *flakyQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.GetContext(ctx, dest, query, args...)
+}
+
+func (q *flakyQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, q.GetContext(ctx, nil, query, args...)
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		inner := &flakyQuerier{failures: 2}
+		_, err := WithRetry(inner, testPolicy).ExecContext(ctx, "UPDATE x")
+		require.NoError(t, err)
+		assert.Equal(t, 3, inner.calls)
+	})
+
+	t.Run("classifies exhausted failures", func(t *testing.T) {
+		inner := &flakyQuerier{failures: 10}
+		err := WithRetry(inner, testPolicy).GetContext(ctx, nil, "SELECT 1")
+		assert.Equal(t, apperrors.ErrCodeUnavailable, apperrors.GetCode(err))
+		assert.Equal(t, testPolicy.MaxAttempts, inner.calls)
+	})
+}
@@ -15,6 +15,10 @@ const (
 	ErrCodeInvalidToken     ErrorCode = "INVALID_TOKEN"
 	ErrCodeTokenExpired     ErrorCode = "TOKEN_EXPIRED"
 	ErrCodeSessionNotPaired ErrorCode = "SESSION_NOT_PAIRED"
+	// The admin password expired and must be changed before anything else
+	ErrCodePasswordChangeRequired ErrorCode = "PASSWORD_CHANGE_REQUIRED"
+	// The action needs a password entered recently in this session
+	ErrCodeReauthRequired ErrorCode = "REAUTH_REQUIRED"
 
 	// Validation
 	ErrCodeValidation      ErrorCode = "VALIDATION_ERROR"
@@ -25,23 +29,39 @@ const (
 	ErrCodeNotFound      ErrorCode = "NOT_FOUND"
 	ErrCodeAlreadyExists ErrorCode = "ALREADY_EXISTS"
 	ErrCodeConflict      ErrorCode = "CONFLICT"
+	ErrCodeLegalHold     ErrorCode = "LEGAL_HOLD"
 
 	// Pairing
 	ErrCodeInvalidPairingCode ErrorCode = "INVALID_PAIRING_CODE"
 	ErrCodePairingExpired     ErrorCode = "PAIRING_EXPIRED"
 	ErrCodeAlreadyPaired      ErrorCode = "ALREADY_PAIRED"
 
+	// Client compatibility
+	ErrCodeUpgradeRequired ErrorCode = "UPGRADE_REQUIRED"
+
 	// Rate Limiting
-	ErrCodeRateLimitExceeded ErrorCode = "RATE_LIMIT_EXCEEDED"
+	ErrCodeRateLimitExceeded      ErrorCode = "RATE_LIMIT_EXCEEDED"
+	ErrCodeTooManyPendingSessions ErrorCode = "TOO_MANY_PENDING_SESSIONS"
+
+	// Event history
+	ErrCodeHistoryExpired ErrorCode = "HISTORY_EXPIRED"
 
 	// Callback
 	ErrCodeCallbackExpired ErrorCode = "CALLBACK_EXPIRED"
 	ErrCodeCallbackFailed  ErrorCode = "CALLBACK_FAILED"
+	// The inbound message already has a reply
+	ErrCodeAlreadyReplied ErrorCode = "ALREADY_REPLIED"
 
 	// Internal
 	ErrCodeInternal ErrorCode = "INTERNAL_ERROR"
 	ErrCodeDatabase ErrorCode = "DATABASE_ERROR"
 	ErrCodeExternal ErrorCode = "EXTERNAL_SERVICE_ERROR"
+
+	// Unavailable (transient; the client may retry)
+	ErrCodeUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	// Shed: the request was refused before any work because the server is
+	// at its in-flight limit
+	ErrCodeShed ErrorCode = "SHED"
 )
 
 // AppError is a structured error that can be returned to clients
@@ -124,8 +144,16 @@ func ValidationError(message string) *AppError {
 	return New(ErrCodeValidation, message)
 }
 
+// InvalidInputDetails are the details of an InvalidInput error, kept so the
+// message can be rendered again in another language.
+type InvalidInputDetails struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
 func InvalidInput(field string, reason string) *AppError {
-	return New(ErrCodeInvalidInput, fmt.Sprintf("Invalid %s: %s", field, reason))
+	return New(ErrCodeInvalidInput, fmt.Sprintf("Invalid %s: %s", field, reason)).
+		WithDetails(InvalidInputDetails{Field: field, Reason: reason})
 }
 
 func MissingRequired(field string) *AppError {
@@ -144,10 +172,20 @@ func AlreadyPaired() *AppError {
 	return New(ErrCodeAlreadyPaired, "Session is already paired")
 }
 
+func UpgradeRequired(minimumVersion string) *AppError {
+	return New(ErrCodeUpgradeRequired, "Plugin version is no longer supported, please upgrade").
+		WithDetails(map[string]string{"minimumVersion": minimumVersion})
+}
+
 func RateLimitExceeded() *AppError {
 	return New(ErrCodeRateLimitExceeded, "Rate limit exceeded")
 }
 
+func TooManyPendingSessions(limit int) *AppError {
+	return New(ErrCodeTooManyPendingSessions, "Too many pending sessions from this address").
+		WithDetails(map[string]int{"limit": limit})
+}
+
 func CallbackExpired() *AppError {
 	return New(ErrCodeCallbackExpired, "Callback URL expired or not available")
 }
@@ -156,11 +194,34 @@ func CallbackFailed(reason string) *AppError {
 	return New(ErrCodeCallbackFailed, fmt.Sprintf("Failed to send callback: %s", reason))
 }
 
+// AlreadyReplied reports the reply that already answers the message.
+func AlreadyReplied(outboundID string) *AppError {
+	return New(ErrCodeAlreadyReplied, "Message has already been replied to").
+		WithDetails(map[string]string{"outboundId": outboundID})
+}
+
+func LegalHold(resource string) *AppError {
+	return New(ErrCodeLegalHold, fmt.Sprintf("%s is under legal hold", resource))
+}
+
+func HistoryExpired() *AppError {
+	return New(ErrCodeHistoryExpired, "Event is no longer in the history; resynchronize from the message queue")
+}
+
+func Shed() *AppError {
+	return New(ErrCodeShed, "Server is overloaded, please retry")
+}
+
 func Internal(message string) *AppError {
 	return New(ErrCodeInternal, message)
 }
 
+// Database wraps a database failure. Errors already classified by the
+// database layer keep their code.
 func Database(cause error) *AppError {
+	if appErr, ok := AsAppError(cause); ok {
+		return appErr
+	}
 	return Wrap(ErrCodeDatabase, "Database error", cause)
 }
 
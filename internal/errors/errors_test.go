@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,7 +53,9 @@ func TestErrorConstructors(t *testing.T) {
 		{"InvalidPairingCode", func() *AppError { return InvalidPairingCode() }, ErrCodeInvalidPairingCode},
 		{"PairingExpired", func() *AppError { return PairingExpired() }, ErrCodePairingExpired},
 		{"AlreadyPaired", func() *AppError { return AlreadyPaired() }, ErrCodeAlreadyPaired},
+		{"UpgradeRequired", func() *AppError { return UpgradeRequired("1.0.0") }, ErrCodeUpgradeRequired},
 		{"RateLimitExceeded", func() *AppError { return RateLimitExceeded() }, ErrCodeRateLimitExceeded},
+		{"TooManyPendingSessions", func() *AppError { return TooManyPendingSessions(5) }, ErrCodeTooManyPendingSessions},
 		{"CallbackExpired", func() *AppError { return CallbackExpired() }, ErrCodeCallbackExpired},
 		{"CallbackFailed", func() *AppError { return CallbackFailed("timeout") }, ErrCodeCallbackFailed},
 		{"Internal", func() *AppError { return Internal("test") }, ErrCodeInternal},
@@ -74,6 +77,12 @@ func TestDatabase(t *testing.T) {
 		assert.Equal(t, ErrCodeDatabase, err.Code)
 		assert.Equal(t, cause, err.Unwrap())
 	})
+
+	t.Run("keeps classified error code", func(t *testing.T) {
+		classified := Wrap(ErrCodeUnavailable, "Database temporarily unavailable", errors.New("deadlock"))
+		err := Database(fmt.Errorf("find session: %w", classified))
+		assert.Equal(t, ErrCodeUnavailable, err.Code)
+	})
 }
 
 func TestExternal(t *testing.T) {
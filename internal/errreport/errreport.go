@@ -0,0 +1,105 @@
+// Package errreport captures unexpected errors and panics for an external
+// error-reporting service. The Reporter interface keeps handlers independent
+// of the concrete backend (logs, Sentry).
+package errreport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Event is a captured error or panic.
+type Event struct {
+	Err       error
+	Stack     []byte
+	Tags      map[string]string
+	Method    string
+	URL       string
+	Timestamp time.Time
+}
+
+// Reporter sends events to an error-reporting backend. Capture must not block
+// the caller on network I/O.
+type Reporter interface {
+	Capture(ctx context.Context, event Event)
+}
+
+// Scope collects tags while a request flows through the middleware chain, so
+// that the recoverer at the top of the chain can attach identifiers (account,
+// session) that are only known further down.
+type Scope struct {
+	mu   sync.Mutex
+	tags map[string]string
+}
+
+func NewScope() *Scope {
+	return &Scope{tags: make(map[string]string)}
+}
+
+func (s *Scope) SetTag(key, value string) {
+	if s == nil || value == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[key] = value
+}
+
+// Tags returns a copy of the collected tags.
+func (s *Scope) Tags() map[string]string {
+	tags := make(map[string]string)
+	if s == nil {
+		return tags
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+type scopeKey struct{}
+
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFrom returns the request scope, or nil outside a request. SetTag is
+// safe to call on a nil scope.
+func ScopeFrom(ctx context.Context) *Scope {
+	if scope, ok := ctx.Value(scopeKey{}).(*Scope); ok {
+		return scope
+	}
+	return nil
+}
+
+// SetTag sets a tag on the request scope of ctx, if any.
+func SetTag(ctx context.Context, key, value string) {
+	ScopeFrom(ctx).SetTag(key, value)
+}
+
+type logReporter struct{}
+
+// NewLogReporter returns a Reporter that only logs events. It is used when no
+// error-reporting service is configured.
+func NewLogReporter() Reporter {
+	return logReporter{}
+}
+
+func (logReporter) Capture(ctx context.Context, event Event) {
+	logEvent := log.Error().Err(event.Err)
+	for k, v := range event.Tags {
+		logEvent = logEvent.Str(k, v)
+	}
+	if event.Method != "" {
+		logEvent = logEvent.Str("method", event.Method).Str("url", event.URL)
+	}
+	if len(event.Stack) > 0 {
+		logEvent = logEvent.Bytes("stack", event.Stack)
+	}
+	logEvent.Msg("captured error")
+}
@@ -0,0 +1,92 @@
+package errreport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	ctx := WithScope(context.Background(), NewScope())
+	SetTag(ctx, "account_id", "acc-1")
+	SetTag(ctx, "empty", "")
+
+	assert.Equal(t, map[string]string{"account_id": "acc-1"}, ScopeFrom(ctx).Tags())
+
+	// No scope outside a request: setting tags is a no-op.
+	SetTag(context.Background(), "account_id", "acc-1")
+	assert.Empty(t, ScopeFrom(context.Background()).Tags())
+}
+
+func TestParseSentryDSN(t *testing.T) {
+	tests := []struct {
+		dsn      string
+		storeURL string
+		key      string
+		wantErr  bool
+	}{
+		{dsn: "https://abc@o1.ingest.sentry.io/42", storeURL: "https://o1.ingest.sentry.io/api/42/store/", key: "abc"},
+		{dsn: "https://abc@glitchtip.example.com/prefix/7", storeURL: "https://glitchtip.example.com/prefix/api/7/store/", key: "abc"},
+		{dsn: "https://sentry.example.com/42", wantErr: true},
+		{dsn: "https://abc@sentry.example.com/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			storeURL, key, err := parseSentryDSN(tt.dsn)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.storeURL, storeURL)
+			assert.Equal(t, tt.key, key)
+		})
+	}
+}
+
+func TestSentryReporter_Capture(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/42"
+	reporter, err := NewSentryReporter(dsn, "test")
+	require.NoError(t, err)
+
+	reporter.Capture(context.Background(), Event{
+		Err:    errors.New("boom"),
+		Stack:  []byte("goroutine 1"),
+		Tags:   map[string]string{"request_id": "req-1"},
+		Method: http.MethodPost,
+		URL:    "/openclaw/reply",
+	})
+
+	select {
+	case req := <-received:
+		assert.Equal(t, "/api/42/store/", req.URL.Path)
+		assert.Contains(t, req.Header.Get("X-Sentry-Auth"), "sentry_key=public")
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not sent")
+	}
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(<-bodies, &payload))
+	assert.Equal(t, "test", payload["environment"])
+	assert.Equal(t, map[string]any{"request_id": "req-1"}, payload["tags"])
+	assert.Equal(t, map[string]any{"stack": "goroutine 1"}, payload["extra"])
+}
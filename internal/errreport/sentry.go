@@ -0,0 +1,145 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const sentryTimeout = 5 * time.Second
+
+// sentryReporter sends events to the Sentry store API. It speaks the plain
+// HTTP protocol, so any Sentry-compatible service (e.g. GlitchTip) works.
+type sentryReporter struct {
+	storeURL    string
+	authHeader  string
+	environment string
+	client      *http.Client
+	fallback    Reporter
+}
+
+// NewSentryReporter creates a Reporter for the given DSN
+// (https://<key>@<host>[/<path>]/<project>). Events are also logged.
+func NewSentryReporter(dsn, environment string) (Reporter, error) {
+	storeURL, key, err := parseSentryDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sentryReporter{
+		storeURL: storeURL,
+		authHeader: fmt.Sprintf(
+			"Sentry sentry_version=7, sentry_client=relay-server-go/1.0, sentry_key=%s", key,
+		),
+		environment: environment,
+		client:      &http.Client{Timeout: sentryTimeout},
+		fallback:    NewLogReporter(),
+	}, nil
+}
+
+func parseSentryDSN(dsn string) (storeURL, key string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SENTRY_DSN: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid SENTRY_DSN: missing public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	prefix, project := "", path
+	if idx >= 0 {
+		prefix, project = "/"+path[:idx], path[idx+1:]
+	}
+	if project == "" {
+		return "", "", fmt.Errorf("invalid SENTRY_DSN: missing project id")
+	}
+
+	return fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project), u.User.Username(), nil
+}
+
+func (r *sentryReporter) Capture(ctx context.Context, event Event) {
+	r.fallback.Capture(ctx, event)
+
+	body, err := json.Marshal(r.buildPayload(event))
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to encode sentry event")
+		return
+	}
+
+	// Send in the background: the request that failed should not wait on
+	// (or be cancelled together with) the report.
+	go r.send(body)
+}
+
+func (r *sentryReporter) buildPayload(event Event) map[string]any {
+	timestamp := event.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	errType, errValue := "error", ""
+	if event.Err != nil {
+		errType = fmt.Sprintf("%T", event.Err)
+		errValue = event.Err.Error()
+	}
+
+	payload := map[string]any{
+		"event_id":  newEventID(),
+		"timestamp": timestamp.UTC().Format(time.RFC3339),
+		"level":     "error",
+		"platform":  "go",
+		"logger":    "relay-server-go",
+		"exception": map[string]any{
+			"values": []map[string]any{{"type": errType, "value": errValue}},
+		},
+		"tags": event.Tags,
+	}
+	if r.environment != "" {
+		payload["environment"] = r.environment
+	}
+	if event.Method != "" {
+		payload["request"] = map[string]string{"method": event.Method, "url": event.URL}
+	}
+	if len(event.Stack) > 0 {
+		payload["extra"] = map[string]string{"stack": string(event.Stack)}
+	}
+	return payload
+}
+
+func (r *sentryReporter) send(body []byte) {
+	req, err := http.NewRequest(http.MethodPost, r.storeURL, bytes.NewReader(body))
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create sentry request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.authHeader)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to send sentry event")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Warn().Int("status", resp.StatusCode).Msg("sentry rejected event")
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
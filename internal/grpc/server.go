@@ -0,0 +1,350 @@
+// Package grpc serves the plugin API over gRPC: the event stream of
+// GET /v1/events and the reply and ack routes of the OpenClaw API, for many
+// subscriptions over one bidirectional stream. Requests run through the same
+// middleware and handlers as their HTTP counterparts, so authentication,
+// rate limits and responses match. Replies and acks are authenticated again
+// with the subscription's token, so one revoked since it subscribed is
+// refused.
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/grpc/relaypb"
+	"github.com/openclaw/relay-server-go/internal/handler"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// transport names gRPC streams in the event stream logs.
+const transport = "grpc"
+
+// forwardedMetadata lists the request metadata passed to the middleware as
+// HTTP headers.
+var forwardedMetadata = []string{
+	middleware.PluginVersionHeader,
+	"User-Agent",
+}
+
+// Server implements the Relay service.
+type Server struct {
+	relaypb.UnimplementedRelayServer
+
+	events *handler.EventsHandler
+	api    http.Handler
+	// The event stream behind the middleware of GET /v1/events
+	subscribe http.Handler
+}
+
+// NewServer creates the Relay service. authenticate is the middleware the
+// event stream route runs behind (authentication, plugin version and rate
+// limit); api serves the OpenClaw routes replies and acks are dispatched to
+// and must authenticate each request itself.
+func NewServer(events *handler.EventsHandler, api http.Handler, authenticate func(http.Handler) http.Handler) *Server {
+	s := &Server{events: events, api: api}
+	s.subscribe = authenticate(http.HandlerFunc(s.serveEvents))
+	return s
+}
+
+// Stream answers the client's requests in order until it closes the stream;
+// the subscriptions' events are sent in between.
+func (s *Server) Stream(stream relaypb.Relay_StreamServer) error {
+	c := newConn(stream)
+	defer c.close()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch payload := req.Payload.(type) {
+		case *relaypb.StreamRequest_Subscribe:
+			s.startSubscription(c, req.RequestId, payload.Subscribe)
+		case *relaypb.StreamRequest_Unsubscribe:
+			c.unsubscribe(req.RequestId, payload.Unsubscribe.GetSubscriptionId())
+		case *relaypb.StreamRequest_Reply:
+			s.reply(c, req.RequestId, payload.Reply)
+		case *relaypb.StreamRequest_Ack:
+			s.ack(c, req.RequestId, payload.Ack)
+		default:
+			c.respondError(req.RequestId, apperrors.ValidationError("Invalid request"))
+		}
+	}
+}
+
+// startSubscription serves the subscription's event stream until it is
+// unsubscribed, the client stream ends or the broker drops it. The response
+// to the request is sent once the subscription succeeded or failed.
+func (s *Server) startSubscription(c *conn, requestID string, req *relaypb.Subscribe) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	sub := &subscription{id: "sub_" + rand.Text(), requestID: requestID, conn: c, cancel: cancel}
+
+	r := c.newRequest(context.WithValue(ctx, subscriptionKey{}, sub), eventsURL(req))
+	if req.GetToken() != "" {
+		r.Header.Set("Authorization", "Bearer "+req.GetToken())
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer cancel()
+
+		w := handler.NewResponseRecorder()
+		s.subscribe.ServeHTTP(w, r)
+		if !c.remove(sub) {
+			// Never started
+			status, body := w.Result()
+			c.send(&relaypb.StreamResponse{Payload: &relaypb.StreamResponse_Response{Response: &relaypb.Response{
+				RequestId: requestID,
+				Status:    int32(status),
+				Body:      body,
+			}}})
+			return
+		}
+		if ctx.Err() == nil {
+			c.send(&relaypb.StreamResponse{Payload: &relaypb.StreamResponse_Closed{Closed: &relaypb.Closed{
+				SubscriptionId: sub.id,
+			}}})
+		}
+	}()
+}
+
+// serveEvents is the event stream handler behind the middleware.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	sub := r.Context().Value(subscriptionKey{}).(*subscription)
+	s.events.ServeEvents(w, r, transport, func(string) handler.EventSink {
+		sub.start(r)
+		return sub
+	})
+}
+
+func (s *Server) reply(c *conn, requestID string, req *relaypb.Reply) {
+	body := struct {
+		MessageID   string          `json:"messageId"`
+		Response    json.RawMessage `json:"response"`
+		ScheduledAt *time.Time      `json:"scheduledAt,omitempty"`
+	}{MessageID: req.GetMessageId(), Response: req.GetResponse()}
+	if req.GetScheduledAt() != nil {
+		scheduledAt := req.GetScheduledAt().AsTime()
+		body.ScheduledAt = &scheduledAt
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.respondError(requestID, apperrors.InvalidInput("response", "must be JSON"))
+		return
+	}
+	s.dispatch(c, requestID, req.GetSubscriptionId(), "/reply", data)
+}
+
+func (s *Server) ack(c *conn, requestID string, req *relaypb.Ack) {
+	data, err := json.Marshal(map[string][]string{"messageIds": req.GetMessageIds()})
+	if err != nil {
+		c.respondError(requestID, err)
+		return
+	}
+	s.dispatch(c, requestID, req.GetSubscriptionId(), "/messages/ack", data)
+}
+
+// dispatch runs the request through the OpenClaw API as a POST made by the
+// subscription's session.
+func (s *Server) dispatch(c *conn, requestID, subscriptionID, path string, body []byte) {
+	sub := c.find(subscriptionID)
+	if sub == nil {
+		c.respondError(requestID, apperrors.NotFound("Subscription"))
+		return
+	}
+	status, resp := handler.CallAPI(sub.request.Context(), s.api, sub.request, path, body)
+	c.respond(requestID, status, resp)
+}
+
+// eventsURL is the GET /v1/events request URL the subscription corresponds
+// to.
+func eventsURL(req *relaypb.Subscribe) string {
+	query := url.Values{}
+	if req.GetRoute() != "" {
+		query.Set("route", req.GetRoute())
+	}
+	if req.GetLastEventId() != "" {
+		query.Set("lastEventId", req.GetLastEventId())
+	}
+	if req.GetHeartbeatSeconds() > 0 {
+		query.Set("heartbeat", strconv.Itoa(int(req.GetHeartbeatSeconds())))
+	}
+	if len(query) == 0 {
+		return "/v1/events"
+	}
+	return "/v1/events?" + query.Encode()
+}
+
+// conn is one client stream. Sends are serialized since gRPC streams do not
+// allow concurrent sends.
+type conn struct {
+	stream relaypb.Relay_StreamServer
+	ctx    context.Context
+	cancel context.CancelFunc
+	// Request headers and address the synthetic HTTP requests carry
+	header     http.Header
+	remoteAddr string
+
+	sendMu sync.Mutex
+
+	mu   sync.Mutex
+	subs map[string]*subscription
+	wg   sync.WaitGroup
+}
+
+func newConn(stream relaypb.Relay_StreamServer) *conn {
+	// Responses use the v2 API format
+	ctx, cancel := context.WithCancel(httputil.WithAPIVersion(stream.Context(), httputil.APIVersionV2))
+	c := &conn{
+		stream: stream,
+		ctx:    ctx,
+		cancel: cancel,
+		header: http.Header{},
+		subs:   map[string]*subscription{},
+	}
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		for _, name := range forwardedMetadata {
+			if values := md.Get(strings.ToLower(name)); len(values) > 0 {
+				c.header.Set(name, values[0])
+			}
+		}
+	}
+	if p, ok := peer.FromContext(stream.Context()); ok && p.Addr != nil {
+		c.remoteAddr = p.Addr.String()
+	}
+	return c
+}
+
+// close ends the subscriptions and waits for their streams to finish.
+func (c *conn) close() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *conn) newRequest(ctx context.Context, target string) *http.Request {
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	r.Header = c.header.Clone()
+	r.RemoteAddr = c.remoteAddr
+	return r
+}
+
+func (c *conn) send(resp *relaypb.StreamResponse) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.stream.Send(resp)
+}
+
+func (c *conn) respond(requestID string, status int, body json.RawMessage) {
+	c.send(&relaypb.StreamResponse{Payload: &relaypb.StreamResponse_Response{Response: &relaypb.Response{
+		RequestId: requestID,
+		Status:    int32(status),
+		Body:      body,
+	}}})
+}
+
+func (c *conn) respondError(requestID string, err error) {
+	w := handler.NewResponseRecorder()
+	httputil.RespondError(w, c.newRequest(c.ctx, "/"), err)
+	status, body := w.Result()
+	c.respond(requestID, status, body)
+}
+
+func (c *conn) unsubscribe(requestID, subscriptionID string) {
+	sub := c.find(subscriptionID)
+	if sub == nil {
+		c.respondError(requestID, apperrors.NotFound("Subscription"))
+		return
+	}
+	sub.cancel()
+	c.remove(sub)
+	c.respond(requestID, http.StatusOK, nil)
+}
+
+func (c *conn) find(subscriptionID string) *subscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subs[subscriptionID]
+}
+
+// remove reports whether the subscription was started and not yet removed.
+func (c *conn) remove(sub *subscription) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subs[sub.id] != sub {
+		return sub.started
+	}
+	delete(c.subs, sub.id)
+	return true
+}
+
+type subscriptionKey struct{}
+
+// subscription is one event stream of a conn and the sink of its events.
+type subscription struct {
+	id        string
+	requestID string
+	conn      *conn
+	cancel    context.CancelFunc
+	// The authenticated event stream request, which replies and acks are
+	// made with; set when the subscription started
+	request *http.Request
+	started bool
+}
+
+// start registers the subscription and answers the Subscribe request, before
+// any of its events are sent.
+func (s *subscription) start(r *http.Request) {
+	s.conn.mu.Lock()
+	s.request = r
+	s.started = true
+	s.conn.subs[s.id] = s
+	s.conn.mu.Unlock()
+
+	s.conn.send(&relaypb.StreamResponse{Payload: &relaypb.StreamResponse_Response{Response: &relaypb.Response{
+		RequestId:      s.requestID,
+		Status:         http.StatusOK,
+		SubscriptionId: s.id,
+	}}})
+}
+
+func (s *subscription) Send(event sse.Event) error {
+	envelope := event.Envelope()
+	return s.conn.send(&relaypb.StreamResponse{Payload: &relaypb.StreamResponse_Event{Event: &relaypb.Event{
+		SubscriptionId:  s.id,
+		Id:              envelope.ID,
+		Seq:             envelope.Seq,
+		Type:            envelope.Type,
+		OccurredAt:      timestamppb.New(envelope.OccurredAt),
+		AccountId:       envelope.AccountID,
+		ConversationKey: envelope.ConversationKey,
+		Data:            envelope.Data,
+	}}})
+}
+
+func (s *subscription) Heartbeat(now time.Time) error {
+	return s.conn.send(&relaypb.StreamResponse{Payload: &relaypb.StreamResponse_Heartbeat{Heartbeat: &relaypb.Heartbeat{
+		SubscriptionId: s.id,
+		Time:           timestamppb.New(now),
+	}}})
+}
@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/grpc/relaypb"
+	"github.com/openclaw/relay-server-go/internal/handler"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+func TestServer(t *testing.T) {
+	session := &model.Session{ID: "sess-1", Status: model.SessionStatusPendingPairing}
+
+	// Stands in for the auth middleware: only the session's token is valid,
+	// until it is revoked
+	revoked := false
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if revoked || r.Header.Get("Authorization") != "Bearer session-token" {
+				httputil.RespondError(w, r, apperrors.Unauthorized("Unauthorized"))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.SessionContextKey, session)))
+		})
+	}
+	// Echoes the OpenClaw API requests with the session they were made by
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{
+			"path":      r.URL.Path,
+			"sessionId": middleware.GetSession(r.Context()).ID,
+			"body":      json.RawMessage(body),
+		})
+	})
+
+	broker := sse.NewMemoryBroker(sse.BrokerOptions{})
+	defer broker.Close()
+	events := handler.NewEventsHandler(broker, nil, nil, nil, nil, nil)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpclib.NewServer()
+	relaypb.RegisterRelayServer(server, NewServer(events, authenticate(api), authenticate))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	client, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := relaypb.NewRelayClient(client).Stream(ctx)
+	require.NoError(t, err)
+
+	request := func(req *relaypb.StreamRequest) *relaypb.Response {
+		t.Helper()
+		require.NoError(t, stream.Send(req))
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.NotNil(t, resp.GetResponse(), "expected a response, got %v", resp)
+		assert.Equal(t, req.RequestId, resp.GetResponse().RequestId)
+		return resp.GetResponse()
+	}
+	subscribe := func(id, token string) *relaypb.Response {
+		return request(&relaypb.StreamRequest{RequestId: id, Payload: &relaypb.StreamRequest_Subscribe{
+			Subscribe: &relaypb.Subscribe{Token: token},
+		}})
+	}
+	recvEvent := func() *relaypb.Event {
+		t.Helper()
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.NotNil(t, resp.GetEvent(), "expected an event, got %v", resp)
+		return resp.GetEvent()
+	}
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		resp := subscribe("s0", "wrong")
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.Status)
+		assert.Empty(t, resp.SubscriptionId)
+	})
+
+	resp := subscribe("s1", "session-token")
+	require.Equal(t, int32(http.StatusOK), resp.Status)
+	subscriptionID := resp.SubscriptionId
+	require.NotEmpty(t, subscriptionID)
+
+	connected := recvEvent()
+	assert.Equal(t, "connected", connected.Type)
+	assert.Equal(t, subscriptionID, connected.SubscriptionId)
+
+	t.Run("delivers the session's events", func(t *testing.T) {
+		require.NoError(t, broker.Publish(ctx, "session:sess-1", sse.NewRawEvent("pairing_complete", "", "", json.RawMessage(`{"accountId":"acc-1"}`))))
+		event := recvEvent()
+		assert.Equal(t, "pairing_complete", event.Type)
+		assert.Equal(t, subscriptionID, event.SubscriptionId)
+		assert.NotEmpty(t, event.Id)
+		assert.JSONEq(t, `{"accountId":"acc-1"}`, string(event.Data))
+	})
+
+	t.Run("dispatches acks as the subscription's session", func(t *testing.T) {
+		resp := request(&relaypb.StreamRequest{RequestId: "a1", Payload: &relaypb.StreamRequest_Ack{
+			Ack: &relaypb.Ack{SubscriptionId: subscriptionID, MessageIds: []string{"in-1"}},
+		}})
+		assert.Equal(t, int32(http.StatusOK), resp.Status)
+		assert.JSONEq(t, `{"path":"/messages/ack","sessionId":"sess-1","body":{"messageIds":["in-1"]}}`, string(resp.Body))
+	})
+
+	t.Run("dispatches replies", func(t *testing.T) {
+		resp := request(&relaypb.StreamRequest{RequestId: "r1", Payload: &relaypb.StreamRequest_Reply{
+			Reply: &relaypb.Reply{SubscriptionId: subscriptionID, MessageId: "in-1", Response: []byte(`{"version":"2.0"}`)},
+		}})
+		assert.Equal(t, int32(http.StatusOK), resp.Status)
+		assert.JSONEq(t, `{"path":"/reply","sessionId":"sess-1","body":{"messageId":"in-1","response":{"version":"2.0"}}}`, string(resp.Body))
+	})
+
+	t.Run("rejects replies that are not JSON", func(t *testing.T) {
+		resp := request(&relaypb.StreamRequest{RequestId: "r2", Payload: &relaypb.StreamRequest_Reply{
+			Reply: &relaypb.Reply{SubscriptionId: subscriptionID, MessageId: "in-1", Response: []byte(`{`)},
+		}})
+		assert.Equal(t, int32(http.StatusBadRequest), resp.Status)
+	})
+
+	t.Run("authenticates dispatched requests again", func(t *testing.T) {
+		revoked = true
+		defer func() { revoked = false }()
+
+		resp := request(&relaypb.StreamRequest{RequestId: "a0", Payload: &relaypb.StreamRequest_Ack{
+			Ack: &relaypb.Ack{SubscriptionId: subscriptionID, MessageIds: []string{"in-1"}},
+		}})
+		assert.Equal(t, int32(http.StatusUnauthorized), resp.Status)
+	})
+
+	t.Run("rejects unknown subscriptions", func(t *testing.T) {
+		resp := request(&relaypb.StreamRequest{RequestId: "a2", Payload: &relaypb.StreamRequest_Ack{
+			Ack: &relaypb.Ack{SubscriptionId: "sub_missing", MessageIds: []string{"in-1"}},
+		}})
+		assert.Equal(t, int32(http.StatusNotFound), resp.Status)
+	})
+
+	t.Run("unsubscribes", func(t *testing.T) {
+		resp := request(&relaypb.StreamRequest{RequestId: "u1", Payload: &relaypb.StreamRequest_Unsubscribe{
+			Unsubscribe: &relaypb.Unsubscribe{SubscriptionId: subscriptionID},
+		}})
+		assert.Equal(t, int32(http.StatusOK), resp.Status)
+
+		resp = request(&relaypb.StreamRequest{RequestId: "a3", Payload: &relaypb.StreamRequest_Ack{
+			Ack: &relaypb.Ack{SubscriptionId: subscriptionID, MessageIds: []string{"in-1"}},
+		}})
+		assert.Equal(t, int32(http.StatusNotFound), resp.Status)
+	})
+
+	t.Run("reports subscriptions the broker closed", func(t *testing.T) {
+		resp := subscribe("s2", "session-token")
+		require.Equal(t, int32(http.StatusOK), resp.Status)
+		assert.Equal(t, "connected", recvEvent().Type)
+
+		broker.Close()
+		closed, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, resp.SubscriptionId, closed.GetClosed().GetSubscriptionId())
+	})
+}
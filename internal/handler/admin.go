@@ -3,19 +3,38 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 
+	"github.com/openclaw/relay-server-go/internal/alert"
 	"github.com/openclaw/relay-server-go/internal/audit"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/jobs"
 	"github.com/openclaw/relay-server-go/internal/middleware"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/openclaw/relay-server-go/internal/sse"
 	"github.com/openclaw/relay-server-go/internal/util"
 )
 
 type AdminHandler struct {
 	adminService      *service.AdminService
+	integrityService  *service.IntegrityService
+	erasureService    *service.ErasureService
+	pairingService    *service.PairingService
+	sessionService    *service.SessionService
+	configService     *service.AccountConfigService
+	settingsService   *service.AccountSettingsService
+	historyService    *service.ChangeHistoryService
+	notifications     *service.AdminNotificationService
+	deploymentService *service.DeploymentService
+	broker            *sse.Broker
 	sessionMiddleware func(http.Handler) http.Handler
 	loginRateLimiter  *middleware.LoginRateLimiter
 	isProduction      bool
@@ -23,13 +42,34 @@ type AdminHandler struct {
 
 func NewAdminHandler(
 	adminService *service.AdminService,
+	integrityService *service.IntegrityService,
+	erasureService *service.ErasureService,
+	pairingService *service.PairingService,
+	sessionService *service.SessionService,
+	configService *service.AccountConfigService,
+	settingsService *service.AccountSettingsService,
+	historyService *service.ChangeHistoryService,
+	notifications *service.AdminNotificationService,
+	deploymentService *service.DeploymentService,
+	broker *sse.Broker,
 	sessionMiddleware func(http.Handler) http.Handler,
+	loginRateLimiter *middleware.LoginRateLimiter,
 	isProduction bool,
 ) *AdminHandler {
 	return &AdminHandler{
 		adminService:      adminService,
+		integrityService:  integrityService,
+		erasureService:    erasureService,
+		pairingService:    pairingService,
+		sessionService:    sessionService,
+		configService:     configService,
+		settingsService:   settingsService,
+		historyService:    historyService,
+		notifications:     notifications,
+		deploymentService: deploymentService,
+		broker:            broker,
 		sessionMiddleware: sessionMiddleware,
-		loginRateLimiter:  middleware.NewLoginRateLimiter(),
+		loginRateLimiter:  loginRateLimiter,
 		isProduction:      isProduction,
 	}
 }
@@ -39,21 +79,62 @@ func (h *AdminHandler) Routes() chi.Router {
 
 	r.With(h.loginRateLimiter.Handler).Post("/api/login", h.Login)
 	r.Post("/api/logout", h.Logout)
+	r.Get("/api/bootstrap", h.BootstrapStatus)
+	r.With(h.loginRateLimiter.Handler).Post("/api/bootstrap", h.Bootstrap)
+
+	r.With(h.sessionMiddleware).Post("/api/password", h.ChangePassword)
 
 	r.Group(func(r chi.Router) {
 		r.Use(h.sessionMiddleware)
+		r.Use(h.requirePasswordRotation)
 		r.Get("/api/stats", h.Stats)
+		r.Get("/api/ratelimit", h.RateLimitStats)
+		r.Get("/api/inbound-limits", h.InboundLimitStats)
+		r.Get("/api/inbound-degraded", h.InboundDegradedStats)
+		r.Get("/api/cleanup-stats", h.CleanupStats)
+		r.Get("/api/sse/channels", h.SSEChannels)
+		r.With(h.loginRateLimiter.Handler).Post("/api/reauth", h.Reauthenticate)
+
+		// Destructive actions require a recent password entry
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireRecentAdminAuth(middleware.AdminStepUpMaxAge))
+			r.Delete("/api/accounts/{id}", h.DeleteAccount)
+			r.Post("/api/accounts/{id}/regenerate-token", h.RegenerateToken)
+			r.Delete("/api/users/{id}", h.DeleteUser)
+			r.Delete("/api/kakao-users/{userKey}", h.EraseKakaoUser)
+			r.Delete("/api/accounts/{id}/legal-hold", h.ReleaseAccountLegalHold)
+			r.Delete("/api/mappings/{id}/legal-hold", h.ReleaseMappingLegalHold)
+			r.Post("/api/messages/inbound/expire", h.ExpireInboundMessages)
+			r.Put("/api/accounts/{id}/config", h.ImportAccountConfig)
+			r.Delete("/api/mappings/{id}", h.DeleteMapping)
+		})
+
+		// Admin sessions
+		r.Get("/api/admin-sessions", h.ListAdminSessions)
+		r.Delete("/api/admin-sessions/{id}", h.RevokeAdminSession)
 
 		// Accounts
 		r.Get("/api/accounts", h.ListAccounts)
 		r.Post("/api/accounts", h.CreateAccount)
 		r.Get("/api/accounts/{id}", h.GetAccount)
-		r.Delete("/api/accounts/{id}", h.DeleteAccount)
-		r.Post("/api/accounts/{id}/regenerate-token", h.RegenerateToken)
+		r.Patch("/api/accounts/{id}", h.UpdateAccount)
+		r.Get("/api/accounts/{id}/deletion-preview", h.PreviewDeleteAccount)
+		r.Get("/api/accounts/{id}/config", h.ExportAccountConfig)
+		r.Get("/api/accounts/{id}/settings", h.GetAccountSettings)
+		r.Patch("/api/accounts/{id}/settings", h.UpdateAccountSettings)
+		r.Put("/api/accounts/{id}/legal-hold", h.SetAccountLegalHold)
 
 		// Mappings
 		r.Get("/api/mappings", h.ListMappings)
-		r.Delete("/api/mappings/{id}", h.DeleteMapping)
+		r.Put("/api/mappings/{id}/legal-hold", h.SetMappingLegalHold)
+
+		// Change history
+		r.Get("/api/history", h.ListChangeHistory)
+
+		// Notifications
+		r.Get("/api/notifications", h.ListNotifications)
+		r.Post("/api/notifications/read-all", h.MarkAllNotificationsRead)
+		r.Post("/api/notifications/{id}/read", h.MarkNotificationRead)
 
 		// Messages
 		r.Get("/api/messages/inbound", h.ListInboundMessages)
@@ -63,12 +144,23 @@ func (h *AdminHandler) Routes() chi.Router {
 		r.Get("/api/users", h.ListUsers)
 		r.Get("/api/users/{id}", h.GetUser)
 		r.Patch("/api/users/{id}", h.UpdateUser)
-		r.Delete("/api/users/{id}", h.DeleteUser)
 
 		// Sessions (Plugin Sessions)
 		r.Get("/api/sessions", h.ListSessions)
 		r.Delete("/api/sessions/{id}", h.DeleteSession)
 		r.Post("/api/sessions/{id}/disconnect", h.DisconnectSession)
+		r.Get("/api/plugin-versions", h.PluginVersions)
+
+		// Pairing (codes and sessions awaiting pairing)
+		r.Get("/api/pairing/codes", h.ListPairingCodes)
+		r.Delete("/api/pairing/codes/{code}", h.RevokePairingCode)
+		r.Get("/api/pairing/sessions", h.ListPendingSessions)
+		r.Delete("/api/pairing/sessions/{id}", h.RevokePendingSession)
+		r.Post("/api/pairing/sessions/{id}/pair", h.ForcePairSession)
+
+		// Data integrity
+		r.Get("/api/integrity", h.CheckIntegrity)
+		r.Post("/api/integrity/repair", h.RepairIntegrity)
 	})
 
 	return r
@@ -79,14 +171,14 @@ func (h *AdminHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Password string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "password is required"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "password")
 		return
 	}
 
-	token, err := h.adminService.Login(r.Context(), req.Password)
+	token, err := h.adminService.Login(r.Context(), req.Password, adminClient(r))
 	if err != nil {
 		log.Error().Err(err).Msg("admin login error")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Login failed"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APILoginFailed)
 		return
 	}
 
@@ -98,7 +190,7 @@ func (h *AdminHandler) Login(w http.ResponseWriter, r *http.Request) {
 				"target": "admin",
 			},
 		})
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Invalid password"})
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIInvalidPassword)
 		return
 	}
 
@@ -109,7 +201,123 @@ func (h *AdminHandler) Login(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 
-	middleware.SetSessionCookie(w, middleware.AdminSessionCookie, token, "/admin", h.isProduction)
+	middleware.SetSessionCookie(w, middleware.AdminSessionCookie, token, "/admin", h.adminService.SessionMaxAge(), h.isProduction)
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// BootstrapStatus reports whether the deployment still needs its first-run
+// bootstrap.
+func (h *AdminHandler) BootstrapStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.deploymentService.Info())
+}
+
+// Bootstrap sets the admin password of a first-run deployment. It requires
+// the setup token printed to the server log at startup.
+func (h *AdminHandler) Bootstrap(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SetupToken     string `json:"setupToken"`
+		Password       string `json:"password"`
+		DeploymentName string `json:"deploymentName"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	info, err := h.deploymentService.Bootstrap(r.Context(), service.BootstrapParams{
+		SetupToken:     req.SetupToken,
+		Password:       req.Password,
+		DeploymentName: req.DeploymentName,
+		RemoteAddr:     httputil.ClientIP(r),
+	})
+	if err != nil {
+		switch apperrors.GetCode(err) {
+		case apperrors.ErrCodeInvalidInput:
+			appErr, _ := apperrors.AsAppError(err)
+			writeAppError(w, r, appErr)
+		case apperrors.ErrCodeForbidden:
+			audit.LogFromRequest(r, audit.Event{
+				Type: audit.EventAuthFailure,
+				Details: map[string]interface{}{
+					"reason": "invalid_setup_token",
+					"target": "bootstrap",
+				},
+			})
+			writeError(w, r, http.StatusForbidden, apperrors.ErrCodeForbidden, i18n.APIInvalidSetupToken)
+		case apperrors.ErrCodeConflict:
+			writeError(w, r, http.StatusConflict, apperrors.ErrCodeConflict, i18n.APIAlreadyBootstrapped)
+		default:
+			log.Error().Err(err).Msg("bootstrap failed")
+			writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIBootstrapFailed)
+		}
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventBootstrap,
+		Details: map[string]interface{}{
+			"deployment_name": info.Name,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, info)
+}
+
+// requirePasswordRotation blocks the admin API until an expired admin
+// password has been changed.
+func (h *AdminHandler) requirePasswordRotation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.deploymentService.PasswordChangeRequired() {
+			writeError(w, r, http.StatusForbidden, apperrors.ErrCodePasswordChangeRequired, i18n.APIPasswordChangeRequired)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ChangePassword replaces the admin password. All admin sessions are ended and
+// the caller receives a fresh session cookie.
+func (h *AdminHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	token, err := h.adminService.ChangePassword(r.Context(), req.CurrentPassword, req.NewPassword, adminClient(r))
+	if err != nil {
+		switch apperrors.GetCode(err) {
+		case apperrors.ErrCodeInvalidInput:
+			appErr, _ := apperrors.AsAppError(err)
+			writeAppError(w, r, appErr)
+		case apperrors.ErrCodeUnauthorized:
+			audit.LogFromRequest(r, audit.Event{
+				Type: audit.EventAuthFailure,
+				Details: map[string]interface{}{
+					"reason": "invalid_password",
+					"target": "password_change",
+				},
+			})
+			// 403 rather than 401 so the admin UI does not treat it as an expired session.
+			writeError(w, r, http.StatusForbidden, apperrors.ErrCodeForbidden, i18n.APICurrentPasswordIncorrect)
+		default:
+			log.Error().Err(err).Msg("admin password change failed")
+			writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIPasswordChangeFailed)
+		}
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventPasswordChange,
+		Details: map[string]interface{}{
+			"target": "admin",
+		},
+	})
+
+	middleware.SetSessionCookie(w, middleware.AdminSessionCookie, token, "/admin", h.adminService.SessionMaxAge(), h.isProduction)
 	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
@@ -134,27 +342,82 @@ func (h *AdminHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.adminService.GetStats(r.Context())
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get stats")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, stats)
 }
 
+func (h *AdminHandler) RateLimitStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"scopes": ratelimit.Stats()})
+}
+
+func (h *AdminHandler) InboundLimitStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, service.GetInboundLimitStats())
+}
+
+func (h *AdminHandler) InboundDegradedStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, service.GetInboundDegradedStats())
+}
+
+func (h *AdminHandler) CleanupStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, jobs.GetCleanupStats())
+}
+
+func (h *AdminHandler) SSEChannels(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.broker.Stats())
+}
+
+// invalidEnum rejects a query parameter outside its allowed values.
+func invalidEnum(field string, valid []string) *apperrors.AppError {
+	return apperrors.InvalidInput(field, "must be one of "+strings.Join(valid, ", "))
+}
+
+var validAccountModes = []string{string(model.AccountModeDirect), string(model.AccountModeRelay)}
+
+func parseAccountFilter(r *http.Request) (model.AccountFilter, error) {
+	var filter model.AccountFilter
+	var err error
+
+	mode := r.URL.Query().Get("mode")
+	if !util.IsValidEnum(mode, validAccountModes) {
+		return filter, invalidEnum("mode", validAccountModes)
+	}
+	filter.Mode = model.AccountMode(mode)
+
+	if filter.CreatedAfter, err = queryTime(r, "createdAfter"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = queryTime(r, "createdBefore"); err != nil {
+		return filter, err
+	}
+	if filter.HasPortalUser, err = queryBool(r, "hasPortalUser"); err != nil {
+		return filter, err
+	}
+	if filter.Suspended, err = queryBool(r, "suspended"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 func (h *AdminHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 	p := ParsePagination(r)
 
-	accounts, err := h.adminService.GetAccounts(r.Context(), p.Limit, p.Offset)
+	filter, err := parseAccountFilter(r)
+	if err != nil {
+		writeAppError(w, r, err)
+		return
+	}
+
+	accounts, total, err := h.adminService.GetAccounts(r.Context(), filter, p.Limit, p.Offset)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list accounts")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items": accounts,
-		"total": len(accounts),
-	})
+	writePage(w, accounts, total, p)
 }
 
 func (h *AdminHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
@@ -164,7 +427,7 @@ func (h *AdminHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		RateLimitPerMinute int     `json:"rateLimitPerMinute"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
 		return
 	}
 
@@ -181,7 +444,7 @@ func (h *AdminHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	account, token, err := h.adminService.CreateAccount(r.Context(), req.OpenclawUserID, mode, rateLimit)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create account")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -201,24 +464,217 @@ func (h *AdminHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	account, err := h.adminService.GetAccountByID(r.Context(), id)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get account")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
 	if account == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Account not found"})
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, account)
 }
 
+func (h *AdminHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		KakaoChannelID *string `json:"kakaoChannelId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+	if req.KakaoChannelID == nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APINothingToUpdate)
+		return
+	}
+
+	account, err := h.adminService.UpdateAccountKakaoChannel(r.Context(), id, strings.TrimSpace(*req.KakaoChannelID))
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		log.Error().Err(err).Msg("failed to update account")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	if account == nil {
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventAccountUpdate,
+		AccountID: id,
+		Details: map[string]interface{}{
+			"updated_by":       "admin",
+			"kakao_channel_id": strings.TrimSpace(*req.KakaoChannelID),
+		},
+	})
+
+	writeJSON(w, http.StatusOK, account)
+}
+
+// ExportAccountConfig returns the account's portable configuration as a JSON
+// download.
+func (h *AdminHandler) ExportAccountConfig(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	cfg, err := h.configService.Export(r.Context(), id)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to export account config")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", accountConfigDisposition(id))
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+// ImportAccountConfig applies an exported configuration, including mode and
+// rate limit, to the account.
+func (h *AdminHandler) ImportAccountConfig(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var cfg service.AccountConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	result, err := h.configService.Import(r.Context(), id, cfg, true)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to import account config")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventConfigImport,
+		AccountID: id,
+		Details: map[string]interface{}{
+			"imported_by":    "admin",
+			"labels_applied": result.LabelsApplied,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *AdminHandler) GetAccountSettings(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	settings, err := h.settingsService.List(r.Context(), id)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to get account settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetAccountSettingsFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"settings": settings})
+}
+
+// UpdateAccountSettings changes the account's settings, including the
+// admin-only ones.
+func (h *AdminHandler) UpdateAccountSettings(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	settings, changes, err := h.settingsService.Update(r.Context(), id, req, true)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			switch appErr.Code {
+			case apperrors.ErrCodeInvalidInput:
+				writeAppError(w, r, appErr)
+				return
+			case apperrors.ErrCodeNotFound:
+				writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+				return
+			}
+		}
+		log.Error().Err(err).Msg("failed to update account settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateAccountSettingsFailed)
+		return
+	}
+
+	if len(changes) > 0 {
+		audit.LogFromRequest(r, audit.Event{
+			Type:      audit.EventSettingsUpdate,
+			AccountID: id,
+			Details: map[string]interface{}{
+				"updated_by": "admin",
+				"changes":    changes,
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"settings": settings})
+}
+
+func (h *AdminHandler) PreviewDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	preview, err := h.adminService.PreviewDeleteAccount(r.Context(), id)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to preview account deletion")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preview)
+}
+
 func (h *AdminHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.adminService.DeleteAccount(r.Context(), id); err != nil {
+	var req struct {
+		PreviewToken string `json:"previewToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "previewToken")
+		return
+	}
+
+	if err := h.adminService.DeleteAccount(r.Context(), id, req.PreviewToken); err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeLegalHold {
+			writeAppError(w, r, appErr)
+			return
+		}
 		log.Error().Err(err).Msg("failed to delete account")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -239,7 +695,7 @@ func (h *AdminHandler) RegenerateToken(w http.ResponseWriter, r *http.Request) {
 	token, err := h.adminService.RegenerateToken(r.Context(), id)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to regenerate token")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -256,35 +712,203 @@ func (h *AdminHandler) RegenerateToken(w http.ResponseWriter, r *http.Request) {
 
 // Mappings
 
+var validPairingStates = []string{
+	string(model.PairingStateUnpaired),
+	string(model.PairingStatePending),
+	string(model.PairingStatePaired),
+	string(model.PairingStateBlocked),
+	string(model.PairingStateArchived),
+}
+
+func parseMappingFilter(r *http.Request) (model.MappingFilter, error) {
+	q := r.URL.Query()
+	filter := model.MappingFilter{
+		AccountID:      q.Get("accountId"),
+		State:          model.PairingState(q.Get("state")),
+		KakaoChannelID: q.Get("channel"),
+	}
+	var err error
+
+	if filter.AccountID != "" && !util.IsValidUUID(filter.AccountID) {
+		return filter, apperrors.InvalidInput("accountId", "must be a UUID")
+	}
+	if !util.IsValidEnum(string(filter.State), validPairingStates) {
+		return filter, invalidEnum("state", validPairingStates)
+	}
+	if filter.LastSeenAfter, err = queryTime(r, "lastSeenAfter"); err != nil {
+		return filter, err
+	}
+	if filter.LastSeenBefore, err = queryTime(r, "lastSeenBefore"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 func (h *AdminHandler) ListMappings(w http.ResponseWriter, r *http.Request) {
 	p := ParsePagination(r)
-	accountID := r.URL.Query().Get("accountId")
 
-	mappings, total, err := h.adminService.GetMappings(r.Context(), p.Limit, p.Offset, accountID)
+	filter, err := parseMappingFilter(r)
+	if err != nil {
+		writeAppError(w, r, err)
+		return
+	}
+
+	mappings, total, err := h.adminService.GetMappings(r.Context(), filter, p.Limit, p.Offset)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list mappings")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items": mappings,
-		"total": total,
-	})
+	writePage(w, mappings, total, p)
 }
 
 func (h *AdminHandler) DeleteMapping(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.adminService.DeleteMapping(r.Context(), id); err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeLegalHold {
+			writeAppError(w, r, appErr)
+			return
+		}
 		log.Error().Err(err).Msg("failed to delete mapping")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// Change history
+
+var validChangeEntityTypes = []string{
+	string(model.ChangeEntityAccountSetting),
+	string(model.ChangeEntityConversationState),
+}
+
+func parseChangeHistoryFilter(r *http.Request) (model.ChangeHistoryFilter, error) {
+	q := r.URL.Query()
+	filter := model.ChangeHistoryFilter{
+		AccountID:  q.Get("accountId"),
+		EntityType: model.ChangeEntityType(q.Get("entityType")),
+		EntityKey:  q.Get("entityKey"),
+	}
+	var err error
+
+	if filter.AccountID != "" && !util.IsValidUUID(filter.AccountID) {
+		return filter, apperrors.InvalidInput("accountId", "must be a UUID")
+	}
+	if !util.IsValidEnum(string(filter.EntityType), validChangeEntityTypes) {
+		return filter, invalidEnum("entityType", validChangeEntityTypes)
+	}
+	if filter.Since, err = queryTime(r, "since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = queryTime(r, "until"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// ListChangeHistory lists changes to account settings and conversation
+// states, newest first.
+func (h *AdminHandler) ListChangeHistory(w http.ResponseWriter, r *http.Request) {
+	p := ParsePagination(r)
+
+	filter, err := parseChangeHistoryFilter(r)
+	if err != nil {
+		writeAppError(w, r, err)
+		return
+	}
+
+	entries, total, err := h.historyService.List(r.Context(), filter, p.Limit, p.Offset)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list change history")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetChangeHistoryFailed)
+		return
+	}
+
+	writePage(w, entries, total, p)
+}
+
+// Notifications
+
+func parseNotificationFilter(r *http.Request) (model.AdminNotificationFilter, error) {
+	filter := model.AdminNotificationFilter{Kind: r.URL.Query().Get("kind")}
+
+	kinds := make([]string, len(alert.Kinds))
+	for i, kind := range alert.Kinds {
+		kinds[i] = string(kind)
+	}
+	if !util.IsValidEnum(filter.Kind, kinds) {
+		return filter, invalidEnum("kind", kinds)
+	}
+	unread, err := queryBool(r, "unread")
+	if err != nil {
+		return filter, err
+	}
+	filter.UnreadOnly = unread != nil && *unread
+	return filter, nil
+}
+
+// ListNotifications lists the server's alerts kept for admins, most recent
+// first, with the number of unread notifications.
+func (h *AdminHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	p := ParsePagination(r)
+
+	filter, err := parseNotificationFilter(r)
+	if err != nil {
+		writeAppError(w, r, err)
+		return
+	}
+
+	items, total, unread, err := h.notifications.List(r.Context(), filter, p.Limit, p.Offset)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list admin notifications")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetNotificationsFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":       items,
+		"total":       total,
+		"unreadCount": unread,
+		"limit":       p.Limit,
+		"offset":      p.Offset,
+	})
+}
+
+func (h *AdminHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !util.IsValidUUID(id) {
+		writeAppError(w, r, apperrors.InvalidInput("id", "must be a UUID"))
+		return
+	}
+
+	if err := h.notifications.MarkRead(r.Context(), id); err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeAppError(w, r, err)
+			return
+		}
+		log.Error().Err(err).Msg("failed to mark admin notification read")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateNotificationsFailed)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
+func (h *AdminHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	count, err := h.notifications.MarkAllRead(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to mark admin notifications read")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateNotificationsFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "count": count})
+}
+
 // Messages
 
 var validInboundStatuses = []string{"queued", "delivered", "expired", "failed"}
@@ -295,25 +919,75 @@ func (h *AdminHandler) ListInboundMessages(w http.ResponseWriter, r *http.Reques
 	status := r.URL.Query().Get("status")
 
 	if accountID != "" && !util.IsValidUUID(accountID) {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid accountId format"})
+		writeAppError(w, r, apperrors.InvalidInput("accountId", "must be a UUID"))
 		return
 	}
 	if !util.IsValidEnum(status, validInboundStatuses) {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid status value"})
+		writeAppError(w, r, invalidEnum("status", validInboundStatuses))
 		return
 	}
 
 	messages, total, err := h.adminService.GetInboundMessages(r.Context(), p.Limit, p.Offset, accountID, status)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list inbound messages")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items": messages,
-		"total": total,
-	})
+	writePage(w, messages, total, p)
+}
+
+// ExpireInboundMessages expires queued messages matching the filters in the
+// body instead of waiting for their callback to expire. ?dryRun=true only
+// counts them.
+func (h *AdminHandler) ExpireInboundMessages(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		AccountID       string `json:"accountId"`
+		ConversationKey string `json:"conversationKey"`
+		// Go duration, e.g. "30m"
+		OlderThan string `json:"olderThan"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	filter := model.InboundExpiryFilter{AccountID: req.AccountID, ConversationKey: req.ConversationKey}
+	if req.OlderThan != "" {
+		olderThan, err := time.ParseDuration(req.OlderThan)
+		if err != nil || olderThan <= 0 {
+			writeAppError(w, r, apperrors.InvalidInput("olderThan", "must be a positive duration such as 30m"))
+			return
+		}
+		before := time.Now().Add(-olderThan)
+		filter.CreatedBefore = &before
+	}
+
+	result, err := h.adminService.ExpireInboundMessages(r.Context(), filter, dryRun)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			writeAppError(w, r, appErr)
+			return
+		}
+		log.Error().Err(err).Msg("failed to expire inbound messages")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	if !dryRun {
+		audit.LogFromRequest(r, audit.Event{
+			Type:      audit.EventInboundExpire,
+			AccountID: req.AccountID,
+			Details: map[string]interface{}{
+				"conversationKey": req.ConversationKey,
+				"olderThan":       req.OlderThan,
+				"expired":         result.Expired,
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, result)
 }
 
 var validOutboundStatuses = []string{"pending", "sent", "failed"}
@@ -324,25 +998,22 @@ func (h *AdminHandler) ListOutboundMessages(w http.ResponseWriter, r *http.Reque
 	status := r.URL.Query().Get("status")
 
 	if accountID != "" && !util.IsValidUUID(accountID) {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid accountId format"})
+		writeAppError(w, r, apperrors.InvalidInput("accountId", "must be a UUID"))
 		return
 	}
 	if !util.IsValidEnum(status, validOutboundStatuses) {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid status value"})
+		writeAppError(w, r, invalidEnum("status", validOutboundStatuses))
 		return
 	}
 
 	messages, total, err := h.adminService.GetOutboundMessages(r.Context(), p.Limit, p.Offset, accountID, status)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list outbound messages")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items": messages,
-		"total": total,
-	})
+	writePage(w, messages, total, p)
 }
 
 // Users
@@ -353,14 +1024,11 @@ func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	users, total, err := h.adminService.GetUsers(r.Context(), p.Limit, p.Offset)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list users")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items": users,
-		"total": total,
-	})
+	writePage(w, users, total, p)
 }
 
 func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
@@ -369,12 +1037,12 @@ func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	user, err := h.adminService.GetUserByID(r.Context(), id)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get user")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
 	if user == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "User not found"})
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIUserNotFound)
 		return
 	}
 
@@ -388,19 +1056,19 @@ func (h *AdminHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		IsActive *bool `json:"isActive"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
 		return
 	}
 
 	user, err := h.adminService.UpdateUser(r.Context(), id, req.IsActive)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to update user")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
 	if user == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "User not found"})
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIUserNotFound)
 		return
 	}
 
@@ -412,7 +1080,7 @@ func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.adminService.DeleteUser(r.Context(), id); err != nil {
 		log.Error().Err(err).Msg("failed to delete user")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -427,6 +1095,97 @@ func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
+// Admin Sessions
+
+// Reauthenticate confirms the admin password for step-up protected actions.
+func (h *AdminHandler) Reauthenticate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "password")
+		return
+	}
+
+	session := middleware.GetAdminSession(r.Context())
+	if err := h.adminService.Reauthenticate(r.Context(), session.ID, req.Password); err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeUnauthorized {
+			audit.LogFromRequest(r, audit.Event{
+				Type: audit.EventAuthFailure,
+				Details: map[string]interface{}{
+					"reason": "invalid_password",
+					"target": "admin_reauth",
+				},
+			})
+			// 403 rather than 401 so the admin UI does not treat it as an expired session.
+			writeError(w, r, http.StatusForbidden, apperrors.ErrCodeForbidden, i18n.APIInvalidPassword)
+			return
+		}
+		log.Error().Err(err).Msg("admin re-authentication failed")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIReauthFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+type adminSessionResponse struct {
+	model.AdminSession
+	Current bool `json:"current"`
+}
+
+func adminClient(r *http.Request) service.AdminClient {
+	return service.AdminClient{
+		IPAddress: httputil.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+func (h *AdminHandler) ListAdminSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.adminService.ListAdminSessions(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list admin sessions")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	var currentID string
+	if current := middleware.GetAdminSession(r.Context()); current != nil {
+		currentID = current.ID
+	}
+
+	items := make([]adminSessionResponse, len(sessions))
+	for i, session := range sessions {
+		items[i] = adminSessionResponse{AdminSession: session, Current: session.ID == currentID}
+	}
+
+	// Admin sessions are few and listed in full.
+	writePage(w, items, len(items), PaginationParams{Limit: len(items)})
+}
+
+func (h *AdminHandler) RevokeAdminSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.adminService.RevokeAdminSession(r.Context(), id); err != nil {
+		log.Error().Err(err).Msg("failed to revoke admin session")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventSessionRevoke,
+		Details: map[string]interface{}{
+			"target":     "admin",
+			"session_id": id,
+		},
+	})
+
+	if current := middleware.GetAdminSession(r.Context()); current != nil && current.ID == id {
+		middleware.ClearSessionCookie(w, middleware.AdminSessionCookie, "/admin")
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
 // Sessions (Plugin Sessions)
 
 var validSessionStatuses = []string{"pending_pairing", "paired", "expired", "disconnected"}
@@ -436,21 +1195,18 @@ func (h *AdminHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Query().Get("status")
 
 	if !util.IsValidEnum(status, validSessionStatuses) {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid status value"})
+		writeAppError(w, r, invalidEnum("status", validSessionStatuses))
 		return
 	}
 
 	sessions, total, err := h.adminService.GetSessions(r.Context(), p.Limit, p.Offset, status)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list sessions")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"items": sessions,
-		"total": total,
-	})
+	writePage(w, sessions, total, p)
 }
 
 func (h *AdminHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
@@ -458,7 +1214,7 @@ func (h *AdminHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.adminService.DeleteSession(r.Context(), id); err != nil {
 		log.Error().Err(err).Msg("failed to delete session")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -470,9 +1226,314 @@ func (h *AdminHandler) DisconnectSession(w http.ResponseWriter, r *http.Request)
 
 	if err := h.adminService.DisconnectSession(r.Context(), id); err != nil {
 		log.Error().Err(err).Msg("failed to disconnect session")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
+
+// Pairing
+
+func (h *AdminHandler) ListPairingCodes(w http.ResponseWriter, r *http.Request) {
+	p := ParsePagination(r)
+
+	codes, total, err := h.pairingService.ListAllActiveCodes(r.Context(), p.Limit, p.Offset)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list pairing codes")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writePage(w, codes, total, p)
+}
+
+func (h *AdminHandler) RevokePairingCode(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	if err := h.pairingService.RevokeAnyCode(r.Context(), code); err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIPairingCodeNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to revoke pairing code")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventCodeRevoke,
+		Details: map[string]interface{}{
+			"code":       code,
+			"revoked_by": "admin",
+		},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+func (h *AdminHandler) ListPendingSessions(w http.ResponseWriter, r *http.Request) {
+	p := ParsePagination(r)
+
+	sessions, total, err := h.adminService.GetSessions(r.Context(), p.Limit, p.Offset, string(model.SessionStatusPendingPairing))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list pending sessions")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writePage(w, sessions, total, p)
+}
+
+func (h *AdminHandler) RevokePendingSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.sessionService.RevokePendingSession(r.Context(), id); err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIPendingSessionNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to revoke pending session")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventSessionRevoke,
+		Details: map[string]interface{}{
+			"session_id": id,
+			"revoked_by": "admin",
+		},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+func (h *AdminHandler) ForcePairSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		ConversationKey string `json:"conversationKey"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.ConversationKey) == "" {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "conversationKey")
+		return
+	}
+	conversationKey := strings.TrimSpace(req.ConversationKey)
+
+	session, err := h.sessionService.ForcePair(r.Context(), id, conversationKey)
+	if err != nil {
+		appErr, ok := apperrors.AsAppError(err)
+		if !ok {
+			log.Error().Err(err).Msg("failed to force pair session")
+			writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+			return
+		}
+		switch appErr.Code {
+		case apperrors.ErrCodeNotFound:
+			writeAppError(w, r, appErr)
+		case apperrors.ErrCodeAlreadyPaired:
+			writeAppError(w, r, appErr)
+		case apperrors.ErrCodePairingExpired, apperrors.ErrCodeInvalidInput:
+			writeAppError(w, r, appErr)
+		default:
+			log.Error().Err(err).Msg("failed to force pair session")
+			writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		}
+		return
+	}
+
+	accountID := ""
+	if session.AccountID != nil {
+		accountID = *session.AccountID
+	}
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventForcePair,
+		AccountID: accountID,
+		Details: map[string]interface{}{
+			"session_id":       id,
+			"conversation_key": conversationKey,
+			"paired_by":        "admin",
+		},
+	})
+
+	writeJSON(w, http.StatusOK, session)
+}
+
+func (h *AdminHandler) PluginVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := h.adminService.GetPluginVersions(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get plugin versions")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"items": versions})
+}
+
+func (h *AdminHandler) CheckIntegrity(w http.ResponseWriter, r *http.Request) {
+	h.runIntegrity(w, r, false)
+}
+
+func (h *AdminHandler) RepairIntegrity(w http.ResponseWriter, r *http.Request) {
+	h.runIntegrity(w, r, true)
+}
+
+func (h *AdminHandler) runIntegrity(w http.ResponseWriter, r *http.Request, repair bool) {
+	report, err := h.integrityService.Run(r.Context(), repair)
+	if err != nil {
+		log.Error().Err(err).Bool("repair", repair).Msg("failed to run integrity check")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// EraseKakaoUser handles a deletion request for a Kakao user, forwarded by a
+// channel operator. ?dryRun=true reports what would be erased.
+func (h *AdminHandler) EraseKakaoUser(w http.ResponseWriter, r *http.Request) {
+	userKey := chi.URLParam(r, "userKey")
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	mode := model.ErasureMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = model.ErasureModeDelete
+	}
+
+	result, err := h.erasureService.EraseKakaoUser(r.Context(), userKey, mode, dryRun)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		log.Error().Err(err).Msg("failed to erase kakao user")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	if !dryRun {
+		// The user key itself is what was erased, so only its hash is kept
+		audit.LogFromRequest(r, audit.Event{
+			Type: audit.EventKakaoUserErase,
+			Details: map[string]interface{}{
+				"user_key_hash": util.HashToken(userKey),
+				"mode":          mode,
+				"conversations": result.Conversations,
+				"accounts":      result.Accounts,
+				"inbound":       result.InboundMessages,
+				"outbound":      result.OutboundMessages,
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Legal hold
+
+func (h *AdminHandler) SetAccountLegalHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	account, err := h.adminService.SetAccountLegalHold(r.Context(), id, req.Reason)
+	if err != nil {
+		h.writeLegalHoldError(w, r, err, "failed to set account legal hold")
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventLegalHoldSet,
+		AccountID: id,
+		Details: map[string]interface{}{
+			"reason": *account.LegalHoldReason,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, account)
+}
+
+func (h *AdminHandler) ReleaseAccountLegalHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	account, err := h.adminService.ReleaseAccountLegalHold(r.Context(), id)
+	if err != nil {
+		h.writeLegalHoldError(w, r, err, "failed to release account legal hold")
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventLegalHoldRelease,
+		AccountID: id,
+	})
+
+	writeJSON(w, http.StatusOK, account)
+}
+
+func (h *AdminHandler) SetMappingLegalHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	conv, err := h.adminService.SetMappingLegalHold(r.Context(), id, req.Reason)
+	if err != nil {
+		h.writeLegalHoldError(w, r, err, "failed to set conversation legal hold")
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventLegalHoldSet,
+		Details: map[string]interface{}{
+			"mapping_id": id,
+			"reason":     *conv.LegalHoldReason,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, conv)
+}
+
+func (h *AdminHandler) ReleaseMappingLegalHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	conv, err := h.adminService.ReleaseMappingLegalHold(r.Context(), id)
+	if err != nil {
+		h.writeLegalHoldError(w, r, err, "failed to release conversation legal hold")
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type: audit.EventLegalHoldRelease,
+		Details: map[string]interface{}{
+			"mapping_id": id,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, conv)
+}
+
+func (h *AdminHandler) writeLegalHoldError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		switch appErr.Code {
+		case apperrors.ErrCodeInvalidInput:
+			writeAppError(w, r, appErr)
+			return
+		case apperrors.ErrCodeNotFound:
+			writeAppError(w, r, appErr)
+			return
+		}
+	}
+	log.Error().Err(err).Msg(msg)
+	writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+}
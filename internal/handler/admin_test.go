@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+func TestParseAccountFilter(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?mode=direct&hasPortalUser=true", nil)
+	filter, err := parseAccountFilter(r)
+	require.NoError(t, err)
+	assert.Equal(t, model.AccountModeDirect, filter.Mode)
+	require.NotNil(t, filter.HasPortalUser)
+	assert.True(t, *filter.HasPortalUser)
+	assert.Nil(t, filter.Suspended)
+
+	_, err = parseAccountFilter(httptest.NewRequest("GET", "/?mode=other", nil))
+	assert.Error(t, err)
+}
+
+func TestParseMappingFilter(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?state=paired&channel=_abc&lastSeenBefore=2026-03-01T00:00:00Z", nil)
+	filter, err := parseMappingFilter(r)
+	require.NoError(t, err)
+	assert.Equal(t, model.PairingStatePaired, filter.State)
+	assert.Equal(t, "_abc", filter.KakaoChannelID)
+	assert.Nil(t, filter.LastSeenAfter)
+	require.NotNil(t, filter.LastSeenBefore)
+
+	for _, query := range []string{"state=gone", "accountId=not-a-uuid", "lastSeenAfter=today"} {
+		_, err := parseMappingFilter(httptest.NewRequest("GET", "/?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestParseChangeHistoryFilter(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?entityType=conversation_state&entityKey=_abc:user&since=2026-03-01T00:00:00Z", nil)
+	filter, err := parseChangeHistoryFilter(r)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeEntityConversationState, filter.EntityType)
+	assert.Equal(t, "_abc:user", filter.EntityKey)
+	require.NotNil(t, filter.Since)
+	assert.Nil(t, filter.Until)
+
+	for _, query := range []string{"entityType=account", "accountId=not-a-uuid", "until=today"} {
+		_, err := parseChangeHistoryFilter(httptest.NewRequest("GET", "/?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestExpireInboundMessages_Validation(t *testing.T) {
+	h := &AdminHandler{adminService: &service.AdminService{}}
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"no filter", `{}`, "filter"},
+		{"invalid account", `{"accountId":"acc-1"}`, "accountId"},
+		{"invalid duration", `{"accountId":"6f1c9c5e-8f7a-4d4e-9d2a-0b8f3c1e2a4b","olderThan":"yesterday"}`, "olderThan"},
+		{"negative duration", `{"conversationKey":"ch:user","olderThan":"-5m"}`, "olderThan"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/messages/inbound/expire", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			h.ExpireInboundMessages(rec, r)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			body := decodeError(t, rec)
+			assert.Equal(t, "INVALID_INPUT", body["code"])
+			assert.Equal(t, tc.field, body["details"].(map[string]any)["field"])
+		})
+	}
+}
+
+func TestParseNotificationFilter(t *testing.T) {
+	filter, err := parseNotificationFilter(httptest.NewRequest("GET", "/?kind=job_failure&unread=true", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "job_failure", filter.Kind)
+	assert.True(t, filter.UnreadOnly)
+
+	filter, err = parseNotificationFilter(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, model.AdminNotificationFilter{}, filter)
+
+	for _, query := range []string{"kind=outage", "unread=maybe"} {
+		_, err := parseNotificationFilter(httptest.NewRequest("GET", "/?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestAdminRoutesRequireStepUp(t *testing.T) {
+	// A session whose password entry is older than the step-up window
+	staleSession := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session := &model.AdminSession{AuthenticatedAt: time.Now().Add(-time.Hour)}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.AdminSessionContextKey, session)))
+		})
+	}
+	h := NewAdminHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		service.NewDeploymentService(nil, "", 0), nil, staleSession, middleware.NewLoginRateLimiter(nil), false)
+	router := h.Routes()
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodDelete, "/api/accounts/acc-1"},
+		{http.MethodPost, "/api/accounts/acc-1/regenerate-token"},
+		{http.MethodPut, "/api/accounts/acc-1/config"},
+		{http.MethodDelete, "/api/mappings/map-1"},
+		{http.MethodDelete, "/api/users/user-1"},
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(route.method, route.path, strings.NewReader("{}")))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code, route.method+" "+route.path)
+		assert.Contains(t, rec.Body.String(), "REAUTH_REQUIRED", route.method+" "+route.path)
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// replyTemplateTypes are the Kakao skill response outputs the relay forwards
+// in POST /openclaw/reply.
+var replyTemplateTypes = []string{
+	"simpleText",
+	"simpleImage",
+	"textCard",
+	"basicCard",
+	"commerceCard",
+	"listCard",
+	"itemCard",
+	"carousel",
+}
+
+type Capabilities struct {
+	APIVersion               httputil.APIVersion       `json:"apiVersion"`
+	SupportedAPIVersions     []httputil.APIVersion     `json:"supportedApiVersions"`
+	Transports               []string                  `json:"transports"`
+	MaxRequestBodyBytes      int64                     `json:"maxRequestBodyBytes"`
+	CallbackTTLSeconds       int                       `json:"callbackTtlSeconds"`
+	HeartbeatIntervalSeconds int                       `json:"heartbeatIntervalSeconds"`
+	HeartbeatMinSeconds      int                       `json:"heartbeatMinSeconds"`
+	HeartbeatMaxSeconds      int                       `json:"heartbeatMaxSeconds"`
+	ReplyTemplateTypes       []string                  `json:"replyTemplateTypes"`
+	Plugin                   PluginVersionCapabilities `json:"plugin"`
+	// Set when v2 events carry a signature
+	EventSigning *EventSigningCapabilities `json:"eventSigning,omitempty"`
+	// Port of the gRPC API on the server's host; set when transports
+	// include grpc
+	GRPCPort int `json:"grpcPort,omitempty"`
+}
+
+type EventSigningCapabilities struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	JWKSPath  string `json:"jwksPath"`
+}
+
+type PluginVersionCapabilities struct {
+	VersionHeader      string `json:"versionHeader"`
+	MinimumVersion     string `json:"minimumVersion,omitempty"`
+	RecommendedVersion string `json:"recommendedVersion,omitempty"`
+}
+
+type CapabilitiesHandler struct {
+	capabilities Capabilities
+}
+
+func NewCapabilitiesHandler(
+	callbackTTL time.Duration,
+	maxBodySize int64,
+	pluginCompat *config.PluginCompatibility,
+	signer *sse.Signer,
+	grpcPort int,
+) *CapabilitiesHandler {
+	// Check on an empty version only to read the configured bounds.
+	compat := pluginCompat.Check("")
+
+	var signing *EventSigningCapabilities
+	if signer != nil {
+		signing = &EventSigningCapabilities{
+			Algorithm: sse.SignatureAlgorithm,
+			KeyID:     signer.KeyID(),
+			JWKSPath:  JWKSPath,
+		}
+	}
+
+	transports := []string{"sse", "websocket"}
+	if grpcPort != 0 {
+		transports = append(transports, "grpc")
+	}
+
+	return &CapabilitiesHandler{
+		capabilities: Capabilities{
+			SupportedAPIVersions:     httputil.SupportedAPIVersions,
+			Transports:               transports,
+			MaxRequestBodyBytes:      maxBodySize,
+			CallbackTTLSeconds:       int(callbackTTL.Seconds()),
+			HeartbeatIntervalSeconds: int(sse.HeartbeatInterval.Seconds()),
+			HeartbeatMinSeconds:      int(sse.MinHeartbeatInterval.Seconds()),
+			HeartbeatMaxSeconds:      int(sse.MaxHeartbeatInterval.Seconds()),
+			ReplyTemplateTypes:       replyTemplateTypes,
+			Plugin: PluginVersionCapabilities{
+				VersionHeader:      middleware.PluginVersionHeader,
+				MinimumVersion:     compat.MinimumVersion,
+				RecommendedVersion: compat.RecommendedVersion,
+			},
+			EventSigning: signing,
+			GRPCPort:     grpcPort,
+		},
+	}
+}
+
+// GET /v1/capabilities, GET /v2/capabilities
+// Public: lets plugins discover what this deployment supports.
+func (h *CapabilitiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	capabilities := h.capabilities
+	capabilities.APIVersion = httputil.APIVersionFrom(r.Context())
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	httputil.Respond(w, r, http.StatusOK, capabilities)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+)
+
+func TestCapabilitiesHandler(t *testing.T) {
+	cfg := &config.Config{PluginMinVersion: "1.0.0", PluginRecommendedVersion: "1.3.0"}
+	compat, err := cfg.PluginCompatibility()
+	require.NoError(t, err)
+
+	h := NewCapabilitiesHandler(55*time.Second, 1<<20, compat, nil, 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/capabilities", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got Capabilities
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, httputil.APIVersionV1, got.APIVersion)
+	assert.Equal(t, []string{"sse", "websocket"}, got.Transports)
+	assert.Equal(t, int64(1<<20), got.MaxRequestBodyBytes)
+	assert.Equal(t, 55, got.CallbackTTLSeconds)
+	assert.Contains(t, got.ReplyTemplateTypes, "simpleText")
+	assert.Equal(t, "1.0.0", got.Plugin.MinimumVersion)
+	assert.Equal(t, "1.3.0", got.Plugin.RecommendedVersion)
+	assert.Zero(t, got.GRPCPort)
+}
+
+func TestCapabilitiesHandler_GRPC(t *testing.T) {
+	compat, err := (&config.Config{}).PluginCompatibility()
+	require.NoError(t, err)
+
+	h := NewCapabilitiesHandler(55*time.Second, 1<<20, compat, nil, 9090)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/capabilities", nil))
+
+	var got Capabilities
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, []string{"sse", "websocket", "grpc"}, got.Transports)
+	assert.Equal(t, 9090, got.GRPCPort)
+}
+
+func TestCapabilitiesHandler_V2Envelope(t *testing.T) {
+	compat, err := (&config.Config{}).PluginCompatibility()
+	require.NoError(t, err)
+
+	h := NewCapabilitiesHandler(55*time.Second, 1<<20, compat, nil, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/capabilities", nil)
+	req = req.WithContext(httputil.WithAPIVersion(req.Context(), httputil.APIVersionV2))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var got struct {
+		Data Capabilities `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, httputil.APIVersionV2, got.Data.APIVersion)
+	assert.Equal(t, httputil.SupportedAPIVersions, got.Data.SupportedAPIVersions)
+}
@@ -2,149 +2,459 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/openclaw/relay-server-go/internal/config"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
 	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/service"
 	"github.com/openclaw/relay-server-go/internal/sse"
 )
 
+// ConnectedEvent is the first event of every stream.
+type ConnectedEvent struct {
+	AccountID                string `json:"accountId"`
+	SessionID                string `json:"sessionId"`
+	Status                   string `json:"status"`
+	HeartbeatIntervalSeconds int    `json:"heartbeatIntervalSeconds"`
+	// Set when the stream only receives the conversations routed to it
+	Route *service.EventRoute `json:"route,omitempty"`
+}
+
+// routeLeaveTimeout bounds handing a closed stream's conversations over to
+// the remaining sessions.
+const routeLeaveTimeout = 10 * time.Second
+
 type EventsHandler struct {
-	broker         *sse.Broker
+	broker         sse.EventBus
+	history        sse.EventHistory
 	messageService *service.MessageService
+	signer         *sse.Signer
+	router         *service.EventRouter
+	setup          *service.SetupChecklistService
+	events         *service.SessionEvents
 }
 
-func NewEventsHandler(broker *sse.Broker, messageService *service.MessageService) *EventsHandler {
+// NewEventsHandler creates the SSE handler. history may be nil when event
+// history is disabled, signer nil when v2 events are not signed, router nil
+// when conversations are not routed between sessions, and setup nil when
+// connections are not recorded for the setup checklist.
+func NewEventsHandler(broker sse.EventBus, history sse.EventHistory, messageService *service.MessageService, signer *sse.Signer, router *service.EventRouter, setup *service.SetupChecklistService) *EventsHandler {
 	return &EventsHandler{
 		broker:         broker,
+		history:        history,
 		messageService: messageService,
+		signer:         signer,
+		router:         router,
+		setup:          setup,
+		events:         service.NewSessionEvents(broker),
 	}
 }
 
-func (h *EventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+// EventHistoryResponse lists events oldest first. With hasMore, request the
+// next page with since set to the last event's ID.
+type EventHistoryResponse struct {
+	Events  []sse.Envelope `json:"events"`
+	HasMore bool           `json:"hasMore"`
+}
+
+// GET /v1/events/history?since=<event ID, sequence number or RFC 3339 time>&limit=
+// Returns recently published events so a plugin that was offline can catch
+// up. Without since, the whole retained history is returned. v2 also accepts
+// the nextCursor of the previous page as cursor.
+func (h *EventsHandler) History(w http.ResponseWriter, r *http.Request) {
 	account := middleware.GetAccount(r.Context())
-	session := middleware.GetSession(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.Unauthorized("Unauthorized"))
+		return
+	}
+	if h.history == nil {
+		httputil.RespondError(w, r, apperrors.New(apperrors.ErrCodeUnavailable, "Event history is disabled"))
+		return
+	}
 
-	// Determine subscription ID
-	var subscribeID string
-	var accountID string
+	cursor, err := parseHistoryCursor(r.URL.Query().Get("since"))
+	if err == nil && httputil.APIVersionFrom(r.Context()) != httputil.APIVersionV1 && r.URL.Query().Has("cursor") {
+		var after *model.Cursor
+		if after, err = httputil.DecodeCursor(r.URL.Query().Get("cursor")); after != nil {
+			cursor = sse.HistoryCursor{EventID: after.ID}
+		}
+	}
+	if err != nil {
+		httputil.RespondError(w, r, err)
+		return
+	}
 
-	if account != nil {
-		// Paired session or legacy account token
-		subscribeID = account.ID
-		accountID = account.ID
-	} else if session != nil {
-		// Pending session - subscribe by session ID for pairing events
-		subscribeID = "session:" + session.ID
-		accountID = ""
-	} else {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	events, hasMore, err := h.history.Since(r.Context(), account.ID, cursor, ParsePagination(r).Limit)
+	if errors.Is(err, sse.ErrHistoryCursorExpired) {
+		httputil.RespondError(w, r, apperrors.HistoryExpired())
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("accountId", account.ID).Msg("failed to read event history")
+		httputil.RespondError(w, r, apperrors.Internal("Failed to read event history"))
 		return
 	}
 
-	flusher, ok := w.(http.Flusher)
+	envelopes := make([]sse.Envelope, len(events))
+	for i, event := range events {
+		envelopes[i] = event.Envelope()
+	}
+	meta := &httputil.PageMeta{HasMore: hasMore}
+	if hasMore && len(events) > 0 {
+		last := events[len(events)-1]
+		meta.NextCursor = httputil.EncodeCursor(model.Cursor{At: last.OccurredAt, ID: last.ID})
+	}
+	httputil.RespondWithMeta(w, r, http.StatusOK, EventHistoryResponse{Events: envelopes, HasMore: hasMore}, meta)
+}
+
+func parseHistoryCursor(since string) (sse.HistoryCursor, error) {
+	if since == "" {
+		return sse.HistoryCursor{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return sse.HistoryCursor{Time: t}, nil
+	}
+	if seq, err := strconv.ParseInt(since, 10, 64); err == nil && seq > 0 {
+		return sse.HistoryCursor{Seq: seq}, nil
+	}
+	if strings.HasPrefix(since, "evt_") {
+		return sse.HistoryCursor{EventID: since}, nil
+	}
+	return sse.HistoryCursor{}, apperrors.InvalidInput("since", "must be an event ID, a sequence number or an RFC 3339 time")
+}
+
+func (h *EventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := w.(http.Flusher); !ok {
+		httputil.RespondLegacyError(w, r, http.StatusInternalServerError, apperrors.Internal("Streaming not supported"))
+		return
+	}
+
+	sub, ok := h.subscribe(w, r, "sse")
 	if !ok {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Streaming not supported"})
 		return
 	}
+	defer h.unsubscribe(r.Context(), sub)
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	client := h.broker.Subscribe(subscribeID)
+	stream := newEventStream(w, httputil.APIVersionFrom(r.Context()) != httputil.APIVersionV1, sub.accountID)
+	if stream.envelope {
+		stream.signer = h.signer
+	}
+	h.stream(r.Context(), r, sub, stream)
+}
+
+// EventSink receives the events of a stream served over a transport outside
+// this package, such as gRPC.
+type EventSink interface {
+	Send(event sse.Event) error
+	Heartbeat(now time.Time) error
+}
+
+// ServeEvents streams the events of r's session like ServeHTTP does, but to
+// the sink newSink returns once the subscription succeeded. Otherwise the
+// error response is written to w. It returns when r's context is done or
+// the broker drops the stream.
+func (h *EventsHandler) ServeEvents(w http.ResponseWriter, r *http.Request, transport string, newSink func(accountID string) EventSink) {
+	sub, ok := h.subscribe(w, r, transport)
+	if !ok {
+		return
+	}
+	defer h.unsubscribe(r.Context(), sub)
+
+	h.stream(r.Context(), r, sub, &externalSink{sink: newSink(sub.accountID), accountID: sub.accountID})
+}
+
+// subscription is what one stream connection receives events for.
+type subscription struct {
+	transport   string
+	session     *model.Session
+	subscribeID string
+	// Empty for pending sessions, which only receive pairing events
+	accountID string
+	// Set when the stream only receives the conversations routed to it
+	route *service.EventRoute
+	conn  string
+}
+
+// subscribe resolves what the request subscribes to and joins its event
+// route. On failure the error response is written and ok is false; on
+// success the caller must call unsubscribe once the stream ends.
+func (h *EventsHandler) subscribe(w http.ResponseWriter, r *http.Request, transport string) (*subscription, bool) {
+	account := middleware.GetAccount(r.Context())
+	session := middleware.GetSession(r.Context())
+
+	sub := &subscription{transport: transport, session: session, conn: rand.Text()}
+	if account != nil {
+		// Paired session or legacy account token
+		sub.subscribeID = account.ID
+		sub.accountID = account.ID
+	} else if session != nil {
+		// Pending session - subscribe by session ID for pairing events
+		sub.subscribeID = "session:" + session.ID
+	} else {
+		httputil.RespondLegacyError(w, r, http.StatusUnauthorized, apperrors.Unauthorized("Unauthorized"))
+		return nil, false
+	}
+
+	// A plugin session running next to others of the account can ask for
+	// a share of the conversations instead of all of them
+	spec := r.URL.Query().Get("route")
+	if spec == "" {
+		return sub, true
+	}
+	if account == nil || session == nil {
+		httputil.RespondLegacyError(w, r, http.StatusBadRequest, apperrors.InvalidInput("route", "requires a paired plugin session"))
+		return nil, false
+	}
+	if h.router == nil {
+		httputil.RespondLegacyError(w, r, http.StatusServiceUnavailable, apperrors.New(apperrors.ErrCodeUnavailable, "Event routing is disabled"))
+		return nil, false
+	}
+	route, err := service.ParseEventRoute(spec)
+	if err != nil {
+		httputil.RespondLegacyError(w, r, http.StatusBadRequest, apperrors.InvalidInput("route", err.Error()))
+		return nil, false
+	}
+	if err := h.router.Join(r.Context(), sub.accountID, session.ID, sub.conn, route); err != nil {
+		log.Error().Err(err).Str("sessionId", session.ID).Msg("failed to join event routes")
+		httputil.RespondLegacyError(w, r, http.StatusServiceUnavailable, apperrors.New(apperrors.ErrCodeUnavailable, "Event routing is unavailable"))
+		return nil, false
+	}
+	sub.route = &route
+	return sub, true
+}
+
+func (h *EventsHandler) unsubscribe(ctx context.Context, sub *subscription) {
+	if sub.route != nil {
+		h.leaveRoute(ctx, sub.accountID, sub.session.ID, sub.conn)
+	}
+}
+
+// stream sends the subscription's events to sink until ctx is done or the
+// broker drops the connection: first the queued messages, then connected,
+// then live events with heartbeats in between.
+func (h *EventsHandler) stream(ctx context.Context, r *http.Request, sub *subscription, sink eventSink) {
+	var client *sse.Client
+	routedSession := ""
+	if sub.route != nil {
+		routedSession = sub.session.ID
+		client = h.broker.SubscribeRouted(sub.subscribeID, routedSession)
+	} else {
+		client = h.broker.Subscribe(sub.subscribeID)
+	}
 	defer h.broker.Unsubscribe(client)
 
 	log.Info().
-		Str("subscribeId", subscribeID).
-		Str("accountId", accountID).
-		Msg("sse connection established")
+		Str("subscribeId", sub.subscribeID).
+		Str("accountId", sub.accountID).
+		Str("transport", sub.transport).
+		Msg("event stream connection established")
 
-	ctx := r.Context()
+	heartbeatInterval := requestedHeartbeat(r)
+
+	// Events missed since the client's last one come first; they are
+	// read after subscribing, so live events may repeat some of them
+	resumed, err := h.resume(ctx, r, sub, sink, routedSession)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resume event stream")
+		return
+	}
 
 	// Send queued messages only if we have an account
-	if accountID != "" {
-		if err := h.sendQueuedMessages(ctx, w, flusher, accountID); err != nil {
+	if sub.accountID != "" {
+		h.setup.Record(ctx, sub.accountID, model.SetupStepSessionCreated)
+		if err := h.sendQueuedMessages(ctx, sink, sub.accountID, routedSession, resumed.messages); err != nil {
 			log.Error().Err(err).Msg("failed to send queued messages")
 		}
 	}
 
-	h.sendEvent(w, flusher, "connected", map[string]any{
-		"accountId": accountID,
-		"sessionId": func() string {
-			if session != nil {
-				return session.ID
-			}
-			return ""
-		}(),
-		"status": func() string {
-			if session != nil {
-				return string(session.Status)
-			}
-			return "paired"
-		}(),
-	})
+	connected := ConnectedEvent{
+		AccountID:                sub.accountID,
+		Status:                   string(model.SessionStatusPaired),
+		HeartbeatIntervalSeconds: int(heartbeatInterval.Seconds()),
+		Route:                    sub.route,
+	}
+	if sub.session != nil {
+		connected.SessionID = sub.session.ID
+		connected.Status = string(sub.session.Status)
+	}
+	sink.sendData("connected", connected)
 
-	heartbeat := time.NewTicker(sse.HeartbeatInterval)
+	if compat := middleware.GetPluginCompat(ctx); compat != nil && compat.Status == config.PluginCompatOutdated {
+		sink.sendData(service.EventUpgradeRequired, compat)
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
 	defer heartbeat.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info().
-				Str("subscribeId", subscribeID).
-				Msg("sse connection closed by client")
+				Str("subscribeId", sub.subscribeID).
+				Str("transport", sub.transport).
+				Msg("event stream connection closed by client")
 			return
 
 		case <-client.Done:
 			log.Info().
-				Str("subscribeId", subscribeID).
-				Msg("sse connection closed by broker")
+				Str("subscribeId", sub.subscribeID).
+				Str("transport", sub.transport).
+				Msg("event stream connection closed by broker")
+			return
+
+		case <-client.Overflow:
+			log.Warn().
+				Str("subscribeId", sub.subscribeID).
+				Str("transport", sub.transport).
+				Str("resumeCursor", sink.resumeCursor()).
+				Msg("event stream client fell behind, closing connection")
+			sink.sendData(sse.EventEventsDropped, sse.EventsDroppedEvent{
+				Dropped:      client.TakeDropped(),
+				Policy:       sse.BackpressureDisconnect,
+				ResumeCursor: sink.resumeCursor(),
+			})
 			return
 
 		case event := <-client.Events:
-			if err := h.sendRawEvent(w, flusher, event); err != nil {
+			if event.Seq > 0 && event.Seq <= resumed.seq {
+				continue
+			}
+			if dropped := client.TakeDropped(); dropped > 0 {
+				if err := sink.sendData(sse.EventEventsDropped, sse.EventsDroppedEvent{
+					Dropped: dropped,
+					Policy:  sse.BackpressureDropOldest,
+				}); err != nil {
+					log.Error().Err(err).Msg("failed to send event")
+					return
+				}
+			}
+			if err := sink.send(event); err != nil {
 				log.Error().Err(err).Msg("failed to send event")
 				return
 			}
+			if event.Type == "message" && service.IsTestConversation(event.ConversationKey) {
+				h.markTestMessageDelivered(ctx, event)
+			}
 
-		case <-heartbeat.C:
-			if _, err := fmt.Fprintf(w, ": ping\n\n"); err != nil {
+		case now := <-heartbeat.C:
+			if err := sink.heartbeat(now); err != nil {
 				log.Debug().
-					Str("subscribeId", subscribeID).
+					Err(err).
+					Str("subscribeId", sub.subscribeID).
+					Str("transport", sub.transport).
 					Msg("heartbeat failed, closing connection")
 				return
 			}
-			flusher.Flush()
+			if sub.route != nil {
+				if err := h.router.Join(ctx, sub.accountID, routedSession, sub.conn, *sub.route); err != nil {
+					log.Warn().Err(err).Str("sessionId", routedSession).Msg("failed to refresh event route")
+				}
+			}
 		}
 	}
 }
 
-func (h *EventsHandler) sendQueuedMessages(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, accountID string) error {
+// resumedEvents records what resume sent: the last sequence number and the
+// IDs of the messages.
+type resumedEvents struct {
+	seq      int64
+	messages map[string]bool
+}
+
+// resume sends the events published to the subscription after the client's
+// Last-Event-ID (header, or lastEventId query parameter for clients that
+// cannot set it) from the event history. When they are no longer kept,
+// resume_expired is sent instead. Only a failed write is returned.
+func (h *EventsHandler) resume(ctx context.Context, r *http.Request, sub *subscription, sink eventSink, routedSession string) (resumedEvents, error) {
+	resumed := resumedEvents{messages: map[string]bool{}}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	if lastEventID == "" || h.history == nil {
+		return resumed, nil
+	}
+	seq, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || seq <= 0 {
+		log.Debug().Str("lastEventId", lastEventID).Msg("ignoring invalid last event ID")
+		return resumed, nil
+	}
+
+	events, _, err := h.history.Since(ctx, sub.subscribeID, sse.HistoryCursor{Seq: seq}, 0)
+	if errors.Is(err, sse.ErrHistoryCursorExpired) {
+		return resumed, sink.sendData(sse.EventResumeExpired, sse.ResumeExpiredEvent{LastEventID: lastEventID})
+	}
+	if err != nil {
+		// The client still gets the queued messages and live events
+		log.Warn().Err(err).Str("subscribeId", sub.subscribeID).Msg("failed to read event history for resume")
+		return resumed, nil
+	}
+
+	for _, event := range events {
+		resumed.seq = event.Seq
+		if routedSession != "" && event.Session != "" && event.Session != routedSession {
+			continue
+		}
+		if err := sink.send(event); err != nil {
+			return resumed, err
+		}
+		if event.Type == "message" {
+			if id := eventMessageID(event); id != "" {
+				resumed.messages[id] = true
+			}
+		}
+	}
+
+	if len(events) > 0 {
+		log.Info().
+			Str("subscribeId", sub.subscribeID).
+			Int64("lastEventId", seq).
+			Int("count", len(events)).
+			Msg("resumed event stream")
+	}
+	return resumed, nil
+}
+
+// sendQueuedMessages replays the account's queued messages except those in
+// skip, which were already sent; a routed stream only gets those of the
+// conversations routed to routedSession.
+func (h *EventsHandler) sendQueuedMessages(ctx context.Context, sink eventSink, accountID, routedSession string, skip map[string]bool) error {
 	messages, err := h.messageService.FindQueuedByAccountID(ctx, accountID)
 	if err != nil {
 		return err
 	}
 
 	for _, msg := range messages {
-		sseData := msg.ToSSEEventData()
+		if skip[msg.ID] {
+			continue
+		}
+		if routedSession != "" && h.router.Route(ctx, accountID, msg.ConversationKey) != routedSession {
+			continue
+		}
+		sseData := msg.ToSSEEventData(nil)
 		log.Debug().
 			Str("messageId", msg.ID).
 			RawJSON("sseEventData", sseData).
 			Msg("sending queued sse message event")
 
-		event := sse.Event{
-			Type: "message",
-			Data: sseData,
-		}
-
-		if err := h.sendRawEvent(w, flusher, event); err != nil {
+		event := sse.NewRawEvent("message", msg.AccountID, msg.ConversationKey, sseData)
+		if err := sink.send(event); err != nil {
 			return err
 		}
 
@@ -163,22 +473,199 @@ func (h *EventsHandler) sendQueuedMessages(ctx context.Context, w http.ResponseW
 	return nil
 }
 
-func (h *EventsHandler) sendEvent(w http.ResponseWriter, flusher http.Flusher, eventType string, data any) error {
-	jsonData, err := json.Marshal(data)
+// markTestMessageDelivered records that a portal test message reached the
+// plugin; live messages otherwise stay queued until acknowledged.
+func (h *EventsHandler) markTestMessageDelivered(ctx context.Context, event sse.Event) {
+	id := eventMessageID(event)
+	if id == "" {
+		return
+	}
+	if err := h.messageService.MarkDelivered(ctx, id); err != nil {
+		log.Warn().Err(err).Str("messageId", id).Msg("failed to mark test message as delivered")
+	}
+}
+
+// eventMessageID returns the inbound message ID of a message event.
+func eventMessageID(event sse.Event) string {
+	var data struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return ""
+	}
+	return data.ID
+}
+
+// leaveRoute unregisters a closed routed stream and sends the queued
+// messages of its conversations again, now routed to the sessions that take
+// them over.
+func (h *EventsHandler) leaveRoute(ctx context.Context, accountID, sessionID, conn string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), routeLeaveTimeout)
+	defer cancel()
+
+	queued, err := h.messageService.FindQueuedByAccountID(ctx, accountID)
 	if err != nil {
+		// The messages are still queued and go out with the next connection
+		log.Warn().Err(err).Str("accountId", accountID).Msg("failed to load queued messages for rebalancing")
+	}
+	var keys []string
+	seen := make(map[string]bool)
+	for _, msg := range queued {
+		if !seen[msg.ConversationKey] {
+			seen[msg.ConversationKey] = true
+			keys = append(keys, msg.ConversationKey)
+		}
+	}
+
+	moved, left, err := h.router.Leave(ctx, accountID, sessionID, conn, keys)
+	if err != nil {
+		log.Warn().Err(err).Str("sessionId", sessionID).Msg("failed to leave event routes")
+		return
+	}
+	if !left {
+		return
+	}
+
+	h.events.RoutesRebalanced(ctx, accountID, sessionID, moved)
+	movedKeys := make(map[string]bool, len(moved))
+	for _, key := range moved {
+		movedKeys[key] = true
+	}
+	for _, msg := range queued {
+		if !movedKeys[msg.ConversationKey] {
+			continue
+		}
+		event := sse.NewRawEvent("message", msg.AccountID, msg.ConversationKey, msg.ToSSEEventData(nil))
+		if err := h.broker.Publish(ctx, accountID, event); err != nil {
+			log.Warn().Err(err).Str("messageId", msg.ID).Msg("failed to publish rebalanced message")
+		}
+	}
+
+	log.Info().
+		Str("accountId", accountID).
+		Str("sessionId", sessionID).
+		Int("movedConversations", len(moved)).
+		Msg("rebalanced event routes")
+}
+
+// requestedHeartbeat reads the heartbeat query parameter (seconds) and
+// clamps it to the server bounds; missing or invalid values use the default.
+func requestedHeartbeat(r *http.Request) time.Duration {
+	seconds, err := strconv.Atoi(r.URL.Query().Get("heartbeat"))
+	if err != nil {
+		return sse.HeartbeatInterval
+	}
+	return sse.ClampHeartbeat(time.Duration(seconds) * time.Second)
+}
+
+// eventSink writes events to one stream connection.
+type eventSink interface {
+	send(event sse.Event) error
+	// sendData sends an event generated for this connection, such as
+	// connected.
+	sendData(eventType string, data any) error
+	heartbeat(now time.Time) error
+	// resumeCursor is the ID of the last event written.
+	resumeCursor() string
+}
+
+// eventStream writes events to one SSE connection: enveloped for v2, bare
+// event data for v1. Every write has a deadline so that a client that stopped
+// reading is detected on the next event or heartbeat.
+type eventStream struct {
+	w        http.ResponseWriter
+	rc       *http.ResponseController
+	envelope bool
+	// Signs envelopes when set; only used with envelope
+	signer    *sse.Signer
+	accountID string
+	// ID of the last event written, sent as the resume cursor when the
+	// stream is closed for falling behind
+	lastEventID string
+}
+
+func newEventStream(w http.ResponseWriter, envelope bool, accountID string) *eventStream {
+	return &eventStream{
+		w:         w,
+		rc:        http.NewResponseController(w),
+		envelope:  envelope,
+		accountID: accountID,
+	}
+}
+
+func (s *eventStream) send(event sse.Event) error {
+	if err := s.write(func() error {
+		if s.signer != nil {
+			return sse.WriteSigned(s.w, event, s.signer)
+		}
+		return sse.Write(s.w, event, s.envelope)
+	}); err != nil {
 		return err
 	}
+	if event.ID != "" {
+		s.lastEventID = event.ID
+	}
+	return nil
+}
 
-	return h.sendRawEvent(w, flusher, sse.Event{Type: eventType, Data: jsonData})
+func (s *eventStream) resumeCursor() string {
+	return s.lastEventID
 }
 
-func (h *EventsHandler) sendRawEvent(w http.ResponseWriter, flusher http.Flusher, event sse.Event) error {
-	if _, err := fmt.Fprintf(w, "event: %s\n", event.Type); err != nil {
+func (s *eventStream) heartbeat(now time.Time) error {
+	return s.write(func() error { return sse.WriteHeartbeat(s.w, now) })
+}
+
+func (s *eventStream) write(fn func() error) error {
+	// Not all writers support deadlines (e.g. test recorders); writes then
+	// block as before.
+	if err := s.rc.SetWriteDeadline(time.Now().Add(sse.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if err := fn(); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "data: %s\n\n", event.Data); err != nil {
+	return s.rc.Flush()
+}
+
+func (s *eventStream) sendData(eventType string, data any) error {
+	event, err := sse.NewEvent(eventType, s.accountID, "", data)
+	if err != nil {
+		return err
+	}
+	return s.send(event)
+}
+
+// externalSink adapts an EventSink.
+type externalSink struct {
+	sink      EventSink
+	accountID string
+	// ID of the last event written
+	lastEventID string
+}
+
+func (s *externalSink) send(event sse.Event) error {
+	if err := s.sink.Send(event); err != nil {
 		return err
 	}
-	flusher.Flush()
+	if event.ID != "" {
+		s.lastEventID = event.ID
+	}
 	return nil
 }
+
+func (s *externalSink) sendData(eventType string, data any) error {
+	event, err := sse.NewEvent(eventType, s.accountID, "", data)
+	if err != nil {
+		return err
+	}
+	return s.send(event)
+}
+
+func (s *externalSink) heartbeat(now time.Time) error {
+	return s.sink.Heartbeat(now)
+}
+
+func (s *externalSink) resumeCursor() string {
+	return s.lastEventID
+}
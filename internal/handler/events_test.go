@@ -2,16 +2,23 @@ package handler
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/openclaw/relay-server-go/internal/httputil"
 	"github.com/openclaw/relay-server-go/internal/middleware"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/service"
 	"github.com/openclaw/relay-server-go/internal/sse"
 )
 
@@ -23,7 +30,7 @@ func withSession(ctx context.Context, session *model.Session) context.Context {
 func TestEventsHandler_ServeHTTP(t *testing.T) {
 	t.Run("returns 401 when no session or account in context", func(t *testing.T) {
 		// Create handler without dependencies (will fail early)
-		handler := NewEventsHandler(nil, nil)
+		handler := NewEventsHandler(nil, nil, nil, nil, nil, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
 		rec := httptest.NewRecorder()
@@ -35,18 +42,17 @@ func TestEventsHandler_ServeHTTP(t *testing.T) {
 	})
 }
 
-func TestEventsHandler_sendEvent(t *testing.T) {
+func TestEventStream_sendData(t *testing.T) {
 	t.Run("formats SSE event correctly", func(t *testing.T) {
-		handler := &EventsHandler{}
 		rec := httptest.NewRecorder()
-		flusher := rec // httptest.ResponseRecorder implements http.Flusher
+		stream := newEventStream(rec, false, "")
 
 		data := map[string]any{
 			"accountId": "acc-1",
 			"status":    "paired",
 		}
 
-		err := handler.sendEvent(rec, flusher, "connected", data)
+		err := stream.sendData("connected", data)
 
 		assert.NoError(t, err)
 		body := rec.Body.String()
@@ -56,18 +62,17 @@ func TestEventsHandler_sendEvent(t *testing.T) {
 	})
 }
 
-func TestEventsHandler_sendRawEvent(t *testing.T) {
+func TestEventStream_send(t *testing.T) {
 	t.Run("writes event and data lines", func(t *testing.T) {
-		handler := &EventsHandler{}
 		rec := httptest.NewRecorder()
-		flusher := rec
+		stream := newEventStream(rec, false, "")
 
 		event := sse.Event{
 			Type: "message",
 			Data: json.RawMessage(`{"text": "hello"}`),
 		}
 
-		err := handler.sendRawEvent(rec, flusher, event)
+		err := stream.send(event)
 
 		assert.NoError(t, err)
 		body := rec.Body.String()
@@ -75,6 +80,45 @@ func TestEventsHandler_sendRawEvent(t *testing.T) {
 		assert.Contains(t, body, `data: {"text": "hello"}`)
 		assert.Contains(t, body, "\n\n")
 	})
+
+	t.Run("wraps data in the envelope for v2", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		stream := newEventStream(rec, true, "")
+
+		event := sse.NewRawEvent("message", "acc-1", "conv-1", json.RawMessage(`{"text":"he said \"hi\""}`))
+
+		err := stream.send(event)
+
+		assert.NoError(t, err)
+		body := rec.Body.String()
+		assert.True(t, strings.HasPrefix(body, "event: message\ndata: "))
+
+		var envelope sse.Envelope
+		data := strings.TrimSuffix(strings.TrimPrefix(body, "event: message\ndata: "), "\n\n")
+		assert.NoError(t, json.Unmarshal([]byte(data), &envelope))
+		assert.Equal(t, event.ID, envelope.ID)
+		assert.Equal(t, "message", envelope.Type)
+		assert.Equal(t, "acc-1", envelope.AccountID)
+		assert.Equal(t, "conv-1", envelope.ConversationKey)
+		assert.Equal(t, sse.SchemaVersion, envelope.SchemaVersion)
+		assert.False(t, envelope.OccurredAt.IsZero())
+		assert.JSONEq(t, `{"text":"he said \"hi\""}`, string(envelope.Data))
+	})
+
+	t.Run("signs v2 envelopes when a signer is set", func(t *testing.T) {
+		signer := sse.NewSigner("deployment-secret")
+		rec := httptest.NewRecorder()
+		stream := newEventStream(rec, true, "")
+		stream.signer = signer
+
+		require.NoError(t, stream.send(sse.NewRawEvent("message", "acc-1", "conv-1", json.RawMessage(`{"text":"hi"}`))))
+
+		data := strings.TrimSuffix(strings.TrimPrefix(rec.Body.String(), "event: message\ndata: "), "\n\n")
+		jwk := signer.JWKS().Keys[0]
+		pub, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		require.NoError(t, err)
+		assert.True(t, sse.VerifyEnvelope(ed25519.PublicKey(pub), []byte(data)))
+	})
 }
 
 // Override sendRawEvent for testing - this tests the format logic
@@ -118,10 +162,10 @@ func TestSSEEventFormat(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			handler := &EventsHandler{}
 			rec := httptest.NewRecorder()
+			stream := newEventStream(rec, false, "")
 
-			err := handler.sendEvent(rec, rec, tc.eventType, tc.data)
+			err := stream.sendData(tc.eventType, tc.data)
 
 			assert.NoError(t, err)
 			body := rec.Body.String()
@@ -144,7 +188,7 @@ func TestInboundMessage_ToSSEEventData(t *testing.T) {
 			CreatedAt:         now,
 		}
 
-		data := msg.ToSSEEventData()
+		data := msg.ToSSEEventData(nil)
 
 		var parsed map[string]any
 		err := json.Unmarshal(data, &parsed)
@@ -154,6 +198,21 @@ func TestInboundMessage_ToSSEEventData(t *testing.T) {
 		assert.NotNil(t, parsed["kakaoPayload"])
 		assert.NotNil(t, parsed["normalized"])
 		assert.NotNil(t, parsed["createdAt"])
+		assert.NotContains(t, parsed, "profile")
+	})
+
+	t.Run("includes the shared kakao profile", func(t *testing.T) {
+		nickname := "Alice"
+		msg := &model.InboundMessage{ID: "msg-1", KakaoPayload: json.RawMessage(`{}`)}
+
+		data := msg.ToSSEEventData(&model.KakaoProfile{Nickname: &nickname, FetchedAt: time.Now()})
+
+		var parsed struct {
+			Profile map[string]any `json:"profile"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &parsed))
+		assert.Equal(t, "Alice", parsed.Profile["nickname"])
+		assert.Nil(t, parsed.Profile["profileImageUrl"])
 	})
 
 	t.Run("handles nil normalized message", func(t *testing.T) {
@@ -165,7 +224,7 @@ func TestInboundMessage_ToSSEEventData(t *testing.T) {
 			CreatedAt:         time.Now(),
 		}
 
-		data := msg.ToSSEEventData()
+		data := msg.ToSSEEventData(nil)
 
 		var parsed map[string]any
 		err := json.Unmarshal(data, &parsed)
@@ -192,3 +251,141 @@ func TestSSEHeaders(t *testing.T) {
 		}
 	})
 }
+
+func TestEventStream_heartbeat(t *testing.T) {
+	rec := httptest.NewRecorder()
+	stream := newEventStream(rec, true, "")
+
+	err := stream.heartbeat(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ": ping 2026-01-02T03:04:05Z\n\n", rec.Body.String())
+	assert.True(t, rec.Flushed)
+}
+
+func TestRequestedHeartbeat(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":     sse.HeartbeatInterval,
+		"abc":  sse.HeartbeatInterval,
+		"0":    sse.HeartbeatInterval,
+		"1":    sse.MinHeartbeatInterval,
+		"15":   15 * time.Second,
+		"3600": sse.MaxHeartbeatInterval,
+		"-10":  sse.HeartbeatInterval,
+	}
+	for query, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v2/events?heartbeat="+query, nil)
+		assert.Equal(t, want, requestedHeartbeat(req), query)
+	}
+}
+
+func TestEventsHandler_History(t *testing.T) {
+	ctx := context.Background()
+	history := sse.NewMemoryEventHistory(10)
+	first := sse.NewRawEvent("message", "acc-1", "conv-1", json.RawMessage(`{"id":"msg-1"}`))
+	second := sse.NewRawEvent("message", "acc-1", "conv-1", json.RawMessage(`{"id":"msg-2"}`))
+	require.NoError(t, history.Append(ctx, "acc-1", first))
+	require.NoError(t, history.Append(ctx, "acc-1", second))
+	handler := NewEventsHandler(nil, history, nil, nil, nil, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v2/events/history"+query, nil)
+		req = req.WithContext(httputil.WithAPIVersion(withAccount(req.Context(), &model.Account{ID: "acc-1"}), httputil.APIVersionV2))
+		rec := httptest.NewRecorder()
+		handler.History(rec, req)
+		return rec
+	}
+
+	t.Run("returns events after the cursor", func(t *testing.T) {
+		rec := get("?since=" + first.ID)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Data EventHistoryResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Data.Events, 1)
+		assert.Equal(t, second.ID, body.Data.Events[0].ID)
+		assert.Equal(t, sse.SchemaVersion, body.Data.Events[0].SchemaVersion)
+		assert.False(t, body.Data.HasMore)
+	})
+
+	t.Run("pages with cursors", func(t *testing.T) {
+		rec := get("?limit=1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Data EventHistoryResponse `json:"data"`
+			Meta httputil.PageMeta    `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Data.Events, 1)
+		assert.Equal(t, first.ID, body.Data.Events[0].ID)
+		assert.True(t, body.Meta.HasMore)
+		require.NotEmpty(t, body.Meta.NextCursor)
+
+		rec = get("?limit=1&cursor=" + body.Meta.NextCursor)
+		body.Meta = httputil.PageMeta{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Data.Events, 1)
+		assert.Equal(t, second.ID, body.Data.Events[0].ID)
+		assert.False(t, body.Meta.HasMore)
+		assert.Empty(t, body.Meta.NextCursor)
+	})
+
+	t.Run("returns 410 for an unknown event ID", func(t *testing.T) {
+		rec := get("?since=evt_gone")
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.Contains(t, rec.Body.String(), "HISTORY_EXPIRED")
+	})
+
+	t.Run("returns 400 for an invalid cursor", func(t *testing.T) {
+		rec := get("?since=yesterday")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestEventsHandler_Resume(t *testing.T) {
+	ctx := context.Background()
+	history := sse.NewMemoryEventHistory(10)
+	broker := sse.NewMemoryBroker(sse.BrokerOptions{History: history})
+	defer broker.Close()
+	for _, id := range []string{"msg-1", "msg-2", "msg-3"} {
+		require.NoError(t, broker.Publish(ctx, "acc-1", sse.NewRawEvent("message", "acc-1", "conv-1", json.RawMessage(`{"id":"`+id+`"}`))))
+	}
+
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("FindQueuedByAccountID", mock.Anything, "acc-1").Return([]model.InboundMessage{
+		{ID: "msg-3", AccountID: "acc-1", ConversationKey: "conv-1"},
+		{ID: "msg-4", AccountID: "acc-1", ConversationKey: "conv-1"},
+	}, nil)
+	inboundRepo.On("MarkDelivered", mock.Anything, "msg-4").Return(nil)
+	handler := NewEventsHandler(broker, history, service.NewMessageService(inboundRepo, new(mockOutboundRepo), nil, nil, nil), nil, nil, nil)
+
+	serve := func(lastEventID string) string {
+		reqCtx, cancel := context.WithTimeout(withAccount(ctx, &model.Account{ID: "acc-1"}), 50*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil).WithContext(reqCtx)
+		req.Header.Set("Last-Event-ID", lastEventID)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	t.Run("sends the events after Last-Event-ID first", func(t *testing.T) {
+		body := serve("1")
+		assert.NotContains(t, body, `"msg-1"`)
+		assert.Less(t, strings.Index(body, "id: 2\n"), strings.Index(body, "id: 3\n"))
+		// msg-3 was resumed, so only msg-4 is replayed from the queue
+		assert.Equal(t, 1, strings.Count(body, `"msg-3"`))
+		assert.Less(t, strings.Index(body, "id: 3\n"), strings.Index(body, `"msg-4"`))
+		inboundRepo.AssertNotCalled(t, "MarkDelivered", mock.Anything, "msg-3")
+	})
+
+	t.Run("reports a cursor that cannot be resumed", func(t *testing.T) {
+		inboundRepo.On("MarkDelivered", mock.Anything, "msg-3").Return(nil)
+		body := serve("7")
+		assert.Contains(t, body, "event: "+sse.EventResumeExpired+"\n")
+		assert.Contains(t, body, `"msg-3"`)
+	})
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// JWKSPath is where the JWKS endpoint is served.
+const JWKSPath = "/.well-known/jwks.json"
+
+// JWKSHandler publishes the key that verifies signed SSE events.
+type JWKSHandler struct {
+	signer *sse.Signer
+}
+
+// NewJWKSHandler creates the handler. With a nil signer the key set is empty.
+func NewJWKSHandler(signer *sse.Signer) *JWKSHandler {
+	return &JWKSHandler{signer: signer}
+}
+
+// GET /.well-known/jwks.json
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	keys := sse.JWKSet{Keys: []sse.JWK{}}
+	if h.signer != nil {
+		keys = h.signer.JWKS()
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	writeJSON(w, http.StatusOK, keys)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	t.Run("publishes the signing key", func(t *testing.T) {
+		signer := sse.NewSigner("deployment-secret")
+		rec := httptest.NewRecorder()
+		NewJWKSHandler(signer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got sse.JWKSet
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		require.Len(t, got.Keys, 1)
+		assert.Equal(t, signer.KeyID(), got.Keys[0].KeyID)
+	})
+
+	t.Run("empty key set when signing is disabled", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewJWKSHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"keys":[]}`, rec.Body.String())
+	})
+}
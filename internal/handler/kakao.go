@@ -1,87 +1,145 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/openclaw/relay-server-go/internal/audit"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 	"github.com/openclaw/relay-server-go/internal/service"
 	"github.com/openclaw/relay-server-go/internal/sse"
 )
 
-type Command struct {
-	Type string // PAIR, UNPAIR, STATUS, HELP, CODE
-	Code string
-}
-
-func parseCommand(utterance string) *Command {
-	trimmed := strings.TrimSpace(utterance)
-
-	if strings.HasPrefix(trimmed, "/pair ") {
-		code := strings.ToUpper(strings.TrimSpace(trimmed[6:]))
-		if code != "" {
-			return &Command{Type: "PAIR", Code: code}
-		}
-	}
-
-	if trimmed == "/unpair" {
-		return &Command{Type: "UNPAIR"}
-	}
-
-	if trimmed == "/status" {
-		return &Command{Type: "STATUS"}
-	}
+// DegradedReply selects how a webhook is answered when storage fails.
+type DegradedReply string
 
-	if trimmed == "/help" {
-		return &Command{Type: "HELP"}
-	}
-
-	if trimmed == "/code" {
-		return &Command{Type: "CODE"}
-	}
-
-	return nil
-}
+const (
+	// DegradedReplyApology tells the user about the problem and whether the
+	// message will be retried.
+	DegradedReplyApology DegradedReply = "apology"
+	// DegradedReplySilent answers as if the message was accepted.
+	DegradedReplySilent DegradedReply = "silent"
+)
 
 type KakaoHandler struct {
 	convService         *service.ConversationService
 	sessionService      *service.SessionService
 	messageService      *service.MessageService
+	accountUsageService *service.AccountUsageService
+	onboardingService   *service.OnboardingService
 	portalAccessService *service.PortalAccessService
-	broker              *sse.Broker
-	callbackTTL         time.Duration
-	portalBaseURL       string
+	experimentService   *service.ExperimentService
+	profileService      *service.KakaoProfileService
+	// Sends the notices for queued messages that are not relayed
+	callbacks service.CallbackSender
+	// nil when webhook delivery is not available
+	webhooks      *service.WebhookDeliveryService
+	rateLimiter   ratelimit.Limiter
+	broker        sse.EventPublisher
+	events        *service.SessionEvents
+	callbackTTL   time.Duration
+	portalBaseURL string
+	defaultLocale i18n.Locale
+	inboundLimits service.InboundLimits
+	// Fast path queue for messages of paired conversations; nil handles
+	// every webhook inline
+	inboundQueue  service.InboundQueue
+	degradedReply DegradedReply
+	// Ask paired users for consent to storing message content before
+	// forwarding their first message
+	consentPrompt bool
+	commands      *CommandRegistry
 }
 
 func NewKakaoHandler(
 	convService *service.ConversationService,
 	sessionService *service.SessionService,
 	messageService *service.MessageService,
+	accountUsageService *service.AccountUsageService,
+	onboardingService *service.OnboardingService,
 	portalAccessService *service.PortalAccessService,
-	broker *sse.Broker,
+	experimentService *service.ExperimentService,
+	profileService *service.KakaoProfileService,
+	webhooks *service.WebhookDeliveryService,
+	callbacks service.CallbackSender,
+	rateLimiter ratelimit.Limiter,
+	broker sse.EventPublisher,
 	callbackTTL time.Duration,
 	portalBaseURL string,
+	defaultLocale i18n.Locale,
+	inboundLimits service.InboundLimits,
+	inboundQueue service.InboundQueue,
+	degradedReply DegradedReply,
+	consentPrompt bool,
 ) *KakaoHandler {
-	return &KakaoHandler{
+	h := &KakaoHandler{
 		convService:         convService,
 		sessionService:      sessionService,
 		messageService:      messageService,
+		accountUsageService: accountUsageService,
+		onboardingService:   onboardingService,
 		portalAccessService: portalAccessService,
+		experimentService:   experimentService,
+		profileService:      profileService,
+		webhooks:            webhooks,
+		callbacks:           callbacks,
+		rateLimiter:         rateLimiter,
 		broker:              broker,
+		events:              service.NewSessionEvents(broker),
 		callbackTTL:         callbackTTL,
 		portalBaseURL:       portalBaseURL,
+		defaultLocale:       defaultLocale,
+		inboundLimits:       inboundLimits,
+		inboundQueue:        inboundQueue,
+		degradedReply:       degradedReply,
+		consentPrompt:       consentPrompt,
+	}
+	h.commands = defaultCommands(h)
+	return h
+}
+
+// RegisterCommand adds a deployment-specific chat command. Built-in commands
+// are matched first, so it cannot shadow them; it is listed by /help.
+func (h *KakaoHandler) RegisterCommand(cmd ChatCommand) {
+	h.commands.Register(cmd)
+}
+
+// locale picks the reply language from the language Kakao reports for the
+// user, falling back to the deployment default.
+func (h *KakaoHandler) locale(req *KakaoWebhookRequest) i18n.Locale {
+	return h.localeFor(req.UserRequest.Lang)
+}
+
+func (h *KakaoHandler) localeFor(lang string) i18n.Locale {
+	if locale, ok := i18n.ParseLocale(lang); ok {
+		return locale
 	}
+	return h.defaultLocale
 }
 
 func (h *KakaoHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if maxBytes := h.inboundLimits.MaxBodyBytes; maxBytes > 0 {
+		if r.ContentLength > maxBytes {
+			h.rejectOversized(w, r.ContentLength)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	}
+
 	var req KakaoWebhookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.rejectOversized(w, r.ContentLength)
+			return
+		}
 		log.Warn().Err(err).Msg("invalid kakao webhook request")
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 		return
@@ -94,240 +152,334 @@ func (h *KakaoHandler) Webhook(w http.ResponseWriter, r *http.Request) {
 
 	conversationKey := service.BuildConversationKey(channelID, userKey)
 
-	log.Info().
+	// Commands read the context from the request
+	r = r.WithContext(audit.WithActor(r.Context(), audit.ActorKakaoUser))
+	ctx := r.Context()
+	locale := h.locale(&req)
+	cmd, args := h.commands.Match(utterance)
+
+	// The utterance is never logged: command arguments carry pairing and
+	// portal codes, and messages are the user's own words.
+	event := log.Info().
 		Str("conversationKey", conversationKey).
-		Str("utterance", truncate(utterance, 50)).
-		Bool("hasCallback", callbackURL != "").
-		Msg("received kakao webhook")
+		Bool("hasCallback", callbackURL != "")
+	if cmd != nil {
+		event = event.Str("command", cmd.Name())
+	}
+	event.Msg("received kakao webhook")
 
-	var callbackURLPtr *string
-	var callbackExpiresAt *time.Time
-	if callbackURL != "" {
-		callbackURLPtr = &callbackURL
-		expires := time.Now().Add(h.callbackTTL)
-		callbackExpiresAt = &expires
+	if cmd == nil && h.enqueueInbound(ctx, &req) {
+		writeJSON(w, http.StatusOK, NewCallbackResponse())
+		return
 	}
 
-	ctx := r.Context()
+	job := h.newInboundJob(&req)
 
-	conv, err := h.convService.FindOrCreate(ctx, channelID, userKey, callbackURLPtr, callbackExpiresAt)
+	// Pairing commands, state changes and message ordering of this
+	// conversation must not interleave with other requests for it.
+	unlock := h.convService.Lock(ctx, conversationKey)
+	defer unlock()
+
+	conv, err := h.convService.FindOrCreate(ctx, channelID, userKey, job.CallbackURL, job.CallbackExpiresAt)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to find or create conversation")
-		writeJSON(w, http.StatusOK, NewCallbackResponse())
+		// Commands are not retried; the user can send them again
+		if cmd != nil || job.NormalizedMessage == nil {
+			job = nil
+		}
+		h.respondDegraded(w, r, locale, job)
 		return
 	}
 
-	cmd := parseCommand(utterance)
 	if cmd != nil {
-		response := h.handleCommand(r, cmd, conv, conversationKey)
+		response := cmd.Execute(&CommandContext{
+			Request:         r,
+			Conversation:    conv,
+			ConversationKey: conversationKey,
+			Locale:          locale,
+			Args:            args,
+		})
 		writeJSON(w, http.StatusOK, response)
 		return
 	}
 
+	if conv.State == model.PairingStateArchived && conv.AccountID != nil {
+		h.reactivate(ctx, conv)
+	}
+
 	if conv.State != model.PairingStatePaired || conv.AccountID == nil {
-		writeJSON(w, http.StatusOK, NewTextResponse(
-			"OpenClaw에 연결되지 않았습니다.\n\n"+
-				"연결하려면 페어링 코드를 받은 후:\n"+
-				"/pair <코드>\n\n"+
-				"를 입력해주세요.\n\n"+
-				"도움말: /help",
-		))
+		greeting := h.experimentService.Text(ctx, service.ExperimentUnpairedGreeting, conversationKey, i18n.T(locale, i18n.KakaoUnpairedGreeting))
+		writeJSON(w, http.StatusOK, NewTextResponse(greeting))
 		return
 	}
 
-	normalizedMsg, _ := json.Marshal(map[string]string{
-		"userId":    userKey,
-		"text":      utterance,
-		"channelId": channelID,
-	})
-
-	msg, err := h.messageService.CreateInbound(ctx, service.CreateInboundParams{
-		AccountID:         *conv.AccountID,
-		ConversationKey:   conversationKey,
-		KakaoPayload:      req.ToJSON(),
-		NormalizedMessage: normalizedMsg,
-		CallbackURL:       callbackURLPtr,
-		CallbackExpiresAt: callbackExpiresAt,
-	})
+	// A disabled account, e.g. one pending deletion, gets no messages
+	suspended, err := h.accountUsageService.Suspended(ctx, *conv.AccountID)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to create inbound message")
-		writeJSON(w, http.StatusOK, NewCallbackResponse())
+		log.Error().Err(err).Str("accountId", *conv.AccountID).Msg("failed to check account status")
+		if job.NormalizedMessage == nil {
+			job = nil
+		}
+		h.respondDegraded(w, r, locale, job)
+		return
+	}
+	if suspended {
+		writeJSON(w, http.StatusOK, NewTextResponse(i18n.T(locale, i18n.KakaoAccountSuspended)))
 		return
 	}
 
-	sseData := msg.ToSSEEventData()
-	log.Debug().
-		Str("messageId", msg.ID).
-		RawJSON("sseEventData", sseData).
-		Msg("publishing sse message event")
+	if h.consentPrompt && conv.ContentConsent == nil {
+		writeJSON(w, http.StatusOK, NewConsentPromptResponse(locale))
+		return
+	}
 
-	if err := h.broker.Publish(ctx, *conv.AccountID, sse.Event{
-		Type: "message",
-		Data: sseData,
-	}); err != nil {
-		log.Warn().Err(err).Msg("failed to publish message event")
+	if job.NormalizedMessage == nil {
+		writeJSON(w, http.StatusOK, NewTextResponse(i18n.T(locale, i18n.KakaoUnsupportedMessage)))
+		return
 	}
 
+	if err := h.recordInbound(ctx, conv, job); err != nil {
+		log.Error().Err(err).Msg("failed to create inbound message")
+		h.respondDegraded(w, r, locale, job)
+		return
+	}
+	if conv.DeliveryPaused() {
+		writeJSON(w, http.StatusOK, NewTextResponse(pauseAutoReply(conv, locale)))
+		return
+	}
 	writeJSON(w, http.StatusOK, NewCallbackResponse())
 }
 
-func (h *KakaoHandler) handleCommand(r *http.Request, cmd *Command, conv *model.ConversationMapping, conversationKey string) *KakaoResponse {
-	ctx := r.Context()
+// pauseAutoReply answers a message held back by a delivery pause with the
+// agent's auto reply, or the default notice when it set none.
+func pauseAutoReply(conv *model.ConversationMapping, locale i18n.Locale) string {
+	if conv.PauseAutoReply != nil {
+		return *conv.PauseAutoReply
+	}
+	return i18n.T(locale, i18n.KakaoDeliveryPaused)
+}
 
-	switch cmd.Type {
-	case "PAIR":
-		if cmd.Code == "" {
-			return NewTextResponse("페어링 코드를 입력해주세요.\n\n예: /pair ABCD-1234")
-		}
+// reactivate resumes relaying for an archived conversation when its user
+// sends a message. On failure the conversation stays archived and the
+// message is answered like one for an unpaired conversation.
+func (h *KakaoHandler) reactivate(ctx context.Context, conv *model.ConversationMapping) {
+	reactivated, err := h.convService.Reactivate(ctx, conv.ConversationKey)
+	if err != nil {
+		log.Error().Err(err).Str("conversationKey", conv.ConversationKey).Msg("failed to reactivate conversation")
+		return
+	}
+	if !reactivated {
+		return
+	}
+	conv.State = model.PairingStatePaired
+	h.events.ConversationReactivated(ctx, *conv.AccountID, conv.ConversationKey)
+}
 
-		if conv.State == model.PairingStatePaired {
-			return NewTextResponse(
-				"이미 OpenClaw에 연결되어 있습니다.\n\n" +
-					"다른 봇에 연결하려면 먼저 /unpair 로 연결을 해제하세요.",
-			)
-		}
+// respondDegraded answers a webhook that could not be processed because
+// storage failed. A non-nil job is scheduled to be retried when the queue is
+// enabled; with the apology reply the user is told whether it will be.
+func (h *KakaoHandler) respondDegraded(w http.ResponseWriter, r *http.Request, locale i18n.Locale, job *service.InboundJob) {
+	service.RecordInboundDegraded()
 
-		result := h.sessionService.VerifyPairingCode(ctx, cmd.Code, conversationKey)
-		if !result.Success {
-			errorMessages := map[string]string{
-				"INVALID_CODE":   "❌ 유효하지 않은 코드입니다.\n\n코드를 다시 확인해주세요.",
-				"INTERNAL_ERROR": "❌ 오류가 발생했습니다. 다시 시도해주세요.",
-			}
-			msg := errorMessages[result.Error]
-			if msg == "" {
-				msg = "페어링에 실패했습니다."
-			}
-			return NewTextResponse(msg)
+	retrying := false
+	if job != nil && h.inboundQueue != nil {
+		var err error
+		retrying, err = h.inboundQueue.RetryLater(r.Context(), job)
+		if err != nil {
+			log.Error().Err(err).Str("conversationKey", job.ConversationKey()).Msg("failed to schedule inbound retry")
 		}
+	}
 
-		// Update conversation state
-		if err := h.convService.UpdateState(ctx, conversationKey, model.PairingStatePaired, &result.AccountID); err != nil {
-			log.Error().Err(err).Msg("failed to update conversation state after session pairing")
-		}
+	if h.degradedReply == DegradedReplySilent {
+		writeJSON(w, http.StatusOK, NewCallbackResponse())
+		return
+	}
+	key := i18n.KakaoDegradedFailed
+	if retrying {
+		key = i18n.KakaoDegradedRetrying
+	}
+	writeJSON(w, http.StatusOK, NewTextResponse(i18n.T(locale, key)))
+}
 
-		// Publish pairing_complete event
-		session, err := h.sessionService.FindByID(ctx, result.SessionID)
-		if err == nil && session != nil {
-			if err := h.sessionService.PublishPairingComplete(ctx, session, conversationKey); err != nil {
-				log.Warn().Err(err).Msg("failed to publish pairing_complete event")
-			}
-		}
+// newInboundJob extracts what recording the message needs from the webhook.
+// NormalizedMessage is nil when the message cannot be represented.
+func (h *KakaoHandler) newInboundJob(req *KakaoWebhookRequest) *service.InboundJob {
+	job := &service.InboundJob{
+		ChannelID:    req.GetChannelID(),
+		UserKey:      req.GetPlusfriendUserKey(),
+		AppUserID:    req.GetAppUserID(),
+		Lang:         req.UserRequest.Lang,
+		KakaoPayload: req.ToJSON(),
+		ReceivedAt:   time.Now(),
+	}
+	if callbackURL := req.UserRequest.CallbackURL; callbackURL != "" {
+		expires := job.ReceivedAt.Add(h.callbackTTL)
+		job.CallbackURL = &callbackURL
+		job.CallbackExpiresAt = &expires
+	}
 
-		return NewTextResponse("✅ OpenClaw에 연결되었습니다!\n\n이제 자유롭게 대화를 시작하세요.")
+	normalizedMsg, err := model.NewTextMessage(
+		model.MessageSender{UserID: job.UserKey, ChannelID: job.ChannelID},
+		req.UserRequest.Utterance,
+		req.UserRequest.Lang,
+	).Marshal()
+	if err != nil {
+		log.Warn().Err(err).Str("conversationKey", job.ConversationKey()).Msg("invalid normalized message")
+		return job
+	}
+	job.NormalizedMessage = normalizedMsg
+	return job
+}
 
-	case "UNPAIR":
-		if conv.State != model.PairingStatePaired {
-			return NewTextResponse("연결된 OpenClaw가 없습니다.")
-		}
+// enqueueInbound is the webhook fast path. A regular message with a
+// callback URL is queued and answered right away, without reading the
+// database; the queue worker upserts the conversation, then stores and
+// publishes the message or answers it through the callback. It reports false
+// when the message must be handled inline instead.
+func (h *KakaoHandler) enqueueInbound(ctx context.Context, req *KakaoWebhookRequest) bool {
+	if h.inboundQueue == nil {
+		return false
+	}
 
-		if err := h.convService.UpdateState(ctx, conversationKey, model.PairingStateUnpaired, nil); err != nil {
-			log.Error().Err(err).Msg("failed to unpair")
-			return NewTextResponse("연결 해제에 실패했습니다. 다시 시도해주세요.")
-		}
+	job := h.newInboundJob(req)
+	// Without a callback only the response can carry a notice, such as the
+	// pairing greeting, so the conversation is looked at inline
+	if job.NormalizedMessage == nil || job.CallbackURL == nil {
+		return false
+	}
 
-		return NewTextResponse("연결이 해제되었습니다.\n\n다시 연결하려면 /pair <코드>를 사용하세요.")
-
-	case "STATUS":
-		if conv.State == model.PairingStatePaired && conv.AccountID != nil {
-			pairedAt := "알 수 없음"
-			if conv.PairedAt != nil {
-				pairedAt = conv.PairedAt.Format("2006-01-02 15:04:05")
-			}
-
-			stats, err := h.messageService.GetQuickStats(ctx, *conv.AccountID)
-			if err != nil {
-				log.Error().Err(err).Msg("failed to get quick stats for status command")
-				return NewTextResponse("✅ 연결됨\n\n연결 시간: " + pairedAt)
-			}
-
-			return NewTextResponse(fmt.Sprintf(
-				"✅ 연결됨\n\n"+
-					"📊 오늘 통계\n"+
-					"• 수신: %d건\n"+
-					"• 발신: %d건 (실패 %d)\n\n"+
-					"📈 전체 통계\n"+
-					"• 총 수신: %d건\n"+
-					"• 총 발신: %d건\n\n"+
-					"연결 시간: %s",
-				stats.InboundToday,
-				stats.OutboundToday,
-				stats.OutboundFailed,
-				stats.InboundTotal,
-				stats.OutboundTotal,
-				pairedAt,
-			))
-		}
-		return NewTextResponse("❌ 연결되지 않음\n\n/pair <코드>로 연결하세요.")
-
-	case "CODE":
-		if conv.State != model.PairingStatePaired {
-			return NewTextResponse(
-				"포털 접속 코드는 연결된 대화에서만 발급할 수 있습니다.\n\n" +
-					"먼저 /pair <코드>로 연결하세요.",
-			)
-		}
+	if err := h.inboundQueue.Enqueue(ctx, job); err != nil {
+		log.Warn().Err(err).Str("conversationKey", job.ConversationKey()).Msg("failed to queue webhook, processing inline")
+		return false
+	}
+	return true
+}
 
-		// Rate limit check: 3 times per 5 minutes
-		allowed, resetAt := h.portalAccessService.CheckCodeGenerationLimit(ctx, conversationKey)
-		if !allowed {
-			minutesLeft := int(time.Until(resetAt).Minutes()) + 1
-			return NewTextResponse(fmt.Sprintf(
-				"⏱️ 코드 생성 한도 초과\n\n"+
-					"5분에 최대 3회까지 코드를 생성할 수 있습니다.\n\n"+
-					"%d분 후 다시 시도해주세요.",
-				minutesLeft,
-			))
-		}
+// ProcessInbound records a webhook queued by the fast path. The queue holds
+// the conversation lock. A message that would have been answered inline,
+// e.g. with the pairing greeting or the suspension notice, gets that answer
+// through its callback and is not relayed.
+func (h *KakaoHandler) ProcessInbound(ctx context.Context, job *service.InboundJob) error {
+	conv, err := h.convService.FindOrCreate(ctx, job.ChannelID, job.UserKey, job.CallbackURL, job.CallbackExpiresAt)
+	if err != nil {
+		return err
+	}
+	locale := h.localeFor(job.Lang)
 
-		code, err := h.portalAccessService.GenerateCode(ctx, conversationKey)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to generate portal access code")
-			return NewTextResponse("코드 생성에 실패했습니다. 잠시 후 다시 시도해주세요.")
-		}
+	if conv.State == model.PairingStateArchived && conv.AccountID != nil {
+		h.reactivate(ctx, conv)
+	}
 
-		// Audit log
-		auditEvent := audit.Event{
-			Type: audit.EventCodeGenerate,
-			Details: map[string]interface{}{
-				"conversationKey": conversationKey,
-				"code":            code.Code,
-				"expiresAt":       code.ExpiresAt,
-			},
-		}
-		if conv.AccountID != nil {
-			auditEvent.AccountID = *conv.AccountID
-		}
-		audit.LogFromRequest(r, auditEvent)
-
-		expiresIn := int(time.Until(code.ExpiresAt).Minutes())
-		msg := fmt.Sprintf(
-			"🔑 포털 접속 코드\n\n"+
-				"코드: %s\n"+
-				"유효시간: %d분\n\n"+
-				"이 코드로 포털에서 대화 내역과 통계를 확인할 수 있습니다.",
-			code.Code,
-			expiresIn,
-		)
-		if h.portalBaseURL != "" {
-			msg += fmt.Sprintf("\n\n포털 주소:\n%s/portal/code", h.portalBaseURL)
-		}
-		return NewTextResponse(msg)
-
-	case "HELP":
-		return NewTextResponse(
-			"📖 도움말\n\n" +
-				"이 봇은 OpenClaw AI 에이전트와 연결하는 중계 서비스입니다.\n\n" +
-				"명령어:\n" +
-				"• /pair <코드> - OpenClaw에 연결\n" +
-				"• /unpair - 연결 해제\n" +
-				"• /status - 연결 상태 확인\n" +
-				"• /code - 포털 접속 코드 발급\n" +
-				"• /help - 이 도움말",
-		)
-
-	default:
-		return NewTextResponse("알 수 없는 명령어입니다. /help를 입력해 도움말을 확인하세요.")
+	if conv.State != model.PairingStatePaired || conv.AccountID == nil {
+		greeting := h.experimentService.Text(ctx, service.ExperimentUnpairedGreeting, conv.ConversationKey, i18n.T(locale, i18n.KakaoUnpairedGreeting))
+		h.notify(ctx, job, NewTextResponse(greeting))
+		return nil
 	}
+
+	suspended, err := h.accountUsageService.Suspended(ctx, *conv.AccountID)
+	if err != nil {
+		return err
+	}
+	if suspended {
+		log.Info().
+			Str("conversationKey", conv.ConversationKey).
+			Str("accountId", *conv.AccountID).
+			Msg("dropping queued message for suspended account")
+		h.notify(ctx, job, NewTextResponse(i18n.T(locale, i18n.KakaoAccountSuspended)))
+		return nil
+	}
+
+	if h.consentPrompt && conv.ContentConsent == nil {
+		h.notify(ctx, job, NewConsentPromptResponse(locale))
+		return nil
+	}
+
+	if err := h.recordInbound(ctx, conv, job); err != nil {
+		return err
+	}
+	if conv.DeliveryPaused() {
+		h.notify(ctx, job, NewTextResponse(pauseAutoReply(conv, locale)))
+	}
+	return nil
+}
+
+// notify answers a queued message through its callback. Callbacks can be
+// used once, so a failed notice is not retried.
+func (h *KakaoHandler) notify(ctx context.Context, job *service.InboundJob, resp *KakaoResponse) {
+	if job.CallbackURL == nil || h.callbacks == nil {
+		return
+	}
+	if job.CallbackExpiresAt != nil && time.Now().After(*job.CallbackExpiresAt) {
+		log.Debug().Str("conversationKey", job.ConversationKey()).Msg("callback expired before the notice was sent")
+		return
+	}
+	if _, err := h.callbacks.SendCallback(ctx, *job.CallbackURL, resp); err != nil {
+		log.Warn().Err(err).Str("conversationKey", job.ConversationKey()).Msg("failed to send notice through callback")
+	}
+}
+
+// recordInbound stores a message of a paired conversation and publishes it
+// to the account. Messages of a paused conversation are only stored; they
+// are published when it resumes. Messages of an account with a webhook are
+// only stored too; the webhook delivery job posts them.
+func (h *KakaoHandler) recordInbound(ctx context.Context, conv *model.ConversationMapping, job *service.InboundJob) error {
+	h.profileService.RefreshInBackground(conv, job.AppUserID)
+
+	params := service.CreateInboundParams{
+		AccountID:         *conv.AccountID,
+		ConversationKey:   conv.ConversationKey,
+		KakaoPayload:      job.KakaoPayload,
+		NormalizedMessage: job.NormalizedMessage,
+		CallbackURL:       job.CallbackURL,
+		CallbackExpiresAt: job.CallbackExpiresAt,
+		ContentConsent:    conv.ContentConsent,
+	}
+	if h.inboundLimits.Apply(&params) {
+		log.Warn().
+			Str("conversationKey", conv.ConversationKey).
+			Int("payloadBytes", len(params.KakaoPayload)).
+			Int("messageBytes", len(params.NormalizedMessage)).
+			Msg("inbound message truncated to size limits")
+	}
+
+	msg, err := h.messageService.CreateInbound(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if conv.DeliveryPaused() {
+		log.Debug().
+			Str("messageId", msg.ID).
+			Str("conversationKey", conv.ConversationKey).
+			Msg("holding message of paused conversation")
+		return nil
+	}
+	if h.webhooks.Handles(ctx, *conv.AccountID) {
+		return nil
+	}
+
+	sseData := msg.ToSSEEventData(h.profileService.Shared(ctx, *conv.AccountID, conv))
+	log.Debug().
+		Str("messageId", msg.ID).
+		RawJSON("sseEventData", sseData).
+		Msg("publishing sse message event")
+
+	event := sse.NewRawEvent("message", *conv.AccountID, conv.ConversationKey, sseData)
+	if err := h.broker.Publish(ctx, *conv.AccountID, event); err != nil {
+		log.Warn().Err(err).Msg("failed to publish message event")
+	}
+	return nil
+}
+
+// rejectOversized answers a webhook whose body exceeds the size limit.
+// contentLength is -1 when the client did not declare it.
+func (h *KakaoHandler) rejectOversized(w http.ResponseWriter, contentLength int64) {
+	service.RecordInboundRejected()
+	log.Warn().
+		Int64("contentLength", contentLength).
+		Int64("maxBytes", h.inboundLimits.MaxBodyBytes).
+		Msg("kakao webhook body too large")
+	writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
 }
 
 func truncate(s string, maxLen int) string {
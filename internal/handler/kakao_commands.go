@@ -0,0 +1,653 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/audit"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+// ChatCommand is a slash command users send in the Kakao chat. Deployments add
+// their own with KakaoHandler.RegisterCommand.
+type ChatCommand interface {
+	// Name identifies the command in logs, e.g. "pair".
+	Name() string
+	// Match reports whether the trimmed utterance invokes the command and
+	// returns its arguments.
+	Match(utterance string) (args string, ok bool)
+	// Execute runs the command with the conversation locked.
+	Execute(cc *CommandContext) *KakaoResponse
+	// Help is the line listed by /help, or "" to leave the command out.
+	Help(locale i18n.Locale) string
+}
+
+// CommandContext is the conversation a command was sent in.
+type CommandContext struct {
+	Request         *http.Request
+	Conversation    *model.ConversationMapping
+	ConversationKey string
+	Locale          i18n.Locale
+	// Text after the command name, trimmed
+	Args string
+}
+
+func (c *CommandContext) Context() context.Context {
+	return c.Request.Context()
+}
+
+// Paired reports whether the conversation is connected to an account,
+// including while it is archived.
+func (c *CommandContext) Paired() bool {
+	return c.Conversation.State == model.PairingStatePaired || c.Archived()
+}
+
+// Archived reports whether the user paused relaying with /archive.
+func (c *CommandContext) Archived() bool {
+	return c.Conversation.State == model.PairingStateArchived
+}
+
+// CommandRegistry matches utterances against chat commands. Commands are
+// tried in the order they were registered; the first match wins.
+type CommandRegistry struct {
+	commands []ChatCommand
+}
+
+func NewCommandRegistry(commands ...ChatCommand) *CommandRegistry {
+	return &CommandRegistry{commands: commands}
+}
+
+func (r *CommandRegistry) Register(cmd ChatCommand) {
+	r.commands = append(r.commands, cmd)
+}
+
+// Match returns the command the utterance invokes and its arguments, or nil
+// for a regular message.
+func (r *CommandRegistry) Match(utterance string) (ChatCommand, string) {
+	if r == nil {
+		return nil, ""
+	}
+	trimmed := strings.TrimSpace(utterance)
+	for _, cmd := range r.commands {
+		if args, ok := cmd.Match(trimmed); ok {
+			return cmd, args
+		}
+	}
+	return nil, ""
+}
+
+// Help lists the help lines of the registered commands.
+func (r *CommandRegistry) Help(locale i18n.Locale) []string {
+	var lines []string
+	for _, cmd := range r.commands {
+		if line := cmd.Help(locale); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// MatchCommand matches "/name" on its own or followed by a space and
+// arguments. It suits most commands' Match.
+func MatchCommand(utterance, name string) (string, bool) {
+	if utterance == name {
+		return "", true
+	}
+	if strings.HasPrefix(utterance, name+" ") {
+		return strings.TrimSpace(utterance[len(name):]), true
+	}
+	return "", false
+}
+
+// defaultCommands are the commands every deployment has.
+func defaultCommands(h *KakaoHandler) *CommandRegistry {
+	registry := NewCommandRegistry(
+		&pairCommand{sessionService: h.sessionService, onboardingService: h.onboardingService},
+		&unpairCommand{convService: h.convService},
+		&archiveCommand{convService: h.convService, events: h.events},
+		&statusCommand{
+			messageService:      h.messageService,
+			sessionService:      h.sessionService,
+			accountUsageService: h.accountUsageService,
+			portalAccessService: h.portalAccessService,
+			portalBaseURL:       h.portalBaseURL,
+		},
+		&codeCommand{portalAccessService: h.portalAccessService, portalBaseURL: h.portalBaseURL},
+		&recentCommand{messageService: h.messageService, limiter: h.rateLimiter, portalBaseURL: h.portalBaseURL},
+		&consentCommand{convService: h.convService},
+	)
+	registry.Register(&helpCommand{registry: registry, experimentService: h.experimentService})
+	return registry
+}
+
+type pairCommand struct {
+	sessionService    *service.SessionService
+	onboardingService *service.OnboardingService
+}
+
+func (c *pairCommand) Name() string { return "pair" }
+
+// Match requires a code; "/pair" on its own is a regular message.
+func (c *pairCommand) Match(utterance string) (string, bool) {
+	args, ok := MatchCommand(utterance, "/pair")
+	if !ok || args == "" {
+		return "", false
+	}
+	return strings.ToUpper(args), true
+}
+
+func (c *pairCommand) Execute(cc *CommandContext) *KakaoResponse {
+	ctx := cc.Context()
+
+	if cc.Paired() {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoPairAlreadyPaired))
+	}
+
+	result := c.sessionService.VerifyPairingCode(ctx, cc.Args, cc.ConversationKey)
+	if !result.Success {
+		errorMessages := map[string]i18n.Key{
+			"INVALID_CODE":   i18n.KakaoPairInvalidCode,
+			"INTERNAL_ERROR": i18n.KakaoPairInternalError,
+		}
+		key, ok := errorMessages[result.Error]
+		if !ok {
+			key = i18n.KakaoPairFailed
+		}
+		return NewTextResponse(i18n.T(cc.Locale, key))
+	}
+
+	// Publish pairing_complete event
+	session, err := c.sessionService.FindByID(ctx, result.SessionID)
+	if err == nil && session != nil {
+		if err := c.sessionService.PublishPairingComplete(ctx, session, cc.ConversationKey); err != nil {
+			log.Warn().Err(err).Msg("failed to publish pairing_complete event")
+		}
+	}
+
+	resp := NewTextResponse(i18n.T(cc.Locale, i18n.KakaoPairSuccess))
+	onboarding, err := c.onboardingService.Get(ctx, result.AccountID)
+	if err != nil {
+		log.Warn().Err(err).Str("accountId", result.AccountID).Msg("failed to load onboarding settings")
+		return resp
+	}
+	addOnboarding(resp, cc.Locale, onboarding)
+	return resp
+}
+
+// maxQuickReplyLabelLen is the longest quick reply label Kakao shows in full.
+const maxQuickReplyLabelLen = 14
+
+// addOnboarding appends the welcome sequence to a pairing reply: an intro
+// card, the privacy notice and the example prompts as quick replies that send
+// them. Kakao shows up to 3 outputs per reply, which this stays within.
+func addOnboarding(resp *KakaoResponse, locale i18n.Locale, onboarding *model.Onboarding) {
+	if !onboarding.Enabled {
+		return
+	}
+
+	intro := onboarding.Intro
+	if intro == "" {
+		intro = i18n.T(locale, i18n.KakaoOnboardingIntro)
+	}
+	privacy := onboarding.PrivacyNotice
+	if privacy == "" {
+		privacy = i18n.T(locale, i18n.KakaoOnboardingPrivacy)
+	}
+
+	resp.Template.Outputs = append(resp.Template.Outputs,
+		KakaoOutput{TextCard: &KakaoTextCard{
+			Title:       i18n.T(locale, i18n.KakaoOnboardingTitle),
+			Description: intro,
+		}},
+		KakaoOutput{SimpleText: &KakaoSimpleText{Text: privacy}},
+	)
+	for _, prompt := range onboarding.ExamplePrompts {
+		resp.Template.QuickReplies = append(resp.Template.QuickReplies, KakaoQuickReply{
+			Label:       truncateRunes(prompt, maxQuickReplyLabelLen),
+			Action:      "message",
+			MessageText: prompt,
+		})
+	}
+}
+
+func (c *pairCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpPair)
+}
+
+type unpairCommand struct {
+	convService *service.ConversationService
+}
+
+func (c *unpairCommand) Name() string { return "unpair" }
+
+func (c *unpairCommand) Match(utterance string) (string, bool) {
+	return "", utterance == "/unpair"
+}
+
+func (c *unpairCommand) Execute(cc *CommandContext) *KakaoResponse {
+	if !cc.Paired() {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoUnpairNotPaired))
+	}
+
+	if err := c.convService.UpdateState(cc.Context(), cc.ConversationKey, model.PairingStateUnpaired, nil); err != nil {
+		log.Error().Err(err).Msg("failed to unpair")
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoUnpairFailed))
+	}
+
+	return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoUnpairSuccess))
+}
+
+func (c *unpairCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpUnpair)
+}
+
+// archiveCommand pauses relaying without unpairing. The next regular message
+// from the user reactivates the conversation.
+type archiveCommand struct {
+	convService *service.ConversationService
+	events      *service.SessionEvents
+}
+
+func (c *archiveCommand) Name() string { return "archive" }
+
+func (c *archiveCommand) Match(utterance string) (string, bool) {
+	return "", utterance == "/archive"
+}
+
+func (c *archiveCommand) Execute(cc *CommandContext) *KakaoResponse {
+	if !cc.Paired() || cc.Conversation.AccountID == nil {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoArchiveNotPaired))
+	}
+	if cc.Archived() {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoArchiveAlready))
+	}
+
+	ctx := cc.Context()
+	archived, err := c.convService.Archive(ctx, cc.ConversationKey)
+	if err != nil || !archived {
+		log.Error().Err(err).Msg("failed to archive")
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoArchiveFailed))
+	}
+	c.events.ConversationArchived(ctx, *cc.Conversation.AccountID, cc.ConversationKey)
+
+	return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoArchiveSuccess))
+}
+
+func (c *archiveCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpArchive)
+}
+
+// statusCommand shows a card with the connection, today's usage, the
+// account's remaining rate limit and the paired plugin, plus a portal access
+// code and link. Parts that cannot be loaded are left out.
+type statusCommand struct {
+	messageService      *service.MessageService
+	sessionService      *service.SessionService
+	accountUsageService *service.AccountUsageService
+	portalAccessService *service.PortalAccessService
+	portalBaseURL       string
+}
+
+func (c *statusCommand) Name() string { return "status" }
+
+func (c *statusCommand) Match(utterance string) (string, bool) {
+	return "", utterance == "/status"
+}
+
+func (c *statusCommand) Execute(cc *CommandContext) *KakaoResponse {
+	ctx := cc.Context()
+	conv := cc.Conversation
+	if !cc.Paired() || conv.AccountID == nil {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoStatusNotPaired))
+	}
+	accountID := *conv.AccountID
+
+	var sections []string
+
+	stats, err := c.messageService.GetQuickStats(ctx, accountID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get quick stats for status command")
+	} else {
+		sections = append(sections, i18n.T(cc.Locale, i18n.KakaoStatusStats,
+			stats.InboundToday,
+			stats.OutboundToday,
+			stats.OutboundFailed,
+			stats.InboundTotal,
+			stats.OutboundTotal,
+		))
+	}
+
+	var details []string
+	if usage, err := c.accountUsageService.GetRateLimit(ctx, accountID); err != nil {
+		log.Warn().Err(err).Msg("failed to get rate limit for status command")
+	} else {
+		details = append(details, i18n.T(cc.Locale, i18n.KakaoStatusRateLimit, usage.Remaining, usage.Limit))
+	}
+
+	if session, err := c.sessionService.FindPairedSession(ctx, cc.ConversationKey); err != nil {
+		log.Warn().Err(err).Msg("failed to get paired session for status command")
+	} else if session != nil {
+		version := i18n.T(cc.Locale, i18n.KakaoStatusUnknown)
+		if session.PluginVersion != nil {
+			version = *session.PluginVersion
+		}
+		details = append(details, i18n.T(cc.Locale, i18n.KakaoStatusDevice, version))
+	}
+
+	code, waitMinutes, err := issuePortalCode(cc, c.portalAccessService)
+	switch {
+	case err != nil:
+		log.Error().Err(err).Msg("failed to generate portal access code for status command")
+	case code == nil:
+		details = append(details, i18n.T(cc.Locale, i18n.KakaoStatusPortalWait, waitMinutes))
+	default:
+		expiresIn := int(time.Until(code.ExpiresAt).Minutes())
+		details = append(details, i18n.T(cc.Locale, i18n.KakaoStatusPortalCode, code.Code, expiresIn))
+	}
+	if len(details) > 0 {
+		sections = append(sections, strings.Join(details, "\n"))
+	}
+
+	pairedAt := i18n.T(cc.Locale, i18n.KakaoStatusUnknown)
+	if conv.PairedAt != nil {
+		pairedAt = conv.PairedAt.Format("2006-01-02 15:04:05")
+	}
+	sections = append(sections, i18n.T(cc.Locale, i18n.KakaoStatusPairedAt, pairedAt))
+
+	title := i18n.T(cc.Locale, i18n.KakaoStatusTitle)
+	if cc.Archived() {
+		title = i18n.T(cc.Locale, i18n.KakaoStatusArchived)
+	}
+	card := &KakaoTextCard{
+		Title:       title,
+		Description: strings.Join(sections, "\n\n"),
+	}
+	if c.portalBaseURL != "" {
+		card.Buttons = []KakaoButton{{
+			Label:      i18n.T(cc.Locale, i18n.KakaoStatusPortalButton),
+			Action:     "webLink",
+			WebLinkURL: c.portalBaseURL + "/portal/code",
+		}}
+	}
+	return NewTextCardResponse(card)
+}
+
+func (c *statusCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpStatus)
+}
+
+// issuePortalCode returns the conversation's portal access code, creating one
+// if it has none. Codes are limited to 3 per 5 minutes per conversation; when
+// the limit is reached the code is nil and the minutes to wait are returned.
+func issuePortalCode(cc *CommandContext, portalAccessService *service.PortalAccessService) (*model.PortalAccessCode, int, error) {
+	ctx := cc.Context()
+
+	allowed, resetAt := portalAccessService.CheckCodeGenerationLimit(ctx, cc.ConversationKey)
+	if !allowed {
+		return nil, int(time.Until(resetAt).Minutes()) + 1, nil
+	}
+
+	code, err := portalAccessService.GenerateCode(ctx, cc.ConversationKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Audit log
+	auditEvent := audit.Event{
+		Type: audit.EventCodeGenerate,
+		Details: map[string]interface{}{
+			"conversationKey": cc.ConversationKey,
+			"code":            code.Code,
+			"expiresAt":       code.ExpiresAt,
+		},
+	}
+	if cc.Conversation.AccountID != nil {
+		auditEvent.AccountID = *cc.Conversation.AccountID
+	}
+	audit.LogFromRequest(cc.Request, auditEvent)
+
+	return code, 0, nil
+}
+
+type codeCommand struct {
+	portalAccessService *service.PortalAccessService
+	portalBaseURL       string
+}
+
+func (c *codeCommand) Name() string { return "code" }
+
+func (c *codeCommand) Match(utterance string) (string, bool) {
+	return "", utterance == "/code"
+}
+
+func (c *codeCommand) Execute(cc *CommandContext) *KakaoResponse {
+	if !cc.Paired() {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoCodeNotPaired))
+	}
+
+	code, minutesLeft, err := issuePortalCode(cc, c.portalAccessService)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate portal access code")
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoCodeFailed))
+	}
+	if code == nil {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoCodeRateLimited, minutesLeft))
+	}
+
+	expiresIn := int(time.Until(code.ExpiresAt).Minutes())
+	msg := i18n.T(cc.Locale, i18n.KakaoCodeIssued, code.Code, expiresIn)
+	if c.portalBaseURL != "" {
+		msg += i18n.T(cc.Locale, i18n.KakaoCodePortalURL, c.portalBaseURL)
+	}
+	return NewTextResponse(msg)
+}
+
+func (c *codeCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpCode)
+}
+
+const (
+	// recentMessageCount is how many messages /recent lists; a Kakao list
+	// card holds at most 5 items.
+	recentMessageCount = 5
+	// maxRecentPreviewLen keeps each item on one line of the list card.
+	maxRecentPreviewLen = 40
+)
+
+// recentCommand lists the last messages exchanged in the conversation as a
+// list card, oldest first. It is limited to 3 uses per minute per
+// conversation.
+type recentCommand struct {
+	messageService *service.MessageService
+	limiter        ratelimit.Limiter
+	portalBaseURL  string
+}
+
+func (c *recentCommand) Name() string { return "recent" }
+
+func (c *recentCommand) Match(utterance string) (string, bool) {
+	return "", utterance == "/recent"
+}
+
+func (c *recentCommand) Execute(cc *CommandContext) *KakaoResponse {
+	if !cc.Paired() {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoRecentNotPaired))
+	}
+
+	ctx := cc.Context()
+	limit := c.limiter.Take(ctx, "kakao_recent", cc.ConversationKey, ratelimit.Per(3, time.Minute))
+	if !limit.Allowed {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoRecentRateLimited, int(limit.RetryAfter/time.Second)+1))
+	}
+
+	result, err := c.messageService.GetConversationMessages(ctx, service.ConversationMessagesParams{
+		ConversationKey: cc.ConversationKey,
+		Limit:           recentMessageCount,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get recent messages")
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoRecentFailed))
+	}
+	if len(result.Messages) == 0 {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoRecentEmpty))
+	}
+
+	loc := model.LoadTimezone("")
+	items := make([]KakaoListItem, len(result.Messages))
+	for i, msg := range result.Messages {
+		sender := i18n.KakaoRecentOutbound
+		if msg.Direction == "inbound" {
+			sender = i18n.KakaoRecentInbound
+		}
+		text := truncateRunes(messagePreview(msg), maxRecentPreviewLen)
+		if text == "" {
+			text = i18n.T(cc.Locale, i18n.KakaoRecentNoText)
+		}
+		// Newest first from the service; list them in reading order
+		items[len(items)-1-i] = KakaoListItem{
+			Title:       text,
+			Description: i18n.T(cc.Locale, sender, msg.CreatedAt.In(loc).Format("01-02 15:04")),
+		}
+	}
+
+	card := &KakaoListCard{
+		Header: KakaoListItemHeader{Title: i18n.T(cc.Locale, i18n.KakaoRecentTitle)},
+		Items:  items,
+	}
+	if result.HasMore && c.portalBaseURL != "" {
+		card.Buttons = []KakaoButton{{
+			Label:      i18n.T(cc.Locale, i18n.KakaoRecentMore),
+			Action:     "webLink",
+			WebLinkURL: c.portalBaseURL + "/portal/code",
+		}}
+	}
+	return NewListCardResponse(card)
+}
+
+func (c *recentCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpRecent)
+}
+
+// messagePreview is the text of a stored message on one line: the user's
+// text for inbound messages, the first text of the reply for outbound ones.
+// It is empty for media and for content that was not stored.
+func messagePreview(msg service.MessageHistoryItem) string {
+	if msg.Content == nil {
+		return ""
+	}
+
+	var text string
+	if msg.Direction == "inbound" {
+		var normalized model.NormalizedMessage
+		if err := json.Unmarshal(*msg.Content, &normalized); err == nil {
+			text = normalized.Text
+		}
+	} else {
+		var reply KakaoResponse
+		if err := json.Unmarshal(*msg.Content, &reply); err == nil && reply.Template != nil {
+			for _, output := range reply.Template.Outputs {
+				switch {
+				case output.SimpleText != nil:
+					text = output.SimpleText.Text
+				case output.TextCard != nil:
+					text = output.TextCard.Title + " " + output.TextCard.Description
+				case output.ListCard != nil:
+					text = output.ListCard.Header.Title
+				}
+				if strings.TrimSpace(text) != "" {
+					break
+				}
+			}
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncateRunes shortens s to at most maxLen characters, ending in "…" when
+// it was cut.
+func truncateRunes(s string, maxLen int) string {
+	if runes := []rune(s); len(runes) > maxLen {
+		return string(runes[:maxLen-1]) + "…"
+	}
+	return s
+}
+
+type consentCommand struct {
+	convService *service.ConversationService
+}
+
+func (c *consentCommand) Name() string { return "consent" }
+
+// Match normalizes the answer to "agree" or "disagree"; anything else asks
+// again.
+func (c *consentCommand) Match(utterance string) (string, bool) {
+	args, ok := MatchCommand(utterance, "/consent")
+	if !ok {
+		return "", false
+	}
+	switch answer := strings.ToLower(args); answer {
+	case "agree", "disagree":
+		return answer, true
+	}
+	return "", true
+}
+
+func (c *consentCommand) Execute(cc *CommandContext) *KakaoResponse {
+	if !cc.Paired() {
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoConsentNotPaired))
+	}
+
+	var consent model.ContentConsent
+	var reply i18n.Key
+	switch cc.Args {
+	case "agree":
+		consent, reply = model.ContentConsentGranted, i18n.KakaoConsentGranted
+	case "disagree":
+		consent, reply = model.ContentConsentDenied, i18n.KakaoConsentDenied
+	default:
+		return NewConsentPromptResponse(cc.Locale)
+	}
+
+	if err := c.convService.SetContentConsent(cc.Context(), cc.ConversationKey, consent); err != nil {
+		log.Error().Err(err).Msg("failed to update content consent")
+		return NewTextResponse(i18n.T(cc.Locale, i18n.KakaoConsentFailed))
+	}
+	return NewTextResponse(i18n.T(cc.Locale, reply))
+}
+
+func (c *consentCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpConsent)
+}
+
+// helpCommand lists the help lines of every command in its registry,
+// including ones registered after it.
+type helpCommand struct {
+	registry          *CommandRegistry
+	experimentService *service.ExperimentService
+}
+
+func (c *helpCommand) Name() string { return "help" }
+
+func (c *helpCommand) Match(utterance string) (string, bool) {
+	return "", utterance == "/help"
+}
+
+func (c *helpCommand) Execute(cc *CommandContext) *KakaoResponse {
+	text := i18n.T(cc.Locale, i18n.KakaoHelp)
+	for _, line := range c.registry.Help(cc.Locale) {
+		text += "\n• " + line
+	}
+	return NewTextResponse(c.experimentService.Text(cc.Context(), service.ExperimentHelp, cc.ConversationKey, text))
+}
+
+func (c *helpCommand) Help(locale i18n.Locale) string {
+	return i18n.T(locale, i18n.KakaoHelpHelp)
+}
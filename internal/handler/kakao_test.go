@@ -1,86 +1,123 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/logging"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/openclaw/relay-server-go/internal/sse"
 )
 
-func TestParseCommand(t *testing.T) {
+func TestCommandRegistryMatch(t *testing.T) {
+	registry := defaultCommands(&KakaoHandler{})
+
 	tests := []struct {
 		name      string
 		utterance string
-		expected  *Command
+		command   string
+		args      string
 	}{
 		{
 			name:      "parse /pair command with code",
 			utterance: "/pair ABCD-1234",
-			expected:  &Command{Type: "PAIR", Code: "ABCD-1234"},
+			command:   "pair",
+			args:      "ABCD-1234",
 		},
 		{
 			name:      "parse /pair command with lowercase code",
 			utterance: "/pair abcd-1234",
-			expected:  &Command{Type: "PAIR", Code: "ABCD-1234"},
+			command:   "pair",
+			args:      "ABCD-1234",
 		},
 		{
 			name:      "parse /pair command with extra spaces",
 			utterance: "/pair   ABCD-1234  ",
-			expected:  &Command{Type: "PAIR", Code: "ABCD-1234"},
+			command:   "pair",
+			args:      "ABCD-1234",
 		},
 		{
 			name:      "reject /pair without code",
 			utterance: "/pair ",
-			expected:  nil,
 		},
 		{
 			name:      "reject /pair without space",
 			utterance: "/pairABCD",
-			expected:  nil,
 		},
 		{
 			name:      "parse /unpair command",
 			utterance: "/unpair",
-			expected:  &Command{Type: "UNPAIR"},
+			command:   "unpair",
 		},
 		{
 			name:      "parse /status command",
 			utterance: "/status",
-			expected:  &Command{Type: "STATUS"},
+			command:   "status",
 		},
 		{
 			name:      "parse /help command",
 			utterance: "/help",
-			expected:  &Command{Type: "HELP"},
+			command:   "help",
+		},
+		{
+			name:      "parse /consent agree",
+			utterance: "/consent agree",
+			command:   "consent",
+			args:      "agree",
+		},
+		{
+			name:      "parse /consent disagree case-insensitively",
+			utterance: "/consent Disagree",
+			command:   "consent",
+			args:      "disagree",
+		},
+		{
+			name:      "parse /consent without an answer",
+			utterance: "/consent",
+			command:   "consent",
 		},
 		{
 			name:      "return nil for regular message",
 			utterance: "Hello, how are you?",
-			expected:  nil,
 		},
 		{
 			name:      "return nil for unknown command",
 			utterance: "/unknown",
-			expected:  nil,
 		},
 		{
 			name:      "trim whitespace from utterance",
 			utterance: "  /help  ",
-			expected:  &Command{Type: "HELP"},
+			command:   "help",
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := parseCommand(tc.utterance)
-			if tc.expected == nil {
-				assert.Nil(t, result)
-			} else {
-				assert.NotNil(t, result)
-				assert.Equal(t, tc.expected.Type, result.Type)
-				assert.Equal(t, tc.expected.Code, result.Code)
+			cmd, args := registry.Match(tc.utterance)
+			if tc.command == "" {
+				assert.Nil(t, cmd)
+				return
 			}
+			require.NotNil(t, cmd)
+			assert.Equal(t, tc.command, cmd.Name())
+			assert.Equal(t, tc.args, args)
 		})
 	}
 }
@@ -146,3 +183,657 @@ func TestWriteJSON(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), "bad request")
 	})
 }
+
+func TestKakaoHandlerLocale(t *testing.T) {
+	h := &KakaoHandler{defaultLocale: i18n.Korean}
+
+	t.Run("uses the language Kakao reports", func(t *testing.T) {
+		req := &KakaoWebhookRequest{UserRequest: KakaoUserRequest{Lang: "en"}}
+		assert.Equal(t, i18n.English, h.locale(req))
+	})
+
+	t.Run("falls back to the deployment default", func(t *testing.T) {
+		assert.Equal(t, i18n.Korean, h.locale(&KakaoWebhookRequest{}))
+		assert.Equal(t, i18n.Korean, h.locale(&KakaoWebhookRequest{UserRequest: KakaoUserRequest{Lang: "ja"}}))
+	})
+}
+
+func TestKakaoHandlerWebhookBodyLimit(t *testing.T) {
+	h := &KakaoHandler{inboundLimits: service.InboundLimits{MaxBodyBytes: 64}}
+	body := `{"userRequest":{"utterance":"` + strings.Repeat("x", 100) + `"}}`
+
+	t.Run("rejects declared oversized body", func(t *testing.T) {
+		before := service.GetInboundLimitStats().Rejected
+		req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		h.Webhook(rec, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, before+1, service.GetInboundLimitStats().Rejected)
+	})
+
+	t.Run("rejects oversized body without content length", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+		req.ContentLength = -1
+		rec := httptest.NewRecorder()
+
+		h.Webhook(rec, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+}
+
+// upsertConversationRepo returns the stored conversation from Upsert, as the
+// webhook does for a known user.
+type upsertConversationRepo struct {
+	stubConversationRepo
+}
+
+func (s *upsertConversationRepo) Upsert(ctx context.Context, params model.UpsertConversationParams) (*model.ConversationMapping, error) {
+	return s.convs[params.ConversationKey], nil
+}
+
+func TestKakaoHandlerWebhookConsentPrompt(t *testing.T) {
+	accountID := "acc-1"
+	convRepo := &upsertConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", AccountID: &accountID, State: model.PairingStatePaired},
+	}}}
+	// No message service: the prompt must be sent before anything is stored
+	h := &KakaoHandler{
+		convService: service.NewConversationService(convRepo, nil),
+		accountUsageService: service.NewAccountUsageService(
+			&stubAccountRepo{account: &model.Account{ID: accountID}}, nil,
+		),
+		defaultLocale: i18n.Korean,
+		consentPrompt: true,
+	}
+
+	body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"hello","user":{"id":"user"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Webhook(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp KakaoResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Template)
+	assert.Equal(t, i18n.T(i18n.Korean, i18n.KakaoConsentPrompt), resp.Template.Outputs[0].SimpleText.Text)
+	require.Len(t, resp.Template.QuickReplies, 2)
+	assert.Equal(t, "/consent agree", resp.Template.QuickReplies[0].MessageText)
+	assert.Equal(t, "/consent disagree", resp.Template.QuickReplies[1].MessageText)
+}
+
+type pingCommand struct{}
+
+func (c *pingCommand) Name() string { return "ping" }
+
+func (c *pingCommand) Match(utterance string) (string, bool) {
+	return MatchCommand(utterance, "/ping")
+}
+
+func (c *pingCommand) Execute(cc *CommandContext) *KakaoResponse {
+	return NewTextResponse("pong " + cc.Args)
+}
+
+func (c *pingCommand) Help(locale i18n.Locale) string {
+	return "/ping - check the bot"
+}
+
+func TestKakaoHandlerRegisterCommand(t *testing.T) {
+	convRepo := &upsertConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", State: model.PairingStateUnpaired},
+	}}}
+	h := &KakaoHandler{
+		convService:       service.NewConversationService(convRepo, nil),
+		experimentService: service.NewExperimentService(nil, nil),
+		defaultLocale:     i18n.English,
+	}
+	h.commands = defaultCommands(h)
+	h.RegisterCommand(&pingCommand{})
+
+	webhook := func(utterance string) string {
+		body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"` + utterance + `","user":{"id":"user"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.Webhook(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp KakaoResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Template)
+		return resp.Template.Outputs[0].SimpleText.Text
+	}
+
+	assert.Equal(t, "pong hi", webhook("/ping hi"))
+
+	help := webhook("/help")
+	assert.True(t, strings.HasPrefix(help, i18n.T(i18n.English, i18n.KakaoHelp)))
+	assert.Contains(t, help, "\n• "+i18n.T(i18n.English, i18n.KakaoHelpPair))
+	assert.Contains(t, help, "\n• /ping - check the bot")
+}
+
+// transitionConversationRepo applies state transitions to the stored
+// conversations.
+type transitionConversationRepo struct {
+	upsertConversationRepo
+}
+
+func (s *transitionConversationRepo) TransitionState(ctx context.Context, key string, from, to model.PairingState) (bool, error) {
+	conv := s.convs[key]
+	if conv == nil || conv.State != from {
+		return false, nil
+	}
+	conv.State = to
+	return true, nil
+}
+
+type recordingPublisher struct {
+	events []sse.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, channel string, event sse.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestKakaoHandlerArchive(t *testing.T) {
+	accountID := "acc-1"
+	convRepo := &transitionConversationRepo{upsertConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", AccountID: &accountID, State: model.PairingStatePaired},
+	}}}}
+	publisher := &recordingPublisher{}
+	queue := &recordingInboundQueue{}
+	// No message service: the consent prompt answers the reactivating message
+	h := &KakaoHandler{
+		convService: service.NewConversationService(convRepo, nil),
+		accountUsageService: service.NewAccountUsageService(
+			&stubAccountRepo{account: &model.Account{ID: accountID}}, nil,
+		),
+		events:        service.NewSessionEvents(publisher),
+		defaultLocale: i18n.English,
+		inboundQueue:  queue,
+		consentPrompt: true,
+	}
+	h.commands = defaultCommands(h)
+
+	webhook := func(utterance string) string {
+		body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"` + utterance + `","user":{"id":"user"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.Webhook(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp KakaoResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Template)
+		return resp.Template.Outputs[0].SimpleText.Text
+	}
+
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoArchiveSuccess), webhook("/archive"))
+	assert.Equal(t, model.PairingStateArchived, convRepo.convs["ch:user"].State)
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, service.EventConversationArchived, publisher.events[0].Type)
+	assert.Equal(t, accountID, publisher.events[0].AccountID)
+
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoArchiveAlready), webhook("/archive"))
+	assert.Len(t, publisher.events, 1)
+
+	// A regular message reactivates the conversation and is not queued on
+	// the fast path while archived
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoConsentPrompt), webhook("hello"))
+	assert.Equal(t, model.PairingStatePaired, convRepo.convs["ch:user"].State)
+	assert.Empty(t, queue.jobs)
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, service.EventConversationReactivated, publisher.events[1].Type)
+}
+
+type recordingInboundQueue struct {
+	service.InboundQueue
+	jobs []*service.InboundJob
+}
+
+func (q *recordingInboundQueue) Enqueue(ctx context.Context, job *service.InboundJob) error {
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func TestKakaoHandlerWebhookFastPath(t *testing.T) {
+	queue := &recordingInboundQueue{}
+	// No services: the fast path must not touch the database
+	h := &KakaoHandler{
+		defaultLocale: i18n.Korean,
+		callbackTTL:   time.Minute,
+		inboundQueue:  queue,
+	}
+
+	body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"hello","callbackUrl":"https://bot-api.kakao.com/cb","lang":"en","user":{"id":"user"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Webhook(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp KakaoResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.UseCallback)
+
+	require.Len(t, queue.jobs, 1)
+	job := queue.jobs[0]
+	assert.Equal(t, "ch:user", job.ConversationKey())
+	require.NotNil(t, job.CallbackURL)
+	assert.Equal(t, "https://bot-api.kakao.com/cb", *job.CallbackURL)
+	assert.Equal(t, "en", job.Lang)
+	assert.NotNil(t, job.NormalizedMessage)
+}
+
+func TestKakaoHandlerWebhookPaused(t *testing.T) {
+	accountID := "acc-1"
+	pausedAt := time.Now()
+	autoReply := "On vacation until Monday"
+	convRepo := &upsertConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", AccountID: &accountID, State: model.PairingStatePaired, DeliveryPausedAt: &pausedAt},
+	}}}
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("Create", mock.Anything, mock.Anything).Return(&model.InboundMessage{ID: "msg-1"}, nil)
+	queue := &recordingInboundQueue{}
+	publisher := &recordingPublisher{}
+	h := &KakaoHandler{
+		convService:    service.NewConversationService(convRepo, nil),
+		messageService: service.NewMessageService(inboundRepo, nil, nil, nil, nil),
+		accountUsageService: service.NewAccountUsageService(
+			&stubAccountRepo{account: &model.Account{ID: accountID}}, nil,
+		),
+		profileService: service.NewKakaoProfileService(nil, nil, nil, 0),
+		broker:         publisher,
+		defaultLocale:  i18n.English,
+		inboundQueue:   queue,
+	}
+
+	webhook := func() string {
+		body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"hello","user":{"id":"user"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.Webhook(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp KakaoResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Template)
+		return resp.Template.Outputs[0].SimpleText.Text
+	}
+
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoDeliveryPaused), webhook())
+
+	convRepo.convs["ch:user"].PauseAutoReply = &autoReply
+	assert.Equal(t, autoReply, webhook())
+
+	// Stored inline and held back from the account
+	inboundRepo.AssertNumberOfCalls(t, "Create", 2)
+	assert.Empty(t, queue.jobs)
+	assert.Empty(t, publisher.events)
+}
+
+// recordingCallbacks records the notices sent through callbacks.
+type recordingCallbacks struct {
+	sent map[string]*KakaoResponse
+}
+
+func (c *recordingCallbacks) SendCallback(ctx context.Context, callbackURL string, payload any) (*model.CallbackResponse, error) {
+	c.sent[callbackURL] = payload.(*KakaoResponse)
+	return &model.CallbackResponse{Status: http.StatusOK}, nil
+}
+
+func TestKakaoHandlerProcessInboundUnpaired(t *testing.T) {
+	convRepo := &upsertConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", State: model.PairingStateUnpaired},
+	}}}
+	callbacks := &recordingCallbacks{sent: map[string]*KakaoResponse{}}
+	// No message service: nothing may be stored for an unpaired conversation
+	h := &KakaoHandler{
+		convService:       service.NewConversationService(convRepo, nil),
+		experimentService: service.NewExperimentService(nil, nil),
+		callbacks:         callbacks,
+		defaultLocale:     i18n.Korean,
+	}
+
+	callbackURL := "https://bot-api.kakao.com/cb"
+	expires := time.Now().Add(time.Minute)
+	err := h.ProcessInbound(context.Background(), &service.InboundJob{
+		ChannelID: "ch", UserKey: "user", Lang: "en", CallbackURL: &callbackURL, CallbackExpiresAt: &expires,
+	})
+	require.NoError(t, err)
+	require.Contains(t, callbacks.sent, callbackURL, "the greeting is sent through the callback")
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoUnpairedGreeting), callbacks.sent[callbackURL].Template.Outputs[0].SimpleText.Text)
+
+	expired := "https://bot-api.kakao.com/expired"
+	expires = time.Now().Add(-time.Second)
+	err = h.ProcessInbound(context.Background(), &service.InboundJob{
+		ChannelID: "ch", UserKey: "user", CallbackURL: &expired, CallbackExpiresAt: &expires,
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, callbacks.sent, expired)
+}
+
+func TestKakaoHandlerWebhookSuspendedAccount(t *testing.T) {
+	accountID := "acc-1"
+	disabledAt := time.Now()
+	convRepo := &upsertConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", AccountID: &accountID, State: model.PairingStatePaired},
+	}}}
+	queue := &recordingInboundQueue{}
+	// No message service: nothing may be stored for a disabled account
+	h := &KakaoHandler{
+		convService: service.NewConversationService(convRepo, nil),
+		accountUsageService: service.NewAccountUsageService(
+			&stubAccountRepo{account: &model.Account{ID: accountID, DisabledAt: &disabledAt}}, nil,
+		),
+		defaultLocale: i18n.English,
+		inboundQueue:  queue,
+	}
+
+	body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"hello","user":{"id":"user"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Webhook(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp KakaoResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Template)
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoAccountSuspended), resp.Template.Outputs[0].SimpleText.Text)
+	assert.Empty(t, queue.jobs)
+
+	t.Run("queued message is dropped", func(t *testing.T) {
+		err := h.ProcessInbound(context.Background(), &service.InboundJob{ChannelID: "ch", UserKey: "user"})
+		assert.NoError(t, err)
+	})
+}
+
+func (q *recordingInboundQueue) RetryLater(ctx context.Context, job *service.InboundJob) (bool, error) {
+	q.jobs = append(q.jobs, job)
+	return true, nil
+}
+
+type failingUpsertConversationRepo struct {
+	stubConversationRepo
+}
+
+func (s *failingUpsertConversationRepo) Upsert(ctx context.Context, params model.UpsertConversationParams) (*model.ConversationMapping, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func TestKakaoHandlerWebhookDegraded(t *testing.T) {
+	webhook := func(h *KakaoHandler, utterance string) *KakaoResponse {
+		body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"` + utterance + `","user":{"id":"user"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.Webhook(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp KakaoResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return &resp
+	}
+	newHandler := func(reply DegradedReply, queue service.InboundQueue) *KakaoHandler {
+		h := &KakaoHandler{
+			convService:   service.NewConversationService(&failingUpsertConversationRepo{}, nil),
+			defaultLocale: i18n.English,
+			inboundQueue:  queue,
+			degradedReply: reply,
+		}
+		h.commands = defaultCommands(h)
+		return h
+	}
+
+	t.Run("apology with retry", func(t *testing.T) {
+		queue := &recordingInboundQueue{}
+		before := service.GetInboundDegradedStats().Responses
+
+		resp := webhook(newHandler(DegradedReplyApology, queue), "hello")
+
+		require.NotNil(t, resp.Template)
+		assert.Equal(t, i18n.T(i18n.English, i18n.KakaoDegradedRetrying), resp.Template.Outputs[0].SimpleText.Text)
+		require.Len(t, queue.jobs, 1)
+		assert.Equal(t, "ch:user", queue.jobs[0].ConversationKey())
+		assert.Equal(t, before+1, service.GetInboundDegradedStats().Responses)
+	})
+
+	t.Run("commands are not retried", func(t *testing.T) {
+		queue := &recordingInboundQueue{}
+
+		resp := webhook(newHandler(DegradedReplyApology, queue), "/status")
+
+		require.NotNil(t, resp.Template)
+		assert.Equal(t, i18n.T(i18n.English, i18n.KakaoDegradedFailed), resp.Template.Outputs[0].SimpleText.Text)
+		assert.Empty(t, queue.jobs)
+	})
+
+	t.Run("silent", func(t *testing.T) {
+		resp := webhook(newHandler(DegradedReplySilent, nil), "hello")
+
+		assert.True(t, resp.UseCallback)
+		assert.Nil(t, resp.Template)
+	})
+}
+
+func TestKakaoHandlerWebhookLogsNoCodes(t *testing.T) {
+	var out bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(logging.NewRedactWriter(&out))
+	t.Cleanup(func() { log.Logger = previous })
+
+	h := &KakaoHandler{
+		convService:   service.NewConversationService(&failingUpsertConversationRepo{}, nil),
+		defaultLocale: i18n.English,
+	}
+	h.commands = defaultCommands(h)
+
+	body := `{"bot":{"id":"ch"},"userRequest":{"utterance":"/pair ABCD-1234","user":{"id":"user"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", strings.NewReader(body))
+	h.Webhook(httptest.NewRecorder(), req)
+
+	assert.NotContains(t, out.String(), "1234")
+	var received map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var event map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		if event["message"] == "received kakao webhook" {
+			received = event
+		}
+	}
+	require.NotNil(t, received)
+	assert.Equal(t, "pair", received["command"])
+	assert.NotContains(t, received, "utterance")
+}
+
+type pairedSessionRepo struct {
+	repository.SessionRepository
+	session *model.Session
+}
+
+func (s *pairedSessionRepo) FindPairedByConversationKey(ctx context.Context, conversationKey string) (*model.Session, error) {
+	return s.session, nil
+}
+
+type activePortalCodeRepo struct {
+	repository.PortalAccessCodeRepository
+	code *model.PortalAccessCode
+}
+
+func (s *activePortalCodeRepo) FindActiveByConversationKey(ctx context.Context, conversationKey string) (*model.PortalAccessCode, error) {
+	return s.code, nil
+}
+
+type stubAccountRepo struct {
+	repository.AccountRepository
+	account *model.Account
+}
+
+func (s *stubAccountRepo) FindByID(ctx context.Context, id string) (*model.Account, error) {
+	return s.account, nil
+}
+
+func TestStatusCommand(t *testing.T) {
+	accountID := "acc-1"
+	pluginVersion := "1.4.0"
+
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("CountByAccountID", mock.Anything, accountID).Return(12, nil)
+	inboundRepo.On("CountByAccountIDSince", mock.Anything, accountID, mock.Anything).Return(3, nil)
+	outboundRepo := new(mockOutboundRepo)
+	outboundRepo.On("CountByAccountID", mock.Anything, accountID).Return(10, nil)
+	outboundRepo.On("CountByAccountIDSince", mock.Anything, accountID, mock.Anything).Return(2, nil)
+	outboundRepo.On("CountByAccountIDAndStatus", mock.Anything, accountID, model.OutboundStatusFailed).Return(1, nil)
+
+	limiter := ratelimit.NewMemoryLimiter()
+	cmd := &statusCommand{
+		messageService: service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil),
+		sessionService: service.NewSessionService(nil, &pairedSessionRepo{session: &model.Session{PluginVersion: &pluginVersion}}, nil, nil, nil, nil, 0, ""),
+		accountUsageService: service.NewAccountUsageService(
+			&stubAccountRepo{account: &model.Account{ID: accountID, RateLimitPerMin: 30}}, limiter,
+		),
+		portalAccessService: service.NewPortalAccessService(
+			&activePortalCodeRepo{code: &model.PortalAccessCode{Code: "ABCD-EFGH", ExpiresAt: time.Now().Add(20 * time.Minute)}},
+			nil, nil, limiter,
+		),
+		portalBaseURL: "https://relay.example.com",
+	}
+	execute := func() *KakaoResponse {
+		return cmd.Execute(&CommandContext{
+			Request:         httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", nil),
+			Conversation:    &model.ConversationMapping{ConversationKey: "ch:user", AccountID: &accountID, State: model.PairingStatePaired},
+			ConversationKey: "ch:user",
+			Locale:          i18n.English,
+		})
+	}
+
+	resp := execute()
+
+	require.NotNil(t, resp.Template)
+	card := resp.Template.Outputs[0].TextCard
+	require.NotNil(t, card)
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoStatusTitle), card.Title)
+	assert.Contains(t, card.Description, i18n.T(i18n.English, i18n.KakaoStatusStats, 3, 2, 1, 12, 10))
+	assert.Contains(t, card.Description, i18n.T(i18n.English, i18n.KakaoStatusRateLimit, 30, 30))
+	assert.Contains(t, card.Description, i18n.T(i18n.English, i18n.KakaoStatusDevice, "1.4.0"))
+	assert.Contains(t, card.Description, "ABCD-EFGH")
+	require.Len(t, card.Buttons, 1)
+	assert.Equal(t, "webLink", card.Buttons[0].Action)
+	assert.Equal(t, "https://relay.example.com/portal/code", card.Buttons[0].WebLinkURL)
+
+	t.Run("portal code is rate limited", func(t *testing.T) {
+		execute()
+		execute()
+
+		card := execute().Template.Outputs[0].TextCard
+		require.NotNil(t, card)
+		assert.NotContains(t, card.Description, "ABCD-EFGH")
+		assert.Contains(t, card.Description, "🔑")
+	})
+}
+
+type stubTimelineRepo struct {
+	repository.MessageTimelineRepository
+	messages []model.TimelineMessage
+}
+
+func (s *stubTimelineRepo) FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.TimelineMessage, error) {
+	return s.messages, nil
+}
+
+func TestRecentCommand(t *testing.T) {
+	raw := func(s string) *json.RawMessage {
+		msg := json.RawMessage(s)
+		return &msg
+	}
+	now := time.Now()
+	timeline := &stubTimelineRepo{messages: []model.TimelineMessage{
+		{ID: "out-2", Direction: "outbound", Content: raw(`{"version":"2.0","template":{"outputs":[{"simpleText":{"text":"Sure,\nhere is a much longer answer than fits on one line"}}]}}`), CreatedAt: now},
+		{ID: "in-2", Direction: "inbound", Content: raw(`{"version":1,"type":"media","text":""}`), CreatedAt: now.Add(-time.Minute)},
+		{ID: "in-1", Direction: "inbound", Content: raw(`{"version":1,"type":"text","text":"Hello"}`), CreatedAt: now.Add(-2 * time.Minute)},
+	}}
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("CountByConversationKey", mock.Anything, "ch:user").Return(10, nil)
+	outboundRepo := new(mockOutboundRepo)
+	outboundRepo.On("CountByConversationKey", mock.Anything, "ch:user").Return(8, nil)
+
+	cmd := &recentCommand{
+		messageService: service.NewMessageService(inboundRepo, outboundRepo, timeline, nil, nil),
+		limiter:        ratelimit.NewMemoryLimiter(),
+		portalBaseURL:  "https://relay.example.com",
+	}
+	accountID := "acc-1"
+	execute := func(state model.PairingState) *KakaoResponse {
+		return cmd.Execute(&CommandContext{
+			Request:         httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", nil),
+			Conversation:    &model.ConversationMapping{ConversationKey: "ch:user", AccountID: &accountID, State: state},
+			ConversationKey: "ch:user",
+			Locale:          i18n.English,
+		})
+	}
+
+	resp := execute(model.PairingStatePaired)
+
+	card := resp.Template.Outputs[0].ListCard
+	require.NotNil(t, card)
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoRecentTitle), card.Header.Title)
+	require.Len(t, card.Items, 3)
+	assert.Equal(t, "Hello", card.Items[0].Title)
+	assert.True(t, strings.HasPrefix(card.Items[0].Description, "Me · "))
+	assert.Equal(t, i18n.T(i18n.English, i18n.KakaoRecentNoText), card.Items[1].Title)
+	assert.Equal(t, "Sure, here is a much longer answer than…", card.Items[2].Title)
+	assert.True(t, strings.HasPrefix(card.Items[2].Description, "OpenClaw · "))
+	require.Len(t, card.Buttons, 1)
+	assert.Equal(t, "https://relay.example.com/portal/code", card.Buttons[0].WebLinkURL)
+
+	t.Run("rate limited per conversation", func(t *testing.T) {
+		execute(model.PairingStatePaired)
+		execute(model.PairingStatePaired)
+
+		resp := execute(model.PairingStatePaired)
+		require.NotNil(t, resp.Template.Outputs[0].SimpleText)
+		assert.Contains(t, resp.Template.Outputs[0].SimpleText.Text, "⏱️")
+	})
+
+	t.Run("unpaired", func(t *testing.T) {
+		resp := execute(model.PairingStateUnpaired)
+		assert.Equal(t, i18n.T(i18n.English, i18n.KakaoRecentNotPaired), resp.Template.Outputs[0].SimpleText.Text)
+	})
+}
+
+func TestAddOnboarding(t *testing.T) {
+	t.Run("defaults with example prompts", func(t *testing.T) {
+		resp := NewTextResponse("paired")
+		addOnboarding(resp, i18n.English, &model.Onboarding{
+			Enabled:        true,
+			ExamplePrompts: []string{"Summarize today's news", "Hi"},
+		})
+
+		outputs := resp.Template.Outputs
+		require.Len(t, outputs, 3)
+		require.NotNil(t, outputs[1].TextCard)
+		assert.Equal(t, i18n.T(i18n.English, i18n.KakaoOnboardingIntro), outputs[1].TextCard.Description)
+		assert.Equal(t, i18n.T(i18n.English, i18n.KakaoOnboardingPrivacy), outputs[2].SimpleText.Text)
+
+		require.Len(t, resp.Template.QuickReplies, 2)
+		assert.Equal(t, "Summarize tod…", resp.Template.QuickReplies[0].Label)
+		assert.Equal(t, "Summarize today's news", resp.Template.QuickReplies[0].MessageText)
+		assert.Equal(t, "Hi", resp.Template.QuickReplies[1].Label)
+	})
+
+	t.Run("custom texts", func(t *testing.T) {
+		resp := NewTextResponse("paired")
+		addOnboarding(resp, i18n.Korean, &model.Onboarding{Enabled: true, Intro: "intro", PrivacyNotice: "privacy"})
+
+		require.Len(t, resp.Template.Outputs, 3)
+		assert.Equal(t, "intro", resp.Template.Outputs[1].TextCard.Description)
+		assert.Equal(t, "privacy", resp.Template.Outputs[2].SimpleText.Text)
+		assert.Empty(t, resp.Template.QuickReplies)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		resp := NewTextResponse("paired")
+		addOnboarding(resp, i18n.Korean, &model.Onboarding{Intro: "intro"})
+
+		assert.Len(t, resp.Template.Outputs, 1)
+	})
+}
@@ -1,6 +1,10 @@
 package handler
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/openclaw/relay-server-go/internal/i18n"
+)
 
 // Kakao Webhook Request Types
 
@@ -16,6 +20,7 @@ type KakaoUserRequest struct {
 	User        KakaoUser              `json:"user"`
 	Utterance   string                 `json:"utterance"`
 	CallbackURL string                 `json:"callbackUrl,omitempty"`
+	Lang        string                 `json:"lang,omitempty"`
 	Params      map[string]string      `json:"params,omitempty"`
 	Block       *KakaoBlock            `json:"block,omitempty"`
 }
@@ -67,6 +72,8 @@ type KakaoTemplate struct {
 type KakaoOutput struct {
 	SimpleText  *KakaoSimpleText  `json:"simpleText,omitempty"`
 	SimpleImage *KakaoSimpleImage `json:"simpleImage,omitempty"`
+	TextCard    *KakaoTextCard    `json:"textCard,omitempty"`
+	ListCard    *KakaoListCard    `json:"listCard,omitempty"`
 }
 
 type KakaoSimpleText struct {
@@ -78,6 +85,35 @@ type KakaoSimpleImage struct {
 	AltText  string `json:"altText,omitempty"`
 }
 
+type KakaoTextCard struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Buttons     []KakaoButton `json:"buttons,omitempty"`
+}
+
+// KakaoListCard shows a header and up to maxListCardItems items.
+type KakaoListCard struct {
+	Header  KakaoListItemHeader `json:"header"`
+	Items   []KakaoListItem     `json:"items"`
+	Buttons []KakaoButton       `json:"buttons,omitempty"`
+}
+
+type KakaoListItemHeader struct {
+	Title string `json:"title"`
+}
+
+type KakaoListItem struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+type KakaoButton struct {
+	Label       string `json:"label"`
+	Action      string `json:"action"`
+	WebLinkURL  string `json:"webLinkUrl,omitempty"`
+	MessageText string `json:"messageText,omitempty"`
+}
+
 type KakaoQuickReply struct {
 	Label       string `json:"label"`
 	Action      string `json:"action"`
@@ -107,6 +143,39 @@ func NewTextResponse(text string) *KakaoResponse {
 	}
 }
 
+func NewTextCardResponse(card *KakaoTextCard) *KakaoResponse {
+	return &KakaoResponse{
+		Version: "2.0",
+		Template: &KakaoTemplate{
+			Outputs: []KakaoOutput{
+				{TextCard: card},
+			},
+		},
+	}
+}
+
+func NewListCardResponse(card *KakaoListCard) *KakaoResponse {
+	return &KakaoResponse{
+		Version: "2.0",
+		Template: &KakaoTemplate{
+			Outputs: []KakaoOutput{
+				{ListCard: card},
+			},
+		},
+	}
+}
+
+// NewConsentPromptResponse asks the user whether message content may be
+// stored, with quick replies that send the matching /consent command.
+func NewConsentPromptResponse(locale i18n.Locale) *KakaoResponse {
+	resp := NewTextResponse(i18n.T(locale, i18n.KakaoConsentPrompt))
+	resp.Template.QuickReplies = []KakaoQuickReply{
+		{Label: i18n.T(locale, i18n.KakaoConsentAgree), Action: "message", MessageText: "/consent agree"},
+		{Label: i18n.T(locale, i18n.KakaoConsentDisagree), Action: "message", MessageText: "/consent disagree"},
+	}
+	return resp
+}
+
 func NewCallbackResponse() *KakaoResponse {
 	return &KakaoResponse{
 		Version:     "2.0",
@@ -123,6 +192,15 @@ func (r *KakaoWebhookRequest) GetPlusfriendUserKey() string {
 	return r.UserRequest.User.ID
 }
 
+// GetAppUserID returns the Kakao app user ID, present only when the channel
+// is linked to a Kakao app.
+func (r *KakaoWebhookRequest) GetAppUserID() string {
+	if id, ok := r.UserRequest.User.Properties["appUserId"].(string); ok {
+		return id
+	}
+	return ""
+}
+
 func (r *KakaoWebhookRequest) GetChannelID() string {
 	if r.Bot != nil && r.Bot.ID != "" {
 		return r.Bot.ID
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -12,50 +16,191 @@ import (
 	"github.com/openclaw/relay-server-go/internal/httputil"
 	"github.com/openclaw/relay-server-go/internal/middleware"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/openclaw/relay-server-go/internal/sse"
 )
 
 type OpenClawHandler struct {
 	messageService *service.MessageService
 	kakaoService   *service.KakaoService
+	convService    *service.ConversationService
+	broker         sse.EventPublisher
+	events         *service.SessionEvents
+	streams        service.ReplyStreamBuffer
+	router         *service.EventRouter
+	replyOnce      bool
 }
 
 func NewOpenClawHandler(
 	messageService *service.MessageService,
 	kakaoService *service.KakaoService,
+	convService *service.ConversationService,
+	broker sse.EventPublisher,
+	streams service.ReplyStreamBuffer,
+	router *service.EventRouter,
+	replyOnce bool,
 ) *OpenClawHandler {
 	return &OpenClawHandler{
 		messageService: messageService,
 		kakaoService:   kakaoService,
+		convService:    convService,
+		broker:         broker,
+		events:         service.NewSessionEvents(broker),
+		streams:        streams,
+		router:         router,
+		replyOnce:      replyOnce,
 	}
 }
 
 func (h *OpenClawHandler) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Post("/reply", h.Reply)
+	r.Post("/reply/stream", h.ReplyStream)
+	r.Post("/messages/ack", h.AckMessages)
+	r.Get("/outbound/scheduled", h.ListScheduledOutbound)
+	r.Delete("/outbound/{id}", h.CancelOutbound)
+	r.Get("/conversations/{key}", h.GetConversation)
+	r.Post("/conversations/{key}/send", h.SendToConversation)
+	r.Post("/conversations/{key}/pause", h.PauseConversation)
+	r.Post("/conversations/{key}/resume", h.ResumeConversation)
+	r.Get("/pairing/list", h.ListPairedUsers)
 	return r
 }
 
+// GET /openclaw/pairing/list?q=&cursor=&limit=
+// Lists the account's paired Kakao users, optionally filtered by a search
+// over nickname, notes and conversation key. v2 pages the list with cursors;
+// v1 returns it whole.
+func (h *OpenClawHandler) ListPairedUsers(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	page, err := parseCursorPage(r)
+	if err != nil {
+		httputil.RespondError(w, r, err)
+		return
+	}
+	conversations, err := h.convService.Search(r.Context(), account.ID, r.URL.Query().Get("q"), page)
+	if err != nil {
+		log.Error().Err(err).Str("accountId", account.ID).Msg("failed to list paired users")
+		httputil.RespondError(w, r, apperrors.Internal("Failed to list paired users"))
+		return
+	}
+	conversations, meta := nextPage(conversations, page, func(conv model.ConversationMapping) model.Cursor {
+		at := conv.FirstSeenAt
+		if conv.PairedAt != nil {
+			at = *conv.PairedAt
+		}
+		return model.Cursor{At: at, ID: conv.ID}
+	})
+
+	users := make([]map[string]any, len(conversations))
+	for i, conv := range conversations {
+		user := formatConversation(conv)
+		user["plusfriendUserKey"] = conv.PlusfriendUserKey
+		if account.ShareKakaoProfile {
+			user["kakaoProfile"] = conv.KakaoProfile()
+		}
+		users[i] = user
+	}
+
+	httputil.RespondWithMeta(w, r, http.StatusOK, map[string]any{
+		"users": users,
+	}, meta)
+}
+
+// GET /openclaw/conversations/{key}
+// Returns the context an agent needs about one of the account's
+// conversations, so it can be fetched on demand instead of being pushed with
+// every event.
+func (h *OpenClawHandler) GetConversation(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	conversationKey := chi.URLParam(r, "key")
+	conv, err := h.convService.FindByKey(r.Context(), conversationKey)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to find conversation")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+	if conv == nil || conv.AccountID == nil || *conv.AccountID != account.ID {
+		httputil.RespondError(w, r, apperrors.NotFound("Conversation"))
+		return
+	}
+
+	health, err := h.convService.Health(r.Context(), []string{conversationKey})
+	if err != nil {
+		log.Error().Err(err).Str("conversationKey", conversationKey).Msg("failed to get conversation health")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+	stats, err := h.messageService.GetConversationStats(r.Context(), conversationKey)
+	if err != nil {
+		log.Error().Err(err).Str("conversationKey", conversationKey).Msg("failed to get conversation stats")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+	locale, err := h.messageService.ConversationLocale(r.Context(), conversationKey)
+	if err != nil {
+		log.Error().Err(err).Str("conversationKey", conversationKey).Msg("failed to get conversation locale")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+
+	activity := health[conversationKey]
+	callbackURL, callbackValid := conv.ValidCallbackURL(time.Now())
+	callbackValid = callbackValid && h.kakaoService.IsAllowedCallbackURL(callbackURL)
+
+	profile := formatConversation(*conv)
+	profile["kakaoChannelId"] = conv.KakaoChannelID
+	profile["plusfriendUserKey"] = conv.PlusfriendUserKey
+	profile["firstSeenAt"] = conv.FirstSeenAt.Format(time.RFC3339)
+	profile["locale"] = locale
+	profile["callbackAvailable"] = callbackValid
+	profile["pauseAutoReply"] = conv.PauseAutoReply
+	profile["lastActivity"] = map[string]any{
+		"lastInboundAt":      formatTime(activity.LastInboundAt),
+		"lastOutboundAt":     formatTime(activity.LastOutboundAt),
+		"queuedCount":        activity.QueuedCount,
+		"recentFailureCount": activity.RecentFailureCount,
+	}
+	profile["stats"] = stats.Messages
+	if account.ShareKakaoProfile {
+		profile["kakaoProfile"] = conv.KakaoProfile()
+	}
+
+	httputil.Respond(w, r, http.StatusOK, profile)
+}
+
 // POST /openclaw/reply
 // Core API: Send reply to Kakao user.
 func (h *OpenClawHandler) Reply(w http.ResponseWriter, r *http.Request) {
 	account := middleware.GetAccount(r.Context())
 	if account == nil {
-		httputil.WriteError(w, apperrors.SessionNotPaired())
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
 		return
 	}
 
 	var req struct {
-		MessageID string          `json:"messageId"`
-		Response  json.RawMessage `json:"response"`
+		MessageID   string          `json:"messageId"`
+		Response    json.RawMessage `json:"response"`
+		ScheduledAt *time.Time      `json:"scheduledAt"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httputil.WriteError(w, apperrors.ValidationError("Invalid request body"))
+		httputil.RespondError(w, r, apperrors.ValidationError("Invalid request body"))
 		return
 	}
 
 	if req.MessageID == "" {
-		httputil.WriteError(w, apperrors.MissingRequired("messageId"))
+		httputil.RespondError(w, r, apperrors.MissingRequired("messageId"))
 		return
 	}
 
@@ -64,51 +209,567 @@ func (h *OpenClawHandler) Reply(w http.ResponseWriter, r *http.Request) {
 	inbound, err := h.messageService.FindInboundByID(ctx, req.MessageID)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to find inbound message")
-		httputil.WriteError(w, apperrors.Database(err))
+		httputil.RespondError(w, r, apperrors.Database(err))
 		return
 	}
 
 	if inbound == nil || inbound.AccountID != account.ID {
-		httputil.WriteError(w, apperrors.NotFound("Message"))
+		httputil.RespondError(w, r, apperrors.NotFound("Message"))
+		return
+	}
+	if !h.assignedToSession(w, r, account.ID, inbound.ConversationKey) {
 		return
 	}
 
-	hasValidCallback := inbound.CallbackURL != nil &&
-		(inbound.CallbackExpiresAt == nil || inbound.CallbackExpiresAt.After(time.Now()))
-
-	if !hasValidCallback {
+	callbackURL, ok := h.replyCallback(ctx, account.ID, inbound, time.Now())
+	if !ok {
 		log.Warn().
 			Str("messageId", req.MessageID).
 			Bool("hasCallbackUrl", inbound.CallbackURL != nil).
 			Msg("no valid callback URL for reply")
-		httputil.WriteError(w, apperrors.CallbackExpired())
+		httputil.RespondError(w, r, apperrors.CallbackExpired())
 		return
 	}
 
-	outbound, err := h.messageService.CreateOutbound(ctx, model.CreateOutboundMessageParams{
+	params := model.CreateOutboundMessageParams{
 		AccountID:        account.ID,
 		InboundMessageID: &req.MessageID,
 		ConversationKey:  inbound.ConversationKey,
 		KakaoTarget:      json.RawMessage("{}"),
 		ResponsePayload:  req.Response,
+		ReplyOnce:        h.replyOnce,
+	}
+	if req.ScheduledAt != nil {
+		h.schedule(w, r, params, *req.ScheduledAt, func(at time.Time) (string, bool) {
+			return h.replyCallback(ctx, account.ID, inbound, at)
+		})
+		return
+	}
+	h.deliver(w, r, params, callbackURL, false)
+}
+
+// maxAckMessageIDs caps how many messages one ack request acknowledges.
+const maxAckMessageIDs = 100
+
+// POST /openclaw/messages/ack
+// Marks messages as processed so they are not sent again when the plugin
+// reconnects. IDs of messages that are unknown or belong to another account
+// are ignored.
+func (h *OpenClawHandler) AckMessages(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	var req struct {
+		MessageIDs []string `json:"messageIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondError(w, r, apperrors.ValidationError("Invalid request body"))
+		return
+	}
+	if len(req.MessageIDs) == 0 {
+		httputil.RespondError(w, r, apperrors.MissingRequired("messageIds"))
+		return
+	}
+	if len(req.MessageIDs) > maxAckMessageIDs {
+		httputil.RespondError(w, r, apperrors.InvalidInput("messageIds", fmt.Sprintf("must contain at most %d IDs", maxAckMessageIDs)))
+		return
+	}
+
+	ctx := r.Context()
+	acknowledged := 0
+	for _, id := range req.MessageIDs {
+		inbound, err := h.messageService.FindInboundByID(ctx, id)
+		if err != nil {
+			log.Error().Err(err).Str("messageId", id).Msg("failed to find inbound message")
+			httputil.RespondError(w, r, apperrors.Database(err))
+			return
+		}
+		if inbound == nil || inbound.AccountID != account.ID {
+			continue
+		}
+		if err := h.messageService.MarkAcked(ctx, id); err != nil {
+			log.Error().Err(err).Str("messageId", id).Msg("failed to acknowledge message")
+			httputil.RespondError(w, r, apperrors.Database(err))
+			return
+		}
+		acknowledged++
+	}
+
+	httputil.Respond(w, r, http.StatusOK, map[string]any{"acknowledged": acknowledged})
+}
+
+// replyCallback returns the callback URL a reply to inbound can use at now.
+func (h *OpenClawHandler) replyCallback(ctx context.Context, accountID string, inbound *model.InboundMessage, now time.Time) (string, bool) {
+	if callbackURL, ok := inbound.ValidCallbackURL(now); ok {
+		return callbackURL, true
+	}
+
+	// Kakao issues a fresh callback URL with every webhook; a newer one
+	// stored on the conversation can still carry the reply.
+	callbackURL, ok := h.conversationCallback(ctx, accountID, inbound.ConversationKey, now)
+	if ok {
+		log.Info().
+			Str("messageId", inbound.ID).
+			Str("conversationKey", inbound.ConversationKey).
+			Msg("message callback expired, using conversation callback")
+	}
+	return callbackURL, ok
+}
+
+// conversationCallback returns the conversation's latest callback URL when
+// the conversation is still paired to the account and the URL is valid at now.
+func (h *OpenClawHandler) conversationCallback(ctx context.Context, accountID, conversationKey string, now time.Time) (string, bool) {
+	conv, err := h.convService.FindByKey(ctx, conversationKey)
+	if err != nil {
+		log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to load conversation callback")
+		return "", false
+	}
+	if conv == nil || conv.AccountID == nil || *conv.AccountID != accountID ||
+		conv.State != model.PairingStatePaired {
+		return "", false
+	}
+	return conv.ValidCallbackURL(now)
+}
+
+// replyStreamFlushMargin is how long before its callback URL expires a
+// stream is sent unfinished rather than lost.
+const replyStreamFlushMargin = 5 * time.Second
+
+// maxSimpleTextRunes is the most text a Kakao simpleText shows.
+const maxSimpleTextRunes = 1000
+
+// maxKakaoOutputs is the most outputs one Kakao response may carry.
+const maxKakaoOutputs = 3
+
+// splitText cuts text into at most limit parts of size characters each. When
+// text does not fit, the last part ends in "…" and truncated is set.
+func splitText(text string, size, limit int) (parts []string, truncated bool) {
+	runes := []rune(text)
+	for len(runes) > 0 {
+		if len(parts) == limit-1 && len(runes) > size {
+			return append(parts, string(runes[:size-1])+"…"), true
+		}
+		n := min(size, len(runes))
+		parts = append(parts, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return parts, false
+}
+
+// replyStreamPart is one part of a streamed reply.
+type replyStreamPart struct {
+	MessageID string `json:"messageId"`
+	Text      string `json:"text"`
+	Done      bool   `json:"done"`
+}
+
+// POST /openclaw/reply/stream
+// Streams a text reply in parts, in repeated calls or as newline-delimited
+// parts of one chunked request. Kakao accepts one callback per webhook and
+// cannot update the "thinking" message, so the relay joins the parts and
+// sends them with the part marked done, or early when the callback URL is
+// about to expire.
+func (h *OpenClawHandler) ReplyStream(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+	if h.streams == nil {
+		httputil.RespondError(w, r, apperrors.New(apperrors.ErrCodeUnavailable, "Reply streaming is disabled"))
+		return
+	}
+
+	ctx := r.Context()
+	dec := json.NewDecoder(r.Body)
+	var inbound *model.InboundMessage
+	var callbackURL string
+	size := 0
+
+	for {
+		var part replyStreamPart
+		if err := dec.Decode(&part); err == io.EOF {
+			break
+		} else if err != nil {
+			httputil.RespondError(w, r, apperrors.ValidationError("Invalid request body"))
+			return
+		}
+
+		if inbound == nil {
+			var ok bool
+			if inbound, callbackURL, ok = h.streamTarget(w, r, account.ID, part.MessageID); !ok {
+				return
+			}
+		} else if part.MessageID != "" && part.MessageID != inbound.ID {
+			httputil.RespondError(w, r, apperrors.InvalidInput("messageId", "must not change within a stream"))
+			return
+		}
+
+		var err error
+		size, err = h.streams.Append(ctx, inbound.ID, part.Text)
+		switch {
+		case errors.Is(err, service.ErrReplyStreamClosed):
+			httputil.RespondError(w, r, apperrors.New(apperrors.ErrCodeConflict, "Reply stream is already finished"))
+			return
+		case errors.Is(err, service.ErrReplyStreamTooLarge):
+			httputil.RespondError(w, r, apperrors.InvalidInput("text", "reply stream is too large"))
+			return
+		case err != nil:
+			log.Error().Err(err).Str("messageId", inbound.ID).Msg("failed to buffer reply stream")
+			httputil.RespondError(w, r, apperrors.Internal("Failed to buffer reply stream"))
+			return
+		}
+
+		done := part.Done
+		if !done && size > 0 {
+			if _, ok := h.replyCallback(ctx, account.ID, inbound, time.Now().Add(replyStreamFlushMargin)); !ok {
+				log.Info().Str("messageId", inbound.ID).Msg("callback about to expire, sending unfinished reply stream")
+				done = true
+			}
+		}
+		if done {
+			if size == 0 {
+				httputil.RespondError(w, r, apperrors.MissingRequired("text"))
+				return
+			}
+			h.finishStream(w, r, account.ID, inbound, callbackURL)
+			return
+		}
+	}
+
+	if inbound == nil {
+		httputil.RespondError(w, r, apperrors.MissingRequired("messageId"))
+		return
+	}
+	httputil.Respond(w, r, http.StatusAccepted, map[string]any{
+		"success":       true,
+		"messageId":     inbound.ID,
+		"bufferedBytes": size,
 	})
+}
+
+// streamTarget loads the message a stream replies to and the callback URL
+// that carries it, writing the error when there is none.
+func (h *OpenClawHandler) streamTarget(w http.ResponseWriter, r *http.Request, accountID, messageID string) (*model.InboundMessage, string, bool) {
+	if messageID == "" {
+		httputil.RespondError(w, r, apperrors.MissingRequired("messageId"))
+		return nil, "", false
+	}
+
+	inbound, err := h.messageService.FindInboundByID(r.Context(), messageID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to find inbound message")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return nil, "", false
+	}
+	if inbound == nil || inbound.AccountID != accountID {
+		httputil.RespondError(w, r, apperrors.NotFound("Message"))
+		return nil, "", false
+	}
+	if !h.assignedToSession(w, r, accountID, inbound.ConversationKey) {
+		return nil, "", false
+	}
+
+	callbackURL, ok := h.replyCallback(r.Context(), accountID, inbound, time.Now())
+	if !ok {
+		log.Warn().Str("messageId", messageID).Msg("no valid callback URL for reply stream")
+		httputil.RespondError(w, r, apperrors.CallbackExpired())
+		return nil, "", false
+	}
+	return inbound, callbackURL, true
+}
+
+// finishStream closes the stream and sends its output as one reply, split
+// into as many simpleText outputs as Kakao accepts. Output beyond that is
+// cut, which the response reports.
+func (h *OpenClawHandler) finishStream(w http.ResponseWriter, r *http.Request, accountID string, inbound *model.InboundMessage, callbackURL string) {
+	output, ok, err := h.streams.Close(r.Context(), inbound.ID)
+	if err != nil {
+		log.Error().Err(err).Str("messageId", inbound.ID).Msg("failed to close reply stream")
+		httputil.RespondError(w, r, apperrors.Internal("Failed to finish reply stream"))
+		return
+	}
+	if !ok {
+		httputil.RespondError(w, r, apperrors.New(apperrors.ErrCodeConflict, "Reply stream is already finished"))
+		return
+	}
+
+	texts, truncated := splitText(output, maxSimpleTextRunes, maxKakaoOutputs)
+	if truncated {
+		log.Warn().Str("messageId", inbound.ID).Int("bytes", len(output)).Msg("reply stream exceeds one callback, truncating")
+	}
+	resp := NewTextResponse(texts[0])
+	for _, text := range texts[1:] {
+		resp.Template.Outputs = append(resp.Template.Outputs, KakaoOutput{SimpleText: &KakaoSimpleText{Text: text}})
+	}
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		httputil.RespondError(w, r, apperrors.Internal("Failed to encode reply"))
+		return
+	}
+	h.deliver(w, r, model.CreateOutboundMessageParams{
+		AccountID:        accountID,
+		InboundMessageID: &inbound.ID,
+		ConversationKey:  inbound.ConversationKey,
+		KakaoTarget:      json.RawMessage("{}"),
+		ResponsePayload:  payload,
+		ReplyOnce:        h.replyOnce,
+	}, callbackURL, truncated)
+}
+
+// POST /openclaw/conversations/{key}/send
+// Sends a response to a paired conversation without naming an inbound
+// message, using the freshest callback URL Kakao issued for it.
+func (h *OpenClawHandler) SendToConversation(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	var req struct {
+		Response    json.RawMessage `json:"response"`
+		ScheduledAt *time.Time      `json:"scheduledAt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.RespondError(w, r, apperrors.ValidationError("Invalid request body"))
+		return
+	}
+	if len(req.Response) == 0 {
+		httputil.RespondError(w, r, apperrors.MissingRequired("response"))
+		return
+	}
+
+	conversationKey := chi.URLParam(r, "key")
+	conv, err := h.convService.FindByKey(r.Context(), conversationKey)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to find conversation")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+	if conv == nil || conv.AccountID == nil || *conv.AccountID != account.ID ||
+		conv.State != model.PairingStatePaired {
+		httputil.RespondError(w, r, apperrors.NotFound("Conversation"))
+		return
+	}
+	if !h.assignedToSession(w, r, account.ID, conversationKey) {
+		return
+	}
+
+	callbackURL, ok := conv.ValidCallbackURL(time.Now())
+	if !ok {
+		log.Warn().
+			Str("conversationKey", conversationKey).
+			Bool("hasCallbackUrl", conv.LastCallbackURL != nil).
+			Msg("no valid callback URL for conversation send")
+		httputil.RespondError(w, r, apperrors.CallbackExpired())
+		return
+	}
+
+	params := model.CreateOutboundMessageParams{
+		AccountID:       account.ID,
+		ConversationKey: conversationKey,
+		KakaoTarget:     json.RawMessage("{}"),
+		ResponsePayload: req.Response,
+	}
+	if req.ScheduledAt != nil {
+		h.schedule(w, r, params, *req.ScheduledAt, conv.ValidCallbackURL)
+		return
+	}
+	h.deliver(w, r, params, callbackURL, false)
+}
+
+// POST /openclaw/conversations/{key}/pause
+// Holds back the conversation's messages until it is resumed, e.g. while the
+// agent is on vacation or under maintenance. The relay keeps storing them and
+// answers the Kakao user with autoReply, or a default notice.
+func (h *OpenClawHandler) PauseConversation(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	var req struct {
+		AutoReply string `json:"autoReply"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httputil.RespondError(w, r, apperrors.ValidationError("Invalid request body"))
+			return
+		}
+	}
+
+	ctx := r.Context()
+	conversationKey := chi.URLParam(r, "key")
+	unlock := h.convService.Lock(ctx, conversationKey)
+	defer unlock()
+
+	if !h.ownsPairedConversation(w, r, account.ID, conversationKey) {
+		return
+	}
+
+	conv, err := h.convService.PauseDelivery(ctx, conversationKey, req.AutoReply)
+	if err != nil {
+		if apperrors.IsAppError(err) {
+			httputil.RespondError(w, r, err)
+			return
+		}
+		log.Error().Err(err).Str("conversationKey", conversationKey).Msg("failed to pause conversation")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+	if conv == nil {
+		httputil.RespondError(w, r, apperrors.NotFound("Conversation"))
+		return
+	}
+
+	h.events.ConversationPaused(ctx, account.ID, conv)
+
+	httputil.Respond(w, r, http.StatusOK, map[string]any{
+		"conversationKey":  conversationKey,
+		"paused":           true,
+		"deliveryPausedAt": formatTime(conv.DeliveryPausedAt),
+		"autoReply":        conv.PauseAutoReply,
+	})
+}
+
+// POST /openclaw/conversations/{key}/resume
+// Lifts a pause and delivers the messages held back meanwhile as message
+// events. Resuming a conversation that is not paused does nothing.
+func (h *OpenClawHandler) ResumeConversation(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	ctx := r.Context()
+	conversationKey := chi.URLParam(r, "key")
+	unlock := h.convService.Lock(ctx, conversationKey)
+	defer unlock()
+
+	if !h.ownsPairedConversation(w, r, account.ID, conversationKey) {
+		return
+	}
+
+	conv, err := h.convService.ResumeDelivery(ctx, conversationKey)
+	if err != nil {
+		log.Error().Err(err).Str("conversationKey", conversationKey).Msg("failed to resume conversation")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+
+	held := 0
+	if conv != nil {
+		held = h.releaseHeld(ctx, account.ID, conversationKey)
+	}
+
+	httputil.Respond(w, r, http.StatusOK, map[string]any{
+		"conversationKey": conversationKey,
+		"paused":          false,
+		"resumed":         conv != nil,
+		"heldCount":       held,
+	})
+}
+
+// ownsPairedConversation writes 404 unless the conversation is paired (or
+// archived) to the account.
+func (h *OpenClawHandler) ownsPairedConversation(w http.ResponseWriter, r *http.Request, accountID, conversationKey string) bool {
+	conv, err := h.convService.FindByKey(r.Context(), conversationKey)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to find conversation")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return false
+	}
+	if conv == nil || conv.AccountID == nil || *conv.AccountID != accountID ||
+		(conv.State != model.PairingStatePaired && conv.State != model.PairingStateArchived) {
+		httputil.RespondError(w, r, apperrors.NotFound("Conversation"))
+		return false
+	}
+	return true
+}
+
+// assignedToSession writes 409 when the conversation is routed to another
+// plugin session of the account, so that two agents never answer the same
+// message.
+func (h *OpenClawHandler) assignedToSession(w http.ResponseWriter, r *http.Request, accountID, conversationKey string) bool {
+	if h.router == nil {
+		return true
+	}
+	owner, err := h.router.Owner(r.Context(), accountID, conversationKey)
+	if err != nil {
+		// Replies still go out when the routes cannot be read
+		log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to check conversation owner")
+		return true
+	}
+	if owner == "" {
+		return true
+	}
+	if session := middleware.GetSession(r.Context()); session != nil && session.ID == owner {
+		return true
+	}
+	httputil.RespondError(w, r, apperrors.New(apperrors.ErrCodeConflict, "Conversation is assigned to another session"))
+	return false
+}
+
+// releaseHeld announces the resume and publishes the messages held back
+// while the conversation was paused. It returns how many there were. Like
+// other published messages they stay queued until a stream delivers them.
+func (h *OpenClawHandler) releaseHeld(ctx context.Context, accountID, conversationKey string) int {
+	messages, err := h.messageService.FindQueuedByConversationKey(ctx, conversationKey)
+	if err != nil {
+		// They are still queued and go out with the next connection
+		log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to load held messages")
+	}
+
+	h.events.ConversationResumed(ctx, accountID, conversationKey, len(messages))
+	for _, msg := range messages {
+		event := sse.NewRawEvent("message", accountID, conversationKey, msg.ToSSEEventData(nil))
+		if err := h.broker.Publish(ctx, accountID, event); err != nil {
+			log.Warn().Err(err).Str("messageId", msg.ID).Msg("failed to publish held message")
+		}
+	}
+	return len(messages)
+}
+
+// deliver records the outbound message, posts it to the Kakao callback URL
+// and writes the result. truncated marks a reply whose text was cut to fit.
+func (h *OpenClawHandler) deliver(w http.ResponseWriter, r *http.Request, params model.CreateOutboundMessageParams, callbackURL string, truncated bool) {
+	ctx := r.Context()
+
+	outbound, err := h.messageService.CreateOutbound(ctx, params)
+	if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeAlreadyReplied {
+		httputil.RespondError(w, r, appErr)
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create outbound message")
-		httputil.WriteError(w, apperrors.Database(err))
+		httputil.RespondError(w, r, apperrors.Database(err))
 		return
 	}
 
 	var responsePayload any
-	json.Unmarshal(req.Response, &responsePayload)
+	json.Unmarshal(params.ResponsePayload, &responsePayload)
 
-	if err := h.kakaoService.SendCallback(ctx, *inbound.CallbackURL, responsePayload); err != nil {
+	resp, err := h.kakaoService.SendCallback(ctx, callbackURL, responsePayload)
+	if resp != nil {
+		if err := h.messageService.RecordCallbackResponse(ctx, outbound.ID, *resp); err != nil {
+			log.Warn().Err(err).Str("outboundId", outbound.ID).Msg("failed to record callback response")
+		}
+	}
+	h.events.ReplyDelivered(ctx, outbound, resp, err)
+	if err != nil {
 		h.messageService.MarkOutboundFailed(ctx, outbound.ID, err.Error())
 		log.Error().
 			Err(err).
 			Str("outboundId", outbound.ID).
-			Str("messageId", req.MessageID).
+			Str("conversationKey", params.ConversationKey).
 			Msg("failed to send callback to Kakao")
-		httputil.WriteError(w, apperrors.CallbackFailed("Kakao callback failed"))
+		httputil.RespondError(w, r, apperrors.CallbackFailed("Kakao callback failed"))
 		return
 	}
 
@@ -118,12 +779,128 @@ func (h *OpenClawHandler) Reply(w http.ResponseWriter, r *http.Request) {
 
 	log.Info().
 		Str("outboundId", outbound.ID).
-		Str("messageId", req.MessageID).
-		Str("accountId", account.ID).
+		Str("conversationKey", params.ConversationKey).
+		Str("accountId", params.AccountID).
 		Msg("reply sent to Kakao")
 
-	httputil.WriteJSON(w, http.StatusOK, map[string]any{
+	warnings := []ratelimit.Warning{}
+	if warning := middleware.GetRateLimitWarning(ctx); warning != nil {
+		warnings = append(warnings, *warning)
+	}
+
+	body := map[string]any{
 		"success":     true,
+		"outboundId":  outbound.ID,
 		"deliveredAt": deliveredAt,
+		"warnings":    warnings,
+	}
+	if truncated {
+		body["truncated"] = true
+	}
+	httputil.Respond(w, r, http.StatusOK, body)
+}
+
+// schedule records the outbound message to be sent at scheduledAt by the
+// scheduled send job. callbackAt resolves the callback URL valid at a time;
+// a reply can only be deferred while its callback URL is still valid.
+func (h *OpenClawHandler) schedule(w http.ResponseWriter, r *http.Request, params model.CreateOutboundMessageParams, scheduledAt time.Time, callbackAt func(time.Time) (string, bool)) {
+	if !scheduledAt.After(time.Now()) {
+		httputil.RespondError(w, r, apperrors.InvalidInput("scheduledAt", "must be in the future"))
+		return
+	}
+	callbackURL, ok := callbackAt(scheduledAt)
+	if !ok {
+		httputil.RespondError(w, r, apperrors.InvalidInput("scheduledAt", "is after the callback URL expires"))
+		return
+	}
+
+	params.ScheduledAt = &scheduledAt
+	params.CallbackURL = &callbackURL
+	outbound, err := h.messageService.CreateOutbound(r.Context(), params)
+	if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeAlreadyReplied {
+		httputil.RespondError(w, r, appErr)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create outbound message")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+
+	log.Info().
+		Str("outboundId", outbound.ID).
+		Str("conversationKey", params.ConversationKey).
+		Time("scheduledAt", scheduledAt).
+		Msg("reply scheduled")
+
+	httputil.Respond(w, r, http.StatusAccepted, map[string]any{
+		"success":     true,
+		"outboundId":  outbound.ID,
+		"scheduledAt": scheduledAt.UnixMilli(),
+	})
+}
+
+// GET /openclaw/outbound/scheduled?cursor=&limit=
+// Lists the account's scheduled replies that have not been sent yet, paged
+// with cursors on v2.
+func (h *OpenClawHandler) ListScheduledOutbound(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	page, err := parseCursorPage(r)
+	if err != nil {
+		httputil.RespondError(w, r, err)
+		return
+	}
+	msgs, err := h.messageService.ListScheduledOutbound(r.Context(), account.ID, page)
+	if err != nil {
+		log.Error().Err(err).Str("accountId", account.ID).Msg("failed to list scheduled outbound messages")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+	msgs, meta := nextPage(msgs, page, func(msg model.OutboundMessage) model.Cursor {
+		return model.Cursor{At: *msg.ScheduledAt, ID: msg.ID}
+	})
+
+	scheduled := make([]map[string]any, 0, len(msgs))
+	for _, msg := range msgs {
+		scheduled = append(scheduled, map[string]any{
+			"id":               msg.ID,
+			"conversationKey":  msg.ConversationKey,
+			"inboundMessageId": msg.InboundMessageID,
+			"scheduledAt":      msg.ScheduledAt.UnixMilli(),
+			"createdAt":        msg.CreatedAt.UnixMilli(),
+		})
+	}
+	httputil.RespondWithMeta(w, r, http.StatusOK, map[string]any{"scheduled": scheduled}, meta)
+}
+
+// DELETE /openclaw/outbound/{id}
+// Cancels an outbound message that has not been sent yet.
+func (h *OpenClawHandler) CancelOutbound(w http.ResponseWriter, r *http.Request) {
+	account := middleware.GetAccount(r.Context())
+	if account == nil {
+		httputil.RespondError(w, r, apperrors.SessionNotPaired())
+		return
+	}
+
+	msg, err := h.messageService.CancelOutbound(r.Context(), account.ID, chi.URLParam(r, "id"))
+	if err != nil {
+		if apperrors.IsAppError(err) {
+			httputil.RespondError(w, r, err)
+			return
+		}
+		log.Error().Err(err).Str("accountId", account.ID).Msg("failed to cancel outbound message")
+		httputil.RespondError(w, r, apperrors.Database(err))
+		return
+	}
+
+	httputil.Respond(w, r, http.StatusOK, map[string]any{
+		"success": true,
+		"id":      msg.ID,
+		"status":  msg.Status,
 	})
 }
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/openclaw/relay-server-go/internal/httputil"
 	"github.com/openclaw/relay-server-go/internal/middleware"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/service"
 )
 
@@ -35,6 +40,11 @@ func (m *mockInboundRepo) FindQueuedByAccountID(ctx context.Context, accountID s
 	return args.Get(0).([]model.InboundMessage), args.Error(1)
 }
 
+func (m *mockInboundRepo) FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, conversationKey)
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
 func (m *mockInboundRepo) FindByAccountID(ctx context.Context, accountID string, limit, offset int) ([]model.InboundMessage, error) {
 	args := m.Called(ctx, accountID, limit, offset)
 	return args.Get(0).([]model.InboundMessage), args.Error(1)
@@ -53,6 +63,14 @@ func (m *mockInboundRepo) Create(ctx context.Context, params model.CreateInbound
 	return args.Get(0).(*model.InboundMessage), args.Error(1)
 }
 
+func (m *mockInboundRepo) CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
 func (m *mockInboundRepo) MarkDelivered(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -68,6 +86,32 @@ func (m *mockInboundRepo) MarkExpired(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockInboundRepo) ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, now, leaseUntil, maxAttempts, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
+func (m *mockInboundRepo) RecordWebhookFailure(ctx context.Context, id, errorMsg string, nextAt time.Time) error {
+	args := m.Called(ctx, id, errorMsg, nextAt)
+	return args.Error(0)
+}
+
+func (m *mockInboundRepo) ReleaseWebhookLease(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockInboundRepo) QueuedBacklog(ctx context.Context) (*model.InboundBacklog, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.InboundBacklog), args.Error(1)
+}
+
 func (m *mockInboundRepo) CountByStatus(ctx context.Context, status model.InboundMessageStatus) (int, error) {
 	args := m.Called(ctx, status)
 	return args.Int(0), args.Error(1)
@@ -83,6 +127,19 @@ func (m *mockInboundRepo) CountByAccountIDSince(ctx context.Context, accountID s
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockInboundRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, accountID, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
+func (m *mockInboundRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	args := m.Called(ctx, accountID, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *mockInboundRepo) FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.InboundMessage, error) {
 	args := m.Called(ctx, conversationKey, limit, offset)
 	return args.Get(0).([]model.InboundMessage), args.Error(1)
@@ -110,6 +167,24 @@ func (m *mockOutboundRepo) FindByID(ctx context.Context, id string) (*model.Outb
 	return args.Get(0).(*model.OutboundMessage), args.Error(1)
 }
 
+func (m *mockOutboundRepo) Cancel(ctx context.Context, id, accountID string) (*model.OutboundMessage, error) {
+	args := m.Called(ctx, id, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OutboundMessage), args.Error(1)
+}
+
+func (m *mockOutboundRepo) FindScheduledByAccountID(ctx context.Context, accountID string, page model.KeysetPage) ([]model.OutboundMessage, error) {
+	args := m.Called(ctx, accountID, page)
+	return args.Get(0).([]model.OutboundMessage), args.Error(1)
+}
+
+func (m *mockOutboundRepo) ClaimDueScheduled(ctx context.Context, now time.Time, limit int) ([]model.OutboundMessage, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]model.OutboundMessage), args.Error(1)
+}
+
 func (m *mockOutboundRepo) FindPendingByAccountID(ctx context.Context, accountID string) ([]model.OutboundMessage, error) {
 	args := m.Called(ctx, accountID)
 	return args.Get(0).([]model.OutboundMessage), args.Error(1)
@@ -133,6 +208,19 @@ func (m *mockOutboundRepo) Create(ctx context.Context, params model.CreateOutbou
 	return args.Get(0).(*model.OutboundMessage), args.Error(1)
 }
 
+func (m *mockOutboundRepo) CreateReplyOnce(ctx context.Context, params model.CreateOutboundMessageParams) (*model.OutboundMessage, bool, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*model.OutboundMessage), args.Bool(1), args.Error(2)
+}
+
+func (m *mockOutboundRepo) RecordCallbackResponse(ctx context.Context, id string, resp model.CallbackResponse) error {
+	args := m.Called(ctx, id, resp)
+	return args.Error(0)
+}
+
 func (m *mockOutboundRepo) MarkSent(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -158,6 +246,19 @@ func (m *mockOutboundRepo) CountByAccountIDSince(ctx context.Context, accountID
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockOutboundRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.OutboundMessage, error) {
+	args := m.Called(ctx, accountID, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OutboundMessage), args.Error(1)
+}
+
+func (m *mockOutboundRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	args := m.Called(ctx, accountID, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *mockOutboundRepo) FindRecentFailedByAccountID(ctx context.Context, accountID string, limit int) ([]model.OutboundMessage, error) {
 	args := m.Called(ctx, accountID, limit)
 	if args.Get(0) == nil {
@@ -198,9 +299,12 @@ type mockKakaoService struct {
 	mock.Mock
 }
 
-func (m *mockKakaoService) SendCallback(ctx context.Context, callbackURL string, payload any) error {
+func (m *mockKakaoService) SendCallback(ctx context.Context, callbackURL string, payload any) (*model.CallbackResponse, error) {
 	args := m.Called(ctx, callbackURL, payload)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CallbackResponse), args.Error(1)
 }
 
 // Helper to add account to context
@@ -212,10 +316,10 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	t.Run("returns 401 when no account in context", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, false)
 
 		body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
 		req := httptest.NewRequest(http.MethodPost, "/openclaw/reply", body)
@@ -230,10 +334,10 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	t.Run("returns 400 when messageId is missing", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, false)
 
 		account := &model.Account{ID: "acc-1"}
 		body := bytes.NewBufferString(`{"response": {"text": "Hello"}}`)
@@ -250,10 +354,10 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	t.Run("returns 400 when request body is invalid", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, false)
 
 		account := &model.Account{ID: "acc-1"}
 		body := bytes.NewBufferString(`{invalid json}`)
@@ -270,12 +374,12 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	t.Run("returns 404 when message not found", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
 		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(nil, nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, false)
 
 		account := &model.Account{ID: "acc-1"}
 		body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
@@ -290,11 +394,46 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 		inboundRepo.AssertExpectations(t)
 	})
 
+	t.Run("returns 409 when message was already replied to", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
+
+		callbackURL := "https://callback.kakao.com/v1"
+		expiresAt := time.Now().Add(1 * time.Hour)
+		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(&model.InboundMessage{
+			ID:                "msg-1",
+			AccountID:         "acc-1",
+			ConversationKey:   "conv-1",
+			CallbackURL:       &callbackURL,
+			CallbackExpiresAt: &expiresAt,
+		}, nil)
+		outboundRepo.On("CreateReplyOnce", mock.Anything, mock.MatchedBy(func(p model.CreateOutboundMessageParams) bool {
+			return p.ReplyOnce && *p.InboundMessageID == "msg-1"
+		})).Return(&model.OutboundMessage{ID: "out-1"}, false, nil)
+
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, true)
+
+		account := &model.Account{ID: "acc-1"}
+		body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/openclaw/reply", body)
+		req = req.WithContext(withAccount(req.Context(), account))
+		rec := httptest.NewRecorder()
+
+		handler.Reply(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "ALREADY_REPLIED")
+		assert.Contains(t, rec.Body.String(), "out-1")
+		outboundRepo.AssertExpectations(t)
+	})
+
 	t.Run("returns 404 when message belongs to different account", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
 		callbackURL := "https://callback.kakao.com/v1"
 		expiresAt := time.Now().Add(1 * time.Hour)
@@ -307,7 +446,7 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 		}
 		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(inboundMsg, nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, false)
 
 		account := &model.Account{ID: "acc-1"}
 		body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
@@ -325,8 +464,8 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	t.Run("returns 400 when callback URL is nil", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
 		inboundMsg := &model.InboundMessage{
 			ID:              "msg-1",
@@ -336,7 +475,8 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 		}
 		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(inboundMsg, nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		convService := service.NewConversationService(&stubConversationRepo{}, nil)
+		handler := NewOpenClawHandler(msgService, kakaoService, convService, nil, nil, nil, false)
 
 		account := &model.Account{ID: "acc-1"}
 		body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
@@ -354,8 +494,8 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	t.Run("returns 400 when callback URL is expired", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
 		callbackURL := "https://callback.kakao.com/v1"
 		expiresAt := time.Now().Add(-1 * time.Hour) // Expired
@@ -368,7 +508,8 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 		}
 		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(inboundMsg, nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		convService := service.NewConversationService(&stubConversationRepo{}, nil)
+		handler := NewOpenClawHandler(msgService, kakaoService, convService, nil, nil, nil, false)
 
 		account := &model.Account{ID: "acc-1"}
 		body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
@@ -384,14 +525,224 @@ func TestOpenClawHandler_Reply(t *testing.T) {
 	})
 }
 
+func TestOpenClawHandler_ScheduledReply(t *testing.T) {
+	callbackURL := "https://callback.kakao.com/v1"
+	expiresAt := time.Now().Add(time.Minute)
+	account := &model.Account{ID: "acc-1"}
+
+	newHandler := func() (*OpenClawHandler, *mockOutboundRepo) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(&model.InboundMessage{
+			ID:                "msg-1",
+			AccountID:         "acc-1",
+			ConversationKey:   "conv-1",
+			CallbackURL:       &callbackURL,
+			CallbackExpiresAt: &expiresAt,
+		}, nil)
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		convService := service.NewConversationService(&stubConversationRepo{}, nil)
+		return NewOpenClawHandler(msgService, service.NewKakaoService(nil), convService, nil, nil, nil, false), outboundRepo
+	}
+	reply := func(handler *OpenClawHandler, scheduledAt time.Time) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"messageId": "msg-1", "response": {"version": "2.0"}, "scheduledAt": %q}`, scheduledAt.Format(time.RFC3339Nano))
+		req := httptest.NewRequest(http.MethodPost, "/openclaw/reply", strings.NewReader(body))
+		req = req.WithContext(withAccount(req.Context(), account))
+		rec := httptest.NewRecorder()
+		handler.Reply(rec, req)
+		return rec
+	}
+
+	t.Run("records the reply for later", func(t *testing.T) {
+		handler, outboundRepo := newHandler()
+		scheduledAt := time.Now().Add(30 * time.Second)
+		outboundRepo.On("Create", mock.Anything, mock.MatchedBy(func(p model.CreateOutboundMessageParams) bool {
+			return p.ScheduledAt != nil && p.ScheduledAt.Equal(scheduledAt) &&
+				p.CallbackURL != nil && *p.CallbackURL == callbackURL
+		})).Return(&model.OutboundMessage{ID: "out-1"}, nil)
+
+		rec := reply(handler, scheduledAt)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"outboundId":"out-1"`)
+		outboundRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a time in the past", func(t *testing.T) {
+		handler, _ := newHandler()
+
+		rec := reply(handler, time.Now().Add(-time.Second))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_INPUT")
+	})
+
+	t.Run("rejects a time after the callback expires", func(t *testing.T) {
+		handler, _ := newHandler()
+
+		rec := reply(handler, expiresAt.Add(time.Second))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_INPUT")
+	})
+
+	t.Run("lists scheduled replies", func(t *testing.T) {
+		handler, outboundRepo := newHandler()
+		scheduledAt := time.Now().Add(30 * time.Second)
+		outboundRepo.On("FindScheduledByAccountID", mock.Anything, "acc-1", model.KeysetPage{}).Return([]model.OutboundMessage{
+			{ID: "out-1", ConversationKey: "conv-1", ScheduledAt: &scheduledAt},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/openclaw/outbound/scheduled", nil)
+		req = req.WithContext(withAccount(req.Context(), account))
+		rec := httptest.NewRecorder()
+		handler.ListScheduledOutbound(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"scheduledAt":%d`, scheduledAt.UnixMilli()))
+	})
+
+	t.Run("pages scheduled replies with cursors on v2", func(t *testing.T) {
+		handler, outboundRepo := newHandler()
+		first := time.Now().Add(30 * time.Second)
+		second := first.Add(time.Minute)
+		after := &model.Cursor{At: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), ID: "out-0"}
+		outboundRepo.On("FindScheduledByAccountID", mock.Anything, "acc-1", model.KeysetPage{After: after, Limit: 2}).Return([]model.OutboundMessage{
+			{ID: "out-1", ConversationKey: "conv-1", ScheduledAt: &first},
+			{ID: "out-2", ConversationKey: "conv-1", ScheduledAt: &second},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/v2/openclaw/outbound/scheduled?limit=1&cursor="+httputil.EncodeCursor(*after), nil)
+		req = req.WithContext(httputil.WithAPIVersion(withAccount(req.Context(), account), httputil.APIVersionV2))
+		rec := httptest.NewRecorder()
+		handler.ListScheduledOutbound(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Data struct {
+				Scheduled []map[string]any `json:"scheduled"`
+			} `json:"data"`
+			Meta httputil.PageMeta `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Len(t, body.Data.Scheduled, 1)
+		assert.Equal(t, "out-1", body.Data.Scheduled[0]["id"])
+		assert.True(t, body.Meta.HasMore)
+		next, err := httputil.DecodeCursor(body.Meta.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, "out-1", next.ID)
+		assert.True(t, first.Equal(next.At))
+	})
+
+	t.Run("rejects an invalid cursor on v2", func(t *testing.T) {
+		handler, _ := newHandler()
+
+		req := httptest.NewRequest(http.MethodGet, "/v2/openclaw/outbound/scheduled?cursor=garbage!", nil)
+		req = req.WithContext(httputil.WithAPIVersion(withAccount(req.Context(), account), httputil.APIVersionV2))
+		rec := httptest.NewRecorder()
+		handler.ListScheduledOutbound(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_INPUT")
+	})
+}
+
+func TestOpenClawHandler_ReplyStream(t *testing.T) {
+	callbackURL := "https://callback.example.com/v1"
+	expiresAt := time.Now().Add(time.Minute)
+	account := &model.Account{ID: "acc-1"}
+
+	newHandler := func() (*OpenClawHandler, *mockOutboundRepo) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(&model.InboundMessage{
+			ID:                "msg-1",
+			AccountID:         "acc-1",
+			ConversationKey:   "conv-1",
+			CallbackURL:       &callbackURL,
+			CallbackExpiresAt: &expiresAt,
+		}, nil)
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		handler := NewOpenClawHandler(msgService, service.NewKakaoService(nil), nil, nil, service.NewMemoryReplyStreamBuffer(), nil, false)
+		return handler, outboundRepo
+	}
+	post := func(handler *OpenClawHandler, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/openclaw/reply/stream", strings.NewReader(body))
+		req = req.WithContext(withAccount(req.Context(), account))
+		rec := httptest.NewRecorder()
+		handler.ReplyStream(rec, req)
+		return rec
+	}
+
+	t.Run("joins parts from repeated calls and chunked bodies into one reply", func(t *testing.T) {
+		handler, outboundRepo := newHandler()
+
+		rec := post(handler, `{"messageId": "msg-1", "text": "Hello, "}`)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"bufferedBytes":7`)
+
+		// The callback host is not allowed, so sending fails after the
+		// outbound message was recorded
+		var sent model.CreateOutboundMessageParams
+		outboundRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(1).(model.CreateOutboundMessageParams)
+		}).Return(&model.OutboundMessage{ID: "out-1"}, nil)
+		outboundRepo.On("MarkFailed", mock.Anything, "out-1", mock.Anything).Return(nil)
+
+		rec = post(handler, "{\"messageId\": \"msg-1\", \"text\": \"wor\"}\n{\"text\": \"ld\", \"done\": true}\n")
+		assert.Contains(t, rec.Body.String(), "CALLBACK_FAILED")
+		assert.Equal(t, "conv-1", sent.ConversationKey)
+		assert.JSONEq(t, `{"version":"2.0","template":{"outputs":[{"simpleText":{"text":"Hello, world"}}]}}`, string(sent.ResponsePayload))
+
+		rec = post(handler, `{"messageId": "msg-1", "text": "late"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("splits long output across outputs and cuts what does not fit", func(t *testing.T) {
+		handler, outboundRepo := newHandler()
+		var sent model.CreateOutboundMessageParams
+		outboundRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(1).(model.CreateOutboundMessageParams)
+		}).Return(&model.OutboundMessage{ID: "out-1"}, nil)
+		outboundRepo.On("MarkFailed", mock.Anything, "out-1", mock.Anything).Return(nil)
+
+		text := strings.Repeat("가", 2500) + strings.Repeat("나", 600)
+		post(handler, `{"messageId": "msg-1", "text": "`+text+`", "done": true}`)
+
+		var resp KakaoResponse
+		require.NoError(t, json.Unmarshal(sent.ResponsePayload, &resp))
+		require.Len(t, resp.Template.Outputs, 3)
+		assert.Equal(t, strings.Repeat("가", 1000), resp.Template.Outputs[0].SimpleText.Text)
+		last := []rune(resp.Template.Outputs[2].SimpleText.Text)
+		assert.Len(t, last, 1000)
+		assert.Equal(t, "…", string(last[999]))
+	})
+
+	t.Run("rejects a part for another message", func(t *testing.T) {
+		handler, _ := newHandler()
+
+		rec := post(handler, "{\"messageId\": \"msg-1\", \"text\": \"a\"}\n{\"messageId\": \"msg-2\", \"text\": \"b\"}")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_INPUT")
+	})
+
+	t.Run("requires text before done", func(t *testing.T) {
+		handler, _ := newHandler()
+
+		rec := post(handler, `{"messageId": "msg-1", "done": true}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "MISSING_REQUIRED")
+	})
+}
+
 func TestOpenClawHandler_Routes(t *testing.T) {
 	t.Run("registers /reply route", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		msgService := service.NewMessageService(inboundRepo, outboundRepo)
-		kakaoService := service.NewKakaoService()
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		kakaoService := service.NewKakaoService(nil)
 
-		handler := NewOpenClawHandler(msgService, kakaoService)
+		handler := NewOpenClawHandler(msgService, kakaoService, nil, nil, nil, nil, false)
 		router := handler.Routes()
 
 		// Verify the route is registered by making a request
@@ -403,6 +754,379 @@ func TestOpenClawHandler_Routes(t *testing.T) {
 		// Should get 401 (no account) not 404 (not found)
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 	})
+
+	t.Run("registers /pairing/list route", func(t *testing.T) {
+		handler := NewOpenClawHandler(nil, nil, nil, nil, nil, nil, false)
+		router := handler.Routes()
+
+		req := httptest.NewRequest(http.MethodGet, "/pairing/list", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestOpenClawHandler_CancelOutbound(t *testing.T) {
+	newRouter := func(outboundRepo *mockOutboundRepo) http.Handler {
+		msgService := service.NewMessageService(new(mockInboundRepo), outboundRepo, nil, nil, nil)
+		return NewOpenClawHandler(msgService, service.NewKakaoService(nil), nil, nil, nil, nil, false).Routes()
+	}
+
+	t.Run("cancels a pending message", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		outboundRepo.On("Cancel", mock.Anything, "out-1", "acc-1").Return(&model.OutboundMessage{
+			ID: "out-1", AccountID: "acc-1", Status: model.OutboundStatusCancelled,
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/outbound/out-1", nil)
+		req = req.WithContext(withAccount(req.Context(), &model.Account{ID: "acc-1"}))
+		rec := httptest.NewRecorder()
+
+		newRouter(outboundRepo).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"cancelled"`)
+	})
+
+	t.Run("returns 409 once the message was sent", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		outboundRepo.On("Cancel", mock.Anything, "out-1", "acc-1").Return(nil, nil)
+		outboundRepo.On("FindByID", mock.Anything, "out-1").Return(&model.OutboundMessage{
+			ID: "out-1", AccountID: "acc-1", Status: model.OutboundStatusSent,
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/outbound/out-1", nil)
+		req = req.WithContext(withAccount(req.Context(), &model.Account{ID: "acc-1"}))
+		rec := httptest.NewRecorder()
+
+		newRouter(outboundRepo).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "CONFLICT")
+	})
+
+	t.Run("returns 404 for unknown messages", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		outboundRepo.On("Cancel", mock.Anything, "missing", "acc-1").Return(nil, nil)
+		outboundRepo.On("FindByID", mock.Anything, "missing").Return(nil, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/outbound/missing", nil)
+		req = req.WithContext(withAccount(req.Context(), &model.Account{ID: "acc-1"}))
+		rec := httptest.NewRecorder()
+
+		newRouter(outboundRepo).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+// stubConversationRepo serves FindByKey and FindHealthByKeys from maps; other
+// methods are not used by the OpenClaw handler.
+type stubConversationRepo struct {
+	repository.ConversationRepository
+	convs  map[string]*model.ConversationMapping
+	health map[string]model.ConnectionHealth
+}
+
+func (s *stubConversationRepo) FindByKey(ctx context.Context, key string) (*model.ConversationMapping, error) {
+	return s.convs[key], nil
+}
+
+func (s *stubConversationRepo) FindHealthByKeys(ctx context.Context, keys []string, failuresSince time.Time) ([]model.ConnectionHealth, error) {
+	var result []model.ConnectionHealth
+	for _, key := range keys {
+		if h, ok := s.health[key]; ok {
+			h.ConversationKey = key
+			result = append(result, h)
+		}
+	}
+	return result, nil
+}
+
+func TestOpenClawHandler_ReplyCallbackFallback(t *testing.T) {
+	accountID := "acc-1"
+	staleURL := "https://bot-api.kakao.com/callback/old"
+	freshURL := "https://example.com/callback/new"
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Minute)
+
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("FindByID", mock.Anything, "msg-1").Return(&model.InboundMessage{
+		ID:                "msg-1",
+		AccountID:         accountID,
+		ConversationKey:   "conv-1",
+		CallbackURL:       &staleURL,
+		CallbackExpiresAt: &past,
+	}, nil)
+	outboundRepo := new(mockOutboundRepo)
+	outboundRepo.On("Create", mock.Anything, mock.Anything).Return(&model.OutboundMessage{ID: "out-1"}, nil)
+	outboundRepo.On("MarkFailed", mock.Anything, "out-1", mock.Anything).Return(nil)
+
+	convService := service.NewConversationService(&stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"conv-1": {ConversationKey: "conv-1", AccountID: &accountID, State: model.PairingStatePaired, LastCallbackURL: &freshURL, LastCallbackExpiresAt: &future},
+	}}, nil)
+	msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+	handler := NewOpenClawHandler(msgService, service.NewKakaoService(nil), convService, nil, nil, nil, false)
+
+	body := bytes.NewBufferString(`{"messageId": "msg-1", "response": {"text": "Hello"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/openclaw/reply", body)
+	req = req.WithContext(withAccount(req.Context(), &model.Account{ID: accountID}))
+	rec := httptest.NewRecorder()
+
+	handler.Reply(rec, req)
+
+	// The conversation's callback was used (and rejected as a non-Kakao host)
+	// instead of failing with CALLBACK_EXPIRED.
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	outboundRepo.AssertCalled(t, "MarkFailed", mock.Anything, "out-1", mock.Anything)
+}
+
+func TestOpenClawHandler_SendToConversation(t *testing.T) {
+	accountID := "acc-1"
+	otherAccountID := "acc-2"
+	callbackURL := "https://bot-api.kakao.com/callback/abc"
+	invalidCallbackURL := "https://example.com/callback"
+	future := time.Now().Add(time.Minute)
+	past := time.Now().Add(-time.Minute)
+
+	convRepo := &stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"fresh":   {ConversationKey: "fresh", AccountID: &accountID, State: model.PairingStatePaired, LastCallbackURL: &invalidCallbackURL, LastCallbackExpiresAt: &future},
+		"expired": {ConversationKey: "expired", AccountID: &accountID, State: model.PairingStatePaired, LastCallbackURL: &callbackURL, LastCallbackExpiresAt: &past},
+		"none":    {ConversationKey: "none", AccountID: &accountID, State: model.PairingStatePaired},
+		"foreign": {ConversationKey: "foreign", AccountID: &otherAccountID, State: model.PairingStatePaired, LastCallbackURL: &callbackURL},
+	}}
+
+	send := func(outboundRepo *mockOutboundRepo, key string) *httptest.ResponseRecorder {
+		msgService := service.NewMessageService(new(mockInboundRepo), outboundRepo, nil, nil, nil)
+		router := NewOpenClawHandler(msgService, service.NewKakaoService(nil), service.NewConversationService(convRepo, nil), nil, nil, nil, false).Routes()
+
+		body := bytes.NewBufferString(`{"response": {"text": "Hello"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/conversations/"+key+"/send", body)
+		req = req.WithContext(withAccount(req.Context(), &model.Account{ID: accountID}))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("returns 404 for another account's conversation", func(t *testing.T) {
+		rec := send(new(mockOutboundRepo), "foreign")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("returns CALLBACK_EXPIRED without a valid callback", func(t *testing.T) {
+		for _, key := range []string{"expired", "none"} {
+			rec := send(new(mockOutboundRepo), key)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, key)
+			assert.Contains(t, rec.Body.String(), "CALLBACK_EXPIRED", key)
+		}
+	})
+
+	t.Run("records the outbound message against the conversation", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		outboundRepo.On("Create", mock.Anything, mock.MatchedBy(func(p model.CreateOutboundMessageParams) bool {
+			return p.ConversationKey == "fresh" && p.AccountID == accountID && p.InboundMessageID == nil
+		})).Return(&model.OutboundMessage{ID: "out-1"}, nil)
+		// The stored URL is not a Kakao host, so delivery fails without
+		// leaving the test process.
+		outboundRepo.On("MarkFailed", mock.Anything, "out-1", mock.Anything).Return(nil)
+
+		rec := send(outboundRepo, "fresh")
+
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Contains(t, rec.Body.String(), "CALLBACK_FAILED")
+		outboundRepo.AssertExpectations(t)
+	})
+}
+
+func TestOpenClawHandler_GetConversation(t *testing.T) {
+	accountID := "acc-1"
+	otherAccountID := "acc-2"
+	nickname := "Alice"
+	pairedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	lastInbound := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	convRepo := &stubConversationRepo{
+		convs: map[string]*model.ConversationMapping{
+			"ch:user": {
+				ConversationKey:   "ch:user",
+				KakaoChannelID:    "ch",
+				PlusfriendUserKey: "user",
+				AccountID:         &accountID,
+				State:             model.PairingStatePaired,
+				Nickname:          &nickname,
+				PairedAt:          &pairedAt,
+			},
+			"foreign": {ConversationKey: "foreign", AccountID: &otherAccountID, State: model.PairingStatePaired},
+		},
+		health: map[string]model.ConnectionHealth{
+			"ch:user": {LastInboundAt: &lastInbound, QueuedCount: 2},
+		},
+	}
+
+	get := func(inboundRepo *mockInboundRepo, outboundRepo *mockOutboundRepo, key string) *httptest.ResponseRecorder {
+		msgService := service.NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+		router := NewOpenClawHandler(msgService, service.NewKakaoService(nil), service.NewConversationService(convRepo, nil), nil, nil, nil, false).Routes()
+
+		req := httptest.NewRequest(http.MethodGet, "/conversations/"+key, nil)
+		req = req.WithContext(withAccount(req.Context(), &model.Account{ID: accountID}))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("returns 404 for another account's conversation", func(t *testing.T) {
+		rec := get(new(mockInboundRepo), new(mockOutboundRepo), "foreign")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("returns 404 for an unknown conversation", func(t *testing.T) {
+		rec := get(new(mockInboundRepo), new(mockOutboundRepo), "missing")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("combines state, activity, stats and locale", func(t *testing.T) {
+		normalized := json.RawMessage(`{"version":1,"type":"text","text":"hi","locale":"ko-KR"}`)
+		inboundRepo := new(mockInboundRepo)
+		inboundRepo.On("CountByConversationKey", mock.Anything, "ch:user").Return(5, nil)
+		inboundRepo.On("CountByConversationKeySince", mock.Anything, "ch:user", mock.Anything).Return(1, nil)
+		inboundRepo.On("FindByConversationKey", mock.Anything, "ch:user", 1, 0).
+			Return([]model.InboundMessage{{ID: "msg-1", NormalizedMessage: &normalized}}, nil)
+		outboundRepo := new(mockOutboundRepo)
+		outboundRepo.On("CountByConversationKey", mock.Anything, "ch:user").Return(4, nil)
+		outboundRepo.On("CountByConversationKeySince", mock.Anything, "ch:user", mock.Anything).Return(1, nil)
+		outboundRepo.On("CountByConversationKeyAndStatus", mock.Anything, "ch:user", model.OutboundStatusFailed).Return(0, nil)
+		outboundRepo.On("CountByConversationKeyAndStatusSince", mock.Anything, "ch:user", model.OutboundStatusFailed, mock.Anything).Return(0, nil)
+
+		rec := get(inboundRepo, outboundRepo, "ch:user")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "ch:user", body["conversationKey"])
+		assert.Equal(t, "paired", body["state"])
+		assert.Equal(t, "Alice", body["nickname"])
+		assert.Equal(t, "2026-01-02T03:04:05Z", body["pairedAt"])
+		assert.Equal(t, "ko-KR", body["locale"])
+		assert.Equal(t, false, body["callbackAvailable"])
+		// The account has not opted in to sharing the Kakao profile
+		assert.NotContains(t, body, "kakaoProfile")
+
+		activity := body["lastActivity"].(map[string]any)
+		assert.Equal(t, "2026-01-03T00:00:00Z", activity["lastInboundAt"])
+		assert.Nil(t, activity["lastOutboundAt"])
+		assert.Equal(t, float64(2), activity["queuedCount"])
+
+		stats := body["stats"].(map[string]any)
+		assert.Equal(t, float64(5), stats["inbound"].(map[string]any)["total"])
+		assert.Equal(t, float64(4), stats["outbound"].(map[string]any)["total"])
+	})
+}
+
+// pausingConversationRepo keeps the delivery pause on the stubbed
+// conversations.
+type pausingConversationRepo struct {
+	stubConversationRepo
+}
+
+func (s *pausingConversationRepo) PauseDelivery(ctx context.Context, key string, autoReply *string) (*model.ConversationMapping, error) {
+	conv := s.convs[key]
+	if conv == nil {
+		return nil, nil
+	}
+	if conv.DeliveryPausedAt == nil {
+		now := time.Now()
+		conv.DeliveryPausedAt = &now
+	}
+	conv.PauseAutoReply = autoReply
+	return conv, nil
+}
+
+func (s *pausingConversationRepo) ResumeDelivery(ctx context.Context, key string) (*model.ConversationMapping, error) {
+	conv := s.convs[key]
+	if conv == nil || conv.DeliveryPausedAt == nil {
+		return nil, nil
+	}
+	conv.DeliveryPausedAt = nil
+	conv.PauseAutoReply = nil
+	return conv, nil
+}
+
+func TestOpenClawHandler_PauseResume(t *testing.T) {
+	accountID := "acc-1"
+	otherAccountID := "acc-2"
+	convRepo := &pausingConversationRepo{stubConversationRepo{convs: map[string]*model.ConversationMapping{
+		"ch:user": {ConversationKey: "ch:user", AccountID: &accountID, State: model.PairingStatePaired},
+		"foreign": {ConversationKey: "foreign", AccountID: &otherAccountID, State: model.PairingStatePaired},
+	}}}
+	normalized := json.RawMessage(`{"version":1,"type":"text","text":"hi"}`)
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("FindQueuedByConversationKey", mock.Anything, "ch:user").Return([]model.InboundMessage{
+		{ID: "msg-1", AccountID: accountID, ConversationKey: "ch:user", NormalizedMessage: &normalized},
+	}, nil)
+	publisher := &recordingPublisher{}
+	msgService := service.NewMessageService(inboundRepo, new(mockOutboundRepo), nil, nil, nil)
+	router := NewOpenClawHandler(msgService, service.NewKakaoService(nil), service.NewConversationService(convRepo, nil), publisher, nil, nil, false).Routes()
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req = req.WithContext(withAccount(req.Context(), &model.Account{ID: accountID}))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("returns 404 for another account's conversation", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, post("/conversations/foreign/pause", "").Code)
+		assert.Equal(t, http.StatusNotFound, post("/conversations/foreign/resume", "").Code)
+		assert.Nil(t, convRepo.convs["foreign"].DeliveryPausedAt)
+	})
+
+	t.Run("rejects an overlong auto reply", func(t *testing.T) {
+		rec := post("/conversations/ch:user/pause", `{"autoReply": "`+strings.Repeat("a", 1001)+`"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Nil(t, convRepo.convs["ch:user"].DeliveryPausedAt)
+	})
+
+	t.Run("pause announces the auto reply", func(t *testing.T) {
+		rec := post("/conversations/ch:user/pause", `{"autoReply": "On vacation"}`)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, true, body["paused"])
+		assert.Equal(t, "On vacation", body["autoReply"])
+		assert.NotNil(t, body["deliveryPausedAt"])
+
+		require.Len(t, publisher.events, 1)
+		assert.Equal(t, service.EventConversationPaused, publisher.events[0].Type)
+	})
+
+	t.Run("resume releases the held messages", func(t *testing.T) {
+		publisher.events = nil
+		rec := post("/conversations/ch:user/resume", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, true, body["resumed"])
+		assert.Equal(t, float64(1), body["heldCount"])
+		assert.Nil(t, convRepo.convs["ch:user"].DeliveryPausedAt)
+
+		require.Len(t, publisher.events, 2)
+		assert.Equal(t, service.EventConversationResumed, publisher.events[0].Type)
+		assert.Equal(t, "message", publisher.events[1].Type)
+	})
+
+	t.Run("resuming again does nothing", func(t *testing.T) {
+		publisher.events = nil
+		rec := post("/conversations/ch:user/resume", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"resumed":false`)
+		assert.Empty(t, publisher.events)
+	})
 }
 
 func TestReplyRequest_Parsing(t *testing.T) {
@@ -3,6 +3,11 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/model"
 )
 
 const (
@@ -32,3 +37,70 @@ func ParsePagination(r *http.Request) PaginationParams {
 		Offset: offset,
 	}
 }
+
+// writePage writes the {items,total,limit,offset} envelope shared by the
+// admin list endpoints. total counts every matching row, not just the page.
+func writePage(w http.ResponseWriter, items any, total int, p PaginationParams) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":  items,
+		"total":  total,
+		"limit":  p.Limit,
+		"offset": p.Offset,
+	})
+}
+
+// parseCursorPage reads the cursor and limit of a v2 list request. v1 lists
+// are not paged, so v1 requests get a zero page that selects every item. The
+// page asks for one item more than the limit so nextPage can tell whether
+// another page follows without counting.
+func parseCursorPage(r *http.Request) (model.KeysetPage, error) {
+	if httputil.APIVersionFrom(r.Context()) == httputil.APIVersionV1 {
+		return model.KeysetPage{}, nil
+	}
+	after, err := httputil.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return model.KeysetPage{}, err
+	}
+	return model.KeysetPage{After: after, Limit: ParsePagination(r).Limit + 1}, nil
+}
+
+// nextPage trims items fetched with parseCursorPage to the requested limit
+// and returns the metadata pointing at the next page, or nil for v1.
+func nextPage[T any](items []T, page model.KeysetPage, cursorOf func(T) model.Cursor) ([]T, *httputil.PageMeta) {
+	if page.Limit == 0 {
+		return items, nil
+	}
+	meta := &httputil.PageMeta{}
+	if limit := page.Limit - 1; len(items) > limit {
+		items = items[:limit]
+		meta.HasMore = true
+		meta.NextCursor = httputil.EncodeCursor(cursorOf(items[limit-1]))
+	}
+	return items, meta
+}
+
+// queryTime parses an optional RFC 3339 query parameter.
+func queryTime(r *http.Request, name string) (*time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, apperrors.InvalidInput(name, "must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
+// queryBool parses an optional true/false query parameter.
+func queryBool(r *http.Request, name string) (*bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, apperrors.InvalidInput(name, "must be true or false")
+	}
+	return &b, nil
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTime(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?createdAfter=2026-01-02T03:04:05Z&bad=yesterday", nil)
+
+	got, err := queryTime(r, "createdAfter")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.True(t, got.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	got, err = queryTime(r, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = queryTime(r, "bad")
+	assert.Error(t, err)
+}
+
+func TestQueryBool(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?suspended=false&bad=maybe", nil)
+
+	got, err := queryBool(r, "suspended")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.False(t, *got)
+
+	got, err = queryBool(r, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = queryBool(r, "bad")
+	assert.Error(t, err)
+}
@@ -4,25 +4,41 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 
 	"github.com/openclaw/relay-server-go/internal/audit"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 	"github.com/openclaw/relay-server-go/internal/middleware"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/service"
-	"github.com/openclaw/relay-server-go/internal/util"
+	"github.com/openclaw/relay-server-go/internal/sse"
 )
 
+// codeSessionCookie holds the token of a read-only portal session opened with
+// a portal access code
+const codeSessionCookie = "portal_code_session"
+
 type PortalHandler struct {
 	portalService       *service.PortalService
 	pairingService      *service.PairingService
 	portalAccessService *service.PortalAccessService
 	convService         *service.ConversationService
 	msgService          *service.MessageService
-	adminService        *service.AdminService
+	publicStats         *service.PublicStatsService
+	configService       *service.AccountConfigService
+	onboardingService   *service.OnboardingService
+	settingsService     *service.AccountSettingsService
+	historyService      *service.ChangeHistoryService
+	testMessages        *service.TestMessageService
+	webhooks            *service.WebhookDeliveryService
+	setupChecklist      *service.SetupChecklistService
+	broker              *sse.Broker
 	isProduction        bool
 }
 
@@ -32,7 +48,15 @@ func NewPortalHandler(
 	portalAccessService *service.PortalAccessService,
 	convService *service.ConversationService,
 	msgService *service.MessageService,
-	adminService *service.AdminService,
+	publicStats *service.PublicStatsService,
+	configService *service.AccountConfigService,
+	onboardingService *service.OnboardingService,
+	settingsService *service.AccountSettingsService,
+	historyService *service.ChangeHistoryService,
+	testMessages *service.TestMessageService,
+	webhooks *service.WebhookDeliveryService,
+	setupChecklist *service.SetupChecklistService,
+	broker *sse.Broker,
 	isProduction bool,
 ) *PortalHandler {
 	return &PortalHandler{
@@ -41,7 +65,15 @@ func NewPortalHandler(
 		portalAccessService: portalAccessService,
 		convService:         convService,
 		msgService:          msgService,
-		adminService:        adminService,
+		publicStats:         publicStats,
+		configService:       configService,
+		onboardingService:   onboardingService,
+		settingsService:     settingsService,
+		historyService:      historyService,
+		testMessages:        testMessages,
+		webhooks:            webhooks,
+		setupChecklist:      setupChecklist,
+		broker:              broker,
 		isProduction:        isProduction,
 	}
 }
@@ -53,6 +85,8 @@ func (h *PortalHandler) PublicRoutes() chi.Router {
 	r.Post("/api/auth/code", h.LoginWithCode)
 	r.Get("/api/code/stats", h.GetCodeStats)
 	r.Get("/api/code/messages", h.GetCodeMessages)
+	r.Post("/api/code/renew", h.RenewCodeSession)
+	r.Post("/api/code/logout", h.LogoutCodeSession)
 
 	return r
 }
@@ -63,13 +97,38 @@ func (h *PortalHandler) AuthenticatedRoutes() chi.Router {
 	r.Post("/api/logout", h.Logout)
 	r.Get("/api/me", h.Me)
 	r.Get("/api/stats", h.GetStats)
+	r.Get("/api/dashboard", h.Dashboard)
+	r.Get("/api/setup-checklist", h.GetSetupChecklist)
 	r.Post("/api/pairing/generate", h.GeneratePairingCode)
+	r.Get("/api/pairing/codes", h.ListPairingCodes)
+	r.Delete("/api/pairing/codes/{code}", h.RevokePairingCode)
 	r.Get("/api/connections", h.ListConnections)
+	r.Patch("/api/connections/{conversationKey}", h.UpdateConnection)
 	r.Post("/api/connections/{conversationKey}/unpair", h.UnpairConnection)
 	r.Patch("/api/connections/{conversationKey}/block", h.BlockConnection)
+	r.Post("/api/test-message", h.SendTestMessage)
 	r.Get("/api/token", h.GetToken)
 	r.Post("/api/token/regenerate", h.RegenerateToken)
+	r.Get("/api/account/config", h.ExportAccountConfig)
+	r.Put("/api/account/config", h.ImportAccountConfig)
+	r.Get("/api/account/timezone", h.GetAccountTimezone)
+	r.Put("/api/account/timezone", h.UpdateAccountTimezone)
+	r.Get("/api/account/profile-sharing", h.GetProfileSharing)
+	r.Put("/api/account/profile-sharing", h.UpdateProfileSharing)
+	r.Get("/api/account/onboarding", h.GetOnboarding)
+	r.Put("/api/account/onboarding", h.UpdateOnboarding)
+	r.Get("/api/account/settings", h.GetAccountSettings)
+	r.Patch("/api/account/settings", h.UpdateAccountSettings)
+	r.Get("/api/account/history", h.GetChangeHistory)
+	r.Get("/api/account/webhook", h.GetWebhook)
+	r.Put("/api/account/webhook", h.UpdateWebhook)
+	r.Delete("/api/account/webhook", h.DeleteWebhook)
+	r.Post("/api/account/webhook/rotate-secret", h.RotateWebhookSecret)
+	r.Get("/api/account/webhook/signature-sample", h.WebhookSignatureSample)
+	r.Get("/api/account/deletion-preview", h.PreviewDeleteAccount)
 	r.Delete("/api/account", h.DeleteAccount)
+	r.Get("/api/account/deletion", h.GetAccountDeletion)
+	r.Post("/api/account/deletion/cancel", h.CancelAccountDeletion)
 	r.Get("/api/messages", h.GetMessages)
 
 	return r
@@ -78,7 +137,7 @@ func (h *PortalHandler) AuthenticatedRoutes() chi.Router {
 func (h *PortalHandler) requireUser(w http.ResponseWriter, r *http.Request) *model.PortalUser {
 	user := middleware.GetPortalUser(r.Context())
 	if user == nil {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Not authenticated"})
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APINotAuthenticated)
 		return nil
 	}
 	return user
@@ -115,42 +174,28 @@ func (h *PortalHandler) Me(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
-		"user": map[string]any{
-			"id":        user.ID,
-			"email":     user.Email,
-			"accountId": user.AccountID,
-			"createdAt": user.CreatedAt.Format(time.RFC3339),
-		},
+		"user": formatPortalUser(user),
 	})
 }
 
+func formatPortalUser(user *model.PortalUser) map[string]any {
+	return map[string]any{
+		"id":        user.ID,
+		"email":     user.Email,
+		"accountId": user.AccountID,
+		"createdAt": user.CreatedAt.Format(time.RFC3339),
+	}
+}
+
 func (h *PortalHandler) GetPublicStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.adminService.GetStats(r.Context())
+	stats, err := h.publicStats.Get(r.Context())
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get public stats")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
-	publicStats := map[string]any{
-		"system": map[string]any{
-			"accounts":    stats.Accounts,
-			"connections": stats.Mappings,
-			"sessions": map[string]int{
-				"pending": stats.Sessions.Pending,
-				"paired":  stats.Sessions.Paired,
-				"total":   stats.Sessions.Total,
-			},
-		},
-		"messages": map[string]any{
-			"inbound": map[string]int{
-				"queued": stats.Messages.Inbound.Queued,
-			},
-		},
-		"isPublic": true,
-	}
-
-	writeJSON(w, http.StatusOK, publicStats)
+	writeJSON(w, http.StatusOK, stats)
 }
 
 func (h *PortalHandler) GetStats(w http.ResponseWriter, r *http.Request) {
@@ -162,7 +207,7 @@ func (h *PortalHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	conversations, err := h.convService.ListByAccountID(r.Context(), user.AccountID)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list connections for stats")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -178,13 +223,130 @@ func (h *PortalHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.msgService.GetUserStats(r.Context(), user.AccountID, connStats)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get user stats")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, stats)
 }
 
+// dashboardRecentMessages is how many messages the dashboard payload includes
+const dashboardRecentMessages = 5
+
+// Dashboard composes everything the portal dashboard needs into one payload.
+// Independent lookups run concurrently; stats and health depend on the
+// connection list and run in a second round.
+func (h *PortalHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+	ctx := r.Context()
+
+	var (
+		wg            sync.WaitGroup
+		account       *model.Account
+		accountErr    error
+		conversations []model.ConversationMapping
+		convErr       error
+		history       *service.MessageHistoryResult
+		historyErr    error
+	)
+	wg.Go(func() {
+		account, accountErr = h.portalService.GetAccountByID(ctx, user.AccountID)
+	})
+	wg.Go(func() {
+		conversations, convErr = h.convService.ListByAccountID(ctx, user.AccountID)
+	})
+	wg.Go(func() {
+		history, historyErr = h.msgService.GetMessageHistory(ctx, service.MessageHistoryParams{
+			AccountID: user.AccountID,
+			Limit:     dashboardRecentMessages,
+		})
+	})
+	wg.Wait()
+
+	if accountErr != nil || convErr != nil || historyErr != nil {
+		log.Error().
+			AnErr("accountErr", accountErr).
+			AnErr("convErr", convErr).
+			AnErr("historyErr", historyErr).
+			Msg("failed to load dashboard")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+	if account == nil {
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+		return
+	}
+
+	connStats := make([]service.ConnectionStat, len(conversations))
+	keys := make([]string, len(conversations))
+	for i, conv := range conversations {
+		lastSeenAt := conv.LastSeenAt
+		connStats[i] = service.ConnectionStat{
+			State:      string(conv.State),
+			LastSeenAt: &lastSeenAt,
+		}
+		keys[i] = conv.ConversationKey
+	}
+
+	var (
+		stats     *service.UserStats
+		statsErr  error
+		health    map[string]model.ConnectionHealth
+		healthErr error
+	)
+	wg.Go(func() {
+		stats, statsErr = h.msgService.GetUserStats(ctx, user.AccountID, connStats)
+	})
+	wg.Go(func() {
+		health, healthErr = h.convService.Health(ctx, keys)
+	})
+	wg.Wait()
+
+	if statsErr != nil {
+		log.Error().Err(statsErr).Msg("failed to get user stats for dashboard")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+	if healthErr != nil {
+		// Health is supplementary; still render the dashboard without it
+		log.Warn().Err(healthErr).Msg("failed to load connection health for dashboard")
+	}
+
+	queued, recentFailures := 0, 0
+	for _, connHealth := range health {
+		queued += connHealth.QueuedCount
+		recentFailures += connHealth.RecentFailureCount
+	}
+
+	messages := history.Messages
+	if messages == nil {
+		messages = []service.MessageHistoryItem{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"user": formatPortalUser(user),
+		"account": map[string]any{
+			"id":             account.ID,
+			"hasToken":       account.RelayTokenHash != nil && *account.RelayTokenHash != "",
+			"kakaoChannelId": account.KakaoChannelID,
+			"createdAt":      account.CreatedAt.Format(time.RFC3339),
+		},
+		"stats":          stats,
+		"recentMessages": messages,
+		"connections": map[string]any{
+			"total":             stats.Connections.Total,
+			"paired":            stats.Connections.Paired,
+			"blocked":           stats.Connections.Blocked,
+			"queued":            queued,
+			"recentFailures":    recentFailures,
+			"consumerConnected": h.broker != nil && h.broker.ClientCount(user.AccountID) > 0,
+		},
+	})
+}
+
 func (h *PortalHandler) GeneratePairingCode(w http.ResponseWriter, r *http.Request) {
 	user := h.requireUser(w, r)
 	if user == nil {
@@ -199,7 +361,7 @@ func (h *PortalHandler) GeneratePairingCode(w http.ResponseWriter, r *http.Reque
 	code, err := h.pairingService.GenerateCode(r.Context(), user.AccountID, req.ExpirySeconds, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to generate pairing code")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGeneratePairingCodeFailed)
 		return
 	}
 
@@ -209,30 +371,175 @@ func (h *PortalHandler) GeneratePairingCode(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+func (h *PortalHandler) ListPairingCodes(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	codes, err := h.pairingService.ListActiveCodes(r.Context(), user.AccountID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list pairing codes")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	formatted := make([]map[string]any, len(codes))
+	for i, code := range codes {
+		formatted[i] = map[string]any{
+			"code":      code.Code,
+			"expiresAt": code.ExpiresAt.Format(time.RFC3339),
+			"createdAt": code.CreatedAt.Format(time.RFC3339),
+			"metadata":  code.Metadata,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"codes": formatted,
+		"total": len(codes),
+	})
+}
+
+func (h *PortalHandler) RevokePairingCode(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	code := chi.URLParam(r, "code")
+	if code == "" {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "code")
+		return
+	}
+
+	if err := h.pairingService.RevokeCode(r.Context(), user.AccountID, code); err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIPairingCodeNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to revoke pairing code")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventCodeRevoke,
+		UserID:    user.ID,
+		AccountID: user.AccountID,
+		Details: map[string]interface{}{
+			"code": code,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
 func (h *PortalHandler) ListConnections(w http.ResponseWriter, r *http.Request) {
 	user := h.requireUser(w, r)
 	if user == nil {
 		return
 	}
 
-	conversations, err := h.convService.ListByAccountID(r.Context(), user.AccountID)
+	p := ParsePagination(r)
+	q := r.URL.Query()
+	result, err := h.convService.List(r.Context(), service.ConnectionListParams{
+		AccountID: user.AccountID,
+		State:     q.Get("state"),
+		Label:     q.Get("label"),
+		Query:     q.Get("q"),
+		Sort:      q.Get("sort"),
+		Order:     q.Get("order"),
+		Limit:     p.Limit,
+		Offset:    p.Offset,
+	})
 	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
 		log.Error().Err(err).Msg("failed to list connections")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
+	conversations := result.Connections
+
+	keys := make([]string, len(conversations))
+	for i, conv := range conversations {
+		keys[i] = conv.ConversationKey
+	}
+	health, err := h.convService.Health(r.Context(), keys)
+	if err != nil {
+		// Health is supplementary; still list the connections without it
+		log.Warn().Err(err).Msg("failed to load connection health")
+	}
+	// SSE consumers subscribe per account, so every connection shares this
+	consumerConnected := h.broker != nil && h.broker.ClientCount(user.AccountID) > 0
 
 	formatted := make([]map[string]any, len(conversations))
 	for i, conv := range conversations {
 		formatted[i] = formatConversation(conv)
+		formatted[i]["kakaoProfile"] = conv.KakaoProfile()
+		if health != nil {
+			connHealth := health[conv.ConversationKey]
+			connHealth.ConsumerConnected = consumerConnected
+			formatted[i]["health"] = connHealth
+		}
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"connections": formatted,
-		"total":       len(conversations),
+		"total":       result.Total,
+		"limit":       p.Limit,
+		"offset":      p.Offset,
+		"hasMore":     result.HasMore,
 	})
 }
 
+func (h *PortalHandler) UpdateConnection(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	conversationKey := chi.URLParam(r, "conversationKey")
+	if conversationKey == "" {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "conversationKey")
+		return
+	}
+
+	var req struct {
+		Nickname string `json:"nickname"`
+		Notes    string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	conv, err := h.convService.FindByKey(r.Context(), conversationKey)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to find conversation")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+	if conv == nil || conv.AccountID == nil || *conv.AccountID != user.AccountID {
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIConnectionNotFound)
+		return
+	}
+
+	if err := h.convService.UpdateDetails(r.Context(), conversationKey, req.Nickname, req.Notes); err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		log.Error().Err(err).Msg("failed to update connection")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateConnectionFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
 func (h *PortalHandler) UnpairConnection(w http.ResponseWriter, r *http.Request) {
 	user := h.requireUser(w, r)
 	if user == nil {
@@ -241,24 +548,27 @@ func (h *PortalHandler) UnpairConnection(w http.ResponseWriter, r *http.Request)
 
 	conversationKey := chi.URLParam(r, "conversationKey")
 	if conversationKey == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Conversation key is required"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "conversationKey")
 		return
 	}
 
+	unlock := h.convService.Lock(r.Context(), conversationKey)
+	defer unlock()
+
 	conv, err := h.convService.FindByKey(r.Context(), conversationKey)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to find conversation")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 	if conv == nil || conv.AccountID == nil || *conv.AccountID != user.AccountID {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Connection not found"})
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIConnectionNotFound)
 		return
 	}
 
 	if err := h.convService.Unpair(r.Context(), conversationKey); err != nil {
 		log.Error().Err(err).Msg("failed to unpair connection")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to unpair connection"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUnpairConnectionFailed)
 		return
 	}
 
@@ -273,18 +583,21 @@ func (h *PortalHandler) BlockConnection(w http.ResponseWriter, r *http.Request)
 
 	conversationKey := chi.URLParam(r, "conversationKey")
 	if conversationKey == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Conversation key is required"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "conversationKey")
 		return
 	}
 
+	unlock := h.convService.Lock(r.Context(), conversationKey)
+	defer unlock()
+
 	conv, err := h.convService.FindByKey(r.Context(), conversationKey)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to find conversation")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 	if conv == nil || conv.AccountID == nil || *conv.AccountID != user.AccountID {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Connection not found"})
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIConnectionNotFound)
 		return
 	}
 
@@ -297,7 +610,7 @@ func (h *PortalHandler) BlockConnection(w http.ResponseWriter, r *http.Request)
 
 	if err := h.convService.UpdateState(r.Context(), conversationKey, newState, &user.AccountID); err != nil {
 		log.Error().Err(err).Msg("failed to update connection state")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to update connection state"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateConnectionStateFailed)
 		return
 	}
 
@@ -307,6 +620,42 @@ func (h *PortalHandler) BlockConnection(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// POST /portal/api/test-message
+// Sends a synthetic message to the account's plugin and reports how far it
+// got, for users whose bot does not seem to receive messages.
+func (h *PortalHandler) SendTestMessage(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	result := h.testMessages.Run(r.Context(), user.AccountID)
+	log.Info().
+		Str("accountId", user.AccountID).
+		Bool("ok", result.OK).
+		Msg("portal test message finished")
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GetSetupChecklist returns which setup steps the account has completed, for
+// the checklist shown to new users.
+func (h *PortalHandler) GetSetupChecklist(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	checklist, err := h.setupChecklist.Get(r.Context(), user.AccountID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get setup checklist")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetSetupChecklistFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checklist)
+}
+
 func (h *PortalHandler) GetToken(w http.ResponseWriter, r *http.Request) {
 	user := h.requireUser(w, r)
 	if user == nil {
@@ -316,11 +665,11 @@ func (h *PortalHandler) GetToken(w http.ResponseWriter, r *http.Request) {
 	account, err := h.portalService.GetAccountByID(r.Context(), user.AccountID)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get account")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 	if account == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Account not found"})
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
 		return
 	}
 
@@ -342,7 +691,7 @@ func (h *PortalHandler) RegenerateToken(w http.ResponseWriter, r *http.Request)
 	account, newToken, err := h.portalService.RegenerateToken(r.Context(), user.AccountID)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to regenerate token")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to regenerate token"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIRegenerateTokenFailed)
 		return
 	}
 
@@ -354,6 +703,7 @@ func (h *PortalHandler) RegenerateToken(w http.ResponseWriter, r *http.Request)
 			"regenerated_by": "portal_user",
 		},
 	})
+	h.setupChecklist.Record(r.Context(), user.AccountID, model.SetupStepTokenRetrieved)
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"token":     newToken,
@@ -361,6 +711,87 @@ func (h *PortalHandler) RegenerateToken(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// ExportAccountConfig returns the user's account configuration as a JSON
+// download for moving to another relay.
+func (h *PortalHandler) ExportAccountConfig(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	cfg, err := h.configService.Export(r.Context(), user.AccountID)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to export account config")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIExportConfigFailed)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", accountConfigDisposition(user.AccountID))
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+// ImportAccountConfig applies the connection labels of an exported
+// configuration. Mode and rate limit stay under operator control.
+func (h *PortalHandler) ImportAccountConfig(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var cfg service.AccountConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	result, err := h.configService.Import(r.Context(), user.AccountID, cfg, false)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		log.Error().Err(err).Msg("failed to import account config")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIImportConfigFailed)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventConfigImport,
+		UserID:    user.ID,
+		AccountID: user.AccountID,
+		Details: map[string]interface{}{
+			"imported_by":    "self",
+			"labels_applied": result.LabelsApplied,
+		},
+	})
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *PortalHandler) PreviewDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	preview, err := h.portalService.PreviewDeleteAccount(r.Context(), user.ID)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to preview account deletion")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preview)
+}
+
 func (h *PortalHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 	user := h.requireUser(w, r)
 	if user == nil {
@@ -368,35 +799,491 @@ func (h *PortalHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Confirm string `json:"confirm"`
+		Confirm      string `json:"confirm"`
+		PreviewToken string `json:"previewToken"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
 		return
 	}
 
 	if req.Confirm != "DELETE" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Please confirm deletion by sending {\"confirm\": \"DELETE\"}"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIConfirmDeletion)
+		return
+	}
+
+	account, err := h.portalService.ScheduleAccountDeletion(r.Context(), user.ID, req.PreviewToken)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
+		if apperrors.GetCode(err) == apperrors.ErrCodeLegalHold {
+			writeError(w, r, http.StatusConflict, apperrors.ErrCodeLegalHold, i18n.APILegalHold)
+			return
+		}
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to schedule account deletion")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIDeleteAccountFailed)
 		return
 	}
 
 	audit.LogFromRequest(r, audit.Event{
-		Type:      audit.EventAccountDelete,
+		Type:      audit.EventDeleteSchedule,
 		UserID:    user.ID,
 		AccountID: user.AccountID,
 		Details: map[string]interface{}{
-			"deleted_by": "self",
+			"deleted_by":   "self",
+			"scheduled_at": account.DeletionScheduledAt,
 		},
 	})
 
-	if err := h.portalService.DeleteAccount(r.Context(), user.ID); err != nil {
-		log.Error().Err(err).Msg("failed to delete account")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to delete account"})
+	writeJSON(w, http.StatusAccepted, accountDeletionStatus(account))
+}
+
+// GetAccountDeletion reports whether the user's account is scheduled for deletion.
+func (h *PortalHandler) GetAccountTimezone(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
 		return
 	}
 
-	middleware.ClearSessionCookie(w, middleware.PortalSessionCookie, "/portal")
-	w.WriteHeader(http.StatusNoContent)
+	account, err := h.portalService.GetAccountByID(r.Context(), user.AccountID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get account")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetAccountFailed)
+		return
+	}
+	if account == nil {
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"timezone": account.Location().String()})
+}
+
+func (h *PortalHandler) UpdateAccountTimezone(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	account, err := h.portalService.UpdateTimezone(r.Context(), user.AccountID, req.Timezone)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			switch appErr.Code {
+			case apperrors.ErrCodeInvalidInput:
+				writeAppError(w, r, appErr)
+				return
+			case apperrors.ErrCodeNotFound:
+				writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+				return
+			}
+		}
+		log.Error().Err(err).Msg("failed to update account timezone")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateTimezoneFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"timezone": account.Timezone})
+}
+
+// GetProfileSharing reports whether plugins receive the Kakao user profile.
+func (h *PortalHandler) GetProfileSharing(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	account, err := h.portalService.GetAccountByID(r.Context(), user.AccountID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get account")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetAccountFailed)
+		return
+	}
+	if account == nil {
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"shareKakaoProfile": account.ShareKakaoProfile})
+}
+
+func (h *PortalHandler) UpdateProfileSharing(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req struct {
+		ShareKakaoProfile *bool `json:"shareKakaoProfile"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ShareKakaoProfile == nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "shareKakaoProfile")
+		return
+	}
+
+	account, err := h.portalService.UpdateShareKakaoProfile(r.Context(), user.AccountID, *req.ShareKakaoProfile)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to update profile sharing")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateProfileSharingFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"shareKakaoProfile": account.ShareKakaoProfile})
+}
+
+// GetOnboarding returns the welcome sequence sent to Kakao users when they
+// pair with the account.
+func (h *PortalHandler) GetOnboarding(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	onboarding, err := h.onboardingService.Get(r.Context(), user.AccountID)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to get onboarding settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetOnboardingFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, onboarding)
+}
+
+func (h *PortalHandler) UpdateOnboarding(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req model.Onboarding
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	onboarding, err := h.onboardingService.Update(r.Context(), user.AccountID, req)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			switch appErr.Code {
+			case apperrors.ErrCodeInvalidInput:
+				writeAppError(w, r, appErr)
+				return
+			case apperrors.ErrCodeNotFound:
+				writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+				return
+			}
+		}
+		log.Error().Err(err).Msg("failed to update onboarding settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateOnboardingFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, onboarding)
+}
+
+// GetAccountSettings lists the account's settings with their values and
+// defaults.
+func (h *PortalHandler) GetAccountSettings(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	settings, err := h.settingsService.List(r.Context(), user.AccountID)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to get account settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetAccountSettingsFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"settings": settings})
+}
+
+// UpdateAccountSettings changes the settings in the body by key; null resets
+// a setting to its default. Admin-only settings are rejected.
+func (h *PortalHandler) UpdateAccountSettings(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	settings, changes, err := h.settingsService.Update(r.Context(), user.AccountID, req, false)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			switch appErr.Code {
+			case apperrors.ErrCodeInvalidInput:
+				writeAppError(w, r, appErr)
+				return
+			case apperrors.ErrCodeNotFound:
+				writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+				return
+			}
+		}
+		log.Error().Err(err).Msg("failed to update account settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateAccountSettingsFailed)
+		return
+	}
+
+	if len(changes) > 0 {
+		audit.LogFromRequest(r, audit.Event{
+			Type:      audit.EventSettingsUpdate,
+			UserID:    user.ID,
+			AccountID: user.AccountID,
+			Details: map[string]interface{}{
+				"updated_by": "self",
+				"changes":    changes,
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"settings": settings})
+}
+
+// GetChangeHistory lists changes to the account's settings and the states of
+// its conversations, newest first.
+func (h *PortalHandler) GetChangeHistory(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	p := ParsePagination(r)
+	filter, err := parseChangeHistoryFilter(r)
+	if err != nil {
+		writeAppError(w, r, err)
+		return
+	}
+	filter.AccountID = user.AccountID
+
+	entries, total, err := h.historyService.List(r.Context(), filter, p.Limit, p.Offset)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get change history")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetChangeHistoryFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"entries": entries,
+		"total":   total,
+		"hasMore": p.Offset+len(entries) < total,
+	})
+}
+
+// GetWebhook returns the URL inbound messages are posted to instead of the
+// event stream; it is null when webhook delivery is off.
+func (h *PortalHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	settings, err := h.webhooks.Get(r.Context(), user.AccountID)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to get webhook settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetWebhookFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateWebhook turns webhook delivery on. Every update generates a new
+// signing secret, which the response carries only this once.
+func (h *PortalHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
+		return
+	}
+
+	settings, err := h.webhooks.Set(r.Context(), user.AccountID, req.URL)
+	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok {
+			switch appErr.Code {
+			case apperrors.ErrCodeInvalidInput:
+				writeAppError(w, r, appErr)
+				return
+			case apperrors.ErrCodeNotFound:
+				writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+				return
+			}
+		}
+		log.Error().Err(err).Msg("failed to update webhook settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateWebhookFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// DeleteWebhook turns webhook delivery off, so messages are consumed over the
+// event stream again.
+func (h *PortalHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	if err := h.webhooks.Remove(r.Context(), user.AccountID); err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("failed to delete webhook settings")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateWebhookFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, service.WebhookSettings{})
+}
+
+// RotateWebhookSecret replaces the webhook signing secret. The new secret is
+// returned only this once; the old one keeps signing requests until
+// previousSecretExpiresAt.
+func (h *PortalHandler) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	settings, err := h.webhooks.RotateSecret(r.Context(), user.AccountID)
+	if err != nil {
+		switch apperrors.GetCode(err) {
+		case apperrors.ErrCodeNotFound:
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+			return
+		case apperrors.ErrCodeConflict:
+			writeError(w, r, http.StatusConflict, apperrors.ErrCodeConflict, i18n.APIWebhookNotConfigured)
+			return
+		}
+		log.Error().Err(err).Msg("failed to rotate webhook secret")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIUpdateWebhookFailed)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventWebhookRotate,
+		AccountID: user.AccountID,
+		Details: map[string]interface{}{
+			"previous_secret_expires_at": formatTime(settings.PreviousSecretExpiresAt),
+		},
+	})
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// WebhookSignatureSample returns a webhook request signed with example
+// secrets and code verifying its signature.
+func (h *PortalHandler) WebhookSignatureSample(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	sample, err := h.webhooks.SignatureSample()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build webhook signature sample")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetWebhookFailed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sample)
+}
+
+func (h *PortalHandler) GetAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	account, err := h.portalService.GetAccountByID(r.Context(), user.AccountID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get account")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIGetAccountFailed)
+		return
+	}
+	if account == nil {
+		writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIAccountNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, accountDeletionStatus(account))
+}
+
+func (h *PortalHandler) CancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	user := h.requireUser(w, r)
+	if user == nil {
+		return
+	}
+
+	account, err := h.portalService.CancelAccountDeletion(r.Context(), user.ID)
+	if err != nil {
+		if apperrors.GetCode(err) == apperrors.ErrCodeNotFound {
+			writeError(w, r, http.StatusNotFound, apperrors.ErrCodeNotFound, i18n.APIDeletionNotScheduled)
+			return
+		}
+		log.Error().Err(err).Msg("failed to cancel account deletion")
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APICancelDeletionFailed)
+		return
+	}
+
+	audit.LogFromRequest(r, audit.Event{
+		Type:      audit.EventDeleteCancel,
+		UserID:    user.ID,
+		AccountID: user.AccountID,
+	})
+
+	writeJSON(w, http.StatusOK, accountDeletionStatus(account))
+}
+
+func accountDeletionStatus(account *model.Account) map[string]any {
+	return map[string]any{
+		"scheduled":           account.DeletionScheduledAt != nil,
+		"requestedAt":         account.DeletionRequestedAt,
+		"deletionScheduledAt": account.DeletionScheduledAt,
+	}
 }
 
 func (h *PortalHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
@@ -407,7 +1294,7 @@ func (h *PortalHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 
 	msgType := r.URL.Query().Get("type")
 	if msgType != "" && msgType != "inbound" && msgType != "outbound" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid type parameter"})
+		writeAppError(w, r, apperrors.InvalidInput("type", "must be inbound or outbound"))
 		return
 	}
 
@@ -426,14 +1313,20 @@ func (h *PortalHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := h.msgService.GetMessageHistory(r.Context(), service.MessageHistoryParams{
-		AccountID: user.AccountID,
-		Type:      msgType,
-		Limit:     limit,
-		Offset:    offset,
+		AccountID:       user.AccountID,
+		Type:            msgType,
+		ConversationKey: r.URL.Query().Get("conversationKey"),
+		Status:          r.URL.Query().Get("status"),
+		Limit:           limit,
+		Offset:          offset,
 	})
 	if err != nil {
+		if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code == apperrors.ErrCodeInvalidInput {
+			writeAppError(w, r, appErr)
+			return
+		}
 		log.Error().Err(err).Msg("failed to get message history")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
 		return
 	}
 
@@ -457,63 +1350,45 @@ func (h *PortalHandler) LoginWithCode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeValidation, i18n.APIInvalidBody)
 		return
 	}
 
 	if req.Code == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Code is required"})
+		writeError(w, r, http.StatusBadRequest, apperrors.ErrCodeMissingRequired, i18n.APIMissingRequired, "code")
 		return
 	}
 
 	// Rate limit check: 5 times per 1 minute per IP
-	clientIP := getClientIP(r)
+	clientIP := httputil.ClientIP(r)
 	allowed, resetAt := h.portalAccessService.CheckLoginLimit(r.Context(), clientIP)
 	if !allowed {
 		secondsLeft := int(time.Until(resetAt).Seconds()) + 1
 		log.Warn().
 			Str("ip", clientIP).
-			Str("code", util.MaskCode(req.Code)).
+			Str("code", req.Code).
 			Msg("code login rate limit exceeded")
 
 		w.Header().Set("Retry-After", fmt.Sprintf("%d", secondsLeft))
-		writeJSON(w, http.StatusTooManyRequests, map[string]string{
-			"error": "Too many login attempts. Please try again later.",
-		})
+		writeError(w, r, http.StatusTooManyRequests, apperrors.ErrCodeRateLimitExceeded, i18n.APITooManyLoginAttempts)
 		return
 	}
 
 	conversationKey, err := h.portalAccessService.VerifyCode(r.Context(), req.Code)
 	if err != nil {
-		log.Warn().Err(err).Str("code", util.MaskCode(req.Code)).Msg("invalid portal code")
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Invalid or expired code"})
+		log.Warn().Err(err).Str("code", req.Code).Msg("invalid portal code")
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIInvalidCode)
 		return
 	}
 
-	session, err := h.portalAccessService.CreateCodeSession(conversationKey)
+	session, err := h.portalAccessService.CreateAndStoreCodeSession(r.Context(), conversationKey)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create code session")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APICreateSessionFailed)
 		return
 	}
 
-	// Store session in Redis
-	if err := h.portalAccessService.StoreSession(r.Context(), session); err != nil {
-		log.Error().Err(err).Msg("failed to store session")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
-		return
-	}
-
-	// Set cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "portal_code_session",
-		Value:    session.Token,
-		Path:     "/portal",
-		MaxAge:   1800, // 30 minutes
-		HttpOnly: true,
-		Secure:   h.isProduction,
-		SameSite: http.SameSiteLaxMode,
-	})
+	h.setCodeSessionCookie(w, session)
 
 	audit.LogFromRequest(r, audit.Event{
 		Type: "code_login",
@@ -526,20 +1401,70 @@ func (h *PortalHandler) LoginWithCode(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{
 		"success":         true,
 		"conversationKey": conversationKey,
+		"expiresAt":       session.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// RenewCodeSession extends an active code session, sliding its expiry forward
+// up to the session's maximum lifetime
+func (h *PortalHandler) RenewCodeSession(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(codeSessionCookie)
+	if err != nil || cookie.Value == "" {
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APINotAuthenticated)
+		return
+	}
+
+	session, err := h.portalAccessService.RenewCodeSession(r.Context(), cookie.Value)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APINotAuthenticated)
+		return
+	}
+
+	h.setCodeSessionCookie(w, session)
+	setCodeSessionExpiryHeaders(w, session)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success":      true,
+		"expiresAt":    session.ExpiresAt.Format(time.RFC3339),
+		"maxExpiresAt": session.MaxExpiresAt().Format(time.RFC3339),
 	})
 }
 
+// LogoutCodeSession deletes the code session. It succeeds even if the session
+// has already expired.
+func (h *PortalHandler) LogoutCodeSession(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(codeSessionCookie)
+	if err == nil && cookie.Value != "" {
+		conversationKey, _ := h.portalAccessService.ValidateCodeSession(r.Context(), cookie.Value)
+		if err := h.portalAccessService.DeleteCodeSession(r.Context(), cookie.Value); err != nil {
+			log.Error().Err(err).Msg("failed to delete code session")
+			writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APILogoutFailed)
+			return
+		}
+		if conversationKey != "" {
+			audit.LogFromRequest(r, audit.Event{
+				Type: "code_logout",
+				Details: map[string]interface{}{
+					"conversationKey": conversationKey,
+				},
+			})
+		}
+	}
+
+	middleware.ClearSessionCookie(w, codeSessionCookie, "/portal")
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
 func (h *PortalHandler) GetCodeStats(w http.ResponseWriter, r *http.Request) {
-	conversationKey := h.getCodeSessionConversationKey(r)
+	conversationKey := h.getCodeSessionConversationKey(w, r)
 	if conversationKey == "" {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Not authenticated"})
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APINotAuthenticated)
 		return
 	}
 
 	stats, err := h.msgService.GetConversationStats(r.Context(), conversationKey)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get conversation stats")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch stats"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIFetchStatsFailed)
 		return
 	}
 
@@ -547,9 +1472,9 @@ func (h *PortalHandler) GetCodeStats(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *PortalHandler) GetCodeMessages(w http.ResponseWriter, r *http.Request) {
-	conversationKey := h.getCodeSessionConversationKey(r)
+	conversationKey := h.getCodeSessionConversationKey(w, r)
 	if conversationKey == "" {
-		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Not authenticated"})
+		writeError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APINotAuthenticated)
 		return
 	}
 
@@ -572,7 +1497,7 @@ func (h *PortalHandler) GetCodeMessages(w http.ResponseWriter, r *http.Request)
 	})
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get conversation messages")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch messages"})
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIFetchMessagesFailed)
 		return
 	}
 
@@ -588,26 +1513,40 @@ func (h *PortalHandler) GetCodeMessages(w http.ResponseWriter, r *http.Request)
 	})
 }
 
-func (h *PortalHandler) getCodeSessionConversationKey(r *http.Request) string {
-	cookie, err := r.Cookie("portal_code_session")
+// getCodeSessionConversationKey returns the conversation of a valid code
+// session, or "" if there is none, and reports the session expiry in headers.
+func (h *PortalHandler) getCodeSessionConversationKey(w http.ResponseWriter, r *http.Request) string {
+	cookie, err := r.Cookie(codeSessionCookie)
 	if err != nil {
 		return ""
 	}
 
-	conversationKey, err := h.portalAccessService.ValidateCodeSession(r.Context(), cookie.Value)
+	session, err := h.portalAccessService.GetCodeSession(r.Context(), cookie.Value)
 	if err != nil {
 		return ""
 	}
 
-	return conversationKey
+	setCodeSessionExpiryHeaders(w, session)
+	return session.ConversationKey
 }
 
-func getClientIP(r *http.Request) string {
-	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-		return forwarded
-	}
-	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-		return realIP
+func (h *PortalHandler) setCodeSessionCookie(w http.ResponseWriter, session *service.PortalCodeSession) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     codeSessionCookie,
+		Value:    session.Token,
+		Path:     "/portal",
+		MaxAge:   int(time.Until(session.ExpiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   h.isProduction,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// setCodeSessionExpiryHeaders tells the client when the code session expires,
+// and warns once it is close enough that the client should renew.
+func setCodeSessionExpiryHeaders(w http.ResponseWriter, session *service.PortalCodeSession) {
+	w.Header().Set("X-Session-Expires-At", session.ExpiresAt.Format(time.RFC3339))
+	if session.ExpiringSoon() {
+		w.Header().Set("X-Session-Expiring", "true")
 	}
-	return r.RemoteAddr
 }
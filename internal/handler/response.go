@@ -5,7 +5,9 @@ import (
 	"strconv"
 	"time"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
@@ -13,6 +15,22 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	httputil.WriteJSON(w, status, data)
 }
 
+// writeError writes a portal or admin API error in the request's locale.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code apperrors.ErrorCode, key i18n.Key, args ...any) {
+	httputil.WriteLocalizedError(w, r, status, code, key, args...)
+}
+
+// writeAppError writes a service error in the request's locale. Errors that
+// are not AppErrors are reported as internal errors.
+func writeAppError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APIInternalError)
+		return
+	}
+	httputil.WriteLocalizedAppError(w, r, appErr)
+}
+
 func formatTime(t *time.Time) any {
 	if t == nil {
 		return nil
@@ -24,11 +42,22 @@ func formatConversation(conv model.ConversationMapping) map[string]any {
 	return map[string]any{
 		"conversationKey": conv.ConversationKey,
 		"state":           conv.State,
+		"nickname":        conv.Nickname,
+		"notes":           conv.Notes,
 		"pairedAt":        formatTime(conv.PairedAt),
 		"lastSeenAt":      conv.LastSeenAt.Format(time.RFC3339),
+		"contentConsent":  conv.ContentConsent,
+		// Set while the agent has paused delivery
+		"deliveryPausedAt": formatTime(conv.DeliveryPausedAt),
 	}
 }
 
 func parseIntParam(s string) (int, error) {
 	return strconv.Atoi(s)
 }
+
+// accountConfigDisposition names the download of an exported account
+// configuration.
+func accountConfigDisposition(accountID string) string {
+	return `attachment; filename="relay-config-` + accountID + `.json"`
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+)
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestWriteError_Localized(t *testing.T) {
+	serve := func(locale i18n.Locale) map[string]any {
+		r := httptest.NewRequest(http.MethodGet, "/portal/api/me", nil)
+		r = r.WithContext(i18n.WithLocale(r.Context(), locale))
+		rec := httptest.NewRecorder()
+		writeError(rec, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APINotAuthenticated)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		return decodeError(t, rec)
+	}
+
+	en := serve(i18n.English)
+	ko := serve(i18n.Korean)
+	assert.Equal(t, "UNAUTHORIZED", en["code"])
+	assert.Equal(t, en["code"], ko["code"])
+	assert.Equal(t, i18n.T(i18n.English, i18n.APINotAuthenticated), en["error"])
+	assert.Equal(t, i18n.T(i18n.Korean, i18n.APINotAuthenticated), ko["error"])
+	assert.NotEqual(t, en["error"], ko["error"])
+}
+
+func TestWriteAppError(t *testing.T) {
+	serve := func(err error) (*httptest.ResponseRecorder, map[string]any) {
+		r := httptest.NewRequest(http.MethodGet, "/admin/api/accounts", nil)
+		r = r.WithContext(i18n.WithLocale(r.Context(), i18n.English))
+		rec := httptest.NewRecorder()
+		writeAppError(rec, r, err)
+		return rec, decodeError(t, rec)
+	}
+
+	t.Run("invalid input keeps field and reason", func(t *testing.T) {
+		rec, body := serve(apperrors.InvalidInput("status", "must be one of: active, suspended"))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "INVALID_INPUT", body["code"])
+		assert.Equal(t, "Invalid status: must be one of: active, suspended", body["error"])
+		assert.Equal(t, map[string]any{"field": "status", "reason": "must be one of: active, suspended"}, body["details"])
+	})
+
+	t.Run("mapped codes are translated", func(t *testing.T) {
+		rec, body := serve(apperrors.LegalHold("account"))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "LEGAL_HOLD", body["code"])
+		assert.Equal(t, i18n.T(i18n.English, i18n.APILegalHold), body["error"])
+	})
+
+	t.Run("plain errors are internal", func(t *testing.T) {
+		rec, body := serve(errors.New("boom"))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "INTERNAL_ERROR", body["code"])
+		assert.NotContains(t, body["error"], "boom")
+	})
+}
@@ -1,11 +1,19 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 
+	"github.com/openclaw/relay-server-go/internal/config"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/middleware"
 	"github.com/openclaw/relay-server-go/internal/service"
 	"github.com/openclaw/relay-server-go/internal/util"
 )
@@ -25,29 +33,38 @@ func (h *SessionHandler) Routes() chi.Router {
 
 	r.Post("/create", h.CreateSession)
 	r.Get("/{sessionToken}/status", h.GetSessionStatus)
+	r.Get("/{sessionToken}/link", h.GetPairingLink)
 
 	return r
 }
 
 // POST /v1/sessions/create
+// A plugin that already has an account may send its relay token so the
+// pairing link of the new session uses the account's Kakao channel.
 func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	result, err := h.sessionService.CreateSession(ctx)
+	pluginVersion := strings.TrimSpace(r.Header.Get(middleware.PluginVersionHeader))
+
+	result, err := h.sessionService.CreateSession(ctx, httputil.ClientIP(r), pluginVersion, middleware.BearerToken(r))
 	if err != nil {
+		if apperrors.IsAppError(err) {
+			httputil.RespondError(w, r, err)
+			return
+		}
 		log.Error().Err(err).Msg("failed to create session")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
+		httputil.RespondLegacyError(w, r, http.StatusInternalServerError, apperrors.Internal("Failed to create session"))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	httputil.Respond(w, r, http.StatusOK, result)
 }
 
 // GET /v1/sessions/{sessionToken}/status
 func (h *SessionHandler) GetSessionStatus(w http.ResponseWriter, r *http.Request) {
 	sessionToken := chi.URLParam(r, "sessionToken")
 	if sessionToken == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Session token is required"})
+		httputil.RespondLegacyError(w, r, http.StatusBadRequest, apperrors.ValidationError("Session token is required"))
 		return
 	}
 
@@ -57,15 +74,97 @@ func (h *SessionHandler) GetSessionStatus(w http.ResponseWriter, r *http.Request
 	result, err := h.sessionService.GetStatus(ctx, tokenHash)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get session status")
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Internal server error"})
+		httputil.RespondLegacyError(w, r, http.StatusInternalServerError, apperrors.Internal("Internal server error"))
+		return
+	}
+
+	if result == nil {
+		httputil.RespondLegacyError(w, r, http.StatusNotFound, apperrors.NotFound("Session"))
+		return
+	}
+
+	httputil.Respond(w, r, http.StatusOK, result)
+}
+
+// GET /v1/sessions/{sessionToken}/link
+func (h *SessionHandler) GetPairingLink(w http.ResponseWriter, r *http.Request) {
+	sessionToken := chi.URLParam(r, "sessionToken")
+	if sessionToken == "" {
+		httputil.RespondLegacyError(w, r, http.StatusBadRequest, apperrors.ValidationError("Session token is required"))
+		return
+	}
+
+	result, err := h.sessionService.PairingLink(r.Context(), util.HashToken(sessionToken))
+	if err != nil {
+		if apperrors.IsAppError(err) {
+			httputil.RespondError(w, r, err)
+			return
+		}
+		log.Error().Err(err).Msg("failed to build pairing link")
+		httputil.RespondLegacyError(w, r, http.StatusInternalServerError, apperrors.Internal("Internal server error"))
+		return
+	}
+
+	if result == nil {
+		httputil.RespondLegacyError(w, r, http.StatusNotFound, apperrors.NotFound("Session"))
+		return
+	}
+
+	httputil.Respond(w, r, http.StatusOK, result)
+}
+
+// GET /v1/sessions/wait?timeout=60s
+// Long-polls until the authenticated session is paired or expired, or the
+// timeout elapses, and returns the session status at that point.
+func (h *SessionHandler) WaitForPairing(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSession(r.Context())
+	if session == nil {
+		httputil.RespondLegacyError(w, r, http.StatusUnauthorized, apperrors.Unauthorized("Unauthorized"))
+		return
+	}
+
+	timeout, err := parseWaitTimeout(r.URL.Query().Get("timeout"))
+	if err != nil {
+		httputil.RespondLegacyError(w, r, http.StatusBadRequest, apperrors.ValidationError("Invalid timeout"))
+		return
+	}
+
+	result, err := h.sessionService.WaitForPairing(r.Context(), session.ID, timeout)
+	if err != nil {
+		if r.Context().Err() != nil {
+			return
+		}
+		log.Error().Err(err).Msg("failed to wait for session pairing")
+		httputil.RespondLegacyError(w, r, http.StatusInternalServerError, apperrors.Internal("Internal server error"))
 		return
 	}
 
 	if result == nil {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Session not found"})
+		httputil.RespondLegacyError(w, r, http.StatusNotFound, apperrors.NotFound("Session"))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	httputil.Respond(w, r, http.StatusOK, result)
 }
 
+// parseWaitTimeout accepts a Go duration ("60s") or plain seconds ("60"),
+// defaulting when empty and capping at config.SessionWaitMaxTimeout.
+func parseWaitTimeout(raw string) (time.Duration, error) {
+	if raw == "" {
+		return config.SessionWaitDefaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return 0, err
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+
+	return min(timeout, config.SessionWaitMaxTimeout), nil
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+)
+
+func TestParseWaitTimeout(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{raw: "", want: config.SessionWaitDefaultTimeout},
+		{raw: "10s", want: 10 * time.Second},
+		{raw: "15", want: 15 * time.Second},
+		{raw: "10m", want: config.SessionWaitMaxTimeout},
+		{raw: "0s", wantErr: true},
+		{raw: "-5", wantErr: true},
+		{raw: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseWaitTimeout(tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
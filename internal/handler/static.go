@@ -1,85 +1,232 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/public"
+)
+
+const (
+	// Assets requested with their current content hash never change.
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	// Everything else is revalidated with the ETag on every use.
+	revalidateCacheControl = "no-cache"
 )
 
 type SPAHandler struct {
-	staticDir   string
+	files       fs.FS
 	indexFile   string
 	routePrefix string
-	absStaticDir string
+	// assets holds preloaded files with their content hashes. It is nil when
+	// serving from disk, where files are read on every request.
+	assets map[string]*staticAsset
+}
+
+type staticAsset struct {
+	content []byte
+	etag    string
+	version string
+	modTime time.Time
 }
 
-func NewSPAHandler(staticDir string, routePrefix string) *SPAHandler {
-	absDir, err := filepath.Abs(staticDir)
+// NewSPAHandler serves a single-page app from files. With preload, all files
+// are read and hashed once, and index.html references assets by content hash
+// so they can be cached as immutable.
+func NewSPAHandler(files fs.FS, routePrefix string, preload bool) *SPAHandler {
+	h := &SPAHandler{
+		files:       files,
+		indexFile:   "index.html",
+		routePrefix: routePrefix,
+	}
+	if preload {
+		assets, err := h.loadAssets()
+		if err != nil {
+			panic(fmt.Sprintf("failed to load static files for %q: %v", routePrefix, err))
+		}
+		h.assets = assets
+	}
+	return h
+}
+
+func (h *SPAHandler) loadAssets() (map[string]*staticAsset, error) {
+	assets := map[string]*staticAsset{}
+	err := fs.WalkDir(h.files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(h.files, name)
+		if err != nil {
+			return err
+		}
+		var modTime time.Time
+		if info, err := d.Info(); err == nil {
+			modTime = info.ModTime()
+		}
+		assets[name] = newStaticAsset(content, modTime)
+		return nil
+	})
 	if err != nil {
-		panic(fmt.Sprintf("failed to resolve static dir path %q: %v", staticDir, err))
+		return nil, err
 	}
-	return &SPAHandler{
-		staticDir:    staticDir,
-		indexFile:    "index.html",
-		routePrefix:  routePrefix,
-		absStaticDir: absDir,
+
+	// Point index.html at versioned asset URLs.
+	if index, ok := assets[h.indexFile]; ok {
+		content := index.content
+		for name, asset := range assets {
+			if name == h.indexFile {
+				continue
+			}
+			ref := []byte(h.routePrefix + "/" + name + `"`)
+			versioned := []byte(h.routePrefix + "/" + name + "?v=" + asset.version + `"`)
+			content = bytes.ReplaceAll(content, ref, versioned)
+		}
+		assets[h.indexFile] = newStaticAsset(content, index.modTime)
+	}
+	return assets, nil
+}
+
+func newStaticAsset(content []byte, modTime time.Time) *staticAsset {
+	sum := sha256.Sum256(content)
+	version := hex.EncodeToString(sum[:8])
+	return &staticAsset{
+		content: content,
+		etag:    `"` + version + `"`,
+		version: version,
+		modTime: modTime,
 	}
 }
 
 func (h *SPAHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Get the path relative to the route prefix
 	// First try wildcard param (for Handle("/*")), then fall back to URL path (for NotFound)
-	path := chi.URLParam(r, "*")
-	if path == "" {
-		path = strings.TrimPrefix(r.URL.Path, h.routePrefix)
-		path = strings.TrimPrefix(path, "/")
+	urlPath := chi.URLParam(r, "*")
+	if urlPath == "" {
+		urlPath = strings.TrimPrefix(r.URL.Path, h.routePrefix)
+		urlPath = strings.TrimPrefix(urlPath, "/")
 	}
 
 	// Redirect to trailing slash if at root of route (e.g., /portal -> /portal/)
-	if path == "" && !strings.HasSuffix(r.URL.Path, "/") {
+	if urlPath == "" && !strings.HasSuffix(r.URL.Path, "/") {
 		http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
 		return
 	}
 
-	if path == "" {
-		path = "/"
+	if strings.HasPrefix(urlPath, "api/") {
+		http.NotFound(w, r)
+		return
 	}
 
-	if strings.HasPrefix(path, "api/") {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
 		http.NotFound(w, r)
 		return
 	}
 
-	filePath := filepath.Join(h.staticDir, filepath.Clean(path))
+	// Path traversal protection: reject rather than silently clean, so a
+	// probe never gets index.html back.
+	if hasTraversal(urlPath) {
+		log.Warn().Str("path", urlPath).Msg("path traversal attempt blocked")
+		http.NotFound(w, r)
+		return
+	}
 
-	// Path traversal protection
-	absFilePath, err := filepath.Abs(filePath)
-	if err != nil || (!strings.HasPrefix(absFilePath, h.absStaticDir+string(filepath.Separator)) && absFilePath != h.absStaticDir) {
-		log.Warn().Str("path", path).Msg("path traversal attempt blocked")
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if name == "" {
+		name = h.indexFile
+	}
+	if !fs.ValidPath(name) {
 		http.NotFound(w, r)
 		return
 	}
 
-	info, err := os.Stat(filePath)
-	if err == nil && !info.IsDir() {
-		http.ServeFile(w, r, filePath)
+	if h.serveFile(w, r, name) {
 		return
 	}
 
-	indexPath := filepath.Join(h.staticDir, h.indexFile)
-	if _, err := os.Stat(indexPath); err != nil {
+	// A missing script or stylesheet must be a real 404; answering with
+	// index.html makes the browser fail with a MIME type error instead.
+	if isAssetPath(name) {
 		http.NotFound(w, r)
 		return
 	}
 
-	http.ServeFile(w, r, indexPath)
+	// Anything else is a client-side route (deep link) handled by the SPA.
+	if !h.serveFile(w, r, h.indexFile) {
+		http.NotFound(w, r)
+	}
+}
+
+// assetExtensions are file types that are never client-side routes.
+var assetExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true, ".json": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".txt": true,
+}
+
+func isAssetPath(name string) bool {
+	return assetExtensions[strings.ToLower(path.Ext(name))]
+}
+
+func hasTraversal(urlPath string) bool {
+	if strings.ContainsAny(urlPath, "\\\x00") {
+		return true
+	}
+	for _, segment := range strings.Split(urlPath, "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
 }
 
-func StaticFileServer(staticDir string, routePrefix string) http.Handler {
-	return NewSPAHandler(staticDir, routePrefix)
+// serveFile writes the named file and reports whether it exists.
+func (h *SPAHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	if h.assets == nil {
+		info, err := fs.Stat(h.files, name)
+		if err != nil || info.IsDir() {
+			return false
+		}
+		w.Header().Set("Cache-Control", revalidateCacheControl)
+		http.ServeFileFS(w, r, h.files, name)
+		return true
+	}
+
+	asset, ok := h.assets[name]
+	if !ok {
+		return false
+	}
+	if v := r.URL.Query().Get("v"); v != "" && v == asset.version {
+		w.Header().Set("Cache-Control", immutableCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", revalidateCacheControl)
+	}
+	w.Header().Set("ETag", asset.etag)
+	http.ServeContent(w, r, name, asset.modTime, bytes.NewReader(asset.content))
+	return true
+}
+
+// StaticFileServer serves one of the embedded UIs (app is "admin" or
+// "portal"). When diskDir is set, files are read from diskDir/app instead so
+// rebuilt bundles are picked up without restarting (development).
+func StaticFileServer(diskDir, app, routePrefix string) http.Handler {
+	if diskDir != "" {
+		return NewSPAHandler(os.DirFS(filepath.Join(diskDir, app)), routePrefix, false)
+	}
+	files, err := fs.Sub(public.FS, app)
+	if err != nil {
+		panic(fmt.Sprintf("embedded static files for %q not found: %v", app, err))
+	}
+	return NewSPAHandler(files, routePrefix, true)
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,7 +33,7 @@ func TestSPAHandler(t *testing.T) {
 	err = os.WriteFile(filepath.Join(tmpDir, "app.js"), []byte(jsContent), 0644)
 	require.NoError(t, err)
 
-	handler := NewSPAHandler(tmpDir, "")
+	handler := NewSPAHandler(os.DirFS(tmpDir), "", false)
 
 	t.Run("serves index.html for root path", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/", nil)
@@ -74,6 +75,49 @@ func TestSPAHandler(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), "Index")
 	})
 
+	t.Run("returns 404 for missing assets", func(t *testing.T) {
+		for _, path := range []string{"/missing.js", "/assets/app.css", "/favicon.ico"} {
+			req := httptest.NewRequest("GET", path, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, path)
+			assert.NotContains(t, rec.Body.String(), "Index", path)
+		}
+	})
+
+	t.Run("falls back to index.html for deep links with dots", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/connections/kakao.user", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Index")
+	})
+
+	t.Run("rejects path traversal", func(t *testing.T) {
+		for _, path := range []string{"/../secret.txt", "/a/../../etc/passwd", "/..\\windows"} {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.URL.Path = path
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, path)
+		}
+	})
+
+	t.Run("returns 404 for non-GET requests", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/dashboard", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
 	t.Run("returns 404 for /api/ paths", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/api/users", nil)
 		rec := httptest.NewRecorder()
@@ -98,7 +142,7 @@ func TestSPAHandler_NoIndexFile(t *testing.T) {
 	require.NoError(t, err)
 	defer os.RemoveAll(tmpDir)
 
-	handler := NewSPAHandler(tmpDir, "")
+	handler := NewSPAHandler(os.DirFS(tmpDir), "", false)
 
 	t.Run("returns 404 when index.html is missing", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/", nil)
@@ -110,11 +154,68 @@ func TestSPAHandler_NoIndexFile(t *testing.T) {
 	})
 }
 
+func TestSPAHandler_Preloaded(t *testing.T) {
+	files := fstest.MapFS{
+		"index.html": {Data: []byte(`<html><script src="/admin/main.js"></script></html>`)},
+		"main.js":    {Data: []byte("console.log('hello');")},
+	}
+	handler := NewSPAHandler(files, "/admin", true)
+	version := handler.assets["main.js"].version
+
+	t.Run("index.html references assets by content hash", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin/", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "/admin/main.js?v="+version)
+		assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("versioned asset is immutable", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin/main.js?v="+version, nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Cache-Control"), "immutable")
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+
+	t.Run("unversioned asset is revalidated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin/main.js?v=stale", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("matching ETag returns 304", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin/main.js", nil)
+		req.Header.Set("If-None-Match", handler.assets["main.js"].etag)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+}
+
 func TestStaticFileServer(t *testing.T) {
-	t.Run("returns SPAHandler", func(t *testing.T) {
-		handler := StaticFileServer("/tmp/test", "/portal")
-		assert.NotNil(t, handler)
-		_, ok := handler.(*SPAHandler)
-		assert.True(t, ok)
+	t.Run("serves embedded files by default", func(t *testing.T) {
+		handler := StaticFileServer("", "portal", "/portal")
+		spa, ok := handler.(*SPAHandler)
+		require.True(t, ok)
+		assert.Contains(t, spa.assets, "index.html")
+	})
+
+	t.Run("serves from disk when a directory is set", func(t *testing.T) {
+		handler := StaticFileServer("/tmp/test", "portal", "/portal")
+		spa, ok := handler.(*SPAHandler)
+		require.True(t, ok)
+		assert.Nil(t, spa.assets)
 	})
 }
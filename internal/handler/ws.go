@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/middleware"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// wsRequestPaths maps the request frame types a plugin may send to the
+// OpenClaw API routes that handle them.
+var wsRequestPaths = map[string]string{
+	"reply": "/reply",
+	"ack":   "/messages/ack",
+}
+
+// WSRequest is a frame sent by the plugin. Data is the body the matching
+// OpenClaw API route takes.
+type WSRequest struct {
+	Type string          `json:"type"`
+	ID   string          `json:"id,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// WSResponse answers a WSRequest with the status and body the OpenClaw API
+// route responded with.
+type WSResponse struct {
+	Type   string          `json:"type"`
+	ID     string          `json:"id,omitempty"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// WSHandler serves the event stream over a WebSocket, for plugin hosts and
+// proxies that handle WebSockets better than SSE. Events are sent as
+// envelopes like on /v2/events, and the plugin can reply and acknowledge
+// messages over the same connection instead of separate requests.
+type WSHandler struct {
+	events *EventsHandler
+	api    http.Handler
+}
+
+// NewWSHandler creates the WebSocket handler. api serves the OpenClaw routes
+// that request frames are dispatched to.
+func NewWSHandler(events *EventsHandler, api http.Handler) *WSHandler {
+	return &WSHandler{events: events, api: api}
+}
+
+// GET /v1/ws
+func (h *WSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.events.subscribe(w, r, "websocket")
+	if !ok {
+		return
+	}
+	defer h.events.unsubscribe(r.Context(), sub)
+
+	// Accept writes the error response itself
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		log.Debug().Err(err).Str("subscribeId", sub.subscribeID).Msg("websocket upgrade failed")
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(middleware.DefaultMaxBodySize)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream := &wsStream{ctx: ctx, conn: conn, signer: h.events.signer, accountID: sub.accountID}
+	go func() {
+		// The stream ends when the plugin closes the connection
+		defer cancel()
+		h.readRequests(ctx, r, stream)
+	}()
+
+	h.events.stream(ctx, r, sub, stream)
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// readRequests answers the plugin's request frames one after another until
+// the connection fails.
+func (h *WSHandler) readRequests(ctx context.Context, r *http.Request, stream *wsStream) {
+	for {
+		msgType, data, err := stream.conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		var resp WSResponse
+		var req WSRequest
+		if msgType != websocket.MessageText || json.Unmarshal(data, &req) != nil {
+			resp = h.respondError(r, req, apperrors.ValidationError("Invalid request frame"))
+		} else {
+			resp = h.dispatch(ctx, r, req)
+		}
+
+		payload, err := json.Marshal(resp)
+		if err != nil {
+			log.Error().Err(err).Str("requestId", req.ID).Msg("failed to marshal websocket response")
+			continue
+		}
+		if err := stream.write(payload); err != nil {
+			return
+		}
+	}
+}
+
+// dispatch runs the request frame through the OpenClaw API as a POST made by
+// the connection's own session.
+func (h *WSHandler) dispatch(ctx context.Context, r *http.Request, req WSRequest) WSResponse {
+	path, ok := wsRequestPaths[req.Type]
+	if !ok {
+		return h.respondError(r, req, apperrors.InvalidInput("type", "must be reply or ack"))
+	}
+	status, body := CallAPI(ctx, h.api, r, path, req.Data)
+	return WSResponse{Type: "response", ID: req.ID, Status: status, Body: body}
+}
+
+func (h *WSHandler) respondError(r *http.Request, req WSRequest, err error) WSResponse {
+	w := NewResponseRecorder()
+	httputil.RespondError(w, r, err)
+	status, body := w.Result()
+	return WSResponse{Type: "response", ID: req.ID, Status: status, Body: body}
+}
+
+// CallAPI runs body through api as a POST to path made with the
+// credentials of r, the request that opened the stream, and returns the
+// status and JSON body the route responded with. ctx must carry r's values.
+// api authenticates the request again, so a token revoked since the stream
+// opened is refused.
+func CallAPI(ctx context.Context, api http.Handler, r *http.Request, path string, body []byte) (int, json.RawMessage) {
+	// A fresh route context so the API router matches path, not the
+	// stream's route
+	apiReq := r.Clone(context.WithValue(ctx, chi.RouteCtxKey, chi.NewRouteContext()))
+	// The query is dropped below; keep a token passed in it
+	if token := r.URL.Query().Get("token"); token != "" && apiReq.Header.Get("Authorization") == "" {
+		apiReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	apiReq.Method = http.MethodPost
+	apiReq.URL.Path = path
+	apiReq.URL.RawPath = ""
+	apiReq.URL.RawQuery = ""
+	apiReq.RequestURI = path
+	apiReq.Body = io.NopCloser(bytes.NewReader(body))
+	apiReq.ContentLength = int64(len(body))
+	apiReq.Header.Set("Content-Type", "application/json")
+
+	w := NewResponseRecorder()
+	api.ServeHTTP(w, apiReq)
+	return w.Result()
+}
+
+// ResponseRecorder captures a response to a request that did not come over
+// HTTP, such as a WebSocket request frame.
+type ResponseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func NewResponseRecorder() *ResponseRecorder {
+	return &ResponseRecorder{header: http.Header{}}
+}
+
+func (w *ResponseRecorder) Header() http.Header {
+	return w.header
+}
+
+func (w *ResponseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *ResponseRecorder) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Result returns the status, 200 when none was written, and the body when
+// it is JSON.
+func (w *ResponseRecorder) Result() (int, json.RawMessage) {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if body := bytes.TrimSpace(w.body.Bytes()); json.Valid(body) {
+		return status, body
+	}
+	return status, nil
+}
+
+// wsStream writes events to one WebSocket connection, one envelope per text
+// message. Envelopes are signed when the server signs events.
+type wsStream struct {
+	ctx       context.Context
+	conn      *websocket.Conn
+	signer    *sse.Signer
+	accountID string
+	// ID of the last event written
+	lastEventID string
+}
+
+func (s *wsStream) send(event sse.Event) error {
+	var data []byte
+	var err error
+	if s.signer != nil {
+		data, err = s.signer.SignEnvelope(event.Envelope())
+	} else {
+		data, err = json.Marshal(event.Envelope())
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.write(data); err != nil {
+		return err
+	}
+	if event.ID != "" {
+		s.lastEventID = event.ID
+	}
+	return nil
+}
+
+func (s *wsStream) sendData(eventType string, data any) error {
+	event, err := sse.NewEvent(eventType, s.accountID, "", data)
+	if err != nil {
+		return err
+	}
+	return s.send(event)
+}
+
+// heartbeat pings the plugin, which also detects a connection that went
+// away without closing.
+func (s *wsStream) heartbeat(time.Time) error {
+	ctx, cancel := context.WithTimeout(s.ctx, sse.WriteTimeout)
+	defer cancel()
+	return s.conn.Ping(ctx)
+}
+
+func (s *wsStream) resumeCursor() string {
+	return s.lastEventID
+}
+
+// write sends one text message; the connection is safe for concurrent
+// writes, so responses and events can interleave.
+func (s *wsStream) write(data []byte) error {
+	ctx, cancel := context.WithTimeout(s.ctx, sse.WriteTimeout)
+	defer cancel()
+	return s.conn.Write(ctx, websocket.MessageText, data)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+func TestWSHandler(t *testing.T) {
+	account := &model.Account{ID: "acc-1"}
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("FindQueuedByAccountID", mock.Anything, "acc-1").Return([]model.InboundMessage{}, nil)
+	inboundRepo.On("FindByID", mock.Anything, "in-1").Return(&model.InboundMessage{ID: "in-1", AccountID: "acc-1"}, nil)
+	inboundRepo.On("FindByID", mock.Anything, "in-other").Return(&model.InboundMessage{ID: "in-other", AccountID: "acc-2"}, nil)
+	inboundRepo.On("MarkAcked", mock.Anything, "in-1").Return(nil)
+	msgService := service.NewMessageService(inboundRepo, new(mockOutboundRepo), nil, nil, nil)
+
+	broker := sse.NewMemoryBroker(sse.BrokerOptions{})
+	defer broker.Close()
+	events := NewEventsHandler(broker, nil, msgService, nil, nil, nil)
+	api := NewOpenClawHandler(msgService, nil, nil, broker, nil, nil, false).Routes()
+	ws := NewWSHandler(events, api)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws.ServeHTTP(w, r.WithContext(withAccount(r.Context(), account)))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/v1/ws", nil)
+	require.NoError(t, err)
+	defer conn.CloseNow()
+
+	read := func(v any) {
+		t.Helper()
+		_, data, err := conn.Read(ctx)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, v))
+	}
+	request := func(frame string) WSResponse {
+		t.Helper()
+		require.NoError(t, conn.Write(ctx, websocket.MessageText, []byte(frame)))
+		var resp WSResponse
+		read(&resp)
+		return resp
+	}
+
+	var envelope sse.Envelope
+	read(&envelope)
+	assert.Equal(t, "connected", envelope.Type)
+
+	// Events arrive as envelopes
+	require.NoError(t, broker.Publish(ctx, "acc-1", sse.NewRawEvent("message", "acc-1", "ch:u1", json.RawMessage(`{"id":"in-1"}`))))
+	read(&envelope)
+	assert.Equal(t, "message", envelope.Type)
+	assert.Equal(t, "ch:u1", envelope.ConversationKey)
+
+	t.Run("acknowledges the account's messages", func(t *testing.T) {
+		resp := request(`{"type":"ack","id":"a1","data":{"messageIds":["in-1","in-other"]}}`)
+		assert.Equal(t, "response", resp.Type)
+		assert.Equal(t, "a1", resp.ID)
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `{"acknowledged":1}`, string(resp.Body))
+		inboundRepo.AssertNotCalled(t, "MarkAcked", mock.Anything, "in-other")
+	})
+
+	t.Run("rejects unknown request types", func(t *testing.T) {
+		resp := request(`{"type":"send","id":"s1","data":{}}`)
+		assert.Equal(t, "s1", resp.ID)
+		assert.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("rejects frames that are not JSON", func(t *testing.T) {
+		resp := request(`reply`)
+		assert.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+}
+
+func TestCallAPI(t *testing.T) {
+	// Stands in for the auth middleware the stream API runs behind
+	valid := true
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !valid || r.Header.Get("Authorization") != "Bearer rt_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	})
+
+	// The stream was opened with the token in the query
+	r := httptest.NewRequest(http.MethodGet, "/v1/ws?token=rt_token", nil)
+	status, body := CallAPI(r.Context(), api, r, "/messages/ack", []byte(`{}`))
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"path":"/messages/ack"}`, string(body))
+
+	// A token revoked after the stream opened is refused
+	valid = false
+	status, _ = CallAPI(r.Context(), api, r, "/messages/ack", []byte(`{}`))
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
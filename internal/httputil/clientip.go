@@ -0,0 +1,16 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+)
+
+// ClientIP returns the client IP for a request. It relies on the RealIP
+// middleware having already resolved forwarded headers from trusted proxies
+// into r.RemoteAddr, so headers are never read here.
+func ClientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
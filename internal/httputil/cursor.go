@@ -0,0 +1,41 @@
+package httputil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// PageMeta is the envelope metadata of v2 list responses. Pass NextCursor as
+// the cursor query parameter to fetch the next page; it is empty on the last
+// page.
+type PageMeta struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// EncodeCursor returns c as an opaque page cursor. Clients must not parse it;
+// its contents may change between releases.
+func EncodeCursor(c model.Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a cursor returned by EncodeCursor. An empty cursor
+// means the first page and decodes to nil.
+func DecodeCursor(cursor string) (*model.Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, apperrors.InvalidInput("cursor", "is not a valid page cursor")
+	}
+	var c model.Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, apperrors.InvalidInput("cursor", "is not a valid page cursor")
+	}
+	return &c, nil
+}
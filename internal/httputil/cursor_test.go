@@ -0,0 +1,36 @@
+package httputil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("round-trips", func(t *testing.T) {
+		want := model.Cursor{At: time.Date(2026, 10, 1, 12, 0, 0, 123456000, time.UTC), ID: "conv-1"}
+		got, err := DecodeCursor(EncodeCursor(want))
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.True(t, want.At.Equal(got.At))
+		assert.Equal(t, want.ID, got.ID)
+	})
+
+	t.Run("decodes an empty cursor as the first page", func(t *testing.T) {
+		got, err := DecodeCursor("")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("rejects malformed cursors", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+			_, err := DecodeCursor(cursor)
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), cursor)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package httputil
+
+import (
+	"net/http"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+)
+
+// Envelope is the v2 success response body. Meta carries list metadata such
+// as pagination cursors.
+type Envelope struct {
+	Data any `json:"data"`
+	Meta any `json:"meta,omitempty"`
+}
+
+// ErrorEnvelope is the v2 error response body.
+type ErrorEnvelope struct {
+	Error *apperrors.AppError `json:"error"`
+}
+
+// Respond writes data as-is for v1 and wrapped in an Envelope for v2.
+func Respond(w http.ResponseWriter, r *http.Request, status int, data any) {
+	RespondWithMeta(w, r, status, data, nil)
+}
+
+// RespondWithMeta is Respond with envelope metadata. v1 responses carry no
+// metadata.
+func RespondWithMeta(w http.ResponseWriter, r *http.Request, status int, data, meta any) {
+	if APIVersionFrom(r.Context()) == APIVersionV1 {
+		WriteJSON(w, status, data)
+		return
+	}
+	WriteJSON(w, status, Envelope{Data: data, Meta: meta})
+}
+
+// RespondError writes err in the error format of the request's API version,
+// with the status derived from its code.
+func RespondError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok {
+		appErr = apperrors.Internal("An unexpected error occurred")
+	}
+
+	if APIVersionFrom(r.Context()) == APIVersionV1 {
+		WriteError(w, appErr)
+		return
+	}
+	WriteJSON(w, statusFromCode(appErr.Code), ErrorEnvelope{Error: appErr})
+}
+
+// RespondLegacyError is RespondError for endpoints whose v1 errors predate
+// error codes: v1 clients keep receiving {"error": message} with the given
+// status, v2 clients get a structured error.
+func RespondLegacyError(w http.ResponseWriter, r *http.Request, status int, err *apperrors.AppError) {
+	if APIVersionFrom(r.Context()) == APIVersionV1 {
+		WriteJSON(w, status, map[string]string{"error": err.Message})
+		return
+	}
+	WriteJSON(w, status, ErrorEnvelope{Error: err})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 )
 
 func WriteJSON(w http.ResponseWriter, status int, data any) {
@@ -48,6 +49,42 @@ func WriteErrorWithStatus(w http.ResponseWriter, status int, err *apperrors.AppE
 	WriteJSON(w, status, response)
 }
 
+// WriteLocalizedError writes a portal or admin API error whose message is
+// rendered from the catalog in the locale negotiated for the request. The code
+// is the same in every locale, so clients branch on it rather than the text.
+func WriteLocalizedError(w http.ResponseWriter, r *http.Request, status int, code apperrors.ErrorCode, key i18n.Key, args ...any) {
+	WriteJSON(w, status, ErrorResponse{
+		Error: i18n.T(i18n.FromContext(r.Context()), key, args...),
+		Code:  code,
+	})
+}
+
+// appErrorMessages translates service errors by code. Errors with other codes
+// keep their own message.
+var appErrorMessages = map[apperrors.ErrorCode]i18n.Key{
+	apperrors.ErrCodeNotFound:       i18n.APINotFound,
+	apperrors.ErrCodeLegalHold:      i18n.APILegalHold,
+	apperrors.ErrCodeAlreadyPaired:  i18n.APIAlreadyPaired,
+	apperrors.ErrCodePairingExpired: i18n.APIPairingExpired,
+}
+
+// WriteLocalizedAppError is WriteLocalizedError for an error returned by a
+// service, with the status derived from its code.
+func WriteLocalizedAppError(w http.ResponseWriter, r *http.Request, err *apperrors.AppError) {
+	locale := i18n.FromContext(r.Context())
+	response := ErrorResponse{
+		Error:   err.Message,
+		Code:    err.Code,
+		Details: err.Details,
+	}
+	if details, ok := err.Details.(apperrors.InvalidInputDetails); ok {
+		response.Error = i18n.T(locale, i18n.APIInvalidInput, details.Field, details.Reason)
+	} else if key, ok := appErrorMessages[err.Code]; ok {
+		response.Error = i18n.T(locale, key)
+	}
+	WriteJSON(w, statusFromCode(err.Code), response)
+}
+
 // statusFromCode maps ErrorCode to HTTP status code
 func statusFromCode(code apperrors.ErrorCode) int {
 	switch code {
@@ -78,11 +115,22 @@ func statusFromCode(code apperrors.ErrorCode) int {
 	// 409 Conflict
 	case apperrors.ErrCodeAlreadyExists,
 		apperrors.ErrCodeConflict,
-		apperrors.ErrCodeAlreadyPaired:
+		apperrors.ErrCodeAlreadyPaired,
+		apperrors.ErrCodeAlreadyReplied,
+		apperrors.ErrCodeLegalHold:
 		return http.StatusConflict
 
+	// 410 Gone
+	case apperrors.ErrCodeHistoryExpired:
+		return http.StatusGone
+
+	// 426 Upgrade Required
+	case apperrors.ErrCodeUpgradeRequired:
+		return http.StatusUpgradeRequired
+
 	// 429 Too Many Requests
-	case apperrors.ErrCodeRateLimitExceeded:
+	case apperrors.ErrCodeRateLimitExceeded,
+		apperrors.ErrCodeTooManyPendingSessions:
 		return http.StatusTooManyRequests
 
 	// 502 Bad Gateway
@@ -90,6 +138,11 @@ func statusFromCode(code apperrors.ErrorCode) int {
 		apperrors.ErrCodeExternal:
 		return http.StatusBadGateway
 
+	// 503 Service Unavailable
+	case apperrors.ErrCodeUnavailable,
+		apperrors.ErrCodeShed:
+		return http.StatusServiceUnavailable
+
 	// 500 Internal Server Error
 	case apperrors.ErrCodeInternal,
 		apperrors.ErrCodeDatabase:
@@ -0,0 +1,33 @@
+package httputil
+
+import "context"
+
+// APIVersion identifies the version of the plugin-facing API a request was
+// routed through. Handlers are shared between versions; only the response
+// envelope differs.
+type APIVersion string
+
+const (
+	APIVersionV1 APIVersion = "v1"
+	APIVersionV2 APIVersion = "v2"
+
+	LatestAPIVersion = APIVersionV2
+)
+
+// SupportedAPIVersions lists the API versions served, oldest first.
+var SupportedAPIVersions = []APIVersion{APIVersionV1, APIVersionV2}
+
+type apiVersionKey struct{}
+
+func WithAPIVersion(ctx context.Context, version APIVersion) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// APIVersionFrom returns the API version of the request, defaulting to v1 for
+// routes that are not explicitly versioned.
+func APIVersionFrom(ctx context.Context) APIVersion {
+	if version, ok := ctx.Value(apiVersionKey{}).(APIVersion); ok {
+		return version
+	}
+	return APIVersionV1
+}
@@ -0,0 +1,712 @@
+package i18n
+
+// Chat replies to Kakao users.
+const (
+	KakaoUnpairedGreeting   Key = "kakao.unpaired_greeting"
+	KakaoHelp               Key = "kakao.help"
+	KakaoHelpPair           Key = "kakao.help.pair"
+	KakaoHelpUnpair         Key = "kakao.help.unpair"
+	KakaoHelpArchive        Key = "kakao.help.archive"
+	KakaoHelpStatus         Key = "kakao.help.status"
+	KakaoHelpCode           Key = "kakao.help.code"
+	KakaoHelpRecent         Key = "kakao.help.recent"
+	KakaoHelpConsent        Key = "kakao.help.consent"
+	KakaoHelpHelp           Key = "kakao.help.help"
+	KakaoPairCodeRequired   Key = "kakao.pair.code_required"
+	KakaoPairAlreadyPaired  Key = "kakao.pair.already_paired"
+	KakaoPairInvalidCode    Key = "kakao.pair.invalid_code"
+	KakaoPairInternalError  Key = "kakao.pair.internal_error"
+	KakaoPairFailed         Key = "kakao.pair.failed"
+	KakaoPairSuccess        Key = "kakao.pair.success"
+	KakaoOnboardingTitle    Key = "kakao.onboarding.title"
+	KakaoOnboardingIntro    Key = "kakao.onboarding.intro"
+	KakaoOnboardingPrivacy  Key = "kakao.onboarding.privacy"
+	KakaoUnpairNotPaired    Key = "kakao.unpair.not_paired"
+	KakaoUnpairFailed       Key = "kakao.unpair.failed"
+	KakaoUnpairSuccess      Key = "kakao.unpair.success"
+	KakaoArchiveNotPaired   Key = "kakao.archive.not_paired"
+	KakaoArchiveAlready     Key = "kakao.archive.already"
+	KakaoArchiveFailed      Key = "kakao.archive.failed"
+	KakaoArchiveSuccess     Key = "kakao.archive.success"
+	KakaoStatusUnknown      Key = "kakao.status.unknown"
+	KakaoStatusTitle        Key = "kakao.status.title"
+	KakaoStatusArchived     Key = "kakao.status.archived"
+	KakaoStatusPairedAt     Key = "kakao.status.paired_at"
+	KakaoStatusStats        Key = "kakao.status.stats"
+	KakaoStatusRateLimit    Key = "kakao.status.rate_limit"
+	KakaoStatusDevice       Key = "kakao.status.device"
+	KakaoStatusPortalCode   Key = "kakao.status.portal_code"
+	KakaoStatusPortalWait   Key = "kakao.status.portal_wait"
+	KakaoStatusPortalButton Key = "kakao.status.portal_button"
+	KakaoStatusNotPaired    Key = "kakao.status.not_paired"
+	KakaoCodeNotPaired      Key = "kakao.code.not_paired"
+	KakaoCodeRateLimited    Key = "kakao.code.rate_limited"
+	KakaoCodeFailed         Key = "kakao.code.failed"
+	KakaoCodeIssued         Key = "kakao.code.issued"
+	KakaoCodePortalURL      Key = "kakao.code.portal_url"
+	KakaoRecentNotPaired    Key = "kakao.recent.not_paired"
+	KakaoRecentRateLimited  Key = "kakao.recent.rate_limited"
+	KakaoRecentFailed       Key = "kakao.recent.failed"
+	KakaoRecentEmpty        Key = "kakao.recent.empty"
+	KakaoRecentTitle        Key = "kakao.recent.title"
+	KakaoRecentInbound      Key = "kakao.recent.inbound"
+	KakaoRecentOutbound     Key = "kakao.recent.outbound"
+	KakaoRecentNoText       Key = "kakao.recent.no_text"
+	KakaoRecentMore         Key = "kakao.recent.more"
+	KakaoUnsupportedMessage Key = "kakao.unsupported_message"
+	KakaoDeliveryPaused     Key = "kakao.delivery_paused"
+	KakaoAccountSuspended   Key = "kakao.account_suspended"
+	KakaoConsentPrompt      Key = "kakao.consent.prompt"
+	KakaoConsentAgree       Key = "kakao.consent.agree"
+	KakaoConsentDisagree    Key = "kakao.consent.disagree"
+	KakaoConsentGranted     Key = "kakao.consent.granted"
+	KakaoConsentDenied      Key = "kakao.consent.denied"
+	KakaoConsentNotPaired   Key = "kakao.consent.not_paired"
+	KakaoConsentFailed      Key = "kakao.consent.failed"
+	KakaoDegradedRetrying   Key = "kakao.degraded.retrying"
+	KakaoDegradedFailed     Key = "kakao.degraded.failed"
+)
+
+// Portal and admin API error messages. The response's code stays the same
+// in every locale; only the message is translated.
+const (
+	APIInternalError               Key = "api.internal_error"
+	APIInvalidBody                 Key = "api.invalid_body"
+	APIMissingRequired             Key = "api.missing_required"
+	APIInvalidInput                Key = "api.invalid_input"
+	APINotFound                    Key = "api.not_found"
+	APILegalHold                   Key = "api.legal_hold"
+	APIAlreadyPaired               Key = "api.already_paired"
+	APIPairingExpired              Key = "api.pairing_expired"
+	APINotAuthenticated            Key = "api.not_authenticated"
+	APIUnauthorized                Key = "api.unauthorized"
+	APISessionValidationFailed     Key = "api.session_validation_failed"
+	APIReauthRequired              Key = "api.reauth_required"
+	APIAdminNotConfigured          Key = "api.admin_not_configured"
+	APISecurityTokenFailed         Key = "api.security_token_failed"
+	APIMissingCSRFToken            Key = "api.missing_csrf_token"
+	APIInvalidCSRFToken            Key = "api.invalid_csrf_token"
+	APITooManyLoginAttempts        Key = "api.too_many_login_attempts"
+	APITooManyRequests             Key = "api.too_many_requests"
+	APIAccountNotFound             Key = "api.account_not_found"
+	APIConnectionNotFound          Key = "api.connection_not_found"
+	APIPairingCodeNotFound         Key = "api.pairing_code_not_found"
+	APIUserNotFound                Key = "api.user_not_found"
+	APIPendingSessionNotFound      Key = "api.pending_session_not_found"
+	APIDeletionNotScheduled        Key = "api.deletion_not_scheduled"
+	APIConfirmDeletion             Key = "api.confirm_deletion"
+	APIInvalidCode                 Key = "api.invalid_code"
+	APINothingToUpdate             Key = "api.nothing_to_update"
+	APIInvalidPassword             Key = "api.invalid_password"
+	APICurrentPasswordIncorrect    Key = "api.current_password_incorrect"
+	APIPasswordChangeRequired      Key = "api.password_change_required"
+	APIInvalidSetupToken           Key = "api.invalid_setup_token"
+	APIAlreadyBootstrapped         Key = "api.already_bootstrapped"
+	APILoginFailed                 Key = "api.login_failed"
+	APIBootstrapFailed             Key = "api.bootstrap_failed"
+	APIPasswordChangeFailed        Key = "api.password_change_failed"
+	APIReauthFailed                Key = "api.reauth_failed"
+	APICreateSessionFailed         Key = "api.create_session_failed"
+	APILogoutFailed                Key = "api.logout_failed"
+	APIGetAccountFailed            Key = "api.get_account_failed"
+	APIFetchStatsFailed            Key = "api.fetch_stats_failed"
+	APIFetchMessagesFailed         Key = "api.fetch_messages_failed"
+	APIGeneratePairingCodeFailed   Key = "api.generate_pairing_code_failed"
+	APIUpdateConnectionFailed      Key = "api.update_connection_failed"
+	APIUnpairConnectionFailed      Key = "api.unpair_connection_failed"
+	APIUpdateConnectionStateFailed Key = "api.update_connection_state_failed"
+	APIRegenerateTokenFailed       Key = "api.regenerate_token_failed"
+	APIExportConfigFailed          Key = "api.export_config_failed"
+	APIImportConfigFailed          Key = "api.import_config_failed"
+	APIDeleteAccountFailed         Key = "api.delete_account_failed"
+	APICancelDeletionFailed        Key = "api.cancel_deletion_failed"
+	APIUpdateTimezoneFailed        Key = "api.update_timezone_failed"
+	APIUpdateProfileSharingFailed  Key = "api.update_profile_sharing_failed"
+	APIGetOnboardingFailed         Key = "api.get_onboarding_failed"
+	APIUpdateOnboardingFailed      Key = "api.update_onboarding_failed"
+	APIGetWebhookFailed            Key = "api.get_webhook_failed"
+	APIUpdateWebhookFailed         Key = "api.update_webhook_failed"
+	APIWebhookNotConfigured        Key = "api.webhook_not_configured"
+	APIGetSetupChecklistFailed     Key = "api.get_setup_checklist_failed"
+	APIGetAccountSettingsFailed    Key = "api.get_account_settings_failed"
+	APIUpdateAccountSettingsFailed Key = "api.update_account_settings_failed"
+	APIGetChangeHistoryFailed      Key = "api.get_change_history_failed"
+	APIGetNotificationsFailed      Key = "api.get_notifications_failed"
+	APIUpdateNotificationsFailed   Key = "api.update_notifications_failed"
+)
+
+// Notification emails.
+const (
+	MailDeletionScheduledSubject Key = "mail.deletion_scheduled.subject"
+	MailDeletionScheduledBody    Key = "mail.deletion_scheduled.body"
+)
+
+var catalog = map[Key]map[Locale]string{
+	KakaoUnpairedGreeting: {
+		Korean: "OpenClaw에 연결되지 않았습니다.\n\n" +
+			"연결하려면 페어링 코드를 받은 후:\n" +
+			"/pair <코드>\n\n" +
+			"를 입력해주세요.\n\n" +
+			"도움말: /help",
+		English: "This chat is not connected to OpenClaw.\n\n" +
+			"Get a pairing code, then send:\n" +
+			"/pair <code>\n\n" +
+			"Help: /help",
+	},
+	KakaoHelp: {
+		Korean: "📖 도움말\n\n" +
+			"이 봇은 OpenClaw AI 에이전트와 연결하는 중계 서비스입니다.\n\n" +
+			"명령어:",
+		English: "📖 Help\n\n" +
+			"This bot relays your messages to an OpenClaw AI agent.\n\n" +
+			"Commands:",
+	},
+	KakaoHelpPair: {
+		Korean:  "/pair <코드> - OpenClaw에 연결",
+		English: "/pair <code> - connect to OpenClaw",
+	},
+	KakaoHelpUnpair: {
+		Korean:  "/unpair - 연결 해제",
+		English: "/unpair - disconnect",
+	},
+	KakaoHelpArchive: {
+		Korean:  "/archive - 연결은 유지하고 전달 일시 중지",
+		English: "/archive - pause relaying, stay connected",
+	},
+	KakaoHelpStatus: {
+		Korean:  "/status - 연결 상태 확인",
+		English: "/status - show connection status",
+	},
+	KakaoHelpCode: {
+		Korean:  "/code - 포털 접속 코드 발급",
+		English: "/code - get a portal access code",
+	},
+	KakaoHelpRecent: {
+		Korean:  "/recent - 최근 대화 보기",
+		English: "/recent - show recent messages",
+	},
+	KakaoHelpConsent: {
+		Korean:  "/consent - 메시지 내용 저장 동의 변경",
+		English: "/consent - change consent to storing messages",
+	},
+	KakaoHelpHelp: {
+		Korean:  "/help - 이 도움말",
+		English: "/help - show this help",
+	},
+	KakaoPairCodeRequired: {
+		Korean:  "페어링 코드를 입력해주세요.\n\n예: /pair ABCD-1234",
+		English: "Please enter a pairing code.\n\nExample: /pair ABCD-1234",
+	},
+	KakaoPairAlreadyPaired: {
+		Korean: "이미 OpenClaw에 연결되어 있습니다.\n\n" +
+			"다른 봇에 연결하려면 먼저 /unpair 로 연결을 해제하세요.",
+		English: "This chat is already connected to OpenClaw.\n\n" +
+			"To connect to another bot, disconnect first with /unpair.",
+	},
+	KakaoPairInvalidCode: {
+		Korean:  "❌ 유효하지 않은 코드입니다.\n\n코드를 다시 확인해주세요.",
+		English: "❌ Invalid code.\n\nPlease check the code and try again.",
+	},
+	KakaoPairInternalError: {
+		Korean:  "❌ 오류가 발생했습니다. 다시 시도해주세요.",
+		English: "❌ Something went wrong. Please try again.",
+	},
+	KakaoPairFailed: {
+		Korean:  "페어링에 실패했습니다.",
+		English: "Pairing failed.",
+	},
+	KakaoPairSuccess: {
+		Korean:  "✅ OpenClaw에 연결되었습니다!\n\n이제 자유롭게 대화를 시작하세요.",
+		English: "✅ Connected to OpenClaw!\n\nYou can start chatting now.",
+	},
+	KakaoOnboardingTitle: {
+		Korean:  "👋 시작하기",
+		English: "👋 Getting started",
+	},
+	KakaoOnboardingIntro: {
+		Korean: "이 채팅에서 보내는 메시지는 연결된 OpenClaw 에이전트에게 전달되고, 답장도 이곳으로 옵니다.\n\n" +
+			"궁금한 것을 묻거나 할 일을 부탁해 보세요. 명령어는 /help로 확인할 수 있습니다.",
+		English: "Messages you send in this chat go to your OpenClaw agent, and its replies arrive here.\n\n" +
+			"Ask a question or hand it a task. Send /help to see the commands.",
+	},
+	KakaoOnboardingPrivacy: {
+		Korean: "🔒 개인정보 안내\n\n" +
+			"메시지는 에이전트에 전달하기 위해 릴레이 서버에 일정 기간 보관됩니다. " +
+			"연결을 끊으려면 /unpair를 입력하세요.",
+		English: "🔒 Privacy\n\n" +
+			"Messages are kept on the relay server for a limited time to deliver them to your agent. " +
+			"Send /unpair to disconnect.",
+	},
+	KakaoUnpairNotPaired: {
+		Korean:  "연결된 OpenClaw가 없습니다.",
+		English: "This chat is not connected to OpenClaw.",
+	},
+	KakaoUnpairFailed: {
+		Korean:  "연결 해제에 실패했습니다. 다시 시도해주세요.",
+		English: "Failed to disconnect. Please try again.",
+	},
+	KakaoUnpairSuccess: {
+		Korean:  "연결이 해제되었습니다.\n\n다시 연결하려면 /pair <코드>를 사용하세요.",
+		English: "Disconnected.\n\nTo connect again, use /pair <code>.",
+	},
+	KakaoArchiveNotPaired: {
+		Korean:  "연결된 OpenClaw가 없습니다.",
+		English: "This chat is not connected to OpenClaw.",
+	},
+	KakaoArchiveAlready: {
+		Korean:  "이미 보관된 대화입니다.\n\n메시지를 보내면 다시 전달이 시작됩니다.",
+		English: "This chat is already archived.\n\nSend a message to resume relaying.",
+	},
+	KakaoArchiveFailed: {
+		Korean:  "보관에 실패했습니다. 다시 시도해주세요.",
+		English: "Failed to archive. Please try again.",
+	},
+	KakaoArchiveSuccess: {
+		Korean: "⏸️ 대화를 보관했습니다. 연결은 유지되지만 메시지가 전달되지 않습니다.\n\n" +
+			"메시지를 보내면 다시 전달이 시작됩니다. 연결을 끊으려면 /unpair를 사용하세요.",
+		English: "⏸️ Chat archived. You stay connected, but messages are not relayed.\n\n" +
+			"Send a message to resume relaying. To disconnect, use /unpair.",
+	},
+	KakaoStatusUnknown: {
+		Korean:  "알 수 없음",
+		English: "unknown",
+	},
+	KakaoStatusTitle: {
+		Korean:  "✅ 연결됨",
+		English: "✅ Connected",
+	},
+	KakaoStatusArchived: {
+		Korean:  "⏸️ 보관됨 (메시지를 보내면 다시 전달됩니다)",
+		English: "⏸️ Archived (send a message to resume)",
+	},
+	KakaoStatusPairedAt: {
+		Korean:  "연결 시간: %s",
+		English: "Connected at: %s",
+	},
+	KakaoStatusStats: {
+		Korean: "📊 오늘 통계\n" +
+			"• 수신: %d건\n" +
+			"• 발신: %d건 (실패 %d)\n\n" +
+			"📈 전체 통계\n" +
+			"• 총 수신: %d건\n" +
+			"• 총 발신: %d건",
+		English: "📊 Today\n" +
+			"• Received: %d\n" +
+			"• Sent: %d (failed %d)\n\n" +
+			"📈 All time\n" +
+			"• Total received: %d\n" +
+			"• Total sent: %d",
+	},
+	KakaoStatusRateLimit: {
+		Korean:  "⚡ 남은 API 한도: %d/%d (분당)",
+		English: "⚡ API requests left: %d/%d per minute",
+	},
+	KakaoStatusDevice: {
+		Korean:  "💻 기기: OpenClaw 플러그인 %s",
+		English: "💻 Device: OpenClaw plugin %s",
+	},
+	KakaoStatusPortalCode: {
+		Korean:  "🔑 포털 접속 코드: %s (%d분간 유효)",
+		English: "🔑 Portal code: %s (valid for %d min)",
+	},
+	KakaoStatusPortalWait: {
+		Korean:  "🔑 포털 접속 코드는 %d분 후 다시 받을 수 있습니다.",
+		English: "🔑 A portal code is available again in %d min.",
+	},
+	KakaoStatusPortalButton: {
+		Korean:  "포털 열기",
+		English: "Open portal",
+	},
+	KakaoStatusNotPaired: {
+		Korean:  "❌ 연결되지 않음\n\n/pair <코드>로 연결하세요.",
+		English: "❌ Not connected\n\nConnect with /pair <code>.",
+	},
+	KakaoCodeNotPaired: {
+		Korean: "포털 접속 코드는 연결된 대화에서만 발급할 수 있습니다.\n\n" +
+			"먼저 /pair <코드>로 연결하세요.",
+		English: "Portal access codes are only available in connected chats.\n\n" +
+			"Connect first with /pair <code>.",
+	},
+	KakaoCodeRateLimited: {
+		Korean: "⏱️ 코드 생성 한도 초과\n\n" +
+			"5분에 최대 3회까지 코드를 생성할 수 있습니다.\n\n" +
+			"%d분 후 다시 시도해주세요.",
+		English: "⏱️ Code limit reached\n\n" +
+			"You can create up to 3 codes every 5 minutes.\n\n" +
+			"Please try again in %d min.",
+	},
+	KakaoCodeFailed: {
+		Korean:  "코드 생성에 실패했습니다. 잠시 후 다시 시도해주세요.",
+		English: "Failed to create a code. Please try again later.",
+	},
+	KakaoCodeIssued: {
+		Korean: "🔑 포털 접속 코드\n\n" +
+			"코드: %s\n" +
+			"유효시간: %d분\n\n" +
+			"이 코드로 포털에서 대화 내역과 통계를 확인할 수 있습니다.",
+		English: "🔑 Portal access code\n\n" +
+			"Code: %s\n" +
+			"Valid for: %d min\n\n" +
+			"Use this code in the portal to view your messages and statistics.",
+	},
+	KakaoCodePortalURL: {
+		Korean:  "\n\n포털 주소:\n%s/portal/code",
+		English: "\n\nPortal:\n%s/portal/code",
+	},
+	KakaoRecentNotPaired: {
+		Korean:  "연결된 OpenClaw가 없습니다.\n\n먼저 /pair <코드>로 연결하세요.",
+		English: "This chat is not connected to OpenClaw.\n\nConnect first with /pair <code>.",
+	},
+	KakaoRecentRateLimited: {
+		Korean:  "⏱️ 최근 대화는 1분에 최대 3회까지 볼 수 있습니다.\n\n%d초 후 다시 시도해주세요.",
+		English: "⏱️ You can view recent messages up to 3 times a minute.\n\nPlease try again in %d seconds.",
+	},
+	KakaoRecentFailed: {
+		Korean:  "최근 대화를 불러오지 못했습니다. 잠시 후 다시 시도해주세요.",
+		English: "Failed to load recent messages. Please try again later.",
+	},
+	KakaoRecentEmpty: {
+		Korean:  "아직 주고받은 메시지가 없습니다.",
+		English: "No messages yet.",
+	},
+	KakaoRecentTitle: {
+		Korean:  "🕘 최근 대화",
+		English: "🕘 Recent messages",
+	},
+	KakaoRecentInbound: {
+		Korean:  "나 · %s",
+		English: "Me · %s",
+	},
+	KakaoRecentOutbound: {
+		Korean:  "OpenClaw · %s",
+		English: "OpenClaw · %s",
+	},
+	KakaoRecentNoText: {
+		Korean:  "(텍스트 없음)",
+		English: "(no text)",
+	},
+	KakaoRecentMore: {
+		Korean:  "포털에서 전체 보기",
+		English: "View all in portal",
+	},
+	KakaoUnsupportedMessage: {
+		Korean:  "이 메시지는 전달할 수 없습니다. 텍스트로 다시 보내 주세요.",
+		English: "This message can't be delivered. Please send it as text.",
+	},
+	KakaoDeliveryPaused: {
+		Korean:  "지금은 답장이 늦어질 수 있습니다. 메시지는 잘 받아 두었다가 전달해 드릴게요.",
+		English: "Replies may be delayed for now. Your message has been kept and will be passed on.",
+	},
+	KakaoAccountSuspended: {
+		Korean:  "연결된 OpenClaw 계정이 정지되어 메시지를 전달할 수 없습니다.",
+		English: "The linked OpenClaw account is suspended, so messages can't be delivered.",
+	},
+	KakaoDegradedRetrying: {
+		Korean:  "일시적인 문제로 메시지 전달이 늦어지고 있습니다. 잠시 후 자동으로 다시 전달합니다.",
+		English: "Your message is delayed by a temporary problem. It will be delivered automatically shortly.",
+	},
+	KakaoDegradedFailed: {
+		Korean:  "일시적인 문제로 요청을 처리하지 못했습니다. 잠시 후 다시 시도해 주세요.",
+		English: "Your request couldn't be processed due to a temporary problem. Please try again shortly.",
+	},
+	KakaoConsentPrompt: {
+		Korean: "메시지를 전달하기 전에 확인이 필요합니다.\n\n" +
+			"대화 내용을 서버에 저장해도 될까요? 동의하지 않으면 메시지는 전달되지만 " +
+			"내용은 저장되지 않고 시각 등 기록만 남습니다.\n\n" +
+			"나중에 /consent 로 변경할 수 있습니다.",
+		English: "Before your messages are delivered, please confirm:\n\n" +
+			"May the relay store the content of your messages? If you disagree, messages " +
+			"are still delivered but only metadata such as the time is kept.\n\n" +
+			"You can change this later with /consent.",
+	},
+	KakaoConsentAgree: {
+		Korean:  "동의",
+		English: "Agree",
+	},
+	KakaoConsentDisagree: {
+		Korean:  "동의 안 함",
+		English: "Disagree",
+	},
+	KakaoConsentGranted: {
+		Korean:  "메시지 내용 저장에 동의했습니다. 이제 메시지를 보내 주세요.",
+		English: "Thanks, message content will be stored. You can now send your message.",
+	},
+	KakaoConsentDenied: {
+		Korean:  "메시지 내용은 저장되지 않습니다. 이제 메시지를 보내 주세요.",
+		English: "Message content will not be stored. You can now send your message.",
+	},
+	KakaoConsentNotPaired: {
+		Korean:  "연결된 OpenClaw가 없습니다. 먼저 /pair <코드> 로 연결해주세요.",
+		English: "This chat is not connected to OpenClaw. Connect first with /pair <code>.",
+	},
+	KakaoConsentFailed: {
+		Korean:  "동의 상태를 저장하지 못했습니다. 잠시 후 다시 시도해주세요.",
+		English: "Could not save your answer. Please try again later.",
+	},
+
+	APIInternalError: {
+		Korean:  "서버 오류가 발생했습니다.",
+		English: "Internal server error",
+	},
+	APIInvalidBody: {
+		Korean:  "요청 형식이 올바르지 않습니다.",
+		English: "Invalid request body",
+	},
+	APIMissingRequired: {
+		Korean:  "%s 값이 필요합니다.",
+		English: "%s is required",
+	},
+	APIInvalidInput: {
+		Korean:  "%s 값이 올바르지 않습니다: %s",
+		English: "Invalid %s: %s",
+	},
+	APINotFound: {
+		Korean:  "요청한 항목을 찾을 수 없습니다.",
+		English: "Not found",
+	},
+	APILegalHold: {
+		Korean:  "법적 보존 중인 데이터라 지금은 삭제할 수 없습니다.",
+		English: "Data is under legal hold and cannot be deleted at this time",
+	},
+	APIAlreadyPaired: {
+		Korean:  "이미 연결된 대화입니다.",
+		English: "Conversation is already paired",
+	},
+	APIPairingExpired: {
+		Korean:  "페어링 코드가 만료되었습니다.",
+		English: "Pairing code has expired",
+	},
+	APINotAuthenticated: {
+		Korean:  "로그인이 필요합니다.",
+		English: "Not authenticated",
+	},
+	APIUnauthorized: {
+		Korean:  "인증되지 않은 요청입니다.",
+		English: "Unauthorized",
+	},
+	APISessionValidationFailed: {
+		Korean:  "세션을 확인하지 못했습니다.",
+		English: "Session validation failed",
+	},
+	APIReauthRequired: {
+		Korean:  "다시 인증해 주세요.",
+		English: "Re-authentication required",
+	},
+	APIAdminNotConfigured: {
+		Korean:  "관리자 설정이 되어 있지 않습니다.",
+		English: "Admin not configured",
+	},
+	APISecurityTokenFailed: {
+		Korean:  "보안 토큰을 생성하지 못했습니다.",
+		English: "Failed to generate security token",
+	},
+	APIMissingCSRFToken: {
+		Korean:  "CSRF 토큰이 없습니다.",
+		English: "Missing CSRF token",
+	},
+	APIInvalidCSRFToken: {
+		Korean:  "CSRF 토큰이 올바르지 않습니다.",
+		English: "Invalid CSRF token",
+	},
+	APITooManyLoginAttempts: {
+		Korean:  "로그인 시도가 너무 많습니다. 잠시 후 다시 시도해 주세요.",
+		English: "Too many login attempts. Please try again later.",
+	},
+	APITooManyRequests: {
+		Korean:  "요청이 너무 많습니다. 잠시 후 다시 시도해 주세요.",
+		English: "Too many requests. Please try again later.",
+	},
+	APIAccountNotFound: {
+		Korean:  "계정을 찾을 수 없습니다.",
+		English: "Account not found",
+	},
+	APIConnectionNotFound: {
+		Korean:  "연결을 찾을 수 없습니다.",
+		English: "Connection not found",
+	},
+	APIPairingCodeNotFound: {
+		Korean:  "페어링 코드를 찾을 수 없습니다.",
+		English: "Pairing code not found",
+	},
+	APIUserNotFound: {
+		Korean:  "사용자를 찾을 수 없습니다.",
+		English: "User not found",
+	},
+	APIPendingSessionNotFound: {
+		Korean:  "대기 중인 세션을 찾을 수 없습니다.",
+		English: "Pending session not found",
+	},
+	APIDeletionNotScheduled: {
+		Korean:  "예약된 계정 삭제가 없습니다.",
+		English: "No account deletion is scheduled",
+	},
+	APIConfirmDeletion: {
+		Korean:  "삭제하려면 {\"confirm\": \"DELETE\"}를 함께 보내 주세요.",
+		English: "Please confirm deletion by sending {\"confirm\": \"DELETE\"}",
+	},
+	APIInvalidCode: {
+		Korean:  "코드가 올바르지 않거나 만료되었습니다.",
+		English: "Invalid or expired code",
+	},
+	APINothingToUpdate: {
+		Korean:  "변경할 내용이 없습니다.",
+		English: "Nothing to update",
+	},
+	APIInvalidPassword: {
+		Korean:  "비밀번호가 올바르지 않습니다.",
+		English: "Invalid password",
+	},
+	APICurrentPasswordIncorrect: {
+		Korean:  "현재 비밀번호가 올바르지 않습니다.",
+		English: "Current password is incorrect",
+	},
+	APIPasswordChangeRequired: {
+		Korean:  "비밀번호를 변경해야 합니다.",
+		English: "Password change required",
+	},
+	APIInvalidSetupToken: {
+		Korean:  "설정 토큰이 올바르지 않습니다.",
+		English: "Invalid setup token",
+	},
+	APIAlreadyBootstrapped: {
+		Korean:  "이미 초기 설정이 완료된 배포입니다.",
+		English: "Deployment is already bootstrapped",
+	},
+	APILoginFailed: {
+		Korean:  "로그인에 실패했습니다.",
+		English: "Login failed",
+	},
+	APIBootstrapFailed: {
+		Korean:  "초기 설정에 실패했습니다.",
+		English: "Bootstrap failed",
+	},
+	APIPasswordChangeFailed: {
+		Korean:  "비밀번호를 변경하지 못했습니다.",
+		English: "Password change failed",
+	},
+	APIReauthFailed: {
+		Korean:  "다시 인증하지 못했습니다.",
+		English: "Re-authentication failed",
+	},
+	APICreateSessionFailed: {
+		Korean:  "세션을 만들지 못했습니다.",
+		English: "Failed to create session",
+	},
+	APILogoutFailed: {
+		Korean:  "로그아웃하지 못했습니다.",
+		English: "Failed to log out",
+	},
+	APIGetAccountFailed: {
+		Korean:  "계정 정보를 불러오지 못했습니다.",
+		English: "Failed to get account",
+	},
+	APIFetchStatsFailed: {
+		Korean:  "통계를 불러오지 못했습니다.",
+		English: "Failed to fetch stats",
+	},
+	APIFetchMessagesFailed: {
+		Korean:  "메시지를 불러오지 못했습니다.",
+		English: "Failed to fetch messages",
+	},
+	APIGeneratePairingCodeFailed: {
+		Korean:  "페어링 코드를 생성하지 못했습니다.",
+		English: "Failed to generate pairing code",
+	},
+	APIUpdateConnectionFailed: {
+		Korean:  "연결 정보를 저장하지 못했습니다.",
+		English: "Failed to update connection",
+	},
+	APIUnpairConnectionFailed: {
+		Korean:  "연결을 해제하지 못했습니다.",
+		English: "Failed to unpair connection",
+	},
+	APIUpdateConnectionStateFailed: {
+		Korean:  "연결 상태를 변경하지 못했습니다.",
+		English: "Failed to update connection state",
+	},
+	APIRegenerateTokenFailed: {
+		Korean:  "토큰을 재발급하지 못했습니다.",
+		English: "Failed to regenerate token",
+	},
+	APIExportConfigFailed: {
+		Korean:  "설정을 내보내지 못했습니다.",
+		English: "Failed to export configuration",
+	},
+	APIImportConfigFailed: {
+		Korean:  "설정을 가져오지 못했습니다.",
+		English: "Failed to import configuration",
+	},
+	APIDeleteAccountFailed: {
+		Korean:  "계정을 삭제하지 못했습니다.",
+		English: "Failed to delete account",
+	},
+	APICancelDeletionFailed: {
+		Korean:  "계정 삭제를 취소하지 못했습니다.",
+		English: "Failed to cancel account deletion",
+	},
+	APIUpdateTimezoneFailed: {
+		Korean:  "시간대를 변경하지 못했습니다.",
+		English: "Failed to update timezone",
+	},
+	APIUpdateProfileSharingFailed: {
+		Korean:  "프로필 공유 설정을 변경하지 못했습니다.",
+		English: "Failed to update profile sharing",
+	},
+	APIGetOnboardingFailed: {
+		Korean:  "온보딩 설정을 불러오지 못했습니다.",
+		English: "Failed to get onboarding settings",
+	},
+	APIUpdateOnboardingFailed: {
+		Korean:  "온보딩 설정을 저장하지 못했습니다.",
+		English: "Failed to update onboarding settings",
+	},
+	APIGetWebhookFailed: {
+		Korean:  "웹훅 설정을 불러오지 못했습니다.",
+		English: "Failed to get webhook settings",
+	},
+	APIUpdateWebhookFailed: {
+		Korean:  "웹훅 설정을 저장하지 못했습니다.",
+		English: "Failed to update webhook settings",
+	},
+	APIWebhookNotConfigured: {
+		Korean:  "웹훅이 설정되어 있지 않습니다.",
+		English: "Webhook is not configured",
+	},
+	APIGetSetupChecklistFailed: {
+		Korean:  "설정 체크리스트를 불러오지 못했습니다.",
+		English: "Failed to get setup checklist",
+	},
+	APIGetAccountSettingsFailed: {
+		Korean:  "계정 설정을 불러오지 못했습니다.",
+		English: "Failed to get account settings",
+	},
+	APIUpdateAccountSettingsFailed: {
+		Korean:  "계정 설정을 저장하지 못했습니다.",
+		English: "Failed to update account settings",
+	},
+	APIGetChangeHistoryFailed: {
+		Korean:  "변경 이력을 불러오지 못했습니다.",
+		English: "Failed to get change history",
+	},
+	APIGetNotificationsFailed: {
+		Korean:  "알림을 불러오지 못했습니다.",
+		English: "Failed to get notifications",
+	},
+	APIUpdateNotificationsFailed: {
+		Korean:  "알림을 읽음 처리하지 못했습니다.",
+		English: "Failed to mark notifications as read",
+	},
+
+	MailDeletionScheduledSubject: {
+		Korean:  "[OpenClaw Relay] 계정 삭제가 예약되었습니다",
+		English: "[OpenClaw Relay] Your account is scheduled for deletion",
+	},
+	MailDeletionScheduledBody: {
+		Korean: "계정 삭제 요청이 접수되었습니다.\n\n" +
+			"계정은 %s에 영구 삭제되며, 그때까지 사용이 중지됩니다.\n" +
+			"삭제를 원하지 않으시면 포털의 설정 페이지에서 삭제 요청을 취소할 수 있습니다.\n",
+		English: "We received your request to delete your account.\n\n" +
+			"The account is suspended and will be permanently deleted on %s.\n" +
+			"If you did not mean to delete it, cancel the request on the portal settings page.\n",
+	},
+}
@@ -0,0 +1,107 @@
+// Package i18n holds the catalog of user-facing messages (chat replies,
+// notification emails) and picks the locale to render them in.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Locale string
+
+const (
+	Korean  Locale = "ko"
+	English Locale = "en"
+)
+
+// DefaultLocale is used when neither the request nor the deployment picks a
+// supported locale.
+const DefaultLocale = Korean
+
+// Key identifies a message in the catalog.
+type Key string
+
+// ParseLocale maps a language tag such as "en-US" to a supported locale.
+func ParseLocale(tag string) (Locale, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	base, _, _ = strings.Cut(base, "_")
+	switch Locale(base) {
+	case Korean:
+		return Korean, true
+	case English:
+		return English, true
+	}
+	return "", false
+}
+
+// T renders the message for key in locale, falling back to DefaultLocale when
+// the locale has no translation. Args are applied with fmt.Sprintf.
+func T(locale Locale, key Key, args ...any) string {
+	translations, ok := catalog[key]
+	if !ok {
+		return string(key)
+	}
+	text, ok := translations[locale]
+	if !ok {
+		text = translations[DefaultLocale]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Negotiate picks the supported locale the client prefers most from an
+// Accept-Language header, or fallback when none is supported.
+func Negotiate(acceptLanguage string, fallback Locale) Locale {
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, ok := ParseLocale(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{locale: locale, q: q})
+	}
+	if len(candidates) == 0 {
+		return fallback
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
+
+type contextKey struct{}
+
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale negotiated for the request, or DefaultLocale.
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{"", Korean},
+		{"en-US,en;q=0.9", English},
+		{"ko-KR,ko;q=0.9,en;q=0.8", Korean},
+		{"fr-FR,en;q=0.5", English},
+		{"en;q=0.3,ko;q=0.7", Korean},
+		{"fr-FR,de;q=0.9", Korean},
+		{"en;q=0", Korean},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.header, Korean), tt.header)
+	}
+	assert.Equal(t, English, Negotiate("fr", English))
+}
+
+func TestParseLocale(t *testing.T) {
+	locale, ok := ParseLocale("EN_us")
+	assert.True(t, ok)
+	assert.Equal(t, English, locale)
+
+	_, ok = ParseLocale("ja")
+	assert.False(t, ok)
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "Pairing failed.", T(English, KakaoPairFailed))
+	assert.Equal(t, "페어링에 실패했습니다.", T(Korean, KakaoPairFailed))
+	assert.Equal(t, "페어링에 실패했습니다.", T(Locale("ja"), KakaoPairFailed))
+	assert.Contains(t, T(English, KakaoCodeRateLimited, 3), "3 min")
+	assert.Equal(t, "missing.key", T(English, Key("missing.key")))
+}
+
+func TestCatalogComplete(t *testing.T) {
+	for key, translations := range catalog {
+		for _, locale := range []Locale{Korean, English} {
+			text, ok := translations[locale]
+			assert.True(t, ok, "%s has no %s translation", key, locale)
+			assert.Equal(t, strings.Count(translations[DefaultLocale], "%"), strings.Count(text, "%"),
+				"%s %s translation has different format verbs", key, locale)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, DefaultLocale, FromContext(context.Background()))
+	assert.Equal(t, English, FromContext(WithLocale(context.Background(), English)))
+}
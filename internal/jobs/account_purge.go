@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+)
+
+// accountPurgeTimeout bounds a single purge run; cascading deletes of large
+// accounts can take a while but should not overlap the next tick.
+const accountPurgeTimeout = 5 * time.Minute
+
+// ScheduledAccountPurger deletes accounts whose scheduled deletion time has
+// passed.
+type ScheduledAccountPurger interface {
+	PurgeScheduledAccounts(ctx context.Context) (int, error)
+}
+
+// AccountPurgeJob performs the final deletion of accounts once the grace
+// period of a portal deletion request has ended.
+type AccountPurgeJob struct {
+	purger   ScheduledAccountPurger
+	interval time.Duration
+	failures *failureAlert
+	done     chan struct{}
+}
+
+func NewAccountPurgeJob(purger ScheduledAccountPurger, interval time.Duration, notifier alert.Notifier) *AccountPurgeJob {
+	return &AccountPurgeJob{
+		purger:   purger,
+		interval: interval,
+		failures: newFailureAlert("account purge", notifier),
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *AccountPurgeJob) Start() {
+	go j.run()
+	log.Info().Dur("interval", j.interval).Msg("account purge job started")
+}
+
+func (j *AccountPurgeJob) Stop() {
+	close(j.done)
+	log.Info().Msg("account purge job stopped")
+}
+
+func (j *AccountPurgeJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.purge()
+
+	for {
+		select {
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.purge()
+		}
+	}
+}
+
+func (j *AccountPurgeJob) purge() {
+	ctx, cancel := context.WithTimeout(context.Background(), min(j.interval, accountPurgeTimeout))
+	defer cancel()
+
+	count, err := j.purger.PurgeScheduledAccounts(ctx)
+	j.failures.record(ctx, err)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to purge scheduled accounts")
+	} else if count > 0 {
+		log.Info().Int("count", count).Msg("purged accounts scheduled for deletion")
+	}
+}
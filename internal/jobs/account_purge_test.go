@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+// scheduledAccountRepo holds the IDs of accounts whose deletion is due;
+// DeleteScheduled removes them.
+type scheduledAccountRepo struct {
+	repository.AccountRepository
+	due       []string
+	err       error
+	deadlines []time.Duration
+}
+
+func (m *scheduledAccountRepo) DeleteScheduled(ctx context.Context) ([]string, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		m.deadlines = append(m.deadlines, time.Until(deadline))
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	ids := m.due
+	m.due = nil
+	return ids, nil
+}
+
+func TestAccountPurgeJob(t *testing.T) {
+	newJob := func(repo *scheduledAccountRepo, interval time.Duration, notifier alert.Notifier) *AccountPurgeJob {
+		portal := service.NewPortalService(nil, nil, repo, nil, nil, "")
+		return NewAccountPurgeJob(portal, interval, notifier)
+	}
+
+	t.Run("a run is bounded by the purge timeout", func(t *testing.T) {
+		repo := &scheduledAccountRepo{due: []string{"acc-1", "acc-2"}}
+		job := newJob(repo, time.Hour, nil)
+
+		job.purge()
+		assert.Empty(t, repo.due)
+		require.Len(t, repo.deadlines, 1)
+		assert.LessOrEqual(t, repo.deadlines[0], accountPurgeTimeout)
+	})
+
+	t.Run("a run does not outlast a short interval", func(t *testing.T) {
+		repo := &scheduledAccountRepo{}
+		job := newJob(repo, time.Minute, nil)
+
+		job.purge()
+		require.Len(t, repo.deadlines, 1)
+		assert.LessOrEqual(t, repo.deadlines[0], time.Minute)
+	})
+
+	t.Run("alerts while purging fails", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		repo := &scheduledAccountRepo{err: errors.New("connection refused")}
+		job := newJob(repo, time.Hour, notifier)
+
+		job.purge()
+		job.purge()
+		require.Len(t, notifier.alerts, 1)
+		assert.Equal(t, alert.StatusFiring, notifier.alerts[0].Status)
+		assert.Equal(t, "account purge", notifier.alerts[0].Subject)
+
+		repo.err = nil
+		job.purge()
+		require.Len(t, notifier.alerts, 2)
+		assert.Equal(t, alert.StatusResolved, notifier.alerts[1].Status)
+	})
+}
@@ -2,13 +2,20 @@ package jobs
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/openclaw/relay-server-go/internal/alert"
 	"github.com/openclaw/relay-server-go/internal/repository"
 )
 
+// AlertInboundBacklog fires while the oldest queued inbound message is older
+// than the configured age, which usually means a plugin stopped fetching.
+const AlertInboundBacklog = "inbound_backlog"
+
 type CleanupJob struct {
 	adminSessionRepo     repository.AdminSessionRepository
 	portalSessionRepo    repository.PortalSessionRepository
@@ -16,8 +23,14 @@ type CleanupJob struct {
 	pairingCodeRepo      repository.PairingCodeRepository
 	inboundMsgRepo       repository.InboundMessageRepository
 	sessionRepo          repository.SessionRepository
+	notificationRepo     repository.AdminNotificationRepository
 	interval             time.Duration
-	done                 chan struct{}
+	notifier             alert.Notifier
+	failures             *failureAlert
+	// Alert when the oldest queued message is older than this (0 disables)
+	backlogAlertAge time.Duration
+	alerting        bool
+	done            chan struct{}
 }
 
 func NewCleanupJob(
@@ -27,8 +40,14 @@ func NewCleanupJob(
 	pairingCodeRepo repository.PairingCodeRepository,
 	inboundMsgRepo repository.InboundMessageRepository,
 	sessionRepo repository.SessionRepository,
+	notificationRepo repository.AdminNotificationRepository,
 	interval time.Duration,
+	notifier alert.Notifier,
+	backlogAlertAge time.Duration,
 ) *CleanupJob {
+	if notifier == nil {
+		notifier = alert.NewLogNotifier()
+	}
 	return &CleanupJob{
 		adminSessionRepo:     adminSessionRepo,
 		portalSessionRepo:    portalSessionRepo,
@@ -36,7 +55,11 @@ func NewCleanupJob(
 		pairingCodeRepo:      pairingCodeRepo,
 		inboundMsgRepo:       inboundMsgRepo,
 		sessionRepo:          sessionRepo,
+		notificationRepo:     notificationRepo,
 		interval:             interval,
+		notifier:             notifier,
+		failures:             newFailureAlert("cleanup", notifier),
+		backlogAlertAge:      backlogAlertAge,
 		done:                 make(chan struct{}),
 	}
 }
@@ -71,21 +94,80 @@ func (j *CleanupJob) cleanup() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	j.runCleanup(ctx, "admin sessions", j.adminSessionRepo.DeleteExpired)
-	j.runCleanup(ctx, "portal sessions", j.portalSessionRepo.DeleteExpired)
-	j.runCleanup(ctx, "portal access codes", j.portalAccessCodeRepo.DeleteExpired)
-	j.runCleanup(ctx, "pairing codes", j.pairingCodeRepo.DeleteExpired)
-	j.runCleanup(ctx, "inbound messages", j.inboundMsgRepo.MarkExpired)
+	start := time.Now()
+	errs := []error{
+		j.runCleanup(ctx, "admin sessions", j.adminSessionRepo.DeleteExpired),
+		j.runCleanup(ctx, "portal sessions", j.portalSessionRepo.DeleteExpired),
+		j.runCleanup(ctx, "portal access codes", j.portalAccessCodeRepo.DeleteExpired),
+		j.runCleanup(ctx, "pairing codes", j.pairingCodeRepo.DeleteExpired),
+		j.runCleanup(ctx, "inbound messages", j.inboundMsgRepo.MarkExpired),
+	}
 	if j.sessionRepo != nil {
-		j.runCleanup(ctx, "sessions", j.sessionRepo.DeleteExpired)
+		errs = append(errs, j.runCleanup(ctx, "sessions", j.sessionRepo.DeleteExpired))
+	}
+	if j.notificationRepo != nil {
+		errs = append(errs, j.runCleanup(ctx, "admin notifications", j.notificationRepo.DeleteExpired))
 	}
+	recordCleanupRun(start, time.Since(start))
+	j.failures.record(ctx, errors.Join(errs...))
+
+	j.checkBacklog(ctx)
 }
 
-func (j *CleanupJob) runCleanup(ctx context.Context, name string, fn func(context.Context) (int64, error)) {
+func (j *CleanupJob) runCleanup(ctx context.Context, name string, fn func(context.Context) (int64, error)) error {
 	count, err := fn(ctx)
+	recordCleanupTarget(name, count, err)
 	if err != nil {
 		log.Error().Err(err).Msgf("failed to cleanup %s", name)
-	} else if count > 0 {
+		return fmt.Errorf("cleanup %s: %w", name, err)
+	}
+	if count > 0 {
 		log.Info().Int64("count", count).Msgf("cleaned up %s", name)
 	}
+	return nil
+}
+
+// checkBacklog records the queued inbound messages left after expiring stale
+// ones, and notifies operators when the backlog starts or stops being older
+// than backlogAlertAge.
+func (j *CleanupJob) checkBacklog(ctx context.Context) {
+	backlog, err := j.inboundMsgRepo.QueuedBacklog(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to measure inbound backlog")
+		return
+	}
+
+	now := time.Now()
+	var age time.Duration
+	if backlog.OldestQueuedAt != nil {
+		age = now.Sub(*backlog.OldestQueuedAt)
+	}
+	stale := j.backlogAlertAge > 0 && age > j.backlogAlertAge
+
+	if stale != j.alerting {
+		j.alerting = stale
+		status, message := alert.StatusFiring, "inbound backlog is not being consumed"
+		if !stale {
+			status, message = alert.StatusResolved, "inbound backlog is being consumed again"
+		}
+		j.notifier.Notify(ctx, alert.Alert{
+			Name:    AlertInboundBacklog,
+			Kind:    alert.KindAnomaly,
+			Status:  status,
+			Message: message,
+			Details: map[string]any{
+				"queued":           backlog.Queued,
+				"oldestAgeSeconds": int64(age.Seconds()),
+				"thresholdSeconds": int64(j.backlogAlertAge.Seconds()),
+			},
+			At: now,
+		})
+	}
+
+	recordBacklog(BacklogStats{
+		Queued:           backlog.Queued,
+		OldestAgeSeconds: int64(age.Seconds()),
+		Alerting:         j.alerting,
+		CheckedAt:        now,
+	})
 }
@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// CleanupStats describes the cleanup job's runs and the inbound backlog it
+// last measured, since process start.
+type CleanupStats struct {
+	Runs           int64      `json:"runs"`
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
+	LastDurationMs int64      `json:"lastDurationMs"`
+	// Per cleanup target, e.g. "inbound messages"
+	Targets map[string]CleanupTargetStats `json:"targets"`
+	Backlog *BacklogStats                 `json:"backlog,omitempty"`
+}
+
+// CleanupTargetStats counts the rows one cleanup target removed or expired.
+type CleanupTargetStats struct {
+	LastRun  int64 `json:"lastRun"`
+	Total    int64 `json:"total"`
+	Failures int64 `json:"failures"`
+}
+
+// BacklogStats is the inbound backlog after the last cleanup run.
+type BacklogStats struct {
+	Queued           int       `json:"queued"`
+	OldestAgeSeconds int64     `json:"oldestAgeSeconds"`
+	Alerting         bool      `json:"alerting"`
+	CheckedAt        time.Time `json:"checkedAt"`
+}
+
+var cleanupStats struct {
+	mu    sync.Mutex
+	stats CleanupStats
+}
+
+func recordCleanupTarget(name string, count int64, err error) {
+	cleanupStats.mu.Lock()
+	defer cleanupStats.mu.Unlock()
+	if cleanupStats.stats.Targets == nil {
+		cleanupStats.stats.Targets = make(map[string]CleanupTargetStats)
+	}
+	target := cleanupStats.stats.Targets[name]
+	if err != nil {
+		target.Failures++
+		target.LastRun = 0
+	} else {
+		target.LastRun = count
+		target.Total += count
+	}
+	cleanupStats.stats.Targets[name] = target
+}
+
+func recordCleanupRun(start time.Time, duration time.Duration) {
+	cleanupStats.mu.Lock()
+	defer cleanupStats.mu.Unlock()
+	cleanupStats.stats.Runs++
+	cleanupStats.stats.LastRunAt = &start
+	cleanupStats.stats.LastDurationMs = duration.Milliseconds()
+}
+
+func recordBacklog(backlog BacklogStats) {
+	cleanupStats.mu.Lock()
+	defer cleanupStats.mu.Unlock()
+	cleanupStats.stats.Backlog = &backlog
+}
+
+// GetCleanupStats returns the process-wide cleanup job stats.
+func GetCleanupStats() CleanupStats {
+	cleanupStats.mu.Lock()
+	defer cleanupStats.mu.Unlock()
+	stats := cleanupStats.stats
+	stats.Targets = make(map[string]CleanupTargetStats, len(cleanupStats.stats.Targets))
+	for name, target := range cleanupStats.stats.Targets {
+		stats.Targets[name] = target
+	}
+	if stats.Backlog != nil {
+		backlog := *stats.Backlog
+		stats.Backlog = &backlog
+	}
+	return stats
+}
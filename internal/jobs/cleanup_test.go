@@ -7,7 +7,9 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/openclaw/relay-server-go/internal/alert"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 )
@@ -36,6 +38,22 @@ func (m *mockAdminSessionRepo) DeleteExpired(ctx context.Context) (int64, error)
 	return m.deleteExpiredCount, nil
 }
 
+func (m *mockAdminSessionRepo) DeleteAll(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockAdminSessionRepo) ListActive(ctx context.Context) ([]model.AdminSession, error) {
+	return nil, nil
+}
+
+func (m *mockAdminSessionRepo) Touch(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockAdminSessionRepo) MarkAuthenticated(ctx context.Context, id string) error {
+	return nil
+}
+
 type mockPortalSessionRepo struct {
 	deleteExpiredCount int64
 }
@@ -72,6 +90,14 @@ func (m *mockPairingCodeRepo) FindActiveByAccountID(ctx context.Context, account
 	return nil, nil
 }
 
+func (m *mockPairingCodeRepo) FindActive(ctx context.Context, limit, offset int) ([]model.PairingCode, error) {
+	return nil, nil
+}
+
+func (m *mockPairingCodeRepo) CountActive(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
 func (m *mockPairingCodeRepo) CountActiveByAccountID(ctx context.Context, accountID string) (int, error) {
 	return 0, nil
 }
@@ -84,12 +110,21 @@ func (m *mockPairingCodeRepo) MarkUsed(ctx context.Context, code string, usedBy
 	return nil
 }
 
+func (m *mockPairingCodeRepo) Revoke(ctx context.Context, accountID, code string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockPairingCodeRepo) RevokeByCode(ctx context.Context, code string) (bool, error) {
+	return false, nil
+}
+
 func (m *mockPairingCodeRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	return m.deleteExpiredCount, nil
 }
 
 type mockInboundMsgRepo struct {
 	markExpiredCount int64
+	backlog          model.InboundBacklog
 }
 
 func (m *mockInboundMsgRepo) FindByID(ctx context.Context, id string) (*model.InboundMessage, error) {
@@ -100,6 +135,10 @@ func (m *mockInboundMsgRepo) FindQueuedByAccountID(ctx context.Context, accountI
 	return nil, nil
 }
 
+func (m *mockInboundMsgRepo) FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error) {
+	return nil, nil
+}
+
 func (m *mockInboundMsgRepo) FindByAccountID(ctx context.Context, accountID string, limit, offset int) ([]model.InboundMessage, error) {
 	return nil, nil
 }
@@ -108,6 +147,10 @@ func (m *mockInboundMsgRepo) Create(ctx context.Context, params model.CreateInbo
 	return nil, nil
 }
 
+func (m *mockInboundMsgRepo) CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error) {
+	return nil, nil
+}
+
 func (m *mockInboundMsgRepo) MarkDelivered(ctx context.Context, id string) error {
 	return nil
 }
@@ -120,6 +163,23 @@ func (m *mockInboundMsgRepo) MarkExpired(ctx context.Context) (int64, error) {
 	return m.markExpiredCount, nil
 }
 
+func (m *mockInboundMsgRepo) ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error) {
+	return nil, nil
+}
+
+func (m *mockInboundMsgRepo) RecordWebhookFailure(ctx context.Context, id, errorMsg string, nextAt time.Time) error {
+	return nil
+}
+
+func (m *mockInboundMsgRepo) ReleaseWebhookLease(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockInboundMsgRepo) QueuedBacklog(ctx context.Context) (*model.InboundBacklog, error) {
+	backlog := m.backlog
+	return &backlog, nil
+}
+
 func (m *mockInboundMsgRepo) CountByStatus(ctx context.Context, status model.InboundMessageStatus) (int, error) {
 	return 0, nil
 }
@@ -136,6 +196,14 @@ func (m *mockInboundMsgRepo) CountByAccountIDSince(ctx context.Context, accountI
 	return 0, nil
 }
 
+func (m *mockInboundMsgRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.InboundMessage, error) {
+	return nil, nil
+}
+
+func (m *mockInboundMsgRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	return 0, nil
+}
+
 func (m *mockInboundMsgRepo) FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.InboundMessage, error) {
 	return nil, nil
 }
@@ -178,6 +246,8 @@ func (m *mockPortalAccessCodeRepo) DeleteExpired(ctx context.Context) (int64, er
 
 type mockSessionRepo struct {
 	deleteExpiredCount int64
+	expiredPending     []model.Session
+	expirePendingErr   error
 }
 
 func (m *mockSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
@@ -204,6 +274,16 @@ func (m *mockSessionRepo) MarkExpired(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockSessionRepo) ExpirePending(ctx context.Context) ([]model.Session, error) {
+	expired := m.expiredPending
+	m.expiredPending = nil
+	return expired, m.expirePendingErr
+}
+
+func (m *mockSessionRepo) RevokePending(ctx context.Context, id string) (*model.Session, error) {
+	return nil, nil
+}
+
 func (m *mockSessionRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	return m.deleteExpiredCount, nil
 }
@@ -212,6 +292,10 @@ func (m *mockSessionRepo) CountPendingByIP(ctx context.Context, ip string, since
 	return 0, nil
 }
 
+func (m *mockSessionRepo) CreateWithinIPLimit(ctx context.Context, params model.CreateSessionParams, limit int, since time.Time) (*model.Session, error) {
+	return nil, nil
+}
+
 func (m *mockSessionRepo) MarkDisconnected(ctx context.Context, id string) error {
 	return nil
 }
@@ -220,9 +304,21 @@ func (m *mockSessionRepo) WithTx(tx *sqlx.Tx) repository.SessionRepository {
 	return m
 }
 
+func (m *mockSessionRepo) UpdatePluginVersion(ctx context.Context, id string, version string) error {
+	return nil
+}
+
+func (m *mockSessionRepo) FindPairedByConversationKey(ctx context.Context, conversationKey string) (*model.Session, error) {
+	return nil, nil
+}
+
+func (m *mockSessionRepo) CountByPluginVersion(ctx context.Context) ([]model.PluginVersionCount, error) {
+	return nil, nil
+}
+
 func TestCleanupJob(t *testing.T) {
 	t.Run("creates job with correct interval", func(t *testing.T) {
-		job := NewCleanupJob(nil, nil, nil, nil, nil, nil, 5*time.Minute)
+		job := NewCleanupJob(nil, nil, nil, nil, nil, nil, nil, 5*time.Minute, nil, 0)
 
 		assert.NotNil(t, job)
 		assert.Equal(t, 5*time.Minute, job.interval)
@@ -236,7 +332,7 @@ func TestCleanupJob(t *testing.T) {
 		msgRepo := &mockInboundMsgRepo{}
 		sessionRepo := &mockSessionRepo{}
 
-		job := NewCleanupJob(adminRepo, portalRepo, portalAccessRepo, pairingRepo, msgRepo, sessionRepo, 100*time.Millisecond)
+		job := NewCleanupJob(adminRepo, portalRepo, portalAccessRepo, pairingRepo, msgRepo, sessionRepo, nil, 100*time.Millisecond, nil, 0)
 
 		job.Start()
 		time.Sleep(50 * time.Millisecond)
@@ -251,10 +347,74 @@ func TestCleanupJob(t *testing.T) {
 		msgRepo := &mockInboundMsgRepo{markExpiredCount: 5}
 		sessionRepo := &mockSessionRepo{deleteExpiredCount: 6}
 
-		job := NewCleanupJob(adminRepo, portalRepo, portalAccessRepo, pairingRepo, msgRepo, sessionRepo, 1*time.Hour)
+		job := NewCleanupJob(adminRepo, portalRepo, portalAccessRepo, pairingRepo, msgRepo, sessionRepo, nil, 1*time.Hour, nil, 0)
 
 		job.Start()
 		time.Sleep(10 * time.Millisecond)
 		job.Stop()
 	})
 }
+
+type recordingNotifier struct {
+	alerts []alert.Alert
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, a alert.Alert) {
+	n.alerts = append(n.alerts, a)
+}
+
+func TestCleanupJob_Stats(t *testing.T) {
+	msgRepo := &mockInboundMsgRepo{markExpiredCount: 5, backlog: model.InboundBacklog{Queued: 2}}
+	job := NewCleanupJob(
+		&mockAdminSessionRepo{}, &mockPortalSessionRepo{}, &mockPortalAccessCodeRepo{}, &mockPairingCodeRepo{},
+		msgRepo, nil, nil, time.Hour, nil, 0,
+	)
+	before := GetCleanupStats()
+
+	job.cleanup()
+
+	stats := GetCleanupStats()
+	assert.Equal(t, before.Runs+1, stats.Runs)
+	require.NotNil(t, stats.LastRunAt)
+	assert.Equal(t, int64(5), stats.Targets["inbound messages"].LastRun)
+	assert.Equal(t, before.Targets["inbound messages"].Total+5, stats.Targets["inbound messages"].Total)
+	require.NotNil(t, stats.Backlog)
+	assert.Equal(t, 2, stats.Backlog.Queued)
+	assert.False(t, stats.Backlog.Alerting)
+}
+
+func TestCleanupJob_BacklogAlert(t *testing.T) {
+	msgRepo := &mockInboundMsgRepo{}
+	notifier := &recordingNotifier{}
+	job := NewCleanupJob(nil, nil, nil, nil, msgRepo, nil, nil, time.Hour, notifier, 10*time.Minute)
+	ctx := context.Background()
+
+	oldest := time.Now().Add(-5 * time.Minute)
+	msgRepo.backlog = model.InboundBacklog{Queued: 3, OldestQueuedAt: &oldest}
+	job.checkBacklog(ctx)
+	assert.Empty(t, notifier.alerts, "backlog younger than the threshold")
+
+	oldest = time.Now().Add(-20 * time.Minute)
+	msgRepo.backlog = model.InboundBacklog{Queued: 7, OldestQueuedAt: &oldest}
+	job.checkBacklog(ctx)
+	job.checkBacklog(ctx)
+	require.Len(t, notifier.alerts, 1, "fires once while the backlog stays stale")
+	assert.Equal(t, AlertInboundBacklog, notifier.alerts[0].Name)
+	assert.Equal(t, alert.StatusFiring, notifier.alerts[0].Status)
+	assert.Equal(t, 7, notifier.alerts[0].Details["queued"])
+	assert.True(t, GetCleanupStats().Backlog.Alerting)
+
+	msgRepo.backlog = model.InboundBacklog{}
+	job.checkBacklog(ctx)
+	require.Len(t, notifier.alerts, 2)
+	assert.Equal(t, alert.StatusResolved, notifier.alerts[1].Status)
+	assert.False(t, GetCleanupStats().Backlog.Alerting)
+
+	t.Run("disabled", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		job := NewCleanupJob(nil, nil, nil, nil, msgRepo, nil, nil, time.Hour, notifier, 0)
+		msgRepo.backlog = model.InboundBacklog{Queued: 7, OldestQueuedAt: &oldest}
+		job.checkBacklog(ctx)
+		assert.Empty(t, notifier.alerts)
+	})
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+)
+
+// AlertJobFailing fires while a background job's runs fail, with the job as
+// subject, and resolves on its next successful run.
+const AlertJobFailing = "job_failing"
+
+// failureAlert notifies operators when a job starts or stops failing, rather
+// than on every failed run. It is only used from the job's own goroutine.
+type failureAlert struct {
+	job      string
+	notifier alert.Notifier
+	failing  bool
+	failures int
+}
+
+func newFailureAlert(job string, notifier alert.Notifier) *failureAlert {
+	if notifier == nil {
+		notifier = alert.NewLogNotifier()
+	}
+	return &failureAlert{job: job, notifier: notifier}
+}
+
+// record notes the result of a run; err is nil when it succeeded.
+func (f *failureAlert) record(ctx context.Context, err error) {
+	if err != nil {
+		f.failures++
+		if f.failing {
+			return
+		}
+		f.failing = true
+		f.notify(ctx, alert.StatusFiring, f.job+" job failed", map[string]any{"error": err.Error()})
+		return
+	}
+	if !f.failing {
+		return
+	}
+	failures := f.failures
+	f.failing, f.failures = false, 0
+	f.notify(ctx, alert.StatusResolved, f.job+" job succeeded again", map[string]any{"failedRuns": failures})
+}
+
+func (f *failureAlert) notify(ctx context.Context, status alert.Status, message string, details map[string]any) {
+	// A run that failed by timing out leaves ctx done; the notification
+	// should still go out
+	f.notifier.Notify(context.WithoutCancel(ctx), alert.Alert{
+		Name:    AlertJobFailing,
+		Kind:    alert.KindJobFailure,
+		Subject: f.job,
+		Status:  status,
+		Message: message,
+		Details: details,
+		At:      time.Now(),
+	})
+}
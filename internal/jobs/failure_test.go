@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+)
+
+func TestFailureAlert(t *testing.T) {
+	notifier := &recordingNotifier{}
+	failures := newFailureAlert("scheduled send", notifier)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	failures.record(ctx, nil)
+	assert.Empty(t, notifier.alerts, "no alert while the job succeeds")
+
+	failures.record(ctx, errors.New("connection refused"))
+	failures.record(ctx, errors.New("connection refused"))
+	require.Len(t, notifier.alerts, 1, "fires once while the job keeps failing")
+	fired := notifier.alerts[0]
+	assert.Equal(t, AlertJobFailing, fired.Name)
+	assert.Equal(t, alert.KindJobFailure, fired.Kind)
+	assert.Equal(t, "scheduled send", fired.Subject)
+	assert.Equal(t, alert.StatusFiring, fired.Status)
+	assert.Equal(t, "connection refused", fired.Details["error"])
+
+	failures.record(ctx, nil)
+	require.Len(t, notifier.alerts, 2)
+	assert.Equal(t, alert.StatusResolved, notifier.alerts[1].Status)
+	assert.Equal(t, 2, notifier.alerts[1].Details["failedRuns"])
+
+	failures.record(ctx, nil)
+	assert.Len(t, notifier.alerts, 2)
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+const (
+	// inboundWorkerWait is how long a worker blocks waiting for work, and so
+	// bounds how long Stop waits for it.
+	inboundWorkerWait = time.Second
+	// inboundWorkerBackoff slows a worker down after a queue error.
+	inboundWorkerBackoff = time.Second
+	// inboundRetryInterval is how often due retries are queued again.
+	inboundRetryInterval = 5 * time.Second
+	// inboundSweepInterval is how often conversations whose jobs no worker
+	// was told about are found. It is longer than ConversationLockTTL, so a
+	// dead worker's lock has expired by then.
+	inboundSweepInterval = 30 * time.Second
+)
+
+// InboundWorker records webhooks accepted on the fast path: it upserts the
+// conversation, stores the message and publishes it to the account. It also
+// queues failed messages again once their retry is due and picks up jobs
+// stranded by a worker that died mid-drain.
+type InboundWorker struct {
+	queue   service.InboundQueue
+	handle  service.InboundJobHandler
+	workers int
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewInboundWorker(queue service.InboundQueue, handle service.InboundJobHandler, workers int) *InboundWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &InboundWorker{
+		queue:   queue,
+		handle:  handle,
+		workers: workers,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+func (j *InboundWorker) Start() {
+	for i := 0; i < j.workers; i++ {
+		j.wg.Add(1)
+		go j.run()
+	}
+	j.wg.Add(1)
+	go j.maintain()
+	log.Info().Int("workers", j.workers).Msg("inbound worker started")
+}
+
+// Stop waits for in-progress jobs to finish.
+func (j *InboundWorker) Stop() {
+	j.cancel()
+	j.wg.Wait()
+	log.Info().Msg("inbound worker stopped")
+}
+
+func (j *InboundWorker) run() {
+	defer j.wg.Done()
+
+	for j.ctx.Err() == nil {
+		// Jobs already claimed are finished even while stopping
+		_, err := j.queue.ProcessNext(context.WithoutCancel(j.ctx), inboundWorkerWait, j.handle)
+		if err == nil {
+			continue
+		}
+		log.Error().Err(err).Msg("inbound queue error")
+		select {
+		case <-j.ctx.Done():
+		case <-time.After(inboundWorkerBackoff):
+		}
+	}
+}
+
+func (j *InboundWorker) maintain() {
+	defer j.wg.Done()
+
+	retries := time.NewTicker(inboundRetryInterval)
+	defer retries.Stop()
+	sweeps := time.NewTicker(inboundSweepInterval)
+	defer sweeps.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			return
+		case <-retries.C:
+			j.promoteRetries()
+		case <-sweeps.C:
+			j.sweepStranded()
+		}
+	}
+}
+
+func (j *InboundWorker) promoteRetries() {
+	count, err := j.queue.PromoteRetries(j.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to queue inbound retries")
+	} else if count > 0 {
+		log.Info().Int("count", count).Msg("queued inbound retries")
+	}
+}
+
+func (j *InboundWorker) sweepStranded() {
+	count, err := j.queue.SweepStranded(j.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to sweep inbound queues")
+	} else if count > 0 {
+		log.Warn().Int("count", count).Msg("queued stranded inbound conversations again")
+	}
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+type fakeInboundQueue struct {
+	service.InboundQueue
+	pending atomic.Int32
+	handled atomic.Int32
+}
+
+func (q *fakeInboundQueue) Enqueue(ctx context.Context, job *service.InboundJob) error {
+	q.pending.Add(1)
+	return nil
+}
+
+func (q *fakeInboundQueue) ProcessNext(ctx context.Context, wait time.Duration, handle service.InboundJobHandler) (bool, error) {
+	if q.pending.Add(-1) < 0 {
+		q.pending.Add(1)
+		time.Sleep(time.Millisecond)
+		return false, nil
+	}
+	return true, handle(ctx, &service.InboundJob{})
+}
+
+func (q *fakeInboundQueue) PromoteRetries(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestInboundWorker(t *testing.T) {
+	queue := &fakeInboundQueue{}
+	for i := 0; i < 5; i++ {
+		queue.Enqueue(context.Background(), &service.InboundJob{})
+	}
+
+	worker := NewInboundWorker(queue, func(ctx context.Context, job *service.InboundJob) error {
+		queue.handled.Add(1)
+		return nil
+	}, 2)
+	worker.Start()
+
+	assert.Eventually(t, func() bool { return queue.handled.Load() == 5 }, time.Second, 5*time.Millisecond)
+	worker.Stop()
+}
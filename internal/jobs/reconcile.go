@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// ReconcileJob completes pairings that were recorded on the session but never
+// reached the conversation mapping. Pairing now updates both in one
+// transaction; this catches rows left behind by older releases and manual edits.
+type ReconcileJob struct {
+	integrityRepo repository.IntegrityRepository
+	interval      time.Duration
+	failures      *failureAlert
+	done          chan struct{}
+}
+
+func NewReconcileJob(integrityRepo repository.IntegrityRepository, interval time.Duration, notifier alert.Notifier) *ReconcileJob {
+	return &ReconcileJob{
+		integrityRepo: integrityRepo,
+		interval:      interval,
+		failures:      newFailureAlert("reconcile", notifier),
+		done:          make(chan struct{}),
+	}
+}
+
+func (j *ReconcileJob) Start() {
+	go j.run()
+	log.Info().Dur("interval", j.interval).Msg("reconcile job started")
+}
+
+func (j *ReconcileJob) Stop() {
+	close(j.done)
+	log.Info().Msg("reconcile job stopped")
+}
+
+func (j *ReconcileJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.reconcile()
+
+	for {
+		select {
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.reconcile()
+		}
+	}
+}
+
+func (j *ReconcileJob) reconcile() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ids, err := j.integrityRepo.FindHalfPairedSessions(ctx)
+	if err != nil {
+		j.failures.record(ctx, err)
+		log.Error().Err(err).Msg("failed to find half-paired sessions")
+		return
+	}
+	if len(ids) == 0 {
+		j.failures.record(ctx, nil)
+		return
+	}
+
+	count, err := j.integrityRepo.CompleteHalfPairedConversations(ctx, ids)
+	j.failures.record(ctx, err)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to reconcile half-paired sessions")
+		return
+	}
+	log.Warn().Int("found", len(ids)).Int64("repaired", count).Msg("reconciled half-paired sessions")
+}
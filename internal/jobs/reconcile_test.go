@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIntegrityRepo struct {
+	halfPaired []string
+	completed  []string
+}
+
+func (m *mockIntegrityRepo) FindPairedSessionsWithoutAccount(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockIntegrityRepo) FindPairedConversationsWithoutAccount(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockIntegrityRepo) FindStaleQueuedMessages(ctx context.Context, before time.Time) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockIntegrityRepo) FindOrphanPortalUsers(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockIntegrityRepo) FindHalfPairedSessions(ctx context.Context) ([]string, error) {
+	return m.halfPaired, nil
+}
+
+func (m *mockIntegrityRepo) DisconnectSessionsWithoutAccount(ctx context.Context, ids []string) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockIntegrityRepo) UnpairConversationsWithoutAccount(ctx context.Context, keys []string) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockIntegrityRepo) ExpireStaleQueuedMessages(ctx context.Context, ids []string, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockIntegrityRepo) CompleteHalfPairedConversations(ctx context.Context, sessionIDs []string) (int64, error) {
+	m.completed = append(m.completed, sessionIDs...)
+	return int64(len(sessionIDs)), nil
+}
+
+func TestReconcileJob(t *testing.T) {
+	t.Run("completes half-paired sessions", func(t *testing.T) {
+		repo := &mockIntegrityRepo{halfPaired: []string{"s1", "s2"}}
+		job := NewReconcileJob(repo, time.Hour, nil)
+
+		job.reconcile()
+
+		assert.Equal(t, []string{"s1", "s2"}, repo.completed)
+	})
+
+	t.Run("skips repair when nothing is found", func(t *testing.T) {
+		repo := &mockIntegrityRepo{}
+		job := NewReconcileJob(repo, time.Hour, nil)
+
+		job.reconcile()
+
+		assert.Empty(t, repo.completed)
+	})
+}
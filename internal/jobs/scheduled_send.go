@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+)
+
+// ScheduledSender sends the scheduled replies that are due.
+type ScheduledSender interface {
+	SendDue(ctx context.Context) (int, error)
+}
+
+// ScheduledSendJob sends replies deferred with scheduledAt. It runs often
+// because the Kakao callbacks they use expire within a minute.
+type ScheduledSendJob struct {
+	sender   ScheduledSender
+	interval time.Duration
+	failures *failureAlert
+	done     chan struct{}
+}
+
+func NewScheduledSendJob(sender ScheduledSender, interval time.Duration, notifier alert.Notifier) *ScheduledSendJob {
+	return &ScheduledSendJob{
+		sender:   sender,
+		interval: interval,
+		failures: newFailureAlert("scheduled send", notifier),
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *ScheduledSendJob) Start() {
+	go j.run()
+	log.Info().Dur("interval", j.interval).Msg("scheduled send job started")
+}
+
+func (j *ScheduledSendJob) Stop() {
+	close(j.done)
+	log.Info().Msg("scheduled send job stopped")
+}
+
+func (j *ScheduledSendJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.send()
+		}
+	}
+}
+
+func (j *ScheduledSendJob) send() {
+	// Sends of one run may each take up to the callback timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	count, err := j.sender.SendDue(ctx)
+	j.failures.record(ctx, err)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to send scheduled replies")
+	} else if count > 0 {
+		log.Info().Int("count", count).Msg("sent scheduled replies")
+	}
+}
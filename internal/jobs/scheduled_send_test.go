@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// scheduledOutboundRepo hands out the due replies once and records how they
+// ended.
+type scheduledOutboundRepo struct {
+	repository.OutboundMessageRepository
+	due       []model.OutboundMessage
+	err       error
+	sent      []string
+	failed    map[string]string
+	deadlines []time.Duration
+}
+
+func (m *scheduledOutboundRepo) ClaimDueScheduled(ctx context.Context, now time.Time, limit int) ([]model.OutboundMessage, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		m.deadlines = append(m.deadlines, time.Until(deadline))
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	msgs := m.due
+	m.due = nil
+	return msgs, nil
+}
+
+func (m *scheduledOutboundRepo) MarkSent(ctx context.Context, id string) error {
+	m.sent = append(m.sent, id)
+	return nil
+}
+
+func (m *scheduledOutboundRepo) MarkFailed(ctx context.Context, id, errorMsg string) error {
+	m.failed[id] = errorMsg
+	return nil
+}
+
+func (m *scheduledOutboundRepo) RecordCallbackResponse(ctx context.Context, id string, resp model.CallbackResponse) error {
+	return nil
+}
+
+// callbackSender answers every callback except those in fail.
+type callbackSender struct {
+	fail map[string]bool
+}
+
+func (s *callbackSender) SendCallback(ctx context.Context, callbackURL string, payload any) (*model.CallbackResponse, error) {
+	if s.fail[callbackURL] {
+		return &model.CallbackResponse{Status: 400}, errors.New("callback failed with status 400")
+	}
+	return &model.CallbackResponse{Status: 200}, nil
+}
+
+func TestScheduledSendJob(t *testing.T) {
+	ok, expired := "https://callback.kakao.com/ok", "https://callback.kakao.com/expired"
+	newJob := func(repo *scheduledOutboundRepo, notifier alert.Notifier) (*ScheduledSendJob, *sse.Broker) {
+		broker := sse.NewMemoryBroker(sse.BrokerOptions{})
+		t.Cleanup(broker.Close)
+		sender := &callbackSender{fail: map[string]bool{expired: true}}
+		scheduler := service.NewOutboundScheduler(repo, sender, service.NewSessionEvents(broker))
+		return NewScheduledSendJob(scheduler, time.Second, notifier), broker
+	}
+
+	t.Run("sends the due replies and reports each outcome", func(t *testing.T) {
+		payload := json.RawMessage(`{"version":"2.0"}`)
+		repo := &scheduledOutboundRepo{
+			due: []model.OutboundMessage{
+				{ID: "out-1", AccountID: "acc-1", CallbackURL: &ok, ResponsePayload: payload},
+				{ID: "out-2", AccountID: "acc-1", CallbackURL: &expired, ResponsePayload: payload},
+			},
+			failed: map[string]string{},
+		}
+		job, broker := newJob(repo, nil)
+		receipts := broker.Subscribe("acc-1")
+
+		job.send()
+		assert.Equal(t, []string{"out-1"}, repo.sent)
+		assert.Equal(t, map[string]string{"out-2": "callback failed with status 400"}, repo.failed)
+		require.Len(t, repo.deadlines, 1)
+		assert.LessOrEqual(t, repo.deadlines[0], time.Minute)
+
+		require.Len(t, receipts.Events, 2)
+		for _, status := range []model.OutboundMessageStatus{model.OutboundStatusSent, model.OutboundStatusFailed} {
+			event := <-receipts.Events
+			assert.Equal(t, service.EventReplyDelivered, event.Type)
+			var data service.ReplyDeliveredEvent
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			assert.Equal(t, status, data.Status)
+		}
+
+		job.send()
+		assert.Len(t, repo.sent, 1, "a reply is only sent once")
+	})
+
+	t.Run("alerts while claiming fails", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		repo := &scheduledOutboundRepo{err: errors.New("connection refused"), failed: map[string]string{}}
+		job, _ := newJob(repo, notifier)
+
+		job.send()
+		job.send()
+		require.Len(t, notifier.alerts, 1)
+		assert.Equal(t, alert.StatusFiring, notifier.alerts[0].Status)
+		assert.Equal(t, "scheduled send", notifier.alerts[0].Subject)
+
+		repo.err = nil
+		job.send()
+		require.Len(t, notifier.alerts, 2)
+		assert.Equal(t, alert.StatusResolved, notifier.alerts[1].Status)
+	})
+}
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+)
+
+// PendingSessionExpirer expires pending sessions past their expiry time and
+// notifies the plugins waiting on them.
+type PendingSessionExpirer interface {
+	ExpirePendingSessions(ctx context.Context) (int64, error)
+}
+
+// SessionExpiryJob expires pending sessions shortly after expires_at so that a
+// plugin waiting for pairing is told immediately instead of on its next poll.
+type SessionExpiryJob struct {
+	expirer  PendingSessionExpirer
+	interval time.Duration
+	failures *failureAlert
+	done     chan struct{}
+}
+
+func NewSessionExpiryJob(expirer PendingSessionExpirer, interval time.Duration, notifier alert.Notifier) *SessionExpiryJob {
+	return &SessionExpiryJob{
+		expirer:  expirer,
+		interval: interval,
+		failures: newFailureAlert("session expiry", notifier),
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *SessionExpiryJob) Start() {
+	go j.run()
+	log.Info().Dur("interval", j.interval).Msg("session expiry job started")
+}
+
+func (j *SessionExpiryJob) Stop() {
+	close(j.done)
+	log.Info().Msg("session expiry job stopped")
+}
+
+func (j *SessionExpiryJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.expire()
+
+	for {
+		select {
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.expire()
+		}
+	}
+}
+
+func (j *SessionExpiryJob) expire() {
+	ctx, cancel := context.WithTimeout(context.Background(), j.interval)
+	defer cancel()
+
+	count, err := j.expirer.ExpirePendingSessions(ctx)
+	j.failures.record(ctx, err)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to expire pending sessions")
+	} else if count > 0 {
+		log.Info().Int64("count", count).Msg("expired pending sessions")
+	}
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+func TestSessionExpiryJob(t *testing.T) {
+	newJob := func(repo *mockSessionRepo, notifier alert.Notifier) (*SessionExpiryJob, *sse.Broker) {
+		broker := sse.NewMemoryBroker(sse.BrokerOptions{})
+		t.Cleanup(broker.Close)
+		sessions := service.NewSessionService(nil, repo, nil, nil, nil, broker, 0, "")
+		return NewSessionExpiryJob(sessions, time.Minute, notifier), broker
+	}
+
+	t.Run("notifies the plugin waiting on an expired session", func(t *testing.T) {
+		expiresAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		repo := &mockSessionRepo{expiredPending: []model.Session{{ID: "s1", Status: model.SessionStatusExpired, ExpiresAt: expiresAt}}}
+		job, broker := newJob(repo, nil)
+		waiting := broker.Subscribe("session:s1")
+		other := broker.Subscribe("session:s2")
+
+		job.expire()
+
+		require.Len(t, waiting.Events, 1)
+		event := <-waiting.Events
+		assert.Equal(t, service.EventPairingExpired, event.Type)
+		var data service.PairingExpiredEvent
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		assert.Equal(t, "s1", data.SessionID)
+		assert.Empty(t, other.Events)
+
+		job.expire()
+		assert.Empty(t, waiting.Events, "a session is only expired once")
+	})
+
+	t.Run("alerts while expiring fails", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		repo := &mockSessionRepo{expirePendingErr: errors.New("connection refused")}
+		job, _ := newJob(repo, notifier)
+
+		job.expire()
+		job.expire()
+		require.Len(t, notifier.alerts, 1)
+		assert.Equal(t, alert.StatusFiring, notifier.alerts[0].Status)
+		assert.Equal(t, "session expiry", notifier.alerts[0].Subject)
+
+		repo.expirePendingErr = nil
+		job.expire()
+		require.Len(t, notifier.alerts, 2)
+		assert.Equal(t, alert.StatusResolved, notifier.alerts[1].Status)
+	})
+}
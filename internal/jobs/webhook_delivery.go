@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+)
+
+// WebhookDeliverer posts the queued messages of webhook accounts that are due
+// and probes the webhooks that were degraded.
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context) (int, error)
+	ProbeDegraded(ctx context.Context) (int, error)
+}
+
+// WebhookDeliveryJob pushes inbound messages to the webhook URL of accounts
+// that configured one. It runs often so that messages arrive about as fast
+// as over the event stream.
+type WebhookDeliveryJob struct {
+	deliverer WebhookDeliverer
+	interval  time.Duration
+	failures  *failureAlert
+	done      chan struct{}
+}
+
+func NewWebhookDeliveryJob(deliverer WebhookDeliverer, interval time.Duration, notifier alert.Notifier) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		deliverer: deliverer,
+		interval:  interval,
+		failures:  newFailureAlert("webhook delivery", notifier),
+		done:      make(chan struct{}),
+	}
+}
+
+func (j *WebhookDeliveryJob) Start() {
+	go j.run()
+	log.Info().Dur("interval", j.interval).Msg("webhook delivery job started")
+}
+
+func (j *WebhookDeliveryJob) Stop() {
+	close(j.done)
+	log.Info().Msg("webhook delivery job stopped")
+}
+
+func (j *WebhookDeliveryJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.deliver()
+		}
+	}
+}
+
+func (j *WebhookDeliveryJob) deliver() {
+	// Posts of one run may each take up to the webhook timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	count, err := j.deliverer.DeliverDue(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to deliver webhook messages")
+	} else if count > 0 {
+		log.Info().Int("count", count).Msg("delivered webhook messages")
+	}
+
+	restored, probeErr := j.deliverer.ProbeDegraded(ctx)
+	if probeErr != nil {
+		log.Error().Err(probeErr).Msg("failed to probe degraded webhooks")
+	} else if restored > 0 {
+		log.Info().Int("count", restored).Msg("restored degraded webhooks")
+	}
+	j.failures.record(ctx, errors.Join(err, probeErr))
+}
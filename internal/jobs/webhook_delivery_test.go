@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/service"
+)
+
+// webhookInboundRepo hands out the due messages once and records failed
+// deliveries and released leases.
+type webhookInboundRepo struct {
+	repository.InboundMessageRepository
+	due       []model.InboundMessage
+	err       error
+	failures  map[string]string
+	released  []string
+	deadlines []time.Duration
+}
+
+func (m *webhookInboundRepo) ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		m.deadlines = append(m.deadlines, time.Until(deadline))
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	msgs := m.due
+	m.due = nil
+	return msgs, nil
+}
+
+func (m *webhookInboundRepo) RecordWebhookFailure(ctx context.Context, id, errorMsg string, nextAt time.Time) error {
+	m.failures[id] = errorMsg
+	return nil
+}
+
+func (m *webhookInboundRepo) ReleaseWebhookLease(ctx context.Context, id string) error {
+	m.released = append(m.released, id)
+	return nil
+}
+
+// webhookAccountRepo holds the webhook accounts; degraded ones are probed
+// once.
+type webhookAccountRepo struct {
+	repository.AccountRepository
+	accounts map[string]*model.Account
+	probed   bool
+}
+
+func (m *webhookAccountRepo) FindByID(ctx context.Context, id string) (*model.Account, error) {
+	return m.accounts[id], nil
+}
+
+func (m *webhookAccountRepo) RecordWebhookFailure(ctx context.Context, id string, threshold int, probeAt time.Time) (*model.Account, error) {
+	account := m.accounts[id]
+	account.WebhookFailures++
+	if account.WebhookDegradedAt != nil {
+		account.WebhookProbeAt = &probeAt
+	}
+	return account, nil
+}
+
+func (m *webhookAccountRepo) ClaimWebhookProbes(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.Account, error) {
+	if m.probed {
+		return nil, nil
+	}
+	m.probed = true
+	var due []model.Account
+	for _, account := range m.accounts {
+		if account.WebhookDegraded() {
+			due = append(due, *account)
+		}
+	}
+	return due, nil
+}
+
+func TestWebhookDeliveryJob(t *testing.T) {
+	// Private addresses are never connected to, so every post fails
+	unreachable := "https://127.0.0.1:1/hook"
+	secret := "whsec_test"
+	newJob := func(inboundRepo *webhookInboundRepo, accountRepo *webhookAccountRepo, notifier alert.Notifier) *WebhookDeliveryJob {
+		deliverer := service.NewWebhookDeliveryService(inboundRepo, accountRepo, nil, nil, "", 0)
+		return NewWebhookDeliveryJob(deliverer, time.Second, notifier)
+	}
+
+	t.Run("records failed deliveries of due messages", func(t *testing.T) {
+		inboundRepo := &webhookInboundRepo{
+			due: []model.InboundMessage{
+				{ID: "in-1", AccountID: "acc-1", ConversationKey: "ch:u1", KakaoPayload: json.RawMessage(`{}`)},
+				{ID: "in-2", AccountID: "acc-1", ConversationKey: "ch:u1", KakaoPayload: json.RawMessage(`{}`)},
+			},
+			failures: map[string]string{},
+		}
+		accountRepo := &webhookAccountRepo{accounts: map[string]*model.Account{
+			"acc-1": {ID: "acc-1", WebhookURL: &unreachable, WebhookSecret: &secret},
+		}}
+		job := newJob(inboundRepo, accountRepo, nil)
+
+		job.deliver()
+		require.Contains(t, inboundRepo.failures, "in-1")
+		assert.Contains(t, inboundRepo.failures["in-1"], "post webhook")
+		assert.NotContains(t, inboundRepo.failures, "in-2", "later messages of a failing account wait")
+		assert.Equal(t, []string{"in-2"}, inboundRepo.released)
+		assert.Equal(t, 1, accountRepo.accounts["acc-1"].WebhookFailures)
+		require.Len(t, inboundRepo.deadlines, 1)
+		assert.LessOrEqual(t, inboundRepo.deadlines[0], 5*time.Minute)
+
+		job.deliver()
+		assert.Len(t, inboundRepo.failures, 1, "a claimed message is only posted once per run")
+	})
+
+	t.Run("probes degraded webhooks", func(t *testing.T) {
+		degradedAt := time.Now().Add(-time.Minute)
+		inboundRepo := &webhookInboundRepo{failures: map[string]string{}}
+		accountRepo := &webhookAccountRepo{accounts: map[string]*model.Account{
+			"acc-1": {ID: "acc-1", WebhookURL: &unreachable, WebhookSecret: &secret, WebhookFailures: 5, WebhookDegradedAt: &degradedAt},
+		}}
+		job := newJob(inboundRepo, accountRepo, nil)
+
+		job.deliver()
+		account := accountRepo.accounts["acc-1"]
+		assert.Equal(t, 6, account.WebhookFailures)
+		require.NotNil(t, account.WebhookProbeAt, "a failed probe schedules the next one")
+		assert.True(t, account.WebhookProbeAt.After(time.Now()))
+		assert.True(t, account.WebhookDegraded())
+	})
+
+	t.Run("alerts while claiming fails", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		inboundRepo := &webhookInboundRepo{err: errors.New("connection refused"), failures: map[string]string{}}
+		job := newJob(inboundRepo, &webhookAccountRepo{probed: true}, notifier)
+
+		job.deliver()
+		job.deliver()
+		require.Len(t, notifier.alerts, 1)
+		assert.Equal(t, alert.StatusFiring, notifier.alerts[0].Status)
+		assert.Equal(t, "webhook delivery", notifier.alerts[0].Subject)
+
+		inboundRepo.err = nil
+		job.deliver()
+		require.Len(t, notifier.alerts, 2)
+		assert.Equal(t, alert.StatusResolved, notifier.alerts[1].Status)
+	})
+}
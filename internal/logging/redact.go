@@ -0,0 +1,178 @@
+// Package logging keeps secrets out of the server logs. zerolog hooks cannot
+// see the fields already added to an event, so secrets are masked by a
+// writer that rewrites each encoded event before it is written.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+// SensitiveFields are the field names, compared case-insensitively, whose
+// values are masked wherever they appear in an event, including nested
+// objects such as audit details. The admin setup token is deliberately not
+// listed: it is logged once so the operator can finish setup.
+var SensitiveFields = []string{
+	"code",
+	"pairingCode",
+	"portalCode",
+	"accessCode",
+	"token",
+	"relayToken",
+	"sessionToken",
+	"password",
+	"secret",
+	"authorization",
+	"cookie",
+}
+
+// URLFields are the field names, compared case-insensitively, whose values
+// are URLs or request paths. Their query strings, which may carry a relay
+// token, are masked. Secrets in the path itself cannot be told apart here;
+// log paths through middleware.RedactedPath instead.
+var URLFields = []string{
+	"path",
+	"url",
+}
+
+// Mask masks a secret the same way util.MaskCode masks codes, keeping the
+// first characters so log lines can still be correlated.
+func Mask(value string) string {
+	return util.MaskCode(value)
+}
+
+type redactWriter struct {
+	out       io.Writer
+	sensitive map[string]bool
+	urls      map[string]bool
+	// Quoted field names, to skip events without any of them cheaply
+	needles    [][]byte
+	urlNeedles [][]byte
+}
+
+// NewRedactWriter returns a writer for zerolog that masks the values of
+// SensitiveFields in every JSON event before passing it to out.
+func NewRedactWriter(out io.Writer) io.Writer {
+	w := &redactWriter{
+		out:       out,
+		sensitive: make(map[string]bool, len(SensitiveFields)),
+		urls:      make(map[string]bool, len(URLFields)),
+	}
+	for _, field := range SensitiveFields {
+		w.sensitive[strings.ToLower(field)] = true
+		w.needles = append(w.needles, []byte(strings.ToLower(`"`+field+`"`)))
+	}
+	for _, field := range URLFields {
+		w.urls[strings.ToLower(field)] = true
+		w.urlNeedles = append(w.urlNeedles, []byte(strings.ToLower(`"`+field+`"`)))
+	}
+	return w
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if !w.mayContainSecrets(p) {
+		return w.out.Write(p)
+	}
+
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(p, &event); err != nil {
+		// Not a JSON event; pass it through rather than lose it
+		return w.out.Write(p)
+	}
+	w.redactObject(event)
+
+	redacted, err := json.Marshal(event)
+	if err != nil {
+		return w.out.Write(p)
+	}
+	if _, err := w.out.Write(append(redacted, '\n')); err != nil {
+		return 0, err
+	}
+	// zerolog treats a short write as an error
+	return len(p), nil
+}
+
+func (w *redactWriter) mayContainSecrets(p []byte) bool {
+	lower := bytes.ToLower(p)
+	for _, needle := range w.needles {
+		if bytes.Contains(lower, needle) {
+			return true
+		}
+	}
+	if !bytes.Contains(p, []byte("?")) {
+		// Every request log has a path; only one with a query needs work
+		return false
+	}
+	for _, needle := range w.urlNeedles {
+		if bytes.Contains(lower, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *redactWriter) redactObject(object map[string]json.RawMessage) {
+	for key, value := range object {
+		if w.sensitive[strings.ToLower(key)] {
+			object[key] = maskValue(value)
+			continue
+		}
+		if w.urls[strings.ToLower(key)] {
+			object[key] = maskQuery(value)
+			continue
+		}
+		if nested, ok := w.redactNested(value); ok {
+			object[key] = nested
+		}
+	}
+}
+
+// redactNested redacts value if it is an object, reporting whether it was.
+func (w *redactWriter) redactNested(value json.RawMessage) (json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false
+	}
+	var nested map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &nested); err != nil {
+		return nil, false
+	}
+	w.redactObject(nested)
+	encoded, err := json.Marshal(nested)
+	if err != nil {
+		return nil, false
+	}
+	return encoded, true
+}
+
+func maskValue(value json.RawMessage) json.RawMessage {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		// Not a string: hide it entirely
+		return json.RawMessage(`"****"`)
+	}
+	if s == "" {
+		// null or empty, nothing to hide
+		return value
+	}
+	masked, _ := json.Marshal(Mask(s))
+	return masked
+}
+
+// maskQuery masks the query string of a URL value, keeping the path.
+func maskQuery(value json.RawMessage) json.RawMessage {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return value
+	}
+	path, _, found := strings.Cut(s, "?")
+	if !found {
+		return value
+	}
+	masked, _ := json.Marshal(path + "?****")
+	return masked
+}
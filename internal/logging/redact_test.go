@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactWriter(t *testing.T) {
+	var out bytes.Buffer
+	logger := zerolog.New(NewRedactWriter(&out))
+
+	decode := func() map[string]any {
+		t.Helper()
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &event))
+		out.Reset()
+		return event
+	}
+
+	t.Run("masks sensitive fields", func(t *testing.T) {
+		logger.Info().
+			Str("code", "ABCD-EFGH").
+			Str("relayToken", "rt_0123456789abcdef").
+			Str("Authorization", "Bearer secret-token").
+			Int("password", 1234).
+			Str("accountId", "acc-1").
+			Msg("pairing code created")
+
+		event := decode()
+		assert.Equal(t, "ABCD-****", event["code"])
+		assert.Equal(t, "rt_0-****", event["relayToken"])
+		assert.Equal(t, "Bear-****", event["Authorization"], "field names match case-insensitively")
+		assert.Equal(t, "****", event["password"])
+		assert.Equal(t, "acc-1", event["accountId"])
+		assert.Equal(t, "pairing code created", event["message"])
+	})
+
+	t.Run("masks nested fields", func(t *testing.T) {
+		logger.Info().Interface("details", map[string]any{"code": "WXYZ-2345", "expiresAt": 60}).Msg("audit")
+
+		event := decode()
+		assert.Equal(t, map[string]any{"code": "WXYZ-****", "expiresAt": float64(60)}, event["details"])
+	})
+
+	t.Run("masks query strings of URLs", func(t *testing.T) {
+		logger.Info().Str("path", "/v1/events?token=rt_0123456789abcdef").Str("url", "https://example.com/cb").Msg("request completed")
+
+		event := decode()
+		assert.Equal(t, "/v1/events?****", event["path"])
+		assert.Equal(t, "https://example.com/cb", event["url"])
+	})
+
+	t.Run("keeps masked values and other events as they are", func(t *testing.T) {
+		logger.Info().Str("code", "ABCD-****").Msg("invalid pairing code")
+		assert.Equal(t, "ABCD-****", decode()["code"])
+
+		logger.Info().Str("conversationKey", "_abc:user").Msg("message received")
+		assert.JSONEq(t, `{"level":"info","conversationKey":"_abc:user","message":"message received"}`, out.String())
+	})
+}
@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMessage(t *testing.T) {
+	raw := string(buildMessage("relay@example.com", Message{
+		To:      "user@example.com",
+		Subject: "계정 삭제 예정 안내",
+		Body:    "line 1\nline 2",
+	}))
+
+	headers, body, ok := strings.Cut(raw, "\r\n\r\n")
+	assert.True(t, ok)
+	assert.Contains(t, headers, "From: relay@example.com\r\n")
+	assert.Contains(t, headers, "To: user@example.com\r\n")
+	assert.Contains(t, headers, "Subject: =?UTF-8?b?")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=UTF-8")
+	assert.Equal(t, "line 1\r\nline 2", body)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/httputil"
+)
+
+// APIVersionMiddleware tags requests with the API version of their route
+// group. Requests to versions older than httputil.LatestAPIVersion get
+// Deprecation, Sunset (when configured) and successor-version Link headers.
+type APIVersionMiddleware struct {
+	version httputil.APIVersion
+	sunset  time.Time
+}
+
+func NewAPIVersionMiddleware(version httputil.APIVersion, sunset time.Time) *APIVersionMiddleware {
+	return &APIVersionMiddleware{
+		version: version,
+		sunset:  sunset,
+	}
+}
+
+func (m *APIVersionMiddleware) Handler(next http.Handler) http.Handler {
+	deprecated := m.version != httputil.LatestAPIVersion
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if deprecated {
+			w.Header().Set("Deprecation", "true")
+			if !m.sunset.IsZero() {
+				w.Header().Set("Sunset", m.sunset.UTC().Format(http.TimeFormat))
+			}
+			w.Header().Set("Link", "<"+successorPath(r.URL.Path, m.version)+`>; rel="successor-version"`)
+		}
+
+		ctx := httputil.WithAPIVersion(r.Context(), m.version)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// successorPath maps a path of an older version to the latest version:
+// /v1/events -> /v2/events, and unprefixed v1 routes such as /openclaw/reply
+// -> /v2/openclaw/reply.
+func successorPath(path string, version httputil.APIVersion) string {
+	latest := "/" + string(httputil.LatestAPIVersion)
+	prefix := "/" + string(version)
+	if path == prefix || strings.HasPrefix(path, prefix+"/") {
+		return latest + strings.TrimPrefix(path, prefix)
+	}
+	return latest + path
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+)
+
+func TestAPIVersionMiddleware(t *testing.T) {
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.RespondLegacyError(w, r, http.StatusNotFound, apperrors.NotFound("Session"))
+	})
+
+	t.Run("v1 is deprecated and keeps legacy errors", func(t *testing.T) {
+		sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+		m := NewAPIVersionMiddleware(httputil.APIVersionV1, sunset)
+
+		rec := httptest.NewRecorder()
+		m.Handler(notFound).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/sessions/abc/status", nil))
+
+		assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+		assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+		assert.Equal(t, `</v2/sessions/abc/status>; rel="successor-version"`, rec.Header().Get("Link"))
+		assert.JSONEq(t, `{"error": "Session not found"}`, rec.Body.String())
+	})
+
+	t.Run("unprefixed v1 routes link to v2", func(t *testing.T) {
+		m := NewAPIVersionMiddleware(httputil.APIVersionV1, time.Time{})
+
+		rec := httptest.NewRecorder()
+		m.Handler(notFound).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/openclaw/reply", nil))
+
+		assert.Empty(t, rec.Header().Get("Sunset"))
+		assert.Equal(t, `</v2/openclaw/reply>; rel="successor-version"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("v2 uses structured errors without deprecation", func(t *testing.T) {
+		m := NewAPIVersionMiddleware(httputil.APIVersionV2, time.Time{})
+
+		rec := httptest.NewRecorder()
+		m.Handler(notFound).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/sessions/abc/status", nil))
+
+		assert.Empty(t, rec.Header().Get("Deprecation"))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		var body httputil.ErrorEnvelope
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.NotNil(t, body.Error)
+		assert.Equal(t, apperrors.ErrCodeNotFound, body.Error.Code)
+		assert.Equal(t, "Session not found", body.Error.Message)
+	})
+
+	t.Run("v2 wraps success responses in data", func(t *testing.T) {
+		m := NewAPIVersionMiddleware(httputil.APIVersionV2, time.Time{})
+		ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httputil.Respond(w, r, http.StatusOK, map[string]string{"status": "paired"})
+		})
+
+		rec := httptest.NewRecorder()
+		m.Handler(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/sessions/abc/status", nil))
+
+		assert.JSONEq(t, `{"data": {"status": "paired"}}`, rec.Body.String())
+	})
+}
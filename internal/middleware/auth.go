@@ -7,6 +7,10 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/openclaw/relay-server-go/internal/audit"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/errreport"
+	"github.com/openclaw/relay-server-go/internal/httputil"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/util"
@@ -50,9 +54,8 @@ func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := extractToken(r)
 		if token == "" {
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Missing authentication token",
-			})
+			httputil.RespondLegacyError(w, r, http.StatusUnauthorized,
+				apperrors.Unauthorized("Missing authentication token"))
 			return
 		}
 
@@ -62,27 +65,35 @@ func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
 		session, err := m.sessionRepo.FindByTokenHash(ctx, tokenHash)
 		if err != nil {
 			log.Error().Err(err).Msg("auth middleware: session lookup error")
-			writeJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Authentication failed",
-			})
+			httputil.RespondLegacyError(w, r, http.StatusInternalServerError,
+				apperrors.Internal("Authentication failed"))
 			return
 		}
 
 		if session == nil {
 			log.Warn().Msg("auth middleware: invalid token attempt")
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Invalid token",
-			})
+			httputil.RespondLegacyError(w, r, http.StatusUnauthorized,
+				apperrors.InvalidToken("Invalid token"))
 			return
 		}
 
 		ctx = context.WithValue(ctx, SessionContextKey, session)
+		ctx = audit.WithActor(ctx, audit.PluginActor(session.ID))
+		errreport.SetTag(ctx, "session_id", session.ID)
 
 		// If session is paired, also add the linked account
 		if session.Status == model.SessionStatusPaired && session.AccountID != nil {
 			linkedAccount, err := m.accountRepo.FindByID(ctx, *session.AccountID)
+			// A disabled account, e.g. one pending deletion, is suspended
+			if err == nil && linkedAccount != nil && linkedAccount.DisabledAt != nil {
+				log.Warn().Str("accountId", linkedAccount.ID).Msg("auth middleware: disabled account")
+				httputil.RespondLegacyError(w, r, http.StatusForbidden,
+					apperrors.Forbidden("Account is disabled"))
+				return
+			}
 			if err == nil && linkedAccount != nil {
 				ctx = context.WithValue(ctx, AccountContextKey, linkedAccount)
+				errreport.SetTag(ctx, "account_id", linkedAccount.ID)
 			}
 		}
 
@@ -95,10 +106,15 @@ func extractToken(r *http.Request) string {
 		return token
 	}
 
+	return BearerToken(r)
+}
+
+// BearerToken returns the token of a Bearer Authorization header, or "" when
+// the request has none.
+func BearerToken(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	if strings.HasPrefix(authHeader, "Bearer ") {
 		return strings.TrimPrefix(authHeader, "Bearer ")
 	}
-
 	return ""
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -23,7 +24,8 @@ type mockAccountRepo struct {
 }
 
 type mockSessionRepo struct {
-	findByTokenHashFunc func(ctx context.Context, tokenHash string) (*model.Session, error)
+	findByTokenHashFunc     func(ctx context.Context, tokenHash string) (*model.Session, error)
+	updatePluginVersionFunc func(ctx context.Context, id string, version string) error
 }
 
 func (m *mockAccountRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.Account, error) {
@@ -67,6 +69,14 @@ func (m *mockSessionRepo) MarkExpired(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockSessionRepo) ExpirePending(ctx context.Context) ([]model.Session, error) {
+	return nil, nil
+}
+
+func (m *mockSessionRepo) RevokePending(ctx context.Context, id string) (*model.Session, error) {
+	return nil, nil
+}
+
 func (m *mockSessionRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	return 0, nil
 }
@@ -75,6 +85,10 @@ func (m *mockSessionRepo) CountPendingByIP(ctx context.Context, ip string, since
 	return 0, nil
 }
 
+func (m *mockSessionRepo) CreateWithinIPLimit(ctx context.Context, params model.CreateSessionParams, limit int, since time.Time) (*model.Session, error) {
+	return nil, nil
+}
+
 func (m *mockSessionRepo) MarkDisconnected(ctx context.Context, id string) error {
 	return nil
 }
@@ -83,10 +97,29 @@ func (m *mockSessionRepo) WithTx(tx *sqlx.Tx) repository.SessionRepository {
 	return m
 }
 
-func (m *mockAccountRepo) FindAll(ctx context.Context, limit, offset int) ([]model.Account, error) {
+func (m *mockSessionRepo) UpdatePluginVersion(ctx context.Context, id string, version string) error {
+	if m.updatePluginVersionFunc != nil {
+		return m.updatePluginVersionFunc(ctx, id, version)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) FindPairedByConversationKey(ctx context.Context, conversationKey string) (*model.Session, error) {
+	return nil, nil
+}
+
+func (m *mockSessionRepo) CountByPluginVersion(ctx context.Context) ([]model.PluginVersionCount, error) {
 	return nil, nil
 }
 
+func (m *mockAccountRepo) FindAll(ctx context.Context, filter model.AccountFilter, limit, offset int) ([]model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) CountFiltered(ctx context.Context, filter model.AccountFilter) (int, error) {
+	return 0, nil
+}
+
 func (m *mockAccountRepo) Create(ctx context.Context, params model.CreateAccountParams) (*model.Account, error) {
 	return nil, nil
 }
@@ -99,6 +132,34 @@ func (m *mockAccountRepo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockAccountRepo) DeletionPreview(ctx context.Context, id string) (*model.AccountDeletionPreview, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) ScheduleDeletion(ctx context.Context, id string, purgeAt time.Time) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) CancelDeletion(ctx context.Context, id string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) DeleteScheduled(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) SetLegalHold(ctx context.Context, id, reason string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) ReleaseLegalHold(ctx context.Context, id string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) IsUnderLegalHold(ctx context.Context, id string) (bool, error) {
+	return false, nil
+}
+
 func (m *mockAccountRepo) Count(ctx context.Context) (int, error) {
 	return 0, nil
 }
@@ -107,6 +168,42 @@ func (m *mockAccountRepo) UpdateToken(ctx context.Context, id, tokenHash string)
 	return nil, nil
 }
 
+func (m *mockAccountRepo) UpdateKakaoChannelID(ctx context.Context, id string, channelID *string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) UpdateTimezone(ctx context.Context, id, timezone string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) UpdateShareKakaoProfile(ctx context.Context, id string, share bool) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) UpdateOnboarding(ctx context.Context, id string, onboarding json.RawMessage) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) UpdateWebhook(ctx context.Context, id string, url, secret *string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) RotateWebhookSecret(ctx context.Context, id, secret string, previousExpiresAt time.Time) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) RecordWebhookFailure(ctx context.Context, id string, threshold int, probeAt time.Time) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) ResetWebhookFailures(ctx context.Context, id string) (*model.Account, error) {
+	return nil, nil
+}
+
+func (m *mockAccountRepo) ClaimWebhookProbes(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.Account, error) {
+	return nil, nil
+}
+
 func (m *mockAccountRepo) WithTx(tx *sqlx.Tx) repository.AccountRepository {
 	return m
 }
@@ -252,6 +349,33 @@ func TestAuthMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	})
 
+	t.Run("rejects session of disabled account", func(t *testing.T) {
+		disabledAt := time.Now()
+		accountRepo := &mockAccountRepo{
+			findByIDFunc: func(ctx context.Context, id string) (*model.Account, error) {
+				return &model.Account{ID: accountID, DisabledAt: &disabledAt}, nil
+			},
+		}
+		sessionRepo := &mockSessionRepo{
+			findByTokenHashFunc: func(ctx context.Context, tokenHash string) (*model.Session, error) {
+				return testSession, nil
+			},
+		}
+
+		middleware := NewAuthMiddleware(accountRepo, sessionRepo)
+		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler should not be called")
+		}))
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+validToken)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
 	t.Run("allows pending session without account", func(t *testing.T) {
 		pendingSession := &model.Session{
 			ID:     "sess-pending",
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler holds requests until release is closed and reports each
+// request entering it on entered.
+func blockingHandler(entered chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func serve(h http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/kakao-talkchannel/webhook", nil))
+	return rec
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	t.Run("passes requests under the limit", func(t *testing.T) {
+		m := NewConcurrencyLimitMiddleware(2, 0, time.Second)
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		assert.Equal(t, http.StatusOK, serve(handler).Code)
+		assert.Equal(t, http.StatusOK, serve(handler).Code)
+		assert.Equal(t, 0, m.Stats().InFlight)
+	})
+
+	t.Run("sheds when full and nothing may queue", func(t *testing.T) {
+		m := NewConcurrencyLimitMiddleware(1, 0, time.Second)
+		entered, release := make(chan struct{}, 1), make(chan struct{})
+		handler := m.Handler(blockingHandler(entered, release))
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(handler)
+		}()
+		<-entered
+
+		rec := serve(handler)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+		var body struct {
+			Code string `json:"code"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "SHED", body.Code)
+		assert.Equal(t, int64(1), m.Stats().Shed)
+
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("queued request runs when a slot frees up", func(t *testing.T) {
+		m := NewConcurrencyLimitMiddleware(1, 1, time.Second)
+		entered, release := make(chan struct{}, 2), make(chan struct{})
+		handler := m.Handler(blockingHandler(entered, release))
+
+		var wg sync.WaitGroup
+		codes := make([]int, 2)
+		for i := range codes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes[i] = serve(handler).Code
+			}()
+		}
+		<-entered
+		require.Eventually(t, func() bool { return m.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+		close(release)
+		wg.Wait()
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK}, codes)
+		assert.Equal(t, int64(0), m.Stats().Shed)
+	})
+
+	t.Run("sheds a queued request after the queue timeout", func(t *testing.T) {
+		m := NewConcurrencyLimitMiddleware(1, 1, 10*time.Millisecond)
+		entered, release := make(chan struct{}, 1), make(chan struct{})
+		handler := m.Handler(blockingHandler(entered, release))
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(handler)
+		}()
+		<-entered
+
+		assert.Equal(t, http.StatusServiceUnavailable, serve(handler).Code)
+		assert.Equal(t, int64(0), m.Stats().Queued)
+
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("disabled without a limit", func(t *testing.T) {
+		m := NewConcurrencyLimitMiddleware(0, 0, time.Second)
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+		assert.Equal(t, http.StatusOK, serve(m.Handler(next)).Code)
+	})
+}
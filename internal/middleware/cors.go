@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const corsPreflightMaxAge = 10 * time.Minute
+
+var (
+	corsAllowedMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}, ", ")
+	corsAllowedHeaders = strings.Join([]string{
+		"Authorization", "Content-Type", PluginVersionHeader,
+	}, ", ")
+	// Headers browser clients need to read: rate limits, deprecation notices
+	// and the request ID for support.
+	corsExposedHeaders = strings.Join([]string{
+		"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+		"Deprecation", "Sunset", "Link", "X-Request-Id",
+	}, ", ")
+)
+
+// CORSMiddleware lets browser-based OpenClaw dashboards call the plugin API
+// from the configured origins. Requests authenticate with a bearer token, so
+// credentials (cookies) are never allowed.
+//
+// It runs on the root router and only acts on the given path prefixes, since
+// preflight requests must be answered before routing, which would otherwise
+// reject OPTIONS with 405.
+type CORSMiddleware struct {
+	origins      map[string]bool
+	anyOrigin    bool
+	pathPrefixes []string
+}
+
+// NewCORSMiddleware allows the listed origins ("*" allows any). With no
+// origins, cross-origin requests get no CORS headers and browsers block them.
+func NewCORSMiddleware(origins []string, pathPrefixes []string) *CORSMiddleware {
+	m := &CORSMiddleware{
+		origins:      make(map[string]bool, len(origins)),
+		pathPrefixes: pathPrefixes,
+	}
+	for _, origin := range origins {
+		if origin == "*" {
+			m.anyOrigin = true
+			continue
+		}
+		m.origins[strings.TrimSuffix(origin, "/")] = true
+	}
+	return m
+}
+
+func (m *CORSMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !m.appliesTo(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if !m.allowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(corsPreflightMaxAge.Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *CORSMiddleware) allowed(origin string) bool {
+	return m.anyOrigin || m.origins[origin]
+}
+
+func (m *CORSMiddleware) appliesTo(path string) bool {
+	for _, prefix := range m.pathPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewCORSMiddleware([]string{"https://dash.example.com"}, []string{"/v1", "/openclaw"}).Handler(next)
+
+	serve := func(method, path, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("allowed origin gets CORS headers and exposed rate limit headers", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/openclaw/messages", "https://dash.example.com", false)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://dash.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "X-RateLimit-Remaining")
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("preflight is answered without reaching the handler", func(t *testing.T) {
+		rec := serve(http.MethodOptions, "/openclaw/reply", "https://dash.example.com", true)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), "POST")
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+		assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("disallowed origin gets no CORS headers", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/v1/events", "https://evil.example.com", false)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+
+		rec = serve(http.MethodOptions, "/v1/events", "https://evil.example.com", true)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("other paths are untouched", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/admin/api/stats", "https://dash.example.com", false)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("wildcard allows any origin", func(t *testing.T) {
+		h := NewCORSMiddleware([]string{"*"}, []string{"/v1"}).Handler(next)
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+		req.Header.Set("Origin", "https://any.example.com")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, "https://any.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
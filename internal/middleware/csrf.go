@@ -3,6 +3,9 @@ package middleware
 import (
 	"net/http"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 	"github.com/openclaw/relay-server-go/internal/util"
 )
 
@@ -33,9 +36,7 @@ func (m *CSRFMiddleware) Handler(next http.Handler) http.Handler {
 			// Generate new CSRF token
 			token, err := util.GenerateToken()
 			if err != nil {
-				writeJSON(w, http.StatusInternalServerError, map[string]string{
-					"error": "Failed to generate security token",
-				})
+				httputil.WriteLocalizedError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APISecurityTokenFailed)
 				return
 			}
 			m.setCSRFCookie(w, token)
@@ -51,16 +52,12 @@ func (m *CSRFMiddleware) Handler(next http.Handler) http.Handler {
 		// For state-changing methods, validate the token
 		headerToken := r.Header.Get(CSRFHeaderName)
 		if headerToken == "" {
-			writeJSON(w, http.StatusForbidden, map[string]string{
-				"error": "Missing CSRF token",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusForbidden, apperrors.ErrCodeForbidden, i18n.APIMissingCSRFToken)
 			return
 		}
 
 		if !util.ConstantTimeEqual(cookie.Value, headerToken) {
-			writeJSON(w, http.StatusForbidden, map[string]string{
-				"error": "Invalid CSRF token",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusForbidden, apperrors.ErrCodeForbidden, i18n.APIInvalidCSRFToken)
 			return
 		}
 
@@ -1,46 +1,63 @@
 package middleware
 
 import (
-	"fmt"
-	"net"
 	"net/http"
-	"time"
+	"net/netip"
 
-	"github.com/openclaw/relay-server-go/internal/service"
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 )
 
+// IPRateLimitMiddleware limits requests per client IP for unauthenticated
+// endpoints. Clients in the exempt CIDRs (e.g. trusted proxies or health
+// checkers) are not limited. A zero limit disables the middleware.
 type IPRateLimitMiddleware struct {
-	limiter *service.RateLimiter
-	limit   int
-	window  time.Duration
-	prefix  string
+	limiter ratelimit.Limiter
+	limit   config.RateLimit
+	scope   string
+	exempt  config.CIDRList
 }
 
-func NewIPRateLimitMiddleware(limiter *service.RateLimiter, limit int, window time.Duration, prefix string) *IPRateLimitMiddleware {
+func NewIPRateLimitMiddleware(
+	limiter ratelimit.Limiter,
+	limit config.RateLimit,
+	scope string,
+	exempt config.CIDRList,
+) *IPRateLimitMiddleware {
 	return &IPRateLimitMiddleware{
 		limiter: limiter,
 		limit:   limit,
-		window:  window,
-		prefix:  prefix,
+		scope:   "ip:" + scope,
+		exempt:  exempt,
 	}
 }
 
 func (m *IPRateLimitMiddleware) Handler(next http.Handler) http.Handler {
+	if !m.limit.Enabled() {
+		return next
+	}
+
+	bucket := ratelimit.Per(m.limit.Limit, m.limit.Window)
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := r.RemoteAddr
-		if host, _, err := net.SplitHostPort(ip); err == nil {
-			ip = host
+		ip := httputil.ClientIP(r)
+
+		if addr, err := netip.ParseAddr(ip); err == nil && m.exempt.Contains(addr) {
+			next.ServeHTTP(w, r)
+			return
 		}
 
-		key := fmt.Sprintf("ip:%s:%s", m.prefix, ip)
-		allowed, resetAt := m.limiter.CheckLimit(r.Context(), key, m.limit, m.window)
+		result := m.limiter.Take(r.Context(), m.scope, ip, bucket)
+		ratelimit.SetHeaders(w, result)
 
-		if !allowed {
-			secondsLeft := int(time.Until(resetAt).Seconds()) + 1
-			w.Header().Set("Retry-After", fmt.Sprintf("%d", secondsLeft))
-			writeJSON(w, http.StatusTooManyRequests, map[string]string{
-				"error": "Too many requests. Please try again later.",
-			})
+		if !result.Allowed {
+			log.Warn().Str("ip", ip).Str("scope", m.scope).Msg("ip rate limit exceeded")
+			httputil.WriteLocalizedError(w, r, http.StatusTooManyRequests, apperrors.ErrCodeRateLimitExceeded, i18n.APITooManyRequests)
 			return
 		}
 
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/openclaw/relay-server-go/internal/i18n"
+)
+
+// LocaleMiddleware stores the locale negotiated from Accept-Language in the
+// request context for user-facing messages (i18n.FromContext).
+type LocaleMiddleware struct {
+	defaultLocale i18n.Locale
+}
+
+func NewLocaleMiddleware(defaultLocale i18n.Locale) *LocaleMiddleware {
+	return &LocaleMiddleware{defaultLocale: defaultLocale}
+}
+
+func (m *LocaleMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.Negotiate(r.Header.Get("Accept-Language"), m.defaultLocale)
+		ctx := i18n.WithLocale(r.Context(), locale)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
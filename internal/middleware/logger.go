@@ -17,7 +17,8 @@ func RequestLogger(next http.Handler) http.Handler {
 		defer func() {
 			log.Info().
 				Str("method", r.Method).
-				Str("path", r.URL.Path).
+				Str("path", RedactedPath(r)).
+				Str("route", RoutePattern(r)).
 				Int("status", ww.Status()).
 				Int("bytes", ww.BytesWritten()).
 				Dur("latency", time.Since(start)).
@@ -2,91 +2,36 @@ package middleware
 
 import (
 	"net/http"
-	"sync"
 	"time"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 )
 
 const (
 	loginMaxAttempts    = 5
 	loginWindowDuration = time.Minute
-	loginCleanupPeriod  = 5 * time.Minute
 )
 
-type loginAttempt struct {
-	count       int
-	windowStart time.Time
-}
-
+// LoginRateLimiter limits login attempts per client IP: up to loginMaxAttempts
+// in a burst, refilled over loginWindowDuration.
 type LoginRateLimiter struct {
-	mu          sync.RWMutex
-	attempts    map[string]*loginAttempt
-	lastCleanup time.Time
+	limiter ratelimit.Limiter
 }
 
-func NewLoginRateLimiter() *LoginRateLimiter {
-	return &LoginRateLimiter{
-		attempts:    make(map[string]*loginAttempt),
-		lastCleanup: time.Now(),
-	}
-}
-
-func (l *LoginRateLimiter) cleanup() {
-	now := time.Now()
-	if now.Sub(l.lastCleanup) < loginCleanupPeriod {
-		return
-	}
-	l.lastCleanup = now
-
-	for ip, attempt := range l.attempts {
-		if now.Sub(attempt.windowStart) > loginWindowDuration {
-			delete(l.attempts, ip)
-		}
-	}
-}
-
-func (l *LoginRateLimiter) isAllowed(ip string) bool {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.cleanup()
-
-	now := time.Now()
-	attempt, exists := l.attempts[ip]
-
-	if !exists {
-		l.attempts[ip] = &loginAttempt{
-			count:       1,
-			windowStart: now,
-		}
-		return true
-	}
-
-	if now.Sub(attempt.windowStart) > loginWindowDuration {
-		attempt.count = 1
-		attempt.windowStart = now
-		return true
-	}
-
-	if attempt.count >= loginMaxAttempts {
-		return false
-	}
-
-	attempt.count++
-	return true
+func NewLoginRateLimiter(limiter ratelimit.Limiter) *LoginRateLimiter {
+	return &LoginRateLimiter{limiter: limiter}
 }
 
 func (l *LoginRateLimiter) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := r.RemoteAddr
-		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-			ip = forwarded
-		}
+		result := l.limiter.Take(r.Context(), "login", httputil.ClientIP(r), ratelimit.Per(loginMaxAttempts, loginWindowDuration))
+		ratelimit.SetHeaders(w, result)
 
-		if !l.isAllowed(ip) {
-			w.Header().Set("Retry-After", "60")
-			writeJSON(w, http.StatusTooManyRequests, map[string]string{
-				"error": "Too many login attempts. Please try again later.",
-			})
+		if !result.Allowed {
+			httputil.WriteLocalizedError(w, r, http.StatusTooManyRequests, apperrors.ErrCodeRateLimitExceeded, i18n.APITooManyLoginAttempts)
 			return
 		}
 
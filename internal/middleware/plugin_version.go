@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// PluginVersionHeader carries the OpenClaw plugin version on plugin requests.
+const PluginVersionHeader = "X-OpenClaw-Plugin-Version"
+
+const PluginCompatContextKey contextKey = "pluginCompat"
+
+// GetPluginCompat returns the compatibility result of the requesting plugin.
+func GetPluginCompat(ctx context.Context) *config.PluginCompatResult {
+	if result, ok := ctx.Value(PluginCompatContextKey).(*config.PluginCompatResult); ok {
+		return result
+	}
+	return nil
+}
+
+// PluginVersionMiddleware checks the plugin version header against the
+// compatibility matrix and rejects unsupported plugins with 426. When it runs
+// after AuthMiddleware, the reported version is recorded on the session.
+type PluginVersionMiddleware struct {
+	compat      *config.PluginCompatibility
+	sessionRepo repository.SessionRepository
+}
+
+func NewPluginVersionMiddleware(
+	compat *config.PluginCompatibility,
+	sessionRepo repository.SessionRepository,
+) *PluginVersionMiddleware {
+	return &PluginVersionMiddleware{
+		compat:      compat,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (m *PluginVersionMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := strings.TrimSpace(r.Header.Get(PluginVersionHeader))
+		result := m.compat.Check(version)
+
+		if result.Status == config.PluginCompatUnsupported {
+			log.Warn().
+				Str("pluginVersion", version).
+				Str("minimumVersion", result.MinimumVersion).
+				Msg("rejected unsupported plugin version")
+			httputil.RespondError(w, r, apperrors.UpgradeRequired(result.MinimumVersion))
+			return
+		}
+
+		ctx := r.Context()
+		if session := GetSession(ctx); session != nil && version != "" {
+			if session.PluginVersion == nil || *session.PluginVersion != version {
+				if err := m.sessionRepo.UpdatePluginVersion(ctx, session.ID, version); err != nil {
+					log.Warn().Err(err).Str("sessionId", session.ID).Msg("failed to record plugin version")
+				} else {
+					session.PluginVersion = &version
+				}
+			}
+		}
+
+		ctx = context.WithValue(ctx, PluginCompatContextKey, &result)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+func newTestPluginVersionMiddleware(t *testing.T, repo *mockSessionRepo) *PluginVersionMiddleware {
+	t.Helper()
+	cfg := &config.Config{PluginMinVersion: "1.0.0", PluginRecommendedVersion: "1.2.0"}
+	compat, err := cfg.PluginCompatibility()
+	require.NoError(t, err)
+	return NewPluginVersionMiddleware(compat, repo)
+}
+
+func TestPluginVersionMiddleware(t *testing.T) {
+	t.Run("rejects unsupported version with 426", func(t *testing.T) {
+		m := newTestPluginVersionMiddleware(t, &mockSessionRepo{})
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler should not be called")
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+		req.Header.Set(PluginVersionHeader, "0.9.0")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUpgradeRequired, rec.Code)
+		assert.Contains(t, rec.Body.String(), "UPGRADE_REQUIRED")
+	})
+
+	t.Run("passes outdated version and exposes result", func(t *testing.T) {
+		m := newTestPluginVersionMiddleware(t, &mockSessionRepo{})
+		var got *config.PluginCompatResult
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = GetPluginCompat(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+		req.Header.Set(PluginVersionHeader, "1.1.0")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.NotNil(t, got)
+		assert.Equal(t, config.PluginCompatOutdated, got.Status)
+		assert.Equal(t, "1.2.0", got.RecommendedVersion)
+	})
+
+	t.Run("records changed version on session", func(t *testing.T) {
+		var recordedID, recordedVersion string
+		repo := &mockSessionRepo{
+			updatePluginVersionFunc: func(ctx context.Context, id string, version string) error {
+				recordedID, recordedVersion = id, version
+				return nil
+			},
+		}
+		m := newTestPluginVersionMiddleware(t, repo)
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		session := &model.Session{ID: "session-1"}
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+		req.Header.Set(PluginVersionHeader, "1.3.0")
+		req = req.WithContext(context.WithValue(req.Context(), SessionContextKey, session))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "session-1", recordedID)
+		assert.Equal(t, "1.3.0", recordedVersion)
+	})
+
+	t.Run("skips update when version is unchanged", func(t *testing.T) {
+		repo := &mockSessionRepo{
+			updatePluginVersionFunc: func(ctx context.Context, id string, version string) error {
+				t.Fatal("version should not be updated")
+				return nil
+			},
+		}
+		m := newTestPluginVersionMiddleware(t, repo)
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		version := "1.3.0"
+		session := &model.Session{ID: "session-1", PluginVersion: &version}
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+		req.Header.Set(PluginVersionHeader, version)
+		req = req.WithContext(context.WithValue(req.Context(), SessionContextKey, session))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
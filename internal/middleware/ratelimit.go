@@ -1,122 +1,65 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
-	"github.com/openclaw/relay-server-go/internal/config"
+	"github.com/openclaw/relay-server-go/internal/alert"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 )
 
-const (
-	maxEntries      = 10000
-	cleanupInterval = time.Minute
-	entryTTL        = 5 * time.Minute
-	windowDuration  = time.Minute
-)
+// rateLimitWindow is the window of the per-account limit, see
+// ratelimit.AccountBucket.
+const rateLimitWindow = time.Minute
 
-type rateLimitEntry struct {
-	timestamps []time.Time
-	lastAccess time.Time
-}
+const RateLimitWarningContextKey contextKey = "rateLimitWarning"
 
-type RateLimiter struct {
-	mu          sync.RWMutex
-	store       map[string]*rateLimitEntry
-	lastCleanup time.Time
-}
+// AlertRateLimitExceeded fires when an account's requests are rejected for
+// exceeding its rate limit, with the account as subject.
+const AlertRateLimitExceeded = "rate_limit_exceeded"
 
-func NewRateLimiter() *RateLimiter {
-	return &RateLimiter{
-		store:       make(map[string]*rateLimitEntry),
-		lastCleanup: time.Now(),
+// GetRateLimitWarning returns the warning for a request that used most of the
+// account's rate limit, or nil.
+func GetRateLimitWarning(ctx context.Context) *ratelimit.Warning {
+	if warning, ok := ctx.Value(RateLimitWarningContextKey).(*ratelimit.Warning); ok {
+		return warning
 	}
+	return nil
 }
 
-func (rl *RateLimiter) cleanup() {
-	now := time.Now()
-	if now.Sub(rl.lastCleanup) < cleanupInterval {
-		return
-	}
-	rl.lastCleanup = now
-
-	for key, entry := range rl.store {
-		if now.Sub(entry.lastAccess) > entryTTL {
-			delete(rl.store, key)
-		}
-	}
-
-	if len(rl.store) > maxEntries {
-		oldest := make([]string, 0, len(rl.store)/5)
-		for key := range rl.store {
-			oldest = append(oldest, key)
-			if len(oldest) >= len(rl.store)/5 {
-				break
-			}
-		}
-		for _, key := range oldest {
-			delete(rl.store, key)
-		}
-	}
-}
-
-func (rl *RateLimiter) Check(accountID string, limit int) (allowed bool, remaining int, resetAt int64) {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	rl.cleanup()
-
-	now := time.Now()
-	windowStart := now.Add(-windowDuration)
-
-	entry, exists := rl.store[accountID]
-	if !exists {
-		entry = &rateLimitEntry{
-			timestamps: make([]time.Time, 0),
-			lastAccess: now,
-		}
-		rl.store[accountID] = entry
-	}
-
-	entry.lastAccess = now
-
-	filtered := entry.timestamps[:0]
-	for _, ts := range entry.timestamps {
-		if ts.After(windowStart) {
-			filtered = append(filtered, ts)
-		}
-	}
-	entry.timestamps = filtered
-
-	remaining = limit - len(entry.timestamps)
-	if remaining < 0 {
-		remaining = 0
-	}
-
-	if len(entry.timestamps) > 0 {
-		resetAt = entry.timestamps[0].Add(windowDuration).Unix()
-	} else {
-		resetAt = now.Add(windowDuration).Unix()
-	}
-
-	if len(entry.timestamps) >= limit {
-		return false, 0, resetAt
-	}
-
-	entry.timestamps = append(entry.timestamps, now)
-	return true, remaining - 1, resetAt
+// RateLimitNotifier is told when an account nears its rate limit, at most
+// once per window per account on each instance.
+type RateLimitNotifier interface {
+	RateLimitWarning(ctx context.Context, accountID string, warning ratelimit.Warning)
 }
 
+// RateLimitMiddleware applies a per-account token bucket: the per-minute limit
+// is the burst capacity and tokens refill smoothly over the minute.
 type RateLimitMiddleware struct {
-	limiter *RateLimiter
+	limiter  ratelimit.Limiter
+	notifier RateLimitNotifier
+	alerts   alert.Notifier
+
+	mu       sync.Mutex
+	notified map[string]time.Time
+	breached map[string]time.Time
 }
 
-func NewRateLimitMiddleware() *RateLimitMiddleware {
+// NewRateLimitMiddleware creates the middleware. notifier and alerts, told
+// when an account exceeds its limit, are optional.
+func NewRateLimitMiddleware(limiter ratelimit.Limiter, notifier RateLimitNotifier, alerts alert.Notifier) *RateLimitMiddleware {
 	return &RateLimitMiddleware{
-		limiter: NewRateLimiter(),
+		limiter:  limiter,
+		notifier: notifier,
+		alerts:   alerts,
+		notified: make(map[string]time.Time),
+		breached: make(map[string]time.Time),
 	}
 }
 
@@ -128,26 +71,69 @@ func (m *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
 			return
 		}
 
-		limit := account.RateLimitPerMin
-		if limit <= 0 {
-			limit = config.DefaultRateLimitPerMin
-		}
+		result := m.limiter.Take(r.Context(), ratelimit.AccountScope, account.ID, ratelimit.AccountBucket(account.RateLimitPerMin))
+		ratelimit.SetHeaders(w, result)
 
-		allowed, remaining, resetAt := m.limiter.Check(account.ID, limit)
-
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
-		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt, 10))
-
-		if !allowed {
+		if !result.Allowed {
 			log.Warn().Str("accountId", account.ID).Msg("rate limit exceeded")
-			w.Header().Set("Retry-After", "60")
-			writeJSON(w, http.StatusTooManyRequests, map[string]string{
-				"error": "Rate limit exceeded",
-			})
+			m.alertExceeded(r.Context(), account.ID, result.Limit)
+			httputil.RespondLegacyError(w, r, http.StatusTooManyRequests, apperrors.RateLimitExceeded())
 			return
 		}
 
+		if result.NearLimit() {
+			warning := result.Warning()
+			m.notify(r.Context(), account.ID, warning)
+			r = r.WithContext(context.WithValue(r.Context(), RateLimitWarningContextKey, &warning))
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+// notify sends the warning unless the account was already warned within the
+// current window, so a busy agent gets one event rather than one per request.
+func (m *RateLimitMiddleware) notify(ctx context.Context, accountID string, warning ratelimit.Warning) {
+	if m.notifier == nil || !m.firstInWindow(m.notified, accountID) {
+		return
+	}
+	m.notifier.RateLimitWarning(ctx, accountID, warning)
+}
+
+// alertExceeded raises the rate limit alert at most once per window per
+// account.
+func (m *RateLimitMiddleware) alertExceeded(ctx context.Context, accountID string, limit int) {
+	if m.alerts == nil || !m.firstInWindow(m.breached, accountID) {
+		return
+	}
+	m.alerts.Notify(context.WithoutCancel(ctx), alert.Alert{
+		Name:    AlertRateLimitExceeded,
+		Kind:    alert.KindQuotaBreach,
+		Subject: accountID,
+		Status:  alert.StatusFiring,
+		Message: "account exceeded its rate limit",
+		Details: map[string]any{
+			"accountId": accountID,
+			"limit":     limit,
+		},
+		At: time.Now(),
+	})
+}
+
+// firstInWindow reports whether accountID is not in seen within the current
+// window, and records it. Entries of past windows are dropped.
+func (m *RateLimitMiddleware) firstInWindow(seen map[string]time.Time, accountID string) bool {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if last, ok := seen[accountID]; ok && now.Sub(last) < rateLimitWindow {
+		return false
+	}
+	seen[accountID] = now
+	for id, last := range seen {
+		if now.Sub(last) >= rateLimitWindow {
+			delete(seen, id)
+		}
+	}
+	return true
+}
@@ -4,58 +4,23 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/config"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 )
 
-func TestRateLimiter(t *testing.T) {
-	t.Run("allows requests under limit", func(t *testing.T) {
-		limiter := NewRateLimiter()
-
-		for i := 0; i < 5; i++ {
-			allowed, remaining, _ := limiter.Check("account-1", 10)
-			assert.True(t, allowed)
-			assert.Equal(t, 10-i-1, remaining)
-		}
-	})
-
-	t.Run("blocks requests over limit", func(t *testing.T) {
-		limiter := NewRateLimiter()
-
-		for i := 0; i < 5; i++ {
-			limiter.Check("account-2", 5)
-		}
-
-		allowed, remaining, _ := limiter.Check("account-2", 5)
-		assert.False(t, allowed)
-		assert.Equal(t, 0, remaining)
-	})
-
-	t.Run("tracks accounts separately", func(t *testing.T) {
-		limiter := NewRateLimiter()
-
-		for i := 0; i < 5; i++ {
-			limiter.Check("account-a", 5)
-		}
-
-		allowed, _, _ := limiter.Check("account-b", 5)
-		assert.True(t, allowed)
-	})
-
-	t.Run("returns reset time", func(t *testing.T) {
-		limiter := NewRateLimiter()
-
-		_, _, resetAt := limiter.Check("account-3", 10)
-		assert.Greater(t, resetAt, int64(0))
-	})
-}
-
 func TestRateLimitMiddleware(t *testing.T) {
 	t.Run("allows request without account", func(t *testing.T) {
-		middleware := NewRateLimitMiddleware()
+		middleware := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(), nil, nil)
 		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		}))
@@ -69,7 +34,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 	})
 
 	t.Run("sets rate limit headers", func(t *testing.T) {
-		middleware := NewRateLimitMiddleware()
+		middleware := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(), nil, nil)
 		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		}))
@@ -89,7 +54,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 	})
 
 	t.Run("returns 429 when rate limited", func(t *testing.T) {
-		middleware := NewRateLimitMiddleware()
+		middleware := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(), nil, nil)
 		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		}))
@@ -108,11 +73,13 @@ func TestRateLimitMiddleware(t *testing.T) {
 		handler.ServeHTTP(rec, req)
 
 		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
-		assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+		// Limit of 2 per minute refills one token every 30 seconds
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
 	})
 
 	t.Run("uses default limit when account limit is zero", func(t *testing.T) {
-		middleware := NewRateLimitMiddleware()
+		middleware := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(), nil, nil)
 		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		}))
@@ -128,4 +95,144 @@ func TestRateLimitMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, "60", rec.Header().Get("X-RateLimit-Limit"))
 	})
+
+	t.Run("warns once near the limit", func(t *testing.T) {
+		notifier := &recordingRateLimitNotifier{}
+		middleware := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(), notifier, nil)
+
+		var warnings []*ratelimit.Warning
+		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			warnings = append(warnings, GetRateLimitWarning(r.Context()))
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		account := &model.Account{ID: "acc-4", RateLimitPerMin: 5}
+		ctx := context.WithValue(context.Background(), AccountContextKey, account)
+
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest("GET", "/test", nil).WithContext(ctx)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		require.Len(t, warnings, 5)
+		assert.Nil(t, warnings[2], "3 of 5 used is below the threshold")
+		require.NotNil(t, warnings[3])
+		assert.Equal(t, ratelimit.WarningCode, warnings[3].Code)
+		assert.Equal(t, 1, warnings[3].Remaining)
+		require.NotNil(t, warnings[4])
+		assert.Equal(t, []string{"acc-4"}, notifier.accounts)
+	})
+
+	t.Run("alerts once when the limit is exceeded", func(t *testing.T) {
+		alerts := &recordingAlertNotifier{}
+		middleware := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(), nil, alerts)
+		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		account := &model.Account{ID: "acc-5", RateLimitPerMin: 1}
+		ctx := context.WithValue(context.Background(), AccountContextKey, account)
+
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("GET", "/test", nil).WithContext(ctx)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		require.Len(t, alerts.alerts, 1)
+		assert.Equal(t, AlertRateLimitExceeded, alerts.alerts[0].Name)
+		assert.Equal(t, alert.KindQuotaBreach, alerts.alerts[0].Kind)
+		assert.Equal(t, "acc-5", alerts.alerts[0].Subject)
+		assert.Equal(t, 1, alerts.alerts[0].Details["limit"])
+	})
+}
+
+type recordingAlertNotifier struct {
+	alerts []alert.Alert
+}
+
+func (n *recordingAlertNotifier) Notify(ctx context.Context, a alert.Alert) {
+	n.alerts = append(n.alerts, a)
+}
+
+type recordingRateLimitNotifier struct {
+	accounts []string
+}
+
+func (n *recordingRateLimitNotifier) RateLimitWarning(ctx context.Context, accountID string, warning ratelimit.Warning) {
+	n.accounts = append(n.accounts, accountID)
+}
+
+func TestLoginRateLimiter(t *testing.T) {
+	limiter := NewLoginRateLimiter(ratelimit.NewMemoryLimiter())
+	handler := limiter.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < loginMaxAttempts; i++ {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/login", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	req = httptest.NewRequest("POST", "/login", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIPRateLimitMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	limit := config.RateLimit{Limit: 1, Window: time.Minute}
+	exempt := config.CIDRList{netip.MustParsePrefix("10.1.0.0/16")}
+
+	m := NewIPRateLimitMiddleware(ratelimit.NewMemoryLimiter(), limit, "test", exempt)
+	handler := m.Handler(ok)
+
+	req := httptest.NewRequest("POST", "/create", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+
+	req = httptest.NewRequest("POST", "/create", nil)
+	req.RemoteAddr = "10.0.0.1:5678"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"`+i18n.T(i18n.DefaultLocale, i18n.APITooManyRequests)+`","code":"RATE_LIMIT_EXCEEDED"}`, rec.Body.String())
+
+	t.Run("exempt CIDRs are not limited", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("POST", "/create", nil)
+			req.RemoteAddr = "10.1.2.3:1234"
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+		}
+	})
+
+	t.Run("zero limit disables the middleware", func(t *testing.T) {
+		disabled := NewIPRateLimitMiddleware(ratelimit.NewMemoryLimiter(), config.RateLimit{}, "test", nil)
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("POST", "/create", nil)
+			rec := httptest.NewRecorder()
+			disabled.Handler(ok).ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
 }
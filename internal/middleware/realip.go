@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+)
+
+// RealIPMiddleware sets r.RemoteAddr to the client IP. Forwarded headers are
+// only honored when the direct peer is a trusted proxy, and X-Forwarded-For is
+// walked from the right so that a client cannot spoof its address by
+// prepending entries: the first hop that is not a trusted proxy is the client.
+type RealIPMiddleware struct {
+	trusted config.CIDRList
+}
+
+func NewRealIPMiddleware(trusted config.CIDRList) *RealIPMiddleware {
+	return &RealIPMiddleware{trusted: trusted}
+}
+
+func (m *RealIPMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := m.clientIP(r); ip != "" {
+			r.RemoteAddr = ip
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *RealIPMiddleware) clientIP(r *http.Request) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	peerAddr, err := netip.ParseAddr(peer)
+	if err != nil || !m.trusted.Contains(peerAddr) {
+		return peer
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// A malformed hop can't be trusted to have been added by a proxy.
+				break
+			}
+			client = addr.Unmap().String()
+			if !m.trusted.Contains(addr) {
+				break
+			}
+		}
+		return client
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if addr, err := netip.ParseAddr(realIP); err == nil {
+			return addr.Unmap().String()
+		}
+	}
+
+	return peer
+}
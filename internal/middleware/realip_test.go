@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	var trusted config.CIDRList
+	assert.NoError(t, trusted.UnmarshalText([]byte("10.0.0.0/8,172.16.0.0/12")))
+	m := NewRealIPMiddleware(trusted)
+
+	resolve := func(remoteAddr string, headers map[string]string) string {
+		var got string
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.RemoteAddr
+		}))
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = remoteAddr
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{
+			name:       "ignores headers from untrusted peer",
+			remoteAddr: "203.0.113.5:4321",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4"},
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "uses forwarded client from trusted proxy",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7"},
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "skips trusted hops from the right",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7, 172.16.0.9, 10.0.0.3"},
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "ignores spoofed entries left of the first untrusted hop",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.7"},
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "stops at malformed hop",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7, garbage"},
+			expected:   "10.0.0.2",
+		},
+		{
+			name:       "falls back to X-Real-IP from trusted proxy",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.8"},
+			expected:   "198.51.100.8",
+		},
+		{
+			name:       "uses peer when trusted proxy sends no headers",
+			remoteAddr: "10.0.0.2:4321",
+			expected:   "10.0.0.2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, resolve(tc.remoteAddr, tc.headers))
+		})
+	}
+}
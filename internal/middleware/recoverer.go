@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/errreport"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+)
+
+// RecovererMiddleware recovers from panics, reports them with their stack
+// trace and request tags, and responds with a structured INTERNAL_ERROR.
+// It must run after chimiddleware.RequestID so the request ID is tagged.
+type RecovererMiddleware struct {
+	reporter errreport.Reporter
+}
+
+func NewRecovererMiddleware(reporter errreport.Reporter) *RecovererMiddleware {
+	return &RecovererMiddleware{reporter: reporter}
+}
+
+func (m *RecovererMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := chimiddleware.GetReqID(r.Context())
+
+		scope := errreport.NewScope()
+		scope.SetTag("request_id", requestID)
+		ctx := errreport.WithScope(r.Context(), scope)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Deliberate abort of the response; let net/http handle it.
+				panic(rec)
+			}
+
+			err, ok := rec.(error)
+			if !ok {
+				err = fmt.Errorf("%v", rec)
+			}
+
+			m.reporter.Capture(ctx, errreport.Event{
+				Err:       fmt.Errorf("panic: %w", err),
+				Stack:     debug.Stack(),
+				Tags:      scope.Tags(),
+				Method:    r.Method,
+				URL:       reportedURL(r),
+				Timestamp: time.Now(),
+			})
+
+			httputil.WriteError(w, apperrors.Internal("Internal server error").
+				WithDetails(map[string]string{"requestId": requestID}))
+		}()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// reportedURL identifies the request in error reports by its route pattern,
+// so session tokens in the path and relay tokens in the query string never
+// leave the server. Requests that matched no route fall back to the redacted
+// path.
+func reportedURL(r *http.Request) string {
+	if pattern := RoutePattern(r); pattern != "" {
+		return pattern
+	}
+	return RedactedPath(r)
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/errreport"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []errreport.Event
+}
+
+func (r *recordingReporter) Capture(ctx context.Context, event errreport.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestRecovererMiddleware(t *testing.T) {
+	t.Run("reports panic with tags and returns INTERNAL_ERROR", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		account := &model.Account{ID: "acc-1"}
+		session := &model.Session{ID: "session-1", Status: model.SessionStatusPaired, AccountID: &account.ID}
+
+		auth := NewAuthMiddleware(
+			&mockAccountRepo{findByIDFunc: func(ctx context.Context, id string) (*model.Account, error) {
+				return account, nil
+			}},
+			&mockSessionRepo{findByTokenHashFunc: func(ctx context.Context, tokenHash string) (*model.Session, error) {
+				return session, nil
+			}},
+		)
+		panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+		handler := chimiddleware.RequestID(NewRecovererMiddleware(reporter).Handler(auth.Handler(panicking)))
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/events", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		var body httputil.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "INTERNAL_ERROR", string(body.Code))
+
+		require.Len(t, reporter.events, 1)
+		event := reporter.events[0]
+		assert.EqualError(t, event.Err, "panic: boom")
+		assert.NotEmpty(t, event.Stack)
+		assert.Equal(t, "acc-1", event.Tags["account_id"])
+		assert.Equal(t, "session-1", event.Tags["session_id"])
+		assert.NotEmpty(t, event.Tags["request_id"])
+	})
+
+	t.Run("reports the route pattern instead of the URL", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		r := chi.NewRouter()
+		r.Use(NewRecovererMiddleware(reporter).Handler)
+		r.Route("/v1/sessions", func(r chi.Router) {
+			r.Get("/{sessionToken}/status", func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			})
+		})
+		r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/sessions/st_secret/status?token=rt_secret", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown?token=rt_secret", nil))
+
+		require.Len(t, reporter.events, 2)
+		assert.Equal(t, "/v1/sessions/{sessionToken}/status", reporter.events[0].URL)
+		assert.Equal(t, "/unknown", reporter.events[1].URL)
+	})
+
+	t.Run("re-panics on ErrAbortHandler", func(t *testing.T) {
+		reporter := &recordingReporter{}
+		handler := NewRecovererMiddleware(reporter).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Empty(t, reporter.events)
+	})
+}
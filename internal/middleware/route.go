@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/openclaw/relay-server-go/internal/logging"
+)
+
+// sensitivePathParams are the URL parameters that carry secrets: plugin
+// session tokens and pairing codes.
+var sensitivePathParams = []string{"sessionToken", "code"}
+
+// RoutePattern returns the chi route pattern the request matched, such as
+// /v1/sessions/{sessionToken}/status. It is only complete once the router
+// has served the request, and empty when no route matched.
+func RoutePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
+
+// RedactedPath returns the request path without its query string, which may
+// hold a relay token, and with the values of secret URL parameters masked.
+func RedactedPath(r *http.Request) string {
+	path := r.URL.Path
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return path
+	}
+	for _, name := range sensitivePathParams {
+		if value := rctx.URLParam(name); value != "" {
+			path = strings.Replace(path, "/"+value, "/"+logging.Mask(value), 1)
+		}
+	}
+	return path
+}
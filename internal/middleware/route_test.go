@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactedPath(t *testing.T) {
+	var path, pattern string
+	record := func(w http.ResponseWriter, r *http.Request) {}
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			// Like RequestLogger, read the route once it has been served
+			path, pattern = RedactedPath(r), RoutePattern(r)
+		})
+	})
+	r.Route("/v1/sessions", func(r chi.Router) {
+		r.Get("/{sessionToken}/status", record)
+	})
+	r.Delete("/portal/api/pairing/codes/{code}", record)
+	r.Get("/openclaw/conversations/{key}", record)
+
+	tests := []struct {
+		method, target, path, pattern string
+	}{
+		{http.MethodGet, "/v1/sessions/st_0123456789/status?token=rt_secret", "/v1/sessions/st_0-****/status", "/v1/sessions/{sessionToken}/status"},
+		{http.MethodDelete, "/portal/api/pairing/codes/ABCD-EFGH", "/portal/api/pairing/codes/ABCD-****", "/portal/api/pairing/codes/{code}"},
+		{http.MethodGet, "/openclaw/conversations/ch:user", "/openclaw/conversations/ch:user", "/openclaw/conversations/{key}"},
+		{http.MethodGet, "/unknown?token=rt_secret", "/unknown", ""},
+	}
+	for _, tt := range tests {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.target, nil))
+		assert.Equal(t, tt.path, path, tt.target)
+		assert.Equal(t, tt.pattern, pattern, tt.target)
+	}
+}
@@ -7,6 +7,10 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/openclaw/relay-server-go/internal/audit"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/httputil"
+	"github.com/openclaw/relay-server-go/internal/i18n"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/util"
@@ -40,37 +44,54 @@ func GetPortalUser(ctx context.Context) *model.PortalUser {
 
 // Admin Session Middleware
 
+// adminSessionTouchInterval limits how often request activity is written to
+// the session row; the idle timeout is accurate to within this interval.
+const adminSessionTouchInterval = time.Minute
+
+// AdminSessionOptions tightens admin session validation beyond the absolute
+// expiry stored with the session.
+type AdminSessionOptions struct {
+	// IdleTimeout ends sessions without requests for this long (0 disables).
+	IdleTimeout time.Duration
+	// BindIP and BindUserAgent reject the session cookie when presented by a
+	// client other than the one that logged in.
+	BindIP        bool
+	BindUserAgent bool
+}
+
 type AdminSessionMiddleware struct {
 	sessionRepo       repository.AdminSessionRepository
-	adminPasswordHash string
+	adminPasswordHash func() string
 	sessionSecret     string
+	opts              AdminSessionOptions
 }
 
+// NewAdminSessionMiddleware takes the admin password hash as a function since
+// it can be set at runtime by the first-run bootstrap.
 func NewAdminSessionMiddleware(
 	sessionRepo repository.AdminSessionRepository,
-	adminPasswordHash, sessionSecret string,
+	adminPasswordHash func() string,
+	sessionSecret string,
+	opts AdminSessionOptions,
 ) *AdminSessionMiddleware {
 	return &AdminSessionMiddleware{
 		sessionRepo:       sessionRepo,
 		adminPasswordHash: adminPasswordHash,
 		sessionSecret:     sessionSecret,
+		opts:              opts,
 	}
 }
 
 func (m *AdminSessionMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if m.adminPasswordHash == "" {
-			writeJSON(w, http.StatusServiceUnavailable, map[string]string{
-				"error": "Admin not configured",
-			})
+		if m.adminPasswordHash() == "" {
+			httputil.WriteLocalizedError(w, r, http.StatusServiceUnavailable, apperrors.ErrCodeUnavailable, i18n.APIAdminNotConfigured)
 			return
 		}
 
 		cookie, err := r.Cookie(AdminSessionCookie)
 		if err != nil || cookie.Value == "" {
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Unauthorized",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
 			return
 		}
 
@@ -78,26 +99,84 @@ func (m *AdminSessionMiddleware) Handler(next http.Handler) http.Handler {
 		session, err := m.sessionRepo.FindByTokenHash(r.Context(), tokenHash)
 		if err != nil {
 			log.Error().Err(err).Msg("admin session middleware: database error")
-			writeJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Session validation failed",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APISessionValidationFailed)
 			return
 		}
 
 		if session == nil {
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Unauthorized",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
 			return
 		}
 
+		if reason := m.rejectReason(r, session); reason != "" {
+			if err := m.sessionRepo.Delete(r.Context(), session.ID); err != nil {
+				log.Error().Err(err).Str("sessionId", session.ID).Msg("admin session middleware: failed to end session")
+			}
+			if reason != "idle_timeout" {
+				audit.LogFromRequest(r, audit.Event{
+					Type: audit.EventAuthFailure,
+					Details: map[string]interface{}{
+						"reason":     reason,
+						"target":     "admin",
+						"session_id": session.ID,
+					},
+				})
+			}
+			ClearSessionCookie(w, AdminSessionCookie, "/admin")
+			httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
+			return
+		}
+
+		// Sliding renewal: activity pushes the idle expiry forward.
+		if time.Since(session.LastSeenAt) > adminSessionTouchInterval {
+			if err := m.sessionRepo.Touch(r.Context(), session.ID); err != nil {
+				log.Warn().Err(err).Str("sessionId", session.ID).Msg("admin session middleware: failed to record activity")
+			}
+		}
+
 		ctx := context.WithValue(r.Context(), AdminSessionContextKey, session)
+		ctx = audit.WithActor(ctx, audit.ActorAdmin)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func (m *AdminSessionMiddleware) ValidatePassword(password string) bool {
-	return util.CheckPasswordHash(password, m.adminPasswordHash)
+// rejectReason returns why an otherwise valid session must not be used, or an
+// empty string when it may.
+func (m *AdminSessionMiddleware) rejectReason(r *http.Request, session *model.AdminSession) string {
+	if m.opts.IdleTimeout > 0 && time.Since(session.LastSeenAt) > m.opts.IdleTimeout {
+		return "idle_timeout"
+	}
+	if m.opts.BindIP && session.IPAddress != nil && *session.IPAddress != httputil.ClientIP(r) {
+		return "session_ip_mismatch"
+	}
+	if m.opts.BindUserAgent && session.UserAgent != nil && *session.UserAgent != r.UserAgent() {
+		return "session_user_agent_mismatch"
+	}
+	return ""
+}
+
+// AdminStepUpMaxAge is how long a password entry authorizes destructive admin
+// actions in the same session.
+const AdminStepUpMaxAge = 5 * time.Minute
+
+// RequireRecentAdminAuth annotates routes that need the admin to have entered
+// the password within maxAge, at login or via re-authentication. It must run
+// after the admin session middleware.
+func RequireRecentAdminAuth(maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session := GetAdminSession(r.Context())
+			if session == nil {
+				httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
+				return
+			}
+			if time.Since(session.AuthenticatedAt) > maxAge {
+				httputil.WriteLocalizedError(w, r, http.StatusForbidden, apperrors.ErrCodeReauthRequired, i18n.APIReauthRequired)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 // Portal Session Middleware
@@ -124,9 +203,7 @@ func (m *PortalSessionMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		cookie, err := r.Cookie(PortalSessionCookie)
 		if err != nil || cookie.Value == "" {
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Unauthorized",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
 			return
 		}
 
@@ -134,30 +211,25 @@ func (m *PortalSessionMiddleware) Handler(next http.Handler) http.Handler {
 		session, err := m.sessionRepo.FindByTokenHash(r.Context(), tokenHash)
 		if err != nil {
 			log.Error().Err(err).Msg("portal session middleware: database error")
-			writeJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Session validation failed",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusInternalServerError, apperrors.ErrCodeInternal, i18n.APISessionValidationFailed)
 			return
 		}
 
 		if session == nil {
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Unauthorized",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
 			return
 		}
 
 		user, err := m.userRepo.FindByID(r.Context(), session.UserID)
 		if err != nil || user == nil {
-			writeJSON(w, http.StatusUnauthorized, map[string]string{
-				"error": "Unauthorized",
-			})
+			httputil.WriteLocalizedError(w, r, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized, i18n.APIUnauthorized)
 			return
 		}
 
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, PortalSessionContextKey, session)
 		ctx = context.WithValue(ctx, PortalUserContextKey, user)
+		ctx = audit.WithActor(ctx, audit.PortalActor(user.ID))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -166,12 +238,12 @@ func hashSessionToken(token, secret string) string {
 	return util.HmacSHA256(secret, token)
 }
 
-func SetSessionCookie(w http.ResponseWriter, name, token string, path string, secure bool) {
+func SetSessionCookie(w http.ResponseWriter, name, token string, path string, maxAge time.Duration, secure bool) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     name,
 		Value:    token,
 		Path:     path,
-		MaxAge:   int(SessionMaxAge.Seconds()),
+		MaxAge:   int(maxAge.Seconds()),
 		HttpOnly: true,
 		Secure:   secure,
 		SameSite: http.SameSiteLaxMode,
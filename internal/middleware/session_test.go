@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type fakeAdminSessionRepo struct {
+	session *model.AdminSession
+	deleted []string
+	touched []string
+}
+
+func (f *fakeAdminSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.AdminSession, error) {
+	if f.session == nil || f.session.TokenHash != tokenHash {
+		return nil, nil
+	}
+	return f.session, nil
+}
+
+func (f *fakeAdminSessionRepo) Create(ctx context.Context, params model.CreateAdminSessionParams) (*model.AdminSession, error) {
+	return nil, nil
+}
+
+func (f *fakeAdminSessionRepo) Delete(ctx context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeAdminSessionRepo) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
+	return nil
+}
+
+func (f *fakeAdminSessionRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeAdminSessionRepo) DeleteAll(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeAdminSessionRepo) ListActive(ctx context.Context) ([]model.AdminSession, error) {
+	return nil, nil
+}
+
+func (f *fakeAdminSessionRepo) Touch(ctx context.Context, id string) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func (f *fakeAdminSessionRepo) MarkAuthenticated(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestAdminSessionMiddleware(t *testing.T) {
+	const secret = "test-secret"
+	const token = "admin-token"
+
+	newSession := func(lastSeen time.Time) *model.AdminSession {
+		ip := "203.0.113.5"
+		ua := "test-browser"
+		return &model.AdminSession{
+			ID:         "s1",
+			TokenHash:  hashSessionToken(token, secret),
+			IPAddress:  &ip,
+			UserAgent:  &ua,
+			LastSeenAt: lastSeen,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+	}
+
+	serve := func(repo *fakeAdminSessionRepo, opts AdminSessionOptions, remoteAddr string) int {
+		m := NewAdminSessionMiddleware(repo, func() string { return "$2a$hash" }, secret, opts)
+		handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/api/stats", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", "test-browser")
+		req.AddCookie(&http.Cookie{Name: AdminSessionCookie, Value: token})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("active session passes and records activity", func(t *testing.T) {
+		repo := &fakeAdminSessionRepo{session: newSession(time.Now().Add(-5 * time.Minute))}
+
+		code := serve(repo, AdminSessionOptions{IdleTimeout: 30 * time.Minute}, "203.0.113.5:1234")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"s1"}, repo.touched)
+		assert.Empty(t, repo.deleted)
+	})
+
+	t.Run("recent activity is not written again", func(t *testing.T) {
+		repo := &fakeAdminSessionRepo{session: newSession(time.Now())}
+
+		code := serve(repo, AdminSessionOptions{IdleTimeout: 30 * time.Minute}, "203.0.113.5:1234")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, repo.touched)
+	})
+
+	t.Run("idle session is ended", func(t *testing.T) {
+		repo := &fakeAdminSessionRepo{session: newSession(time.Now().Add(-time.Hour))}
+
+		code := serve(repo, AdminSessionOptions{IdleTimeout: 30 * time.Minute}, "203.0.113.5:1234")
+
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, []string{"s1"}, repo.deleted)
+	})
+
+	t.Run("IP binding rejects another client", func(t *testing.T) {
+		repo := &fakeAdminSessionRepo{session: newSession(time.Now())}
+
+		assert.Equal(t, http.StatusOK, serve(repo, AdminSessionOptions{}, "198.51.100.7:1234"))
+		assert.Equal(t, http.StatusUnauthorized, serve(repo, AdminSessionOptions{BindIP: true}, "198.51.100.7:1234"))
+		assert.Equal(t, []string{"s1"}, repo.deleted)
+	})
+}
+
+func TestRequireRecentAdminAuth(t *testing.T) {
+	handler := RequireRecentAdminAuth(5 * time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(session *model.AdminSession) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/api/accounts/1", nil)
+		if session != nil {
+			req = req.WithContext(context.WithValue(req.Context(), AdminSessionContextKey, session))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("recent authentication passes", func(t *testing.T) {
+		rec := serve(&model.AdminSession{AuthenticatedAt: time.Now().Add(-time.Minute)})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("stale authentication requires re-authentication", func(t *testing.T) {
+		rec := serve(&model.AdminSession{AuthenticatedAt: time.Now().Add(-time.Hour)})
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), "REAUTH_REQUIRED")
+	})
+
+	t.Run("missing session is unauthorized", func(t *testing.T) {
+		rec := serve(nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("message follows the request locale", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/api/accounts/1", nil)
+		ctx := context.WithValue(req.Context(), AdminSessionContextKey, &model.AdminSession{AuthenticatedAt: time.Now().Add(-time.Hour)})
+		req = req.WithContext(i18n.WithLocale(ctx, i18n.English))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"error":"`+i18n.T(i18n.English, i18n.APIReauthRequired)+`","code":"REAUTH_REQUIRED"}`, rec.Body.String())
+	})
+}
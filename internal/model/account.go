@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -10,9 +11,99 @@ type Account struct {
 	RelayTokenHash  *string     `db:"relay_token_hash" json:"-"`
 	Mode            AccountMode `db:"mode" json:"mode"`
 	RateLimitPerMin int         `db:"rate_limit_per_minute" json:"rateLimitPerMinute"`
-	CreatedAt       time.Time   `db:"created_at" json:"createdAt"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updatedAt"`
-	DisabledAt      *time.Time  `db:"disabled_at" json:"disabledAt,omitempty"`
+	KakaoChannelID  *string     `db:"kakao_channel_id" json:"kakaoChannelId,omitempty"`
+	// IANA zone name that defines the account's day boundaries (e.g. "today"
+	// in stats).
+	Timezone string `db:"timezone" json:"timezone"`
+	// Whether the Kakao user profile is included in events and the OpenClaw
+	// API. The relay still fetches it for the portal and admin UI.
+	ShareKakaoProfile bool `db:"share_kakao_profile" json:"shareKakaoProfile"`
+	// Onboarding settings as JSON; nil uses DefaultOnboarding.
+	Onboarding *json.RawMessage `db:"onboarding" json:"-"`
+	CreatedAt  time.Time        `db:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time        `db:"updated_at" json:"updatedAt"`
+	DisabledAt *time.Time       `db:"disabled_at" json:"disabledAt,omitempty"`
+	// Set while a portal deletion request is pending; the account is purged
+	// once DeletionScheduledAt passes unless the request is cancelled.
+	DeletionRequestedAt *time.Time `db:"deletion_requested_at" json:"deletionRequestedAt,omitempty"`
+	DeletionScheduledAt *time.Time `db:"deletion_scheduled_at" json:"deletionScheduledAt,omitempty"`
+	// Set by an admin to keep the account's data from being purged or
+	// erased; see LegalHold.
+	LegalHoldAt     *time.Time `db:"legal_hold_at" json:"legalHoldAt,omitempty"`
+	LegalHoldReason *string    `db:"legal_hold_reason" json:"legalHoldReason,omitempty"`
+	// When set, inbound messages are POSTed here instead of being consumed
+	// over the event stream; see WebhookDeliveryService.
+	WebhookURL *string `db:"webhook_url" json:"webhookUrl,omitempty"`
+	// Signing secret of webhook requests, encrypted when ENCRYPTION_KEY is set
+	WebhookSecret *string `db:"webhook_secret" json:"-"`
+	// Secret replaced by the last rotation; requests are signed with it too
+	// until WebhookPreviousSecretExpiresAt.
+	WebhookPreviousSecret          *string    `db:"webhook_previous_secret" json:"-"`
+	WebhookPreviousSecretExpiresAt *time.Time `db:"webhook_previous_secret_expires_at" json:"-"`
+	// Consecutive failed webhook posts. Once there are enough of them the
+	// account falls back to the event stream (WebhookDegradedAt) and its
+	// endpoint is probed at WebhookProbeAt until it answers again.
+	WebhookFailures   int        `db:"webhook_failures" json:"-"`
+	WebhookDegradedAt *time.Time `db:"webhook_degraded_at" json:"-"`
+	WebhookProbeAt    *time.Time `db:"webhook_probe_at" json:"-"`
+}
+
+// UsesWebhook reports whether the account receives messages by webhook.
+func (a *Account) UsesWebhook() bool {
+	return a.WebhookURL != nil && *a.WebhookURL != ""
+}
+
+// WebhookDegraded reports whether the account's webhook failed too often
+// and its messages are consumed over the event stream until it recovers.
+func (a *Account) WebhookDegraded() bool {
+	return a.UsesWebhook() && a.WebhookDegradedAt != nil
+}
+
+// PreviousWebhookSecret returns the secret replaced by the last rotation
+// while it is still valid at now.
+func (a *Account) PreviousWebhookSecret(now time.Time) *string {
+	if a.WebhookPreviousSecret == nil || a.WebhookPreviousSecretExpiresAt == nil || !now.Before(*a.WebhookPreviousSecretExpiresAt) {
+		return nil
+	}
+	return a.WebhookPreviousSecret
+}
+
+// Onboarding is the welcome sequence sent to a Kakao user with the reply to a
+// successful /pair. Empty texts use the relay's default wording in the user's
+// language.
+type Onboarding struct {
+	Enabled        bool     `json:"enabled"`
+	Intro          string   `json:"intro"`
+	ExamplePrompts []string `json:"examplePrompts"`
+	PrivacyNotice  string   `json:"privacyNotice"`
+}
+
+// DefaultOnboarding is used by accounts that have not configured onboarding.
+func DefaultOnboarding() Onboarding {
+	return Onboarding{Enabled: true, ExamplePrompts: []string{}}
+}
+
+// DefaultTimezone is used for accounts that have not picked a timezone, and
+// when a stored zone cannot be loaded.
+const DefaultTimezone = "Asia/Seoul"
+
+// Location returns the account's time zone, falling back to DefaultTimezone.
+func (a *Account) Location() *time.Location {
+	return LoadTimezone(a.Timezone)
+}
+
+// LoadTimezone resolves an IANA zone name, falling back to DefaultTimezone
+// (and to UTC if even that is unavailable).
+func LoadTimezone(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
 }
 
 type CreateAccountParams struct {
@@ -22,9 +113,35 @@ type CreateAccountParams struct {
 	RateLimitPerMin int
 }
 
+// AccountDeletionPreview counts what deleting an account affects. Messages,
+// pairing codes and portal users (with their OAuth links) are removed;
+// conversations and plugin sessions are detached from the account.
+type AccountDeletionPreview struct {
+	Conversations    int      `db:"conversations" json:"conversations"`
+	InboundMessages  int      `db:"inbound_messages" json:"inboundMessages"`
+	OutboundMessages int      `db:"outbound_messages" json:"outboundMessages"`
+	PairingCodes     int      `db:"pairing_codes" json:"pairingCodes"`
+	Sessions         int      `db:"sessions" json:"sessions"`
+	PortalUsers      int      `db:"portal_users" json:"portalUsers"`
+	OAuthProviders   []string `db:"-" json:"oauthProviders"`
+	// The account or one of its conversations is under legal hold, so
+	// deletion will be refused.
+	LegalHold bool `db:"legal_hold" json:"legalHold"`
+}
+
 type UpdateAccountParams struct {
 	OpenclawUserID  *string
 	Mode            *AccountMode
 	RateLimitPerMin *int
 	DisabledAt      *time.Time
 }
+
+// AccountFilter narrows admin account listings. Zero or nil fields match all
+// accounts. An account is suspended while DisabledAt is set.
+type AccountFilter struct {
+	Mode          AccountMode
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	HasPortalUser *bool
+	Suspended     *bool
+}
@@ -5,13 +5,19 @@ import (
 )
 
 type AdminSession struct {
-	ID        string    `db:"id" json:"id"`
-	TokenHash string    `db:"token_hash" json:"-"`
-	ExpiresAt time.Time `db:"expires_at" json:"expiresAt"`
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	ID              string    `db:"id" json:"id"`
+	TokenHash       string    `db:"token_hash" json:"-"`
+	IPAddress       *string   `db:"ip_address" json:"ipAddress"`
+	UserAgent       *string   `db:"user_agent" json:"userAgent"`
+	LastSeenAt      time.Time `db:"last_seen_at" json:"lastSeenAt"`
+	AuthenticatedAt time.Time `db:"authenticated_at" json:"authenticatedAt"`
+	ExpiresAt       time.Time `db:"expires_at" json:"expiresAt"`
+	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
 }
 
 type CreateAdminSessionParams struct {
 	TokenHash string
 	ExpiresAt time.Time
+	IPAddress *string
+	UserAgent *string
 }
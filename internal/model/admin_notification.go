@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AdminNotification is an alert raised by the server, kept for admins. Kind
+// is an alert.Kind and Name the alert; while the notification is unread,
+// the same alert for the same Subject updates it and counts Occurrences.
+type AdminNotification struct {
+	ID             string           `db:"id" json:"id"`
+	Kind           string           `db:"kind" json:"kind"`
+	Name           string           `db:"name" json:"name"`
+	Subject        string           `db:"subject" json:"subject"`
+	Message        string           `db:"message" json:"message"`
+	Details        *json.RawMessage `db:"details" json:"details"`
+	Occurrences    int              `db:"occurrences" json:"occurrences"`
+	CreatedAt      time.Time        `db:"created_at" json:"createdAt"`
+	LastOccurredAt time.Time        `db:"last_occurred_at" json:"lastOccurredAt"`
+	// Set when the condition ended after the notification was raised
+	ResolvedAt *time.Time `db:"resolved_at" json:"resolvedAt"`
+	ReadAt     *time.Time `db:"read_at" json:"readAt"`
+}
+
+// AdminNotificationFilter narrows notification listings. Zero fields match
+// all notifications.
+type AdminNotificationFilter struct {
+	Kind       string
+	UnreadOnly bool
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ChangeEntityType is the kind of thing a change history entry is about.
+type ChangeEntityType string
+
+const (
+	// EntityKey is the setting key
+	ChangeEntityAccountSetting ChangeEntityType = "account_setting"
+	// EntityKey is the conversation key; values are ConversationStateValue
+	ChangeEntityConversationState ChangeEntityType = "conversation_state"
+)
+
+// ChangeHistoryEntry records one change: who (Actor) changed what (EntityType
+// and EntityKey) from OldValue to NewValue, and when. A nil value means the
+// setting had its default or the conversation did not exist.
+type ChangeHistoryEntry struct {
+	ID         string           `db:"id" json:"id"`
+	AccountID  *string          `db:"account_id" json:"accountId"`
+	EntityType ChangeEntityType `db:"entity_type" json:"entityType"`
+	EntityKey  string           `db:"entity_key" json:"entityKey"`
+	OldValue   *json.RawMessage `db:"old_value" json:"oldValue"`
+	NewValue   *json.RawMessage `db:"new_value" json:"newValue"`
+	Actor      string           `db:"actor" json:"actor"`
+	ChangedAt  time.Time        `db:"changed_at" json:"changedAt"`
+}
+
+// ConversationStateValue is the recorded value of a conversation state.
+type ConversationStateValue struct {
+	State     PairingState `json:"state"`
+	AccountID *string      `json:"accountId"`
+}
+
+// ChangeHistoryFilter narrows change history listings. Zero or nil fields
+// match all entries.
+type ChangeHistoryFilter struct {
+	AccountID  string
+	EntityType ChangeEntityType
+	EntityKey  string
+	Since      *time.Time
+	Until      *time.Time
+}
@@ -11,11 +11,80 @@ type ConversationMapping struct {
 	PlusfriendUserKey     string       `db:"plusfriend_user_key" json:"plusfriendUserKey"`
 	AccountID             *string      `db:"account_id" json:"accountId,omitempty"`
 	State                 PairingState `db:"state" json:"state"`
+	Nickname              *string      `db:"nickname" json:"nickname,omitempty"`
+	Notes                 *string      `db:"notes" json:"notes,omitempty"`
 	LastCallbackURL       *string      `db:"last_callback_url" json:"-"`
 	LastCallbackExpiresAt *time.Time   `db:"last_callback_expires_at" json:"-"`
 	FirstSeenAt           time.Time    `db:"first_seen_at" json:"firstSeenAt"`
 	LastSeenAt            time.Time    `db:"last_seen_at" json:"lastSeenAt"`
 	PairedAt              *time.Time   `db:"paired_at" json:"pairedAt,omitempty"`
+	// Profile fetched from the Kakao API; see KakaoProfile
+	KakaoNickname         *string    `db:"kakao_nickname" json:"kakaoNickname,omitempty"`
+	KakaoProfileImageURL  *string    `db:"kakao_profile_image_url" json:"kakaoProfileImageUrl,omitempty"`
+	KakaoProfileFetchedAt *time.Time `db:"kakao_profile_fetched_at" json:"kakaoProfileFetchedAt,omitempty"`
+	// Nil until the user answers the consent prompt
+	ContentConsent   *ContentConsent `db:"content_consent" json:"contentConsent,omitempty"`
+	ContentConsentAt *time.Time      `db:"content_consent_at" json:"contentConsentAt,omitempty"`
+	// Set by an admin to keep the conversation from being erased
+	LegalHoldAt     *time.Time `db:"legal_hold_at" json:"legalHoldAt,omitempty"`
+	LegalHoldReason *string    `db:"legal_hold_reason" json:"legalHoldReason,omitempty"`
+	// Set by the agent to hold back the conversation's messages; see
+	// DeliveryPaused
+	DeliveryPausedAt *time.Time `db:"delivery_paused_at" json:"deliveryPausedAt,omitempty"`
+	PauseAutoReply   *string    `db:"pause_auto_reply" json:"pauseAutoReply,omitempty"`
+}
+
+// MappingFilter narrows admin conversation listings. Zero or nil fields
+// match all conversations.
+type MappingFilter struct {
+	AccountID      string
+	State          PairingState
+	KakaoChannelID string
+	LastSeenAfter  *time.Time
+	LastSeenBefore *time.Time
+}
+
+// KakaoProfile is the Kakao user's own profile, as opposed to the nickname
+// the account assigns to a connection.
+type KakaoProfile struct {
+	Nickname        *string   `json:"nickname"`
+	ProfileImageURL *string   `json:"profileImageUrl"`
+	FetchedAt       time.Time `json:"fetchedAt"`
+}
+
+// KakaoProfile returns the stored profile, or nil if none has been fetched.
+func (c *ConversationMapping) KakaoProfile() *KakaoProfile {
+	if c.KakaoProfileFetchedAt == nil {
+		return nil
+	}
+	return &KakaoProfile{
+		Nickname:        c.KakaoNickname,
+		ProfileImageURL: c.KakaoProfileImageURL,
+		FetchedAt:       *c.KakaoProfileFetchedAt,
+	}
+}
+
+// DeliveryPaused reports whether the agent paused the conversation. Its
+// messages are stored but not delivered to the account until it resumes.
+func (c *ConversationMapping) DeliveryPaused() bool {
+	return c.DeliveryPausedAt != nil
+}
+
+// ValidCallbackURL returns the latest callback URL Kakao issued for the
+// conversation if it has not expired at now.
+func (c *ConversationMapping) ValidCallbackURL(now time.Time) (string, bool) {
+	return validCallback(c.LastCallbackURL, c.LastCallbackExpiresAt, now)
+}
+
+// ConnectionHealth is derived message activity for one conversation.
+// RecentFailureCount counts failed outbound messages within the recent window.
+type ConnectionHealth struct {
+	ConversationKey    string     `db:"conversation_key" json:"-"`
+	LastInboundAt      *time.Time `db:"last_inbound_at" json:"lastInboundAt"`
+	LastOutboundAt     *time.Time `db:"last_outbound_at" json:"lastOutboundAt"`
+	QueuedCount        int        `db:"queued_count" json:"queuedCount"`
+	RecentFailureCount int        `db:"recent_failure_count" json:"recentFailureCount"`
+	ConsumerConnected  bool       `db:"-" json:"consumerConnected"`
 }
 
 type UpsertConversationParams struct {
@@ -25,3 +94,25 @@ type UpsertConversationParams struct {
 	CallbackURL       *string
 	CallbackExpiresAt *time.Time
 }
+
+// ConversationSort is a column account conversations can be ordered by
+type ConversationSort string
+
+const (
+	ConversationSortLastSeenAt ConversationSort = "last_seen_at"
+	ConversationSortPairedAt   ConversationSort = "paired_at"
+)
+
+// ListConversationsParams filters and pages an account's conversations.
+// Label matches the user-assigned nickname case-insensitively; Query matches
+// nickname, notes or key anywhere.
+type ListConversationsParams struct {
+	AccountID string
+	States    []PairingState
+	Label     string
+	Query     string
+	Sort      ConversationSort
+	Ascending bool
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,14 @@
+package model
+
+// Keys of the deployment_settings table
+const (
+	DeploymentSettingAdminPasswordHash   = "admin_password_hash"
+	DeploymentSettingAdminPasswordSetAt  = "admin_password_set_at"
+	DeploymentSettingAdminPasswordSeed   = "admin_password_env_hash"
+	DeploymentSettingAdminSessionSecret  = "admin_session_secret"
+	DeploymentSettingPortalSessionSecret = "portal_session_secret"
+	DeploymentSettingSSESigningKey       = "sse_signing_key"
+	DeploymentSettingBootstrappedAt      = "bootstrapped_at"
+	DeploymentSettingBootstrappedFrom    = "bootstrapped_from"
+	DeploymentSettingName                = "deployment_name"
+)
@@ -14,6 +14,17 @@ const (
 	PairingStatePending  PairingState = "pending"
 	PairingStatePaired   PairingState = "paired"
 	PairingStateBlocked  PairingState = "blocked"
+	// Archived conversations stay paired but are not relayed until the user
+	// sends another message
+	PairingStateArchived PairingState = "archived"
+)
+
+// ContentConsent records the Kakao user's answer to storing message bodies.
+type ContentConsent string
+
+const (
+	ContentConsentGranted ContentConsent = "granted"
+	ContentConsentDenied  ContentConsent = "denied"
 )
 
 type InboundMessageStatus string
@@ -31,6 +42,9 @@ const (
 	OutboundStatusPending OutboundMessageStatus = "pending"
 	OutboundStatusSent    OutboundMessageStatus = "sent"
 	OutboundStatusFailed  OutboundMessageStatus = "failed"
+	// OutboundStatusCancelled marks a pending message the plugin withdrew
+	// before it was delivered.
+	OutboundStatusCancelled OutboundMessageStatus = "cancelled"
 )
 
 type SessionStatus string
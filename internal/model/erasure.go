@@ -0,0 +1,28 @@
+package model
+
+// ErasureMode selects what erasing a Kakao user does to their messages.
+type ErasureMode string
+
+const (
+	// ErasureModeDelete removes the messages.
+	ErasureModeDelete ErasureMode = "delete"
+	// ErasureModeAnonymize keeps the messages for account statistics but
+	// strips their content and moves them to a random conversation key that
+	// cannot be linked back to the user.
+	ErasureModeAnonymize ErasureMode = "anonymize"
+)
+
+// KakaoUserErasure counts the rows erasing a Kakao user touches, across all
+// accounts the user talked to.
+type KakaoUserErasure struct {
+	Conversations       int `db:"conversations" json:"conversations"`
+	Accounts            int `db:"accounts" json:"accounts"`
+	InboundMessages     int `db:"inbound_messages" json:"inboundMessages"`
+	OutboundMessages    int `db:"outbound_messages" json:"outboundMessages"`
+	PortalAccessCodes   int `db:"portal_access_codes" json:"portalAccessCodes"`
+	ExperimentExposures int `db:"experiment_exposures" json:"experimentExposures"`
+	Sessions            int `db:"sessions" json:"sessions"`
+	// Conversations left in place because they or their account are under
+	// legal hold
+	HeldConversations int `db:"held_conversations" json:"heldConversations"`
+}
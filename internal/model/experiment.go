@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type ExperimentExposure struct {
+	ID              string    `db:"id" json:"id"`
+	Experiment      string    `db:"experiment" json:"experiment"`
+	Variant         string    `db:"variant" json:"variant"`
+	ConversationKey string    `db:"conversation_key" json:"conversationKey"`
+	FirstShownAt    time.Time `db:"first_shown_at" json:"firstShownAt"`
+	LastShownAt     time.Time `db:"last_shown_at" json:"lastShownAt"`
+	ShownCount      int       `db:"shown_count" json:"shownCount"`
+}
+
+// ExperimentVariantStats summarizes pairing conversion for one variant.
+// A conversation counts as converted when it was paired after it first saw the variant.
+type ExperimentVariantStats struct {
+	Experiment     string  `db:"experiment" json:"experiment"`
+	Variant        string  `db:"variant" json:"variant"`
+	Exposures      int     `db:"exposures" json:"exposures"`
+	Conversions    int     `db:"conversions" json:"conversions"`
+	ConversionRate float64 `db:"-" json:"conversionRate"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// IntegrityFinding is the result of one consistency check.
+type IntegrityFinding struct {
+	Check       string   `json:"check"`
+	Description string   `json:"description"`
+	Count       int      `json:"count"`
+	SampleIDs   []string `json:"sampleIds,omitempty"`
+	Repairable  bool     `json:"repairable"`
+	Repaired    int64    `json:"repaired"`
+}
+
+type IntegrityReport struct {
+	CheckedAt time.Time          `json:"checkedAt"`
+	Repair    bool               `json:"repair"`
+	Findings  []IntegrityFinding `json:"findings"`
+}
+
+// Issues returns the number of inconsistent rows found across all checks.
+func (r *IntegrityReport) Issues() int {
+	total := 0
+	for _, f := range r.Findings {
+		total += f.Count
+	}
+	return total
+}
@@ -18,20 +18,83 @@ type InboundMessage struct {
 	CreatedAt         time.Time            `db:"created_at" json:"createdAt"`
 	DeliveredAt       *time.Time           `db:"delivered_at" json:"deliveredAt,omitempty"`
 	AckedAt           *time.Time           `db:"acked_at" json:"ackedAt,omitempty"`
+	// Webhook delivery state of accounts with a webhook URL
+	WebhookAttempts int        `db:"webhook_attempts" json:"webhookAttempts,omitempty"`
+	WebhookNextAt   *time.Time `db:"webhook_next_at" json:"-"`
+	WebhookError    *string    `db:"webhook_error" json:"webhookError,omitempty"`
 }
 
-// ToSSEEventData returns JSON data for SSE message events
-func (m *InboundMessage) ToSSEEventData() json.RawMessage {
-	data, _ := json.Marshal(map[string]any{
-		"id":              m.ID,
-		"conversationKey": m.ConversationKey,
-		"kakaoPayload":    m.KakaoPayload,
-		"normalized":      m.NormalizedMessage,
-		"createdAt":       m.CreatedAt,
+// OffloadedPayload replaces a Kakao payload that was moved to blob storage;
+// only the reference stays in Postgres.
+type OffloadedPayload struct {
+	Offloaded     bool   `json:"offloaded"`
+	BlobKey       string `json:"blobKey"`
+	OriginalBytes int    `json:"originalBytes"`
+}
+
+// maxOffloadedPayloadBytes bounds the size of a reference, so large payloads
+// are not decoded just to find out they are not one.
+const maxOffloadedPayloadBytes = 512
+
+// ParseOffloadedPayload returns the reference stored in place of an
+// offloaded payload.
+func ParseOffloadedPayload(payload json.RawMessage) (*OffloadedPayload, bool) {
+	if len(payload) > maxOffloadedPayloadBytes {
+		return nil, false
+	}
+	var ref OffloadedPayload
+	if err := json.Unmarshal(payload, &ref); err != nil || !ref.Offloaded || ref.BlobKey == "" {
+		return nil, false
+	}
+	return &ref, true
+}
+
+// InboundBacklog summarizes the inbound messages still waiting for a plugin
+// to fetch them.
+type InboundBacklog struct {
+	Queued         int        `db:"queued"`
+	OldestQueuedAt *time.Time `db:"oldest_queued_at"`
+}
+
+// MessageEvent is the data of the SSE message event.
+type MessageEvent struct {
+	ID              string           `json:"id"`
+	ConversationKey string           `json:"conversationKey"`
+	KakaoPayload    json.RawMessage  `json:"kakaoPayload"`
+	Normalized      *json.RawMessage `json:"normalized"`
+	Profile         *KakaoProfile    `json:"profile,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+}
+
+// ToSSEEventData returns JSON data for SSE message events. profile is the
+// sender's Kakao profile, nil if unknown or not shared with the account.
+func (m *InboundMessage) ToSSEEventData(profile *KakaoProfile) json.RawMessage {
+	data, _ := json.Marshal(MessageEvent{
+		ID:              m.ID,
+		ConversationKey: m.ConversationKey,
+		KakaoPayload:    m.KakaoPayload,
+		Normalized:      m.NormalizedMessage,
+		Profile:         profile,
+		CreatedAt:       m.CreatedAt,
 	})
 	return data
 }
 
+// ValidCallbackURL returns the message's callback URL if it has not expired
+// at now.
+func (m *InboundMessage) ValidCallbackURL(now time.Time) (string, bool) {
+	return validCallback(m.CallbackURL, m.CallbackExpiresAt, now)
+}
+
+// validCallback reports whether a callback URL is present and unexpired; a
+// nil expiry never expires.
+func validCallback(url *string, expiresAt *time.Time, now time.Time) (string, bool) {
+	if url == nil || (expiresAt != nil && !expiresAt.After(now)) {
+		return "", false
+	}
+	return *url, true
+}
+
 type CreateInboundMessageParams struct {
 	AccountID         string
 	ConversationKey   string
@@ -53,6 +116,27 @@ type OutboundMessage struct {
 	ErrorMessage     *string               `db:"error_message" json:"errorMessage,omitempty"`
 	CreatedAt        time.Time             `db:"created_at" json:"createdAt"`
 	SentAt           *time.Time            `db:"sent_at" json:"sentAt,omitempty"`
+	// Set on replies deferred until then
+	ScheduledAt  *time.Time `db:"scheduled_at" json:"scheduledAt,omitempty"`
+	CallbackURL  *string    `db:"callback_url" json:"-"`
+	DispatchedAt *time.Time `db:"dispatched_at" json:"-"`
+	// Set on replies that must be the only one to their inbound message
+	ReplyOnce bool `db:"reply_once" json:"-"`
+	// Kakao's answer to the callback post: HTTP status, start of the body
+	// and the task ID when one was returned
+	CallbackStatus   *int    `db:"callback_status" json:"callbackStatus,omitempty"`
+	CallbackResponse *string `db:"callback_response" json:"callbackResponse,omitempty"`
+	CallbackTaskID   *string `db:"callback_task_id" json:"callbackTaskId,omitempty"`
+}
+
+// CallbackResponse is what Kakao answered when a reply was posted to its
+// callback URL.
+type CallbackResponse struct {
+	Status int
+	// Start of the response body
+	Body string
+	// Set when Kakao returned one
+	TaskID string
 }
 
 type CreateOutboundMessageParams struct {
@@ -61,4 +145,45 @@ type CreateOutboundMessageParams struct {
 	ConversationKey  string
 	KakaoTarget      json.RawMessage
 	ResponsePayload  json.RawMessage
+	// Set to defer sending to CallbackURL until ScheduledAt
+	ScheduledAt *time.Time
+	CallbackURL *string
+	// Refuse the reply when InboundMessageID already has a pending or sent one
+	ReplyOnce bool
+}
+
+// TimelineMessage is a row of the merged inbound/outbound message timeline.
+// Content is the normalized message for inbound rows and the response
+// payload for outbound rows.
+type TimelineMessage struct {
+	ID              string           `db:"id"`
+	ConversationKey string           `db:"conversation_key"`
+	Direction       string           `db:"direction"`
+	Status          string           `db:"status"`
+	Content         *json.RawMessage `db:"content"`
+	CreatedAt       time.Time        `db:"created_at"`
+}
+
+// InboundExpiryFilter selects the queued inbound messages an admin expires.
+// Empty fields match all messages.
+type InboundExpiryFilter struct {
+	AccountID       string
+	ConversationKey string
+	// Only messages created before this time
+	CreatedBefore *time.Time
+}
+
+// InboundExpiryResult reports how many queued messages were expired, or
+// would be on a dry run.
+type InboundExpiryResult struct {
+	Expired   int            `json:"expired"`
+	ByAccount map[string]int `json:"byAccount"`
+	DryRun    bool           `json:"dryRun"`
+}
+
+// MessageFilter narrows message history queries. Empty fields match all
+// messages. Status is compared against either direction's status values.
+type MessageFilter struct {
+	ConversationKey string
+	Status          string
 }
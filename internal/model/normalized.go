@@ -0,0 +1,113 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// NormalizedMessageVersion is the schema version written by this server.
+// Consumers should ignore fields they do not know and treat a missing
+// version as the original {userId, text, channelId} format.
+const NormalizedMessageVersion = 1
+
+type NormalizedMessageType string
+
+const (
+	NormalizedMessageTypeText  NormalizedMessageType = "text"
+	NormalizedMessageTypeMedia NormalizedMessageType = "media"
+)
+
+// NormalizedMessage is the channel-independent form of an inbound message
+// stored in normalized_message and sent to plugins as "normalized".
+type NormalizedMessage struct {
+	Version     int                   `json:"version"`
+	Type        NormalizedMessageType `json:"type"`
+	Text        string                `json:"text"`
+	Attachments []MessageAttachment   `json:"attachments,omitempty"`
+	Sender      MessageSender         `json:"sender"`
+	Locale      string                `json:"locale,omitempty"`
+
+	// Flat copies of Sender kept for consumers of the unversioned format
+	UserID    string `json:"userId"`
+	ChannelID string `json:"channelId"`
+
+	// Set when the text was cut to the inbound size limit
+	Truncated     bool `json:"truncated,omitempty"`
+	OriginalBytes int  `json:"originalBytes,omitempty"`
+}
+
+type MessageSender struct {
+	UserID    string `json:"userId"`
+	ChannelID string `json:"channelId"`
+}
+
+type MessageAttachment struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	Name string `json:"name,omitempty"`
+}
+
+// NewTextMessage builds a current-version text message.
+func NewTextMessage(sender MessageSender, text, locale string) *NormalizedMessage {
+	return &NormalizedMessage{
+		Version:   NormalizedMessageVersion,
+		Type:      NormalizedMessageTypeText,
+		Text:      text,
+		Sender:    sender,
+		Locale:    locale,
+		UserID:    sender.UserID,
+		ChannelID: sender.ChannelID,
+	}
+}
+
+// Validate checks the message against the schema of its version.
+func (m *NormalizedMessage) Validate() error {
+	if m.Version < 1 || m.Version > NormalizedMessageVersion {
+		return fmt.Errorf("unsupported normalized message version %d", m.Version)
+	}
+	if m.Sender.UserID == "" {
+		return errors.New("normalized message sender.userId is required")
+	}
+	if m.UserID != m.Sender.UserID || m.ChannelID != m.Sender.ChannelID {
+		return errors.New("normalized message userId/channelId must match sender")
+	}
+	switch m.Type {
+	case NormalizedMessageTypeText:
+		if m.Text == "" && !m.Truncated {
+			return errors.New("normalized text message has no text")
+		}
+	case NormalizedMessageTypeMedia:
+		if len(m.Attachments) == 0 {
+			return errors.New("normalized media message has no attachments")
+		}
+	default:
+		return fmt.Errorf("unknown normalized message type %q", m.Type)
+	}
+	for _, a := range m.Attachments {
+		if a.Type == "" || a.URL == "" {
+			return errors.New("normalized message attachments need a type and url")
+		}
+	}
+	return nil
+}
+
+// Marshal validates the message and encodes it for storage.
+func (m *NormalizedMessage) Marshal() (json.RawMessage, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// ParseNormalizedMessage decodes and validates a stored message.
+func ParseNormalizedMessage(data json.RawMessage) (*NormalizedMessage, error) {
+	var m NormalizedMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decode normalized message: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Cursor is the position of the last item of a keyset page: its sort key and
+// ID. The next page starts after it, so rows inserted or removed meanwhile do
+// not shift the pages the way offsets do.
+type Cursor struct {
+	At time.Time `json:"t"`
+	ID string    `json:"id"`
+}
+
+// KeysetPage selects up to Limit items after After, or from the start when
+// After is nil. A zero Limit selects every item.
+type KeysetPage struct {
+	After *Cursor
+	Limit int
+}
@@ -13,10 +13,15 @@ type Session struct {
 	AccountID             *string          `db:"account_id" json:"accountId,omitempty"`
 	PairedConversationKey *string          `db:"paired_conversation_key" json:"pairedConversationKey,omitempty"`
 	Metadata              *json.RawMessage `db:"metadata" json:"metadata,omitempty"`
+	ClientIP              *string          `db:"client_ip" json:"clientIp,omitempty"`
+	PluginVersion         *string          `db:"plugin_version" json:"pluginVersion,omitempty"`
 	ExpiresAt             time.Time        `db:"expires_at" json:"expiresAt"`
 	PairedAt              *time.Time       `db:"paired_at" json:"pairedAt,omitempty"`
 	CreatedAt             time.Time        `db:"created_at" json:"createdAt"`
 	UpdatedAt             time.Time        `db:"updated_at" json:"updatedAt"`
+	// Channel of the account whose relay token created the session; its
+	// pairing link points there instead of the deployment channel
+	KakaoChannelID *string `db:"kakao_channel_id" json:"kakaoChannelId,omitempty"`
 }
 
 type CreateSessionParams struct {
@@ -24,4 +29,15 @@ type CreateSessionParams struct {
 	PairingCode      string
 	ExpiresAt        time.Time
 	Metadata         *json.RawMessage
+	ClientIP         *string
+	PluginVersion    *string
+	KakaoChannelID   *string
+}
+
+// PluginVersionCount is the number of active sessions reporting a plugin version.
+// Version is nil for sessions that did not report one.
+type PluginVersionCount struct {
+	Version *string       `db:"plugin_version" json:"version"`
+	Status  SessionStatus `db:"status" json:"status"`
+	Count   int           `db:"count" json:"count"`
 }
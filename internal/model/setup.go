@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Setup checklist steps, in the order a new account usually completes them.
+const (
+	// The relay token was shown to the user, who can now configure a plugin
+	SetupStepTokenRetrieved = "token_retrieved"
+	// A plugin connected with the account's token or paired a session
+	SetupStepSessionCreated = "session_created"
+	// A Kakao user paired with the account
+	SetupStepPaired = "paired"
+	// A Kakao message reached the account
+	SetupStepFirstMessage = "first_message"
+	// A reply was delivered to Kakao
+	SetupStepFirstReply = "first_reply"
+)
+
+// SetupSteps lists the checklist steps in order.
+var SetupSteps = []string{
+	SetupStepTokenRetrieved,
+	SetupStepSessionCreated,
+	SetupStepPaired,
+	SetupStepFirstMessage,
+	SetupStepFirstReply,
+}
+
+type SetupChecklistStep struct {
+	Name   string     `json:"name"`
+	Done   bool       `json:"done"`
+	DoneAt *time.Time `json:"doneAt,omitempty"`
+}
+
+// SetupChecklist is an account's progress through setting up the relay.
+type SetupChecklist struct {
+	Steps     []SetupChecklistStep `json:"steps"`
+	Completed bool                 `json:"completed"`
+}
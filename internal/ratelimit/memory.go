@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	memoryMaxEntries      = 10000
+	memoryCleanupInterval = time.Minute
+)
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket will be full again; after that the entry carries
+	// no state and can be dropped.
+	full time.Time
+}
+
+// MemoryLimiter keeps buckets in process memory. It is suitable for a single
+// instance and for tests. At most maxEntries buckets are kept, so callers
+// rotating through many keys cannot grow it without bound.
+type MemoryLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*memoryBucket
+	maxEntries  int
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:     make(map[string]*memoryBucket),
+		maxEntries:  memoryMaxEntries,
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+func (l *MemoryLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < memoryCleanupInterval && len(l.buckets) < l.maxEntries {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+	if len(l.buckets) >= l.maxEntries {
+		l.evict()
+	}
+}
+
+// evict drops the buckets closest to full until a tenth of the capacity is
+// free. Those carry the least state: a dropped bucket starts full again, which
+// only matters for keys that were actually limited.
+func (l *MemoryLimiter) evict() {
+	keys := make([]string, 0, len(l.buckets))
+	for key := range l.buckets {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return l.buckets[a].full.Compare(l.buckets[b].full)
+	})
+
+	excess := len(l.buckets) - l.maxEntries*9/10
+	for _, key := range keys[:excess] {
+		delete(l.buckets, key)
+	}
+}
+
+func (l *MemoryLimiter) Take(ctx context.Context, scope, id string, bucket Bucket) Result {
+	result := l.apply(scope, id, bucket, 1)
+	record(scope, result)
+	return result
+}
+
+func (l *MemoryLimiter) Peek(ctx context.Context, scope, id string, bucket Bucket) Result {
+	return l.apply(scope, id, bucket, 0)
+}
+
+// apply refills the bucket and takes requested tokens from it if available.
+func (l *MemoryLimiter) apply(scope, id string, bucket Bucket, requested float64) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	interval := bucket.interval()
+	capacity := float64(bucket.Capacity)
+	key := scope + ":" + id
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if requested == 0 {
+			return Result{Allowed: true, Limit: bucket.Capacity, Remaining: bucket.Capacity, ResetAt: now}
+		}
+		b = &memoryBucket{tokens: capacity, last: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.last)
+	if elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(interval))
+	}
+	b.last = now
+
+	result := Result{Limit: bucket.Capacity}
+	if b.tokens >= requested {
+		b.tokens -= requested
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((requested - b.tokens) * float64(interval)))
+	}
+
+	b.full = now.Add(time.Duration(math.Ceil((capacity - b.tokens) * float64(interval))))
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAt = b.full
+	return result
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ScopeStats counts decisions for one scope since process start.
+type ScopeStats struct {
+	Scope   string `json:"scope"`
+	Allowed int64  `json:"allowed"`
+	Denied  int64  `json:"denied"`
+	Errors  int64  `json:"errors"`
+}
+
+type counters struct {
+	allowed atomic.Int64
+	denied  atomic.Int64
+	errors  atomic.Int64
+}
+
+var metrics sync.Map // scope -> *counters
+
+func scopeCounters(scope string) *counters {
+	if c, ok := metrics.Load(scope); ok {
+		return c.(*counters)
+	}
+	c, _ := metrics.LoadOrStore(scope, &counters{})
+	return c.(*counters)
+}
+
+func record(scope string, result Result) {
+	c := scopeCounters(scope)
+	if result.Allowed {
+		c.allowed.Add(1)
+	} else {
+		c.denied.Add(1)
+	}
+}
+
+func recordError(scope string) {
+	scopeCounters(scope).errors.Add(1)
+}
+
+// Stats returns per-scope counters for all limiters in the process, sorted by scope.
+func Stats() []ScopeStats {
+	stats := make([]ScopeStats, 0)
+	metrics.Range(func(key, value any) bool {
+		c := value.(*counters)
+		stats = append(stats, ScopeStats{
+			Scope:   key.(string),
+			Allowed: c.allowed.Load(),
+			Denied:  c.denied.Load(),
+			Errors:  c.errors.Load(),
+		})
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Scope < stats[j].Scope })
+	return stats
+}
@@ -0,0 +1,130 @@
+// Package ratelimit implements token bucket rate limiting with interchangeable
+// Redis and in-memory backends.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/config"
+)
+
+// Bucket describes a token bucket that holds up to Capacity tokens and refills
+// one token every Interval. Capacity is the allowed burst.
+type Bucket struct {
+	Capacity int
+	Interval time.Duration
+}
+
+// Per returns a bucket allowing limit requests per window, refilled smoothly
+// over the window, with a burst of up to limit.
+func Per(limit int, window time.Duration) Bucket {
+	if limit <= 0 {
+		limit = 1
+	}
+	return Bucket{Capacity: limit, Interval: window / time.Duration(limit)}
+}
+
+func (b Bucket) interval() time.Duration {
+	if b.Interval <= 0 {
+		return time.Millisecond
+	}
+	return b.Interval
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next token is available when denied.
+	RetryAfter time.Duration
+	// ResetAt is when the bucket will be full again.
+	ResetAt time.Time
+}
+
+// WarningRatio is the share of a limit that can be used before callers are
+// warned that requests will soon be denied.
+const WarningRatio = 0.8
+
+// WarningCode identifies rate limit warnings in responses and SSE events.
+const WarningCode = "rate_limit_warning"
+
+// Warning tells a caller that it is close to its limit.
+type Warning struct {
+	Code      string    `json:"code"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+// NearLimit reports whether the request was allowed but left at most
+// 1-WarningRatio of the bucket.
+func (r Result) NearLimit() bool {
+	return r.Allowed && r.Limit > 0 && float64(r.Limit-r.Remaining) >= WarningRatio*float64(r.Limit)
+}
+
+// Warning returns the warning to send for a result that is NearLimit.
+func (r Result) Warning() Warning {
+	return Warning{
+		Code:      WarningCode,
+		Limit:     r.Limit,
+		Remaining: r.Remaining,
+		ResetAt:   r.ResetAt.Truncate(time.Second),
+	}
+}
+
+// Limiter consumes one token from the bucket identified by scope and id.
+// Scope groups keys of the same kind (e.g. "account", "login") and is used
+// for metrics; id identifies the caller within the scope.
+type Limiter interface {
+	Take(ctx context.Context, scope, id string, bucket Bucket) Result
+	// Peek reports the bucket's state without taking a token. It is not
+	// counted in metrics.
+	Peek(ctx context.Context, scope, id string, bucket Bucket) Result
+}
+
+// AccountScope is the scope of the per-account API rate limit.
+const AccountScope = "account"
+
+// AccountBucket is the per-account API rate limit: limitPerMin is the burst
+// and tokens refill smoothly over the minute. Zero uses the default limit.
+func AccountBucket(limitPerMin int) Bucket {
+	if limitPerMin <= 0 {
+		limitPerMin = config.DefaultRateLimitPerMin
+	}
+	return Per(limitPerMin, time.Minute)
+}
+
+// RetryAfterSeconds rounds a wait up to whole seconds, as Retry-After requires.
+func RetryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// SetHeaders writes the standard rate limit headers for a result.
+// Retry-After is only set when the request was denied.
+func SetHeaders(w http.ResponseWriter, result Result) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+	if !result.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(RetryAfterSeconds(result.RetryAfter)))
+	}
+}
+
+// allowAll is returned when a backend cannot answer, so that a limiter outage
+// does not take the API down with it.
+func allowAll(now time.Time, bucket Bucket) Result {
+	return Result{
+		Allowed:   true,
+		Limit:     bucket.Capacity,
+		Remaining: bucket.Capacity,
+		ResetAt:   now,
+	}
+}
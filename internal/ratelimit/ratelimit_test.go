@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	opts, err := redis.ParseURL("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis URL not parseable, skipping")
+	}
+	client := redis.NewClient(opts)
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		t.Skip("Redis not available for testing")
+	}
+	client.FlushDB(ctx)
+	return client
+}
+
+// testLimiter runs the behavior every backend must share.
+func testLimiter(t *testing.T, newLimiter func(t *testing.T) Limiter) {
+	ctx := context.Background()
+
+	t.Run("allows burst up to capacity", func(t *testing.T) {
+		limiter := newLimiter(t)
+		bucket := Bucket{Capacity: 5, Interval: time.Minute}
+
+		for i := 0; i < 5; i++ {
+			result := limiter.Take(ctx, "test", "burst", bucket)
+			assert.True(t, result.Allowed, "Request %d should be allowed", i+1)
+			assert.Equal(t, 5, result.Limit)
+			assert.Equal(t, 5-i-1, result.Remaining)
+		}
+
+		result := limiter.Take(ctx, "test", "burst", bucket)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RetryAfter, time.Minute)
+		assert.True(t, result.ResetAt.After(time.Now()))
+	})
+
+	t.Run("refills one token per interval", func(t *testing.T) {
+		limiter := newLimiter(t)
+		bucket := Bucket{Capacity: 2, Interval: 200 * time.Millisecond}
+
+		assert.True(t, limiter.Take(ctx, "test", "refill", bucket).Allowed)
+		assert.True(t, limiter.Take(ctx, "test", "refill", bucket).Allowed)
+		assert.False(t, limiter.Take(ctx, "test", "refill", bucket).Allowed)
+
+		time.Sleep(250 * time.Millisecond)
+
+		assert.True(t, limiter.Take(ctx, "test", "refill", bucket).Allowed)
+		assert.False(t, limiter.Take(ctx, "test", "refill", bucket).Allowed)
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		limiter := newLimiter(t)
+		bucket := Per(1, time.Minute)
+
+		assert.True(t, limiter.Take(ctx, "test", "a", bucket).Allowed)
+		assert.False(t, limiter.Take(ctx, "test", "a", bucket).Allowed)
+		assert.True(t, limiter.Take(ctx, "test", "b", bucket).Allowed)
+		assert.True(t, limiter.Take(ctx, "other", "a", bucket).Allowed)
+	})
+
+	t.Run("peek does not take a token", func(t *testing.T) {
+		limiter := newLimiter(t)
+		bucket := Bucket{Capacity: 3, Interval: time.Minute}
+
+		result := limiter.Peek(ctx, "test", "peek", bucket)
+		assert.Equal(t, 3, result.Remaining)
+
+		limiter.Take(ctx, "test", "peek", bucket)
+		for i := 0; i < 2; i++ {
+			result = limiter.Peek(ctx, "test", "peek", bucket)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, 2, result.Remaining)
+		}
+	})
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	testLimiter(t, func(t *testing.T) Limiter {
+		return NewMemoryLimiter()
+	})
+}
+
+func TestRedisLimiter(t *testing.T) {
+	testLimiter(t, func(t *testing.T) Limiter {
+		client := newTestRedisClient(t)
+		t.Cleanup(func() { client.Close() })
+		return NewRedisLimiter(client)
+	})
+}
+
+func TestRedisLimiter_AllowsOnFailure(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:9999"})
+	defer client.Close()
+
+	limiter := NewRedisLimiter(client)
+	result := limiter.Take(context.Background(), "test_failure", "key", Per(1, time.Minute))
+
+	require.True(t, result.Allowed, "Should allow request on Redis failure")
+	assert.Equal(t, 1, result.Remaining)
+
+	var errors int64
+	for _, s := range Stats() {
+		if s.Scope == "test_failure" {
+			errors = s.Errors
+		}
+	}
+	assert.Equal(t, int64(1), errors)
+}
+
+func TestMemoryLimiter_Cleanup(t *testing.T) {
+	now := time.Now()
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	limiter.Take(context.Background(), "test", "stale", Per(2, time.Minute))
+	assert.Len(t, limiter.buckets, 1)
+
+	now = now.Add(2 * time.Minute)
+	limiter.Take(context.Background(), "test", "fresh", Per(2, time.Minute))
+
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "test:fresh")
+
+	t.Run("caps the number of buckets", func(t *testing.T) {
+		limiter := NewMemoryLimiter()
+		limiter.maxEntries = 10
+		limiter.now = func() time.Time { return now }
+
+		// A limited key's bucket stays empty for the whole window
+		bucket := Per(2, time.Minute)
+		limiter.Take(context.Background(), "test", "limited", bucket)
+		limiter.Take(context.Background(), "test", "limited", bucket)
+		for i := 0; i < 50; i++ {
+			limiter.Take(context.Background(), "test", fmt.Sprintf("rotating-%d", i), Per(100, time.Minute))
+		}
+
+		assert.LessOrEqual(t, len(limiter.buckets), 10)
+		assert.Contains(t, limiter.buckets, "test:limited", "the most limited keys are kept")
+		assert.False(t, limiter.Take(context.Background(), "test", "limited", bucket).Allowed)
+	})
+}
+
+func TestPer(t *testing.T) {
+	bucket := Per(60, time.Minute)
+	assert.Equal(t, 60, bucket.Capacity)
+	assert.Equal(t, time.Second, bucket.Interval)
+
+	assert.Equal(t, 1, Per(0, time.Minute).Capacity)
+}
+
+func TestResult_NearLimit(t *testing.T) {
+	assert.False(t, Result{Allowed: true, Limit: 10, Remaining: 3}.NearLimit())
+	assert.True(t, Result{Allowed: true, Limit: 10, Remaining: 2}.NearLimit())
+	assert.True(t, Result{Allowed: true, Limit: 10, Remaining: 0}.NearLimit())
+	assert.False(t, Result{Allowed: false, Limit: 10, Remaining: 0}.NearLimit(), "denied requests are not warnings")
+}
+
+func TestSetHeaders(t *testing.T) {
+	t.Run("allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SetHeaders(rec, Result{Allowed: true, Limit: 10, Remaining: 9, ResetAt: time.Unix(1700000000, 0)})
+
+		assert.Equal(t, "10", rec.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "9", rec.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "1700000000", rec.Header().Get("X-RateLimit-Reset"))
+		assert.Empty(t, rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("denied rounds retry up to seconds", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SetHeaders(rec, Result{Allowed: false, Limit: 10, RetryAfter: 1500 * time.Millisecond})
+
+		assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	})
+}
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const redisKeyPrefix = "ratelimit:bucket:"
+
+// tokenBucketScript stores the bucket state (tokens, last refill in ms) in a
+// hash so that refill is computed lazily and atomically on each request.
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local requested = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+    tokens = capacity
+    ts = now
+end
+
+local elapsed = math.max(0, now - ts)
+tokens = math.min(capacity, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= requested then
+    tokens = tokens - requested
+    allowed = 1
+end
+
+redis.call('HSET', key, 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', key, math.ceil(capacity / rate) + 1000)
+
+local waitMs = 0
+if allowed == 0 then
+    waitMs = math.ceil((requested - tokens) / rate)
+end
+local fullMs = math.ceil((capacity - tokens) / rate)
+
+return {allowed, math.floor(tokens), waitMs, fullMs}
+`)
+
+// RedisLimiter keeps buckets in Redis so limits hold across instances.
+// If Redis is unavailable the request is allowed.
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (l *RedisLimiter) Take(ctx context.Context, scope, id string, bucket Bucket) Result {
+	result, err := l.run(ctx, scope, id, bucket, 1)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("scope", scope).
+			Str("id", id).
+			Msg("rate limit check failed, allowing request")
+		recordError(scope)
+		return allowAll(time.Now(), bucket)
+	}
+	record(scope, result)
+	return result
+}
+
+func (l *RedisLimiter) Peek(ctx context.Context, scope, id string, bucket Bucket) Result {
+	result, err := l.run(ctx, scope, id, bucket, 0)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("scope", scope).
+			Str("id", id).
+			Msg("rate limit peek failed")
+		return allowAll(time.Now(), bucket)
+	}
+	return result
+}
+
+// run refills the bucket and takes requested tokens from it if available.
+func (l *RedisLimiter) run(ctx context.Context, scope, id string, bucket Bucket, requested int) (Result, error) {
+	now := time.Now()
+	ratePerMs := float64(time.Millisecond) / float64(bucket.interval())
+
+	values, err := tokenBucketScript.Run(
+		ctx,
+		l.client,
+		[]string{redisKeyPrefix + scope + ":" + id},
+		bucket.Capacity,
+		ratePerMs,
+		now.UnixMilli(),
+		requested,
+	).Int64Slice()
+
+	if err == nil && len(values) != 4 {
+		err = fmt.Errorf("unexpected rate limit result: %v", values)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      bucket.Capacity,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAt:    now.Add(time.Duration(values[3]) * time.Millisecond),
+	}, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -13,11 +14,40 @@ import (
 type AccountRepository interface {
 	FindByID(ctx context.Context, id string) (*model.Account, error)
 	FindByTokenHash(ctx context.Context, tokenHash string) (*model.Account, error)
-	FindAll(ctx context.Context, limit, offset int) ([]model.Account, error)
+	FindAll(ctx context.Context, filter model.AccountFilter, limit, offset int) ([]model.Account, error)
+	CountFiltered(ctx context.Context, filter model.AccountFilter) (int, error)
 	Create(ctx context.Context, params model.CreateAccountParams) (*model.Account, error)
 	Update(ctx context.Context, id string, params model.UpdateAccountParams) (*model.Account, error)
 	UpdateToken(ctx context.Context, id, tokenHash string) (*model.Account, error)
+	UpdateKakaoChannelID(ctx context.Context, id string, channelID *string) (*model.Account, error)
+	UpdateTimezone(ctx context.Context, id, timezone string) (*model.Account, error)
+	UpdateShareKakaoProfile(ctx context.Context, id string, share bool) (*model.Account, error)
+	UpdateOnboarding(ctx context.Context, id string, onboarding json.RawMessage) (*model.Account, error)
+	// UpdateWebhook sets the webhook URL and secret, dropping a rotated
+	// secret; nil for both turns webhook delivery off.
+	UpdateWebhook(ctx context.Context, id string, url, secret *string) (*model.Account, error)
+	// RotateWebhookSecret replaces the webhook secret, keeping the old one
+	// valid until previousExpiresAt. Returns nil when the account has no
+	// webhook.
+	RotateWebhookSecret(ctx context.Context, id, secret string, previousExpiresAt time.Time) (*model.Account, error)
+	// RecordWebhookFailure counts a failed webhook post. The post that
+	// reaches threshold consecutive failures marks the webhook degraded; while
+	// it is degraded every failure schedules the next probe at probeAt.
+	RecordWebhookFailure(ctx context.Context, id string, threshold int, probeAt time.Time) (*model.Account, error)
+	// ResetWebhookFailures clears the failure count and restores a degraded
+	// webhook.
+	ResetWebhookFailures(ctx context.Context, id string) (*model.Account, error)
+	// ClaimWebhookProbes claims up to limit degraded webhooks whose probe is
+	// due, postponing their next probe to leaseUntil.
+	ClaimWebhookProbes(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.Account, error)
 	Delete(ctx context.Context, id string) error
+	DeletionPreview(ctx context.Context, id string) (*model.AccountDeletionPreview, error)
+	ScheduleDeletion(ctx context.Context, id string, purgeAt time.Time) (*model.Account, error)
+	CancelDeletion(ctx context.Context, id string) (*model.Account, error)
+	DeleteScheduled(ctx context.Context) ([]string, error)
+	SetLegalHold(ctx context.Context, id, reason string) (*model.Account, error)
+	ReleaseLegalHold(ctx context.Context, id string) (*model.Account, error)
+	IsUnderLegalHold(ctx context.Context, id string) (bool, error)
 	Count(ctx context.Context) (int, error)
 	// WithTx returns a new repository that uses the given transaction
 	WithTx(tx *sqlx.Tx) AccountRepository
@@ -35,7 +65,7 @@ type sqlxDB interface {
 }
 
 func NewAccountRepository(db *sqlx.DB) AccountRepository {
-	return &accountRepo{db: db}
+	return &accountRepo{db: withRetry(db)}
 }
 
 func (r *accountRepo) WithTx(tx *sqlx.Tx) AccountRepository {
@@ -59,19 +89,43 @@ func (r *accountRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*m
 	return HandleNotFound(&account, err)
 }
 
-func (r *accountRepo) FindAll(ctx context.Context, limit, offset int) ([]model.Account, error) {
+// accountFilterClause matches the optional AccountFilter fields passed as
+// the first five parameters; empty or NULL values match all rows.
+const accountFilterClause = `
+		WHERE ($1 = '' OR mode::text = $1)
+		AND ($2::timestamptz IS NULL OR created_at >= $2)
+		AND ($3::timestamptz IS NULL OR created_at < $3)
+		AND ($4::boolean IS NULL OR EXISTS (
+			SELECT 1 FROM portal_users u WHERE u.account_id = accounts.id
+		) = $4)
+		AND ($5::boolean IS NULL OR (disabled_at IS NOT NULL) = $5)
+`
+
+func accountFilterArgs(filter model.AccountFilter) []any {
+	return []any{string(filter.Mode), filter.CreatedAfter, filter.CreatedBefore, filter.HasPortalUser, filter.Suspended}
+}
+
+func (r *accountRepo) FindAll(ctx context.Context, filter model.AccountFilter, limit, offset int) ([]model.Account, error) {
 	var accounts []model.Account
 	err := r.db.SelectContext(ctx, &accounts, `
-		SELECT * FROM accounts
+		SELECT * FROM accounts`+accountFilterClause+`
 		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`, limit, offset)
+		LIMIT $6 OFFSET $7
+	`, append(accountFilterArgs(filter), limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
 	return accounts, nil
 }
 
+func (r *accountRepo) CountFiltered(ctx context.Context, filter model.AccountFilter) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM accounts`+accountFilterClause,
+		accountFilterArgs(filter)...)
+	return count, err
+}
+
 func (r *accountRepo) Create(ctx context.Context, params model.CreateAccountParams) (*model.Account, error) {
 	var account model.Account
 	err := r.db.GetContext(ctx, &account, `
@@ -105,6 +159,136 @@ func (r *accountRepo) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+// DeletionPreview counts the rows that deleting the account removes or detaches.
+func (r *accountRepo) DeletionPreview(ctx context.Context, id string) (*model.AccountDeletionPreview, error) {
+	var preview model.AccountDeletionPreview
+	err := r.db.GetContext(ctx, &preview, `
+		SELECT
+			(SELECT COUNT(*) FROM conversation_mappings WHERE account_id = $1) AS conversations,
+			(SELECT COUNT(*) FROM inbound_messages WHERE account_id = $1) AS inbound_messages,
+			(SELECT COUNT(*) FROM outbound_messages WHERE account_id = $1) AS outbound_messages,
+			(SELECT COUNT(*) FROM pairing_codes WHERE account_id = $1) AS pairing_codes,
+			(SELECT COUNT(*) FROM sessions WHERE account_id = $1) AS sessions,
+			(SELECT COUNT(*) FROM portal_users WHERE account_id = $1) AS portal_users,
+			`+accountUnderLegalHold+` AS legal_hold
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	preview.OAuthProviders = []string{}
+	err = r.db.SelectContext(ctx, &preview.OAuthProviders, `
+		SELECT DISTINCT o.provider
+		FROM oauth_accounts o
+		JOIN portal_users u ON u.id = o.user_id
+		WHERE u.account_id = $1
+		ORDER BY o.provider
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// ScheduleDeletion marks the account for purging at purgeAt and suspends it
+// in the meantime. An account that is already disabled stays disabled.
+func (r *accountRepo) ScheduleDeletion(ctx context.Context, id string, purgeAt time.Time) (*model.Account, error) {
+	var account model.Account
+	now := time.Now()
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			deletion_requested_at = $2,
+			deletion_scheduled_at = $3,
+			disabled_at = COALESCE(disabled_at, $2),
+			updated_at = $2
+		WHERE id = $1
+		RETURNING *
+	`, id, now, purgeAt)
+	return HandleNotFound(&account, err)
+}
+
+// CancelDeletion clears a pending deletion and lifts the suspension it
+// caused. Returns nil when no deletion was scheduled.
+func (r *accountRepo) CancelDeletion(ctx context.Context, id string) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			disabled_at = CASE WHEN disabled_at = deletion_requested_at THEN NULL ELSE disabled_at END,
+			deletion_requested_at = NULL,
+			deletion_scheduled_at = NULL,
+			updated_at = $2
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL
+		RETURNING *
+	`, id, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+// DeleteScheduled removes accounts whose scheduled deletion time has passed
+// and returns their IDs. Accounts under legal hold stay scheduled and are
+// purged on the first run after the hold is released.
+func (r *accountRepo) DeleteScheduled(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, `
+		DELETE FROM accounts a
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= NOW()
+		AND a.legal_hold_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM conversation_mappings c
+			WHERE c.account_id = a.id AND c.legal_hold_at IS NOT NULL
+		)
+		RETURNING id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// accountUnderLegalHold is true when the account $1 or one of its
+// conversations is under legal hold. Deleting the account would cascade to
+// the messages of those conversations.
+const accountUnderLegalHold = `(
+	EXISTS (SELECT 1 FROM accounts WHERE id = $1 AND legal_hold_at IS NOT NULL)
+	OR EXISTS (SELECT 1 FROM conversation_mappings WHERE account_id = $1 AND legal_hold_at IS NOT NULL)
+)`
+
+// SetLegalHold places the account under legal hold, replacing the reason of
+// an existing hold but keeping its start time.
+func (r *accountRepo) SetLegalHold(ctx context.Context, id, reason string) (*model.Account, error) {
+	var account model.Account
+	now := time.Now()
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			legal_hold_at = COALESCE(legal_hold_at, $2),
+			legal_hold_reason = $3,
+			updated_at = $2
+		WHERE id = $1
+		RETURNING *
+	`, id, now, reason)
+	return HandleNotFound(&account, err)
+}
+
+// ReleaseLegalHold lifts the account's hold. Returns nil when the account is
+// not held.
+func (r *accountRepo) ReleaseLegalHold(ctx context.Context, id string) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			legal_hold_at = NULL,
+			legal_hold_reason = NULL,
+			updated_at = $2
+		WHERE id = $1 AND legal_hold_at IS NOT NULL
+		RETURNING *
+	`, id, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+func (r *accountRepo) IsUnderLegalHold(ctx context.Context, id string) (bool, error) {
+	var held bool
+	err := r.db.GetContext(ctx, &held, `SELECT `+accountUnderLegalHold, id)
+	return held, err
+}
+
 func (r *accountRepo) Count(ctx context.Context) (int, error) {
 	var count int
 	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM accounts`)
@@ -122,3 +306,138 @@ func (r *accountRepo) UpdateToken(ctx context.Context, id, tokenHash string) (*m
 	`, id, tokenHash, time.Now())
 	return HandleNotFound(&account, err)
 }
+
+// UpdateKakaoChannelID sets the account's Kakao channel; nil clears it.
+func (r *accountRepo) UpdateKakaoChannelID(ctx context.Context, id string, channelID *string) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			kakao_channel_id = $2,
+			updated_at = $3
+		WHERE id = $1
+		RETURNING *
+	`, id, channelID, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+// UpdateTimezone sets the IANA zone name used for the account's day boundaries.
+func (r *accountRepo) UpdateTimezone(ctx context.Context, id, timezone string) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			timezone = $2,
+			updated_at = $3
+		WHERE id = $1
+		RETURNING *
+	`, id, timezone, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+// UpdateShareKakaoProfile sets whether plugins receive the Kakao user profile.
+func (r *accountRepo) UpdateShareKakaoProfile(ctx context.Context, id string, share bool) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			share_kakao_profile = $2,
+			updated_at = $3
+		WHERE id = $1
+		RETURNING *
+	`, id, share, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+// UpdateOnboarding stores the account's onboarding settings.
+func (r *accountRepo) UpdateOnboarding(ctx context.Context, id string, onboarding json.RawMessage) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			onboarding = $2,
+			updated_at = $3
+		WHERE id = $1
+		RETURNING *
+	`, id, onboarding, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+func (r *accountRepo) UpdateWebhook(ctx context.Context, id string, url, secret *string) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			webhook_url = $2,
+			webhook_secret = $3,
+			webhook_previous_secret = NULL,
+			webhook_previous_secret_expires_at = NULL,
+			webhook_failures = 0,
+			webhook_degraded_at = NULL,
+			webhook_probe_at = NULL,
+			updated_at = $4
+		WHERE id = $1
+		RETURNING *
+	`, id, url, secret, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+func (r *accountRepo) RotateWebhookSecret(ctx context.Context, id, secret string, previousExpiresAt time.Time) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			webhook_previous_secret = webhook_secret,
+			webhook_previous_secret_expires_at = $3,
+			webhook_secret = $2,
+			updated_at = $4
+		WHERE id = $1 AND webhook_url IS NOT NULL
+		RETURNING *
+	`, id, secret, previousExpiresAt, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+func (r *accountRepo) RecordWebhookFailure(ctx context.Context, id string, threshold int, probeAt time.Time) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			webhook_failures = webhook_failures + 1,
+			webhook_degraded_at = CASE
+				WHEN webhook_degraded_at IS NULL AND webhook_failures + 1 >= $2 THEN $4
+				ELSE webhook_degraded_at
+			END,
+			webhook_probe_at = CASE
+				WHEN webhook_degraded_at IS NOT NULL OR webhook_failures + 1 >= $2 THEN $3
+			END
+		WHERE id = $1 AND webhook_url IS NOT NULL
+		RETURNING *
+	`, id, threshold, probeAt, time.Now())
+	return HandleNotFound(&account, err)
+}
+
+func (r *accountRepo) ResetWebhookFailures(ctx context.Context, id string) (*model.Account, error) {
+	var account model.Account
+	err := r.db.GetContext(ctx, &account, `
+		UPDATE accounts SET
+			webhook_failures = 0,
+			webhook_degraded_at = NULL,
+			webhook_probe_at = NULL
+		WHERE id = $1
+		RETURNING *
+	`, id)
+	return HandleNotFound(&account, err)
+}
+
+func (r *accountRepo) ClaimWebhookProbes(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.Account, error) {
+	var accounts []model.Account
+	err := r.db.SelectContext(ctx, &accounts, `
+		UPDATE accounts SET
+			webhook_probe_at = $2
+		WHERE id IN (
+			SELECT id FROM accounts
+			WHERE webhook_degraded_at IS NOT NULL
+			AND webhook_url IS NOT NULL
+			AND disabled_at IS NULL
+			AND webhook_probe_at <= $1
+			ORDER BY webhook_probe_at ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, now, leaseUntil, limit)
+	return accounts, err
+}
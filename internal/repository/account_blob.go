@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/blob"
+)
+
+// blobAccountRepo deletes the offloaded payloads of an account together with
+// the account. The inbound rows referencing them go with the account by
+// cascade, so nothing else would ever remove the blobs.
+type blobAccountRepo struct {
+	AccountRepository
+	store blob.Store
+}
+
+// NewBlobAccountRepository wraps repo so that deleting or purging an account
+// also deletes its blobs in store.
+func NewBlobAccountRepository(repo AccountRepository, store blob.Store) AccountRepository {
+	return &blobAccountRepo{AccountRepository: repo, store: store}
+}
+
+// inboundBlobPrefix is the prefix of every blob inboundBlobKey creates for
+// the account.
+func inboundBlobPrefix(accountID string) string {
+	return "inbound/" + accountID + "/"
+}
+
+// deleteBlobs removes the account's blobs after its rows are gone. A failure
+// is logged for manual cleanup rather than failing a deletion that already
+// happened.
+func (r *blobAccountRepo) deleteBlobs(ctx context.Context, accountID string) {
+	prefix := inboundBlobPrefix(accountID)
+	if err := r.store.DeletePrefix(ctx, prefix); err != nil {
+		log.Error().Err(err).Str("accountId", accountID).Str("blobPrefix", prefix).Msg("failed to delete offloaded payloads of deleted account")
+	}
+}
+
+func (r *blobAccountRepo) Delete(ctx context.Context, id string) error {
+	if err := r.AccountRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.deleteBlobs(ctx, id)
+	return nil
+}
+
+func (r *blobAccountRepo) DeleteScheduled(ctx context.Context) ([]string, error) {
+	ids, err := r.AccountRepository.DeleteScheduled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		r.deleteBlobs(ctx, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/blob"
+)
+
+// deletingAccountRepo reports the accounts it is asked to delete as gone
+// and purges the scheduled ones.
+type deletingAccountRepo struct {
+	AccountRepository
+	deleted   []string
+	scheduled []string
+}
+
+func (m *deletingAccountRepo) Delete(ctx context.Context, id string) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *deletingAccountRepo) DeleteScheduled(ctx context.Context) ([]string, error) {
+	ids := m.scheduled
+	m.scheduled = nil
+	return ids, nil
+}
+
+func TestBlobAccountRepository(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	keys := map[string]string{}
+	for _, accountID := range []string{"acc-1", "acc-2", "acc-3"} {
+		keys[accountID] = inboundBlobKey(accountID, time.Now())
+		require.NoError(t, store.Put(ctx, keys[accountID], []byte(`{}`)))
+	}
+
+	inner := &deletingAccountRepo{scheduled: []string{"acc-2"}}
+	repo := NewBlobAccountRepository(inner, store)
+
+	require.NoError(t, repo.Delete(ctx, "acc-1"))
+	assert.Equal(t, []string{"acc-1"}, inner.deleted)
+	ids, err := repo.DeleteScheduled(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acc-2"}, ids)
+
+	for _, accountID := range []string{"acc-1", "acc-2"} {
+		_, err := store.Get(ctx, keys[accountID])
+		assert.ErrorIs(t, err, blob.ErrNotFound, accountID)
+	}
+	_, err = store.Get(ctx, keys["acc-3"])
+	assert.NoError(t, err, "other accounts keep their blobs")
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/audit"
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type AccountSettingsRepository interface {
+	// Find returns the account's stored settings by key; settings without a
+	// stored value are missing.
+	Find(ctx context.Context, accountID string) (map[string]json.RawMessage, error)
+	// Update stores the values in set and removes the keys in unset, and
+	// records the changed settings in the change history. It returns the
+	// settings stored before and after.
+	Update(ctx context.Context, accountID string, set map[string]json.RawMessage, unset []string) (prev, stored map[string]json.RawMessage, err error)
+}
+
+type accountSettingsRepo struct {
+	db database.Querier
+}
+
+func NewAccountSettingsRepository(db *sqlx.DB) AccountSettingsRepository {
+	return &accountSettingsRepo{db: withRetry(db)}
+}
+
+func (r *accountSettingsRepo) Find(ctx context.Context, accountID string) (map[string]json.RawMessage, error) {
+	var rows []json.RawMessage
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT settings FROM account_settings WHERE account_id = $1
+	`, accountID)
+	if err != nil || len(rows) == 0 {
+		return map[string]json.RawMessage{}, err
+	}
+	return decodeSettings(rows[0])
+}
+
+func (r *accountSettingsRepo) Update(
+	ctx context.Context,
+	accountID string,
+	set map[string]json.RawMessage,
+	unset []string,
+) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	setJSON, err := json.Marshal(set)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unset == nil {
+		unset = []string{}
+	}
+
+	// The row is locked so the previous settings, and the changes recorded
+	// against them, are the ones this update replaced
+	var row struct {
+		Prev   json.RawMessage `db:"prev"`
+		Stored json.RawMessage `db:"stored"`
+	}
+	err = r.db.GetContext(ctx, &row, `
+		WITH prev AS (
+			SELECT settings FROM account_settings WHERE account_id = $1 FOR UPDATE
+		), upsert AS (
+			INSERT INTO account_settings (account_id, settings)
+			VALUES ($1, ('{}'::jsonb || $2::jsonb) - $3::text[])
+			ON CONFLICT (account_id) DO UPDATE SET
+				settings = (account_settings.settings || $2::jsonb) - $3::text[],
+				updated_at = NOW()
+			RETURNING settings
+		), old AS (
+			SELECT COALESCE((SELECT settings FROM prev), '{}'::jsonb) AS settings
+		), history AS (
+			INSERT INTO change_history (account_id, entity_type, entity_key, old_value, new_value, actor)
+			SELECT $1, $4, k.key, old.settings -> k.key, upsert.settings -> k.key, $5
+			FROM old, upsert,
+				(SELECT jsonb_object_keys($2::jsonb) AS key UNION SELECT unnest($3::text[])) AS k
+			WHERE (old.settings -> k.key) IS DISTINCT FROM (upsert.settings -> k.key)
+		)
+		SELECT
+			old.settings AS prev,
+			upsert.settings AS stored
+		FROM old, upsert
+	`, accountID, string(setJSON), unset, model.ChangeEntityAccountSetting, audit.Actor(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prev, err := decodeSettings(row.Prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err := decodeSettings(row.Stored)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prev, stored, nil
+}
+
+func decodeSettings(data json.RawMessage) (map[string]json.RawMessage, error) {
+	settings := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRepository_DeleteScheduled(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewAccountRepository(db.DB)
+	ctx := context.Background()
+
+	due := createTestAccount(t, db)
+	notDue := createTestAccount(t, db)
+	held := createTestAccount(t, db)
+
+	_, err := repo.ScheduleDeletion(ctx, due, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	scheduled, err := repo.ScheduleDeletion(ctx, notDue, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.NotNil(t, scheduled.DisabledAt, "the account is suspended until it is purged")
+	_, err = repo.ScheduleDeletion(ctx, held, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = repo.SetLegalHold(ctx, held, "litigation")
+	require.NoError(t, err)
+
+	ids, err := repo.DeleteScheduled(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, ids, due)
+	assert.NotContains(t, ids, notDue)
+	assert.NotContains(t, ids, held)
+
+	t.Run("held account is purged once released", func(t *testing.T) {
+		_, err := repo.ReleaseLegalHold(ctx, held)
+		require.NoError(t, err)
+
+		ids, err := repo.DeleteScheduled(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, ids, held)
+	})
+
+	t.Run("cancelled deletion lifts the suspension", func(t *testing.T) {
+		account, err := repo.CancelDeletion(ctx, notDue)
+		require.NoError(t, err)
+		require.NotNil(t, account)
+		assert.Nil(t, account.DisabledAt)
+		assert.Nil(t, account.DeletionScheduledAt)
+	})
+}
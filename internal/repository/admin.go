@@ -5,6 +5,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/openclaw/relay-server-go/internal/database"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
@@ -14,14 +15,18 @@ type AdminSessionRepository interface {
 	Delete(ctx context.Context, id string) error
 	DeleteByTokenHash(ctx context.Context, tokenHash string) error
 	DeleteExpired(ctx context.Context) (int64, error)
+	DeleteAll(ctx context.Context) (int64, error)
+	ListActive(ctx context.Context) ([]model.AdminSession, error)
+	Touch(ctx context.Context, id string) error
+	MarkAuthenticated(ctx context.Context, id string) error
 }
 
 type adminSessionRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewAdminSessionRepository(db *sqlx.DB) AdminSessionRepository {
-	return &adminSessionRepo{db: db}
+	return &adminSessionRepo{db: withRetry(db)}
 }
 
 func (r *adminSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.AdminSession, error) {
@@ -36,10 +41,10 @@ func (r *adminSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string
 func (r *adminSessionRepo) Create(ctx context.Context, params model.CreateAdminSessionParams) (*model.AdminSession, error) {
 	var session model.AdminSession
 	err := r.db.GetContext(ctx, &session, `
-		INSERT INTO admin_sessions (token_hash, expires_at)
-		VALUES ($1, $2)
+		INSERT INTO admin_sessions (token_hash, expires_at, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4)
 		RETURNING *
-	`, params.TokenHash, params.ExpiresAt)
+	`, params.TokenHash, params.ExpiresAt, params.IPAddress, params.UserAgent)
 	if err != nil {
 		return nil, err
 	}
@@ -63,3 +68,34 @@ func (r *adminSessionRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	}
 	return result.RowsAffected()
 }
+
+func (r *adminSessionRepo) DeleteAll(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM admin_sessions`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *adminSessionRepo) ListActive(ctx context.Context) ([]model.AdminSession, error) {
+	var sessions []model.AdminSession
+	err := r.db.SelectContext(ctx, &sessions, `
+		SELECT * FROM admin_sessions
+		WHERE expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`)
+	return sessions, err
+}
+
+// Touch records activity on the session, extending its idle expiry.
+func (r *adminSessionRepo) Touch(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE admin_sessions SET last_seen_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// MarkAuthenticated records that the admin re-entered the password in the
+// session, for step-up checks on destructive actions.
+func (r *adminSessionRepo) MarkAuthenticated(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE admin_sessions SET authenticated_at = NOW() WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type AdminNotificationRepository interface {
+	// Raise records n, or updates the unread notification with its name and
+	// subject: the message, details and last occurrence are replaced, the
+	// occurrences counted and the notification no longer resolved.
+	Raise(ctx context.Context, n *model.AdminNotification) error
+	// Resolve marks the unread notification with the name and subject
+	// resolved.
+	Resolve(ctx context.Context, name, subject string) error
+	// List returns the matching notifications, most recent first, how many
+	// match and how many are unread in total.
+	List(ctx context.Context, filter model.AdminNotificationFilter, limit, offset int) (items []model.AdminNotification, total, unread int, err error)
+	// MarkRead reports whether the notification exists.
+	MarkRead(ctx context.Context, id string) (bool, error)
+	MarkAllRead(ctx context.Context) (int64, error)
+	// DeleteExpired deletes notifications read more than 90 days ago.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type adminNotificationRepo struct {
+	db database.Querier
+}
+
+func NewAdminNotificationRepository(db *sqlx.DB) AdminNotificationRepository {
+	return &adminNotificationRepo{db: withRetry(db)}
+}
+
+func (r *adminNotificationRepo) Raise(ctx context.Context, n *model.AdminNotification) error {
+	return r.db.GetContext(ctx, n, `
+		INSERT INTO admin_notifications (kind, name, subject, message, details, last_occurred_at)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6)
+		ON CONFLICT (name, subject) WHERE read_at IS NULL DO UPDATE SET
+			kind = EXCLUDED.kind,
+			message = EXCLUDED.message,
+			details = EXCLUDED.details,
+			occurrences = admin_notifications.occurrences + 1,
+			last_occurred_at = EXCLUDED.last_occurred_at,
+			resolved_at = NULL
+		RETURNING *
+	`, n.Kind, n.Name, n.Subject, n.Message, n.Details, n.LastOccurredAt)
+}
+
+func (r *adminNotificationRepo) Resolve(ctx context.Context, name, subject string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE admin_notifications SET resolved_at = NOW()
+		WHERE name = $1 AND subject = $2 AND read_at IS NULL AND resolved_at IS NULL
+	`, name, subject)
+	return err
+}
+
+func (r *adminNotificationRepo) List(ctx context.Context, filter model.AdminNotificationFilter, limit, offset int) ([]model.AdminNotification, int, int, error) {
+	var args []any
+	conditions := []string{"TRUE"}
+	if filter.Kind != "" {
+		args = append(args, filter.Kind)
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	if filter.UnreadOnly {
+		conditions = append(conditions, "read_at IS NULL")
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var counts struct {
+		Total  int `db:"total"`
+		Unread int `db:"unread"`
+	}
+	err := r.db.GetContext(ctx, &counts, fmt.Sprintf(`
+		SELECT
+			COUNT(*) FILTER (WHERE %s) AS total,
+			COUNT(*) FILTER (WHERE read_at IS NULL) AS unread
+		FROM admin_notifications
+	`, where), args...)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT * FROM admin_notifications
+		WHERE %s
+		ORDER BY last_occurred_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	var items []model.AdminNotification
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, 0, 0, err
+	}
+	return items, counts.Total, counts.Unread, nil
+}
+
+func (r *adminNotificationRepo) MarkRead(ctx context.Context, id string) (bool, error) {
+	var found bool
+	err := r.db.GetContext(ctx, &found, `
+		WITH target AS (
+			SELECT id FROM admin_notifications WHERE id = $1
+		), updated AS (
+			UPDATE admin_notifications SET read_at = NOW()
+			WHERE id IN (SELECT id FROM target) AND read_at IS NULL
+		)
+		SELECT EXISTS(SELECT 1 FROM target)
+	`, id)
+	return found, err
+}
+
+func (r *adminNotificationRepo) MarkAllRead(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE admin_notifications SET read_at = NOW() WHERE read_at IS NULL`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *adminNotificationRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM admin_notifications WHERE read_at < NOW() - INTERVAL '90 days'
+	`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
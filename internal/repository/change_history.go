@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// Changes are recorded by the statements that make them, in the same
+// statement, so an entry exists exactly when its change was committed.
+
+// conversationStateJSON is the recorded value (model.ConversationStateValue)
+// of the conversation_mappings columns of alias.
+func conversationStateJSON(alias string) string {
+	return fmt.Sprintf("jsonb_build_object('state', %[1]s.state, 'accountId', %[1]s.account_id)", alias)
+}
+
+type ChangeHistoryRepository interface {
+	// List returns the matching entries, newest first, and how many match.
+	List(ctx context.Context, filter model.ChangeHistoryFilter, limit, offset int) ([]model.ChangeHistoryEntry, int, error)
+}
+
+type changeHistoryRepo struct {
+	db database.Querier
+}
+
+func NewChangeHistoryRepository(db *sqlx.DB) ChangeHistoryRepository {
+	return &changeHistoryRepo{db: withRetry(db)}
+}
+
+func (r *changeHistoryRepo) List(ctx context.Context, filter model.ChangeHistoryFilter, limit, offset int) ([]model.ChangeHistoryEntry, int, error) {
+	var args []any
+	conditions := []string{"TRUE"}
+	if filter.AccountID != "" {
+		args = append(args, filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf("account_id = $%d", len(args)))
+	}
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+	if filter.EntityKey != "" {
+		args = append(args, filter.EntityKey)
+		conditions = append(conditions, fmt.Sprintf("entity_key = $%d", len(args)))
+	}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("changed_at >= $%d", len(args)))
+	}
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conditions = append(conditions, fmt.Sprintf("changed_at < $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM change_history WHERE "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT * FROM change_history
+		WHERE %s
+		ORDER BY changed_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	var entries []model.ChangeHistoryEntry
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/openclaw/relay-server-go/internal/audit"
+	"github.com/openclaw/relay-server-go/internal/database"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
@@ -13,19 +17,37 @@ type ConversationRepository interface {
 	FindByKey(ctx context.Context, key string) (*model.ConversationMapping, error)
 	FindByAccountID(ctx context.Context, accountID string) ([]model.ConversationMapping, error)
 	FindPairedByAccountID(ctx context.Context, accountID string) ([]model.ConversationMapping, error)
+	SearchPairedByAccountID(ctx context.Context, accountID, query string, page model.KeysetPage) ([]model.ConversationMapping, error)
+	ListByAccount(ctx context.Context, params model.ListConversationsParams) ([]model.ConversationMapping, int, error)
 	Upsert(ctx context.Context, params model.UpsertConversationParams) (*model.ConversationMapping, error)
 	UpdateState(ctx context.Context, key string, state model.PairingState, accountID *string) error
+	TransitionState(ctx context.Context, key string, from, to model.PairingState) (bool, error)
 	UpdateCallback(ctx context.Context, key string, callbackURL string, expiresAt time.Time) error
+	UpdateDetails(ctx context.Context, key string, nickname, notes *string) error
+	UpdateKakaoProfile(ctx context.Context, key string, profile model.KakaoProfile) error
+	UpdateContentConsent(ctx context.Context, key string, consent model.ContentConsent) error
+	FindHealthByKeys(ctx context.Context, keys []string, failuresSince time.Time) ([]model.ConnectionHealth, error)
+	SetLegalHold(ctx context.Context, id, reason string) (*model.ConversationMapping, error)
+	ReleaseLegalHold(ctx context.Context, id string) (*model.ConversationMapping, error)
+	IsUnderLegalHold(ctx context.Context, id string) (bool, error)
+	PauseDelivery(ctx context.Context, key string, autoReply *string) (*model.ConversationMapping, error)
+	ResumeDelivery(ctx context.Context, key string) (*model.ConversationMapping, error)
 	Delete(ctx context.Context, id string) error
 	CountByState(ctx context.Context, state model.PairingState) (int, error)
+	// WithTx returns a new repository that uses the given transaction
+	WithTx(tx *sqlx.Tx) ConversationRepository
 }
 
 type conversationRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewConversationRepository(db *sqlx.DB) ConversationRepository {
-	return &conversationRepo{db: db}
+	return &conversationRepo{db: withRetry(db)}
+}
+
+func (r *conversationRepo) WithTx(tx *sqlx.Tx) ConversationRepository {
+	return &conversationRepo{db: tx}
 }
 
 func (r *conversationRepo) FindByKey(ctx context.Context, key string) (*model.ConversationMapping, error) {
@@ -56,6 +78,100 @@ func (r *conversationRepo) FindPairedByAccountID(ctx context.Context, accountID
 	return convs, err
 }
 
+// SearchPairedByAccountID matches query case-insensitively against the
+// nickname, notes and conversation key; an empty query matches every paired
+// conversation. Results are most recently paired first, keyed on
+// (paired_at, id) for page cursors.
+func (r *conversationRepo) SearchPairedByAccountID(ctx context.Context, accountID, query string, page model.KeysetPage) ([]model.ConversationMapping, error) {
+	args := []any{accountID}
+	conditions := []string{"account_id = $1", "state = 'paired'"}
+	if query != "" {
+		args = append(args, containsPattern(query))
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf("(nickname ILIKE $%d OR notes ILIKE $%d OR conversation_key ILIKE $%d)", n, n, n))
+	}
+	if page.After != nil {
+		args = append(args, page.After.At, page.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(COALESCE(paired_at, first_seen_at), id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	limit := ""
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		limit = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	var convs []model.ConversationMapping
+	err := r.db.SelectContext(ctx, &convs, fmt.Sprintf(`
+		SELECT * FROM conversation_mappings
+		WHERE %s
+		ORDER BY COALESCE(paired_at, first_seen_at) DESC, id DESC
+		%s
+	`, strings.Join(conditions, " AND "), limit), args...)
+	return convs, err
+}
+
+// conversationSortColumns whitelists ORDER BY columns for ListByAccount
+var conversationSortColumns = map[model.ConversationSort]string{
+	model.ConversationSortLastSeenAt: "last_seen_at",
+	model.ConversationSortPairedAt:   "paired_at",
+}
+
+// ListByAccount returns one page of the account's conversations along with the
+// total number matching the filters.
+func (r *conversationRepo) ListByAccount(ctx context.Context, params model.ListConversationsParams) ([]model.ConversationMapping, int, error) {
+	args := []any{params.AccountID}
+	conditions := []string{"account_id = $1"}
+	if len(params.States) > 0 {
+		states := make([]string, len(params.States))
+		for i, state := range params.States {
+			states[i] = string(state)
+		}
+		args = append(args, states)
+		conditions = append(conditions, fmt.Sprintf("state = ANY($%d::text[])", len(args)))
+	}
+	if params.Label != "" {
+		args = append(args, params.Label)
+		conditions = append(conditions, fmt.Sprintf("LOWER(nickname) = LOWER($%d)", len(args)))
+	}
+	if params.Query != "" {
+		args = append(args, containsPattern(params.Query))
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf("(nickname ILIKE $%d OR notes ILIKE $%d OR conversation_key ILIKE $%d)", n, n, n))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM conversation_mappings WHERE "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := conversationSortColumns[params.Sort]
+	if !ok {
+		column = conversationSortColumns[model.ConversationSortPairedAt]
+	}
+	direction := "DESC"
+	if params.Ascending {
+		direction = "ASC"
+	}
+
+	args = append(args, params.Limit, params.Offset)
+	query := fmt.Sprintf(`
+		SELECT * FROM conversation_mappings
+		WHERE %s
+		ORDER BY %s %s NULLS LAST, id
+		LIMIT $%d OFFSET $%d
+	`, where, column, direction, len(args)-1, len(args))
+
+	var convs []model.ConversationMapping
+	if err := r.db.SelectContext(ctx, &convs, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return convs, total, nil
+}
+
+// Upsert creates the conversation or refreshes an existing one in a single
+// statement, so concurrent first webhooks from a new user converge on one row
+// without a separate read.
 func (r *conversationRepo) Upsert(ctx context.Context, params model.UpsertConversationParams) (*model.ConversationMapping, error) {
 	var conv model.ConversationMapping
 	err := r.db.GetContext(ctx, &conv, `
@@ -63,8 +179,13 @@ func (r *conversationRepo) Upsert(ctx context.Context, params model.UpsertConver
 			(conversation_key, kakao_channel_id, plusfriend_user_key, last_callback_url, last_callback_expires_at)
 		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (conversation_key) DO UPDATE SET
-			last_callback_url = EXCLUDED.last_callback_url,
-			last_callback_expires_at = EXCLUDED.last_callback_expires_at,
+			-- A webhook without a callback keeps the stored one, which may
+			-- still be valid for replies.
+			last_callback_url = COALESCE(EXCLUDED.last_callback_url, conversation_mappings.last_callback_url),
+			last_callback_expires_at = CASE
+				WHEN EXCLUDED.last_callback_url IS NULL THEN conversation_mappings.last_callback_expires_at
+				ELSE EXCLUDED.last_callback_expires_at
+			END,
 			last_seen_at = NOW()
 		RETURNING *
 	`, params.ConversationKey, params.KakaoChannelID, params.PlusfriendUserKey,
@@ -82,15 +203,53 @@ func (r *conversationRepo) UpdateState(ctx context.Context, key string, state mo
 	}
 
 	_, err := r.db.ExecContext(ctx, `
-		UPDATE conversation_mappings SET
-			state = $2,
-			account_id = $3,
-			paired_at = COALESCE($4, paired_at)
-		WHERE conversation_key = $1
-	`, key, state, accountID, pairedAt)
+		WITH prev AS (
+			SELECT conversation_key, state, account_id FROM conversation_mappings
+			WHERE conversation_key = $1
+			FOR UPDATE
+		), updated AS (
+			UPDATE conversation_mappings SET
+				state = $2,
+				account_id = $3,
+				paired_at = COALESCE($4, paired_at)
+			WHERE conversation_key = $1
+			RETURNING conversation_key, state, account_id
+		)
+		INSERT INTO change_history (account_id, entity_type, entity_key, old_value, new_value, actor)
+		SELECT COALESCE(u.account_id, p.account_id), $5, u.conversation_key,
+			`+conversationStateJSON("p")+`, `+conversationStateJSON("u")+`, $6
+		FROM updated u JOIN prev p USING (conversation_key)
+		WHERE (p.state, p.account_id) IS DISTINCT FROM (u.state, u.account_id)
+	`, key, state, accountID, pairedAt, model.ChangeEntityConversationState, audit.Actor(ctx))
 	return err
 }
 
+// TransitionState moves the conversation from one state to another, keeping
+// its account and paired_at. It reports false when the conversation was not
+// in the from state.
+func (r *conversationRepo) TransitionState(ctx context.Context, key string, from, to model.PairingState) (bool, error) {
+	var transitioned bool
+	err := r.db.GetContext(ctx, &transitioned, `
+		WITH prev AS (
+			SELECT conversation_key, state, account_id FROM conversation_mappings
+			WHERE conversation_key = $1 AND state = $2
+			FOR UPDATE
+		), updated AS (
+			UPDATE conversation_mappings c SET state = $3
+			FROM prev
+			WHERE c.conversation_key = prev.conversation_key AND c.state = $2
+			RETURNING c.conversation_key, c.state, c.account_id
+		), history AS (
+			INSERT INTO change_history (account_id, entity_type, entity_key, old_value, new_value, actor)
+			SELECT u.account_id, $4, u.conversation_key,
+				`+conversationStateJSON("p")+`, `+conversationStateJSON("u")+`, $5
+			FROM updated u JOIN prev p USING (conversation_key)
+		)
+		SELECT EXISTS (SELECT 1 FROM updated)
+	`, key, from, to, model.ChangeEntityConversationState, audit.Actor(ctx))
+	return transitioned, err
+}
+
 func (r *conversationRepo) UpdateCallback(ctx context.Context, key string, callbackURL string, expiresAt time.Time) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE conversation_mappings SET
@@ -102,8 +261,152 @@ func (r *conversationRepo) UpdateCallback(ctx context.Context, key string, callb
 	return err
 }
 
+// UpdateDetails sets the user-assigned nickname and notes; nil clears a field.
+func (r *conversationRepo) UpdateDetails(ctx context.Context, key string, nickname, notes *string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE conversation_mappings SET
+			nickname = $2,
+			notes = $3
+		WHERE conversation_key = $1
+	`, key, nickname, notes)
+	return err
+}
+
+// UpdateKakaoProfile stores the profile fetched from the Kakao API.
+func (r *conversationRepo) UpdateKakaoProfile(ctx context.Context, key string, profile model.KakaoProfile) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE conversation_mappings SET
+			kakao_nickname = $2,
+			kakao_profile_image_url = $3,
+			kakao_profile_fetched_at = $4
+		WHERE conversation_key = $1
+	`, key, profile.Nickname, profile.ProfileImageURL, profile.FetchedAt)
+	return err
+}
+
+// UpdateContentConsent records the user's answer to the consent prompt.
+func (r *conversationRepo) UpdateContentConsent(ctx context.Context, key string, consent model.ContentConsent) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE conversation_mappings SET
+			content_consent = $2,
+			content_consent_at = $3
+		WHERE conversation_key = $1
+	`, key, consent, time.Now())
+	return err
+}
+
+// FindHealthByKeys aggregates message activity for all keys in one query.
+// Every key gets a row, zero-valued when it has no messages.
+func (r *conversationRepo) FindHealthByKeys(ctx context.Context, keys []string, failuresSince time.Time) ([]model.ConnectionHealth, error) {
+	var health []model.ConnectionHealth
+	err := r.db.SelectContext(ctx, &health, `
+		WITH inbound AS (
+			SELECT conversation_key,
+				MAX(created_at) AS last_inbound_at,
+				COUNT(*) FILTER (WHERE status = 'queued') AS queued_count
+			FROM inbound_messages
+			WHERE conversation_key = ANY($1)
+			GROUP BY conversation_key
+		), outbound AS (
+			SELECT conversation_key,
+				MAX(created_at) AS last_outbound_at,
+				COUNT(*) FILTER (WHERE status = 'failed' AND created_at >= $2) AS recent_failure_count
+			FROM outbound_messages
+			WHERE conversation_key = ANY($1)
+			GROUP BY conversation_key
+		)
+		SELECT k.key AS conversation_key,
+			i.last_inbound_at,
+			o.last_outbound_at,
+			COALESCE(i.queued_count, 0) AS queued_count,
+			COALESCE(o.recent_failure_count, 0) AS recent_failure_count
+		FROM unnest($1::text[]) AS k(key)
+		LEFT JOIN inbound i ON i.conversation_key = k.key
+		LEFT JOIN outbound o ON o.conversation_key = k.key
+	`, keys, failuresSince)
+	return health, err
+}
+
+// SetLegalHold places the conversation under legal hold, replacing the reason
+// of an existing hold but keeping its start time.
+func (r *conversationRepo) SetLegalHold(ctx context.Context, id, reason string) (*model.ConversationMapping, error) {
+	var conv model.ConversationMapping
+	err := r.db.GetContext(ctx, &conv, `
+		UPDATE conversation_mappings SET
+			legal_hold_at = COALESCE(legal_hold_at, $2),
+			legal_hold_reason = $3
+		WHERE id = $1
+		RETURNING *
+	`, id, time.Now(), reason)
+	return HandleNotFound(&conv, err)
+}
+
+// ReleaseLegalHold lifts the conversation's hold. Returns nil when the
+// conversation is not held.
+func (r *conversationRepo) ReleaseLegalHold(ctx context.Context, id string) (*model.ConversationMapping, error) {
+	var conv model.ConversationMapping
+	err := r.db.GetContext(ctx, &conv, `
+		UPDATE conversation_mappings SET
+			legal_hold_at = NULL,
+			legal_hold_reason = NULL
+		WHERE id = $1 AND legal_hold_at IS NOT NULL
+		RETURNING *
+	`, id)
+	return HandleNotFound(&conv, err)
+}
+
+// IsUnderLegalHold reports whether the conversation, or the account it
+// belongs to, is under legal hold.
+func (r *conversationRepo) IsUnderLegalHold(ctx context.Context, id string) (bool, error) {
+	var held bool
+	err := r.db.GetContext(ctx, &held, `
+		SELECT EXISTS (
+			SELECT 1 FROM conversation_mappings c
+			LEFT JOIN accounts a ON a.id = c.account_id
+			WHERE c.id = $1 AND (c.legal_hold_at IS NOT NULL OR a.legal_hold_at IS NOT NULL)
+		)
+	`, id)
+	return held, err
+}
+
+// PauseDelivery holds back the conversation's messages, replacing the auto
+// reply of an earlier pause but keeping its start.
+func (r *conversationRepo) PauseDelivery(ctx context.Context, key string, autoReply *string) (*model.ConversationMapping, error) {
+	var conv model.ConversationMapping
+	err := r.db.GetContext(ctx, &conv, `
+		UPDATE conversation_mappings SET
+			delivery_paused_at = COALESCE(delivery_paused_at, $2),
+			pause_auto_reply = $3
+		WHERE conversation_key = $1
+		RETURNING *
+	`, key, time.Now(), autoReply)
+	return HandleNotFound(&conv, err)
+}
+
+// ResumeDelivery lifts the conversation's pause. Returns nil when the
+// conversation is not paused.
+func (r *conversationRepo) ResumeDelivery(ctx context.Context, key string) (*model.ConversationMapping, error) {
+	var conv model.ConversationMapping
+	err := r.db.GetContext(ctx, &conv, `
+		UPDATE conversation_mappings SET
+			delivery_paused_at = NULL,
+			pause_auto_reply = NULL
+		WHERE conversation_key = $1 AND delivery_paused_at IS NOT NULL
+		RETURNING *
+	`, key)
+	return HandleNotFound(&conv, err)
+}
+
 func (r *conversationRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM conversation_mappings WHERE id = $1`, id)
+	_, err := r.db.ExecContext(ctx, `
+		WITH deleted AS (
+			DELETE FROM conversation_mappings WHERE id = $1
+			RETURNING conversation_key, state, account_id
+		)
+		INSERT INTO change_history (account_id, entity_type, entity_key, old_value, new_value, actor)
+		SELECT d.account_id, $2, d.conversation_key, `+conversationStateJSON("d")+`, NULL, $3
+		FROM deleted d
+	`, id, model.ChangeEntityConversationState, audit.Actor(ctx))
 	return err
 }
 
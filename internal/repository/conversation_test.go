@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/audit"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationRepository_UpsertConcurrent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewConversationRepository(db.DB)
+	ctx := context.Background()
+	key := fmt.Sprintf("test-channel:race-%d", time.Now().UnixNano())
+	defer db.DB.ExecContext(ctx, `DELETE FROM conversation_mappings WHERE conversation_key = $1`, key)
+
+	const webhooks = 8
+	ids := make([]string, webhooks)
+	errs := make([]error, webhooks)
+	var wg sync.WaitGroup
+	for i := range webhooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conv, err := repo.Upsert(ctx, model.UpsertConversationParams{
+				ConversationKey:   key,
+				KakaoChannelID:    "test-channel",
+				PlusfriendUserKey: key,
+			})
+			errs[i] = err
+			if conv != nil {
+				ids[i] = conv.ID
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range webhooks {
+		require.NoError(t, errs[i])
+		assert.Equal(t, ids[0], ids[i])
+	}
+
+	var rows int
+	require.NoError(t, db.DB.GetContext(ctx, &rows, `SELECT COUNT(*) FROM conversation_mappings WHERE conversation_key = $1`, key))
+	assert.Equal(t, 1, rows)
+}
+
+func TestConversationRepository_UpsertKeepsCallback(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewConversationRepository(db.DB)
+	ctx := context.Background()
+	key := fmt.Sprintf("test-channel:callback-%d", time.Now().UnixNano())
+	defer db.DB.ExecContext(ctx, `DELETE FROM conversation_mappings WHERE conversation_key = $1`, key)
+
+	callbackURL := "https://bot-api.kakao.com/callback/1"
+	expiresAt := time.Now().Add(time.Minute)
+	_, err := repo.Upsert(ctx, model.UpsertConversationParams{
+		ConversationKey:   key,
+		KakaoChannelID:    "test-channel",
+		PlusfriendUserKey: key,
+		CallbackURL:       &callbackURL,
+		CallbackExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	conv, err := repo.Upsert(ctx, model.UpsertConversationParams{
+		ConversationKey:   key,
+		KakaoChannelID:    "test-channel",
+		PlusfriendUserKey: key,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, conv.LastCallbackURL)
+	assert.Equal(t, callbackURL, *conv.LastCallbackURL)
+}
+
+func TestConversationRepository_StateHistory(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewConversationRepository(db.DB)
+	historyRepo := NewChangeHistoryRepository(db.DB)
+	ctx := audit.WithActor(context.Background(), audit.ActorKakaoUser)
+	key := fmt.Sprintf("test-channel:history-%d", time.Now().UnixNano())
+	defer db.DB.ExecContext(ctx, `DELETE FROM change_history WHERE entity_key = $1`, key)
+	defer db.DB.ExecContext(ctx, `DELETE FROM conversation_mappings WHERE conversation_key = $1`, key)
+
+	conv, err := repo.Upsert(ctx, model.UpsertConversationParams{
+		ConversationKey:   key,
+		KakaoChannelID:    "test-channel",
+		PlusfriendUserKey: key,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateState(ctx, key, model.PairingStateBlocked, nil))
+	// Setting the same state again is not a change
+	require.NoError(t, repo.UpdateState(ctx, key, model.PairingStateBlocked, nil))
+	moved, err := repo.TransitionState(ctx, key, model.PairingStatePaired, model.PairingStateArchived)
+	require.NoError(t, err)
+	assert.False(t, moved)
+	require.NoError(t, repo.Delete(audit.WithActor(ctx, audit.ActorAdmin), conv.ID))
+
+	entries, total, err := historyRepo.List(ctx, model.ChangeHistoryFilter{
+		EntityType: model.ChangeEntityConversationState,
+		EntityKey:  key,
+	}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+
+	// Newest first
+	assert.Equal(t, audit.ActorAdmin, entries[0].Actor)
+	assert.JSONEq(t, `{"state":"blocked","accountId":null}`, string(*entries[0].OldValue))
+	assert.Nil(t, entries[0].NewValue)
+	assert.Equal(t, audit.ActorKakaoUser, entries[1].Actor)
+	assert.JSONEq(t, `{"state":"unpaired","accountId":null}`, string(*entries[1].OldValue))
+	assert.JSONEq(t, `{"state":"blocked","accountId":null}`, string(*entries[1].NewValue))
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DeploymentSettingsRepository stores deployment-wide key/value settings.
+type DeploymentSettingsRepository interface {
+	GetAll(ctx context.Context) (map[string]string, error)
+	// GetOrCreate stores value under key unless the key already exists and
+	// returns the stored value, so concurrent instances agree on one value.
+	GetOrCreate(ctx context.Context, key, value string) (string, error)
+	// Set stores all given settings, replacing existing values.
+	Set(ctx context.Context, settings map[string]string) error
+	// Bootstrap claims the one-time bootstrap by writing bootstrappedAt and
+	// stores the given settings in the same statement. Returns false, writing
+	// nothing, when the deployment was already bootstrapped.
+	Bootstrap(ctx context.Context, bootstrappedAt string, settings map[string]string) (bool, error)
+}
+
+type deploymentSettingsRepo struct {
+	db sqlxDB
+}
+
+func NewDeploymentSettingsRepository(db *sqlx.DB) DeploymentSettingsRepository {
+	return &deploymentSettingsRepo{db: withRetry(db)}
+}
+
+func (r *deploymentSettingsRepo) GetAll(ctx context.Context) (map[string]string, error) {
+	var rows []struct {
+		Key   string `db:"key"`
+		Value string `db:"value"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, `SELECT key, value FROM deployment_settings`); err != nil {
+		return nil, err
+	}
+	settings := make(map[string]string, len(rows))
+	for _, row := range rows {
+		settings[row.Key] = row.Value
+	}
+	return settings, nil
+}
+
+func (r *deploymentSettingsRepo) GetOrCreate(ctx context.Context, key, value string) (string, error) {
+	var stored string
+	err := r.db.GetContext(ctx, &stored, `
+		WITH ins AS (
+			INSERT INTO deployment_settings (key, value)
+			VALUES ($1, $2)
+			ON CONFLICT (key) DO NOTHING
+			RETURNING value
+		)
+		SELECT value FROM ins
+		UNION ALL
+		SELECT value FROM deployment_settings WHERE key = $1
+		LIMIT 1
+	`, key, value)
+	return stored, err
+}
+
+func (r *deploymentSettingsRepo) Set(ctx context.Context, settings map[string]string) error {
+	keys, values := splitSettings(settings)
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO deployment_settings (key, value)
+		SELECT k, v FROM unnest($1::text[], $2::text[]) AS t(k, v)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+	`, keys, values)
+	return err
+}
+
+func (r *deploymentSettingsRepo) Bootstrap(ctx context.Context, bootstrappedAt string, settings map[string]string) (bool, error) {
+	keys, values := splitSettings(settings)
+
+	var claimed bool
+	err := r.db.GetContext(ctx, &claimed, `
+		WITH claim AS (
+			INSERT INTO deployment_settings (key, value)
+			VALUES ('bootstrapped_at', $1)
+			ON CONFLICT (key) DO NOTHING
+			RETURNING key
+		), stored AS (
+			INSERT INTO deployment_settings (key, value)
+			SELECT k, v FROM unnest($2::text[], $3::text[]) AS t(k, v)
+			WHERE EXISTS (SELECT 1 FROM claim)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+		)
+		SELECT EXISTS (SELECT 1 FROM claim)
+	`, bootstrappedAt, keys, values)
+	return claimed, err
+}
+
+func splitSettings(settings map[string]string) (keys, values []string) {
+	keys = make([]string, 0, len(settings))
+	values = make([]string, 0, len(settings))
+	for k, v := range settings {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return keys, values
+}
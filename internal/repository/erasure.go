@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// ErasureRepository removes everything stored about a Kakao user. The relay
+// keeps no foreign keys to conversation_mappings, so every table holding a
+// conversation key is handled explicitly. Each erase runs as one statement,
+// so a failure leaves the data untouched and a retry is safe.
+type ErasureRepository interface {
+	CountKakaoUser(ctx context.Context, plusfriendUserKey string) (*model.KakaoUserErasure, error)
+	DeleteKakaoUser(ctx context.Context, plusfriendUserKey string) (*model.KakaoUserErasure, error)
+	AnonymizeKakaoUser(ctx context.Context, plusfriendUserKey string) (*model.KakaoUserErasure, error)
+	// FindBlobKeys lists the blobs holding offloaded payloads of the
+	// messages an erase would remove, so they can be deleted with them.
+	FindBlobKeys(ctx context.Context, plusfriendUserKey string) ([]string, error)
+}
+
+type erasureRepo struct {
+	db database.Querier
+}
+
+func NewErasureRepository(db *sqlx.DB) ErasureRepository {
+	return &erasureRepo{db: withRetry(db)}
+}
+
+// kakaoUserKeysCTEs select the user's conversations. Conversations under
+// legal hold, or paired to an account under hold, are left out of keys, and
+// messages that belong to a held account are kept even when their
+// conversation is erased (erasable_messages).
+const kakaoUserKeysCTEs = `
+	held_accounts AS (
+		SELECT id FROM accounts WHERE legal_hold_at IS NOT NULL
+	),
+	candidates AS (
+		SELECT conversation_key, account_id, legal_hold_at FROM conversation_mappings
+		WHERE plusfriend_user_key = $1
+	),
+	keys AS (
+		SELECT conversation_key, 'erased:' || gen_random_uuid()::text AS erased_key
+		FROM candidates
+		WHERE legal_hold_at IS NULL
+		AND (account_id IS NULL OR account_id NOT IN (SELECT id FROM held_accounts))
+	)
+`
+
+// erasableMessages restricts a message table aliased m to the erased
+// conversations.
+const erasableMessages = `
+	m.conversation_key = k.conversation_key
+	AND m.account_id NOT IN (SELECT id FROM held_accounts)
+`
+
+func (r *erasureRepo) CountKakaoUser(ctx context.Context, plusfriendUserKey string) (*model.KakaoUserErasure, error) {
+	var counts model.KakaoUserErasure
+	err := r.db.GetContext(ctx, &counts, `
+		WITH `+kakaoUserKeysCTEs+`
+		SELECT
+			(SELECT count(*) FROM keys) AS conversations,
+			(SELECT count(DISTINCT c.account_id) FROM candidates c JOIN keys k USING (conversation_key)) AS accounts,
+			(SELECT count(*) FROM inbound_messages m JOIN keys k ON `+erasableMessages+`) AS inbound_messages,
+			(SELECT count(*) FROM outbound_messages m JOIN keys k ON `+erasableMessages+`) AS outbound_messages,
+			(SELECT count(*) FROM portal_access_codes WHERE conversation_key IN (SELECT conversation_key FROM keys)) AS portal_access_codes,
+			(SELECT count(*) FROM experiment_exposures WHERE conversation_key IN (SELECT conversation_key FROM keys)) AS experiment_exposures,
+			(SELECT count(*) FROM sessions WHERE paired_conversation_key IN (SELECT conversation_key FROM keys)) AS sessions,
+			(SELECT count(*) FROM candidates) - (SELECT count(*) FROM keys) AS held_conversations
+	`, plusfriendUserKey)
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+func (r *erasureRepo) FindBlobKeys(ctx context.Context, plusfriendUserKey string) ([]string, error) {
+	var keys []string
+	err := r.db.SelectContext(ctx, &keys, `
+		WITH `+kakaoUserKeysCTEs+`
+		SELECT m.kakao_payload->>'blobKey' FROM inbound_messages m JOIN keys k ON `+erasableMessages+`
+		WHERE m.kakao_payload->>'offloaded' = 'true'
+		AND m.kakao_payload ? 'blobKey'
+	`, plusfriendUserKey)
+	return keys, err
+}
+
+// forgetKakaoUserCTEs are shared by both erase modes: access codes, experiment
+// exposures, the conversations' state history and the conversation mappings
+// themselves are always deleted, and sessions only lose the reference to the
+// conversation.
+const forgetKakaoUserCTEs = `
+	codes AS (
+		DELETE FROM portal_access_codes WHERE conversation_key IN (SELECT conversation_key FROM keys)
+		RETURNING 1
+	),
+	exposures AS (
+		DELETE FROM experiment_exposures WHERE conversation_key IN (SELECT conversation_key FROM keys)
+		RETURNING 1
+	),
+	sess AS (
+		UPDATE sessions SET paired_conversation_key = NULL, updated_at = NOW()
+		WHERE paired_conversation_key IN (SELECT conversation_key FROM keys)
+		RETURNING 1
+	),
+	history AS (
+		DELETE FROM change_history
+		WHERE entity_type = 'conversation_state' AND entity_key IN (SELECT conversation_key FROM keys)
+		RETURNING 1
+	),
+	mappings AS (
+		DELETE FROM conversation_mappings WHERE conversation_key IN (SELECT conversation_key FROM keys)
+		RETURNING account_id
+	)
+	SELECT
+		(SELECT count(*) FROM mappings) AS conversations,
+		(SELECT count(DISTINCT account_id) FROM mappings) AS accounts,
+		(SELECT count(*) FROM inbound) AS inbound_messages,
+		(SELECT count(*) FROM outbound) AS outbound_messages,
+		(SELECT count(*) FROM codes) AS portal_access_codes,
+		(SELECT count(*) FROM exposures) AS experiment_exposures,
+		(SELECT count(*) FROM sess) AS sessions,
+		(SELECT count(*) FROM candidates) - (SELECT count(*) FROM keys) AS held_conversations
+`
+
+func (r *erasureRepo) DeleteKakaoUser(ctx context.Context, plusfriendUserKey string) (*model.KakaoUserErasure, error) {
+	var counts model.KakaoUserErasure
+	err := r.db.GetContext(ctx, &counts, `
+		WITH `+kakaoUserKeysCTEs+`,
+		inbound AS (
+			DELETE FROM inbound_messages m USING keys k
+			WHERE `+erasableMessages+`
+			RETURNING 1
+		),
+		outbound AS (
+			DELETE FROM outbound_messages m USING keys k
+			WHERE `+erasableMessages+`
+			RETURNING 1
+		),
+	`+forgetKakaoUserCTEs, plusfriendUserKey)
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+func (r *erasureRepo) AnonymizeKakaoUser(ctx context.Context, plusfriendUserKey string) (*model.KakaoUserErasure, error) {
+	var counts model.KakaoUserErasure
+	err := r.db.GetContext(ctx, &counts, `
+		WITH `+kakaoUserKeysCTEs+`,
+		inbound AS (
+			UPDATE inbound_messages m SET
+				conversation_key = k.erased_key,
+				kakao_payload = '{"erased":true}',
+				normalized_message = NULL,
+				callback_url = NULL,
+				callback_expires_at = NULL,
+				source_event_id = NULL
+			FROM keys k
+			WHERE `+erasableMessages+`
+			RETURNING 1
+		),
+		outbound AS (
+			UPDATE outbound_messages m SET
+				conversation_key = k.erased_key,
+				kakao_target = '{}',
+				response_payload = '{"erased":true}',
+				error_message = NULL
+			FROM keys k
+			WHERE `+erasableMessages+`
+			RETURNING 1
+		),
+	`+forgetKakaoUserCTEs, plusfriendUserKey)
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type ExperimentRepository interface {
+	RecordExposure(ctx context.Context, experiment, variant, conversationKey string) error
+	GetVariantStats(ctx context.Context) ([]model.ExperimentVariantStats, error)
+}
+
+type experimentRepo struct {
+	db database.Querier
+}
+
+func NewExperimentRepository(db *sqlx.DB) ExperimentRepository {
+	return &experimentRepo{db: withRetry(db)}
+}
+
+// RecordExposure keeps the first variant a conversation saw so that conversion
+// is attributed to it even if the experiment definition changes later.
+func (r *experimentRepo) RecordExposure(ctx context.Context, experiment, variant, conversationKey string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO experiment_exposures (experiment, variant, conversation_key)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (experiment, conversation_key) DO UPDATE SET
+			last_shown_at = NOW(),
+			shown_count = experiment_exposures.shown_count + 1
+	`, experiment, variant, conversationKey)
+	return err
+}
+
+func (r *experimentRepo) GetVariantStats(ctx context.Context) ([]model.ExperimentVariantStats, error) {
+	var stats []model.ExperimentVariantStats
+	err := r.db.SelectContext(ctx, &stats, `
+		SELECT
+			e.experiment,
+			e.variant,
+			COUNT(*) as exposures,
+			COUNT(*) FILTER (
+				WHERE cm.state = 'paired' AND cm.paired_at >= e.first_shown_at
+			) as conversions
+		FROM experiment_exposures e
+		LEFT JOIN conversation_mappings cm ON cm.conversation_key = e.conversation_key
+		GROUP BY e.experiment, e.variant
+		ORDER BY e.experiment, e.variant
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		if stats[i].Exposures > 0 {
+			stats[i].ConversionRate = float64(stats[i].Conversions) / float64(stats[i].Exposures)
+		}
+	}
+	return stats, nil
+}
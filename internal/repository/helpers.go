@@ -3,6 +3,11 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
 )
 
 // HandleNotFound processes a database query result, converting sql.ErrNoRows
@@ -23,3 +28,20 @@ func HandleNotFound[T any](result *T, err error) (*T, error) {
 	}
 	return result, nil
 }
+
+// withRetry wraps a connection pool so repository queries are retried when
+// they certainly did not run (see database.IsRetryable) and failures are
+// classified into AppErrors.
+// Transactions (WithTx) use the raw *sqlx.Tx: a failed statement aborts the
+// transaction, so retrying a single statement inside it is pointless.
+func withRetry(db *sqlx.DB) database.Querier {
+	return database.WithRetry(db, database.DefaultRetryPolicy)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern builds a LIKE/ILIKE pattern matching s anywhere, with LIKE
+// wildcards in s matched literally.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+)
+
+// integrityScanLimit caps the rows returned by each Find* query so that a
+// badly broken database cannot make an audit load everything into memory.
+const integrityScanLimit = 1000
+
+// IntegrityRepository finds and repairs rows left inconsistent by partial
+// failures or by deletes that SET NULL a foreign key. Repairs re-check the
+// inconsistency in their WHERE clause so they are safe against concurrent
+// fixes.
+type IntegrityRepository interface {
+	FindPairedSessionsWithoutAccount(ctx context.Context) ([]string, error)
+	FindPairedConversationsWithoutAccount(ctx context.Context) ([]string, error)
+	FindStaleQueuedMessages(ctx context.Context, before time.Time) ([]string, error)
+	FindOrphanPortalUsers(ctx context.Context) ([]string, error)
+	FindHalfPairedSessions(ctx context.Context) ([]string, error)
+	DisconnectSessionsWithoutAccount(ctx context.Context, ids []string) (int64, error)
+	UnpairConversationsWithoutAccount(ctx context.Context, keys []string) (int64, error)
+	ExpireStaleQueuedMessages(ctx context.Context, ids []string, before time.Time) (int64, error)
+	CompleteHalfPairedConversations(ctx context.Context, sessionIDs []string) (int64, error)
+}
+
+// halfPairedCondition matches a paired session whose conversation was not
+// updated by the pairing: it is not paired to the session's account and has
+// not been (un)paired since. Blocked conversations are left alone, and
+// archived ones still count as paired.
+const halfPairedCondition = `
+	s.status = 'paired'
+	AND s.account_id IS NOT NULL
+	AND c.state <> 'blocked'
+	AND (c.paired_at IS NULL OR c.paired_at < s.paired_at)
+	AND NOT (c.state IN ('paired', 'archived') AND c.account_id = s.account_id)
+`
+
+type integrityRepo struct {
+	db database.Querier
+}
+
+func NewIntegrityRepository(db *sqlx.DB) IntegrityRepository {
+	return &integrityRepo{db: withRetry(db)}
+}
+
+// Paired sessions whose account was deleted (sessions.account_id is SET NULL).
+func (r *integrityRepo) FindPairedSessionsWithoutAccount(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT s.id FROM sessions s
+		LEFT JOIN accounts a ON a.id = s.account_id
+		WHERE s.status = 'paired' AND a.id IS NULL
+		ORDER BY s.created_at
+		LIMIT $1
+	`, integrityScanLimit)
+	return ids, err
+}
+
+// Conversations still paired or archived although their account is gone.
+func (r *integrityRepo) FindPairedConversationsWithoutAccount(ctx context.Context) ([]string, error) {
+	var keys []string
+	err := r.db.SelectContext(ctx, &keys, `
+		SELECT c.conversation_key FROM conversation_mappings c
+		LEFT JOIN accounts a ON a.id = c.account_id
+		WHERE c.state IN ('paired', 'archived') AND a.id IS NULL
+		ORDER BY c.first_seen_at
+		LIMIT $1
+	`, integrityScanLimit)
+	return keys, err
+}
+
+// Queued messages created before the given time. Unlike MarkExpired this also
+// catches messages without a callback expiry.
+func (r *integrityRepo) FindStaleQueuedMessages(ctx context.Context, before time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT id FROM inbound_messages
+		WHERE status = 'queued' AND created_at < $1
+		ORDER BY created_at
+		LIMIT $2
+	`, before, integrityScanLimit)
+	return ids, err
+}
+
+// Portal users whose account no longer exists.
+func (r *integrityRepo) FindOrphanPortalUsers(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT u.id FROM portal_users u
+		LEFT JOIN accounts a ON a.id = u.account_id
+		WHERE a.id IS NULL
+		ORDER BY u.created_at
+		LIMIT $1
+	`, integrityScanLimit)
+	return ids, err
+}
+
+// Paired sessions whose conversation mapping missed the pairing update.
+func (r *integrityRepo) FindHalfPairedSessions(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT s.id FROM sessions s
+		JOIN conversation_mappings c ON c.conversation_key = s.paired_conversation_key
+		WHERE `+halfPairedCondition+`
+		ORDER BY s.paired_at
+		LIMIT $1
+	`, integrityScanLimit)
+	return ids, err
+}
+
+func (r *integrityRepo) DisconnectSessionsWithoutAccount(ctx context.Context, ids []string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE sessions SET
+			status = 'disconnected',
+			updated_at = NOW()
+		WHERE id = ANY($1)
+		AND status = 'paired'
+		AND NOT EXISTS (SELECT 1 FROM accounts a WHERE a.id = sessions.account_id)
+	`, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *integrityRepo) UnpairConversationsWithoutAccount(ctx context.Context, keys []string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE conversation_mappings SET
+			state = 'unpaired',
+			account_id = NULL,
+			paired_at = NULL
+		WHERE conversation_key = ANY($1)
+		AND state IN ('paired', 'archived')
+		AND NOT EXISTS (SELECT 1 FROM accounts a WHERE a.id = conversation_mappings.account_id)
+	`, keys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *integrityRepo) ExpireStaleQueuedMessages(ctx context.Context, ids []string, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE inbound_messages SET status = 'expired'
+		WHERE id = ANY($1)
+		AND status = 'queued'
+		AND created_at < $2
+	`, ids, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CompleteHalfPairedConversations applies the missed pairing update to the
+// conversations of the given sessions.
+func (r *integrityRepo) CompleteHalfPairedConversations(ctx context.Context, sessionIDs []string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE conversation_mappings c SET
+			state = 'paired',
+			account_id = s.account_id,
+			paired_at = s.paired_at
+		FROM sessions s
+		WHERE s.id = ANY($1)
+		AND c.conversation_key = s.paired_conversation_key
+		AND `+halfPairedCondition, sessionIDs)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,36 +2,69 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/openclaw/relay-server-go/internal/database"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
 type InboundMessageRepository interface {
 	FindByID(ctx context.Context, id string) (*model.InboundMessage, error)
+	// FindQueuedByAccountID leaves out messages held back by a delivery pause.
 	FindQueuedByAccountID(ctx context.Context, accountID string) ([]model.InboundMessage, error)
+	FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error)
 	FindByAccountID(ctx context.Context, accountID string, limit, offset int) ([]model.InboundMessage, error)
 	FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.InboundMessage, error)
 	CountByAccountID(ctx context.Context, accountID string) (int, error)
 	CountByConversationKey(ctx context.Context, conversationKey string) (int, error)
 	CountByConversationKeySince(ctx context.Context, conversationKey string, since time.Time) (int, error)
 	Create(ctx context.Context, params model.CreateInboundMessageParams) (*model.InboundMessage, error)
+	CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error)
 	MarkDelivered(ctx context.Context, id string) error
 	MarkAcked(ctx context.Context, id string) error
+	// MarkExpired expires queued messages whose callback has expired, except
+	// those held back by a delivery pause.
 	MarkExpired(ctx context.Context) (int64, error)
+	// ClaimWebhookDue claims up to limit queued messages of webhook accounts
+	// that are due for a delivery attempt, holding them until leaseUntil so
+	// that other instances skip them. Accounts whose webhook is degraded are
+	// left out, and so are messages behind an earlier message of their
+	// account that waits for a retry or is claimed by another instance.
+	ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error)
+	// RecordWebhookFailure counts a failed delivery attempt and schedules the
+	// next one at nextAt.
+	RecordWebhookFailure(ctx context.Context, id, errorMsg string, nextAt time.Time) error
+	// ReleaseWebhookLease makes a claimed message that was not attempted due
+	// again.
+	ReleaseWebhookLease(ctx context.Context, id string) error
 	CountByStatus(ctx context.Context, status model.InboundMessageStatus) (int, error)
+	// QueuedBacklog counts queued messages and finds the oldest one. Messages
+	// held back by a delivery pause are not backlog.
+	QueuedBacklog(ctx context.Context) (*model.InboundBacklog, error)
 	CountByAccountIDAndStatus(ctx context.Context, accountID string, status model.InboundMessageStatus) (int, error)
 	CountByAccountIDSince(ctx context.Context, accountID string, since time.Time) (int, error)
+	FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.InboundMessage, error)
+	CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error)
 }
 
+// inboundNotPaused matches inbound messages whose conversation is not
+// paused; a paused conversation's queued messages wait for it to resume.
+const inboundNotPaused = `NOT EXISTS (
+	SELECT 1 FROM conversation_mappings c
+	WHERE c.conversation_key = inbound_messages.conversation_key
+	AND c.delivery_paused_at IS NOT NULL
+)`
+
 type inboundMessageRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewInboundMessageRepository(db *sqlx.DB) InboundMessageRepository {
-	return &inboundMessageRepo{db: db}
+	return &inboundMessageRepo{db: withRetry(db)}
 }
 
 func (r *inboundMessageRepo) FindByID(ctx context.Context, id string) (*model.InboundMessage, error) {
@@ -45,11 +78,22 @@ func (r *inboundMessageRepo) FindQueuedByAccountID(ctx context.Context, accountI
 	err := r.db.SelectContext(ctx, &msgs, `
 		SELECT * FROM inbound_messages
 		WHERE account_id = $1 AND status = 'queued'
+		AND `+inboundNotPaused+`
 		ORDER BY created_at ASC
 	`, accountID)
 	return msgs, err
 }
 
+func (r *inboundMessageRepo) FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error) {
+	var msgs []model.InboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		SELECT * FROM inbound_messages
+		WHERE conversation_key = $1 AND status = 'queued'
+		ORDER BY created_at ASC
+	`, conversationKey)
+	return msgs, err
+}
+
 func (r *inboundMessageRepo) FindByAccountID(ctx context.Context, accountID string, limit, offset int) ([]model.InboundMessage, error) {
 	var msgs []model.InboundMessage
 	err := r.db.SelectContext(ctx, &msgs, `
@@ -114,6 +158,54 @@ func (r *inboundMessageRepo) Create(ctx context.Context, params model.CreateInbo
 	return &msg, nil
 }
 
+// CreateBatch inserts all messages in one statement, in the order given. The
+// rows are passed as parallel arrays, so the statement has a fixed number of
+// parameters however many messages there are.
+func (r *inboundMessageRepo) CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	n := len(params)
+	accountIDs := make([]string, n)
+	conversationKeys := make([]string, n)
+	payloads := make([]string, n)
+	normalized := make([]*string, n)
+	callbackURLs := make([]*string, n)
+	callbackExpiresAt := make([]*time.Time, n)
+	sourceEventIDs := make([]*string, n)
+	for i, p := range params {
+		accountIDs[i] = p.AccountID
+		conversationKeys[i] = p.ConversationKey
+		payloads[i] = string(p.KakaoPayload)
+		if len(p.NormalizedMessage) > 0 {
+			m := string(p.NormalizedMessage)
+			normalized[i] = &m
+		}
+		callbackURLs[i] = p.CallbackURL
+		callbackExpiresAt[i] = p.CallbackExpiresAt
+		sourceEventIDs[i] = p.SourceEventID
+	}
+
+	var msgs []model.InboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		INSERT INTO inbound_messages
+			(account_id, conversation_key, kakao_payload, normalized_message,
+			 callback_url, callback_expires_at, source_event_id)
+		SELECT a, c, p, m, u, e, s
+		FROM unnest($1::text[], $2::text[], $3::jsonb[], $4::jsonb[],
+		            $5::text[], $6::timestamptz[], $7::text[])
+			WITH ORDINALITY AS t(a, c, p, m, u, e, s, ord)
+		ORDER BY ord
+		RETURNING *
+	`, accountIDs, conversationKeys, payloads, normalized, callbackURLs,
+		callbackExpiresAt, sourceEventIDs)
+	if err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
 func (r *inboundMessageRepo) MarkDelivered(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE inbound_messages SET
@@ -140,6 +232,7 @@ func (r *inboundMessageRepo) MarkExpired(ctx context.Context) (int64, error) {
 		WHERE status = 'queued'
 		AND callback_expires_at IS NOT NULL
 		AND callback_expires_at < NOW()
+		AND `+inboundNotPaused+`
 	`)
 	if err != nil {
 		return 0, err
@@ -147,6 +240,57 @@ func (r *inboundMessageRepo) MarkExpired(ctx context.Context) (int64, error) {
 	return result.RowsAffected()
 }
 
+func (r *inboundMessageRepo) ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error) {
+	var msgs []model.InboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		UPDATE inbound_messages SET
+			webhook_next_at = $2
+		WHERE id IN (
+			SELECT id FROM inbound_messages
+			WHERE status = 'queued'
+			AND webhook_attempts < $3
+			AND (webhook_next_at IS NULL OR webhook_next_at <= $1)
+			AND account_id IN (
+				SELECT id FROM accounts
+				WHERE webhook_url IS NOT NULL AND webhook_degraded_at IS NULL AND disabled_at IS NULL
+			)
+			AND `+inboundNotPaused+`
+			AND NOT EXISTS (
+				SELECT 1 FROM inbound_messages earlier
+				WHERE earlier.account_id = inbound_messages.account_id
+				AND earlier.status = 'queued'
+				AND earlier.webhook_attempts < $3
+				AND earlier.webhook_next_at > $1
+				AND earlier.created_at < inbound_messages.created_at
+			)
+			ORDER BY created_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, now, leaseUntil, maxAttempts, limit)
+	return msgs, err
+}
+
+func (r *inboundMessageRepo) RecordWebhookFailure(ctx context.Context, id, errorMsg string, nextAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE inbound_messages SET
+			webhook_attempts = webhook_attempts + 1,
+			webhook_next_at = $3,
+			webhook_error = $2
+		WHERE id = $1
+	`, id, errorMsg, nextAt)
+	return err
+}
+
+func (r *inboundMessageRepo) ReleaseWebhookLease(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE inbound_messages SET webhook_next_at = NULL
+		WHERE id = $1 AND status = 'queued'
+	`, id)
+	return err
+}
+
 func (r *inboundMessageRepo) CountByStatus(ctx context.Context, status model.InboundMessageStatus) (int, error) {
 	var count int
 	err := r.db.GetContext(ctx, &count, `
@@ -155,6 +299,19 @@ func (r *inboundMessageRepo) CountByStatus(ctx context.Context, status model.Inb
 	return count, err
 }
 
+func (r *inboundMessageRepo) QueuedBacklog(ctx context.Context) (*model.InboundBacklog, error) {
+	var backlog model.InboundBacklog
+	err := r.db.GetContext(ctx, &backlog, `
+		SELECT COUNT(*) AS queued, MIN(created_at) AS oldest_queued_at
+		FROM inbound_messages WHERE status = 'queued'
+		AND `+inboundNotPaused+`
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return &backlog, nil
+}
+
 func (r *inboundMessageRepo) CountByAccountIDAndStatus(ctx context.Context, accountID string, status model.InboundMessageStatus) (int, error) {
 	var count int
 	err := r.db.GetContext(ctx, &count, `
@@ -171,6 +328,35 @@ func (r *inboundMessageRepo) CountByAccountIDSince(ctx context.Context, accountI
 	return count, err
 }
 
+// messageFilterClause matches the optional MessageFilter fields passed as
+// the two parameters after the account ID; empty values match all rows.
+// status is compared as text so a value from the other direction's enum
+// matches nothing instead of failing the cast.
+const messageFilterClause = `
+		AND ($2 = '' OR conversation_key = $2)
+		AND ($3 = '' OR status::text = $3)
+`
+
+func (r *inboundMessageRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.InboundMessage, error) {
+	var msgs []model.InboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		SELECT * FROM inbound_messages
+		WHERE account_id = $1`+messageFilterClause+`
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`, accountID, filter.ConversationKey, filter.Status, limit, offset)
+	return msgs, err
+}
+
+func (r *inboundMessageRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM inbound_messages
+		WHERE account_id = $1`+messageFilterClause,
+		accountID, filter.ConversationKey, filter.Status)
+	return count, err
+}
+
 // Outbound Message Repository
 
 type OutboundMessageRepository interface {
@@ -184,20 +370,29 @@ type OutboundMessageRepository interface {
 	CountByConversationKeyAndStatus(ctx context.Context, conversationKey string, status model.OutboundMessageStatus) (int, error)
 	CountByConversationKeyAndStatusSince(ctx context.Context, conversationKey string, status model.OutboundMessageStatus, since time.Time) (int, error)
 	Create(ctx context.Context, params model.CreateOutboundMessageParams) (*model.OutboundMessage, error)
+	// CreateReplyOnce creates a ReplyOnce reply unless its inbound message
+	// already has a pending or sent one, which it returns instead with false.
+	CreateReplyOnce(ctx context.Context, params model.CreateOutboundMessageParams) (*model.OutboundMessage, bool, error)
 	MarkSent(ctx context.Context, id string) error
 	MarkFailed(ctx context.Context, id string, errorMsg string) error
+	RecordCallbackResponse(ctx context.Context, id string, resp model.CallbackResponse) error
+	Cancel(ctx context.Context, id, accountID string) (*model.OutboundMessage, error)
+	FindScheduledByAccountID(ctx context.Context, accountID string, page model.KeysetPage) ([]model.OutboundMessage, error)
+	ClaimDueScheduled(ctx context.Context, now time.Time, limit int) ([]model.OutboundMessage, error)
 	CountByAccountIDAndStatus(ctx context.Context, accountID string, status model.OutboundMessageStatus) (int, error)
 	CountByAccountIDAndStatusSince(ctx context.Context, accountID string, status model.OutboundMessageStatus, since time.Time) (int, error)
 	CountByAccountIDSince(ctx context.Context, accountID string, since time.Time) (int, error)
 	FindRecentFailedByAccountID(ctx context.Context, accountID string, limit int) ([]model.OutboundMessage, error)
+	FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.OutboundMessage, error)
+	CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error)
 }
 
 type outboundMessageRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewOutboundMessageRepository(db *sqlx.DB) OutboundMessageRepository {
-	return &outboundMessageRepo{db: db}
+	return &outboundMessageRepo{db: withRetry(db)}
 }
 
 func (r *outboundMessageRepo) FindByID(ctx context.Context, id string) (*model.OutboundMessage, error) {
@@ -285,23 +480,61 @@ func (r *outboundMessageRepo) Create(ctx context.Context, params model.CreateOut
 	var msg model.OutboundMessage
 	err := r.db.GetContext(ctx, &msg, `
 		INSERT INTO outbound_messages
-			(account_id, inbound_message_id, conversation_key, kakao_target, response_payload)
-		VALUES ($1, $2, $3, $4, $5)
+			(account_id, inbound_message_id, conversation_key, kakao_target, response_payload,
+			 scheduled_at, callback_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING *
 	`, params.AccountID, params.InboundMessageID, params.ConversationKey,
-		params.KakaoTarget, params.ResponsePayload)
+		params.KakaoTarget, params.ResponsePayload, params.ScheduledAt, params.CallbackURL)
 	if err != nil {
 		return nil, err
 	}
 	return &msg, nil
 }
 
+func (r *outboundMessageRepo) CreateReplyOnce(ctx context.Context, params model.CreateOutboundMessageParams) (*model.OutboundMessage, bool, error) {
+	var msgs []model.OutboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		INSERT INTO outbound_messages
+			(account_id, inbound_message_id, conversation_key, kakao_target, response_payload,
+			 scheduled_at, callback_url, reply_once)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, true)
+		ON CONFLICT (inbound_message_id) WHERE reply_once AND status IN ('pending', 'sent') DO NOTHING
+		RETURNING *
+	`, params.AccountID, params.InboundMessageID, params.ConversationKey,
+		params.KakaoTarget, params.ResponsePayload, params.ScheduledAt, params.CallbackURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(msgs) > 0 {
+		return &msgs[0], true, nil
+	}
+
+	var msg model.OutboundMessage
+	err = r.db.GetContext(ctx, &msg, `
+		SELECT * FROM outbound_messages
+		WHERE inbound_message_id = $1 AND reply_once AND status IN ('pending', 'sent')
+	`, params.InboundMessageID)
+	existing, err := HandleNotFound(&msg, err)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing == nil {
+		// The other reply failed or was cancelled in between
+		return r.CreateReplyOnce(ctx, params)
+	}
+	return existing, false, nil
+}
+
+// MarkSent, MarkFailed and Cancel only move a message out of 'pending', so
+// whichever transition commits first wins and the others are no-ops.
+
 func (r *outboundMessageRepo) MarkSent(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE outbound_messages SET
 			status = 'sent',
 			sent_at = $2
-		WHERE id = $1
+		WHERE id = $1 AND status = 'pending'
 	`, id, time.Now())
 	return err
 }
@@ -311,11 +544,80 @@ func (r *outboundMessageRepo) MarkFailed(ctx context.Context, id string, errorMs
 		UPDATE outbound_messages SET
 			status = 'failed',
 			error_message = $2
-		WHERE id = $1
+		WHERE id = $1 AND status = 'pending'
 	`, id, errorMsg)
 	return err
 }
 
+func (r *outboundMessageRepo) RecordCallbackResponse(ctx context.Context, id string, resp model.CallbackResponse) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE outbound_messages SET
+			callback_status = $2,
+			callback_response = NULLIF($3, ''),
+			callback_task_id = NULLIF($4, '')
+		WHERE id = $1
+	`, id, resp.Status, resp.Body, resp.TaskID)
+	return err
+}
+
+// Cancel withdraws a pending message owned by the account. Returns nil when
+// no such pending message exists.
+func (r *outboundMessageRepo) Cancel(ctx context.Context, id, accountID string) (*model.OutboundMessage, error) {
+	var msg model.OutboundMessage
+	err := r.db.GetContext(ctx, &msg, `
+		UPDATE outbound_messages SET
+			status = 'cancelled'
+		WHERE id = $1 AND account_id = $2 AND status = 'pending'
+		RETURNING *
+	`, id, accountID)
+	return HandleNotFound(&msg, err)
+}
+
+// FindScheduledByAccountID returns the account's scheduled messages that
+// have not been sent yet, soonest first, keyed on (scheduled_at, id) for page
+// cursors.
+func (r *outboundMessageRepo) FindScheduledByAccountID(ctx context.Context, accountID string, page model.KeysetPage) ([]model.OutboundMessage, error) {
+	args := []any{accountID}
+	conditions := []string{"account_id = $1", "status = 'pending'", "scheduled_at IS NOT NULL"}
+	if page.After != nil {
+		args = append(args, page.After.At, page.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(scheduled_at, id) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+	limit := ""
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		limit = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	var msgs []model.OutboundMessage
+	err := r.db.SelectContext(ctx, &msgs, fmt.Sprintf(`
+		SELECT * FROM outbound_messages
+		WHERE %s
+		ORDER BY scheduled_at ASC, id ASC
+		%s
+	`, strings.Join(conditions, " AND "), limit), args...)
+	return msgs, err
+}
+
+// ClaimDueScheduled marks up to limit scheduled messages due at now as
+// dispatched and returns them. Concurrent callers claim different messages.
+func (r *outboundMessageRepo) ClaimDueScheduled(ctx context.Context, now time.Time, limit int) ([]model.OutboundMessage, error) {
+	var msgs []model.OutboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		UPDATE outbound_messages SET
+			dispatched_at = $1
+		WHERE id IN (
+			SELECT id FROM outbound_messages
+			WHERE status = 'pending' AND scheduled_at <= $1 AND dispatched_at IS NULL
+			ORDER BY scheduled_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, now, limit)
+	return msgs, err
+}
+
 func (r *outboundMessageRepo) CountByAccountIDAndStatus(ctx context.Context, accountID string, status model.OutboundMessageStatus) (int, error) {
 	var count int
 	err := r.db.GetContext(ctx, &count, `
@@ -351,3 +653,76 @@ func (r *outboundMessageRepo) FindRecentFailedByAccountID(ctx context.Context, a
 	`, accountID, limit)
 	return msgs, err
 }
+
+func (r *outboundMessageRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.OutboundMessage, error) {
+	var msgs []model.OutboundMessage
+	err := r.db.SelectContext(ctx, &msgs, `
+		SELECT * FROM outbound_messages
+		WHERE account_id = $1`+messageFilterClause+`
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`, accountID, filter.ConversationKey, filter.Status, limit, offset)
+	return msgs, err
+}
+
+func (r *outboundMessageRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM outbound_messages
+		WHERE account_id = $1`+messageFilterClause,
+		accountID, filter.ConversationKey, filter.Status)
+	return count, err
+}
+
+// Message Timeline Repository
+
+// MessageTimelineRepository reads inbound and outbound messages as one
+// timeline, newest first. Both directions are merged in a single UNION ALL
+// query so LIMIT/OFFSET page across directions consistently.
+type MessageTimelineRepository interface {
+	FindByAccountID(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.TimelineMessage, error)
+	FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.TimelineMessage, error)
+}
+
+type messageTimelineRepo struct {
+	db database.Querier
+}
+
+func NewMessageTimelineRepository(db *sqlx.DB) MessageTimelineRepository {
+	return &messageTimelineRepo{db: withRetry(db)}
+}
+
+// timelineQuery selects both directions filtered on column. id breaks ties
+// between messages created in the same instant so pages never overlap.
+func timelineQuery(column, filter string) string {
+	return `
+		SELECT id, conversation_key, 'inbound' AS direction, status::text AS status,
+			normalized_message AS content, created_at
+		FROM inbound_messages
+		WHERE ` + column + ` = $1` + filter + `
+		UNION ALL
+		SELECT id, conversation_key, 'outbound' AS direction, status::text AS status,
+			response_payload AS content, created_at
+		FROM outbound_messages
+		WHERE ` + column + ` = $1` + filter + `
+		ORDER BY created_at DESC, id DESC
+	`
+}
+
+var (
+	timelineByAccountQuery      = timelineQuery("account_id", messageFilterClause) + `LIMIT $4 OFFSET $5`
+	timelineByConversationQuery = timelineQuery("conversation_key", "") + `LIMIT $2 OFFSET $3`
+)
+
+func (r *messageTimelineRepo) FindByAccountID(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.TimelineMessage, error) {
+	var msgs []model.TimelineMessage
+	err := r.db.SelectContext(ctx, &msgs, timelineByAccountQuery,
+		accountID, filter.ConversationKey, filter.Status, limit, offset)
+	return msgs, err
+}
+
+func (r *messageTimelineRepo) FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.TimelineMessage, error) {
+	var msgs []model.TimelineMessage
+	err := r.db.SelectContext(ctx, &msgs, timelineByConversationQuery, conversationKey, limit, offset)
+	return msgs, err
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/blob"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// blobInboundMessageRepo keeps Kakao payloads of at least minBytes in a blob
+// store and only a model.OffloadedPayload reference in the row. Messages read
+// for delivery get their payload back; listings keep the reference.
+type blobInboundMessageRepo struct {
+	InboundMessageRepository
+	store    blob.Store
+	minBytes int
+}
+
+// NewBlobInboundMessageRepository wraps repo so that large payloads are
+// offloaded to store.
+func NewBlobInboundMessageRepository(repo InboundMessageRepository, store blob.Store, minBytes int) InboundMessageRepository {
+	return &blobInboundMessageRepo{InboundMessageRepository: repo, store: store, minBytes: minBytes}
+}
+
+// inboundBlobKey groups blobs by account and day, so that object storage
+// lifecycle rules can expire them and an account's blobs share a prefix.
+func inboundBlobKey(accountID string, now time.Time) string {
+	return fmt.Sprintf("inbound/%s/%s/%s.json", accountID, now.UTC().Format("2006-01-02"), rand.Text())
+}
+
+// offload moves the payload of params to the blob store. A failed upload
+// keeps the payload in the row rather than losing the message.
+func (r *blobInboundMessageRepo) offload(ctx context.Context, params *model.CreateInboundMessageParams) {
+	if len(params.KakaoPayload) < r.minBytes {
+		return
+	}
+
+	key := inboundBlobKey(params.AccountID, time.Now())
+	if err := r.store.Put(ctx, key, params.KakaoPayload); err != nil {
+		log.Warn().Err(err).Str("conversationKey", params.ConversationKey).Msg("failed to offload payload, storing it inline")
+		return
+	}
+	ref, _ := json.Marshal(model.OffloadedPayload{
+		Offloaded:     true,
+		BlobKey:       key,
+		OriginalBytes: len(params.KakaoPayload),
+	})
+	params.KakaoPayload = ref
+}
+
+// hydrate replaces an offloaded reference with the stored payload. When the
+// blob cannot be read the reference is left in place.
+func (r *blobInboundMessageRepo) hydrate(ctx context.Context, msg *model.InboundMessage) {
+	ref, ok := model.ParseOffloadedPayload(msg.KakaoPayload)
+	if !ok {
+		return
+	}
+	data, err := r.store.Get(ctx, ref.BlobKey)
+	if err != nil {
+		event := log.Warn()
+		if !errors.Is(err, blob.ErrNotFound) {
+			event = log.Error()
+		}
+		event.Err(err).Str("messageId", msg.ID).Str("blobKey", ref.BlobKey).Msg("failed to load offloaded payload")
+		return
+	}
+	msg.KakaoPayload = data
+}
+
+func (r *blobInboundMessageRepo) Create(ctx context.Context, params model.CreateInboundMessageParams) (*model.InboundMessage, error) {
+	payload := params.KakaoPayload
+	r.offload(ctx, &params)
+
+	msg, err := r.InboundMessageRepository.Create(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	msg.KakaoPayload = payload
+	return msg, nil
+}
+
+func (r *blobInboundMessageRepo) CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error) {
+	payloads := make([]json.RawMessage, len(params))
+	offloaded := make([]model.CreateInboundMessageParams, len(params))
+	for i, p := range params {
+		payloads[i] = p.KakaoPayload
+		r.offload(ctx, &p)
+		offloaded[i] = p
+	}
+
+	msgs, err := r.InboundMessageRepository.CreateBatch(ctx, offloaded)
+	if err != nil {
+		return nil, err
+	}
+	// Rows come back in the order given
+	for i := range msgs {
+		msgs[i].KakaoPayload = payloads[i]
+	}
+	return msgs, nil
+}
+
+func (r *blobInboundMessageRepo) FindByID(ctx context.Context, id string) (*model.InboundMessage, error) {
+	msg, err := r.InboundMessageRepository.FindByID(ctx, id)
+	if err != nil || msg == nil {
+		return msg, err
+	}
+	r.hydrate(ctx, msg)
+	return msg, nil
+}
+
+func (r *blobInboundMessageRepo) FindQueuedByAccountID(ctx context.Context, accountID string) ([]model.InboundMessage, error) {
+	msgs, err := r.InboundMessageRepository.FindQueuedByAccountID(ctx, accountID)
+	for i := range msgs {
+		r.hydrate(ctx, &msgs[i])
+	}
+	return msgs, err
+}
+
+func (r *blobInboundMessageRepo) FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error) {
+	msgs, err := r.InboundMessageRepository.FindQueuedByConversationKey(ctx, conversationKey)
+	for i := range msgs {
+		r.hydrate(ctx, &msgs[i])
+	}
+	return msgs, err
+}
+
+func (r *blobInboundMessageRepo) ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error) {
+	msgs, err := r.InboundMessageRepository.ClaimWebhookDue(ctx, now, leaseUntil, maxAttempts, limit)
+	for i := range msgs {
+		r.hydrate(ctx, &msgs[i])
+	}
+	return msgs, err
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/blob"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// memoryInboundRepo keeps created messages in memory.
+type memoryInboundRepo struct {
+	InboundMessageRepository
+	msgs []model.InboundMessage
+}
+
+func (m *memoryInboundRepo) Create(ctx context.Context, params model.CreateInboundMessageParams) (*model.InboundMessage, error) {
+	msg := model.InboundMessage{
+		ID:              params.ConversationKey,
+		AccountID:       params.AccountID,
+		ConversationKey: params.ConversationKey,
+		KakaoPayload:    params.KakaoPayload,
+		Status:          model.InboundStatusQueued,
+	}
+	m.msgs = append(m.msgs, msg)
+	return &msg, nil
+}
+
+func (m *memoryInboundRepo) CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error) {
+	var msgs []model.InboundMessage
+	for _, p := range params {
+		msg, _ := m.Create(ctx, p)
+		msgs = append(msgs, *msg)
+	}
+	return msgs, nil
+}
+
+func (m *memoryInboundRepo) FindByID(ctx context.Context, id string) (*model.InboundMessage, error) {
+	for _, msg := range m.msgs {
+		if msg.ID == id {
+			return &msg, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryInboundRepo) FindQueuedByAccountID(ctx context.Context, accountID string) ([]model.InboundMessage, error) {
+	return append([]model.InboundMessage(nil), m.msgs...), nil
+}
+
+func TestBlobInboundMessageRepository(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	inner := &memoryInboundRepo{}
+	repo := NewBlobInboundMessageRepository(inner, store, 64)
+
+	large := json.RawMessage(`{"text":"` + strings.Repeat("a", 100) + `"}`)
+	small := json.RawMessage(`{"text":"hi"}`)
+
+	created, err := repo.Create(ctx, model.CreateInboundMessageParams{AccountID: "acc-1", ConversationKey: "large", KakaoPayload: large})
+	require.NoError(t, err)
+	assert.JSONEq(t, string(large), string(created.KakaoPayload), "the caller gets the full payload")
+
+	batch, err := repo.CreateBatch(ctx, []model.CreateInboundMessageParams{
+		{AccountID: "acc-1", ConversationKey: "small", KakaoPayload: small},
+		{AccountID: "acc-1", ConversationKey: "large-2", KakaoPayload: large},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.JSONEq(t, string(small), string(batch[0].KakaoPayload))
+	assert.JSONEq(t, string(large), string(batch[1].KakaoPayload))
+
+	t.Run("stores only a reference for large payloads", func(t *testing.T) {
+		ref, ok := model.ParseOffloadedPayload(inner.msgs[0].KakaoPayload)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(ref.BlobKey, "inbound/acc-1/"))
+		assert.Equal(t, len(large), ref.OriginalBytes)
+
+		assert.JSONEq(t, string(small), string(inner.msgs[1].KakaoPayload))
+		_, ok = model.ParseOffloadedPayload(inner.msgs[2].KakaoPayload)
+		assert.True(t, ok)
+	})
+
+	t.Run("restores payloads read for delivery", func(t *testing.T) {
+		msg, err := repo.FindByID(ctx, "large")
+		require.NoError(t, err)
+		assert.JSONEq(t, string(large), string(msg.KakaoPayload))
+
+		msgs, err := repo.FindQueuedByAccountID(ctx, "acc-1")
+		require.NoError(t, err)
+		require.Len(t, msgs, 3)
+		for _, msg := range msgs {
+			_, offloaded := model.ParseOffloadedPayload(msg.KakaoPayload)
+			assert.False(t, offloaded, msg.ID)
+		}
+	})
+
+	t.Run("keeps the reference when the blob is gone", func(t *testing.T) {
+		ref, _ := model.ParseOffloadedPayload(inner.msgs[0].KakaoPayload)
+		require.NoError(t, store.Delete(ctx, ref.BlobKey))
+
+		msg, err := repo.FindByID(ctx, "large")
+		require.NoError(t, err)
+		assert.Equal(t, inner.msgs[0].KakaoPayload, msg.KakaoPayload)
+	})
+}
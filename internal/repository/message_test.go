@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestAccount inserts an account for message rows to reference and
+// removes it, with its messages, when the test ends.
+func createTestAccount(tb testing.TB, db *database.DB) string {
+	tb.Helper()
+	ctx := context.Background()
+	account, err := NewAccountRepository(db.DB).Create(ctx, model.CreateAccountParams{
+		RelayTokenHash:  fmt.Sprintf("test-token-%d", time.Now().UnixNano()),
+		Mode:            model.AccountModeRelay,
+		RateLimitPerMin: 60,
+	})
+	require.NoError(tb, err)
+	tb.Cleanup(func() {
+		db.DB.ExecContext(ctx, `DELETE FROM accounts WHERE id = $1`, account.ID)
+	})
+	return account.ID
+}
+
+func inboundParams(accountID string, n int) []model.CreateInboundMessageParams {
+	params := make([]model.CreateInboundMessageParams, n)
+	for i := range params {
+		params[i] = model.CreateInboundMessageParams{
+			AccountID:       accountID,
+			ConversationKey: "test-channel:burst",
+			KakaoPayload:    json.RawMessage(fmt.Sprintf(`{"utterance":"message %d"}`, i)),
+		}
+	}
+	return params
+}
+
+func TestInboundMessageRepository_CreateBatch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewInboundMessageRepository(db.DB)
+	ctx := context.Background()
+	accountID := createTestAccount(t, db)
+
+	callbackURL := "https://bot-api.kakao.com/callback/1"
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Microsecond)
+	sourceEventID := fmt.Sprintf("evt-%d", time.Now().UnixNano())
+	params := inboundParams(accountID, 3)
+	params[1].NormalizedMessage = json.RawMessage(`{"type":"text","text":"message 1"}`)
+	params[1].CallbackURL = &callbackURL
+	params[1].CallbackExpiresAt = &expiresAt
+	params[2].SourceEventID = &sourceEventID
+
+	msgs, err := repo.CreateBatch(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, msgs, 3)
+
+	for i, msg := range msgs {
+		assert.JSONEq(t, string(params[i].KakaoPayload), string(msg.KakaoPayload), "rows come back in order")
+		assert.Equal(t, model.InboundStatusQueued, msg.Status)
+	}
+	assert.Nil(t, msgs[0].NormalizedMessage)
+	assert.Nil(t, msgs[0].CallbackURL)
+	require.NotNil(t, msgs[1].CallbackURL)
+	assert.Equal(t, callbackURL, *msgs[1].CallbackURL)
+	require.NotNil(t, msgs[1].CallbackExpiresAt)
+	assert.True(t, expiresAt.Equal(*msgs[1].CallbackExpiresAt))
+	require.NotNil(t, msgs[2].SourceEventID)
+	assert.Equal(t, sourceEventID, *msgs[2].SourceEventID)
+
+	t.Run("empty batch", func(t *testing.T) {
+		msgs, err := repo.CreateBatch(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, msgs)
+	})
+
+	t.Run("duplicate source event fails the whole batch", func(t *testing.T) {
+		dup := inboundParams(accountID, 2)
+		dup[1].SourceEventID = &sourceEventID
+		_, err := repo.CreateBatch(ctx, dup)
+		require.Error(t, err)
+
+		count, err := repo.CountByAccountID(ctx, accountID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+}
+
+// Run with: go test ./internal/repository -run '^$' -bench InboundInsert
+func BenchmarkInboundInsert(b *testing.B) {
+	db := setupTestDB(b)
+	defer db.Close()
+
+	repo := NewInboundMessageRepository(db.DB)
+	ctx := context.Background()
+	accountID := createTestAccount(b, db)
+
+	for _, size := range []int{10, 100, 1000} {
+		params := inboundParams(accountID, size)
+
+		b.Run(fmt.Sprintf("per-row/%d", size), func(b *testing.B) {
+			for b.Loop() {
+				for _, p := range params {
+					if _, err := repo.Create(ctx, p); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(b.N*size)/b.Elapsed().Seconds(), "msgs/s")
+		})
+
+		b.Run(fmt.Sprintf("batch/%d", size), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := repo.CreateBatch(ctx, params); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N*size)/b.Elapsed().Seconds(), "msgs/s")
+		})
+	}
+}
+
+// BenchmarkWebhookHotPath runs the statements of one paired inbound webhook:
+// the conversation upsert and the message insert. The exec mode sends every
+// statement unprepared like lib/pq did, cache_statement is pgx's default
+// that prepares and caches them per connection; compare the two with
+// benchstat.
+//
+// Run with: go test ./internal/repository -run '^$' -bench WebhookHotPath -count 10
+func BenchmarkWebhookHotPath(b *testing.B) {
+	for _, mode := range []string{"exec", "cache_statement"} {
+		b.Run(mode, func(b *testing.B) {
+			db, err := database.Connect(testDatabaseURL + "&default_query_exec_mode=" + mode)
+			require.NoError(b, err)
+			defer db.Close()
+
+			convRepo := NewConversationRepository(db.DB)
+			inboundRepo := NewInboundMessageRepository(db.DB)
+			ctx := context.Background()
+			accountID := createTestAccount(b, db)
+			key := fmt.Sprintf("test-channel:bench-%d", time.Now().UnixNano())
+			defer db.DB.ExecContext(ctx, `DELETE FROM conversation_mappings WHERE conversation_key = $1`, key)
+
+			payload := json.RawMessage(`{"utterance":"hello"}`)
+			for b.Loop() {
+				if _, err := convRepo.Upsert(ctx, model.UpsertConversationParams{
+					ConversationKey:   key,
+					KakaoChannelID:    "test-channel",
+					PlusfriendUserKey: key,
+				}); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := inboundRepo.Create(ctx, model.CreateInboundMessageParams{
+					AccountID:       accountID,
+					ConversationKey: key,
+					KakaoPayload:    payload,
+				}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -6,24 +6,29 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/openclaw/relay-server-go/internal/database"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
 type PairingCodeRepository interface {
 	FindByCode(ctx context.Context, code string) (*model.PairingCode, error)
 	FindActiveByAccountID(ctx context.Context, accountID string) ([]model.PairingCode, error)
+	FindActive(ctx context.Context, limit, offset int) ([]model.PairingCode, error)
+	CountActive(ctx context.Context) (int, error)
 	CountActiveByAccountID(ctx context.Context, accountID string) (int, error)
 	Create(ctx context.Context, params model.CreatePairingCodeParams) (*model.PairingCode, error)
 	MarkUsed(ctx context.Context, code string, usedBy string) error
+	Revoke(ctx context.Context, accountID, code string) (bool, error)
+	RevokeByCode(ctx context.Context, code string) (bool, error)
 	DeleteExpired(ctx context.Context) (int64, error)
 }
 
 type pairingCodeRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewPairingCodeRepository(db *sqlx.DB) PairingCodeRepository {
-	return &pairingCodeRepo{db: db}
+	return &pairingCodeRepo{db: withRetry(db)}
 }
 
 func (r *pairingCodeRepo) FindByCode(ctx context.Context, code string) (*model.PairingCode, error) {
@@ -45,6 +50,26 @@ func (r *pairingCodeRepo) FindActiveByAccountID(ctx context.Context, accountID s
 	return codes, err
 }
 
+func (r *pairingCodeRepo) FindActive(ctx context.Context, limit, offset int) ([]model.PairingCode, error) {
+	var codes []model.PairingCode
+	err := r.db.SelectContext(ctx, &codes, `
+		SELECT * FROM pairing_codes
+		WHERE used_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	return codes, err
+}
+
+func (r *pairingCodeRepo) CountActive(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM pairing_codes
+		WHERE used_at IS NULL AND expires_at > NOW()
+	`)
+	return count, err
+}
+
 func (r *pairingCodeRepo) CountActiveByAccountID(ctx context.Context, accountID string) (int, error) {
 	var count int
 	err := r.db.GetContext(ctx, &count, `
@@ -77,6 +102,33 @@ func (r *pairingCodeRepo) MarkUsed(ctx context.Context, code string, usedBy stri
 	return err
 }
 
+// Revoke expires an unused code of the account immediately so it can no longer
+// be redeemed. The row is left for the cleanup job to delete.
+func (r *pairingCodeRepo) Revoke(ctx context.Context, accountID, code string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE pairing_codes SET expires_at = NOW()
+		WHERE code = $1 AND account_id = $2 AND used_at IS NULL AND expires_at > NOW()
+	`, code, accountID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// RevokeByCode is Revoke without the account check, for admins.
+func (r *pairingCodeRepo) RevokeByCode(ctx context.Context, code string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE pairing_codes SET expires_at = NOW()
+		WHERE code = $1 AND used_at IS NULL AND expires_at > NOW()
+	`, code)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
 func (r *pairingCodeRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	result, err := r.db.ExecContext(ctx, `
 		DELETE FROM pairing_codes
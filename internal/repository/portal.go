@@ -6,6 +6,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/openclaw/relay-server-go/internal/database"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
@@ -18,11 +19,11 @@ type PortalUserRepository interface {
 }
 
 type portalUserRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewPortalUserRepository(db *sqlx.DB) PortalUserRepository {
-	return &portalUserRepo{db: db}
+	return &portalUserRepo{db: withRetry(db)}
 }
 
 func (r *portalUserRepo) FindByID(ctx context.Context, id string) (*model.PortalUser, error) {
@@ -73,11 +74,11 @@ type PortalSessionRepository interface {
 }
 
 type portalSessionRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 func NewPortalSessionRepository(db *sqlx.DB) PortalSessionRepository {
-	return &portalSessionRepo{db: db}
+	return &portalSessionRepo{db: withRetry(db)}
 }
 
 func (r *portalSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PortalSession, error) {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/openclaw/relay-server-go/internal/database"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
@@ -20,12 +21,12 @@ type PortalAccessCodeRepository interface {
 }
 
 type portalAccessCodeRepo struct {
-	db *sqlx.DB
+	db database.Querier
 }
 
 // NewPortalAccessCodeRepository creates a new portal access code repository
 func NewPortalAccessCodeRepository(db *sqlx.DB) PortalAccessCodeRepository {
-	return &portalAccessCodeRepo{db: db}
+	return &portalAccessCodeRepo{db: withRetry(db)}
 }
 
 // Create creates a new portal access code
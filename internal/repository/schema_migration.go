@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+)
+
+type SchemaMigrationRepository interface {
+	// Applied returns the names of the migrations recorded as applied.
+	Applied(ctx context.Context) ([]string, error)
+}
+
+type schemaMigrationRepo struct {
+	db database.Querier
+}
+
+func NewSchemaMigrationRepository(db *sqlx.DB) SchemaMigrationRepository {
+	return &schemaMigrationRepo{db: withRetry(db)}
+}
+
+func (r *schemaMigrationRepo) Applied(ctx context.Context) ([]string, error) {
+	var names []string
+	err := r.db.SelectContext(ctx, &names, `SELECT name FROM schema_migrations ORDER BY name`)
+	return names, err
+}
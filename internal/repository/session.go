@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -14,12 +15,24 @@ type SessionRepository interface {
 	FindByID(ctx context.Context, id string) (*model.Session, error)
 	FindByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error)
 	FindByPairingCode(ctx context.Context, code string) (*model.Session, error)
+	// FindPairedByConversationKey returns the most recently paired session of
+	// a conversation, or nil.
+	FindPairedByConversationKey(ctx context.Context, conversationKey string) (*model.Session, error)
 	Create(ctx context.Context, params model.CreateSessionParams) (*model.Session, error)
+	// CreateWithinIPLimit creates the session unless its client IP already
+	// holds limit pending sessions created since since, in which case it
+	// returns ErrPendingSessionLimit. Concurrent calls for one IP are
+	// serialized, so they cannot exceed the limit together.
+	CreateWithinIPLimit(ctx context.Context, params model.CreateSessionParams, limit int, since time.Time) (*model.Session, error)
 	MarkPaired(ctx context.Context, id string, accountID string, conversationKey string) error
 	MarkExpired(ctx context.Context, id string) error
+	ExpirePending(ctx context.Context) ([]model.Session, error)
+	RevokePending(ctx context.Context, id string) (*model.Session, error)
 	MarkDisconnected(ctx context.Context, id string) error
 	DeleteExpired(ctx context.Context) (int64, error)
 	CountPendingByIP(ctx context.Context, ip string, since time.Time) (int, error)
+	UpdatePluginVersion(ctx context.Context, id string, version string) error
+	CountByPluginVersion(ctx context.Context) ([]model.PluginVersionCount, error)
 	// WithTx returns a new repository that uses the given transaction
 	WithTx(tx *sqlx.Tx) SessionRepository
 }
@@ -31,12 +44,18 @@ type sessionDB interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
 
+// ErrPendingSessionLimit is returned by CreateWithinIPLimit when the client
+// IP holds the maximum number of pending sessions.
+var ErrPendingSessionLimit = errors.New("pending session limit reached")
+
 type sessionRepo struct {
 	db sessionDB
+	// Starts transactions; nil when db already is one
+	conn *sqlx.DB
 }
 
 func NewSessionRepository(db *sqlx.DB) SessionRepository {
-	return &sessionRepo{db: db}
+	return &sessionRepo{db: withRetry(db), conn: db}
 }
 
 func (r *sessionRepo) WithTx(tx *sqlx.Tx) SessionRepository {
@@ -72,19 +91,78 @@ func (r *sessionRepo) FindByPairingCode(ctx context.Context, code string) (*mode
 	return HandleNotFound(&session, err)
 }
 
+func (r *sessionRepo) FindPairedByConversationKey(ctx context.Context, conversationKey string) (*model.Session, error) {
+	var session model.Session
+	err := r.db.GetContext(ctx, &session, `
+		SELECT * FROM sessions
+		WHERE paired_conversation_key = $1
+		AND status = 'paired'
+		ORDER BY paired_at DESC
+		LIMIT 1
+	`, conversationKey)
+	return HandleNotFound(&session, err)
+}
+
 func (r *sessionRepo) Create(ctx context.Context, params model.CreateSessionParams) (*model.Session, error) {
 	var session model.Session
 	err := r.db.GetContext(ctx, &session, `
-		INSERT INTO sessions (session_token_hash, pairing_code, expires_at, metadata)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO sessions (session_token_hash, pairing_code, expires_at, metadata, client_ip, plugin_version, kakao_channel_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING *
-	`, params.SessionTokenHash, params.PairingCode, params.ExpiresAt, params.Metadata)
+	`, params.SessionTokenHash, params.PairingCode, params.ExpiresAt, params.Metadata, params.ClientIP, params.PluginVersion, params.KakaoChannelID)
 	if err != nil {
 		return nil, err
 	}
 	return &session, nil
 }
 
+func (r *sessionRepo) CreateWithinIPLimit(ctx context.Context, params model.CreateSessionParams, limit int, since time.Time) (*model.Session, error) {
+	if r.conn == nil {
+		return r.createWithinIPLimit(ctx, params, limit, since)
+	}
+
+	tx, err := r.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := (&sessionRepo{db: tx}).createWithinIPLimit(ctx, params, limit, since)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// createWithinIPLimit counts and inserts under a transaction-scoped advisory
+// lock on the client IP. Each statement sees the rows committed before it
+// ran, so a request that waited for the lock counts the session the holder
+// created.
+func (r *sessionRepo) createWithinIPLimit(ctx context.Context, params model.CreateSessionParams, limit int, since time.Time) (*model.Session, error) {
+	if params.ClientIP == nil {
+		return r.Create(ctx, params)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		SELECT pg_advisory_xact_lock(hashtext('sessions.client_ip'), hashtext($1))
+	`, *params.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := r.CountPendingByIP(ctx, *params.ClientIP, since)
+	if err != nil {
+		return nil, err
+	}
+	if pending >= limit {
+		return nil, ErrPendingSessionLimit
+	}
+	return r.Create(ctx, params)
+}
+
 func (r *sessionRepo) MarkPaired(ctx context.Context, id string, accountID string, conversationKey string) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE sessions SET
@@ -109,6 +187,35 @@ func (r *sessionRepo) MarkExpired(ctx context.Context, id string) error {
 	return err
 }
 
+// ExpirePending marks pending sessions past expires_at as expired and returns them.
+func (r *sessionRepo) ExpirePending(ctx context.Context) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.SelectContext(ctx, &sessions, `
+		UPDATE sessions SET
+			status = 'expired',
+			updated_at = NOW()
+		WHERE status = 'pending_pairing'
+		AND expires_at <= NOW()
+		RETURNING *
+	`)
+	return sessions, err
+}
+
+// RevokePending expires a pending session immediately and returns it, or nil
+// when the session is not pending.
+func (r *sessionRepo) RevokePending(ctx context.Context, id string) (*model.Session, error) {
+	var session model.Session
+	err := r.db.GetContext(ctx, &session, `
+		UPDATE sessions SET
+			status = 'expired',
+			expires_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'pending_pairing'
+		RETURNING *
+	`, id)
+	return HandleNotFound(&session, err)
+}
+
 func (r *sessionRepo) MarkDisconnected(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE sessions SET
@@ -132,7 +239,35 @@ func (r *sessionRepo) DeleteExpired(ctx context.Context) (int64, error) {
 }
 
 func (r *sessionRepo) CountPendingByIP(ctx context.Context, ip string, since time.Time) (int, error) {
-	// Note: This requires storing IP in metadata when creating session
-	// For now, return 0 to not block (rate limiting can be added later)
-	return 0, nil
+	var count int
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM sessions
+		WHERE client_ip = $1
+		AND status = 'pending_pairing'
+		AND expires_at > NOW()
+		AND created_at >= $2
+	`, ip, since)
+	return count, err
+}
+
+func (r *sessionRepo) UpdatePluginVersion(ctx context.Context, id string, version string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE sessions SET
+			plugin_version = $2,
+			updated_at = $3
+		WHERE id = $1
+	`, id, version, time.Now())
+	return err
+}
+
+func (r *sessionRepo) CountByPluginVersion(ctx context.Context) ([]model.PluginVersionCount, error) {
+	var counts []model.PluginVersionCount
+	err := r.db.SelectContext(ctx, &counts, `
+		SELECT plugin_version, status, COUNT(*) as count
+		FROM sessions
+		WHERE status IN ('pending_pairing', 'paired')
+		GROUP BY plugin_version, status
+		ORDER BY count DESC
+	`)
+	return counts, err
 }
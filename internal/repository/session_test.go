@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRepository_CreateWithinIPLimitConcurrent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewSessionRepository(db.DB)
+	ctx := context.Background()
+	ip := fmt.Sprintf("race-%d", time.Now().UnixNano())
+	defer db.DB.ExecContext(ctx, `DELETE FROM sessions WHERE client_ip = $1`, ip)
+
+	const requests, limit = 8, 3
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = repo.CreateWithinIPLimit(ctx, model.CreateSessionParams{
+				SessionTokenHash: fmt.Sprintf("%s-token-%d", ip, i),
+				PairingCode:      fmt.Sprintf("%s-code-%d", ip, i),
+				ExpiresAt:        time.Now().Add(time.Hour),
+				ClientIP:         &ip,
+			}, limit, time.Now().Add(-time.Hour))
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrPendingSessionLimit)
+	}
+	assert.Equal(t, limit, created)
+
+	var rows int
+	require.NoError(t, db.DB.GetContext(ctx, &rows, `SELECT COUNT(*) FROM sessions WHERE client_ip = $1`, ip))
+	assert.Equal(t, limit, rows)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/openclaw/relay-server-go/internal/database"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type AccountSetupRepository interface {
+	// FindFlags returns when each recorded step was done.
+	FindFlags(ctx context.Context, accountID string) (map[string]time.Time, error)
+	// SetFlag records a step as done at doneAt unless it already is.
+	SetFlag(ctx context.Context, accountID, step string, doneAt time.Time) error
+	// FindDerived returns when the steps that existing records show were
+	// first done.
+	FindDerived(ctx context.Context, accountID string) (map[string]time.Time, error)
+}
+
+type accountSetupRepo struct {
+	db database.Querier
+}
+
+func NewAccountSetupRepository(db *sqlx.DB) AccountSetupRepository {
+	return &accountSetupRepo{db: withRetry(db)}
+}
+
+func (r *accountSetupRepo) FindFlags(ctx context.Context, accountID string) (map[string]time.Time, error) {
+	var rows []struct {
+		Step   string    `db:"step"`
+		DoneAt time.Time `db:"done_at"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT step, done_at FROM account_setup_flags WHERE account_id = $1
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		flags[row.Step] = row.DoneAt
+	}
+	return flags, nil
+}
+
+func (r *accountSetupRepo) SetFlag(ctx context.Context, accountID, step string, doneAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO account_setup_flags (account_id, step, done_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (account_id, step) DO NOTHING
+	`, accountID, step, doneAt)
+	return err
+}
+
+func (r *accountSetupRepo) FindDerived(ctx context.Context, accountID string) (map[string]time.Time, error) {
+	var row struct {
+		SessionCreated *time.Time `db:"session_created"`
+		Paired         *time.Time `db:"paired"`
+		FirstMessage   *time.Time `db:"first_message"`
+		FirstReply     *time.Time `db:"first_reply"`
+	}
+	// Portal test messages do not count as a first message
+	err := r.db.GetContext(ctx, &row, `
+		SELECT
+			(SELECT MIN(created_at) FROM sessions WHERE account_id = $1) AS session_created,
+			(SELECT MIN(paired_at) FROM conversation_mappings WHERE account_id = $1) AS paired,
+			(SELECT MIN(created_at) FROM inbound_messages
+				WHERE account_id = $1 AND conversation_key NOT LIKE 'relay-test:%') AS first_message,
+			(SELECT MIN(created_at) FROM outbound_messages
+				WHERE account_id = $1 AND status = 'sent') AS first_reply
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	derived := map[string]time.Time{}
+	for step, at := range map[string]*time.Time{
+		model.SetupStepSessionCreated: row.SessionCreated,
+		model.SetupStepPaired:         row.Paired,
+		model.SetupStepFirstMessage:   row.FirstMessage,
+		model.SetupStepFirstReply:     row.FirstReply,
+	} {
+		if at != nil {
+			derived[step] = *at
+		}
+	}
+	return derived, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+// accountConfigVersion is the export format version; imports of other
+// versions are rejected.
+const accountConfigVersion = 1
+
+// AccountConfig is the portable configuration of an account, used to move an
+// account between relays (for example from the hosted relay to a self-hosted
+// one). Messages, tokens and pairings are not included.
+type AccountConfig struct {
+	Version    int                   `json:"version"`
+	ExportedAt time.Time             `json:"exportedAt"`
+	Settings   AccountConfigSettings `json:"settings"`
+	Labels     []ConversationLabel   `json:"labels"`
+}
+
+type AccountConfigSettings struct {
+	Mode               model.AccountMode `json:"mode"`
+	RateLimitPerMinute int               `json:"rateLimitPerMinute"`
+	KakaoChannelID     *string           `json:"kakaoChannelId"`
+}
+
+// ConversationLabel is the user-assigned nickname and notes of a connection.
+type ConversationLabel struct {
+	ConversationKey string `json:"conversationKey"`
+	Nickname        string `json:"nickname,omitempty"`
+	Notes           string `json:"notes,omitempty"`
+}
+
+// AccountConfigImportResult reports what an import changed. Labels of
+// conversations not connected to the target account are skipped since
+// connections cannot be created without pairing.
+type AccountConfigImportResult struct {
+	SettingsApplied bool     `json:"settingsApplied"`
+	LabelsApplied   int      `json:"labelsApplied"`
+	LabelsSkipped   []string `json:"labelsSkipped"`
+}
+
+type AccountConfigService struct {
+	accountRepo repository.AccountRepository
+	convRepo    repository.ConversationRepository
+}
+
+func NewAccountConfigService(
+	accountRepo repository.AccountRepository,
+	convRepo repository.ConversationRepository,
+) *AccountConfigService {
+	return &AccountConfigService{
+		accountRepo: accountRepo,
+		convRepo:    convRepo,
+	}
+}
+
+func (s *AccountConfigService) Export(ctx context.Context, accountID string) (*AccountConfig, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	convs, err := s.convRepo.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find conversations: %w", err)
+	}
+
+	labels := []ConversationLabel{}
+	for _, conv := range convs {
+		if conv.Nickname == nil && conv.Notes == nil {
+			continue
+		}
+		label := ConversationLabel{ConversationKey: conv.ConversationKey}
+		if conv.Nickname != nil {
+			label.Nickname = *conv.Nickname
+		}
+		if conv.Notes != nil {
+			label.Notes = *conv.Notes
+		}
+		labels = append(labels, label)
+	}
+
+	return &AccountConfig{
+		Version:    accountConfigVersion,
+		ExportedAt: time.Now(),
+		Settings: AccountConfigSettings{
+			Mode:               account.Mode,
+			RateLimitPerMinute: account.RateLimitPerMin,
+			KakaoChannelID:     account.KakaoChannelID,
+		},
+		Labels: labels,
+	}, nil
+}
+
+// Import applies an exported configuration to the account. Settings are only
+// applied when applySettings is set, since mode and rate limit are managed by
+// the relay operator rather than the account owner.
+func (s *AccountConfigService) Import(ctx context.Context, accountID string, cfg AccountConfig, applySettings bool) (*AccountConfigImportResult, error) {
+	if err := validateAccountConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	result := &AccountConfigImportResult{LabelsSkipped: []string{}}
+
+	if applySettings {
+		mode := cfg.Settings.Mode
+		rateLimit := cfg.Settings.RateLimitPerMinute
+		if _, err := s.accountRepo.Update(ctx, accountID, model.UpdateAccountParams{
+			Mode:            &mode,
+			RateLimitPerMin: &rateLimit,
+			DisabledAt:      account.DisabledAt,
+		}); err != nil {
+			return nil, fmt.Errorf("update account: %w", err)
+		}
+		if _, err := s.accountRepo.UpdateKakaoChannelID(ctx, accountID, cfg.Settings.KakaoChannelID); err != nil {
+			return nil, fmt.Errorf("update kakao channel: %w", err)
+		}
+		result.SettingsApplied = true
+	}
+
+	convs, err := s.convRepo.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find conversations: %w", err)
+	}
+	owned := make(map[string]bool, len(convs))
+	for _, conv := range convs {
+		owned[conv.ConversationKey] = true
+	}
+
+	for _, label := range cfg.Labels {
+		if !owned[label.ConversationKey] {
+			result.LabelsSkipped = append(result.LabelsSkipped, label.ConversationKey)
+			continue
+		}
+		nickname := strings.TrimSpace(label.Nickname)
+		notes := strings.TrimSpace(label.Notes)
+		if err := s.convRepo.UpdateDetails(ctx, label.ConversationKey, optionalString(nickname), optionalString(notes)); err != nil {
+			return nil, fmt.Errorf("update conversation details: %w", err)
+		}
+		result.LabelsApplied++
+	}
+
+	log.Info().
+		Str("accountId", accountID).
+		Bool("settingsApplied", result.SettingsApplied).
+		Int("labelsApplied", result.LabelsApplied).
+		Int("labelsSkipped", len(result.LabelsSkipped)).
+		Msg("account configuration imported")
+
+	return result, nil
+}
+
+// validateAccountConfig checks the whole document before anything is written
+// so a bad import leaves the account unchanged.
+func validateAccountConfig(cfg AccountConfig) error {
+	if cfg.Version != accountConfigVersion {
+		return apperrors.InvalidInput("version", fmt.Sprintf("unsupported configuration version %d", cfg.Version))
+	}
+
+	switch cfg.Settings.Mode {
+	case model.AccountModeDirect, model.AccountModeRelay:
+	default:
+		return apperrors.InvalidInput("settings.mode", "must be direct or relay")
+	}
+	if cfg.Settings.RateLimitPerMinute <= 0 {
+		return apperrors.InvalidInput("settings.rateLimitPerMinute", "must be positive")
+	}
+	if id := cfg.Settings.KakaoChannelID; id != nil && !util.IsValidKakaoChannelID(*id) {
+		return apperrors.InvalidInput("settings.kakaoChannelId", "must be a channel public ID such as _xkAbC")
+	}
+
+	for _, label := range cfg.Labels {
+		if label.ConversationKey == "" {
+			return apperrors.InvalidInput("labels", "conversationKey is required")
+		}
+		if utf8.RuneCountInString(strings.TrimSpace(label.Nickname)) > maxConversationNicknameLen {
+			return apperrors.InvalidInput("labels", fmt.Sprintf("nickname must be at most %d characters", maxConversationNicknameLen))
+		}
+		if utf8.RuneCountInString(strings.TrimSpace(label.Notes)) > maxConversationNotesLen {
+			return apperrors.InvalidInput("labels", fmt.Sprintf("notes must be at most %d characters", maxConversationNotesLen))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestAccountConfigService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Export includes settings and labelled conversations", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{
+			ID: "acc-1", Mode: model.AccountModeRelay, RateLimitPerMin: 30, KakaoChannelID: strPtr("_abc"),
+		}
+		convRepo := new(mockConversationRepo)
+		convRepo.On("FindByAccountID", ctx, "acc-1").Return([]model.ConversationMapping{
+			{ConversationKey: "ch:u1", Nickname: strPtr("Alice")},
+			{ConversationKey: "ch:u2"},
+		}, nil)
+
+		cfg, err := NewAccountConfigService(accountRepo, convRepo).Export(ctx, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, accountConfigVersion, cfg.Version)
+		assert.Equal(t, model.AccountModeRelay, cfg.Settings.Mode)
+		assert.Equal(t, 30, cfg.Settings.RateLimitPerMinute)
+		assert.Equal(t, []ConversationLabel{{ConversationKey: "ch:u1", Nickname: "Alice"}}, cfg.Labels)
+	})
+
+	t.Run("Import applies labels to owned conversations only", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1", Mode: model.AccountModeDirect, RateLimitPerMin: 60}
+		convRepo := new(mockConversationRepo)
+		convRepo.On("FindByAccountID", ctx, "acc-1").Return([]model.ConversationMapping{
+			{ConversationKey: "ch:u1"},
+		}, nil)
+		convRepo.On("UpdateDetails", ctx, "ch:u1", strPtr("Alice"), (*string)(nil)).Return(nil)
+
+		result, err := NewAccountConfigService(accountRepo, convRepo).Import(ctx, "acc-1", AccountConfig{
+			Version:  accountConfigVersion,
+			Settings: AccountConfigSettings{Mode: model.AccountModeRelay, RateLimitPerMinute: 10},
+			Labels: []ConversationLabel{
+				{ConversationKey: "ch:u1", Nickname: " Alice "},
+				{ConversationKey: "ch:other", Nickname: "Bob"},
+			},
+		}, false)
+		require.NoError(t, err)
+		assert.False(t, result.SettingsApplied)
+		assert.Equal(t, 1, result.LabelsApplied)
+		assert.Equal(t, []string{"ch:other"}, result.LabelsSkipped)
+		assert.Equal(t, model.AccountModeDirect, accountRepo.accounts["acc-1"].Mode)
+		convRepo.AssertExpectations(t)
+	})
+
+	t.Run("Import applies settings when allowed", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1", Mode: model.AccountModeDirect, RateLimitPerMin: 60}
+		convRepo := new(mockConversationRepo)
+		convRepo.On("FindByAccountID", ctx, "acc-1").Return([]model.ConversationMapping{}, nil)
+
+		result, err := NewAccountConfigService(accountRepo, convRepo).Import(ctx, "acc-1", AccountConfig{
+			Version:  accountConfigVersion,
+			Settings: AccountConfigSettings{Mode: model.AccountModeRelay, RateLimitPerMinute: 10, KakaoChannelID: strPtr("_abc")},
+		}, true)
+		require.NoError(t, err)
+		assert.True(t, result.SettingsApplied)
+		acc := accountRepo.accounts["acc-1"]
+		assert.Equal(t, model.AccountModeRelay, acc.Mode)
+		assert.Equal(t, 10, acc.RateLimitPerMin)
+		assert.Equal(t, "_abc", *acc.KakaoChannelID)
+	})
+
+	t.Run("Import rejects an unsupported version before writing", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+		convRepo := new(mockConversationRepo)
+
+		_, err := NewAccountConfigService(accountRepo, convRepo).Import(ctx, "acc-1", AccountConfig{Version: 99}, true)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+		convRepo.AssertNotCalled(t, "FindByAccountID", mock.Anything, mock.Anything)
+	})
+}
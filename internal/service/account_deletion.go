@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+// accountDeletionTokenTTL is how long a deletion preview token stays valid
+const accountDeletionTokenTTL = 10 * time.Minute
+
+// accountDeletionGracePeriod is how long a portal deletion request can be
+// cancelled before the account is purged
+const accountDeletionGracePeriod = 7 * 24 * time.Hour
+
+// AccountDeletionPreview is what deleting an account affects, with the token
+// the delete call must present. Deleting without a fresh preview is refused so
+// an account cannot be removed by a stray request.
+type AccountDeletionPreview struct {
+	model.AccountDeletionPreview
+	AccountID    string    `json:"accountId"`
+	PreviewToken string    `json:"previewToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+func previewAccountDeletion(ctx context.Context, accountRepo repository.AccountRepository, secret, accountID string) (*AccountDeletionPreview, error) {
+	account, err := accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	counts, err := accountRepo.DeletionPreview(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account deletion preview: %w", err)
+	}
+
+	expiresAt := time.Now().Add(accountDeletionTokenTTL).Truncate(time.Second)
+	return &AccountDeletionPreview{
+		AccountDeletionPreview: *counts,
+		AccountID:              accountID,
+		PreviewToken:           signAccountDeletion(secret, accountID, expiresAt),
+		ExpiresAt:              expiresAt,
+	}, nil
+}
+
+// signAccountDeletion returns "<unix expiry>.<hmac>" bound to the account.
+func signAccountDeletion(secret, accountID string, expiresAt time.Time) string {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + util.HmacSHA256(secret, "account-delete:"+accountID+":"+exp)
+}
+
+func verifyAccountDeletionToken(secret, accountID, token string) error {
+	exp, _, ok := strings.Cut(token, ".")
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if !ok || err != nil || !util.ConstantTimeEqual(token, signAccountDeletion(secret, accountID, time.Unix(unix, 0))) {
+		return apperrors.InvalidInput("previewToken", "is missing or invalid; request a deletion preview first")
+	}
+	if time.Now().After(time.Unix(unix, 0)) {
+		return apperrors.InvalidInput("previewToken", "has expired; request a new deletion preview")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+)
+
+func TestAccountDeletionToken(t *testing.T) {
+	secret := "test-secret"
+
+	t.Run("accepts a fresh token for the same account", func(t *testing.T) {
+		token := signAccountDeletion(secret, "acc-1", time.Now().Add(time.Minute))
+
+		assert.NoError(t, verifyAccountDeletionToken(secret, "acc-1", token))
+	})
+
+	t.Run("rejects a token for another account", func(t *testing.T) {
+		token := signAccountDeletion(secret, "acc-1", time.Now().Add(time.Minute))
+
+		err := verifyAccountDeletionToken(secret, "acc-2", token)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		token := signAccountDeletion(secret, "acc-1", time.Now().Add(-time.Second))
+
+		err := verifyAccountDeletionToken(secret, "acc-1", token)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	})
+
+	t.Run("rejects missing and malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"", "abc", "123.deadbeef"} {
+			err := verifyAccountDeletionToken(secret, "acc-1", token)
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), token)
+		}
+	})
+}
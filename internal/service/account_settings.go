@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+const (
+	maxMessageRetentionDays = 365
+	maxAutoReplyTextLen     = 1000
+)
+
+// Account settings. Features read them with Get, which returns the default
+// when the account has not changed a setting.
+var (
+	// SettingLocale is the language of relay messages sent to the account's
+	// Kakao users; empty follows the deployment default.
+	SettingLocale = AccountSetting[string]{
+		Key: "locale",
+		Normalize: func(v string) (string, error) {
+			if v == "" {
+				return v, nil
+			}
+			locale, ok := i18n.ParseLocale(v)
+			if !ok {
+				return "", fmt.Errorf("must be ko or en")
+			}
+			return string(locale), nil
+		},
+	}
+	// SettingMessageRetentionDays overrides how long the account's messages
+	// are kept; 0 uses the relay's retention. Managed by the operator.
+	SettingMessageRetentionDays = AccountSetting[int]{
+		Key:       "messageRetentionDays",
+		AdminOnly: true,
+		Normalize: func(v int) (int, error) {
+			if v < 0 || v > maxMessageRetentionDays {
+				return 0, fmt.Errorf("must be between 0 and %d", maxMessageRetentionDays)
+			}
+			return v, nil
+		},
+	}
+	// SettingAutoReplyText is replied to Kakao users when no plugin is
+	// connected; empty turns the auto-reply off.
+	SettingAutoReplyText = AccountSetting[string]{
+		Key: "autoReplyText",
+		Normalize: func(v string) (string, error) {
+			v = strings.TrimSpace(v)
+			if utf8.RuneCountInString(v) > maxAutoReplyTextLen {
+				return "", fmt.Errorf("must be at most %d characters", maxAutoReplyTextLen)
+			}
+			return v, nil
+		},
+	}
+)
+
+// accountSettings lists the known settings in the order they are listed.
+var accountSettings = []accountSettingDefinition{
+	SettingLocale,
+	SettingMessageRetentionDays,
+	SettingAutoReplyText,
+}
+
+// accountSettingDefinition is the part of an AccountSetting the service needs
+// without knowing its value type.
+type accountSettingDefinition interface {
+	key() string
+	adminOnly() bool
+	defaultValue() json.RawMessage
+	// normalize validates a JSON value and returns it in stored form.
+	normalize(raw json.RawMessage) (json.RawMessage, error)
+}
+
+// AccountSetting defines a per-account setting of type T. Normalize, when
+// set, validates a new value and returns the form to store.
+type AccountSetting[T any] struct {
+	Key     string
+	Default T
+	// AdminOnly settings can only be changed by relay admins; account owners
+	// see them read-only.
+	AdminOnly bool
+	Normalize func(T) (T, error)
+}
+
+// Get returns the account's value of the setting, or its default when the
+// account has none or the stored value is no longer valid.
+func (d AccountSetting[T]) Get(ctx context.Context, s *AccountSettingsService, accountID string) (T, error) {
+	stored, err := s.settingsRepo.Find(ctx, accountID)
+	if err != nil {
+		return d.Default, fmt.Errorf("find account settings: %w", err)
+	}
+	raw, ok := stored[d.Key]
+	if !ok {
+		return d.Default, nil
+	}
+	value, err := d.decode(raw)
+	if err != nil {
+		log.Warn().Err(err).Str("accountId", accountID).Str("key", d.Key).Msg("invalid account setting, using default")
+		return d.Default, nil
+	}
+	return value, nil
+}
+
+func (d AccountSetting[T]) decode(raw json.RawMessage) (T, error) {
+	var value T
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&value); err != nil {
+		return d.Default, fmt.Errorf("has the wrong type")
+	}
+	if d.Normalize != nil {
+		return d.Normalize(value)
+	}
+	return value, nil
+}
+
+func (d AccountSetting[T]) key() string     { return d.Key }
+func (d AccountSetting[T]) adminOnly() bool { return d.AdminOnly }
+
+func (d AccountSetting[T]) defaultValue() json.RawMessage {
+	data, _ := json.Marshal(d.Default)
+	return data
+}
+
+func (d AccountSetting[T]) normalize(raw json.RawMessage) (json.RawMessage, error) {
+	value, err := d.decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// AccountSettingValue is a setting as listed to portal users and admins.
+type AccountSettingValue struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Default   json.RawMessage `json:"default"`
+	IsDefault bool            `json:"isDefault"`
+	AdminOnly bool            `json:"adminOnly"`
+}
+
+// AccountSettingChange is a setting whose value an update changed. A nil
+// value means the default was in effect.
+type AccountSettingChange struct {
+	Key      string          `json:"key"`
+	OldValue json.RawMessage `json:"oldValue"`
+	NewValue json.RawMessage `json:"newValue"`
+}
+
+// AccountSettingsService stores per-account settings. Settings are defined in
+// code with a type, default and validation, so features only read the
+// settings they know and accounts only store values that were valid.
+type AccountSettingsService struct {
+	accountRepo  repository.AccountRepository
+	settingsRepo repository.AccountSettingsRepository
+}
+
+func NewAccountSettingsService(
+	accountRepo repository.AccountRepository,
+	settingsRepo repository.AccountSettingsRepository,
+) *AccountSettingsService {
+	return &AccountSettingsService{accountRepo: accountRepo, settingsRepo: settingsRepo}
+}
+
+// List returns every known setting with the account's value.
+func (s *AccountSettingsService) List(ctx context.Context, accountID string) ([]AccountSettingValue, error) {
+	if err := s.requireAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	stored, err := s.settingsRepo.Find(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account settings: %w", err)
+	}
+	return listAccountSettings(stored), nil
+}
+
+// Update applies changes by setting key; a null value resets the setting to
+// its default. Unless admin is set, admin-only settings are rejected. Nothing
+// is stored when any value is invalid.
+func (s *AccountSettingsService) Update(
+	ctx context.Context,
+	accountID string,
+	changes map[string]json.RawMessage,
+	admin bool,
+) ([]AccountSettingValue, []AccountSettingChange, error) {
+	set := map[string]json.RawMessage{}
+	var unset []string
+	for key, raw := range changes {
+		def := findAccountSetting(key)
+		if def == nil {
+			return nil, nil, apperrors.InvalidInput(key, "unknown setting")
+		}
+		if def.adminOnly() && !admin {
+			return nil, nil, apperrors.InvalidInput(key, "can only be changed by an admin")
+		}
+		if raw == nil || string(raw) == "null" {
+			unset = append(unset, key)
+			continue
+		}
+		value, err := def.normalize(raw)
+		if err != nil {
+			return nil, nil, apperrors.InvalidInput(key, err.Error())
+		}
+		set[key] = value
+	}
+
+	if err := s.requireAccount(ctx, accountID); err != nil {
+		return nil, nil, err
+	}
+	prev, stored, err := s.settingsRepo.Update(ctx, accountID, set, unset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("update account settings: %w", err)
+	}
+
+	changed := diffAccountSettings(prev, stored)
+	if len(changed) > 0 {
+		keys := make([]string, len(changed))
+		for i, c := range changed {
+			keys[i] = c.Key
+		}
+		log.Info().Str("accountId", accountID).Strs("keys", keys).Bool("admin", admin).Msg("account settings updated")
+	}
+	return listAccountSettings(stored), changed, nil
+}
+
+func (s *AccountSettingsService) requireAccount(ctx context.Context, accountID string) error {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return apperrors.NotFound("Account")
+	}
+	return nil
+}
+
+func findAccountSetting(key string) accountSettingDefinition {
+	for _, def := range accountSettings {
+		if def.key() == key {
+			return def
+		}
+	}
+	return nil
+}
+
+func listAccountSettings(stored map[string]json.RawMessage) []AccountSettingValue {
+	values := make([]AccountSettingValue, 0, len(accountSettings))
+	for _, def := range accountSettings {
+		value := AccountSettingValue{
+			Key:       def.key(),
+			Default:   def.defaultValue(),
+			AdminOnly: def.adminOnly(),
+		}
+		if raw, ok := stored[def.key()]; ok {
+			value.Value = raw
+		} else {
+			value.Value = value.Default
+			value.IsDefault = true
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// diffAccountSettings returns the settings whose stored value differs, by
+// key.
+func diffAccountSettings(prev, stored map[string]json.RawMessage) []AccountSettingChange {
+	keys := map[string]bool{}
+	for key := range prev {
+		keys[key] = true
+	}
+	for key := range stored {
+		keys[key] = true
+	}
+
+	var changes []AccountSettingChange
+	for key := range keys {
+		oldValue, newValue := prev[key], stored[key]
+		if bytes.Equal(compactJSON(oldValue), compactJSON(newValue)) {
+			continue
+		}
+		changes = append(changes, AccountSettingChange{Key: key, OldValue: oldValue, NewValue: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+func compactJSON(raw json.RawMessage) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
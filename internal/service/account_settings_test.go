@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type mockAccountSettingsRepo struct {
+	settings map[string]map[string]json.RawMessage
+}
+
+func newMockAccountSettingsRepo() *mockAccountSettingsRepo {
+	return &mockAccountSettingsRepo{settings: map[string]map[string]json.RawMessage{}}
+}
+
+func (m *mockAccountSettingsRepo) Find(ctx context.Context, accountID string) (map[string]json.RawMessage, error) {
+	stored := map[string]json.RawMessage{}
+	for k, v := range m.settings[accountID] {
+		stored[k] = v
+	}
+	return stored, nil
+}
+
+func (m *mockAccountSettingsRepo) Update(ctx context.Context, accountID string, set map[string]json.RawMessage, unset []string) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	prev, _ := m.Find(ctx, accountID)
+	stored, _ := m.Find(ctx, accountID)
+	for k, v := range set {
+		stored[k] = v
+	}
+	for _, k := range unset {
+		delete(stored, k)
+	}
+	m.settings[accountID] = stored
+	return prev, stored, nil
+}
+
+func TestAccountSettingsService(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+	settingsRepo := newMockAccountSettingsRepo()
+	svc := NewAccountSettingsService(accountRepo, settingsRepo)
+
+	valueOf := func(settings []AccountSettingValue, key string) AccountSettingValue {
+		for _, s := range settings {
+			if s.Key == key {
+				return s
+			}
+		}
+		t.Fatalf("setting %s not listed", key)
+		return AccountSettingValue{}
+	}
+
+	t.Run("lists defaults when nothing is stored", func(t *testing.T) {
+		settings, err := svc.List(ctx, "acc-1")
+		require.NoError(t, err)
+		require.Len(t, settings, len(accountSettings))
+		locale := valueOf(settings, "locale")
+		assert.True(t, locale.IsDefault)
+		assert.JSONEq(t, `""`, string(locale.Value))
+		assert.True(t, valueOf(settings, "messageRetentionDays").AdminOnly)
+	})
+
+	t.Run("update normalizes values and reports changes", func(t *testing.T) {
+		settings, changes, err := svc.Update(ctx, "acc-1", map[string]json.RawMessage{
+			"locale":        json.RawMessage(`"en-US"`),
+			"autoReplyText": json.RawMessage(`"  Back soon  "`),
+		}, false)
+		require.NoError(t, err)
+		assert.JSONEq(t, `"en"`, string(valueOf(settings, "locale").Value))
+		assert.False(t, valueOf(settings, "locale").IsDefault)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "autoReplyText", changes[0].Key)
+		assert.Nil(t, changes[0].OldValue)
+		assert.JSONEq(t, `"Back soon"`, string(changes[0].NewValue))
+
+		locale, err := SettingLocale.Get(ctx, svc, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "en", locale)
+	})
+
+	t.Run("unchanged values are not reported", func(t *testing.T) {
+		_, changes, err := svc.Update(ctx, "acc-1", map[string]json.RawMessage{"locale": json.RawMessage(`"en"`)}, false)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("null resets to the default", func(t *testing.T) {
+		_, changes, err := svc.Update(ctx, "acc-1", map[string]json.RawMessage{"locale": json.RawMessage(`null`)}, false)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Nil(t, changes[0].NewValue)
+
+		locale, err := SettingLocale.Get(ctx, svc, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "", locale)
+	})
+
+	t.Run("admin-only settings", func(t *testing.T) {
+		change := map[string]json.RawMessage{"messageRetentionDays": json.RawMessage(`30`)}
+		_, _, err := svc.Update(ctx, "acc-1", change, false)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+
+		_, _, err = svc.Update(ctx, "acc-1", change, true)
+		require.NoError(t, err)
+		days, err := SettingMessageRetentionDays.Get(ctx, svc, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, 30, days)
+	})
+
+	t.Run("rejects invalid changes without storing any", func(t *testing.T) {
+		valid := json.RawMessage(`"ko"`)
+		for name, change := range map[string]map[string]json.RawMessage{
+			"unknown key":   {"locale": valid, "theme": json.RawMessage(`"dark"`)},
+			"wrong type":    {"locale": valid, "autoReplyText": json.RawMessage(`42`)},
+			"too long":      {"locale": valid, "autoReplyText": json.RawMessage(`"` + strings.Repeat("a", maxAutoReplyTextLen+1) + `"`)},
+			"out of range":  {"locale": valid, "messageRetentionDays": json.RawMessage(`-1`)},
+			"invalid value": {"locale": json.RawMessage(`"fr"`)},
+		} {
+			_, _, err := svc.Update(ctx, "acc-1", change, true)
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), name)
+		}
+		locale, err := SettingLocale.Get(ctx, svc, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "", locale)
+	})
+
+	t.Run("invalid stored values fall back to defaults", func(t *testing.T) {
+		settingsRepo.settings["acc-2"] = map[string]json.RawMessage{"messageRetentionDays": json.RawMessage(`"forever"`)}
+		days, err := SettingMessageRetentionDays.Get(ctx, svc, "acc-2")
+		require.NoError(t, err)
+		assert.Equal(t, 0, days)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := svc.List(ctx, "missing")
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+}
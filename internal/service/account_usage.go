@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// AccountUsageService reports how much of its API rate limit an account has
+// left, for showing to the user in chat, and whether the account still
+// accepts messages.
+type AccountUsageService struct {
+	accountRepo repository.AccountRepository
+	limiter     ratelimit.Limiter
+}
+
+func NewAccountUsageService(accountRepo repository.AccountRepository, limiter ratelimit.Limiter) *AccountUsageService {
+	return &AccountUsageService{accountRepo: accountRepo, limiter: limiter}
+}
+
+// GetRateLimit returns the account's rate limit bucket without using it.
+func (s *AccountUsageService) GetRateLimit(ctx context.Context, accountID string) (*ratelimit.Result, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+
+	result := s.limiter.Peek(ctx, ratelimit.AccountScope, account.ID, ratelimit.AccountBucket(account.RateLimitPerMin))
+	return &result, nil
+}
+
+// Suspended reports whether messages for the account must be refused: it is
+// disabled, for example while its deletion is pending, or no longer exists.
+func (s *AccountUsageService) Suspended(ctx context.Context, accountID string) (bool, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("find account: %w", err)
+	}
+	return account == nil || account.DisabledAt != nil, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
+)
+
+func TestAccountUsageService_GetRateLimit(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1", RateLimitPerMin: 10}
+	accountRepo.accounts["acc-2"] = &model.Account{ID: "acc-2"}
+	limiter := ratelimit.NewMemoryLimiter()
+	svc := NewAccountUsageService(accountRepo, limiter)
+
+	limiter.Take(ctx, ratelimit.AccountScope, "acc-1", ratelimit.AccountBucket(10))
+	limiter.Take(ctx, ratelimit.AccountScope, "acc-1", ratelimit.AccountBucket(10))
+
+	result, err := svc.GetRateLimit(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.Limit)
+	assert.Equal(t, 8, result.Remaining)
+
+	t.Run("unset limit uses the default", func(t *testing.T) {
+		result, err := svc.GetRateLimit(ctx, "acc-2")
+		require.NoError(t, err)
+		assert.Equal(t, 60, result.Limit)
+		assert.Equal(t, 60, result.Remaining)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := svc.GetRateLimit(ctx, "missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestAccountUsageService_Suspended(t *testing.T) {
+	ctx := context.Background()
+	disabledAt := time.Now()
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+	accountRepo.accounts["acc-2"] = &model.Account{ID: "acc-2", DisabledAt: &disabledAt}
+	svc := NewAccountUsageService(accountRepo, ratelimit.NewMemoryLimiter())
+
+	for accountID, want := range map[string]bool{"acc-1": false, "acc-2": true, "missing": true} {
+		suspended, err := svc.Suspended(ctx, accountID)
+		require.NoError(t, err)
+		assert.Equal(t, want, suspended, accountID)
+	}
+}
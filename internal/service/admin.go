@@ -9,6 +9,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/util"
@@ -37,11 +38,24 @@ func (qb *queryBuilder) addCondition(column string, value interface{}) {
 	qb.conditions = append(qb.conditions, fmt.Sprintf("%s = $%d", column, len(qb.args)))
 }
 
-func (qb *queryBuilder) buildSelect(table string, limit, offset int) (selectQuery, countQuery string, args []interface{}) {
-	whereClause := ""
-	if len(qb.conditions) > 0 {
-		whereClause = " WHERE " + strings.Join(qb.conditions, " AND ")
+// addTimeBound adds "column op value"; a nil value leaves the query unchanged.
+func (qb *queryBuilder) addTimeBound(column, op string, value *time.Time) {
+	if value == nil {
+		return
 	}
+	qb.args = append(qb.args, *value)
+	qb.conditions = append(qb.conditions, fmt.Sprintf("%s %s $%d", column, op, len(qb.args)))
+}
+
+func (qb *queryBuilder) where() string {
+	if len(qb.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(qb.conditions, " AND ")
+}
+
+func (qb *queryBuilder) buildSelect(table string, limit, offset int) (selectQuery, countQuery string, args []interface{}) {
+	whereClause := qb.where()
 
 	countQuery = fmt.Sprintf("SELECT COUNT(*) FROM %s%s", table, whereClause)
 
@@ -65,8 +79,17 @@ type AdminService struct {
 	outboundRepo      repository.OutboundMessageRepository
 	portalUserRepo    repository.PortalUserRepository
 	pluginSessionRepo repository.SessionRepository
-	adminPasswordHash string
+	experimentRepo    repository.ExperimentRepository
+	events            *SessionEvents
+	deployment        *DeploymentService
 	sessionSecret     string
+	sessionMaxAge     time.Duration
+}
+
+// AdminClient identifies the client an admin session is created for.
+type AdminClient struct {
+	IPAddress string
+	UserAgent string
 }
 
 func NewAdminService(
@@ -78,7 +101,11 @@ func NewAdminService(
 	outboundRepo repository.OutboundMessageRepository,
 	portalUserRepo repository.PortalUserRepository,
 	pluginSessionRepo repository.SessionRepository,
-	adminPasswordHash, sessionSecret string,
+	experimentRepo repository.ExperimentRepository,
+	events *SessionEvents,
+	deployment *DeploymentService,
+	sessionSecret string,
+	sessionMaxAge time.Duration,
 ) *AdminService {
 	return &AdminService{
 		db:                db,
@@ -89,27 +116,76 @@ func NewAdminService(
 		outboundRepo:      outboundRepo,
 		portalUserRepo:    portalUserRepo,
 		pluginSessionRepo: pluginSessionRepo,
-		adminPasswordHash: adminPasswordHash,
+		experimentRepo:    experimentRepo,
+		events:            events,
+		deployment:        deployment,
 		sessionSecret:     sessionSecret,
+		sessionMaxAge:     sessionMaxAge,
 	}
 }
 
-func (s *AdminService) Login(ctx context.Context, password string) (string, error) {
-	if !util.CheckPasswordHash(password, s.adminPasswordHash) {
+func (s *AdminService) Login(ctx context.Context, password string, client AdminClient) (string, error) {
+	ok, err := s.deployment.CheckAdminPassword(ctx, password)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
 		return "", nil
 	}
+	return s.createSession(ctx, client)
+}
+
+// Reauthenticate confirms the admin password within an existing session so
+// that step-up protected actions are allowed again.
+func (s *AdminService) Reauthenticate(ctx context.Context, sessionID, password string) error {
+	ok, err := s.deployment.CheckAdminPassword(ctx, password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.Unauthorized("Invalid password")
+	}
+	if err := s.sessionRepo.MarkAuthenticated(ctx, sessionID); err != nil {
+		return fmt.Errorf("mark admin session authenticated: %w", err)
+	}
+	return nil
+}
+
+// SessionMaxAge is the absolute lifetime of an admin session.
+func (s *AdminService) SessionMaxAge() time.Duration {
+	return s.sessionMaxAge
+}
+
+// ChangePassword replaces the admin password and ends every admin session,
+// returning a new session token for the caller.
+func (s *AdminService) ChangePassword(ctx context.Context, currentPassword, newPassword string, client AdminClient) (string, error) {
+	if err := s.deployment.ChangeAdminPassword(ctx, currentPassword, newPassword); err != nil {
+		return "", err
+	}
+
+	revoked, err := s.sessionRepo.DeleteAll(ctx)
+	if err != nil {
+		return "", fmt.Errorf("revoke admin sessions: %w", err)
+	}
+	log.Info().Int64("sessions", revoked).Msg("admin sessions revoked after password change")
+
+	return s.createSession(ctx, client)
+}
 
+func (s *AdminService) createSession(ctx context.Context, client AdminClient) (string, error) {
 	token, err := util.GenerateToken()
 	if err != nil {
 		return "", err
 	}
 
 	tokenHash := util.HmacSHA256(s.sessionSecret, token)
-	expiresAt := time.Now().Add(24 * time.Hour)
+	expiresAt := time.Now().Add(s.sessionMaxAge)
 
 	_, err = s.sessionRepo.Create(ctx, model.CreateAdminSessionParams{
 		TokenHash: tokenHash,
 		ExpiresAt: expiresAt,
+		IPAddress: optionalString(client.IPAddress),
+		UserAgent: optionalString(client.UserAgent),
 	})
 	if err != nil {
 		return "", err
@@ -123,6 +199,23 @@ func (s *AdminService) Logout(ctx context.Context, token string) error {
 	return s.sessionRepo.DeleteByTokenHash(ctx, tokenHash)
 }
 
+// ListAdminSessions returns the unexpired admin sessions, most recently
+// active first.
+func (s *AdminService) ListAdminSessions(ctx context.Context) ([]model.AdminSession, error) {
+	sessions, err := s.sessionRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list admin sessions: %w", err)
+	}
+	if sessions == nil {
+		sessions = []model.AdminSession{}
+	}
+	return sessions, nil
+}
+
+func (s *AdminService) RevokeAdminSession(ctx context.Context, id string) error {
+	return s.sessionRepo.Delete(ctx, id)
+}
+
 func (s *AdminService) ValidateSession(ctx context.Context, token string) bool {
 	tokenHash := util.HmacSHA256(s.sessionSecret, token)
 	session, err := s.sessionRepo.FindByTokenHash(ctx, tokenHash)
@@ -149,6 +242,7 @@ type Stats struct {
 			Failed int `json:"failed"`
 		} `json:"outbound"`
 	} `json:"messages"`
+	Experiments []model.ExperimentVariantStats `json:"experiments"`
 }
 
 func (s *AdminService) GetStats(ctx context.Context) (*Stats, error) {
@@ -172,13 +266,35 @@ func (s *AdminService) GetStats(ctx context.Context) (*Stats, error) {
 	}
 	stats.Messages.Inbound.Queued = queuedCount
 
-	// Session stats
-	var sessionStats struct {
-		Pending int `db:"pending"`
-		Paired  int `db:"paired"`
-		Total   int `db:"total"`
+	sessionStats, err := s.countSessions(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to get session stats")
 	}
-	err = s.db.GetContext(ctx, &sessionStats, `
+	stats.Sessions.Pending = sessionStats.Pending
+	stats.Sessions.Paired = sessionStats.Paired
+	stats.Sessions.Total = sessionStats.Total
+
+	experiments, err := s.experimentRepo.GetVariantStats(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to get experiment stats")
+	}
+	stats.Experiments = experiments
+	if stats.Experiments == nil {
+		stats.Experiments = []model.ExperimentVariantStats{}
+	}
+
+	return stats, nil
+}
+
+type sessionCounts struct {
+	Pending int `db:"pending"`
+	Paired  int `db:"paired"`
+	Total   int `db:"total"`
+}
+
+func (s *AdminService) countSessions(ctx context.Context) (sessionCounts, error) {
+	var counts sessionCounts
+	err := s.db.GetContext(ctx, &counts, `
 		SELECT
 			COUNT(*) FILTER (WHERE status = 'pending_pairing') as pending,
 			COUNT(*) FILTER (WHERE status = 'paired') as paired,
@@ -186,13 +302,9 @@ func (s *AdminService) GetStats(ctx context.Context) (*Stats, error) {
 		FROM sessions
 	`)
 	if err != nil {
-		log.Warn().Err(err).Msg("failed to get session stats")
+		return sessionCounts{}, fmt.Errorf("count sessions: %w", err)
 	}
-	stats.Sessions.Pending = sessionStats.Pending
-	stats.Sessions.Paired = sessionStats.Paired
-	stats.Sessions.Total = sessionStats.Total
-
-	return stats, nil
+	return counts, nil
 }
 
 func (s *AdminService) CreateAccount(ctx context.Context, openclawUserID *string, mode model.AccountMode, rateLimit int) (*model.Account, string, error) {
@@ -232,66 +344,111 @@ func (s *AdminService) RegenerateToken(ctx context.Context, accountID string) (s
 		return "", err
 	}
 
+	s.events.TokenRegenerated(ctx, accountID, "admin")
+
 	return token, nil
 }
 
-func (s *AdminService) GetAccounts(ctx context.Context, limit, offset int) ([]model.Account, error) {
-	return s.accountRepo.FindAll(ctx, limit, offset)
+func (s *AdminService) GetAccounts(ctx context.Context, filter model.AccountFilter, limit, offset int) ([]model.Account, int, error) {
+	accounts, err := s.accountRepo.FindAll(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.accountRepo.CountFiltered(ctx, filter)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to get accounts count")
+	}
+
+	return accounts, total, nil
 }
 
 func (s *AdminService) GetAccountByID(ctx context.Context, id string) (*model.Account, error) {
 	return s.accountRepo.FindByID(ctx, id)
 }
 
-func (s *AdminService) DeleteAccount(ctx context.Context, id string) error {
+// UpdateAccountKakaoChannel sets the channel used for the account's chat
+// links; an empty channelID clears it so the deployment default applies.
+func (s *AdminService) UpdateAccountKakaoChannel(ctx context.Context, id, channelID string) (*model.Account, error) {
+	if channelID == "" {
+		return s.accountRepo.UpdateKakaoChannelID(ctx, id, nil)
+	}
+	if !util.IsValidKakaoChannelID(channelID) {
+		return nil, apperrors.InvalidInput("kakaoChannelId", "must be a channel public ID such as _xkAbC")
+	}
+	return s.accountRepo.UpdateKakaoChannelID(ctx, id, &channelID)
+}
+
+// PreviewDeleteAccount reports what deleting the account affects and issues
+// the token DeleteAccount requires.
+func (s *AdminService) PreviewDeleteAccount(ctx context.Context, id string) (*AccountDeletionPreview, error) {
+	return previewAccountDeletion(ctx, s.accountRepo, s.sessionSecret, id)
+}
+
+func (s *AdminService) DeleteAccount(ctx context.Context, id, previewToken string) error {
+	if err := verifyAccountDeletionToken(s.sessionSecret, id, previewToken); err != nil {
+		return err
+	}
+	if err := checkAccountLegalHold(ctx, s.accountRepo, id); err != nil {
+		return err
+	}
 	return s.accountRepo.Delete(ctx, id)
 }
 
 // Mappings
 
-func (s *AdminService) GetMappings(ctx context.Context, limit, offset int, accountID string) ([]model.ConversationMapping, int, error) {
-	var mappings []model.ConversationMapping
-	var total int
-	var err error
-
-	if accountID != "" {
-		mappings, err = s.convRepo.FindByAccountID(ctx, accountID)
-		total = len(mappings)
-	} else {
-		err = s.db.SelectContext(ctx, &mappings, `
-			SELECT * FROM conversation_mappings
-			ORDER BY first_seen_at DESC
-			LIMIT $1 OFFSET $2
-		`, limit, offset)
-		if err == nil {
-			if countErr := s.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM conversation_mappings`); countErr != nil {
-				log.Warn().Err(countErr).Msg("failed to get mappings count")
-			}
-		}
-	}
+// AdminMapping is a conversation mapping with its account's details joined
+// in, so operators don't have to look them up by ID.
+type AdminMapping struct {
+	model.ConversationMapping
+	AccountMode *model.AccountMode `db:"account_mode" json:"accountMode,omitempty"`
+	// Email of the account's earliest portal user, if it has one
+	UserEmail *string `db:"user_email" json:"userEmail,omitempty"`
+}
 
+func (s *AdminService) GetMappings(ctx context.Context, filter model.MappingFilter, limit, offset int) ([]AdminMapping, int, error) {
+	qb := newQueryBuilder()
+	qb.addCondition("m.account_id", filter.AccountID)
+	qb.addCondition("m.state", string(filter.State))
+	qb.addCondition("m.kakao_channel_id", filter.KakaoChannelID)
+	qb.addTimeBound("m.last_seen_at", ">=", filter.LastSeenAfter)
+	qb.addTimeBound("m.last_seen_at", "<", filter.LastSeenBefore)
+	whereClause := qb.where()
+
+	mappings := []AdminMapping{}
+	err := s.db.SelectContext(ctx, &mappings, fmt.Sprintf(`
+		SELECT m.*, a.mode AS account_mode, (
+			SELECT u.email FROM portal_users u
+			WHERE u.account_id = m.account_id
+			ORDER BY u.created_at
+			LIMIT 1
+		) AS user_email
+		FROM conversation_mappings m
+		LEFT JOIN accounts a ON a.id = m.account_id%s
+		ORDER BY m.first_seen_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, len(qb.args)+1, len(qb.args)+2), append(qb.args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Apply pagination for accountID case
-	if accountID != "" && len(mappings) > 0 {
-		start := offset
-		end := offset + limit
-		if start > len(mappings) {
-			mappings = []model.ConversationMapping{}
-		} else {
-			if end > len(mappings) {
-				end = len(mappings)
-			}
-			mappings = mappings[start:end]
-		}
+	var total int
+	if countErr := s.db.GetContext(ctx, &total,
+		"SELECT COUNT(*) FROM conversation_mappings m"+whereClause, qb.args...); countErr != nil {
+		log.Warn().Err(countErr).Msg("failed to get mappings count")
 	}
 
 	return mappings, total, nil
 }
 
 func (s *AdminService) DeleteMapping(ctx context.Context, id string) error {
+	held, err := s.convRepo.IsUnderLegalHold(ctx, id)
+	if err != nil {
+		return fmt.Errorf("check legal hold: %w", err)
+	}
+	if held {
+		return apperrors.LegalHold("Mapping")
+	}
 	return s.convRepo.Delete(ctx, id)
 }
 
@@ -325,6 +482,45 @@ func (s *AdminService) GetInboundMessages(ctx context.Context, limit, offset int
 	return messages, total, nil
 }
 
+// ExpireInboundMessages marks the queued messages matching filter expired,
+// so plugins no longer receive them. At least one filter is required. On a
+// dry run the matching messages are only counted.
+func (s *AdminService) ExpireInboundMessages(ctx context.Context, filter model.InboundExpiryFilter, dryRun bool) (*model.InboundExpiryResult, error) {
+	if filter.AccountID == "" && filter.ConversationKey == "" && filter.CreatedBefore == nil {
+		return nil, apperrors.InvalidInput("filter", "set at least one of accountId, conversationKey, olderThan")
+	}
+	if filter.AccountID != "" && !util.IsValidUUID(filter.AccountID) {
+		return nil, apperrors.InvalidInput("accountId", "must be a UUID")
+	}
+
+	qb := newQueryBuilder()
+	qb.addCondition("status", string(model.InboundStatusQueued))
+	qb.addCondition("account_id", filter.AccountID)
+	qb.addCondition("conversation_key", filter.ConversationKey)
+	qb.addTimeBound("created_at", "<", filter.CreatedBefore)
+
+	matched := "SELECT account_id FROM inbound_messages" + qb.where()
+	if !dryRun {
+		matched = "UPDATE inbound_messages SET status = 'expired'" + qb.where() + " RETURNING account_id"
+	}
+
+	var counts []struct {
+		AccountID string `db:"account_id"`
+		Count     int    `db:"count"`
+	}
+	query := "WITH matched AS (" + matched + ") SELECT account_id, COUNT(*) AS count FROM matched GROUP BY account_id"
+	if err := s.db.SelectContext(ctx, &counts, query, qb.args...); err != nil {
+		return nil, fmt.Errorf("expire inbound messages: %w", err)
+	}
+
+	result := &model.InboundExpiryResult{ByAccount: make(map[string]int), DryRun: dryRun}
+	for _, c := range counts {
+		result.ByAccount[c.AccountID] = c.Count
+		result.Expired += c.Count
+	}
+	return result, nil
+}
+
 func (s *AdminService) GetOutboundMessages(ctx context.Context, limit, offset int, accountID, status string) ([]model.OutboundMessage, int, error) {
 	var messages []model.OutboundMessage
 	var total int
@@ -430,5 +626,22 @@ func (s *AdminService) DeleteSession(ctx context.Context, id string) error {
 }
 
 func (s *AdminService) DisconnectSession(ctx context.Context, id string) error {
-	return s.pluginSessionRepo.MarkDisconnected(ctx, id)
+	session, err := s.pluginSessionRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.pluginSessionRepo.MarkDisconnected(ctx, id); err != nil {
+		return err
+	}
+
+	if session != nil {
+		s.events.SessionDisconnected(ctx, session, "admin")
+	}
+	return nil
+}
+
+// GetPluginVersions returns the plugin version distribution of active sessions.
+func (s *AdminService) GetPluginVersions(ctx context.Context) ([]model.PluginVersionCount, error) {
+	return s.pluginSessionRepo.CountByPluginVersion(ctx)
 }
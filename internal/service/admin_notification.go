@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// AlertMigrationsPending fires when the server starts against a database that
+// has not applied all of its migrations.
+const AlertMigrationsPending = "migrations_pending"
+
+// AdminNotificationService is the admin notification center: an
+// alert.Notifier that keeps alerts for admins to read in the admin UI, so
+// deployments without an ops webhook still see them.
+type AdminNotificationService struct {
+	repo repository.AdminNotificationRepository
+}
+
+func NewAdminNotificationService(repo repository.AdminNotificationRepository) *AdminNotificationService {
+	return &AdminNotificationService{repo: repo}
+}
+
+// Notify raises a notification for a firing alert and marks the unread one
+// resolved when it ends. Failures are only logged, like other notifiers.
+func (s *AdminNotificationService) Notify(ctx context.Context, a alert.Alert) {
+	var err error
+	if a.Status == alert.StatusResolved {
+		err = s.repo.Resolve(ctx, a.Name, a.Subject)
+	} else {
+		err = s.raise(ctx, a)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("alert", a.Name).Msg("failed to record admin notification")
+	}
+}
+
+func (s *AdminNotificationService) raise(ctx context.Context, a alert.Alert) error {
+	n := &model.AdminNotification{
+		Kind:           string(a.Kind),
+		Name:           a.Name,
+		Subject:        a.Subject,
+		Message:        a.Message,
+		LastOccurredAt: a.At,
+	}
+	if n.Kind == "" {
+		n.Kind = string(alert.KindAnomaly)
+	}
+	if n.LastOccurredAt.IsZero() {
+		n.LastOccurredAt = time.Now()
+	}
+	if len(a.Details) > 0 {
+		details, err := json.Marshal(a.Details)
+		if err != nil {
+			return fmt.Errorf("encode details: %w", err)
+		}
+		raw := json.RawMessage(details)
+		n.Details = &raw
+	}
+	return s.repo.Raise(ctx, n)
+}
+
+// List returns the matching notifications, most recent first, how many match
+// and how many are unread in total.
+func (s *AdminNotificationService) List(ctx context.Context, filter model.AdminNotificationFilter, limit, offset int) ([]model.AdminNotification, int, int, error) {
+	items, total, unread, err := s.repo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("list admin notifications: %w", err)
+	}
+	if items == nil {
+		items = []model.AdminNotification{}
+	}
+	return items, total, unread, nil
+}
+
+func (s *AdminNotificationService) MarkRead(ctx context.Context, id string) error {
+	found, err := s.repo.MarkRead(ctx, id)
+	if err != nil {
+		return fmt.Errorf("mark admin notification read: %w", err)
+	}
+	if !found {
+		return apperrors.NotFound("Notification")
+	}
+	return nil
+}
+
+// MarkAllRead returns how many notifications were unread.
+func (s *AdminNotificationService) MarkAllRead(ctx context.Context) (int64, error) {
+	count, err := s.repo.MarkAllRead(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("mark admin notifications read: %w", err)
+	}
+	return count, nil
+}
+
+// CheckMigrations notifies operators when migrations in known, the
+// migrations this server was built with, are not recorded as applied, and
+// returns them.
+func CheckMigrations(ctx context.Context, repo repository.SchemaMigrationRepository, known []string, notifier alert.Notifier) ([]string, error) {
+	applied, err := repo.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	var pending []string
+	for _, name := range known {
+		if !slices.Contains(applied, name) {
+			pending = append(pending, name)
+		}
+	}
+	if len(pending) > 0 {
+		notifier.Notify(ctx, alert.Alert{
+			Name:    AlertMigrationsPending,
+			Kind:    alert.KindMigrationPending,
+			Status:  alert.StatusFiring,
+			Message: fmt.Sprintf("%d database migrations are not applied", len(pending)),
+			Details: map[string]any{"pending": pending},
+			At:      time.Now(),
+		})
+	}
+	return pending, nil
+}
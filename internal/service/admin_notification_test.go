@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type mockAdminNotificationRepo struct {
+	mock.Mock
+}
+
+func (m *mockAdminNotificationRepo) Raise(ctx context.Context, n *model.AdminNotification) error {
+	return m.Called(ctx, n).Error(0)
+}
+
+func (m *mockAdminNotificationRepo) Resolve(ctx context.Context, name, subject string) error {
+	return m.Called(ctx, name, subject).Error(0)
+}
+
+func (m *mockAdminNotificationRepo) List(ctx context.Context, filter model.AdminNotificationFilter, limit, offset int) ([]model.AdminNotification, int, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	items, _ := args.Get(0).([]model.AdminNotification)
+	return items, args.Int(1), args.Int(2), args.Error(3)
+}
+
+func (m *mockAdminNotificationRepo) MarkRead(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAdminNotificationRepo) MarkAllRead(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockAdminNotificationRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type stubSchemaMigrationRepo []string
+
+func (r stubSchemaMigrationRepo) Applied(ctx context.Context) ([]string, error) {
+	return r, nil
+}
+
+type recordingAlertNotifier struct {
+	alerts []alert.Alert
+}
+
+func (n *recordingAlertNotifier) Notify(ctx context.Context, a alert.Alert) {
+	n.alerts = append(n.alerts, a)
+}
+
+func TestAdminNotificationService_Notify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("raises firing alerts", func(t *testing.T) {
+		repo := new(mockAdminNotificationRepo)
+		var raised *model.AdminNotification
+		repo.On("Raise", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			raised = args.Get(1).(*model.AdminNotification)
+		}).Return(nil)
+
+		at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		NewAdminNotificationService(repo).Notify(ctx, alert.Alert{
+			Name:    "job_failing",
+			Kind:    alert.KindJobFailure,
+			Subject: "scheduled send",
+			Status:  alert.StatusFiring,
+			Message: "scheduled send job failed",
+			Details: map[string]any{"error": "connection refused"},
+			At:      at,
+		})
+
+		require.NotNil(t, raised)
+		assert.Equal(t, "job_failure", raised.Kind)
+		assert.Equal(t, "scheduled send", raised.Subject)
+		assert.Equal(t, at, raised.LastOccurredAt)
+		require.NotNil(t, raised.Details)
+		assert.JSONEq(t, `{"error":"connection refused"}`, string(*raised.Details))
+	})
+
+	t.Run("defaults to anomalies", func(t *testing.T) {
+		repo := new(mockAdminNotificationRepo)
+		repo.On("Raise", ctx, mock.MatchedBy(func(n *model.AdminNotification) bool {
+			return n.Kind == "anomaly" && n.Details == nil && !n.LastOccurredAt.IsZero()
+		})).Return(nil)
+
+		NewAdminNotificationService(repo).Notify(ctx, alert.Alert{Name: "inbound_backlog", Status: alert.StatusFiring})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("resolves resolved alerts", func(t *testing.T) {
+		repo := new(mockAdminNotificationRepo)
+		repo.On("Resolve", ctx, "job_failing", "reconcile").Return(nil)
+
+		NewAdminNotificationService(repo).Notify(ctx, alert.Alert{Name: "job_failing", Subject: "reconcile", Status: alert.StatusResolved})
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Raise", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminNotificationService_MarkRead(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mockAdminNotificationRepo)
+	repo.On("MarkRead", ctx, "n1").Return(true, nil)
+	repo.On("MarkRead", ctx, "n2").Return(false, nil)
+	svc := NewAdminNotificationService(repo)
+
+	assert.NoError(t, svc.MarkRead(ctx, "n1"))
+	assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(svc.MarkRead(ctx, "n2")))
+}
+
+func TestAdminNotificationService_List(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mockAdminNotificationRepo)
+	filter := model.AdminNotificationFilter{UnreadOnly: true}
+	repo.On("List", ctx, filter, 20, 0).Return(nil, 0, 3, nil)
+
+	items, total, unread, err := NewAdminNotificationService(repo).List(ctx, filter, 20, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, items, "encodes as an empty list")
+	assert.Equal(t, 0, total)
+	assert.Equal(t, 3, unread)
+}
+
+func TestCheckMigrations(t *testing.T) {
+	ctx := context.Background()
+	known := []string{"0000_init", "0001_accounts", "0002_sessions"}
+
+	t.Run("notifies pending migrations", func(t *testing.T) {
+		notifier := &recordingAlertNotifier{}
+		pending, err := CheckMigrations(ctx, stubSchemaMigrationRepo{"0000_init"}, known, notifier)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0001_accounts", "0002_sessions"}, pending)
+
+		require.Len(t, notifier.alerts, 1)
+		fired := notifier.alerts[0]
+		assert.Equal(t, AlertMigrationsPending, fired.Name)
+		assert.Equal(t, alert.KindMigrationPending, fired.Kind)
+		details, err := json.Marshal(fired.Details)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"pending":["0001_accounts","0002_sessions"]}`, string(details))
+	})
+
+	t.Run("stays quiet when up to date", func(t *testing.T) {
+		notifier := &recordingAlertNotifier{}
+		pending, err := CheckMigrations(ctx, stubSchemaMigrationRepo(known), known, notifier)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		assert.Empty(t, notifier.alerts)
+	})
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// ChangeHistoryService answers who changed an account setting or a
+// conversation's state, and when. Entries are written by the repositories
+// making the changes, with the actor taken from the request context (see
+// audit.WithActor).
+type ChangeHistoryService struct {
+	repo repository.ChangeHistoryRepository
+}
+
+func NewChangeHistoryService(repo repository.ChangeHistoryRepository) *ChangeHistoryService {
+	return &ChangeHistoryService{repo: repo}
+}
+
+// List returns the matching entries, newest first, and how many match.
+func (s *ChangeHistoryService) List(ctx context.Context, filter model.ChangeHistoryFilter, limit, offset int) ([]model.ChangeHistoryEntry, int, error) {
+	entries, total, err := s.repo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list change history: %w", err)
+	}
+	if entries == nil {
+		entries = []model.ChangeHistoryEntry{}
+	}
+	return entries, total, nil
+}
@@ -3,20 +3,46 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 )
 
+// Limits for user-assigned connection details, in characters
+const (
+	maxConversationNicknameLen = 50
+	maxConversationNotesLen    = 1000
+	// Kakao's simpleText limit
+	maxPauseAutoReplyLen = 1000
+)
+
+// Window for counting recent outbound failures in connection health
+const connectionHealthFailureWindow = 24 * time.Hour
+
 type ConversationService struct {
-	repo repository.ConversationRepository
+	repo   repository.ConversationRepository
+	locker ConversationLocker
+}
+
+// NewConversationService creates the service. locker may be nil, which leaves
+// conversation updates unserialized.
+func NewConversationService(repo repository.ConversationRepository, locker ConversationLocker) *ConversationService {
+	return &ConversationService{repo: repo, locker: locker}
 }
 
-func NewConversationService(repo repository.ConversationRepository) *ConversationService {
-	return &ConversationService{repo: repo}
+// Lock serializes work on one conversation, such as a webhook or a state
+// change, with other requests for it. Hold it around the whole
+// read-modify-write and call the returned function when done. When the lock
+// cannot be taken the work proceeds unlocked: dropping a user's message would
+// be worse than the rare reordering.
+func (s *ConversationService) Lock(ctx context.Context, key string) func() {
+	return lockConversation(ctx, s.locker, key)
 }
 
 func BuildConversationKey(channelID, userKey string) string {
@@ -71,6 +97,185 @@ func (s *ConversationService) Unpair(ctx context.Context, key string) error {
 	return s.repo.UpdateState(ctx, key, model.PairingStateUnpaired, nil)
 }
 
+// Archive pauses relaying for a paired conversation without unpairing it.
+// It reports false when the conversation was not paired.
+func (s *ConversationService) Archive(ctx context.Context, key string) (bool, error) {
+	archived, err := s.repo.TransitionState(ctx, key, model.PairingStatePaired, model.PairingStateArchived)
+	if err != nil {
+		return false, fmt.Errorf("archive conversation: %w", err)
+	}
+	if archived {
+		log.Info().Str("conversationKey", key).Msg("conversation archived")
+	}
+	return archived, nil
+}
+
+// Reactivate resumes relaying for an archived conversation. It reports false
+// when the conversation was not archived.
+func (s *ConversationService) Reactivate(ctx context.Context, key string) (bool, error) {
+	reactivated, err := s.repo.TransitionState(ctx, key, model.PairingStateArchived, model.PairingStatePaired)
+	if err != nil {
+		return false, fmt.Errorf("reactivate conversation: %w", err)
+	}
+	if reactivated {
+		log.Info().Str("conversationKey", key).Msg("conversation reactivated")
+	}
+	return reactivated, nil
+}
+
+// PauseDelivery holds back the conversation's messages from the account
+// until ResumeDelivery. The Kakao user is answered with autoReply, or the
+// default notice when it is empty. Pausing again replaces the auto reply.
+func (s *ConversationService) PauseDelivery(ctx context.Context, key, autoReply string) (*model.ConversationMapping, error) {
+	autoReply = strings.TrimSpace(autoReply)
+	if utf8.RuneCountInString(autoReply) > maxPauseAutoReplyLen {
+		return nil, apperrors.InvalidInput("autoReply", fmt.Sprintf("must be at most %d characters", maxPauseAutoReplyLen))
+	}
+
+	conv, err := s.repo.PauseDelivery(ctx, key, optionalString(autoReply))
+	if err != nil {
+		return nil, fmt.Errorf("pause delivery: %w", err)
+	}
+	if conv != nil {
+		log.Info().Str("conversationKey", key).Msg("conversation delivery paused")
+	}
+	return conv, nil
+}
+
+// ResumeDelivery delivers the conversation's messages again. It returns nil
+// when the conversation was not paused.
+func (s *ConversationService) ResumeDelivery(ctx context.Context, key string) (*model.ConversationMapping, error) {
+	conv, err := s.repo.ResumeDelivery(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("resume delivery: %w", err)
+	}
+	if conv != nil {
+		log.Info().Str("conversationKey", key).Msg("conversation delivery resumed")
+	}
+	return conv, nil
+}
+
 func (s *ConversationService) ListByAccountID(ctx context.Context, accountID string) ([]model.ConversationMapping, error) {
 	return s.repo.FindPairedByAccountID(ctx, accountID)
 }
+
+// Search lists one page of the account's paired conversations whose
+// nickname, notes or conversation key contain query. An empty query lists all
+// of them.
+func (s *ConversationService) Search(ctx context.Context, accountID, query string, page model.KeysetPage) ([]model.ConversationMapping, error) {
+	return s.repo.SearchPairedByAccountID(ctx, accountID, strings.TrimSpace(query), page)
+}
+
+type ConnectionListParams struct {
+	AccountID string
+	State     string // "paired", "blocked", "archived", "all", or "" for paired
+	Label     string
+	Query     string
+	Sort      string // "last_seen_at" or "paired_at" (default)
+	Order     string // "asc" or "desc" (default)
+	Limit     int
+	Offset    int
+}
+
+type ConnectionListResult struct {
+	Connections []model.ConversationMapping
+	Total       int
+	HasMore     bool
+}
+
+// List returns one page of the account's connections, filtered and sorted in
+// the database.
+func (s *ConversationService) List(ctx context.Context, params ConnectionListParams) (*ConnectionListResult, error) {
+	listParams := model.ListConversationsParams{
+		AccountID: params.AccountID,
+		Label:     strings.TrimSpace(params.Label),
+		Query:     strings.TrimSpace(params.Query),
+		Limit:     params.Limit,
+		Offset:    params.Offset,
+	}
+
+	switch params.State {
+	case "", string(model.PairingStatePaired):
+		listParams.States = []model.PairingState{model.PairingStatePaired}
+	case string(model.PairingStateBlocked):
+		listParams.States = []model.PairingState{model.PairingStateBlocked}
+	case string(model.PairingStateArchived):
+		listParams.States = []model.PairingState{model.PairingStateArchived}
+	case "all":
+		listParams.States = []model.PairingState{model.PairingStatePaired, model.PairingStateArchived, model.PairingStateBlocked}
+	default:
+		return nil, apperrors.InvalidInput("state", "must be paired, blocked, archived or all")
+	}
+
+	switch model.ConversationSort(params.Sort) {
+	case "", model.ConversationSortPairedAt:
+		listParams.Sort = model.ConversationSortPairedAt
+	case model.ConversationSortLastSeenAt:
+		listParams.Sort = model.ConversationSortLastSeenAt
+	default:
+		return nil, apperrors.InvalidInput("sort", "must be last_seen_at or paired_at")
+	}
+
+	switch params.Order {
+	case "", "desc":
+	case "asc":
+		listParams.Ascending = true
+	default:
+		return nil, apperrors.InvalidInput("order", "must be asc or desc")
+	}
+
+	convs, total, err := s.repo.ListByAccount(ctx, listParams)
+	if err != nil {
+		return nil, fmt.Errorf("list connections: %w", err)
+	}
+
+	return &ConnectionListResult{
+		Connections: convs,
+		Total:       total,
+		HasMore:     params.Offset+len(convs) < total,
+	}, nil
+}
+
+// UpdateDetails sets the conversation's nickname and notes. Blank values clear
+// the field.
+func (s *ConversationService) UpdateDetails(ctx context.Context, key, nickname, notes string) error {
+	nickname = strings.TrimSpace(nickname)
+	notes = strings.TrimSpace(notes)
+
+	if utf8.RuneCountInString(nickname) > maxConversationNicknameLen {
+		return apperrors.InvalidInput("nickname", fmt.Sprintf("must be at most %d characters", maxConversationNicknameLen))
+	}
+	if utf8.RuneCountInString(notes) > maxConversationNotesLen {
+		return apperrors.InvalidInput("notes", fmt.Sprintf("must be at most %d characters", maxConversationNotesLen))
+	}
+
+	return s.repo.UpdateDetails(ctx, key, optionalString(nickname), optionalString(notes))
+}
+
+// SetContentConsent records whether the conversation's message bodies may be
+// stored.
+func (s *ConversationService) SetContentConsent(ctx context.Context, key string, consent model.ContentConsent) error {
+	if err := s.repo.UpdateContentConsent(ctx, key, consent); err != nil {
+		return fmt.Errorf("update content consent: %w", err)
+	}
+	log.Info().Str("conversationKey", key).Str("consent", string(consent)).Msg("content consent updated")
+	return nil
+}
+
+// Health returns message activity per conversation key, fetched in a single
+// batched query.
+func (s *ConversationService) Health(ctx context.Context, keys []string) (map[string]model.ConnectionHealth, error) {
+	result := make(map[string]model.ConnectionHealth, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	health, err := s.repo.FindHealthByKeys(ctx, keys, time.Now().Add(-connectionHealthFailureWindow))
+	if err != nil {
+		return nil, fmt.Errorf("find connection health: %w", err)
+	}
+	for _, h := range health {
+		result[h.ConversationKey] = h
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+const (
+	// ConversationLockTTL bounds how long a crashed holder blocks a
+	// conversation. It is longer than Kakao waits for a webhook response.
+	ConversationLockTTL = 10 * time.Second
+	// ConversationLockWait is how long to wait for a held lock.
+	ConversationLockWait = 3 * time.Second
+
+	conversationLockRetry = 20 * time.Millisecond
+)
+
+// ErrConversationLockTimeout is returned when the lock stays held for longer
+// than ConversationLockWait.
+var ErrConversationLockTimeout = errors.New("timed out waiting for conversation lock")
+
+// ConversationLocker serializes state transitions and inbound processing of
+// one conversation (pairing, unpairing, blocking, webhooks) across requests
+// and instances. Locks are not reentrant.
+type ConversationLocker interface {
+	// Lock blocks until the conversation is locked and returns the function
+	// that releases it.
+	Lock(ctx context.Context, conversationKey string) (unlock func(), err error)
+}
+
+// lockConversation takes the lock, or proceeds unlocked with a warning when
+// there is no locker or the lock cannot be taken.
+func lockConversation(ctx context.Context, locker ConversationLocker, key string) func() {
+	if locker == nil {
+		return func() {}
+	}
+	unlock, err := locker.Lock(ctx, key)
+	if err != nil {
+		log.Warn().Err(err).Str("conversationKey", key).Msg("proceeding without conversation lock")
+		return func() {}
+	}
+	return unlock
+}
+
+// releaseLockScript deletes the lock only if it still holds our token, so a
+// holder whose lock expired cannot release the next holder's.
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+    return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type redisConversationLocker struct {
+	client *redisclient.Client
+	ttl    time.Duration
+	wait   time.Duration
+}
+
+// NewRedisConversationLocker returns a locker shared by all instances.
+func NewRedisConversationLocker(client *redisclient.Client) ConversationLocker {
+	return &redisConversationLocker{client: client, ttl: ConversationLockTTL, wait: ConversationLockWait}
+}
+
+func conversationLockKey(conversationKey string) string {
+	return fmt.Sprintf("conversation_lock:%s", conversationKey)
+}
+
+func (l *redisConversationLocker) Lock(ctx context.Context, conversationKey string) (func(), error) {
+	key := conversationLockKey(conversationKey)
+	token, err := util.GenerateToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate lock token: %w", err)
+	}
+
+	deadline := time.Now().Add(l.wait)
+	for {
+		ok, err := l.client.SetNX(ctx, key, token, l.ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("acquire conversation lock: %w", err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrConversationLockTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(conversationLockRetry):
+		}
+	}
+
+	return func() {
+		// Release even if the request context is already done
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+		if err := releaseLockScript.Run(ctx, l.client, []string{key}, token).Err(); err != nil {
+			log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to release conversation lock")
+		}
+	}, nil
+}
+
+// MemoryConversationLocker locks within this process only. It is suitable for
+// a single instance and for tests.
+type MemoryConversationLocker struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+	wait  time.Duration
+}
+
+func NewMemoryConversationLocker() *MemoryConversationLocker {
+	return &MemoryConversationLocker{locks: make(map[string]chan struct{}), wait: ConversationLockWait}
+}
+
+func (l *MemoryConversationLocker) Lock(ctx context.Context, conversationKey string) (func(), error) {
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+
+	for {
+		l.mu.Lock()
+		held, ok := l.locks[conversationKey]
+		if !ok {
+			released := make(chan struct{})
+			l.locks[conversationKey] = released
+			l.mu.Unlock()
+
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					l.mu.Lock()
+					delete(l.locks, conversationKey)
+					l.mu.Unlock()
+					close(released)
+				})
+			}, nil
+		}
+		l.mu.Unlock()
+
+		select {
+		case <-held:
+		case <-timer.C:
+			return nil, ErrConversationLockTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryConversationLocker(t *testing.T) {
+	t.Run("serializes holders of the same key", func(t *testing.T) {
+		locker := NewMemoryConversationLocker()
+
+		var mu sync.Mutex
+		inside, maxInside := 0, 0
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock, err := locker.Lock(context.Background(), "ch:user")
+				if !assert.NoError(t, err) {
+					return
+				}
+				mu.Lock()
+				inside++
+				if inside > maxInside {
+					maxInside = inside
+				}
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				inside--
+				mu.Unlock()
+				unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, maxInside)
+	})
+
+	t.Run("different keys do not block each other", func(t *testing.T) {
+		locker := NewMemoryConversationLocker()
+
+		unlockA, err := locker.Lock(context.Background(), "ch:a")
+		require.NoError(t, err)
+		defer unlockA()
+
+		unlockB, err := locker.Lock(context.Background(), "ch:b")
+		require.NoError(t, err)
+		unlockB()
+	})
+
+	t.Run("times out while held", func(t *testing.T) {
+		locker := NewMemoryConversationLocker()
+		locker.wait = 20 * time.Millisecond
+
+		unlock, err := locker.Lock(context.Background(), "ch:user")
+		require.NoError(t, err)
+		defer unlock()
+
+		_, err = locker.Lock(context.Background(), "ch:user")
+		assert.ErrorIs(t, err, ErrConversationLockTimeout)
+	})
+
+	t.Run("unlock is idempotent", func(t *testing.T) {
+		locker := NewMemoryConversationLocker()
+
+		first, err := locker.Lock(context.Background(), "ch:user")
+		require.NoError(t, err)
+		first()
+
+		second, err := locker.Lock(context.Background(), "ch:user")
+		require.NoError(t, err)
+		first()
+
+		locker.wait = 20 * time.Millisecond
+		_, err = locker.Lock(context.Background(), "ch:user")
+		assert.ErrorIs(t, err, ErrConversationLockTimeout, "stale unlock must not release the next holder")
+		second()
+	})
+}
+
+func TestConversationService_Lock_FailsOpen(t *testing.T) {
+	locker := NewMemoryConversationLocker()
+	locker.wait = 10 * time.Millisecond
+	svc := NewConversationService(nil, locker)
+
+	unlock := svc.Lock(context.Background(), "ch:user")
+	defer unlock()
+
+	// The second caller proceeds unlocked rather than dropping the update.
+	done := make(chan struct{})
+	go func() {
+		svc.Lock(context.Background(), "ch:user")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Lock did not fail open")
+	}
+}
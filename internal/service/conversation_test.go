@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+func TestConversationService_UpdateDetails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("trims values and clears blank fields", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		repo.On("UpdateDetails", ctx, "ch:user", mock.MatchedBy(func(v *string) bool {
+			return v != nil && *v == "Mom"
+		}), (*string)(nil)).Return(nil)
+		svc := NewConversationService(repo, nil)
+
+		err := svc.UpdateDetails(ctx, "ch:user", "  Mom ", "   ")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects overly long nickname", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		svc := NewConversationService(repo, nil)
+
+		err := svc.UpdateDetails(ctx, "ch:user", strings.Repeat("가", maxConversationNicknameLen+1), "")
+
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+		repo.AssertNotCalled(t, "UpdateDetails", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConversationService_Search(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lists all paired conversations for a blank query", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		repo.On("SearchPairedByAccountID", ctx, "acc-1", "", model.KeysetPage{}).Return([]model.ConversationMapping{{ConversationKey: "ch:a"}}, nil)
+		svc := NewConversationService(repo, nil)
+
+		convs, err := svc.Search(ctx, "acc-1", " ", model.KeysetPage{})
+
+		assert.NoError(t, err)
+		assert.Len(t, convs, 1)
+	})
+
+	t.Run("searches with a query", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		page := model.KeysetPage{After: &model.Cursor{At: time.Unix(100, 0), ID: "conv-9"}, Limit: 21}
+		repo.On("SearchPairedByAccountID", ctx, "acc-1", "mom", page).Return([]model.ConversationMapping{}, nil)
+		svc := NewConversationService(repo, nil)
+
+		_, err := svc.Search(ctx, "acc-1", "mom", page)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestConversationService_Archive(t *testing.T) {
+	ctx := context.Background()
+
+	repo := new(mockConversationRepo)
+	repo.On("TransitionState", ctx, "ch:user", model.PairingStatePaired, model.PairingStateArchived).Return(true, nil)
+	repo.On("TransitionState", ctx, "ch:user", model.PairingStateArchived, model.PairingStatePaired).Return(false, nil)
+	svc := NewConversationService(repo, nil)
+
+	archived, err := svc.Archive(ctx, "ch:user")
+	assert.NoError(t, err)
+	assert.True(t, archived)
+
+	reactivated, err := svc.Reactivate(ctx, "ch:user")
+	assert.NoError(t, err)
+	assert.False(t, reactivated)
+	repo.AssertExpectations(t)
+}
+
+func TestConversationService_Health(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("skips the query without keys", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		svc := NewConversationService(repo, nil)
+
+		health, err := svc.Health(ctx, nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, health)
+		repo.AssertNotCalled(t, "FindHealthByKeys", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("indexes health by conversation key", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		repo.On("FindHealthByKeys", ctx, []string{"ch:a", "ch:b"}, mock.AnythingOfType("time.Time")).Return([]model.ConnectionHealth{
+			{ConversationKey: "ch:a", QueuedCount: 2},
+			{ConversationKey: "ch:b", RecentFailureCount: 1},
+		}, nil)
+		svc := NewConversationService(repo, nil)
+
+		health, err := svc.Health(ctx, []string{"ch:a", "ch:b"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, health["ch:a"].QueuedCount)
+		assert.Equal(t, 1, health["ch:b"].RecentFailureCount)
+	})
+}
+
+func TestConversationService_List(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("defaults to paired connections sorted by paired_at", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		repo.On("ListByAccount", ctx, model.ListConversationsParams{
+			AccountID: "acc-1",
+			States:    []model.PairingState{model.PairingStatePaired},
+			Sort:      model.ConversationSortPairedAt,
+			Limit:     2,
+		}).Return([]model.ConversationMapping{{ConversationKey: "ch:a"}, {ConversationKey: "ch:b"}}, 5, nil)
+		svc := NewConversationService(repo, nil)
+
+		result, err := svc.List(ctx, ConnectionListParams{AccountID: "acc-1", Limit: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Connections, 2)
+		assert.Equal(t, 5, result.Total)
+		assert.True(t, result.HasMore)
+	})
+
+	t.Run("passes filters and sort through", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		repo.On("ListByAccount", ctx, model.ListConversationsParams{
+			AccountID: "acc-1",
+			States:    []model.PairingState{model.PairingStatePaired, model.PairingStateArchived, model.PairingStateBlocked},
+			Label:     "Mom",
+			Sort:      model.ConversationSortLastSeenAt,
+			Ascending: true,
+			Limit:     50,
+			Offset:    50,
+		}).Return([]model.ConversationMapping{}, 50, nil)
+		svc := NewConversationService(repo, nil)
+
+		result, err := svc.List(ctx, ConnectionListParams{
+			AccountID: "acc-1",
+			State:     "all",
+			Label:     " Mom ",
+			Sort:      "last_seen_at",
+			Order:     "asc",
+			Limit:     50,
+			Offset:    50,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, result.HasMore)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown state and sort", func(t *testing.T) {
+		repo := new(mockConversationRepo)
+		svc := NewConversationService(repo, nil)
+
+		_, err := svc.List(ctx, ConnectionListParams{AccountID: "acc-1", State: "unpaired"})
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+
+		_, err = svc.List(ctx, ConnectionListParams{AccountID: "acc-1", Sort: "id"})
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+
+		repo.AssertNotCalled(t, "ListByAccount", mock.Anything, mock.Anything)
+	})
+}
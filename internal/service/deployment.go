@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+const (
+	minAdminPasswordLen  = 12
+	maxDeploymentNameLen = 100
+)
+
+// DeploymentService holds deployment-wide settings stored in the database:
+// the admin password hash and, when not set in the environment, the session
+// secrets. ADMIN_PASSWORD_HASH only seeds the stored hash (and re-seeds it when
+// the env value changes, to recover a lost password); afterwards the password
+// is changed through the admin API. A deployment without any admin password
+// starts in first-run mode, where a one-time bootstrap sets it using a setup
+// token printed to the log.
+//
+// The admin password hash is read from the database again on every password
+// check, so a password changed or bootstrapped on one instance applies to all
+// instances sharing the database right away.
+type DeploymentService struct {
+	repo            repository.DeploymentSettingsRepository
+	envPasswordHash string
+	passwordMaxAge  time.Duration
+
+	mu            sync.RWMutex
+	passwordHash  string
+	passwordSetAt time.Time
+	settings      map[string]string
+	setupToken    string
+}
+
+// DeploymentInfo describes the bootstrap state of the deployment.
+type DeploymentInfo struct {
+	BootstrapRequired bool       `json:"bootstrapRequired"`
+	Name              string     `json:"name,omitempty"`
+	BootstrappedAt    *time.Time `json:"bootstrappedAt,omitempty"`
+}
+
+type BootstrapParams struct {
+	SetupToken     string
+	Password       string
+	DeploymentName string
+	RemoteAddr     string
+}
+
+// NewDeploymentService creates the service. A positive passwordMaxAge forces
+// the admin password to be changed once it is older.
+func NewDeploymentService(
+	repo repository.DeploymentSettingsRepository,
+	envPasswordHash string,
+	passwordMaxAge time.Duration,
+) *DeploymentService {
+	return &DeploymentService{
+		repo:            repo,
+		envPasswordHash: envPasswordHash,
+		passwordMaxAge:  passwordMaxAge,
+		settings:        map[string]string{},
+	}
+}
+
+// Load reads the stored settings. It must run once at startup before the
+// service is used; when no admin password is configured it generates the
+// setup token for the bootstrap.
+func (s *DeploymentService) Load(ctx context.Context) error {
+	settings, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("load deployment settings: %w", err)
+	}
+
+	if s.envPasswordHash != "" && s.envPasswordHash != settings[model.DeploymentSettingAdminPasswordSeed] {
+		seeded := map[string]string{
+			model.DeploymentSettingAdminPasswordHash:  s.envPasswordHash,
+			model.DeploymentSettingAdminPasswordSeed:  s.envPasswordHash,
+			model.DeploymentSettingAdminPasswordSetAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		if err := s.repo.Set(ctx, seeded); err != nil {
+			return fmt.Errorf("store admin password hash: %w", err)
+		}
+		for k, v := range seeded {
+			settings[k] = v
+		}
+		log.Info().Msg("admin password hash seeded from ADMIN_PASSWORD_HASH")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.applySettings(settings)
+	if s.passwordHash == "" {
+		token, err := util.GenerateToken()
+		if err != nil {
+			return fmt.Errorf("generate setup token: %w", err)
+		}
+		s.setupToken = token
+	}
+	return nil
+}
+
+// reload replaces the cached settings with the stored ones.
+func (s *DeploymentService) reload(ctx context.Context) error {
+	settings, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("load deployment settings: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applySettings(settings)
+	return nil
+}
+
+// applySettings caches settings. s.mu must be held.
+func (s *DeploymentService) applySettings(settings map[string]string) {
+	s.settings = settings
+	s.passwordHash = settings[model.DeploymentSettingAdminPasswordHash]
+	s.passwordSetAt, _ = time.Parse(time.RFC3339, settings[model.DeploymentSettingAdminPasswordSetAt])
+	// Bootstrapped by another instance
+	if s.passwordHash != "" {
+		s.setupToken = ""
+	}
+}
+
+// SetupToken returns the token the bootstrap requires, or "" once an admin
+// password is configured.
+func (s *DeploymentService) SetupToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.setupToken
+}
+
+// SessionSecret returns envValue when set. Otherwise it returns the secret
+// stored under key, generating and storing a random one on first start.
+func (s *DeploymentService) SessionSecret(ctx context.Context, key, envValue string) (string, error) {
+	if envValue != "" {
+		return envValue, nil
+	}
+
+	generated, err := util.GenerateToken()
+	if err != nil {
+		return "", fmt.Errorf("generate %s: %w", key, err)
+	}
+	secret, err := s.repo.GetOrCreate(ctx, key, generated)
+	if err != nil {
+		return "", fmt.Errorf("store %s: %w", key, err)
+	}
+	if secret == generated {
+		log.Info().Str("key", key).Msg("generated session secret and stored it in the database")
+	}
+	return secret, nil
+}
+
+// AdminPasswordHash returns the current admin password hash, or "" when the
+// deployment has not been bootstrapped.
+func (s *DeploymentService) AdminPasswordHash() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.passwordHash
+}
+
+// CheckAdminPassword reports whether password matches the admin password
+// currently stored in the database.
+func (s *DeploymentService) CheckAdminPassword(ctx context.Context, password string) (bool, error) {
+	if err := s.reload(ctx); err != nil {
+		return false, err
+	}
+	return util.CheckPasswordHash(password, s.AdminPasswordHash()), nil
+}
+
+// PasswordChangeRequired reports whether the admin password is older than the
+// configured maximum age. A password without a recorded age is not expired.
+func (s *DeploymentService) PasswordChangeRequired() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.passwordMaxAge <= 0 || s.passwordSetAt.IsZero() {
+		return false
+	}
+	return time.Since(s.passwordSetAt) > s.passwordMaxAge
+}
+
+// ChangeAdminPassword replaces the admin password after verifying the
+// current one. Callers are responsible for ending existing admin sessions.
+func (s *DeploymentService) ChangeAdminPassword(ctx context.Context, currentPassword, newPassword string) error {
+	ok, err := s.CheckAdminPassword(ctx, currentPassword)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.Unauthorized("Current password is incorrect")
+	}
+	if err := validateAdminPassword(newPassword); err != nil {
+		return err
+	}
+	if newPassword == currentPassword {
+		return apperrors.InvalidInput("newPassword", "must differ from the current password")
+	}
+
+	hash, err := util.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("hash admin password: %w", err)
+	}
+
+	now := time.Now().UTC()
+	settings := map[string]string{
+		model.DeploymentSettingAdminPasswordHash:  hash,
+		model.DeploymentSettingAdminPasswordSetAt: now.Format(time.RFC3339),
+	}
+	if err := s.repo.Set(ctx, settings); err != nil {
+		return fmt.Errorf("store admin password hash: %w", err)
+	}
+
+	s.mu.Lock()
+	s.passwordHash = hash
+	s.passwordSetAt = now
+	for k, v := range settings {
+		s.settings[k] = v
+	}
+	s.mu.Unlock()
+
+	log.Info().Msg("admin password changed")
+	return nil
+}
+
+func validateAdminPassword(password string) error {
+	if utf8.RuneCountInString(password) < minAdminPasswordLen {
+		return apperrors.InvalidInput("password", fmt.Sprintf("must be at least %d characters", minAdminPasswordLen))
+	}
+	return nil
+}
+
+func (s *DeploymentService) Info() DeploymentInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := DeploymentInfo{
+		BootstrapRequired: s.passwordHash == "",
+		Name:              s.settings[model.DeploymentSettingName],
+	}
+	if at, err := time.Parse(time.RFC3339, s.settings[model.DeploymentSettingBootstrappedAt]); err == nil {
+		info.BootstrappedAt = &at
+	}
+	return info
+}
+
+// Bootstrap sets the admin password of a first-run deployment and records
+// deployment metadata. It succeeds once; later calls are rejected.
+func (s *DeploymentService) Bootstrap(ctx context.Context, params BootstrapParams) (*DeploymentInfo, error) {
+	setupToken := s.SetupToken()
+	if setupToken == "" {
+		return nil, apperrors.New(apperrors.ErrCodeConflict, "Deployment is already bootstrapped")
+	}
+	if !util.ConstantTimeEqual(params.SetupToken, setupToken) {
+		return nil, apperrors.Forbidden("Invalid setup token")
+	}
+
+	name := strings.TrimSpace(params.DeploymentName)
+	if utf8.RuneCountInString(name) > maxDeploymentNameLen {
+		return nil, apperrors.InvalidInput("deploymentName", fmt.Sprintf("must be at most %d characters", maxDeploymentNameLen))
+	}
+	if err := validateAdminPassword(params.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := util.HashPassword(params.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hash admin password: %w", err)
+	}
+
+	now := time.Now().UTC()
+	settings := map[string]string{
+		model.DeploymentSettingAdminPasswordHash:  hash,
+		model.DeploymentSettingAdminPasswordSetAt: now.Format(time.RFC3339),
+		model.DeploymentSettingBootstrappedFrom:   params.RemoteAddr,
+	}
+	if name != "" {
+		settings[model.DeploymentSettingName] = name
+	}
+
+	claimed, err := s.repo.Bootstrap(ctx, now.Format(time.RFC3339), settings)
+	if err != nil {
+		return nil, fmt.Errorf("store bootstrap settings: %w", err)
+	}
+	if !claimed {
+		return nil, apperrors.New(apperrors.ErrCodeConflict, "Deployment is already bootstrapped")
+	}
+
+	s.mu.Lock()
+	s.passwordHash = hash
+	s.passwordSetAt = now
+	s.setupToken = ""
+	settings[model.DeploymentSettingBootstrappedAt] = now.Format(time.RFC3339)
+	for k, v := range settings {
+		s.settings[k] = v
+	}
+	s.mu.Unlock()
+
+	log.Info().Str("name", name).Str("from", params.RemoteAddr).Msg("deployment bootstrapped")
+
+	info := s.Info()
+	return &info, nil
+}
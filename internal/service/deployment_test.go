@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+type fakeDeploymentSettingsRepo struct {
+	settings map[string]string
+}
+
+func newFakeDeploymentSettingsRepo() *fakeDeploymentSettingsRepo {
+	return &fakeDeploymentSettingsRepo{settings: map[string]string{}}
+}
+
+func (f *fakeDeploymentSettingsRepo) GetAll(ctx context.Context) (map[string]string, error) {
+	all := make(map[string]string, len(f.settings))
+	for k, v := range f.settings {
+		all[k] = v
+	}
+	return all, nil
+}
+
+func (f *fakeDeploymentSettingsRepo) GetOrCreate(ctx context.Context, key, value string) (string, error) {
+	if stored, ok := f.settings[key]; ok {
+		return stored, nil
+	}
+	f.settings[key] = value
+	return value, nil
+}
+
+func (f *fakeDeploymentSettingsRepo) Set(ctx context.Context, settings map[string]string) error {
+	for k, v := range settings {
+		f.settings[k] = v
+	}
+	return nil
+}
+
+func (f *fakeDeploymentSettingsRepo) Bootstrap(ctx context.Context, bootstrappedAt string, settings map[string]string) (bool, error) {
+	if _, ok := f.settings[model.DeploymentSettingBootstrappedAt]; ok {
+		return false, nil
+	}
+	f.settings[model.DeploymentSettingBootstrappedAt] = bootstrappedAt
+	for k, v := range settings {
+		f.settings[k] = v
+	}
+	return true, nil
+}
+
+func TestDeploymentService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("env password hash skips bootstrap", func(t *testing.T) {
+		svc := NewDeploymentService(newFakeDeploymentSettingsRepo(), "$2a$env", 0)
+		require.NoError(t, svc.Load(ctx))
+
+		assert.Equal(t, "$2a$env", svc.AdminPasswordHash())
+		assert.Empty(t, svc.SetupToken())
+		assert.False(t, svc.Info().BootstrapRequired)
+	})
+
+	t.Run("bootstrap sets the admin password once", func(t *testing.T) {
+		repo := newFakeDeploymentSettingsRepo()
+		svc := NewDeploymentService(repo, "", 0)
+		require.NoError(t, svc.Load(ctx))
+		assert.True(t, svc.Info().BootstrapRequired)
+		token := svc.SetupToken()
+		require.NotEmpty(t, token)
+
+		_, err := svc.Bootstrap(ctx, BootstrapParams{SetupToken: "wrong", Password: "long enough password"})
+		assert.Equal(t, apperrors.ErrCodeForbidden, apperrors.GetCode(err))
+
+		_, err = svc.Bootstrap(ctx, BootstrapParams{SetupToken: token, Password: "short"})
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+
+		info, err := svc.Bootstrap(ctx, BootstrapParams{SetupToken: token, Password: "long enough password", DeploymentName: "self-hosted"})
+		require.NoError(t, err)
+		assert.False(t, info.BootstrapRequired)
+		assert.Equal(t, "self-hosted", info.Name)
+		assert.NotNil(t, info.BootstrappedAt)
+		assert.True(t, util.CheckPasswordHash("long enough password", svc.AdminPasswordHash()))
+		assert.Equal(t, svc.AdminPasswordHash(), repo.settings[model.DeploymentSettingAdminPasswordHash])
+
+		_, err = svc.Bootstrap(ctx, BootstrapParams{SetupToken: token, Password: "another long password"})
+		assert.Equal(t, apperrors.ErrCodeConflict, apperrors.GetCode(err))
+	})
+
+	t.Run("stored password hash is used after restart", func(t *testing.T) {
+		repo := newFakeDeploymentSettingsRepo()
+		repo.settings[model.DeploymentSettingAdminPasswordHash] = "$2a$stored"
+
+		svc := NewDeploymentService(repo, "", 0)
+		require.NoError(t, svc.Load(ctx))
+		assert.Equal(t, "$2a$stored", svc.AdminPasswordHash())
+		assert.Empty(t, svc.SetupToken())
+	})
+
+	t.Run("env password hash seeds the stored hash once", func(t *testing.T) {
+		repo := newFakeDeploymentSettingsRepo()
+		svc := NewDeploymentService(repo, "$2a$env", 0)
+		require.NoError(t, svc.Load(ctx))
+		assert.Equal(t, "$2a$env", repo.settings[model.DeploymentSettingAdminPasswordHash])
+
+		repo.settings[model.DeploymentSettingAdminPasswordHash] = "$2a$changed"
+		svc = NewDeploymentService(repo, "$2a$env", 0)
+		require.NoError(t, svc.Load(ctx))
+		assert.Equal(t, "$2a$changed", svc.AdminPasswordHash())
+
+		svc = NewDeploymentService(repo, "$2a$recovery", 0)
+		require.NoError(t, svc.Load(ctx))
+		assert.Equal(t, "$2a$recovery", svc.AdminPasswordHash())
+	})
+
+	t.Run("ChangeAdminPassword verifies the current password", func(t *testing.T) {
+		hash, err := util.HashPassword("old password 123")
+		require.NoError(t, err)
+		repo := newFakeDeploymentSettingsRepo()
+		svc := NewDeploymentService(repo, hash, 0)
+		require.NoError(t, svc.Load(ctx))
+
+		err = svc.ChangeAdminPassword(ctx, "wrong", "new password 456")
+		assert.Equal(t, apperrors.ErrCodeUnauthorized, apperrors.GetCode(err))
+
+		err = svc.ChangeAdminPassword(ctx, "old password 123", "short")
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+
+		err = svc.ChangeAdminPassword(ctx, "old password 123", "old password 123")
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+
+		require.NoError(t, svc.ChangeAdminPassword(ctx, "old password 123", "new password 456"))
+		assert.True(t, util.CheckPasswordHash("new password 456", svc.AdminPasswordHash()))
+		assert.Equal(t, svc.AdminPasswordHash(), repo.settings[model.DeploymentSettingAdminPasswordHash])
+	})
+
+	t.Run("password changed on another instance applies right away", func(t *testing.T) {
+		oldHash, err := util.HashPassword("old password 123")
+		require.NoError(t, err)
+		repo := newFakeDeploymentSettingsRepo()
+		repo.settings[model.DeploymentSettingAdminPasswordHash] = oldHash
+		svc := NewDeploymentService(repo, "", 0)
+		require.NoError(t, svc.Load(ctx))
+
+		other := NewDeploymentService(repo, "", 0)
+		require.NoError(t, other.Load(ctx))
+		require.NoError(t, other.ChangeAdminPassword(ctx, "old password 123", "new password 456"))
+
+		ok, err := svc.CheckAdminPassword(ctx, "old password 123")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		ok, err = svc.CheckAdminPassword(ctx, "new password 456")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("bootstrap on another instance ends first-run mode", func(t *testing.T) {
+		repo := newFakeDeploymentSettingsRepo()
+		svc := NewDeploymentService(repo, "", 0)
+		require.NoError(t, svc.Load(ctx))
+		other := NewDeploymentService(repo, "", 0)
+		require.NoError(t, other.Load(ctx))
+
+		_, err := other.Bootstrap(ctx, BootstrapParams{SetupToken: other.SetupToken(), Password: "long enough password"})
+		require.NoError(t, err)
+
+		ok, err := svc.CheckAdminPassword(ctx, "long enough password")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, svc.SetupToken())
+		assert.False(t, svc.Info().BootstrapRequired)
+	})
+
+	t.Run("PasswordChangeRequired after max age", func(t *testing.T) {
+		repo := newFakeDeploymentSettingsRepo()
+		repo.settings[model.DeploymentSettingAdminPasswordHash] = "$2a$stored"
+		repo.settings[model.DeploymentSettingAdminPasswordSetAt] = time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+
+		svc := NewDeploymentService(repo, "", 24*time.Hour)
+		require.NoError(t, svc.Load(ctx))
+		assert.True(t, svc.PasswordChangeRequired())
+
+		svc = NewDeploymentService(repo, "", 0)
+		require.NoError(t, svc.Load(ctx))
+		assert.False(t, svc.PasswordChangeRequired())
+	})
+
+	t.Run("SessionSecret prefers env and otherwise persists a generated secret", func(t *testing.T) {
+		repo := newFakeDeploymentSettingsRepo()
+		svc := NewDeploymentService(repo, "", 0)
+
+		secret, err := svc.SessionSecret(ctx, model.DeploymentSettingAdminSessionSecret, "from-env")
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", secret)
+
+		generated, err := svc.SessionSecret(ctx, model.DeploymentSettingAdminSessionSecret, "")
+		require.NoError(t, err)
+		assert.Len(t, generated, 64)
+
+		again, err := svc.SessionSecret(ctx, model.DeploymentSettingAdminSessionSecret, "")
+		require.NoError(t, err)
+		assert.Equal(t, generated, again)
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/blob"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// KakaoUserErasureResult reports an erasure, or with DryRun what an erasure
+// would touch.
+type KakaoUserErasureResult struct {
+	Mode   model.ErasureMode `json:"mode"`
+	DryRun bool              `json:"dryRun"`
+	model.KakaoUserErasure
+}
+
+// ErasureService handles deletion requests for a Kakao user, which channel
+// operators forward when a user asks to be forgotten.
+type ErasureService struct {
+	repo  repository.ErasureRepository
+	blobs blob.Store
+}
+
+// NewErasureService creates the service. blobs is the store of offloaded
+// payloads, nil when offloading is off.
+func NewErasureService(repo repository.ErasureRepository, blobs blob.Store) *ErasureService {
+	return &ErasureService{repo: repo, blobs: blobs}
+}
+
+// EraseKakaoUser erases every conversation of plusfriendUserKey across all
+// accounts. With dryRun it only counts the affected rows.
+func (s *ErasureService) EraseKakaoUser(ctx context.Context, plusfriendUserKey string, mode model.ErasureMode, dryRun bool) (*KakaoUserErasureResult, error) {
+	if strings.TrimSpace(plusfriendUserKey) == "" {
+		return nil, apperrors.InvalidInput("userKey", "is required")
+	}
+	if mode != model.ErasureModeDelete && mode != model.ErasureModeAnonymize {
+		return nil, apperrors.InvalidInput("mode", "must be delete or anonymize")
+	}
+
+	var (
+		counts   *model.KakaoUserErasure
+		blobKeys []string
+		err      error
+	)
+	// The rows referencing the blobs are gone after the erase
+	if !dryRun && s.blobs != nil {
+		if blobKeys, err = s.repo.FindBlobKeys(ctx, plusfriendUserKey); err != nil {
+			return nil, fmt.Errorf("find offloaded payloads: %w", err)
+		}
+	}
+
+	switch {
+	case dryRun:
+		counts, err = s.repo.CountKakaoUser(ctx, plusfriendUserKey)
+	case mode == model.ErasureModeAnonymize:
+		counts, err = s.repo.AnonymizeKakaoUser(ctx, plusfriendUserKey)
+	default:
+		counts, err = s.repo.DeleteKakaoUser(ctx, plusfriendUserKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erase kakao user: %w", err)
+	}
+
+	if !dryRun {
+		s.deleteBlobs(ctx, blobKeys)
+		log.Info().
+			Str("mode", string(mode)).
+			Int("conversations", counts.Conversations).
+			Int("inboundMessages", counts.InboundMessages).
+			Int("outboundMessages", counts.OutboundMessages).
+			Int("offloadedPayloads", len(blobKeys)).
+			Msg("erased kakao user")
+	}
+
+	return &KakaoUserErasureResult{Mode: mode, DryRun: dryRun, KakaoUserErasure: *counts}, nil
+}
+
+// deleteBlobs removes offloaded payloads of erased messages. A blob that
+// cannot be deleted is logged for manual cleanup; the rows are already gone.
+func (s *ErasureService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Error().Err(err).Str("blobKey", key).Msg("failed to delete offloaded payload of erased message")
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/blob"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// fakeErasureRepo records which operation ran for which user key.
+type fakeErasureRepo struct {
+	calls []string
+	keys  []string
+}
+
+func (r *fakeErasureRepo) record(op, key string) (*model.KakaoUserErasure, error) {
+	r.calls = append(r.calls, op)
+	r.keys = append(r.keys, key)
+	return &model.KakaoUserErasure{Conversations: 2, Accounts: 2, InboundMessages: 5}, nil
+}
+
+func (r *fakeErasureRepo) CountKakaoUser(ctx context.Context, key string) (*model.KakaoUserErasure, error) {
+	return r.record("count", key)
+}
+
+func (r *fakeErasureRepo) DeleteKakaoUser(ctx context.Context, key string) (*model.KakaoUserErasure, error) {
+	return r.record("delete", key)
+}
+
+func (r *fakeErasureRepo) AnonymizeKakaoUser(ctx context.Context, key string) (*model.KakaoUserErasure, error) {
+	return r.record("anonymize", key)
+}
+
+func (r *fakeErasureRepo) FindBlobKeys(ctx context.Context, key string) ([]string, error) {
+	r.calls = append(r.calls, "blobs")
+	return []string{"inbound/acc-1/2026-01-02/a.json"}, nil
+}
+
+func TestErasureService_EraseKakaoUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dry run only counts", func(t *testing.T) {
+		repo := &fakeErasureRepo{}
+		result, err := NewErasureService(repo, nil).EraseKakaoUser(ctx, "user-1", model.ErasureModeAnonymize, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"count"}, repo.calls)
+		assert.Equal(t, []string{"user-1"}, repo.keys)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, model.ErasureModeAnonymize, result.Mode)
+		assert.Equal(t, 5, result.InboundMessages)
+	})
+
+	t.Run("dispatches on mode", func(t *testing.T) {
+		repo := &fakeErasureRepo{}
+		svc := NewErasureService(repo, nil)
+
+		_, err := svc.EraseKakaoUser(ctx, "user-1", model.ErasureModeDelete, false)
+		require.NoError(t, err)
+		_, err = svc.EraseKakaoUser(ctx, "user-1", model.ErasureModeAnonymize, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"delete", "anonymize"}, repo.calls)
+	})
+
+	t.Run("deletes offloaded payloads of erased messages", func(t *testing.T) {
+		store, err := blob.NewLocalStore(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, "inbound/acc-1/2026-01-02/a.json", []byte("{}")))
+		repo := &fakeErasureRepo{}
+
+		_, err = NewErasureService(repo, store).EraseKakaoUser(ctx, "user-1", model.ErasureModeDelete, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"blobs", "delete"}, repo.calls)
+		_, err = store.Get(ctx, "inbound/acc-1/2026-01-02/a.json")
+		assert.ErrorIs(t, err, blob.ErrNotFound)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		repo := &fakeErasureRepo{}
+		svc := NewErasureService(repo, nil)
+
+		_, err := svc.EraseKakaoUser(ctx, " ", model.ErasureModeDelete, false)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+		_, err = svc.EraseKakaoUser(ctx, "user-1", "purge", true)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+		assert.Empty(t, repo.calls)
+	})
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// RouteMemberTTL is how long a routed session counts as connected since its
+// last heartbeat. It outlasts the longest heartbeat interval, so only
+// sessions whose instance died without leaving drop out.
+const RouteMemberTTL = 2 * sse.MaxHeartbeatInterval
+
+const (
+	routeHash        = "hash"
+	routeLabelPrefix = "label:"
+)
+
+// EventRoute is what a plugin session asks to receive when an account runs
+// several: conversations carrying one of Labels, and with Hash a share of
+// the conversations no label route claims.
+type EventRoute struct {
+	Labels []string `json:"labels,omitempty"`
+	Hash   bool     `json:"hash,omitempty"`
+}
+
+// ParseEventRoute parses the route query parameter of the event stream, a
+// comma-separated list of "hash" and "label:<label>" entries.
+func ParseEventRoute(spec string) (EventRoute, error) {
+	var route EventRoute
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == routeHash:
+			route.Hash = true
+		case strings.HasPrefix(entry, routeLabelPrefix) && len(entry) > len(routeLabelPrefix):
+			route.Labels = append(route.Labels, strings.TrimPrefix(entry, routeLabelPrefix))
+		default:
+			return EventRoute{}, fmt.Errorf("invalid route entry %q", entry)
+		}
+	}
+	return route, nil
+}
+
+// routeMember is a routed session as stored in the registry.
+type routeMember struct {
+	EventRoute
+	// Connection that registered the session; a reconnect replaces it
+	Conn   string `json:"conn"`
+	SeenAt int64  `json:"seenAt"`
+}
+
+// leaveRouteScript removes the session only if the leaving connection still
+// owns it, so a late disconnect does not remove a newer connection, and
+// releases the conversations it owned.
+var leaveRouteScript = redis.NewScript(`
+local member = redis.call('HGET', KEYS[1], ARGV[1])
+if not member or cjson.decode(member).conn ~= ARGV[2] then
+    return 0
+end
+redis.call('HDEL', KEYS[1], ARGV[1])
+local owners = redis.call('HGETALL', KEYS[2])
+for i = 1, #owners, 2 do
+    if owners[i + 1] == ARGV[1] then
+        redis.call('HDEL', KEYS[2], owners[i])
+    end
+end
+return 1
+`)
+
+// claimRouteScript records the conversation's owner unless another instance
+// has claimed it first; ARGV[3] is the owner that was found gone.
+var claimRouteScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], ARGV[1])
+if current and current ~= ARGV[3] then
+    return current
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return ARGV[2]
+`)
+
+// EventRouter assigns each conversation of an account to one of its routed
+// plugin sessions, so that agents can scale out. Sessions are registered in
+// Redis and shared by all instances. A conversation goes to a session whose
+// labels include its label (nickname), otherwise to one of the hash
+// sessions, chosen by rendezvous hashing. The choice is recorded: the
+// conversation stays with its owner while it is connected, even when other
+// sessions join, and only moves when the owner leaves.
+type EventRouter struct {
+	client   *redisclient.Client
+	convRepo repository.ConversationRepository
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+var _ sse.Router = (*EventRouter)(nil)
+
+func NewEventRouter(client *redisclient.Client, convRepo repository.ConversationRepository) *EventRouter {
+	return &EventRouter{client: client, convRepo: convRepo, ttl: RouteMemberTTL, now: time.Now}
+}
+
+func eventRoutesKey(accountID string) string {
+	return fmt.Sprintf("event_routes:%s", accountID)
+}
+
+func eventRouteOwnersKey(accountID string) string {
+	return fmt.Sprintf("event_route_owners:%s", accountID)
+}
+
+// Join registers a connection of the session, or refreshes it on heartbeat.
+func (r *EventRouter) Join(ctx context.Context, accountID, sessionID, conn string, route EventRoute) error {
+	data, err := json.Marshal(routeMember{EventRoute: route, Conn: conn, SeenAt: r.now().UnixMilli()})
+	if err != nil {
+		return err
+	}
+
+	key := eventRoutesKey(accountID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, sessionID, data)
+		pipe.PExpire(ctx, key, r.ttl)
+		pipe.PExpire(ctx, eventRouteOwnersKey(accountID), r.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("join event routes: %w", err)
+	}
+	return nil
+}
+
+// Leave removes the connection's session, releases its conversations and
+// returns which of conversationKeys were routed to it, so their queued
+// messages can go to the sessions that take them over. It reports false when
+// the session has reconnected since and stays.
+func (r *EventRouter) Leave(ctx context.Context, accountID, sessionID, conn string, conversationKeys []string) ([]string, bool, error) {
+	members, err := r.members(ctx, accountID)
+	if err != nil {
+		return nil, false, err
+	}
+	if member, ok := members[sessionID]; !ok || member.Conn != conn {
+		return nil, false, nil
+	}
+
+	var owned []string
+	for _, key := range conversationKeys {
+		owner, err := r.owner(ctx, accountID, members, key)
+		if err != nil {
+			return nil, false, err
+		}
+		if owner == "" {
+			owner = r.pick(ctx, members, key)
+		}
+		if owner == sessionID {
+			owned = append(owned, key)
+		}
+	}
+
+	keys := []string{eventRoutesKey(accountID), eventRouteOwnersKey(accountID)}
+	left, err := leaveRouteScript.Run(ctx, r.client, keys, sessionID, conn).Int()
+	if err != nil {
+		return nil, false, fmt.Errorf("leave event routes: %w", err)
+	}
+	return owned, left > 0, nil
+}
+
+// Route returns the session that receives the conversation's events, or ""
+// when the account has no routed sessions. A conversation without a
+// connected owner is assigned one.
+func (r *EventRouter) Route(ctx context.Context, accountID, conversationKey string) string {
+	members, err := r.members(ctx, accountID)
+	if err != nil {
+		// Unrouted events still reach every session
+		log.Warn().Err(err).Str("accountId", accountID).Msg("failed to read event routes")
+		return ""
+	}
+	if len(members) == 0 {
+		return ""
+	}
+
+	owner, err := r.client.HGet(ctx, eventRouteOwnersKey(accountID), conversationKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Warn().Err(err).Str("accountId", accountID).Msg("failed to read event route owner")
+		return r.pick(ctx, members, conversationKey)
+	}
+	if _, ok := members[owner]; ok {
+		return owner
+	}
+
+	picked := r.pick(ctx, members, conversationKey)
+	claimed, err := claimRouteScript.Run(ctx, r.client, []string{eventRouteOwnersKey(accountID)},
+		conversationKey, picked, owner, r.ttl.Milliseconds()).Text()
+	if err != nil {
+		log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to claim event route")
+		return picked
+	}
+	return claimed
+}
+
+// Owner returns the connected session that owns the conversation, or ""
+// when it has none yet.
+func (r *EventRouter) Owner(ctx context.Context, accountID, conversationKey string) (string, error) {
+	members, err := r.members(ctx, accountID)
+	if err != nil {
+		return "", err
+	}
+	return r.owner(ctx, accountID, members, conversationKey)
+}
+
+func (r *EventRouter) owner(ctx context.Context, accountID string, members map[string]routeMember, conversationKey string) (string, error) {
+	owner, err := r.client.HGet(ctx, eventRouteOwnersKey(accountID), conversationKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read event route owner: %w", err)
+	}
+	if _, ok := members[owner]; !ok {
+		return "", nil
+	}
+	return owner, nil
+}
+
+// members returns the account's routed sessions seen within the TTL.
+func (r *EventRouter) members(ctx context.Context, accountID string) (map[string]routeMember, error) {
+	entries, err := r.client.HGetAll(ctx, eventRoutesKey(accountID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("read event routes: %w", err)
+	}
+
+	cutoff := r.now().Add(-r.ttl).UnixMilli()
+	members := make(map[string]routeMember, len(entries))
+	for sessionID, data := range entries {
+		var member routeMember
+		if err := json.Unmarshal([]byte(data), &member); err != nil || member.SeenAt < cutoff {
+			continue
+		}
+		members[sessionID] = member
+	}
+	return members, nil
+}
+
+// pick looks up the conversation's label when any session routes by label.
+func (r *EventRouter) pick(ctx context.Context, members map[string]routeMember, conversationKey string) string {
+	label := ""
+	for _, member := range members {
+		if len(member.Labels) == 0 {
+			continue
+		}
+		conv, err := r.convRepo.FindByKey(ctx, conversationKey)
+		if err != nil {
+			log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to load conversation label for routing")
+		} else if conv != nil && conv.Nickname != nil {
+			label = *conv.Nickname
+		}
+		break
+	}
+	return pickRoute(members, conversationKey, label)
+}
+
+// pickRoute chooses among the sessions with a matching label, then the hash
+// sessions, then all sessions, so that every conversation has an owner.
+func pickRoute(members map[string]routeMember, conversationKey, label string) string {
+	var byLabel, byHash, all []string
+	for sessionID, member := range members {
+		all = append(all, sessionID)
+		if member.Hash {
+			byHash = append(byHash, sessionID)
+		}
+		if label == "" {
+			continue
+		}
+		for _, l := range member.Labels {
+			if strings.EqualFold(l, label) {
+				byLabel = append(byLabel, sessionID)
+				break
+			}
+		}
+	}
+
+	candidates := all
+	if len(byLabel) > 0 {
+		candidates = byLabel
+	} else if len(byHash) > 0 {
+		candidates = byHash
+	}
+
+	best, bestScore := "", uint64(0)
+	for _, sessionID := range candidates {
+		h := fnv.New64a()
+		h.Write([]byte(sessionID + "|" + conversationKey))
+		if score := h.Sum64(); best == "" || score > bestScore {
+			best, bestScore = sessionID, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+func TestParseEventRoute(t *testing.T) {
+	route, err := ParseEventRoute("label:vip, hash,label:sales")
+	require.NoError(t, err)
+	assert.Equal(t, EventRoute{Labels: []string{"vip", "sales"}, Hash: true}, route)
+
+	for _, spec := range []string{"label:", "sticky", "hash,"} {
+		_, err := ParseEventRoute(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestPickRoute(t *testing.T) {
+	members := map[string]routeMember{
+		"vip":   {EventRoute: EventRoute{Labels: []string{"VIP"}}},
+		"hash1": {EventRoute: EventRoute{Hash: true}},
+		"hash2": {EventRoute: EventRoute{Hash: true}},
+		"hash3": {EventRoute: EventRoute{Hash: true}},
+	}
+
+	t.Run("prefers sessions with the label", func(t *testing.T) {
+		assert.Equal(t, "vip", pickRoute(members, "ch:a", "vip"))
+	})
+
+	t.Run("spreads unlabeled conversations over hash sessions", func(t *testing.T) {
+		owners := map[string]int{}
+		for i := 0; i < 100; i++ {
+			owners[pickRoute(members, fmt.Sprintf("ch:%d", i), "")]++
+		}
+		assert.Zero(t, owners["vip"])
+		assert.Len(t, owners, 3)
+	})
+
+	t.Run("only moves the conversations of a leaving session", func(t *testing.T) {
+		before := map[string]string{}
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("ch:%d", i)
+			before[key] = pickRoute(members, key, "")
+		}
+
+		remaining := map[string]routeMember{}
+		for id, member := range members {
+			if id != "hash2" {
+				remaining[id] = member
+			}
+		}
+		for key, owner := range before {
+			after := pickRoute(remaining, key, "")
+			if owner != "hash2" {
+				assert.Equal(t, owner, after, key)
+			} else {
+				assert.NotEqual(t, "hash2", after, key)
+			}
+		}
+	})
+
+	t.Run("falls back to any session", func(t *testing.T) {
+		only := map[string]routeMember{"vip": members["vip"]}
+		assert.Equal(t, "vip", pickRoute(only, "ch:a", ""))
+	})
+}
+
+func TestEventRouter(t *testing.T) {
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	defer client.Close()
+	ctx := context.Background()
+	client.Del(ctx, eventRoutesKey("acc-1"), eventRouteOwnersKey("acc-1"))
+
+	router := NewEventRouter(client, new(mockConversationRepo))
+	hash := EventRoute{Hash: true}
+
+	assert.Empty(t, router.Route(ctx, "acc-1", "ch:a"))
+
+	require.NoError(t, router.Join(ctx, "acc-1", "s1", "c1", hash))
+	require.NoError(t, router.Join(ctx, "acc-1", "s2", "c2", hash))
+	owner := router.Route(ctx, "acc-1", "ch:a")
+	assert.Contains(t, []string{"s1", "s2"}, owner)
+
+	other := map[string]string{"s1": "s2", "s2": "s1"}[owner]
+	conns := map[string]string{"s1": "c1", "s2": "c2"}
+
+	// The conversation sticks to its owner when sessions join
+	for i := 3; i <= 10; i++ {
+		id := fmt.Sprintf("s%d", i)
+		require.NoError(t, router.Join(ctx, "acc-1", id, "c"+id, EventRoute{}))
+	}
+	assert.Equal(t, owner, router.Route(ctx, "acc-1", "ch:a"))
+	recorded, err := router.Owner(ctx, "acc-1", "ch:a")
+	require.NoError(t, err)
+	assert.Equal(t, owner, recorded)
+
+	// A stale connection of the owner does not remove it
+	_, left, err := router.Leave(ctx, "acc-1", owner, "old", []string{"ch:a"})
+	require.NoError(t, err)
+	assert.False(t, left)
+
+	moved, left, err := router.Leave(ctx, "acc-1", owner, conns[owner], []string{"ch:a"})
+	require.NoError(t, err)
+	assert.True(t, left)
+	assert.Equal(t, []string{"ch:a"}, moved)
+	assert.Equal(t, other, router.Route(ctx, "acc-1", "ch:a"))
+	recorded, err = router.Owner(ctx, "acc-1", "ch:a")
+	require.NoError(t, err)
+	assert.Equal(t, other, recorded)
+}
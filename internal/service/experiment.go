@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// Experiments on relay-generated messages.
+const (
+	ExperimentUnpairedGreeting = "unpaired_greeting"
+	ExperimentHelp             = "help"
+)
+
+// ExperimentVariant is one text served to a bucket of conversations.
+// An empty Text serves the built-in default, which makes it the control.
+// Weight defaults to 1 when unset; a weight of 0 disables the variant.
+type ExperimentVariant struct {
+	Name   string `json:"name"`
+	Text   string `json:"text"`
+	Weight *int   `json:"weight,omitempty"`
+}
+
+type Experiment struct {
+	Name     string              `json:"name"`
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// LoadExperiments reads experiment definitions from a JSON file containing an
+// array of experiments. An empty path means no experiments are running.
+func LoadExperiments(path string) ([]Experiment, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read experiments file: %w", err)
+	}
+
+	var experiments []Experiment
+	if err := json.Unmarshal(data, &experiments); err != nil {
+		return nil, fmt.Errorf("parse experiments file: %w", err)
+	}
+
+	for _, exp := range experiments {
+		if exp.Name == "" {
+			return nil, fmt.Errorf("experiment name is required")
+		}
+		if len(exp.Variants) == 0 {
+			return nil, fmt.Errorf("experiment %q has no variants", exp.Name)
+		}
+		for _, v := range exp.Variants {
+			if v.Name == "" {
+				return nil, fmt.Errorf("experiment %q has a variant without a name", exp.Name)
+			}
+			if v.Weight != nil && *v.Weight < 0 {
+				return nil, fmt.Errorf("experiment %q variant %q has a negative weight", exp.Name, v.Name)
+			}
+		}
+	}
+
+	return experiments, nil
+}
+
+type ExperimentService struct {
+	experimentRepo repository.ExperimentRepository
+	experiments    map[string]Experiment
+}
+
+func NewExperimentService(experimentRepo repository.ExperimentRepository, experiments []Experiment) *ExperimentService {
+	byName := make(map[string]Experiment, len(experiments))
+	for _, exp := range experiments {
+		byName[exp.Name] = exp
+	}
+	return &ExperimentService{
+		experimentRepo: experimentRepo,
+		experiments:    byName,
+	}
+}
+
+// Assign returns the variant for a conversation. Assignment is a stable hash of
+// the experiment name and conversation key, so a conversation always lands in
+// the same bucket. Returns nil when the experiment is not running.
+func (s *ExperimentService) Assign(experiment, conversationKey string) *ExperimentVariant {
+	exp, ok := s.experiments[experiment]
+	if !ok {
+		return nil
+	}
+	return assignVariant(exp, conversationKey)
+}
+
+// Text returns the text for the conversation's variant and records the exposure.
+// When the experiment is not running, or the variant has no text, fallback is used.
+func (s *ExperimentService) Text(ctx context.Context, experiment, conversationKey, fallback string) string {
+	variant := s.Assign(experiment, conversationKey)
+	if variant == nil {
+		return fallback
+	}
+
+	if err := s.experimentRepo.RecordExposure(ctx, experiment, variant.Name, conversationKey); err != nil {
+		log.Warn().Err(err).
+			Str("experiment", experiment).
+			Str("variant", variant.Name).
+			Msg("failed to record experiment exposure")
+	}
+
+	if variant.Text == "" {
+		return fallback
+	}
+	return variant.Text
+}
+
+func (s *ExperimentService) GetVariantStats(ctx context.Context) ([]model.ExperimentVariantStats, error) {
+	return s.experimentRepo.GetVariantStats(ctx)
+}
+
+func assignVariant(exp Experiment, conversationKey string) *ExperimentVariant {
+	total := 0
+	for _, v := range exp.Variants {
+		total += variantWeight(v)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(exp.Name + ":" + conversationKey))
+	bucket := int(h.Sum32() % uint32(total))
+
+	for i := range exp.Variants {
+		bucket -= variantWeight(exp.Variants[i])
+		if bucket < 0 {
+			return &exp.Variants[i]
+		}
+	}
+	return nil
+}
+
+// variantWeight treats an unset weight as 1 so that variants split evenly by
+// default. An explicit 0 keeps the variant out of assignment.
+func variantWeight(v ExperimentVariant) int {
+	if v.Weight == nil {
+		return 1
+	}
+	return *v.Weight
+}
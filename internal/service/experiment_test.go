@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type mockExperimentRepo struct {
+	mock.Mock
+}
+
+func (m *mockExperimentRepo) RecordExposure(ctx context.Context, experiment, variant, conversationKey string) error {
+	args := m.Called(ctx, experiment, variant, conversationKey)
+	return args.Error(0)
+}
+
+func (m *mockExperimentRepo) GetVariantStats(ctx context.Context) ([]model.ExperimentVariantStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ExperimentVariantStats), args.Error(1)
+}
+
+func TestExperimentService_Assign(t *testing.T) {
+	svc := NewExperimentService(new(mockExperimentRepo), []Experiment{
+		{
+			Name: ExperimentHelp,
+			Variants: []ExperimentVariant{
+				{Name: "control"},
+				{Name: "short", Text: "short help"},
+			},
+		},
+	})
+
+	t.Run("returns nil for unknown experiment", func(t *testing.T) {
+		assert.Nil(t, svc.Assign("unknown", "ch:user"))
+	})
+
+	t.Run("assignment is stable per conversation", func(t *testing.T) {
+		first := svc.Assign(ExperimentHelp, "ch:user-1")
+		require.NotNil(t, first)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, first.Name, svc.Assign(ExperimentHelp, "ch:user-1").Name)
+		}
+	})
+
+	t.Run("splits conversations across variants", func(t *testing.T) {
+		seen := make(map[string]int)
+		for i := 0; i < 200; i++ {
+			v := svc.Assign(ExperimentHelp, fmt.Sprintf("ch:user-%d", i))
+			seen[v.Name]++
+		}
+		assert.Greater(t, seen["control"], 0)
+		assert.Greater(t, seen["short"], 0)
+	})
+
+	t.Run("never assigns variants with weight 0", func(t *testing.T) {
+		zero := 0
+		svc := NewExperimentService(new(mockExperimentRepo), []Experiment{
+			{
+				Name: ExperimentHelp,
+				Variants: []ExperimentVariant{
+					{Name: "control"},
+					{Name: "disabled", Text: "disabled help", Weight: &zero},
+				},
+			},
+		})
+		for i := 0; i < 200; i++ {
+			assert.Equal(t, "control", svc.Assign(ExperimentHelp, fmt.Sprintf("ch:user-%d", i)).Name)
+		}
+
+		allDisabled := NewExperimentService(new(mockExperimentRepo), []Experiment{
+			{Name: ExperimentHelp, Variants: []ExperimentVariant{{Name: "disabled", Weight: &zero}}},
+		})
+		assert.Nil(t, allDisabled.Assign(ExperimentHelp, "ch:user"))
+	})
+}
+
+func TestExperimentService_Text(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uses fallback without recording when experiment is not running", func(t *testing.T) {
+		repo := new(mockExperimentRepo)
+		svc := NewExperimentService(repo, nil)
+
+		assert.Equal(t, "default", svc.Text(ctx, ExperimentHelp, "ch:user", "default"))
+		repo.AssertNotCalled(t, "RecordExposure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("serves variant text and records exposure", func(t *testing.T) {
+		repo := new(mockExperimentRepo)
+		repo.On("RecordExposure", ctx, ExperimentHelp, "only", "ch:user").Return(nil)
+		svc := NewExperimentService(repo, []Experiment{
+			{Name: ExperimentHelp, Variants: []ExperimentVariant{{Name: "only", Text: "variant help"}}},
+		})
+
+		assert.Equal(t, "variant help", svc.Text(ctx, ExperimentHelp, "ch:user", "default"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("control variant serves fallback but is still recorded", func(t *testing.T) {
+		repo := new(mockExperimentRepo)
+		repo.On("RecordExposure", ctx, ExperimentHelp, "control", "ch:user").Return(nil)
+		svc := NewExperimentService(repo, []Experiment{
+			{Name: ExperimentHelp, Variants: []ExperimentVariant{{Name: "control"}}},
+		})
+
+		assert.Equal(t, "default", svc.Text(ctx, ExperimentHelp, "ch:user", "default"))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestLoadExperiments(t *testing.T) {
+	t.Run("empty path means no experiments", func(t *testing.T) {
+		experiments, err := LoadExperiments("")
+		assert.NoError(t, err)
+		assert.Nil(t, experiments)
+	})
+
+	t.Run("parses experiments file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "experiments.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[
+			{"name": "help", "variants": [{"name": "control"}, {"name": "short", "text": "hi", "weight": 2}]}
+		]`), 0o600))
+
+		experiments, err := LoadExperiments(path)
+		require.NoError(t, err)
+		require.Len(t, experiments, 1)
+		assert.Equal(t, "help", experiments[0].Name)
+		assert.Nil(t, experiments[0].Variants[0].Weight)
+		require.NotNil(t, experiments[0].Variants[1].Weight)
+		assert.Equal(t, 2, *experiments[0].Variants[1].Weight)
+	})
+
+	t.Run("rejects experiment without variants", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "experiments.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "help", "variants": []}]`), 0o600))
+
+		_, err := LoadExperiments(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,43 @@
+package service
+
+import "sync/atomic"
+
+// InboundDegradedStats counts webhooks that could not be processed normally
+// since process start.
+type InboundDegradedStats struct {
+	// Webhooks answered in degraded mode because storage failed
+	Responses int64 `json:"responses"`
+	// Failed messages scheduled for another attempt
+	RetriesQueued int64 `json:"retriesQueued"`
+	// Messages given up on after InboundMaxAttempts and moved to the
+	// dead-letter list
+	RetriesDropped int64 `json:"retriesDropped"`
+}
+
+var inboundDegradedCounters struct {
+	responses      atomic.Int64
+	retriesQueued  atomic.Int64
+	retriesDropped atomic.Int64
+}
+
+// RecordInboundDegraded counts a webhook answered in degraded mode.
+func RecordInboundDegraded() {
+	inboundDegradedCounters.responses.Add(1)
+}
+
+func recordInboundRetryQueued() {
+	inboundDegradedCounters.retriesQueued.Add(1)
+}
+
+func recordInboundRetryDropped() {
+	inboundDegradedCounters.retriesDropped.Add(1)
+}
+
+// GetInboundDegradedStats returns the process-wide degraded mode counters.
+func GetInboundDegradedStats() InboundDegradedStats {
+	return InboundDegradedStats{
+		Responses:      inboundDegradedCounters.responses.Load(),
+		RetriesQueued:  inboundDegradedCounters.retriesQueued.Load(),
+		RetriesDropped: inboundDegradedCounters.retriesDropped.Load(),
+	}
+}
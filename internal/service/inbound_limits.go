@@ -0,0 +1,117 @@
+package service
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// InboundLimits caps how much of a webhook is stored with an inbound message
+// and forwarded in its SSE event. Zero values disable the corresponding cap.
+type InboundLimits struct {
+	// Webhook bodies above this size are rejected before decoding
+	MaxBodyBytes int64
+	// Stored kakao_payload; larger payloads are replaced by a marker
+	MaxPayloadBytes int
+	// Stored normalized_message; the text is cut to fit
+	MaxMessageBytes int
+}
+
+// InboundLimitStats counts enforced limits since process start.
+type InboundLimitStats struct {
+	Rejected          int64 `json:"rejected"`
+	PayloadsTruncated int64 `json:"payloadsTruncated"`
+	MessagesTruncated int64 `json:"messagesTruncated"`
+}
+
+var inboundLimitCounters struct {
+	rejected          atomic.Int64
+	payloadsTruncated atomic.Int64
+	messagesTruncated atomic.Int64
+}
+
+// RecordInboundRejected counts a webhook rejected for exceeding MaxBodyBytes.
+func RecordInboundRejected() {
+	inboundLimitCounters.rejected.Add(1)
+}
+
+// GetInboundLimitStats returns the process-wide limit counters.
+func GetInboundLimitStats() InboundLimitStats {
+	return InboundLimitStats{
+		Rejected:          inboundLimitCounters.rejected.Load(),
+		PayloadsTruncated: inboundLimitCounters.payloadsTruncated.Load(),
+		MessagesTruncated: inboundLimitCounters.messagesTruncated.Load(),
+	}
+}
+
+// Apply trims the payload and normalized message of params to the limits and
+// reports whether anything was truncated.
+func (l InboundLimits) Apply(params *CreateInboundParams) bool {
+	truncated := false
+	if l.MaxPayloadBytes > 0 && len(params.KakaoPayload) > l.MaxPayloadBytes {
+		params.KakaoPayload = truncationMarker(len(params.KakaoPayload))
+		inboundLimitCounters.payloadsTruncated.Add(1)
+		truncated = true
+	}
+	if l.MaxMessageBytes > 0 && len(params.NormalizedMessage) > l.MaxMessageBytes {
+		params.NormalizedMessage = trimNormalizedMessage(params.NormalizedMessage, l.MaxMessageBytes)
+		inboundLimitCounters.messagesTruncated.Add(1)
+		truncated = true
+	}
+	return truncated
+}
+
+func truncationMarker(originalBytes int) json.RawMessage {
+	data, _ := json.Marshal(map[string]any{
+		"truncated":     true,
+		"originalBytes": originalBytes,
+	})
+	return data
+}
+
+// trimNormalizedMessage shortens the "text" field until the message fits in
+// maxBytes, flagging it as truncated. Messages that cannot be made to fit
+// (no text, or other fields alone are too large) become a bare marker.
+func trimNormalizedMessage(raw json.RawMessage, maxBytes int) json.RawMessage {
+	var msg map[string]any
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return truncationMarker(len(raw))
+	}
+	text, ok := msg["text"].(string)
+	if !ok {
+		return truncationMarker(len(raw))
+	}
+
+	msg["truncated"] = true
+	msg["originalBytes"] = len(raw)
+	for {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return truncationMarker(len(raw))
+		}
+		if len(data) <= maxBytes {
+			return data
+		}
+		if text == "" {
+			return truncationMarker(len(raw))
+		}
+		// Escaping can make the encoded text longer than the string, so cut
+		// by the overflow and re-check.
+		text = truncateUTF8(text, len(text)-(len(data)-maxBytes))
+		msg["text"] = text
+	}
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
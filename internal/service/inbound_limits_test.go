@@ -0,0 +1,72 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundLimits_Apply(t *testing.T) {
+	t.Run("leaves small messages unchanged", func(t *testing.T) {
+		params := CreateInboundParams{
+			KakaoPayload:      json.RawMessage(`{"userRequest":{}}`),
+			NormalizedMessage: json.RawMessage(`{"text":"hi"}`),
+		}
+		limits := InboundLimits{MaxPayloadBytes: 100, MaxMessageBytes: 100}
+
+		assert.False(t, limits.Apply(&params))
+		assert.JSONEq(t, `{"userRequest":{}}`, string(params.KakaoPayload))
+		assert.JSONEq(t, `{"text":"hi"}`, string(params.NormalizedMessage))
+	})
+
+	t.Run("replaces oversized payload with marker", func(t *testing.T) {
+		payload := json.RawMessage(`{"contexts":["` + strings.Repeat("x", 200) + `"]}`)
+		params := CreateInboundParams{KakaoPayload: payload}
+		before := GetInboundLimitStats().PayloadsTruncated
+
+		assert.True(t, InboundLimits{MaxPayloadBytes: 100}.Apply(&params))
+
+		var marker map[string]any
+		require.NoError(t, json.Unmarshal(params.KakaoPayload, &marker))
+		assert.Equal(t, true, marker["truncated"])
+		assert.Equal(t, float64(len(payload)), marker["originalBytes"])
+		assert.Equal(t, before+1, GetInboundLimitStats().PayloadsTruncated)
+	})
+
+	t.Run("cuts message text to fit", func(t *testing.T) {
+		normalized, _ := json.Marshal(map[string]string{
+			"userId": "user-1",
+			"text":   strings.Repeat("가", 100),
+		})
+		params := CreateInboundParams{NormalizedMessage: normalized}
+
+		assert.True(t, InboundLimits{MaxMessageBytes: 120}.Apply(&params))
+		assert.LessOrEqual(t, len(params.NormalizedMessage), 120)
+
+		var msg map[string]any
+		require.NoError(t, json.Unmarshal(params.NormalizedMessage, &msg))
+		assert.Equal(t, "user-1", msg["userId"])
+		assert.Equal(t, true, msg["truncated"])
+		text := msg["text"].(string)
+		assert.NotEmpty(t, text)
+		assert.True(t, strings.HasPrefix(strings.Repeat("가", 100), text))
+	})
+
+	t.Run("falls back to marker when text cannot fit", func(t *testing.T) {
+		normalized, _ := json.Marshal(map[string]string{
+			"userId": strings.Repeat("u", 200),
+			"text":   "hello",
+		})
+		params := CreateInboundParams{NormalizedMessage: normalized}
+
+		assert.True(t, InboundLimits{MaxMessageBytes: 100}.Apply(&params))
+
+		var marker map[string]any
+		require.NoError(t, json.Unmarshal(params.NormalizedMessage, &marker))
+		assert.Equal(t, true, marker["truncated"])
+		assert.NotContains(t, marker, "userId")
+	})
+}
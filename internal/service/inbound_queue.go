@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+const (
+	inboundQueueReadyKey = "inbound_queue:ready"
+	inboundQueueHeldKey  = "inbound_queue:held"
+	inboundQueueDeadKey  = "inbound_queue:dead"
+	inboundQueueConvKeys = "inbound_queue:conv:*"
+
+	// InboundMaxAttempts is how often a message is tried before it is moved
+	// to the dead-letter list; retries back off from InboundRetryDelay,
+	// doubling each time. The conversation is held back meanwhile, so later
+	// messages are not handled before the one being retried.
+	InboundMaxAttempts = 5
+	InboundRetryDelay  = 30 * time.Second
+
+	// inboundQueueDeadMax caps the dead-letter list, which keeps the newest
+	// jobs for inspection.
+	inboundQueueDeadMax = 1000
+
+	// inboundQueueMaxDrain caps how many jobs one worker handles per lock,
+	// keeping well inside ConversationLockTTL.
+	inboundQueueMaxDrain = 20
+)
+
+// InboundJob is a webhook accepted on the fast path: everything needed to
+// record the message once the response has been sent to Kakao.
+type InboundJob struct {
+	ChannelID string `json:"channelId"`
+	UserKey   string `json:"userKey"`
+	AppUserID string `json:"appUserId,omitempty"`
+	// Language Kakao reported for the user, for notices sent by the worker
+	Lang              string          `json:"lang,omitempty"`
+	KakaoPayload      json.RawMessage `json:"kakaoPayload"`
+	NormalizedMessage json.RawMessage `json:"normalizedMessage"`
+	CallbackURL       *string         `json:"callbackUrl,omitempty"`
+	CallbackExpiresAt *time.Time      `json:"callbackExpiresAt,omitempty"`
+	ReceivedAt        time.Time       `json:"receivedAt"`
+	// Failed attempts so far
+	Attempts int `json:"attempts,omitempty"`
+}
+
+func (j *InboundJob) ConversationKey() string {
+	return BuildConversationKey(j.ChannelID, j.UserKey)
+}
+
+// InboundJobHandler records one queued webhook. The conversation is locked
+// while it runs.
+type InboundJobHandler func(ctx context.Context, job *InboundJob) error
+
+// InboundQueue defers webhook processing until after the response. Jobs of
+// one conversation are handled one at a time, in the order they were queued.
+type InboundQueue interface {
+	Enqueue(ctx context.Context, job *InboundJob) error
+	// ProcessNext waits up to wait for a conversation with queued jobs and
+	// handles them. It reports false when there was nothing to do. A job the
+	// handler fails stays at the head of its conversation, which is held back
+	// until the retry is due. A conversation another worker is draining, or
+	// one held back, is skipped; it is handled once that worker is done or
+	// the retry is due.
+	ProcessNext(ctx context.Context, wait time.Duration, handle InboundJobHandler) (bool, error)
+	// RetryLater counts a failed attempt of a job that was not queued yet,
+	// queues it and holds its conversation back until the retry is due. It
+	// reports false when the job was moved to the dead-letter list after
+	// InboundMaxAttempts.
+	RetryLater(ctx context.Context, job *InboundJob) (bool, error)
+	// PromoteRetries releases the conversations whose retry is due.
+	PromoteRetries(ctx context.Context) (int, error)
+	// SweepStranded marks conversations with queued jobs as ready again when
+	// no worker was told about them, e.g. after a worker died mid-drain.
+	SweepStranded(ctx context.Context) (int, error)
+}
+
+// inboundRetryDelay is the backoff before the given attempt.
+func inboundRetryDelay(attempts int) time.Duration {
+	return InboundRetryDelay << (attempts - 1)
+}
+
+// RedisInboundQueue keeps a job list per conversation plus a list of
+// conversations that have work. A worker claims a conversation by taking its
+// lock, so ordering holds across workers and instances. A job is removed only
+// after it was handled or given up on; if a worker dies mid-drain, the
+// remaining jobs are picked up by the next sweep. Conversations waiting for a
+// retry are kept in a sorted set scored by when the retry is due.
+type RedisInboundQueue struct {
+	client *redisclient.Client
+	locker ConversationLocker
+}
+
+func NewRedisInboundQueue(client *redisclient.Client, locker ConversationLocker) *RedisInboundQueue {
+	return &RedisInboundQueue{client: client, locker: locker}
+}
+
+func inboundQueueKey(conversationKey string) string {
+	return fmt.Sprintf("inbound_queue:conv:%s", conversationKey)
+}
+
+// releaseHeldScript marks the conversations whose retry is due as ready, in
+// one step so a conversation is neither lost nor released twice.
+var releaseHeldScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, conversationKey in ipairs(due) do
+    redis.call('ZREM', KEYS[1], conversationKey)
+    redis.call('RPUSH', KEYS[2], conversationKey)
+end
+return #due
+`)
+
+func (q *RedisInboundQueue) Enqueue(ctx context.Context, job *InboundJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal inbound job: %w", err)
+	}
+
+	key := job.ConversationKey()
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, inboundQueueKey(key), data)
+		pipe.RPush(ctx, inboundQueueReadyKey, key)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue inbound job: %w", err)
+	}
+	return nil
+}
+
+func (q *RedisInboundQueue) ProcessNext(ctx context.Context, wait time.Duration, handle InboundJobHandler) (bool, error) {
+	ready, err := q.client.BLPop(ctx, wait, inboundQueueReadyKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("wait for inbound jobs: %w", err)
+	}
+	conversationKey := ready[1]
+
+	unlock, err := q.locker.Lock(ctx, conversationKey)
+	if errors.Is(err, ErrConversationLockTimeout) {
+		// The holder checks for jobs queued meanwhile once it unlocks
+		log.Debug().Str("conversationKey", conversationKey).Msg("skipping inbound jobs of locked conversation")
+		return true, nil
+	}
+	if err != nil {
+		// Hand the conversation back so its jobs are not stranded
+		q.client.RPush(context.WithoutCancel(ctx), inboundQueueReadyKey, conversationKey)
+		return true, fmt.Errorf("lock conversation %s: %w", conversationKey, err)
+	}
+
+	// Checked under the lock, as a drain may have just held it back
+	held, err := q.held(ctx, conversationKey)
+	if err != nil {
+		unlock()
+		q.client.RPush(context.WithoutCancel(ctx), inboundQueueReadyKey, conversationKey)
+		return true, err
+	}
+	if held {
+		// PromoteRetries marks it ready once the retry is due
+		unlock()
+		return true, nil
+	}
+
+	err = q.drain(ctx, conversationKey, handle)
+	unlock()
+
+	// Jobs left behind by the drain limit, or queued while a skipped worker
+	// was told about them, need another worker
+	key := inboundQueueKey(conversationKey)
+	if held, heldErr := q.held(ctx, conversationKey); heldErr != nil || held {
+		return true, err
+	}
+	if n, lenErr := q.client.LLen(ctx, key).Result(); lenErr == nil && n > 0 {
+		q.client.RPush(ctx, inboundQueueReadyKey, conversationKey)
+	}
+	return true, err
+}
+
+// held reports whether the conversation waits for the retry of its first job.
+func (q *RedisInboundQueue) held(ctx context.Context, conversationKey string) (bool, error) {
+	err := q.client.ZScore(ctx, inboundQueueHeldKey, conversationKey).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check held conversation: %w", err)
+	}
+	return true, nil
+}
+
+// drain handles up to inboundQueueMaxDrain jobs of the locked conversation.
+// It stops at a failed job, which stays at the head until its retry.
+func (q *RedisInboundQueue) drain(ctx context.Context, conversationKey string, handle InboundJobHandler) error {
+	key := inboundQueueKey(conversationKey)
+	for i := 0; i < inboundQueueMaxDrain; i++ {
+		data, err := q.client.LIndex(ctx, key, 0).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read inbound job: %w", err)
+		}
+
+		var job InboundJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			log.Error().Err(err).Str("conversationKey", conversationKey).Msg("moving malformed inbound job to the dead-letter list")
+			_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				deadLetter(ctx, pipe, data)
+				pipe.LPop(ctx, key)
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("remove inbound job: %w", err)
+			}
+			continue
+		}
+
+		handleErr := handle(ctx, &job)
+		if handleErr == nil {
+			if err := q.client.LPop(ctx, key).Err(); err != nil {
+				return fmt.Errorf("remove inbound job: %w", err)
+			}
+			continue
+		}
+
+		log.Error().Err(handleErr).Str("conversationKey", conversationKey).Msg("failed to process inbound job")
+		// The job stays at the head with its attempt counted, so later jobs
+		// wait for it; one given up on is removed and the drain goes on
+		var retrying bool
+		_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			var err error
+			retrying, err = queueRetry(ctx, pipe, &job, func(data []byte) {
+				pipe.LSet(ctx, key, 0, data)
+			})
+			if err != nil {
+				return err
+			}
+			if !retrying {
+				pipe.LPop(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("schedule inbound retry: %w", err)
+		}
+		recordInboundRetry(&job, retrying)
+		if retrying {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *RedisInboundQueue) RetryLater(ctx context.Context, job *InboundJob) (bool, error) {
+	var retrying bool
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		var err error
+		retrying, err = queueRetry(ctx, pipe, job, func(data []byte) {
+			pipe.RPush(ctx, inboundQueueKey(job.ConversationKey()), data)
+		})
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("schedule inbound retry: %w", err)
+	}
+	recordInboundRetry(job, retrying)
+	return retrying, nil
+}
+
+// queueRetry counts a failed attempt of job and adds the commands that
+// schedule its retry to pipe: store writes the updated job and its
+// conversation is held back until the retry is due. After
+// InboundMaxAttempts the job is moved to the dead-letter list instead. It
+// reports whether the job will be retried.
+func queueRetry(ctx context.Context, pipe redis.Pipeliner, job *InboundJob, store func(data []byte)) (bool, error) {
+	job.Attempts++
+	data, err := json.Marshal(job)
+	if err != nil {
+		return false, fmt.Errorf("marshal inbound job: %w", err)
+	}
+
+	if job.Attempts >= InboundMaxAttempts {
+		deadLetter(ctx, pipe, data)
+		return false, nil
+	}
+	store(data)
+	due := time.Now().Add(inboundRetryDelay(job.Attempts))
+	// A later retry already holding the conversation is kept
+	pipe.ZAddGT(ctx, inboundQueueHeldKey, redis.Z{
+		Score:  float64(due.UnixMilli()),
+		Member: job.ConversationKey(),
+	})
+	return true, nil
+}
+
+// deadLetter adds the commands that keep a job given up on in the
+// dead-letter list.
+func deadLetter(ctx context.Context, pipe redis.Pipeliner, data []byte) {
+	pipe.LPush(ctx, inboundQueueDeadKey, data)
+	pipe.LTrim(ctx, inboundQueueDeadKey, 0, inboundQueueDeadMax-1)
+}
+
+func recordInboundRetry(job *InboundJob, retrying bool) {
+	if retrying {
+		recordInboundRetryQueued()
+		return
+	}
+	recordInboundRetryDropped()
+	log.Error().
+		Str("conversationKey", job.ConversationKey()).
+		Int("attempts", job.Attempts).
+		Msg("moved inbound message to the dead-letter list after repeated failures")
+}
+
+func (q *RedisInboundQueue) PromoteRetries(ctx context.Context) (int, error) {
+	released, err := releaseHeldScript.Run(ctx, q.client,
+		[]string{inboundQueueHeldKey, inboundQueueReadyKey},
+		time.Now().UnixMilli(),
+	).Int()
+	if err != nil {
+		return 0, fmt.Errorf("release held conversations: %w", err)
+	}
+	return released, nil
+}
+
+func (q *RedisInboundQueue) SweepStranded(ctx context.Context) (int, error) {
+	swept := 0
+	iter := q.client.Scan(ctx, 0, inboundQueueConvKeys, 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		conversationKey := strings.TrimPrefix(key, inboundQueueKey(""))
+
+		n, err := q.client.LLen(ctx, key).Result()
+		if err != nil {
+			return swept, fmt.Errorf("count inbound jobs: %w", err)
+		}
+		if n == 0 {
+			continue
+		}
+		// Released by PromoteRetries once the retry is due
+		held, err := q.held(ctx, conversationKey)
+		if err != nil {
+			return swept, err
+		}
+		if held {
+			continue
+		}
+		_, err = q.client.LPos(ctx, inboundQueueReadyKey, conversationKey, redis.LPosArgs{}).Result()
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, redis.Nil) {
+			return swept, fmt.Errorf("find ready conversation: %w", err)
+		}
+
+		if err := q.client.RPush(ctx, inboundQueueReadyKey, conversationKey).Err(); err != nil {
+			return swept, fmt.Errorf("mark conversation ready: %w", err)
+		}
+		swept++
+	}
+	if err := iter.Err(); err != nil {
+		return swept, fmt.Errorf("scan inbound queues: %w", err)
+	}
+	return swept, nil
+}
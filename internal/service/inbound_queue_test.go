@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+func TestRedisInboundQueue(t *testing.T) {
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	defer client.Close()
+	ctx := context.Background()
+	client.Del(ctx, inboundQueueReadyKey, inboundQueueKey("ch:a"), inboundQueueKey("ch:b"))
+
+	queue := NewRedisInboundQueue(client, NewMemoryConversationLocker())
+
+	for _, job := range []*InboundJob{
+		{ChannelID: "ch", UserKey: "a", AppUserID: "1"},
+		{ChannelID: "ch", UserKey: "b", AppUserID: "2"},
+		{ChannelID: "ch", UserKey: "a", AppUserID: "3"},
+	} {
+		require.NoError(t, queue.Enqueue(ctx, job))
+	}
+
+	var handled []string
+	handle := func(ctx context.Context, job *InboundJob) error {
+		handled = append(handled, job.AppUserID)
+		return nil
+	}
+
+	// The first notification drains all of conversation a, in order
+	ok, err := queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1", "3"}, handled)
+
+	for {
+		ok, err := queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+	}
+	assert.Equal(t, []string{"1", "3", "2"}, handled)
+}
+
+func TestRedisInboundQueue_Failures(t *testing.T) {
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	defer client.Close()
+	ctx := context.Background()
+	client.Del(ctx, inboundQueueReadyKey, inboundQueueHeldKey, inboundQueueDeadKey, inboundQueueKey("ch:f"), inboundQueueKey("ch:r"))
+
+	queue := NewRedisInboundQueue(client, NewMemoryConversationLocker())
+	require.NoError(t, queue.Enqueue(ctx, &InboundJob{ChannelID: "ch", UserKey: "f", AppUserID: "1"}))
+	require.NoError(t, queue.Enqueue(ctx, &InboundJob{ChannelID: "ch", UserKey: "f", AppUserID: "2"}))
+	require.NoError(t, queue.Enqueue(ctx, &InboundJob{ChannelID: "ch", UserKey: "f", AppUserID: "3", Attempts: InboundMaxAttempts - 1}))
+
+	var handled []string
+	failing := map[string]bool{"1": true, "3": true}
+	handle := func(ctx context.Context, job *InboundJob) error {
+		handled = append(handled, job.AppUserID)
+		if failing[job.AppUserID] {
+			return assert.AnError
+		}
+		return nil
+	}
+
+	// A failed job stays at the head and holds the later ones back
+	ok, err := queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1"}, handled)
+	jobs := client.LRange(ctx, inboundQueueKey("ch:f"), 0, -1).Val()
+	require.Len(t, jobs, 3)
+	assert.Contains(t, jobs[0], `"appUserId":"1"`)
+	assert.Contains(t, jobs[0], `"attempts":1`)
+	assert.Greater(t, client.ZScore(ctx, inboundQueueHeldKey, "ch:f").Val(), float64(time.Now().UnixMilli()))
+
+	// Notifications of the jobs queued after it find the conversation held
+	for ok {
+		ok, err = queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"1"}, handled)
+	swept, err := queue.SweepStranded(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, swept, "a held conversation is left to its retry")
+
+	promoted, err := queue.PromoteRetries(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, promoted, "the retry is not due yet")
+
+	// Once due, the conversation is handled in order again; the job out of
+	// attempts is kept as dead letter
+	client.ZAdd(ctx, inboundQueueHeldKey, redis.Z{Score: 0, Member: "ch:f"})
+	promoted, err = queue.PromoteRetries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, promoted)
+	failing["1"] = false
+	ok, err = queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1", "1", "2", "3"}, handled)
+	assert.Zero(t, client.LLen(ctx, inboundQueueKey("ch:f")).Val())
+	assert.Zero(t, client.ZCard(ctx, inboundQueueHeldKey).Val())
+
+	dead := client.LRange(ctx, inboundQueueDeadKey, 0, -1).Val()
+	require.Len(t, dead, 1)
+	assert.Contains(t, dead[0], `"appUserId":"3"`)
+
+	t.Run("retry of a message handled inline", func(t *testing.T) {
+		retrying, err := queue.RetryLater(ctx, &InboundJob{ChannelID: "ch", UserKey: "r", AppUserID: "4"})
+		require.NoError(t, err)
+		assert.True(t, retrying)
+
+		jobs := client.LRange(ctx, inboundQueueKey("ch:r"), 0, -1).Val()
+		require.Len(t, jobs, 1)
+		assert.Contains(t, jobs[0], `"attempts":1`)
+		assert.NoError(t, client.ZScore(ctx, inboundQueueHeldKey, "ch:r").Err())
+	})
+}
+
+func TestRedisInboundQueue_SweepStranded(t *testing.T) {
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	defer client.Close()
+	ctx := context.Background()
+	client.Del(ctx, inboundQueueReadyKey, inboundQueueKey("ch:s"))
+
+	locker := NewMemoryConversationLocker()
+	locker.wait = 10 * time.Millisecond
+	queue := NewRedisInboundQueue(client, locker)
+
+	var handled []string
+	handle := func(ctx context.Context, job *InboundJob) error {
+		handled = append(handled, job.AppUserID)
+		return nil
+	}
+
+	// A conversation locked by another worker is skipped without an error
+	unlock, err := locker.Lock(ctx, "ch:s")
+	require.NoError(t, err)
+	require.NoError(t, queue.Enqueue(ctx, &InboundJob{ChannelID: "ch", UserKey: "s", AppUserID: "1"}))
+	ok, err := queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, handled)
+
+	// Its holder died, so the sweep has to find the job
+	unlock()
+	swept, err := queue.SweepStranded(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+	swept, err = queue.SweepStranded(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, swept, "a conversation already marked ready is not added twice")
+
+	ok, err = queue.ProcessNext(ctx, 100*time.Millisecond, handle)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1"}, handled)
+}
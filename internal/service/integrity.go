@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/alert"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+const integritySampleSize = 10
+
+// AlertIntegrityIssue fires for each check that found issues on startup, with
+// the check as subject.
+const AlertIntegrityIssue = "integrity_issue"
+
+// Integrity check names
+const (
+	IntegrityPairedSessionsWithoutAccount      = "paired_sessions_without_account"
+	IntegrityPairedConversationsWithoutAccount = "paired_conversations_without_account"
+	IntegrityStaleQueuedMessages               = "stale_queued_messages"
+	IntegrityOrphanPortalUsers                 = "orphan_portal_users"
+	IntegrityHalfPairedSessions                = "half_paired_sessions"
+)
+
+type integrityCheck struct {
+	name        string
+	description string
+	find        func(ctx context.Context) ([]string, error)
+	// repair fixes the found rows; nil means the finding is report-only
+	// because no fix is safe without a human decision.
+	repair func(ctx context.Context, ids []string) (int64, error)
+}
+
+// IntegrityService audits cross-table consistency and repairs the cases
+// where the fix is unambiguous.
+type IntegrityService struct {
+	repo        repository.IntegrityRepository
+	callbackTTL time.Duration
+}
+
+func NewIntegrityService(repo repository.IntegrityRepository, callbackTTL time.Duration) *IntegrityService {
+	return &IntegrityService{
+		repo:        repo,
+		callbackTTL: callbackTTL,
+	}
+}
+
+func (s *IntegrityService) checks() []integrityCheck {
+	staleBefore := time.Now().Add(-s.callbackTTL)
+
+	return []integrityCheck{
+		{
+			name:        IntegrityPairedSessionsWithoutAccount,
+			description: "Paired plugin sessions whose account no longer exists (repair: disconnect)",
+			find:        s.repo.FindPairedSessionsWithoutAccount,
+			repair:      s.repo.DisconnectSessionsWithoutAccount,
+		},
+		{
+			name:        IntegrityPairedConversationsWithoutAccount,
+			description: "Conversations marked paired to a deleted account (repair: unpair)",
+			find:        s.repo.FindPairedConversationsWithoutAccount,
+			repair:      s.repo.UnpairConversationsWithoutAccount,
+		},
+		{
+			name:        IntegrityStaleQueuedMessages,
+			description: "Queued inbound messages older than the callback TTL (repair: expire)",
+			find: func(ctx context.Context) ([]string, error) {
+				return s.repo.FindStaleQueuedMessages(ctx, staleBefore)
+			},
+			repair: func(ctx context.Context, ids []string) (int64, error) {
+				return s.repo.ExpireStaleQueuedMessages(ctx, ids, staleBefore)
+			},
+		},
+		{
+			name:        IntegrityHalfPairedSessions,
+			description: "Paired sessions whose conversation was not marked paired (repair: complete pairing)",
+			find:        s.repo.FindHalfPairedSessions,
+			repair:      s.repo.CompleteHalfPairedConversations,
+		},
+		{
+			name:        IntegrityOrphanPortalUsers,
+			description: "Portal users whose account no longer exists (report only)",
+			find:        s.repo.FindOrphanPortalUsers,
+		},
+	}
+}
+
+// CheckNames lists the available checks in the order they run.
+func (s *IntegrityService) CheckNames() []string {
+	checks := s.checks()
+	names := make([]string, len(checks))
+	for i, check := range checks {
+		names[i] = check.name
+	}
+	return names
+}
+
+// Run executes the named checks, or every check when none are named. With
+// repair set, repairable findings are fixed and the number of fixed rows is
+// reported per check.
+func (s *IntegrityService) Run(ctx context.Context, repair bool, only ...string) (*model.IntegrityReport, error) {
+	report := &model.IntegrityReport{
+		CheckedAt: time.Now(),
+		Repair:    repair,
+		Findings:  []model.IntegrityFinding{},
+	}
+
+	for _, check := range s.checks() {
+		if len(only) > 0 && !slices.Contains(only, check.name) {
+			continue
+		}
+
+		ids, err := check.find(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("integrity check %s: %w", check.name, err)
+		}
+
+		finding := model.IntegrityFinding{
+			Check:       check.name,
+			Description: check.description,
+			Count:       len(ids),
+			Repairable:  check.repair != nil,
+		}
+		if len(ids) > integritySampleSize {
+			finding.SampleIDs = ids[:integritySampleSize]
+		} else {
+			finding.SampleIDs = ids
+		}
+
+		if repair && check.repair != nil && len(ids) > 0 {
+			repaired, err := check.repair(ctx, ids)
+			if err != nil {
+				return nil, fmt.Errorf("integrity repair %s: %w", check.name, err)
+			}
+			finding.Repaired = repaired
+			log.Info().Str("check", check.name).Int64("repaired", repaired).Msg("integrity repair applied")
+		}
+
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return report, nil
+}
+
+// CheckOnStartup runs the audit and logs every finding with issues, notifying
+// operators of each.
+func (s *IntegrityService) CheckOnStartup(ctx context.Context, repair bool, notifier alert.Notifier) error {
+	report, err := s.Run(ctx, repair)
+	if err != nil {
+		return err
+	}
+
+	if report.Issues() == 0 {
+		log.Info().Msg("integrity check passed")
+		return nil
+	}
+	for _, f := range report.Findings {
+		if f.Count == 0 {
+			continue
+		}
+		log.Warn().
+			Str("check", f.Check).
+			Int("count", f.Count).
+			Strs("sampleIds", f.SampleIDs).
+			Int64("repaired", f.Repaired).
+			Msg("integrity issue found")
+		notifier.Notify(ctx, alert.Alert{
+			Name:    AlertIntegrityIssue,
+			Kind:    alert.KindAnomaly,
+			Subject: f.Check,
+			Status:  alert.StatusFiring,
+			Message: f.Description,
+			Details: map[string]any{
+				"count":     f.Count,
+				"sampleIds": f.SampleIDs,
+				"repaired":  f.Repaired,
+			},
+			At: report.CheckedAt,
+		})
+	}
+	return nil
+}
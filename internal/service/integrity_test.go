@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIntegrityRepo struct {
+	mock.Mock
+}
+
+func (m *mockIntegrityRepo) ids(args mock.Arguments) ([]string, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockIntegrityRepo) FindPairedSessionsWithoutAccount(ctx context.Context) ([]string, error) {
+	return m.ids(m.Called(ctx))
+}
+
+func (m *mockIntegrityRepo) FindPairedConversationsWithoutAccount(ctx context.Context) ([]string, error) {
+	return m.ids(m.Called(ctx))
+}
+
+func (m *mockIntegrityRepo) FindStaleQueuedMessages(ctx context.Context, before time.Time) ([]string, error) {
+	return m.ids(m.Called(ctx, before))
+}
+
+func (m *mockIntegrityRepo) FindOrphanPortalUsers(ctx context.Context) ([]string, error) {
+	return m.ids(m.Called(ctx))
+}
+
+func (m *mockIntegrityRepo) FindHalfPairedSessions(ctx context.Context) ([]string, error) {
+	return m.ids(m.Called(ctx))
+}
+
+func (m *mockIntegrityRepo) DisconnectSessionsWithoutAccount(ctx context.Context, ids []string) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockIntegrityRepo) UnpairConversationsWithoutAccount(ctx context.Context, keys []string) (int64, error) {
+	args := m.Called(ctx, keys)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockIntegrityRepo) ExpireStaleQueuedMessages(ctx context.Context, ids []string, before time.Time) (int64, error) {
+	args := m.Called(ctx, ids, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockIntegrityRepo) CompleteHalfPairedConversations(ctx context.Context, sessionIDs []string) (int64, error) {
+	args := m.Called(ctx, sessionIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newIntegrityRepoWithFindings() *mockIntegrityRepo {
+	repo := new(mockIntegrityRepo)
+	repo.On("FindPairedSessionsWithoutAccount", mock.Anything).Return([]string{"s1", "s2"}, nil)
+	repo.On("FindPairedConversationsWithoutAccount", mock.Anything).Return([]string{}, nil)
+	repo.On("FindStaleQueuedMessages", mock.Anything, mock.AnythingOfType("time.Time")).Return([]string{"m1"}, nil)
+	repo.On("FindHalfPairedSessions", mock.Anything).Return([]string{"s3"}, nil)
+	repo.On("FindOrphanPortalUsers", mock.Anything).Return([]string{"u1"}, nil)
+	return repo
+}
+
+func TestIntegrityService_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports findings without repairing", func(t *testing.T) {
+		repo := newIntegrityRepoWithFindings()
+		svc := NewIntegrityService(repo, 55*time.Second)
+
+		report, err := svc.Run(ctx, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, report.Issues())
+		require.Len(t, report.Findings, 5)
+		assert.Equal(t, IntegrityPairedSessionsWithoutAccount, report.Findings[0].Check)
+		assert.Equal(t, []string{"s1", "s2"}, report.Findings[0].SampleIDs)
+		assert.False(t, report.Findings[4].Repairable)
+		repo.AssertNotCalled(t, "DisconnectSessionsWithoutAccount", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repairs only repairable findings", func(t *testing.T) {
+		repo := newIntegrityRepoWithFindings()
+		repo.On("DisconnectSessionsWithoutAccount", mock.Anything, []string{"s1", "s2"}).Return(int64(2), nil)
+		repo.On("ExpireStaleQueuedMessages", mock.Anything, []string{"m1"}, mock.AnythingOfType("time.Time")).Return(int64(1), nil)
+		repo.On("CompleteHalfPairedConversations", mock.Anything, []string{"s3"}).Return(int64(1), nil)
+		svc := NewIntegrityService(repo, 55*time.Second)
+
+		report, err := svc.Run(ctx, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), report.Findings[0].Repaired)
+		assert.Equal(t, int64(1), report.Findings[2].Repaired)
+		assert.Equal(t, int64(1), report.Findings[3].Repaired)
+		assert.Equal(t, int64(0), report.Findings[4].Repaired)
+		repo.AssertNotCalled(t, "UnpairConversationsWithoutAccount", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("samples large findings", func(t *testing.T) {
+		ids := make([]string, 25)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("s%d", i)
+		}
+		repo := new(mockIntegrityRepo)
+		repo.On("FindPairedSessionsWithoutAccount", mock.Anything).Return(ids, nil)
+		repo.On("FindPairedConversationsWithoutAccount", mock.Anything).Return(nil, nil)
+		repo.On("FindStaleQueuedMessages", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("FindHalfPairedSessions", mock.Anything).Return(nil, nil)
+		repo.On("FindOrphanPortalUsers", mock.Anything).Return(nil, nil)
+		svc := NewIntegrityService(repo, 55*time.Second)
+
+		report, err := svc.Run(ctx, false)
+		require.NoError(t, err)
+		assert.Equal(t, 25, report.Findings[0].Count)
+		assert.Len(t, report.Findings[0].SampleIDs, integritySampleSize)
+	})
+
+	t.Run("runs only the named checks", func(t *testing.T) {
+		repo := new(mockIntegrityRepo)
+		repo.On("FindHalfPairedSessions", mock.Anything).Return([]string{"s3"}, nil)
+		repo.On("CompleteHalfPairedConversations", mock.Anything, []string{"s3"}).Return(int64(1), nil)
+		svc := NewIntegrityService(repo, 55*time.Second)
+
+		report, err := svc.Run(ctx, true, IntegrityHalfPairedSessions)
+		require.NoError(t, err)
+		require.Len(t, report.Findings, 1)
+		assert.Equal(t, IntegrityHalfPairedSessions, report.Findings[0].Check)
+		assert.Equal(t, int64(1), report.Findings[0].Repaired)
+		repo.AssertNotCalled(t, "FindPairedSessionsWithoutAccount", mock.Anything)
+	})
+
+	t.Run("returns query errors", func(t *testing.T) {
+		repo := new(mockIntegrityRepo)
+		repo.On("FindPairedSessionsWithoutAccount", mock.Anything).Return(nil, errors.New("db down"))
+		svc := NewIntegrityService(repo, 55*time.Second)
+
+		_, err := svc.Run(ctx, false)
+		assert.Error(t, err)
+	})
+}
@@ -4,53 +4,114 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
 )
 
 const (
 	callbackTimeout = 5 * time.Second
+	// How much of Kakao's callback response is read, and how much of it kept
+	callbackResponseReadBytes    = 4096
+	callbackResponseSnippetBytes = 512
 )
 
-var allowedCallbackHosts = []string{
+// DefaultCallbackHosts are the hosts Kakao issues callback URLs on. An entry
+// starting with "." matches any subdomain; other entries match exactly.
+var DefaultCallbackHosts = []string{
 	".kakao.com",
 	".kakaocdn.net",
 	".kakaoenterprise.com",
 }
 
+// errPrivateCallbackAddress is returned when a callback host resolves to an
+// address the relay must not connect to.
+var errPrivateCallbackAddress = errors.New("callback host resolves to a non-public address")
+
+// reservedPrefixes are non-public ranges that netip does not classify as
+// private or link-local: "this network" (reached as localhost on Linux) and
+// the carrier-grade NAT shared address space.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
 type KakaoService struct {
-	client *http.Client
+	client       *http.Client
+	allowedHosts []string
 }
 
-func NewKakaoService() *KakaoService {
+// NewKakaoService creates the service. Callbacks are only sent to
+// allowedHosts (DefaultCallbackHosts when empty), and never to private,
+// loopback or link-local addresses whatever the host resolves to, so a forged
+// webhook cannot make the relay call into its own network.
+func NewKakaoService(allowedHosts []string) *KakaoService {
+	if len(allowedHosts) == 0 {
+		allowedHosts = DefaultCallbackHosts
+	}
+
 	return &KakaoService{
-		client: &http.Client{
-			Timeout: callbackTimeout,
+		client:       newPublicHTTPClient(callbackTimeout),
+		allowedHosts: allowedHosts,
+	}
+}
+
+// newPublicHTTPClient returns a client that only connects to public
+// addresses and does not follow redirects, since a redirect could lead
+// anywhere. It is used for URLs the relay does not control.
+func newPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: blockNonPublicAddress,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
 		},
 	}
 }
 
-func (s *KakaoService) SendCallback(ctx context.Context, callbackURL string, payload any) error {
-	if !isValidCallbackURL(callbackURL) {
+// IsAllowedCallbackURL reports whether callbackURL may be used for a reply.
+func (s *KakaoService) IsAllowedCallbackURL(callbackURL string) bool {
+	return isValidCallbackURL(callbackURL, s.allowedHosts)
+}
+
+// SendCallback posts payload to a Kakao callback URL. Kakao's answer is
+// returned whenever one was received, also with the error of a failure status.
+func (s *KakaoService) SendCallback(ctx context.Context, callbackURL string, payload any) (*model.CallbackResponse, error) {
+	if !s.IsAllowedCallbackURL(callbackURL) {
 		log.Warn().Str("url", callbackURL).Msg("invalid callback URL rejected")
-		return fmt.Errorf("invalid callback URL")
+		return nil, fmt.Errorf("invalid callback URL")
 	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
+		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
 	start := time.Now()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -67,44 +128,108 @@ func (s *KakaoService) SendCallback(ctx context.Context, callbackURL string, pay
 			Str("url", callbackURL).
 			Dur("elapsed", elapsed).
 			Msg("kakao callback error")
-		return fmt.Errorf("callback request failed: %w", err)
+		return nil, fmt.Errorf("callback request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	result := readCallbackResponse(resp)
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		log.Error().
 			Str("url", callbackURL).
 			Int("status", resp.StatusCode).
+			Str("response", result.Body).
 			Dur("elapsed", elapsed).
 			Msg("kakao callback failed")
-		return fmt.Errorf("callback failed with status %d", resp.StatusCode)
+		return result, fmt.Errorf("callback failed with status %d", resp.StatusCode)
 	}
 
 	log.Info().
 		Str("url", callbackURL).
 		Int("status", resp.StatusCode).
+		Str("taskId", result.TaskID).
 		Dur("elapsed", elapsed).
 		Msg("kakao callback successful")
 
-	return nil
+	return result, nil
 }
 
-func isValidCallbackURL(rawURL string) bool {
+// readCallbackResponse keeps the start of Kakao's answer and the task ID it
+// may carry, e.g. {"taskId": "...", "status": "SUCCESS"}.
+func readCallbackResponse(resp *http.Response) *model.CallbackResponse {
+	result := &model.CallbackResponse{Status: resp.StatusCode}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, callbackResponseReadBytes))
+	if err != nil {
+		return result
+	}
+
+	var parsed struct {
+		TaskID string `json:"taskId"`
+	}
+	if json.Unmarshal(data, &parsed) == nil {
+		result.TaskID = parsed.TaskID
+	}
+	if len(data) > callbackResponseSnippetBytes {
+		data = data[:callbackResponseSnippetBytes]
+	}
+	result.Body = strings.ToValidUTF8(string(data), "")
+	return result
+}
+
+func isValidCallbackURL(rawURL string, allowedHosts []string) bool {
 	parsed, err := url.Parse(rawURL)
 	if err != nil {
 		return false
 	}
 
-	if parsed.Scheme != "https" {
+	if parsed.Scheme != "https" || parsed.User != nil {
+		return false
+	}
+	if port := parsed.Port(); port != "" && port != "443" {
 		return false
 	}
 
 	hostname := strings.ToLower(parsed.Hostname())
-	for _, suffix := range allowedCallbackHosts {
-		if strings.HasSuffix(hostname, suffix) {
+	if hostname == "" || net.ParseIP(hostname) != nil {
+		return false
+	}
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, ".") {
+			if strings.HasSuffix(hostname, allowed) {
+				return true
+			}
+		} else if hostname == allowed {
 			return true
 		}
 	}
 
 	return false
 }
+
+// blockNonPublicAddress is a net.Dialer Control function. It runs after DNS
+// resolution, so it also catches allowed hosts that resolve to an internal
+// address and DNS answers that change between validation and connect.
+func blockNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("parse callback address: %w", err)
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return errPrivateCallbackAddress
+	}
+	return nil
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+const (
+	kakaoProfileAPIURL  = "https://kapi.kakao.com/v2/user/me"
+	kakaoProfileTimeout = 5 * time.Second
+)
+
+// KakaoProfileFetcher looks up a Kakao user's profile by the app user ID
+// Kakao includes in webhooks of channels linked to a Kakao app.
+type KakaoProfileFetcher interface {
+	FetchProfile(ctx context.Context, appUserID string) (*model.KakaoProfile, error)
+}
+
+type kakaoProfileAPI struct {
+	client   *http.Client
+	url      string
+	adminKey string
+}
+
+// NewKakaoProfileAPI returns a fetcher that calls the Kakao user API with the
+// app's admin key.
+func NewKakaoProfileAPI(adminKey string) KakaoProfileFetcher {
+	return &kakaoProfileAPI{
+		client:   &http.Client{Timeout: kakaoProfileTimeout},
+		url:      kakaoProfileAPIURL,
+		adminKey: adminKey,
+	}
+}
+
+type kakaoUserResponse struct {
+	KakaoAccount struct {
+		Profile struct {
+			Nickname        string `json:"nickname"`
+			ProfileImageURL string `json:"profile_image_url"`
+		} `json:"profile"`
+	} `json:"kakao_account"`
+}
+
+func (a *kakaoProfileAPI) FetchProfile(ctx context.Context, appUserID string) (*model.KakaoProfile, error) {
+	query := url.Values{"target_id_type": {"user_id"}, "target_id": {appUserID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "KakaoAK "+a.adminKey)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request kakao user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kakao user API returned status %d", resp.StatusCode)
+	}
+
+	var user kakaoUserResponse
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("decode kakao user: %w", err)
+	}
+
+	profile := user.KakaoAccount.Profile
+	return &model.KakaoProfile{
+		Nickname:        optionalString(profile.Nickname),
+		ProfileImageURL: optionalString(profile.ProfileImageURL),
+		FetchedAt:       time.Now(),
+	}, nil
+}
+
+// KakaoProfileService keeps the Kakao profile of paired users on their
+// conversation mapping and decides whether plugins may see it.
+type KakaoProfileService struct {
+	fetcher     KakaoProfileFetcher
+	convRepo    repository.ConversationRepository
+	accountRepo repository.AccountRepository
+	ttl         time.Duration
+
+	// Conversation keys with a fetch in progress
+	refreshing sync.Map
+}
+
+// NewKakaoProfileService creates the service. A nil fetcher disables fetching;
+// profiles stored earlier are still served.
+func NewKakaoProfileService(
+	fetcher KakaoProfileFetcher,
+	convRepo repository.ConversationRepository,
+	accountRepo repository.AccountRepository,
+	ttl time.Duration,
+) *KakaoProfileService {
+	return &KakaoProfileService{
+		fetcher:     fetcher,
+		convRepo:    convRepo,
+		accountRepo: accountRepo,
+		ttl:         ttl,
+	}
+}
+
+// isStale reports whether conv's profile is missing or older than the TTL.
+func (s *KakaoProfileService) isStale(conv *model.ConversationMapping, now time.Time) bool {
+	return conv.KakaoProfileFetchedAt == nil || now.Sub(*conv.KakaoProfileFetchedAt) >= s.ttl
+}
+
+// Refresh fetches and stores the profile of conv's user when the stored one
+// is stale. appUserID is empty for channels not linked to a Kakao app, in
+// which case there is nothing to fetch.
+func (s *KakaoProfileService) Refresh(ctx context.Context, conv *model.ConversationMapping, appUserID string) error {
+	if s.fetcher == nil || appUserID == "" || !s.isStale(conv, time.Now()) {
+		return nil
+	}
+	if _, busy := s.refreshing.LoadOrStore(conv.ConversationKey, struct{}{}); busy {
+		return nil
+	}
+	defer s.refreshing.Delete(conv.ConversationKey)
+
+	profile, err := s.fetcher.FetchProfile(ctx, appUserID)
+	if err != nil {
+		return fmt.Errorf("fetch kakao profile: %w", err)
+	}
+	if err := s.convRepo.UpdateKakaoProfile(ctx, conv.ConversationKey, *profile); err != nil {
+		return fmt.Errorf("store kakao profile: %w", err)
+	}
+
+	conv.KakaoNickname = profile.Nickname
+	conv.KakaoProfileImageURL = profile.ProfileImageURL
+	conv.KakaoProfileFetchedAt = &profile.FetchedAt
+	return nil
+}
+
+// RefreshInBackground runs Refresh without holding up the caller, so a slow
+// Kakao API cannot delay the webhook response. The fetched profile shows up
+// from the conversation's next event.
+func (s *KakaoProfileService) RefreshInBackground(conv *model.ConversationMapping, appUserID string) {
+	if s.fetcher == nil || appUserID == "" || !s.isStale(conv, time.Now()) {
+		return
+	}
+	snapshot := *conv
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), kakaoProfileTimeout)
+		defer cancel()
+		if err := s.Refresh(ctx, &snapshot, appUserID); err != nil {
+			log.Warn().Err(err).Str("conversationKey", snapshot.ConversationKey).Msg("failed to refresh kakao profile")
+		}
+	}()
+}
+
+// Shared returns the profile of conv to include in events for accountID, or
+// nil when none is stored or the account does not share profiles.
+func (s *KakaoProfileService) Shared(ctx context.Context, accountID string, conv *model.ConversationMapping) *model.KakaoProfile {
+	profile := conv.KakaoProfile()
+	if profile == nil {
+		return nil
+	}
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		log.Warn().Err(err).Str("accountId", accountID).Msg("failed to load account for kakao profile")
+		return nil
+	}
+	if account == nil || !account.ShareKakaoProfile {
+		return nil
+	}
+	return profile
+}
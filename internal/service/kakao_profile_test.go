@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type stubProfileFetcher struct {
+	profile *model.KakaoProfile
+	calls   int
+}
+
+func (f *stubProfileFetcher) FetchProfile(ctx context.Context, appUserID string) (*model.KakaoProfile, error) {
+	f.calls++
+	return f.profile, nil
+}
+
+func TestKakaoProfileAPI_FetchProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "KakaoAK admin-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "user_id", r.URL.Query().Get("target_id_type"))
+		assert.Equal(t, "12345", r.URL.Query().Get("target_id"))
+		w.Write([]byte(`{"id":12345,"kakao_account":{"profile":{"nickname":"Alice","profile_image_url":""}}}`))
+	}))
+	defer server.Close()
+
+	api := NewKakaoProfileAPI("admin-key").(*kakaoProfileAPI)
+	api.url = server.URL
+
+	profile, err := api.FetchProfile(context.Background(), "12345")
+	require.NoError(t, err)
+	require.NotNil(t, profile.Nickname)
+	assert.Equal(t, "Alice", *profile.Nickname)
+	assert.Nil(t, profile.ProfileImageURL)
+	assert.False(t, profile.FetchedAt.IsZero())
+
+	api.adminKey = "wrong"
+	_, err = api.FetchProfile(context.Background(), "12345")
+	assert.Error(t, err)
+}
+
+func TestKakaoProfileService_Refresh(t *testing.T) {
+	nickname := "Alice"
+	fetched := &model.KakaoProfile{Nickname: &nickname, FetchedAt: time.Now()}
+
+	t.Run("fetches and stores a missing profile", func(t *testing.T) {
+		fetcher := &stubProfileFetcher{profile: fetched}
+		convRepo := new(mockConversationRepo)
+		convRepo.On("UpdateKakaoProfile", mock.Anything, "ch:user", *fetched).Return(nil)
+		svc := NewKakaoProfileService(fetcher, convRepo, newMockAccountRepo(), time.Hour)
+		conv := &model.ConversationMapping{ConversationKey: "ch:user"}
+
+		require.NoError(t, svc.Refresh(context.Background(), conv, "12345"))
+
+		assert.Equal(t, 1, fetcher.calls)
+		assert.Equal(t, &nickname, conv.KakaoNickname)
+		convRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips fresh profiles and channels without an app", func(t *testing.T) {
+		fetcher := &stubProfileFetcher{profile: fetched}
+		svc := NewKakaoProfileService(fetcher, new(mockConversationRepo), newMockAccountRepo(), time.Hour)
+		recent := time.Now().Add(-time.Minute)
+
+		require.NoError(t, svc.Refresh(context.Background(), &model.ConversationMapping{KakaoProfileFetchedAt: &recent}, "12345"))
+		require.NoError(t, svc.Refresh(context.Background(), &model.ConversationMapping{}, ""))
+
+		assert.Zero(t, fetcher.calls)
+	})
+
+	t.Run("does nothing without a fetcher", func(t *testing.T) {
+		svc := NewKakaoProfileService(nil, new(mockConversationRepo), newMockAccountRepo(), time.Hour)
+		assert.NoError(t, svc.Refresh(context.Background(), &model.ConversationMapping{}, "12345"))
+	})
+}
+
+func TestKakaoProfileService_Shared(t *testing.T) {
+	nickname := "Alice"
+	fetchedAt := time.Now()
+	conv := &model.ConversationMapping{KakaoNickname: &nickname, KakaoProfileFetchedAt: &fetchedAt}
+
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["sharing"] = &model.Account{ID: "sharing", ShareKakaoProfile: true}
+	accountRepo.accounts["opted-out"] = &model.Account{ID: "opted-out"}
+	svc := NewKakaoProfileService(nil, new(mockConversationRepo), accountRepo, time.Hour)
+
+	profile := svc.Shared(context.Background(), "sharing", conv)
+	require.NotNil(t, profile)
+	assert.Equal(t, "Alice", *profile.Nickname)
+
+	assert.Nil(t, svc.Shared(context.Background(), "opted-out", conv))
+	assert.Nil(t, svc.Shared(context.Background(), "sharing", &model.ConversationMapping{}))
+}
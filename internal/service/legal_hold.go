@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// maxLegalHoldReasonLength bounds the free-text reason stored with a hold
+const maxLegalHoldReasonLength = 500
+
+func validateLegalHoldReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", apperrors.InvalidInput("reason", "is required")
+	}
+	if utf8.RuneCountInString(reason) > maxLegalHoldReasonLength {
+		return "", apperrors.InvalidInput("reason", fmt.Sprintf("must be at most %d characters", maxLegalHoldReasonLength))
+	}
+	return reason, nil
+}
+
+// checkAccountLegalHold refuses deleting an account that is, or has a
+// conversation, under legal hold.
+func checkAccountLegalHold(ctx context.Context, accountRepo repository.AccountRepository, accountID string) error {
+	held, err := accountRepo.IsUnderLegalHold(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("check legal hold: %w", err)
+	}
+	if held {
+		return apperrors.LegalHold("Account")
+	}
+	return nil
+}
+
+// SetAccountLegalHold places the account under legal hold. Calling it on a
+// held account updates the reason.
+func (s *AdminService) SetAccountLegalHold(ctx context.Context, id, reason string) (*model.Account, error) {
+	reason, err := validateLegalHoldReason(reason)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.accountRepo.SetLegalHold(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+	log.Info().Str("accountId", id).Msg("account legal hold set")
+	return account, nil
+}
+
+// ReleaseAccountLegalHold lifts the account's hold. Returns NotFound when the
+// account is not held.
+func (s *AdminService) ReleaseAccountLegalHold(ctx context.Context, id string) (*model.Account, error) {
+	account, err := s.accountRepo.ReleaseLegalHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Legal hold")
+	}
+	log.Info().Str("accountId", id).Msg("account legal hold released")
+	return account, nil
+}
+
+// SetMappingLegalHold places a conversation under legal hold. Calling it on a
+// held conversation updates the reason.
+func (s *AdminService) SetMappingLegalHold(ctx context.Context, id, reason string) (*model.ConversationMapping, error) {
+	reason, err := validateLegalHoldReason(reason)
+	if err != nil {
+		return nil, err
+	}
+	conv, err := s.convRepo.SetLegalHold(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+	if conv == nil {
+		return nil, apperrors.NotFound("Mapping")
+	}
+	log.Info().Str("mappingId", id).Msg("conversation legal hold set")
+	return conv, nil
+}
+
+// ReleaseMappingLegalHold lifts a conversation's hold. Returns NotFound when
+// the conversation is not held.
+func (s *AdminService) ReleaseMappingLegalHold(ctx context.Context, id string) (*model.ConversationMapping, error) {
+	conv, err := s.convRepo.ReleaseLegalHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if conv == nil {
+		return nil, apperrors.NotFound("Legal hold")
+	}
+	log.Info().Str("mappingId", id).Msg("conversation legal hold released")
+	return conv, nil
+}
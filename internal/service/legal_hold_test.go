@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+func TestAdminService_AccountLegalHold(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+	svc := &AdminService{accountRepo: accountRepo, sessionSecret: "test-secret"}
+
+	_, err := svc.SetAccountLegalHold(ctx, "acc-1", "  ")
+	assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	_, err = svc.SetAccountLegalHold(ctx, "acc-1", strings.Repeat("x", maxLegalHoldReasonLength+1))
+	assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	_, err = svc.SetAccountLegalHold(ctx, "missing", "case 42")
+	assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+
+	account, err := svc.SetAccountLegalHold(ctx, "acc-1", " case 42 ")
+	require.NoError(t, err)
+	require.NotNil(t, account.LegalHoldAt)
+	assert.Equal(t, "case 42", *account.LegalHoldReason)
+
+	token := signAccountDeletion("test-secret", "acc-1", time.Now().Add(time.Minute))
+	err = svc.DeleteAccount(ctx, "acc-1", token)
+	assert.Equal(t, apperrors.ErrCodeLegalHold, apperrors.GetCode(err))
+	assert.Contains(t, accountRepo.accounts, "acc-1")
+
+	account, err = svc.ReleaseAccountLegalHold(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.Nil(t, account.LegalHoldAt)
+	_, err = svc.ReleaseAccountLegalHold(ctx, "acc-1")
+	assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+
+	require.NoError(t, svc.DeleteAccount(ctx, "acc-1", token))
+	assert.NotContains(t, accountRepo.accounts, "acc-1")
+}
+
+func TestAdminService_DeleteMappingUnderLegalHold(t *testing.T) {
+	ctx := context.Background()
+	convRepo := new(mockConversationRepo)
+	convRepo.On("IsUnderLegalHold", mock.Anything, "held").Return(true, nil)
+	convRepo.On("IsUnderLegalHold", mock.Anything, "free").Return(false, nil)
+	convRepo.On("Delete", mock.Anything, "free").Return(nil)
+	svc := &AdminService{convRepo: convRepo}
+
+	err := svc.DeleteMapping(ctx, "held")
+	assert.Equal(t, apperrors.ErrCodeLegalHold, apperrors.GetCode(err))
+	require.NoError(t, svc.DeleteMapping(ctx, "free"))
+
+	convRepo.AssertNotCalled(t, "Delete", mock.Anything, "held")
+	convRepo.AssertExpectations(t)
+}
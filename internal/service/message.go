@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 )
@@ -21,25 +21,81 @@ type CreateInboundParams struct {
 	CallbackURL       *string
 	CallbackExpiresAt *time.Time
 	SourceEventID     *string
+	// Consent state of the conversation; when denied only metadata is stored
+	ContentConsent *model.ContentConsent
 }
 
+// contentNotStored replaces the Kakao payload of messages stored without
+// their content.
+var contentNotStored = json.RawMessage(`{"contentStored":false}`)
+
 type MessageService struct {
 	inboundRepo  repository.InboundMessageRepository
 	outboundRepo repository.OutboundMessageRepository
+	timelineRepo repository.MessageTimelineRepository
+	accountRepo  repository.AccountRepository
+	statsCache   ConversationStatsCache
 }
 
+// NewMessageService creates the message service. accountRepo is used to
+// resolve account timezones for daily stats; when nil every account uses
+// model.DefaultTimezone. statsCache is optional.
 func NewMessageService(
 	inboundRepo repository.InboundMessageRepository,
 	outboundRepo repository.OutboundMessageRepository,
+	timelineRepo repository.MessageTimelineRepository,
+	accountRepo repository.AccountRepository,
+	statsCache ConversationStatsCache,
 ) *MessageService {
 	return &MessageService{
 		inboundRepo:  inboundRepo,
 		outboundRepo: outboundRepo,
+		timelineRepo: timelineRepo,
+		accountRepo:  accountRepo,
+		statsCache:   statsCache,
 	}
 }
 
+// invalidateConversationStats drops cached stats after a message is added to
+// the conversation.
+func (s *MessageService) invalidateConversationStats(ctx context.Context, conversationKey string) {
+	if s.statsCache != nil {
+		s.statsCache.Invalidate(ctx, conversationKey)
+	}
+}
+
+// startOfDay returns midnight of now's date in loc.
+func startOfDay(now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+}
+
+// accountLocation returns the time zone of the account's day boundaries. A
+// failed lookup falls back to the default zone rather than failing stats.
+func (s *MessageService) accountLocation(ctx context.Context, accountID string) *time.Location {
+	if s.accountRepo == nil {
+		return model.LoadTimezone("")
+	}
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		log.Warn().Err(err).Str("accountId", accountID).Msg("failed to load account timezone")
+		return model.LoadTimezone("")
+	}
+	if account == nil {
+		return model.LoadTimezone("")
+	}
+	return account.Location()
+}
+
 func (s *MessageService) CreateInbound(ctx context.Context, params CreateInboundParams) (*model.InboundMessage, error) {
-	msg, err := s.inboundRepo.Create(ctx, model.CreateInboundMessageParams{
+	if len(params.NormalizedMessage) > 0 {
+		if _, err := model.ParseNormalizedMessage(params.NormalizedMessage); err != nil {
+			return nil, apperrors.InvalidInput("normalizedMessage", err.Error())
+		}
+	}
+
+	storeContent := params.ContentConsent == nil || *params.ContentConsent == model.ContentConsentGranted
+	create := model.CreateInboundMessageParams{
 		AccountID:         params.AccountID,
 		ConversationKey:   params.ConversationKey,
 		KakaoPayload:      params.KakaoPayload,
@@ -47,15 +103,32 @@ func (s *MessageService) CreateInbound(ctx context.Context, params CreateInbound
 		CallbackURL:       params.CallbackURL,
 		CallbackExpiresAt: params.CallbackExpiresAt,
 		SourceEventID:     params.SourceEventID,
-	})
+	}
+	if !storeContent {
+		create.KakaoPayload = contentNotStored
+		create.NormalizedMessage = nil
+	}
+
+	msg, err := s.inboundRepo.Create(ctx, create)
 	if err != nil {
 		return nil, fmt.Errorf("create inbound message: %w", err)
 	}
+	s.invalidateConversationStats(ctx, params.ConversationKey)
+
+	// The content is still delivered to a connected plugin; only the stored
+	// row goes without it.
+	if !storeContent {
+		msg.KakaoPayload = params.KakaoPayload
+		if len(params.NormalizedMessage) > 0 {
+			msg.NormalizedMessage = &params.NormalizedMessage
+		}
+	}
 
 	log.Info().
 		Str("messageId", msg.ID).
 		Str("accountId", params.AccountID).
 		Str("conversationKey", params.ConversationKey).
+		Bool("contentStored", storeContent).
 		Msg("inbound message created")
 
 	return msg, nil
@@ -69,6 +142,12 @@ func (s *MessageService) FindQueuedByAccountID(ctx context.Context, accountID st
 	return s.inboundRepo.FindQueuedByAccountID(ctx, accountID)
 }
 
+// FindQueuedByConversationKey returns the conversation's undelivered
+// messages, including those held back by a delivery pause.
+func (s *MessageService) FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error) {
+	return s.inboundRepo.FindQueuedByConversationKey(ctx, conversationKey)
+}
+
 func (s *MessageService) MarkDelivered(ctx context.Context, id string) error {
 	if err := s.inboundRepo.MarkDelivered(ctx, id); err != nil {
 		return fmt.Errorf("mark delivered: %w", err)
@@ -85,11 +164,28 @@ func (s *MessageService) MarkAcked(ctx context.Context, id string) error {
 	return nil
 }
 
+// CreateOutbound records an outbound message. A ReplyOnce reply to a message
+// that already has a pending or sent one is refused with AlreadyReplied.
 func (s *MessageService) CreateOutbound(ctx context.Context, params model.CreateOutboundMessageParams) (*model.OutboundMessage, error) {
-	msg, err := s.outboundRepo.Create(ctx, params)
+	var msg *model.OutboundMessage
+	var err error
+	if params.ReplyOnce && params.InboundMessageID != nil {
+		var created bool
+		msg, created, err = s.outboundRepo.CreateReplyOnce(ctx, params)
+		if err == nil && !created {
+			log.Info().
+				Str("messageId", *params.InboundMessageID).
+				Str("outboundId", msg.ID).
+				Msg("refused duplicate reply")
+			return nil, apperrors.AlreadyReplied(msg.ID)
+		}
+	} else {
+		msg, err = s.outboundRepo.Create(ctx, params)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("create outbound message: %w", err)
 	}
+	s.invalidateConversationStats(ctx, params.ConversationKey)
 
 	log.Info().
 		Str("messageId", msg.ID).
@@ -100,6 +196,43 @@ func (s *MessageService) CreateOutbound(ctx context.Context, params model.Create
 	return msg, nil
 }
 
+// CancelOutbound cancels a pending outbound message of the account. Returns
+// NotFound for unknown or foreign messages and Conflict when the message
+// has already left the pending state.
+func (s *MessageService) CancelOutbound(ctx context.Context, accountID, id string) (*model.OutboundMessage, error) {
+	msg, err := s.outboundRepo.Cancel(ctx, id, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("cancel outbound message: %w", err)
+	}
+	if msg != nil {
+		log.Info().
+			Str("messageId", id).
+			Str("accountId", accountID).
+			Msg("outbound message cancelled")
+		return msg, nil
+	}
+
+	existing, err := s.outboundRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("find outbound message: %w", err)
+	}
+	if existing == nil || existing.AccountID != accountID {
+		return nil, apperrors.NotFound("Outbound message")
+	}
+	return nil, apperrors.New(apperrors.ErrCodeConflict, fmt.Sprintf("Outbound message is already %s", existing.Status)).
+		WithDetails(map[string]string{"status": string(existing.Status)})
+}
+
+// ListScheduledOutbound returns one page of the account's scheduled replies
+// that have not been sent yet.
+func (s *MessageService) ListScheduledOutbound(ctx context.Context, accountID string, page model.KeysetPage) ([]model.OutboundMessage, error) {
+	msgs, err := s.outboundRepo.FindScheduledByAccountID(ctx, accountID, page)
+	if err != nil {
+		return nil, fmt.Errorf("find scheduled outbound messages: %w", err)
+	}
+	return msgs, nil
+}
+
 func (s *MessageService) MarkOutboundSent(ctx context.Context, id string) error {
 	return s.outboundRepo.MarkSent(ctx, id)
 }
@@ -108,11 +241,18 @@ func (s *MessageService) MarkOutboundFailed(ctx context.Context, id, errorMsg st
 	return s.outboundRepo.MarkFailed(ctx, id, errorMsg)
 }
 
+// RecordCallbackResponse stores what Kakao answered to the outbound message.
+func (s *MessageService) RecordCallbackResponse(ctx context.Context, id string, resp model.CallbackResponse) error {
+	return s.outboundRepo.RecordCallbackResponse(ctx, id, resp)
+}
+
 type MessageHistoryParams struct {
-	AccountID string
-	Type      string // "inbound", "outbound", or "" for all
-	Limit     int
-	Offset    int
+	AccountID       string
+	Type            string // "inbound", "outbound", or "" for all
+	ConversationKey string // optional
+	Status          string // optional; an inbound or outbound message status
+	Limit           int
+	Offset          int
 }
 
 type MessageHistoryResult struct {
@@ -125,6 +265,7 @@ type MessageHistoryItem struct {
 	ID              string           `json:"id"`
 	ConversationKey string           `json:"conversationKey"`
 	Direction       string           `json:"direction"`
+	Status          string           `json:"status,omitempty"`
 	Content         *json.RawMessage `json:"content,omitempty"`
 	CreatedAt       time.Time        `json:"createdAt"`
 }
@@ -177,8 +318,7 @@ func (s *MessageService) GetUserStats(ctx context.Context, accountID string, con
 		}
 	}
 
-	now := time.Now()
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	todayStart := startOfDay(time.Now(), s.accountLocation(ctx, accountID))
 
 	inboundTotal, err := s.inboundRepo.CountByAccountID(ctx, accountID)
 	if err != nil {
@@ -282,8 +422,7 @@ type QuickStats struct {
 func (s *MessageService) GetQuickStats(ctx context.Context, accountID string) (*QuickStats, error) {
 	stats := &QuickStats{}
 
-	now := time.Now()
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	todayStart := startOfDay(time.Now(), s.accountLocation(ctx, accountID))
 
 	inboundTotal, err := s.inboundRepo.CountByAccountID(ctx, accountID)
 	if err != nil {
@@ -329,6 +468,11 @@ func (s *MessageService) GetMessageHistory(ctx context.Context, params MessageHi
 		params.Limit = 100
 	}
 
+	filter := model.MessageFilter{ConversationKey: params.ConversationKey, Status: params.Status}
+	if filter != (model.MessageFilter{}) {
+		return s.getFilteredMessageHistory(ctx, params, filter)
+	}
+
 	switch params.Type {
 	case "inbound":
 		inboundMsgs, err := s.inboundRepo.FindByAccountID(ctx, params.AccountID, params.Limit, params.Offset)
@@ -344,6 +488,7 @@ func (s *MessageService) GetMessageHistory(ctx context.Context, params MessageHi
 				ID:              msg.ID,
 				ConversationKey: msg.ConversationKey,
 				Direction:       "inbound",
+				Status:          string(msg.Status),
 				Content:         msg.NormalizedMessage,
 				CreatedAt:       msg.CreatedAt,
 			})
@@ -364,20 +509,16 @@ func (s *MessageService) GetMessageHistory(ctx context.Context, params MessageHi
 				ID:              msg.ID,
 				ConversationKey: msg.ConversationKey,
 				Direction:       "outbound",
+				Status:          string(msg.Status),
 				Content:         &payload,
 				CreatedAt:       msg.CreatedAt,
 			})
 		}
 
 	default:
-		// Fetch both and merge by created_at
-		inboundMsgs, err := s.inboundRepo.FindByAccountID(ctx, params.AccountID, params.Limit, params.Offset)
+		timeline, err := s.timelineRepo.FindByAccountID(ctx, params.AccountID, model.MessageFilter{}, params.Limit, params.Offset)
 		if err != nil {
-			return nil, fmt.Errorf("find inbound messages: %w", err)
-		}
-		outboundMsgs, err := s.outboundRepo.FindByAccountID(ctx, params.AccountID, params.Limit, params.Offset)
-		if err != nil {
-			return nil, fmt.Errorf("find outbound messages: %w", err)
+			return nil, fmt.Errorf("find messages: %w", err)
 		}
 
 		inboundCount, err := s.inboundRepo.CountByAccountID(ctx, params.AccountID)
@@ -390,35 +531,109 @@ func (s *MessageService) GetMessageHistory(ctx context.Context, params MessageHi
 		}
 		total = inboundCount + outboundCount
 
+		messages = timelineItems(timeline)
+	}
+
+	return &MessageHistoryResult{
+		Messages: messages,
+		Total:    total,
+		HasMore:  params.Offset+len(messages) < total,
+	}, nil
+}
+
+var (
+	inboundStatuses = map[string]bool{
+		string(model.InboundStatusQueued):    true,
+		string(model.InboundStatusDelivered): true,
+		string(model.InboundStatusAcked):     true,
+		string(model.InboundStatusExpired):   true,
+	}
+	outboundStatuses = map[string]bool{
+		string(model.OutboundStatusPending):   true,
+		string(model.OutboundStatusSent):      true,
+		string(model.OutboundStatusFailed):    true,
+		string(model.OutboundStatusCancelled): true,
+	}
+)
+
+// getFilteredMessageHistory is GetMessageHistory narrowed to a conversation
+// and/or status. A status must belong to the requested direction.
+func (s *MessageService) getFilteredMessageHistory(ctx context.Context, params MessageHistoryParams, filter model.MessageFilter) (*MessageHistoryResult, error) {
+	if filter.Status != "" {
+		valid := inboundStatuses[filter.Status] || outboundStatuses[filter.Status]
+		switch params.Type {
+		case "inbound":
+			valid = inboundStatuses[filter.Status]
+		case "outbound":
+			valid = outboundStatuses[filter.Status]
+		}
+		if !valid {
+			return nil, apperrors.InvalidInput("status", "unknown status for this message type")
+		}
+	}
+
+	var messages []MessageHistoryItem
+	var total int
+
+	switch params.Type {
+	case "inbound":
+		inboundMsgs, err := s.inboundRepo.FindByAccountIDFiltered(ctx, params.AccountID, filter, params.Limit, params.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("find inbound messages: %w", err)
+		}
+		total, err = s.inboundRepo.CountByAccountIDFiltered(ctx, params.AccountID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("count inbound messages: %w", err)
+		}
 		for _, msg := range inboundMsgs {
 			messages = append(messages, MessageHistoryItem{
 				ID:              msg.ID,
 				ConversationKey: msg.ConversationKey,
 				Direction:       "inbound",
+				Status:          string(msg.Status),
 				Content:         msg.NormalizedMessage,
 				CreatedAt:       msg.CreatedAt,
 			})
 		}
+
+	case "outbound":
+		outboundMsgs, err := s.outboundRepo.FindByAccountIDFiltered(ctx, params.AccountID, filter, params.Limit, params.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("find outbound messages: %w", err)
+		}
+		total, err = s.outboundRepo.CountByAccountIDFiltered(ctx, params.AccountID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("count outbound messages: %w", err)
+		}
 		for _, msg := range outboundMsgs {
 			payload := msg.ResponsePayload
 			messages = append(messages, MessageHistoryItem{
 				ID:              msg.ID,
 				ConversationKey: msg.ConversationKey,
 				Direction:       "outbound",
+				Status:          string(msg.Status),
 				Content:         &payload,
 				CreatedAt:       msg.CreatedAt,
 			})
 		}
 
-		// Sort by created_at descending
-		sort.Slice(messages, func(i, j int) bool {
-			return messages[i].CreatedAt.After(messages[j].CreatedAt)
-		})
+	default:
+		timeline, err := s.timelineRepo.FindByAccountID(ctx, params.AccountID, filter, params.Limit, params.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("find messages: %w", err)
+		}
 
-		// Limit results
-		if len(messages) > params.Limit {
-			messages = messages[:params.Limit]
+		inboundCount, err := s.inboundRepo.CountByAccountIDFiltered(ctx, params.AccountID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("count inbound messages: %w", err)
 		}
+		outboundCount, err := s.outboundRepo.CountByAccountIDFiltered(ctx, params.AccountID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("count outbound messages: %w", err)
+		}
+		total = inboundCount + outboundCount
+
+		messages = timelineItems(timeline)
 	}
 
 	return &MessageHistoryResult{
@@ -428,6 +643,21 @@ func (s *MessageService) GetMessageHistory(ctx context.Context, params MessageHi
 	}, nil
 }
 
+func timelineItems(timeline []model.TimelineMessage) []MessageHistoryItem {
+	items := make([]MessageHistoryItem, len(timeline))
+	for i, msg := range timeline {
+		items[i] = MessageHistoryItem{
+			ID:              msg.ID,
+			ConversationKey: msg.ConversationKey,
+			Direction:       msg.Direction,
+			Status:          msg.Status,
+			Content:         msg.Content,
+			CreatedAt:       msg.CreatedAt,
+		}
+	}
+	return items
+}
+
 // ConversationStats represents statistics for a specific conversation
 type ConversationStats struct {
 	ConversationKey string `json:"conversationKey"`
@@ -445,14 +675,33 @@ type ConversationStats struct {
 	} `json:"messages"`
 }
 
-// GetConversationStats returns statistics for a specific conversation
+// GetConversationStats returns statistics for a specific conversation. Results
+// are served from the stats cache when one is configured.
 func (s *MessageService) GetConversationStats(ctx context.Context, conversationKey string) (*ConversationStats, error) {
+	if s.statsCache != nil {
+		if stats, ok := s.statsCache.Get(ctx, conversationKey); ok {
+			return stats, nil
+		}
+	}
+
+	stats, err := s.countConversationStats(ctx, conversationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.statsCache != nil {
+		s.statsCache.Set(ctx, stats)
+	}
+	return stats, nil
+}
+
+func (s *MessageService) countConversationStats(ctx context.Context, conversationKey string) (*ConversationStats, error) {
 	stats := &ConversationStats{
 		ConversationKey: conversationKey,
 	}
 
-	now := time.Now()
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Code sessions are not tied to an account, so the default zone applies.
+	todayStart := startOfDay(time.Now(), model.LoadTimezone(""))
 
 	inboundTotal, err := s.inboundRepo.CountByConversationKey(ctx, conversationKey)
 	if err != nil {
@@ -528,6 +777,7 @@ func (s *MessageService) GetConversationMessages(ctx context.Context, params Con
 				ID:              msg.ID,
 				ConversationKey: msg.ConversationKey,
 				Direction:       "inbound",
+				Status:          string(msg.Status),
 				Content:         msg.NormalizedMessage,
 				CreatedAt:       msg.CreatedAt,
 			})
@@ -548,20 +798,16 @@ func (s *MessageService) GetConversationMessages(ctx context.Context, params Con
 				ID:              msg.ID,
 				ConversationKey: msg.ConversationKey,
 				Direction:       "outbound",
+				Status:          string(msg.Status),
 				Content:         &payload,
 				CreatedAt:       msg.CreatedAt,
 			})
 		}
 
 	default:
-		// Fetch both and merge
-		inboundMsgs, err := s.inboundRepo.FindByConversationKey(ctx, params.ConversationKey, params.Limit, params.Offset)
-		if err != nil {
-			return nil, fmt.Errorf("find inbound messages: %w", err)
-		}
-		outboundMsgs, err := s.outboundRepo.FindByConversationKey(ctx, params.ConversationKey, params.Limit, params.Offset)
+		timeline, err := s.timelineRepo.FindByConversationKey(ctx, params.ConversationKey, params.Limit, params.Offset)
 		if err != nil {
-			return nil, fmt.Errorf("find outbound messages: %w", err)
+			return nil, fmt.Errorf("find messages: %w", err)
 		}
 
 		inboundCount, err := s.inboundRepo.CountByConversationKey(ctx, params.ConversationKey)
@@ -574,35 +820,7 @@ func (s *MessageService) GetConversationMessages(ctx context.Context, params Con
 		}
 		total = inboundCount + outboundCount
 
-		for _, msg := range inboundMsgs {
-			messages = append(messages, MessageHistoryItem{
-				ID:              msg.ID,
-				ConversationKey: msg.ConversationKey,
-				Direction:       "inbound",
-				Content:         msg.NormalizedMessage,
-				CreatedAt:       msg.CreatedAt,
-			})
-		}
-		for _, msg := range outboundMsgs {
-			payload := msg.ResponsePayload
-			messages = append(messages, MessageHistoryItem{
-				ID:              msg.ID,
-				ConversationKey: msg.ConversationKey,
-				Direction:       "outbound",
-				Content:         &payload,
-				CreatedAt:       msg.CreatedAt,
-			})
-		}
-
-		// Sort by created_at descending
-		sort.Slice(messages, func(i, j int) bool {
-			return messages[i].CreatedAt.After(messages[j].CreatedAt)
-		})
-
-		// Limit results
-		if len(messages) > params.Limit {
-			messages = messages[:params.Limit]
-		}
+		messages = timelineItems(timeline)
 	}
 
 	return &MessageHistoryResult{
@@ -611,3 +829,21 @@ func (s *MessageService) GetConversationMessages(ctx context.Context, params Con
 		HasMore:  params.Offset+len(messages) < total,
 	}, nil
 }
+
+// ConversationLocale returns the locale of the conversation's latest inbound
+// message, or "" when it has none or the message predates locales.
+func (s *MessageService) ConversationLocale(ctx context.Context, conversationKey string) (string, error) {
+	messages, err := s.inboundRepo.FindByConversationKey(ctx, conversationKey, 1, 0)
+	if err != nil {
+		return "", fmt.Errorf("find latest inbound message: %w", err)
+	}
+	if len(messages) == 0 || messages[0].NormalizedMessage == nil {
+		return "", nil
+	}
+
+	var normalized model.NormalizedMessage
+	if err := json.Unmarshal(*messages[0].NormalizedMessage, &normalized); err != nil {
+		return "", nil
+	}
+	return normalized.Locale, nil
+}
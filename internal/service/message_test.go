@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/model"
 )
 
@@ -33,6 +34,14 @@ func (m *mockInboundRepo) FindQueuedByAccountID(ctx context.Context, accountID s
 	return args.Get(0).([]model.InboundMessage), args.Error(1)
 }
 
+func (m *mockInboundRepo) FindQueuedByConversationKey(ctx context.Context, conversationKey string) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, conversationKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
 func (m *mockInboundRepo) FindByAccountID(ctx context.Context, accountID string, limit, offset int) ([]model.InboundMessage, error) {
 	args := m.Called(ctx, accountID, limit, offset)
 	if args.Get(0) == nil {
@@ -54,6 +63,14 @@ func (m *mockInboundRepo) Create(ctx context.Context, params model.CreateInbound
 	return args.Get(0).(*model.InboundMessage), args.Error(1)
 }
 
+func (m *mockInboundRepo) CreateBatch(ctx context.Context, params []model.CreateInboundMessageParams) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
 func (m *mockInboundRepo) MarkDelivered(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -69,6 +86,32 @@ func (m *mockInboundRepo) MarkExpired(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockInboundRepo) ClaimWebhookDue(ctx context.Context, now, leaseUntil time.Time, maxAttempts, limit int) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, now, leaseUntil, maxAttempts, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
+func (m *mockInboundRepo) RecordWebhookFailure(ctx context.Context, id, errorMsg string, nextAt time.Time) error {
+	args := m.Called(ctx, id, errorMsg, nextAt)
+	return args.Error(0)
+}
+
+func (m *mockInboundRepo) ReleaseWebhookLease(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockInboundRepo) QueuedBacklog(ctx context.Context) (*model.InboundBacklog, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.InboundBacklog), args.Error(1)
+}
+
 func (m *mockInboundRepo) CountByStatus(ctx context.Context, status model.InboundMessageStatus) (int, error) {
 	args := m.Called(ctx, status)
 	return args.Int(0), args.Error(1)
@@ -84,6 +127,19 @@ func (m *mockInboundRepo) CountByAccountIDSince(ctx context.Context, accountID s
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockInboundRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.InboundMessage, error) {
+	args := m.Called(ctx, accountID, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InboundMessage), args.Error(1)
+}
+
+func (m *mockInboundRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	args := m.Called(ctx, accountID, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *mockInboundRepo) FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.InboundMessage, error) {
 	args := m.Called(ctx, conversationKey, limit, offset)
 	if args.Get(0) == nil {
@@ -114,6 +170,24 @@ func (m *mockOutboundRepo) FindByID(ctx context.Context, id string) (*model.Outb
 	return args.Get(0).(*model.OutboundMessage), args.Error(1)
 }
 
+func (m *mockOutboundRepo) Cancel(ctx context.Context, id, accountID string) (*model.OutboundMessage, error) {
+	args := m.Called(ctx, id, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OutboundMessage), args.Error(1)
+}
+
+func (m *mockOutboundRepo) FindScheduledByAccountID(ctx context.Context, accountID string, page model.KeysetPage) ([]model.OutboundMessage, error) {
+	args := m.Called(ctx, accountID, page)
+	return args.Get(0).([]model.OutboundMessage), args.Error(1)
+}
+
+func (m *mockOutboundRepo) ClaimDueScheduled(ctx context.Context, now time.Time, limit int) ([]model.OutboundMessage, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]model.OutboundMessage), args.Error(1)
+}
+
 func (m *mockOutboundRepo) FindPendingByAccountID(ctx context.Context, accountID string) ([]model.OutboundMessage, error) {
 	args := m.Called(ctx, accountID)
 	if args.Get(0) == nil {
@@ -143,6 +217,19 @@ func (m *mockOutboundRepo) Create(ctx context.Context, params model.CreateOutbou
 	return args.Get(0).(*model.OutboundMessage), args.Error(1)
 }
 
+func (m *mockOutboundRepo) CreateReplyOnce(ctx context.Context, params model.CreateOutboundMessageParams) (*model.OutboundMessage, bool, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*model.OutboundMessage), args.Bool(1), args.Error(2)
+}
+
+func (m *mockOutboundRepo) RecordCallbackResponse(ctx context.Context, id string, resp model.CallbackResponse) error {
+	args := m.Called(ctx, id, resp)
+	return args.Error(0)
+}
+
 func (m *mockOutboundRepo) MarkSent(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -168,6 +255,19 @@ func (m *mockOutboundRepo) CountByAccountIDSince(ctx context.Context, accountID
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockOutboundRepo) FindByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.OutboundMessage, error) {
+	args := m.Called(ctx, accountID, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OutboundMessage), args.Error(1)
+}
+
+func (m *mockOutboundRepo) CountByAccountIDFiltered(ctx context.Context, accountID string, filter model.MessageFilter) (int, error) {
+	args := m.Called(ctx, accountID, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *mockOutboundRepo) FindRecentFailedByAccountID(ctx context.Context, accountID string, limit int) ([]model.OutboundMessage, error) {
 	args := m.Called(ctx, accountID, limit)
 	if args.Get(0) == nil {
@@ -204,18 +304,38 @@ func (m *mockOutboundRepo) CountByConversationKeyAndStatusSince(ctx context.Cont
 	return args.Int(0), args.Error(1)
 }
 
+type mockTimelineRepo struct {
+	mock.Mock
+}
+
+func (m *mockTimelineRepo) FindByAccountID(ctx context.Context, accountID string, filter model.MessageFilter, limit, offset int) ([]model.TimelineMessage, error) {
+	args := m.Called(ctx, accountID, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TimelineMessage), args.Error(1)
+}
+
+func (m *mockTimelineRepo) FindByConversationKey(ctx context.Context, conversationKey string, limit, offset int) ([]model.TimelineMessage, error) {
+	args := m.Called(ctx, conversationKey, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TimelineMessage), args.Error(1)
+}
+
 func TestMessageService_CreateInbound(t *testing.T) {
 	t.Run("creates inbound message successfully", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		params := CreateInboundParams{
 			AccountID:         "acc-1",
 			ConversationKey:   "conv-1",
 			KakaoPayload:      json.RawMessage(`{"type": "text"}`),
-			NormalizedMessage: json.RawMessage(`{"text": "Hello"}`),
+			NormalizedMessage: json.RawMessage(`{"version": 1, "type": "text", "text": "Hello", "sender": {"userId": "user-1", "channelId": "ch-1"}, "userId": "user-1", "channelId": "ch-1"}`),
 		}
 
 		expectedMsg := &model.InboundMessage{
@@ -238,10 +358,70 @@ func TestMessageService_CreateInbound(t *testing.T) {
 		inboundRepo.AssertExpectations(t)
 	})
 
+	t.Run("rejects invalid normalized message", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		svc := NewMessageService(inboundRepo, new(mockOutboundRepo), nil, nil, nil)
+
+		for name, raw := range map[string]string{
+			"unversioned":  `{"userId": "user-1", "text": "Hello", "channelId": "ch-1"}`,
+			"future":       `{"version": 99, "type": "text", "text": "Hello", "sender": {"userId": "user-1"}, "userId": "user-1"}`,
+			"no sender":    `{"version": 1, "type": "text", "text": "Hello"}`,
+			"unknown type": `{"version": 1, "type": "sticker", "sender": {"userId": "user-1"}, "userId": "user-1"}`,
+			"empty text":   `{"version": 1, "type": "text", "text": "", "sender": {"userId": "user-1"}, "userId": "user-1"}`,
+			"bad json":     `{"version": `,
+		} {
+			_, err := svc.CreateInbound(context.Background(), CreateInboundParams{
+				AccountID:         "acc-1",
+				ConversationKey:   "conv-1",
+				NormalizedMessage: json.RawMessage(raw),
+			})
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), name)
+		}
+		inboundRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stores only metadata without consent", func(t *testing.T) {
+		payload := json.RawMessage(`{"userRequest": {"utterance": "secret"}}`)
+		normalized := json.RawMessage(`{"version": 1, "type": "text", "text": "secret", "sender": {"userId": "user-1", "channelId": "ch-1"}, "userId": "user-1", "channelId": "ch-1"}`)
+
+		for consent, stored := range map[model.ContentConsent]bool{
+			model.ContentConsentGranted: true,
+			model.ContentConsentDenied:  false,
+		} {
+			inboundRepo := new(mockInboundRepo)
+			svc := NewMessageService(inboundRepo, new(mockOutboundRepo), nil, nil, nil)
+
+			var created model.CreateInboundMessageParams
+			inboundRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				created = args.Get(1).(model.CreateInboundMessageParams)
+			}).Return(&model.InboundMessage{ID: "msg-1", KakaoPayload: contentNotStored}, nil)
+
+			msg, err := svc.CreateInbound(context.Background(), CreateInboundParams{
+				AccountID:         "acc-1",
+				ConversationKey:   "conv-1",
+				KakaoPayload:      payload,
+				NormalizedMessage: normalized,
+				ContentConsent:    &consent,
+			})
+
+			assert.NoError(t, err, consent)
+			if stored {
+				assert.JSONEq(t, string(payload), string(created.KakaoPayload), consent)
+				assert.NotNil(t, created.NormalizedMessage, consent)
+			} else {
+				assert.JSONEq(t, `{"contentStored": false}`, string(created.KakaoPayload), consent)
+				assert.Nil(t, created.NormalizedMessage, consent)
+				// The returned message still carries the content for live delivery
+				assert.JSONEq(t, string(payload), string(msg.KakaoPayload), consent)
+				assert.JSONEq(t, string(normalized), string(*msg.NormalizedMessage), consent)
+			}
+		}
+	})
+
 	t.Run("returns error when repository fails", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		params := CreateInboundParams{
@@ -264,7 +444,7 @@ func TestMessageService_FindInboundByID(t *testing.T) {
 	t.Run("finds message by ID", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		expectedMsg := &model.InboundMessage{
@@ -286,7 +466,7 @@ func TestMessageService_FindInboundByID(t *testing.T) {
 	t.Run("returns nil when not found", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("FindByID", ctx, "msg-unknown").Return(nil, nil)
@@ -303,7 +483,7 @@ func TestMessageService_FindQueuedByAccountID(t *testing.T) {
 	t.Run("finds queued messages", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		expectedMsgs := []model.InboundMessage{
@@ -325,7 +505,7 @@ func TestMessageService_MarkDelivered(t *testing.T) {
 	t.Run("marks message as delivered", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("MarkDelivered", ctx, "msg-1").Return(nil)
@@ -339,7 +519,7 @@ func TestMessageService_MarkDelivered(t *testing.T) {
 	t.Run("returns error when repository fails", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("MarkDelivered", ctx, "msg-1").Return(assert.AnError)
@@ -356,7 +536,7 @@ func TestMessageService_MarkAcked(t *testing.T) {
 	t.Run("marks message as acked", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("MarkAcked", ctx, "msg-1").Return(nil)
@@ -372,7 +552,7 @@ func TestMessageService_CreateOutbound(t *testing.T) {
 	t.Run("creates outbound message successfully", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundID := "msg-in-1"
@@ -406,7 +586,7 @@ func TestMessageService_MarkOutboundSent(t *testing.T) {
 	t.Run("marks outbound as sent", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		outboundRepo.On("MarkSent", ctx, "msg-out-1").Return(nil)
@@ -422,7 +602,7 @@ func TestMessageService_MarkOutboundFailed(t *testing.T) {
 	t.Run("marks outbound as failed", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		outboundRepo.On("MarkFailed", ctx, "msg-out-1", "connection timeout").Return(nil)
@@ -438,7 +618,7 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 	t.Run("returns inbound messages only", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		now := time.Now()
@@ -466,7 +646,7 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 	t.Run("returns outbound messages only", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		now := time.Now()
@@ -488,44 +668,111 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 		outboundRepo.AssertExpectations(t)
 	})
 
-	t.Run("returns all messages sorted by created_at", func(t *testing.T) {
+	t.Run("pages all messages through the merged timeline", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		timelineRepo := new(mockTimelineRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, timelineRepo, nil, nil)
 
 		ctx := context.Background()
 		now := time.Now()
 		normalized := json.RawMessage(`{"text": "Hello"}`)
-		inboundMsgs := []model.InboundMessage{
-			{ID: "msg-1", ConversationKey: "conv-1", NormalizedMessage: &normalized, CreatedAt: now.Add(-2 * time.Minute)},
-		}
-		outboundMsgs := []model.OutboundMessage{
-			{ID: "msg-out-1", ConversationKey: "conv-1", ResponsePayload: json.RawMessage(`{"text": "Reply"}`), CreatedAt: now.Add(-1 * time.Minute)},
+		reply := json.RawMessage(`{"text": "Reply"}`)
+		timeline := []model.TimelineMessage{
+			{ID: "msg-out-1", ConversationKey: "conv-1", Direction: "outbound", Content: &reply, CreatedAt: now.Add(-1 * time.Minute)},
+			{ID: "msg-1", ConversationKey: "conv-1", Direction: "inbound", Content: &normalized, CreatedAt: now.Add(-2 * time.Minute)},
 		}
 
-		inboundRepo.On("FindByAccountID", ctx, "acc-1", 20, 0).Return(inboundMsgs, nil)
-		outboundRepo.On("FindByAccountID", ctx, "acc-1", 20, 0).Return(outboundMsgs, nil)
-		inboundRepo.On("CountByAccountID", ctx, "acc-1").Return(1, nil)
-		outboundRepo.On("CountByAccountID", ctx, "acc-1").Return(1, nil)
+		timelineRepo.On("FindByAccountID", ctx, "acc-1", model.MessageFilter{}, 2, 2).Return(timeline, nil)
+		inboundRepo.On("CountByAccountID", ctx, "acc-1").Return(3, nil)
+		outboundRepo.On("CountByAccountID", ctx, "acc-1").Return(2, nil)
 
 		result, err := svc.GetMessageHistory(ctx, MessageHistoryParams{
 			AccountID: "acc-1",
 			Type:      "", // All
+			Limit:     2,
+			Offset:    2,
 		})
 
 		assert.NoError(t, err)
 		assert.Len(t, result.Messages, 2)
-		// Should be sorted by created_at descending (newest first)
 		assert.Equal(t, "msg-out-1", result.Messages[0].ID)
+		assert.Equal(t, "outbound", result.Messages[0].Direction)
 		assert.Equal(t, "msg-1", result.Messages[1].ID)
+		assert.Equal(t, 5, result.Total)
+		assert.True(t, result.HasMore) // 2 + 2 < 5
+		timelineRepo.AssertExpectations(t)
 		inboundRepo.AssertExpectations(t)
 		outboundRepo.AssertExpectations(t)
 	})
 
+	t.Run("filters outbound messages by status", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
+
+		ctx := context.Background()
+		filter := model.MessageFilter{Status: "failed"}
+		outboundRepo.On("FindByAccountIDFiltered", ctx, "acc-1", filter, 20, 0).Return([]model.OutboundMessage{
+			{ID: "out-1", ConversationKey: "conv-1", Status: model.OutboundStatusFailed},
+		}, nil)
+		outboundRepo.On("CountByAccountIDFiltered", ctx, "acc-1", filter).Return(1, nil)
+
+		result, err := svc.GetMessageHistory(ctx, MessageHistoryParams{
+			AccountID: "acc-1",
+			Type:      "outbound",
+			Status:    "failed",
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Messages, 1)
+		assert.Equal(t, "failed", result.Messages[0].Status)
+		assert.Equal(t, 1, result.Total)
+		outboundRepo.AssertExpectations(t)
+	})
+
+	t.Run("filters all directions by conversation", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		timelineRepo := new(mockTimelineRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, timelineRepo, nil, nil)
+
+		ctx := context.Background()
+		filter := model.MessageFilter{ConversationKey: "conv-1"}
+		timelineRepo.On("FindByAccountID", ctx, "acc-1", filter, 20, 0).Return([]model.TimelineMessage{
+			{ID: "in-1", ConversationKey: "conv-1", Direction: "inbound", Status: "acked"},
+		}, nil)
+		inboundRepo.On("CountByAccountIDFiltered", ctx, "acc-1", filter).Return(1, nil)
+		outboundRepo.On("CountByAccountIDFiltered", ctx, "acc-1", filter).Return(0, nil)
+
+		result, err := svc.GetMessageHistory(ctx, MessageHistoryParams{
+			AccountID:       "acc-1",
+			ConversationKey: "conv-1",
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Messages, 1)
+		assert.Equal(t, 1, result.Total)
+		timelineRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a status from the other direction", func(t *testing.T) {
+		svc := NewMessageService(new(mockInboundRepo), new(mockOutboundRepo), nil, nil, nil)
+
+		for _, params := range []MessageHistoryParams{
+			{AccountID: "acc-1", Type: "inbound", Status: "failed"},
+			{AccountID: "acc-1", Type: "outbound", Status: "queued"},
+			{AccountID: "acc-1", Status: "bogus"},
+		} {
+			_, err := svc.GetMessageHistory(context.Background(), params)
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), params.Status)
+		}
+	})
+
 	t.Run("limits results to specified limit", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("FindByAccountID", ctx, "acc-1", 5, 0).Return([]model.InboundMessage{}, nil)
@@ -544,7 +791,7 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 	t.Run("enforces max limit of 100", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("FindByAccountID", ctx, "acc-1", 100, 0).Return([]model.InboundMessage{}, nil)
@@ -563,7 +810,7 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 	t.Run("defaults limit to 20 when not specified", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		inboundRepo.On("FindByAccountID", ctx, "acc-1", 20, 0).Return([]model.InboundMessage{}, nil)
@@ -582,7 +829,7 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 	t.Run("calculates HasMore correctly", func(t *testing.T) {
 		inboundRepo := new(mockInboundRepo)
 		outboundRepo := new(mockOutboundRepo)
-		svc := NewMessageService(inboundRepo, outboundRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, nil)
 
 		ctx := context.Background()
 		now := time.Now()
@@ -607,3 +854,179 @@ func TestMessageService_GetMessageHistory(t *testing.T) {
 		inboundRepo.AssertExpectations(t)
 	})
 }
+
+func TestStartOfDay(t *testing.T) {
+	seoul := model.LoadTimezone("Asia/Seoul")
+
+	// 2024-03-01 16:30 UTC is already 2024-03-02 01:30 in Seoul.
+	now := time.Date(2024, 3, 1, 16, 30, 0, 0, time.UTC)
+
+	assert.True(t, startOfDay(now, seoul).Equal(time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)))
+	assert.True(t, startOfDay(now, time.UTC).Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestMessageService_GetQuickStats(t *testing.T) {
+	t.Run("counts today from the account's midnight", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1", Timezone: "America/New_York"}
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, accountRepo, nil)
+
+		ctx := context.Background()
+		loc := model.LoadTimezone("America/New_York")
+		sinceMidnight := mock.MatchedBy(func(since time.Time) bool {
+			return since.Equal(startOfDay(time.Now(), loc))
+		})
+
+		inboundRepo.On("CountByAccountID", ctx, "acc-1").Return(10, nil)
+		inboundRepo.On("CountByAccountIDSince", ctx, "acc-1", sinceMidnight).Return(3, nil)
+		outboundRepo.On("CountByAccountID", ctx, "acc-1").Return(8, nil)
+		outboundRepo.On("CountByAccountIDSince", ctx, "acc-1", sinceMidnight).Return(2, nil)
+		outboundRepo.On("CountByAccountIDAndStatus", ctx, "acc-1", model.OutboundStatusFailed).Return(1, nil)
+
+		stats, err := svc.GetQuickStats(ctx, "acc-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.InboundToday)
+		assert.Equal(t, 2, stats.OutboundToday)
+		inboundRepo.AssertExpectations(t)
+		outboundRepo.AssertExpectations(t)
+	})
+
+	t.Run("falls back to the default zone for unknown timezones", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1", Timezone: "Not/AZone"}
+		svc := NewMessageService(new(mockInboundRepo), new(mockOutboundRepo), nil, accountRepo, nil)
+
+		assert.Equal(t, model.DefaultTimezone, svc.accountLocation(context.Background(), "acc-1").String())
+		assert.Equal(t, model.DefaultTimezone, svc.accountLocation(context.Background(), "missing").String())
+	})
+}
+
+type fakeStatsCache struct {
+	entries map[string]*ConversationStats
+}
+
+func (c *fakeStatsCache) Get(ctx context.Context, conversationKey string) (*ConversationStats, bool) {
+	stats, ok := c.entries[conversationKey]
+	return stats, ok
+}
+
+func (c *fakeStatsCache) Set(ctx context.Context, stats *ConversationStats) {
+	c.entries[stats.ConversationKey] = stats
+}
+
+func (c *fakeStatsCache) Invalidate(ctx context.Context, conversationKey string) {
+	delete(c.entries, conversationKey)
+}
+
+func TestMessageService_GetConversationStats(t *testing.T) {
+	t.Run("serves repeat calls from the cache until a message arrives", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		cache := &fakeStatsCache{entries: map[string]*ConversationStats{}}
+		svc := NewMessageService(inboundRepo, outboundRepo, nil, nil, cache)
+
+		ctx := context.Background()
+		inboundRepo.On("CountByConversationKey", ctx, "conv-1").Return(4, nil).Twice()
+		inboundRepo.On("CountByConversationKeySince", ctx, "conv-1", mock.Anything).Return(1, nil).Twice()
+		outboundRepo.On("CountByConversationKey", ctx, "conv-1").Return(3, nil).Twice()
+		outboundRepo.On("CountByConversationKeySince", ctx, "conv-1", mock.Anything).Return(1, nil).Twice()
+		outboundRepo.On("CountByConversationKeyAndStatus", ctx, "conv-1", model.OutboundStatusFailed).Return(0, nil).Twice()
+		outboundRepo.On("CountByConversationKeyAndStatusSince", ctx, "conv-1", model.OutboundStatusFailed, mock.Anything).Return(0, nil).Twice()
+
+		first, err := svc.GetConversationStats(ctx, "conv-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 4, first.Messages.Inbound.Total)
+
+		cached, err := svc.GetConversationStats(ctx, "conv-1")
+		assert.NoError(t, err)
+		assert.Same(t, first, cached)
+		inboundRepo.AssertNumberOfCalls(t, "CountByConversationKey", 1)
+
+		inboundRepo.On("Create", ctx, mock.Anything).Return(&model.InboundMessage{ID: "msg-1"}, nil)
+		_, err = svc.CreateInbound(ctx, CreateInboundParams{AccountID: "acc-1", ConversationKey: "conv-1"})
+		assert.NoError(t, err)
+		assert.NotContains(t, cache.entries, "conv-1")
+
+		_, err = svc.GetConversationStats(ctx, "conv-1")
+		assert.NoError(t, err)
+		inboundRepo.AssertNumberOfCalls(t, "CountByConversationKey", 2)
+	})
+}
+
+func TestMessageService_GetConversationMessages(t *testing.T) {
+	t.Run("pages all messages through the merged timeline", func(t *testing.T) {
+		inboundRepo := new(mockInboundRepo)
+		outboundRepo := new(mockOutboundRepo)
+		timelineRepo := new(mockTimelineRepo)
+		svc := NewMessageService(inboundRepo, outboundRepo, timelineRepo, nil, nil)
+
+		ctx := context.Background()
+		timelineRepo.On("FindByConversationKey", ctx, "conv-1", 20, 20).Return([]model.TimelineMessage{
+			{ID: "msg-1", ConversationKey: "conv-1", Direction: "inbound", CreatedAt: time.Now()},
+		}, nil)
+		inboundRepo.On("CountByConversationKey", ctx, "conv-1").Return(11, nil)
+		outboundRepo.On("CountByConversationKey", ctx, "conv-1").Return(10, nil)
+
+		result, err := svc.GetConversationMessages(ctx, ConversationMessagesParams{
+			ConversationKey: "conv-1",
+			Offset:          20,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Messages, 1)
+		assert.Equal(t, 21, result.Total)
+		assert.False(t, result.HasMore)
+		timelineRepo.AssertExpectations(t)
+	})
+}
+
+func TestMessageService_CancelOutbound(t *testing.T) {
+	t.Run("cancels a pending message", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		svc := NewMessageService(new(mockInboundRepo), outboundRepo, nil, nil, nil)
+
+		ctx := context.Background()
+		outboundRepo.On("Cancel", ctx, "out-1", "acc-1").Return(&model.OutboundMessage{
+			ID: "out-1", AccountID: "acc-1", Status: model.OutboundStatusCancelled,
+		}, nil)
+
+		msg, err := svc.CancelOutbound(ctx, "acc-1", "out-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.OutboundStatusCancelled, msg.Status)
+		outboundRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("conflicts when the message already left pending", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		svc := NewMessageService(new(mockInboundRepo), outboundRepo, nil, nil, nil)
+
+		ctx := context.Background()
+		outboundRepo.On("Cancel", ctx, "out-1", "acc-1").Return(nil, nil)
+		outboundRepo.On("FindByID", ctx, "out-1").Return(&model.OutboundMessage{
+			ID: "out-1", AccountID: "acc-1", Status: model.OutboundStatusSent,
+		}, nil)
+
+		_, err := svc.CancelOutbound(ctx, "acc-1", "out-1")
+
+		assert.Equal(t, apperrors.ErrCodeConflict, apperrors.GetCode(err))
+	})
+
+	t.Run("hides other accounts' messages", func(t *testing.T) {
+		outboundRepo := new(mockOutboundRepo)
+		svc := NewMessageService(new(mockInboundRepo), outboundRepo, nil, nil, nil)
+
+		ctx := context.Background()
+		outboundRepo.On("Cancel", ctx, "out-1", "acc-1").Return(nil, nil)
+		outboundRepo.On("FindByID", ctx, "out-1").Return(&model.OutboundMessage{
+			ID: "out-1", AccountID: "acc-2", Status: model.OutboundStatusPending,
+		}, nil)
+
+		_, err := svc.CancelOutbound(ctx, "acc-1", "out-1")
+
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+}
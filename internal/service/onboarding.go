@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// Limits follow what Kakao displays: a text card description holds 400
+// characters, a simple text 1000 and a reply offers at most 10 quick replies.
+const (
+	maxOnboardingIntroLen   = 400
+	maxOnboardingPrivacyLen = 1000
+	maxOnboardingPrompts    = 5
+	maxOnboardingPromptLen  = 100
+)
+
+// OnboardingService manages the welcome sequence an account's Kakao users
+// receive when they pair.
+type OnboardingService struct {
+	accountRepo repository.AccountRepository
+}
+
+func NewOnboardingService(accountRepo repository.AccountRepository) *OnboardingService {
+	return &OnboardingService{accountRepo: accountRepo}
+}
+
+// Get returns the account's onboarding settings, or the defaults when it has
+// none.
+func (s *OnboardingService) Get(ctx context.Context, accountID string) (*model.Onboarding, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	onboarding := model.DefaultOnboarding()
+	if account.Onboarding != nil {
+		if err := json.Unmarshal(*account.Onboarding, &onboarding); err != nil {
+			log.Warn().Err(err).Str("accountId", accountID).Msg("invalid onboarding settings, using defaults")
+			onboarding = model.DefaultOnboarding()
+		}
+	}
+	return &onboarding, nil
+}
+
+// Update validates and stores the account's onboarding settings.
+func (s *OnboardingService) Update(ctx context.Context, accountID string, onboarding model.Onboarding) (*model.Onboarding, error) {
+	onboarding.Intro = strings.TrimSpace(onboarding.Intro)
+	onboarding.PrivacyNotice = strings.TrimSpace(onboarding.PrivacyNotice)
+	prompts := make([]string, 0, len(onboarding.ExamplePrompts))
+	for _, prompt := range onboarding.ExamplePrompts {
+		if prompt = strings.TrimSpace(prompt); prompt != "" {
+			prompts = append(prompts, prompt)
+		}
+	}
+	onboarding.ExamplePrompts = prompts
+
+	if err := validateOnboarding(onboarding); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(onboarding)
+	if err != nil {
+		return nil, fmt.Errorf("marshal onboarding: %w", err)
+	}
+	account, err := s.accountRepo.UpdateOnboarding(ctx, accountID, data)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	log.Info().Str("accountId", accountID).Bool("enabled", onboarding.Enabled).Msg("onboarding settings updated")
+	return &onboarding, nil
+}
+
+func validateOnboarding(onboarding model.Onboarding) error {
+	if utf8.RuneCountInString(onboarding.Intro) > maxOnboardingIntroLen {
+		return apperrors.InvalidInput("intro", fmt.Sprintf("must be at most %d characters", maxOnboardingIntroLen))
+	}
+	if utf8.RuneCountInString(onboarding.PrivacyNotice) > maxOnboardingPrivacyLen {
+		return apperrors.InvalidInput("privacyNotice", fmt.Sprintf("must be at most %d characters", maxOnboardingPrivacyLen))
+	}
+	if len(onboarding.ExamplePrompts) > maxOnboardingPrompts {
+		return apperrors.InvalidInput("examplePrompts", fmt.Sprintf("must have at most %d prompts", maxOnboardingPrompts))
+	}
+	for _, prompt := range onboarding.ExamplePrompts {
+		if utf8.RuneCountInString(prompt) > maxOnboardingPromptLen {
+			return apperrors.InvalidInput("examplePrompts", fmt.Sprintf("prompts must be at most %d characters", maxOnboardingPromptLen))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+func TestOnboardingService(t *testing.T) {
+	ctx := context.Background()
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+	svc := NewOnboardingService(accountRepo)
+
+	t.Run("defaults when not configured", func(t *testing.T) {
+		onboarding, err := svc.Get(ctx, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, model.DefaultOnboarding(), *onboarding)
+	})
+
+	t.Run("update trims and drops empty prompts", func(t *testing.T) {
+		updated, err := svc.Update(ctx, "acc-1", model.Onboarding{
+			Enabled:        true,
+			Intro:          "  Ask me anything  ",
+			ExamplePrompts: []string{" Summarize my inbox ", "", "  "},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Ask me anything", updated.Intro)
+		assert.Equal(t, []string{"Summarize my inbox"}, updated.ExamplePrompts)
+
+		stored, err := svc.Get(ctx, "acc-1")
+		require.NoError(t, err)
+		assert.Equal(t, updated, stored)
+	})
+
+	t.Run("rejects too many prompts", func(t *testing.T) {
+		_, err := svc.Update(ctx, "acc-1", model.Onboarding{ExamplePrompts: []string{"a", "b", "c", "d", "e", "f"}})
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	})
+
+	t.Run("rejects a long intro", func(t *testing.T) {
+		_, err := svc.Update(ctx, "acc-1", model.Onboarding{Intro: strings.Repeat("가", maxOnboardingIntroLen+1)})
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	})
+
+	t.Run("invalid stored settings fall back to defaults", func(t *testing.T) {
+		raw := json.RawMessage(`{"enabled":`)
+		accountRepo.accounts["acc-2"] = &model.Account{ID: "acc-2", Onboarding: &raw}
+
+		onboarding, err := svc.Get(ctx, "acc-2")
+		require.NoError(t, err)
+		assert.True(t, onboarding.Enabled)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := svc.Get(ctx, "missing")
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+}
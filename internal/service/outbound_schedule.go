@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// scheduledSendBatch caps how many due messages one run sends.
+const scheduledSendBatch = 100
+
+// CallbackSender posts a response to a Kakao callback URL and returns
+// Kakao's answer when there was one.
+type CallbackSender interface {
+	SendCallback(ctx context.Context, callbackURL string, payload any) (*model.CallbackResponse, error)
+}
+
+// OutboundScheduler sends replies that were scheduled for later. Kakao
+// callback URLs expire within a minute, so a reply can only be deferred
+// while the callback it was scheduled with is still valid.
+type OutboundScheduler struct {
+	outboundRepo repository.OutboundMessageRepository
+	sender       CallbackSender
+	events       *SessionEvents
+}
+
+func NewOutboundScheduler(outboundRepo repository.OutboundMessageRepository, sender CallbackSender, events *SessionEvents) *OutboundScheduler {
+	return &OutboundScheduler{outboundRepo: outboundRepo, sender: sender, events: events}
+}
+
+// SendDue sends the scheduled replies that are due and returns how many were
+// sent. A reply whose send fails is marked failed; it is not retried since
+// its callback URL is single-use and short-lived.
+func (s *OutboundScheduler) SendDue(ctx context.Context) (int, error) {
+	msgs, err := s.outboundRepo.ClaimDueScheduled(ctx, time.Now(), scheduledSendBatch)
+	if err != nil {
+		return 0, fmt.Errorf("claim scheduled outbound messages: %w", err)
+	}
+
+	sent := 0
+	for _, msg := range msgs {
+		resp, err := s.send(ctx, &msg)
+		if resp != nil {
+			if err := s.outboundRepo.RecordCallbackResponse(ctx, msg.ID, *resp); err != nil {
+				log.Warn().Err(err).Str("outboundId", msg.ID).Msg("failed to record callback response")
+			}
+		}
+		s.events.ReplyDelivered(ctx, &msg, resp, err)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("outboundId", msg.ID).
+				Str("conversationKey", msg.ConversationKey).
+				Msg("failed to send scheduled reply")
+			if err := s.outboundRepo.MarkFailed(ctx, msg.ID, err.Error()); err != nil {
+				log.Error().Err(err).Str("outboundId", msg.ID).Msg("failed to mark scheduled reply failed")
+			}
+			continue
+		}
+		if err := s.outboundRepo.MarkSent(ctx, msg.ID); err != nil {
+			log.Error().Err(err).Str("outboundId", msg.ID).Msg("failed to mark scheduled reply sent")
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *OutboundScheduler) send(ctx context.Context, msg *model.OutboundMessage) (*model.CallbackResponse, error) {
+	if msg.CallbackURL == nil {
+		return nil, fmt.Errorf("no callback URL")
+	}
+	var payload any
+	if err := json.Unmarshal(msg.ResponsePayload, &payload); err != nil {
+		return nil, fmt.Errorf("decode response payload: %w", err)
+	}
+	return s.sender.SendCallback(ctx, *msg.CallbackURL, payload)
+}
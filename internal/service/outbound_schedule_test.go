@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type fakeCallbackSender struct {
+	sent map[string]any
+	fail map[string]bool
+}
+
+func (f *fakeCallbackSender) SendCallback(ctx context.Context, callbackURL string, payload any) (*model.CallbackResponse, error) {
+	if f.fail[callbackURL] {
+		return &model.CallbackResponse{Status: 400, Body: `{"status":"FAIL"}`}, errors.New("callback failed with status 400")
+	}
+	f.sent[callbackURL] = payload
+	return &model.CallbackResponse{Status: 200, TaskID: "task-" + callbackURL[len(callbackURL)-2:]}, nil
+}
+
+func TestOutboundScheduler_SendDue(t *testing.T) {
+	ok := "https://callback.kakao.com/ok"
+	bad := "https://callback.kakao.com/bad"
+	outboundRepo := new(mockOutboundRepo)
+	outboundRepo.On("ClaimDueScheduled", mock.Anything, mock.Anything, scheduledSendBatch).Return([]model.OutboundMessage{
+		{ID: "out-1", CallbackURL: &ok, ResponsePayload: json.RawMessage(`{"version":"2.0"}`)},
+		{ID: "out-2", CallbackURL: &bad, ResponsePayload: json.RawMessage(`{"version":"2.0"}`)},
+		{ID: "out-3", ResponsePayload: json.RawMessage(`{"version":"2.0"}`)},
+	}, nil)
+	outboundRepo.On("MarkSent", mock.Anything, "out-1").Return(nil)
+	outboundRepo.On("MarkFailed", mock.Anything, "out-2", "callback failed with status 400").Return(nil)
+	outboundRepo.On("MarkFailed", mock.Anything, "out-3", "no callback URL").Return(nil)
+	outboundRepo.On("RecordCallbackResponse", mock.Anything, "out-1", model.CallbackResponse{Status: 200, TaskID: "task-ok"}).Return(nil)
+	outboundRepo.On("RecordCallbackResponse", mock.Anything, "out-2", model.CallbackResponse{Status: 400, Body: `{"status":"FAIL"}`}).Return(nil)
+
+	sender := &fakeCallbackSender{sent: map[string]any{}, fail: map[string]bool{bad: true}}
+	sent, err := NewOutboundScheduler(outboundRepo, sender, nil).SendDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, map[string]any{"version": "2.0"}, sender.sent[ok])
+	outboundRepo.AssertExpectations(t)
+}
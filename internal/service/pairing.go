@@ -11,6 +11,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 )
@@ -146,6 +147,56 @@ func (s *PairingService) ListActiveCodes(ctx context.Context, accountID string)
 	return s.codeRepo.FindActiveByAccountID(ctx, accountID)
 }
 
+// RevokeCode expires an active code of the account before its expiry time.
+func (s *PairingService) RevokeCode(ctx context.Context, accountID, code string) error {
+	normalizedCode := strings.ToUpper(strings.TrimSpace(code))
+
+	revoked, err := s.codeRepo.Revoke(ctx, accountID, normalizedCode)
+	if err != nil {
+		return fmt.Errorf("revoke pairing code: %w", err)
+	}
+	if !revoked {
+		return apperrors.NotFound("Pairing code")
+	}
+
+	log.Info().
+		Str("code", normalizedCode).
+		Str("accountId", accountID).
+		Msg("pairing code revoked")
+
+	return nil
+}
+
+// ListAllActiveCodes returns one page of unused, unexpired codes across all
+// accounts along with their total count.
+func (s *PairingService) ListAllActiveCodes(ctx context.Context, limit, offset int) ([]model.PairingCode, int, error) {
+	codes, err := s.codeRepo.FindActive(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find active codes: %w", err)
+	}
+	total, err := s.codeRepo.CountActive(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count active codes: %w", err)
+	}
+	return codes, total, nil
+}
+
+// RevokeAnyCode expires an active code regardless of its account.
+func (s *PairingService) RevokeAnyCode(ctx context.Context, code string) error {
+	normalizedCode := strings.ToUpper(strings.TrimSpace(code))
+
+	revoked, err := s.codeRepo.RevokeByCode(ctx, normalizedCode)
+	if err != nil {
+		return fmt.Errorf("revoke pairing code: %w", err)
+	}
+	if !revoked {
+		return apperrors.NotFound("Pairing code")
+	}
+
+	log.Info().Str("code", normalizedCode).Msg("pairing code revoked by admin")
+	return nil
+}
+
 func generateRandomCode() string {
 	chars := []byte(pairingCodeChars)
 	part1 := make([]byte, 4)
@@ -1,12 +1,84 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
 )
 
+type mockPairingCodeRepo struct {
+	mock.Mock
+}
+
+func (m *mockPairingCodeRepo) FindByCode(ctx context.Context, code string) (*model.PairingCode, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PairingCode), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) FindActiveByAccountID(ctx context.Context, accountID string) ([]model.PairingCode, error) {
+	args := m.Called(ctx, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PairingCode), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) FindActive(ctx context.Context, limit, offset int) ([]model.PairingCode, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PairingCode), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) CountActive(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) CountActiveByAccountID(ctx context.Context, accountID string) (int, error) {
+	args := m.Called(ctx, accountID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) Create(ctx context.Context, params model.CreatePairingCodeParams) (*model.PairingCode, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PairingCode), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) MarkUsed(ctx context.Context, code string, usedBy string) error {
+	args := m.Called(ctx, code, usedBy)
+	return args.Error(0)
+}
+
+func (m *mockPairingCodeRepo) Revoke(ctx context.Context, accountID, code string) (bool, error) {
+	args := m.Called(ctx, accountID, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) RevokeByCode(ctx context.Context, code string) (bool, error) {
+	args := m.Called(ctx, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockPairingCodeRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestGenerateRandomCode(t *testing.T) {
 	t.Run("generates code in correct format XXXX-XXXX", func(t *testing.T) {
 		code := generateRandomCode()
@@ -68,3 +140,39 @@ func TestPairingCodeChars(t *testing.T) {
 		assert.Len(t, pairingCodeChars, 32)
 	})
 }
+
+func TestPairingService_RevokeCode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revokes a normalized code", func(t *testing.T) {
+		codeRepo := new(mockPairingCodeRepo)
+		codeRepo.On("Revoke", ctx, "acc-1", "ABCD-EFGH").Return(true, nil)
+		svc := NewPairingService(codeRepo, new(mockConversationRepo))
+
+		err := svc.RevokeCode(ctx, "acc-1", " abcd-efgh ")
+
+		assert.NoError(t, err)
+		codeRepo.AssertExpectations(t)
+	})
+
+	t.Run("returns not found for unknown or inactive code", func(t *testing.T) {
+		codeRepo := new(mockPairingCodeRepo)
+		codeRepo.On("Revoke", ctx, "acc-1", "ABCD-EFGH").Return(false, nil)
+		svc := NewPairingService(codeRepo, new(mockConversationRepo))
+
+		err := svc.RevokeCode(ctx, "acc-1", "ABCD-EFGH")
+
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+
+	t.Run("wraps repository errors", func(t *testing.T) {
+		codeRepo := new(mockPairingCodeRepo)
+		codeRepo.On("Revoke", ctx, "acc-1", "ABCD-EFGH").Return(false, errors.New("db down"))
+		svc := NewPairingService(codeRepo, new(mockConversationRepo))
+
+		err := svc.RevokeCode(ctx, "acc-1", "ABCD-EFGH")
+
+		assert.Error(t, err)
+		assert.NotEqual(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+}
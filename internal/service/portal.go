@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/i18n"
+	"github.com/openclaw/relay-server-go/internal/mail"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/util"
@@ -15,6 +19,8 @@ type PortalService struct {
 	userRepo      repository.PortalUserRepository
 	sessionRepo   repository.PortalSessionRepository
 	accountRepo   repository.AccountRepository
+	events        *SessionEvents
+	mailer        mail.Sender
 	sessionSecret string
 }
 
@@ -22,12 +28,19 @@ func NewPortalService(
 	userRepo repository.PortalUserRepository,
 	sessionRepo repository.PortalSessionRepository,
 	accountRepo repository.AccountRepository,
+	events *SessionEvents,
+	mailer mail.Sender,
 	sessionSecret string,
 ) *PortalService {
+	if mailer == nil {
+		mailer = mail.NewLogSender()
+	}
 	return &PortalService{
 		userRepo:      userRepo,
 		sessionRepo:   sessionRepo,
 		accountRepo:   accountRepo,
+		events:        events,
+		mailer:        mailer,
 		sessionSecret: sessionSecret,
 	}
 }
@@ -55,6 +68,46 @@ func (s *PortalService) GetAccountByID(ctx context.Context, accountID string) (*
 	return s.accountRepo.FindByID(ctx, accountID)
 }
 
+// UpdateTimezone sets the IANA zone that defines the account's day
+// boundaries, e.g. "today" in stats.
+func (s *PortalService) UpdateTimezone(ctx context.Context, accountID, timezone string) (*model.Account, error) {
+	timezone = strings.TrimSpace(timezone)
+	// LoadLocation accepts "" and "Local" as aliases for UTC and the server
+	// zone; neither is a meaningful account setting.
+	if timezone == "" || timezone == "Local" {
+		return nil, apperrors.InvalidInput("timezone", "must be an IANA time zone name")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, apperrors.InvalidInput("timezone", "unknown time zone")
+	}
+
+	account, err := s.accountRepo.UpdateTimezone(ctx, accountID, timezone)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	log.Info().Str("accountId", accountID).Str("timezone", timezone).Msg("account timezone updated")
+	return account, nil
+}
+
+// UpdateShareKakaoProfile sets whether the account's plugins receive the
+// Kakao user profile of its connections.
+func (s *PortalService) UpdateShareKakaoProfile(ctx context.Context, accountID string, share bool) (*model.Account, error) {
+	account, err := s.accountRepo.UpdateShareKakaoProfile(ctx, accountID, share)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	log.Info().Str("accountId", accountID).Bool("shareKakaoProfile", share).Msg("kakao profile sharing updated")
+	return account, nil
+}
+
 func (s *PortalService) RegenerateToken(ctx context.Context, accountID string) (*model.Account, string, error) {
 	newToken, err := util.GenerateToken()
 	if err != nil {
@@ -68,23 +121,104 @@ func (s *PortalService) RegenerateToken(ctx context.Context, accountID string) (
 	}
 
 	log.Info().Str("accountId", accountID).Msg("relay token regenerated")
+	s.events.TokenRegenerated(ctx, accountID, "portal")
 
 	return account, newToken, nil
 }
 
-func (s *PortalService) DeleteAccount(ctx context.Context, userID string) error {
+// PreviewDeleteAccount reports what deleting the user's account affects and
+// issues the token DeleteAccount requires.
+func (s *PortalService) PreviewDeleteAccount(ctx context.Context, userID string) (*AccountDeletionPreview, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperrors.NotFound("User")
+	}
+	return previewAccountDeletion(ctx, s.accountRepo, s.sessionSecret, user.AccountID)
+}
+
+// ScheduleAccountDeletion suspends the user's account and schedules it for
+// purging after the grace period. The user is notified by email and can cancel
+// until then.
+func (s *PortalService) ScheduleAccountDeletion(ctx context.Context, userID, previewToken string) (*model.Account, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperrors.NotFound("User")
+	}
+
+	if err := verifyAccountDeletionToken(s.sessionSecret, user.AccountID, previewToken); err != nil {
+		return nil, err
+	}
+	if err := checkAccountLegalHold(ctx, s.accountRepo, user.AccountID); err != nil {
+		return nil, err
+	}
+
+	purgeAt := time.Now().Add(accountDeletionGracePeriod)
+	account, err := s.accountRepo.ScheduleDeletion(ctx, user.AccountID, purgeAt)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	log.Info().
+		Str("userId", userID).
+		Str("accountId", user.AccountID).
+		Time("purgeAt", purgeAt).
+		Msg("portal account deletion scheduled")
+
+	locale := i18n.FromContext(ctx)
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: i18n.T(locale, i18n.MailDeletionScheduledSubject),
+		Body:    i18n.T(locale, i18n.MailDeletionScheduledBody, purgeAt.UTC().Format("2006-01-02 15:04 UTC")),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Warn().Err(err).Str("userId", userID).Msg("failed to send account deletion notice")
+	}
+
+	return account, nil
+}
+
+// CancelAccountDeletion withdraws a pending deletion request and restores the
+// account. Returns NotFound when no deletion is scheduled.
+func (s *PortalService) CancelAccountDeletion(ctx context.Context, userID string) (*model.Account, error) {
 	user, err := s.userRepo.FindByID(ctx, userID)
-	if err != nil || user == nil {
-		return err
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperrors.NotFound("User")
 	}
 
-	if err := s.accountRepo.Delete(ctx, user.AccountID); err != nil {
-		return err
+	account, err := s.accountRepo.CancelDeletion(ctx, user.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Scheduled deletion")
 	}
 
-	log.Info().Str("userId", userID).Str("accountId", user.AccountID).Msg("portal user account deleted")
+	log.Info().Str("userId", userID).Str("accountId", user.AccountID).Msg("portal account deletion cancelled")
+	return account, nil
+}
 
-	return nil
+// PurgeScheduledAccounts deletes accounts whose grace period has ended.
+func (s *PortalService) PurgeScheduledAccounts(ctx context.Context) (int, error) {
+	ids, err := s.accountRepo.DeleteScheduled(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		log.Info().Str("accountId", id).Msg("scheduled account deletion completed")
+	}
+	return len(ids), nil
 }
 
 func (s *PortalService) CreateSession(ctx context.Context, userID string) (string, error) {
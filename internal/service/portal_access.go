@@ -15,15 +15,20 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	redisclient "github.com/openclaw/relay-server-go/internal/redis"
-	"github.com/openclaw/relay-server-go/internal/util"
 )
 
 const (
 	portalCodeChars      = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 	portalCodeTTLMinutes = 30
 	sessionTTLMinutes    = 30
+
+	// CodeSessionMaxLifetime caps how far renewals can extend a code session
+	CodeSessionMaxLifetime = 4 * time.Hour
+	// CodeSessionExpiryWarning is how close to expiry responses start warning
+	CodeSessionExpiryWarning = 5 * time.Minute
 )
 
 // PortalCodeSession represents a temporary portal session
@@ -31,6 +36,22 @@ type PortalCodeSession struct {
 	Token           string
 	ConversationKey string
 	ExpiresAt       time.Time
+	CreatedAt       time.Time
+}
+
+// MaxExpiresAt is the latest time renewals can extend the session to
+func (s *PortalCodeSession) MaxExpiresAt() time.Time {
+	createdAt := s.CreatedAt
+	if createdAt.IsZero() {
+		// Sessions stored before renewal existed
+		createdAt = s.ExpiresAt.Add(-sessionTTLMinutes * time.Minute)
+	}
+	return createdAt.Add(CodeSessionMaxLifetime)
+}
+
+// ExpiringSoon reports whether the session is within CodeSessionExpiryWarning of expiring
+func (s *PortalCodeSession) ExpiringSoon() bool {
+	return time.Until(s.ExpiresAt) <= CodeSessionExpiryWarning
 }
 
 // PortalAccessService handles portal access code operations
@@ -38,7 +59,7 @@ type PortalAccessService struct {
 	codeRepo    repository.PortalAccessCodeRepository
 	convRepo    repository.ConversationRepository
 	redisClient *redisclient.Client
-	rateLimiter *RateLimiter
+	rateLimiter ratelimit.Limiter
 }
 
 // NewPortalAccessService creates a new portal access service
@@ -46,12 +67,13 @@ func NewPortalAccessService(
 	codeRepo repository.PortalAccessCodeRepository,
 	convRepo repository.ConversationRepository,
 	redisClient *redisclient.Client,
+	rateLimiter ratelimit.Limiter,
 ) *PortalAccessService {
 	return &PortalAccessService{
 		codeRepo:    codeRepo,
 		convRepo:    convRepo,
 		redisClient: redisClient,
-		rateLimiter: NewRateLimiter(redisClient.Client),
+		rateLimiter: rateLimiter,
 	}
 }
 
@@ -64,7 +86,7 @@ func (s *PortalAccessService) GenerateCode(
 	existing, err := s.codeRepo.FindActiveByConversationKey(ctx, conversationKey)
 	if err == nil && existing != nil {
 		log.Info().
-			Str("code", util.MaskCode(existing.Code)).
+			Str("code", existing.Code).
 			Str("conversationKey", conversationKey).
 			Time("expiresAt", existing.ExpiresAt).
 			Msg("reusing existing portal access code")
@@ -92,7 +114,7 @@ func (s *PortalAccessService) GenerateCode(
 	}
 
 	log.Info().
-		Str("code", util.MaskCode(code)).
+		Str("code", code).
 		Str("conversationKey", conversationKey).
 		Time("expiresAt", pac.ExpiresAt).
 		Msg("portal access code created")
@@ -110,7 +132,7 @@ func (s *PortalAccessService) VerifyCode(
 	pac, err := s.codeRepo.FindActiveByCode(ctx, normalizedCode)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Warn().Str("code", util.MaskCode(normalizedCode)).Msg("invalid or expired portal code")
+			log.Warn().Str("code", normalizedCode).Msg("invalid or expired portal code")
 			return "", fmt.Errorf("invalid or expired code")
 		}
 		return "", fmt.Errorf("verify portal code: %w", err)
@@ -123,17 +145,38 @@ func (s *PortalAccessService) VerifyCode(
 	}
 
 	log.Info().
-		Str("code", util.MaskCode(normalizedCode)).
+		Str("code", normalizedCode).
 		Str("conversationKey", pac.ConversationKey).
 		Msg("portal code verified")
 
 	return pac.ConversationKey, nil
 }
 
-// CreateCodeSession creates a temporary session for portal access
-func (s *PortalAccessService) CreateCodeSession(
+// CreateAndStoreCodeSession creates a temporary session for portal access and
+// stores it in Redis, so any instance can validate it
+func (s *PortalAccessService) CreateAndStoreCodeSession(
+	ctx context.Context,
 	conversationKey string,
 ) (*PortalCodeSession, error) {
+	session, err := newCodeSession(conversationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storeSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("conversationKey", conversationKey).
+		Time("expiresAt", session.ExpiresAt).
+		Msg("portal code session created")
+
+	return session, nil
+}
+
+// newCodeSession builds a session with a fresh random token
+func newCodeSession(conversationKey string) (*PortalCodeSession, error) {
 	// Generate secure random token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
@@ -141,17 +184,14 @@ func (s *PortalAccessService) CreateCodeSession(
 	}
 	token := base64.URLEncoding.EncodeToString(tokenBytes)
 
+	now := time.Now()
 	session := &PortalCodeSession{
 		Token:           token,
 		ConversationKey: conversationKey,
-		ExpiresAt:       time.Now().Add(sessionTTLMinutes * time.Minute),
+		ExpiresAt:       now.Add(sessionTTLMinutes * time.Minute),
+		CreatedAt:       now,
 	}
 
-	log.Info().
-		Str("conversationKey", conversationKey).
-		Time("expiresAt", session.ExpiresAt).
-		Msg("portal code session created")
-
 	return session, nil
 }
 
@@ -160,26 +200,84 @@ func (s *PortalAccessService) ValidateCodeSession(
 	ctx context.Context,
 	token string,
 ) (string, error) {
-	key := fmt.Sprintf("portal_session:%s", token)
-	data, err := s.redisClient.Get(ctx, key).Result()
+	session, err := s.GetCodeSession(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return session.ConversationKey, nil
+}
+
+// GetCodeSession loads a portal session by token
+func (s *PortalAccessService) GetCodeSession(
+	ctx context.Context,
+	token string,
+) (*PortalCodeSession, error) {
+	data, err := s.redisClient.Get(ctx, portalSessionKey(token)).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return "", fmt.Errorf("session expired or invalid")
+			return nil, fmt.Errorf("session expired or invalid")
 		}
-		return "", fmt.Errorf("validate session: %w", err)
+		return nil, fmt.Errorf("validate session: %w", err)
 	}
 
 	var session PortalCodeSession
 	if err := json.Unmarshal([]byte(data), &session); err != nil {
-		return "", fmt.Errorf("unmarshal session: %w", err)
+		return nil, fmt.Errorf("unmarshal session: %w", err)
 	}
 
-	return session.ConversationKey, nil
+	return &session, nil
 }
 
-// StoreSession stores a session in Redis with automatic expiry
-func (s *PortalAccessService) StoreSession(ctx context.Context, session *PortalCodeSession) error {
-	key := fmt.Sprintf("portal_session:%s", session.Token)
+// RenewCodeSession slides the session expiry forward, up to CodeSessionMaxLifetime
+// after it was created
+func (s *PortalAccessService) RenewCodeSession(
+	ctx context.Context,
+	token string,
+) (*PortalCodeSession, error) {
+	session, err := s.GetCodeSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(sessionTTLMinutes * time.Minute)
+	if maxExpiresAt := session.MaxExpiresAt(); expiresAt.After(maxExpiresAt) {
+		expiresAt = maxExpiresAt
+	}
+	if !expiresAt.After(session.ExpiresAt) {
+		return session, nil
+	}
+
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = session.MaxExpiresAt().Add(-CodeSessionMaxLifetime)
+	}
+	session.ExpiresAt = expiresAt
+	if err := s.storeSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	log.Debug().
+		Str("conversationKey", session.ConversationKey).
+		Time("expiresAt", session.ExpiresAt).
+		Msg("portal code session renewed")
+
+	return session, nil
+}
+
+// DeleteCodeSession removes a portal session so its token can no longer be used
+func (s *PortalAccessService) DeleteCodeSession(ctx context.Context, token string) error {
+	if err := s.redisClient.Del(ctx, portalSessionKey(token)).Err(); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+func portalSessionKey(token string) string {
+	return fmt.Sprintf("portal_session:%s", token)
+}
+
+// storeSession stores a session in Redis with automatic expiry
+func (s *PortalAccessService) storeSession(ctx context.Context, session *PortalCodeSession) error {
+	key := portalSessionKey(session.Token)
 	data, err := json.Marshal(session)
 	if err != nil {
 		return fmt.Errorf("marshal session: %w", err)
@@ -195,7 +293,7 @@ func (s *PortalAccessService) StoreSession(ctx context.Context, session *PortalC
 	}
 
 	log.Debug().
-		Str("token", session.Token[:16]+"...").
+		Str("token", session.Token).
 		Str("conversationKey", session.ConversationKey).
 		Dur("ttl", ttl).
 		Msg("session stored in redis")
@@ -209,8 +307,7 @@ func (s *PortalAccessService) CheckCodeGenerationLimit(
 	ctx context.Context,
 	conversationKey string,
 ) (allowed bool, resetAt time.Time) {
-	key := fmt.Sprintf("code_gen:%s", conversationKey)
-	return s.rateLimiter.CheckLimit(ctx, key, 3, 5*time.Minute)
+	return checkLimit(s.rateLimiter.Take(ctx, "code_gen", conversationKey, ratelimit.Per(3, 5*time.Minute)))
 }
 
 // CheckLoginLimit checks if login attempts are allowed for an IP
@@ -219,8 +316,16 @@ func (s *PortalAccessService) CheckLoginLimit(
 	ctx context.Context,
 	ip string,
 ) (allowed bool, resetAt time.Time) {
-	key := fmt.Sprintf("code_login:%s", ip)
-	return s.rateLimiter.CheckLimit(ctx, key, 5, 1*time.Minute)
+	return checkLimit(s.rateLimiter.Take(ctx, "code_login", ip, ratelimit.Per(5, 1*time.Minute)))
+}
+
+// checkLimit reports a result as (allowed, resetAt), where resetAt is when the
+// next attempt will be allowed if this one was denied.
+func checkLimit(result ratelimit.Result) (bool, time.Time) {
+	if !result.Allowed {
+		return false, time.Now().Add(result.RetryAfter)
+	}
+	return true, result.ResetAt
 }
 
 // generatePortalCode generates an 8-character code in XXXX-XXXX format
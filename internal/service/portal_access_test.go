@@ -6,7 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -97,6 +101,11 @@ func (m *mockConversationRepo) UpdateState(ctx context.Context, key string, stat
 	return args.Error(0)
 }
 
+func (m *mockConversationRepo) TransitionState(ctx context.Context, key string, from, to model.PairingState) (bool, error) {
+	args := m.Called(ctx, key, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *mockConversationRepo) UpdateCallback(ctx context.Context, key string, callbackURL string, expiresAt time.Time) error {
 	args := m.Called(ctx, key, callbackURL, expiresAt)
 	return args.Error(0)
@@ -112,6 +121,86 @@ func (m *mockConversationRepo) CountByState(ctx context.Context, state model.Pai
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockConversationRepo) SearchPairedByAccountID(ctx context.Context, accountID, query string, page model.KeysetPage) ([]model.ConversationMapping, error) {
+	args := m.Called(ctx, accountID, query, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ConversationMapping), args.Error(1)
+}
+
+func (m *mockConversationRepo) UpdateDetails(ctx context.Context, key string, nickname, notes *string) error {
+	args := m.Called(ctx, key, nickname, notes)
+	return args.Error(0)
+}
+
+func (m *mockConversationRepo) UpdateKakaoProfile(ctx context.Context, key string, profile model.KakaoProfile) error {
+	args := m.Called(ctx, key, profile)
+	return args.Error(0)
+}
+
+func (m *mockConversationRepo) UpdateContentConsent(ctx context.Context, key string, consent model.ContentConsent) error {
+	args := m.Called(ctx, key, consent)
+	return args.Error(0)
+}
+
+func (m *mockConversationRepo) SetLegalHold(ctx context.Context, id, reason string) (*model.ConversationMapping, error) {
+	args := m.Called(ctx, id, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ConversationMapping), args.Error(1)
+}
+
+func (m *mockConversationRepo) ReleaseLegalHold(ctx context.Context, id string) (*model.ConversationMapping, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ConversationMapping), args.Error(1)
+}
+
+func (m *mockConversationRepo) PauseDelivery(ctx context.Context, key string, autoReply *string) (*model.ConversationMapping, error) {
+	args := m.Called(ctx, key, autoReply)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ConversationMapping), args.Error(1)
+}
+
+func (m *mockConversationRepo) ResumeDelivery(ctx context.Context, key string) (*model.ConversationMapping, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ConversationMapping), args.Error(1)
+}
+
+func (m *mockConversationRepo) IsUnderLegalHold(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockConversationRepo) ListByAccount(ctx context.Context, params model.ListConversationsParams) ([]model.ConversationMapping, int, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]model.ConversationMapping), args.Int(1), args.Error(2)
+}
+
+func (m *mockConversationRepo) FindHealthByKeys(ctx context.Context, keys []string, failuresSince time.Time) ([]model.ConnectionHealth, error) {
+	args := m.Called(ctx, keys, failuresSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ConnectionHealth), args.Error(1)
+}
+
+func (m *mockConversationRepo) WithTx(tx *sqlx.Tx) repository.ConversationRepository {
+	return m
+}
+
 func TestGenerateCode_ReusePolicy(t *testing.T) {
 	mockCodeRepo := new(mockPortalAccessCodeRepo)
 	mockConvRepo := new(mockConversationRepo)
@@ -230,10 +319,8 @@ func TestVerifyCode_InvalidCode(t *testing.T) {
 	mockCodeRepo.AssertNotCalled(t, "MarkUsed")
 }
 
-func TestCreateCodeSession(t *testing.T) {
-	service := &PortalAccessService{}
-
-	session, err := service.CreateCodeSession("test-conv")
+func TestNewCodeSession(t *testing.T) {
+	session, err := newCodeSession("test-conv")
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, session.Token)
@@ -242,6 +329,83 @@ func TestCreateCodeSession(t *testing.T) {
 	assert.True(t, session.ExpiresAt.Before(time.Now().Add(31*time.Minute)))
 }
 
+func TestPortalCodeSession_MaxExpiresAt(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+
+	session := &PortalCodeSession{CreatedAt: createdAt, ExpiresAt: createdAt.Add(30 * time.Minute)}
+	assert.Equal(t, createdAt.Add(CodeSessionMaxLifetime), session.MaxExpiresAt())
+
+	// Sessions stored without CreatedAt are assumed to be fresh from login
+	legacy := &PortalCodeSession{ExpiresAt: createdAt.Add(30 * time.Minute)}
+	assert.Equal(t, createdAt.Add(CodeSessionMaxLifetime), legacy.MaxExpiresAt())
+}
+
+func TestPortalCodeSession_ExpiringSoon(t *testing.T) {
+	assert.False(t, (&PortalCodeSession{ExpiresAt: time.Now().Add(20 * time.Minute)}).ExpiringSoon())
+	assert.True(t, (&PortalCodeSession{ExpiresAt: time.Now().Add(2 * time.Minute)}).ExpiringSoon())
+}
+
+func newTestPortalAccessService(t *testing.T) *PortalAccessService {
+	t.Helper()
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	t.Cleanup(func() { client.Close() })
+	return &PortalAccessService{redisClient: client}
+}
+
+func TestRenewCodeSession(t *testing.T) {
+	svc := newTestPortalAccessService(t)
+	ctx := context.Background()
+
+	t.Run("slides expiry forward", func(t *testing.T) {
+		session, err := newCodeSession("test-conv")
+		require.NoError(t, err)
+		session.ExpiresAt = time.Now().Add(2 * time.Minute)
+		require.NoError(t, svc.storeSession(ctx, session))
+
+		renewed, err := svc.RenewCodeSession(ctx, session.Token)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), renewed.ExpiresAt, 5*time.Second)
+
+		stored, err := svc.GetCodeSession(ctx, session.Token)
+		require.NoError(t, err)
+		assert.WithinDuration(t, renewed.ExpiresAt, stored.ExpiresAt, time.Second)
+		assert.Equal(t, "test-conv", stored.ConversationKey)
+	})
+
+	t.Run("stops at the maximum lifetime", func(t *testing.T) {
+		session, err := newCodeSession("test-conv")
+		require.NoError(t, err)
+		session.CreatedAt = time.Now().Add(-CodeSessionMaxLifetime + 10*time.Minute)
+		session.ExpiresAt = time.Now().Add(time.Minute)
+		require.NoError(t, svc.storeSession(ctx, session))
+
+		renewed, err := svc.RenewCodeSession(ctx, session.Token)
+		require.NoError(t, err)
+		assert.WithinDuration(t, session.MaxExpiresAt(), renewed.ExpiresAt, time.Second)
+	})
+
+	t.Run("rejects an unknown token", func(t *testing.T) {
+		_, err := svc.RenewCodeSession(ctx, "missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteCodeSession(t *testing.T) {
+	svc := newTestPortalAccessService(t)
+	ctx := context.Background()
+
+	session, err := svc.CreateAndStoreCodeSession(ctx, "test-conv")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteCodeSession(ctx, session.Token))
+
+	_, err = svc.ValidateCodeSession(ctx, session.Token)
+	assert.Error(t, err)
+}
+
 func TestGeneratePortalCode_Format(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		code := generatePortalCode()
@@ -268,3 +432,83 @@ func TestGeneratePortalCode_Uniqueness(t *testing.T) {
 		codes[code] = true
 	}
 }
+
+func TestCheckCodeGenerationLimit(t *testing.T) {
+	ctx := context.Background()
+
+	service := &PortalAccessService{
+		rateLimiter: ratelimit.NewMemoryLimiter(),
+	}
+
+	conversationKey := "test-conv-123"
+
+	// Should allow 3 times per 5 minutes
+	for i := 0; i < 3; i++ {
+		allowed, _ := service.CheckCodeGenerationLimit(ctx, conversationKey)
+		assert.True(t, allowed, "Request %d should be allowed", i+1)
+	}
+
+	// 4th attempt should be denied
+	allowed, resetAt := service.CheckCodeGenerationLimit(ctx, conversationKey)
+	assert.False(t, allowed, "Should be rate limited after 3 attempts")
+	assert.True(t, resetAt.After(time.Now()), "Reset time should be in future")
+}
+
+func TestCheckLoginLimit(t *testing.T) {
+	ctx := context.Background()
+
+	service := &PortalAccessService{
+		rateLimiter: ratelimit.NewMemoryLimiter(),
+	}
+
+	clientIP := "192.168.1.100"
+
+	// Should allow 5 times per 1 minute
+	for i := 0; i < 5; i++ {
+		allowed, _ := service.CheckLoginLimit(ctx, clientIP)
+		assert.True(t, allowed, "Request %d should be allowed", i+1)
+	}
+
+	// 6th attempt should be denied
+	allowed, resetAt := service.CheckLoginLimit(ctx, clientIP)
+	assert.False(t, allowed, "Should be rate limited after 5 attempts")
+	assert.True(t, resetAt.After(time.Now()), "Reset time should be in future")
+}
+
+func TestCreateAndStoreCodeSession(t *testing.T) {
+	svc := newTestPortalAccessService(t)
+	ctx := context.Background()
+
+	session, err := svc.CreateAndStoreCodeSession(ctx, "test-conv")
+	require.NoError(t, err)
+
+	conversationKey, err := svc.ValidateCodeSession(ctx, session.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "test-conv", conversationKey)
+
+	ttl, err := svc.redisClient.TTL(ctx, portalSessionKey(session.Token)).Result()
+	require.NoError(t, err)
+	assert.InDelta(t, (30 * time.Minute).Seconds(), ttl.Seconds(), 5)
+}
+
+// Instances behind a load balancer share nothing but Redis, so a session
+// created on one must be usable and revocable on any other.
+func TestCodeSession_AcrossInstances(t *testing.T) {
+	login := newTestPortalAccessService(t)
+	other := newTestPortalAccessService(t)
+	ctx := context.Background()
+
+	session, err := login.CreateAndStoreCodeSession(ctx, "test-conv")
+	require.NoError(t, err)
+
+	conversationKey, err := other.ValidateCodeSession(ctx, session.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "test-conv", conversationKey)
+
+	_, err = other.RenewCodeSession(ctx, session.Token)
+	require.NoError(t, err)
+
+	require.NoError(t, other.DeleteCodeSession(ctx, session.Token))
+	_, err = login.ValidateCodeSession(ctx, session.Token)
+	assert.Error(t, err)
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/mail"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 )
@@ -116,6 +119,15 @@ func (m *mockPortalSessionRepo) DeleteExpired(ctx context.Context) (int64, error
 	return count, nil
 }
 
+type recordingMailer struct {
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
 type mockAccountRepo struct {
 	accounts map[string]*model.Account
 }
@@ -132,6 +144,11 @@ func (m *mockAccountRepo) FindByID(ctx context.Context, id string) (*model.Accou
 }
 
 func (m *mockAccountRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.Account, error) {
+	for _, acc := range m.accounts {
+		if acc.RelayTokenHash != nil && *acc.RelayTokenHash == tokenHash && acc.DisabledAt == nil {
+			return acc, nil
+		}
+	}
 	return nil, nil
 }
 
@@ -140,22 +157,208 @@ func (m *mockAccountRepo) Create(ctx context.Context, params model.CreateAccount
 }
 
 func (m *mockAccountRepo) Update(ctx context.Context, id string, params model.UpdateAccountParams) (*model.Account, error) {
-	return nil, nil
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	if params.Mode != nil {
+		acc.Mode = *params.Mode
+	}
+	if params.RateLimitPerMin != nil {
+		acc.RateLimitPerMin = *params.RateLimitPerMin
+	}
+	acc.DisabledAt = params.DisabledAt
+	return acc, nil
 }
 
 func (m *mockAccountRepo) UpdateToken(ctx context.Context, id, tokenHash string) (*model.Account, error) {
 	return nil, nil
 }
 
+func (m *mockAccountRepo) UpdateKakaoChannelID(ctx context.Context, id string, channelID *string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	acc.KakaoChannelID = channelID
+	return acc, nil
+}
+
+func (m *mockAccountRepo) UpdateTimezone(ctx context.Context, id, timezone string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	acc.Timezone = timezone
+	return acc, nil
+}
+
+func (m *mockAccountRepo) UpdateShareKakaoProfile(ctx context.Context, id string, share bool) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	acc.ShareKakaoProfile = share
+	return acc, nil
+}
+
+func (m *mockAccountRepo) UpdateOnboarding(ctx context.Context, id string, onboarding json.RawMessage) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	acc.Onboarding = &onboarding
+	return acc, nil
+}
+
+func (m *mockAccountRepo) UpdateWebhook(ctx context.Context, id string, url, secret *string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	acc.WebhookURL = url
+	acc.WebhookSecret = secret
+	acc.WebhookPreviousSecret = nil
+	acc.WebhookPreviousSecretExpiresAt = nil
+	return acc, nil
+}
+
+func (m *mockAccountRepo) RotateWebhookSecret(ctx context.Context, id, secret string, previousExpiresAt time.Time) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok || !acc.UsesWebhook() {
+		return nil, nil
+	}
+	acc.WebhookPreviousSecret = acc.WebhookSecret
+	acc.WebhookPreviousSecretExpiresAt = &previousExpiresAt
+	acc.WebhookSecret = &secret
+	return acc, nil
+}
+
+func (m *mockAccountRepo) RecordWebhookFailure(ctx context.Context, id string, threshold int, probeAt time.Time) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok || !acc.UsesWebhook() {
+		return nil, nil
+	}
+	acc.WebhookFailures++
+	if acc.WebhookDegradedAt == nil && acc.WebhookFailures >= threshold {
+		now := time.Now()
+		acc.WebhookDegradedAt = &now
+	}
+	acc.WebhookProbeAt = nil
+	if acc.WebhookDegradedAt != nil {
+		acc.WebhookProbeAt = &probeAt
+	}
+	return acc, nil
+}
+
+func (m *mockAccountRepo) ResetWebhookFailures(ctx context.Context, id string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	acc.WebhookFailures = 0
+	acc.WebhookDegradedAt = nil
+	acc.WebhookProbeAt = nil
+	return acc, nil
+}
+
+func (m *mockAccountRepo) ClaimWebhookProbes(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.Account, error) {
+	var due []model.Account
+	for _, acc := range m.accounts {
+		if len(due) == limit {
+			break
+		}
+		if acc.WebhookDegraded() && acc.WebhookProbeAt != nil && !acc.WebhookProbeAt.After(now) {
+			acc.WebhookProbeAt = &leaseUntil
+			due = append(due, *acc)
+		}
+	}
+	return due, nil
+}
+
 func (m *mockAccountRepo) Delete(ctx context.Context, id string) error {
 	delete(m.accounts, id)
 	return nil
 }
 
-func (m *mockAccountRepo) FindAll(ctx context.Context, limit, offset int) ([]model.Account, error) {
+func (m *mockAccountRepo) FindAll(ctx context.Context, filter model.AccountFilter, limit, offset int) ([]model.Account, error) {
 	return nil, nil
 }
 
+func (m *mockAccountRepo) CountFiltered(ctx context.Context, filter model.AccountFilter) (int, error) {
+	return 0, nil
+}
+
+func (m *mockAccountRepo) DeletionPreview(ctx context.Context, id string) (*model.AccountDeletionPreview, error) {
+	return &model.AccountDeletionPreview{OAuthProviders: []string{}}, nil
+}
+
+func (m *mockAccountRepo) ScheduleDeletion(ctx context.Context, id string, purgeAt time.Time) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	now := time.Now()
+	acc.DeletionRequestedAt = &now
+	acc.DeletionScheduledAt = &purgeAt
+	if acc.DisabledAt == nil {
+		acc.DisabledAt = &now
+	}
+	return acc, nil
+}
+
+func (m *mockAccountRepo) CancelDeletion(ctx context.Context, id string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok || acc.DeletionScheduledAt == nil {
+		return nil, nil
+	}
+	if acc.DisabledAt != nil && acc.DeletionRequestedAt != nil && acc.DisabledAt.Equal(*acc.DeletionRequestedAt) {
+		acc.DisabledAt = nil
+	}
+	acc.DeletionRequestedAt = nil
+	acc.DeletionScheduledAt = nil
+	return acc, nil
+}
+
+func (m *mockAccountRepo) DeleteScheduled(ctx context.Context) ([]string, error) {
+	var ids []string
+	for id, acc := range m.accounts {
+		if acc.DeletionScheduledAt != nil && !acc.DeletionScheduledAt.After(time.Now()) && acc.LegalHoldAt == nil {
+			delete(m.accounts, id)
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockAccountRepo) SetLegalHold(ctx context.Context, id, reason string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok {
+		return nil, nil
+	}
+	if acc.LegalHoldAt == nil {
+		now := time.Now()
+		acc.LegalHoldAt = &now
+	}
+	acc.LegalHoldReason = &reason
+	return acc, nil
+}
+
+func (m *mockAccountRepo) ReleaseLegalHold(ctx context.Context, id string) (*model.Account, error) {
+	acc, ok := m.accounts[id]
+	if !ok || acc.LegalHoldAt == nil {
+		return nil, nil
+	}
+	acc.LegalHoldAt = nil
+	acc.LegalHoldReason = nil
+	return acc, nil
+}
+
+func (m *mockAccountRepo) IsUnderLegalHold(ctx context.Context, id string) (bool, error) {
+	acc, ok := m.accounts[id]
+	return ok && acc.LegalHoldAt != nil, nil
+}
+
 func (m *mockAccountRepo) Count(ctx context.Context) (int, error) {
 	return len(m.accounts), nil
 }
@@ -170,7 +373,7 @@ func TestPortalService(t *testing.T) {
 		sessionRepo := newMockPortalSessionRepo()
 		accountRepo := newMockAccountRepo()
 
-		svc := NewPortalService(userRepo, sessionRepo, accountRepo, "test-secret")
+		svc := NewPortalService(userRepo, sessionRepo, accountRepo, nil, nil, "test-secret")
 
 		token, err := svc.CreateSession(context.Background(), "user-123")
 
@@ -191,7 +394,7 @@ func TestPortalService(t *testing.T) {
 			AccountID: "account-123",
 		}
 
-		svc := NewPortalService(userRepo, sessionRepo, accountRepo, "test-secret")
+		svc := NewPortalService(userRepo, sessionRepo, accountRepo, nil, nil, "test-secret")
 
 		// Create a session
 		token, _ := svc.CreateSession(context.Background(), "user-123")
@@ -209,7 +412,7 @@ func TestPortalService(t *testing.T) {
 		sessionRepo := newMockPortalSessionRepo()
 		accountRepo := newMockAccountRepo()
 
-		svc := NewPortalService(userRepo, sessionRepo, accountRepo, "test-secret")
+		svc := NewPortalService(userRepo, sessionRepo, accountRepo, nil, nil, "test-secret")
 
 		user, err := svc.ValidateSession(context.Background(), "invalid-token")
 
@@ -222,7 +425,7 @@ func TestPortalService(t *testing.T) {
 		sessionRepo := newMockPortalSessionRepo()
 		accountRepo := newMockAccountRepo()
 
-		svc := NewPortalService(userRepo, sessionRepo, accountRepo, "test-secret")
+		svc := NewPortalService(userRepo, sessionRepo, accountRepo, nil, nil, "test-secret")
 
 		// Create a session
 		token, _ := svc.CreateSession(context.Background(), "user-123")
@@ -234,4 +437,107 @@ func TestPortalService(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, sessionRepo.sessions, 0)
 	})
+
+	t.Run("ScheduleAccountDeletion requires the preview token", func(t *testing.T) {
+		userRepo := newMockPortalUserRepo()
+		sessionRepo := newMockPortalSessionRepo()
+		accountRepo := newMockAccountRepo()
+		mailer := &recordingMailer{}
+
+		userRepo.users["user-123"] = &model.PortalUser{ID: "user-123", AccountID: "account-123", Email: "user@example.com"}
+		accountRepo.accounts["account-123"] = &model.Account{ID: "account-123"}
+
+		svc := NewPortalService(userRepo, sessionRepo, accountRepo, nil, mailer, "test-secret")
+
+		_, err := svc.ScheduleAccountDeletion(context.Background(), "user-123", "")
+		assert.Error(t, err)
+		assert.Nil(t, accountRepo.accounts["account-123"].DeletionScheduledAt)
+
+		preview, err := svc.PreviewDeleteAccount(context.Background(), "user-123")
+		assert.NoError(t, err)
+		assert.Equal(t, "account-123", preview.AccountID)
+
+		account, err := svc.ScheduleAccountDeletion(context.Background(), "user-123", preview.PreviewToken)
+		assert.NoError(t, err)
+		assert.NotNil(t, account.DisabledAt)
+		assert.WithinDuration(t, time.Now().Add(accountDeletionGracePeriod), *account.DeletionScheduledAt, time.Minute)
+		assert.Len(t, accountRepo.accounts, 1)
+		assert.Len(t, mailer.sent, 1)
+		assert.Equal(t, "user@example.com", mailer.sent[0].To)
+	})
+
+	t.Run("ScheduleAccountDeletion is refused under legal hold", func(t *testing.T) {
+		userRepo := newMockPortalUserRepo()
+		accountRepo := newMockAccountRepo()
+		heldAt := time.Now()
+
+		userRepo.users["user-123"] = &model.PortalUser{ID: "user-123", AccountID: "account-123"}
+		accountRepo.accounts["account-123"] = &model.Account{ID: "account-123", LegalHoldAt: &heldAt}
+
+		svc := NewPortalService(userRepo, newMockPortalSessionRepo(), accountRepo, nil, &recordingMailer{}, "test-secret")
+
+		preview, err := svc.PreviewDeleteAccount(context.Background(), "user-123")
+		assert.NoError(t, err)
+		_, err = svc.ScheduleAccountDeletion(context.Background(), "user-123", preview.PreviewToken)
+		assert.Equal(t, apperrors.ErrCodeLegalHold, apperrors.GetCode(err))
+		assert.Nil(t, accountRepo.accounts["account-123"].DeletionScheduledAt)
+	})
+
+	t.Run("CancelAccountDeletion restores the account", func(t *testing.T) {
+		userRepo := newMockPortalUserRepo()
+		sessionRepo := newMockPortalSessionRepo()
+		accountRepo := newMockAccountRepo()
+
+		userRepo.users["user-123"] = &model.PortalUser{ID: "user-123", AccountID: "account-123"}
+		accountRepo.accounts["account-123"] = &model.Account{ID: "account-123"}
+
+		svc := NewPortalService(userRepo, sessionRepo, accountRepo, nil, &recordingMailer{}, "test-secret")
+
+		_, err := svc.CancelAccountDeletion(context.Background(), "user-123")
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+
+		preview, _ := svc.PreviewDeleteAccount(context.Background(), "user-123")
+		_, err = svc.ScheduleAccountDeletion(context.Background(), "user-123", preview.PreviewToken)
+		assert.NoError(t, err)
+
+		account, err := svc.CancelAccountDeletion(context.Background(), "user-123")
+		assert.NoError(t, err)
+		assert.Nil(t, account.DisabledAt)
+		assert.Nil(t, account.DeletionScheduledAt)
+	})
+
+	t.Run("PurgeScheduledAccounts removes only due accounts", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		past := time.Now().Add(-time.Minute)
+		future := time.Now().Add(time.Hour)
+		accountRepo.accounts["due"] = &model.Account{ID: "due", DeletionScheduledAt: &past}
+		accountRepo.accounts["pending"] = &model.Account{ID: "pending", DeletionScheduledAt: &future}
+		accountRepo.accounts["active"] = &model.Account{ID: "active"}
+
+		svc := NewPortalService(newMockPortalUserRepo(), newMockPortalSessionRepo(), accountRepo, nil, nil, "test-secret")
+
+		purged, err := svc.PurgeScheduledAccounts(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.NotContains(t, accountRepo.accounts, "due")
+		assert.Len(t, accountRepo.accounts, 2)
+	})
+	t.Run("UpdateTimezone validates the zone name", func(t *testing.T) {
+		accountRepo := newMockAccountRepo()
+		accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1", Timezone: model.DefaultTimezone}
+		svc := NewPortalService(newMockPortalUserRepo(), newMockPortalSessionRepo(), accountRepo, nil, nil, "test-secret")
+
+		account, err := svc.UpdateTimezone(context.Background(), "acc-1", " America/New_York ")
+		assert.NoError(t, err)
+		assert.Equal(t, "America/New_York", account.Timezone)
+
+		for _, tz := range []string{"", "Local", "Mars/Olympus"} {
+			_, err := svc.UpdateTimezone(context.Background(), "acc-1", tz)
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), tz)
+		}
+		assert.Equal(t, "America/New_York", accountRepo.accounts["acc-1"].Timezone)
+
+		_, err = svc.UpdateTimezone(context.Background(), "missing", "UTC")
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
 }
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+const (
+	// PublicStatsTTL bounds how stale the public landing page stats can get.
+	PublicStatsTTL = 45 * time.Second
+	// PublicStatsStaleTTL is how long the last good stats are kept to serve
+	// while the database is failing.
+	PublicStatsStaleTTL = 24 * time.Hour
+
+	publicStatsKey      = "public_stats"
+	publicStatsStaleKey = "public_stats:stale"
+)
+
+// PublicStats is the subset of system stats shown to unauthenticated visitors.
+type PublicStats struct {
+	System struct {
+		Accounts    int `json:"accounts"`
+		Connections int `json:"connections"`
+		Sessions    struct {
+			Pending int `json:"pending"`
+			Paired  int `json:"paired"`
+			Total   int `json:"total"`
+		} `json:"sessions"`
+	} `json:"system"`
+	Messages struct {
+		Inbound struct {
+			Queued int `json:"queued"`
+		} `json:"inbound"`
+	} `json:"messages"`
+	IsPublic bool `json:"isPublic"`
+}
+
+// PublicStatsSource computes public stats, returning an error rather than
+// partial results when a query fails.
+type PublicStatsSource interface {
+	GetPublicStats(ctx context.Context) (*PublicStats, error)
+}
+
+// PublicStatsCache stores public stats. Fresh entries expire after the TTL;
+// the stale copy outlives them to cover database outages. Implementations are
+// best-effort: a failed lookup is reported as a miss.
+type PublicStatsCache interface {
+	Get(ctx context.Context) (*PublicStats, bool)
+	GetStale(ctx context.Context) (*PublicStats, bool)
+	Set(ctx context.Context, stats *PublicStats)
+}
+
+// PublicStatsService serves public stats from cache, falling back to the last
+// good stats when they cannot be computed.
+type PublicStatsService struct {
+	source PublicStatsSource
+	cache  PublicStatsCache
+}
+
+// NewPublicStatsService creates a public stats service. cache is optional.
+func NewPublicStatsService(source PublicStatsSource, cache PublicStatsCache) *PublicStatsService {
+	return &PublicStatsService{source: source, cache: cache}
+}
+
+func (s *PublicStatsService) Get(ctx context.Context) (*PublicStats, error) {
+	if s.cache != nil {
+		if stats, ok := s.cache.Get(ctx); ok {
+			return stats, nil
+		}
+	}
+
+	stats, err := s.source.GetPublicStats(ctx)
+	if err != nil {
+		if s.cache != nil {
+			if stale, ok := s.cache.GetStale(ctx); ok {
+				log.Warn().Err(err).Msg("serving stale public stats")
+				return stale, nil
+			}
+		}
+		return nil, err
+	}
+
+	if s.cache != nil {
+		s.cache.Set(ctx, stats)
+	}
+	return stats, nil
+}
+
+type redisPublicStatsCache struct {
+	client *redisclient.Client
+	ttl    time.Duration
+}
+
+func NewRedisPublicStatsCache(client *redisclient.Client, ttl time.Duration) PublicStatsCache {
+	return &redisPublicStatsCache{client: client, ttl: ttl}
+}
+
+func (c *redisPublicStatsCache) Get(ctx context.Context) (*PublicStats, bool) {
+	return c.get(ctx, publicStatsKey)
+}
+
+func (c *redisPublicStatsCache) GetStale(ctx context.Context) (*PublicStats, bool) {
+	return c.get(ctx, publicStatsStaleKey)
+}
+
+func (c *redisPublicStatsCache) get(ctx context.Context, key string) (*PublicStats, bool) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Warn().Err(err).Str("key", key).Msg("failed to read cached public stats")
+		}
+		return nil, false
+	}
+
+	var stats PublicStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("discarding malformed cached public stats")
+		return nil, false
+	}
+	return &stats, true
+}
+
+func (c *redisPublicStatsCache) Set(ctx context.Context, stats *PublicStats) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	_, err = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, publicStatsKey, data, c.ttl)
+		pipe.Set(ctx, publicStatsStaleKey, data, PublicStatsStaleTTL)
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to cache public stats")
+	}
+}
+
+// GetPublicStats computes the public subset of GetStats. Unlike GetStats it
+// fails on the first query error, so callers can fall back to cached stats
+// instead of showing zeros.
+func (s *AdminService) GetPublicStats(ctx context.Context) (*PublicStats, error) {
+	stats := &PublicStats{IsPublic: true}
+
+	accounts, err := s.accountRepo.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count accounts: %w", err)
+	}
+	stats.System.Accounts = accounts
+
+	// Paired conversations are reported as connections
+	connections, err := s.convRepo.CountByState(ctx, model.PairingStatePaired)
+	if err != nil {
+		return nil, fmt.Errorf("count connections: %w", err)
+	}
+	stats.System.Connections = connections
+
+	queued, err := s.inboundRepo.CountByStatus(ctx, model.InboundStatusQueued)
+	if err != nil {
+		return nil, fmt.Errorf("count queued messages: %w", err)
+	}
+	stats.Messages.Inbound.Queued = queued
+
+	sessions, err := s.countSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.System.Sessions.Pending = sessions.Pending
+	stats.System.Sessions.Paired = sessions.Paired
+	stats.System.Sessions.Total = sessions.Total
+
+	return stats, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+type fakePublicStatsSource struct {
+	stats *PublicStats
+	err   error
+	calls int
+}
+
+func (f *fakePublicStatsSource) GetPublicStats(ctx context.Context) (*PublicStats, error) {
+	f.calls++
+	return f.stats, f.err
+}
+
+type fakePublicStatsCache struct {
+	fresh *PublicStats
+	stale *PublicStats
+}
+
+func (f *fakePublicStatsCache) Get(ctx context.Context) (*PublicStats, bool) {
+	return f.fresh, f.fresh != nil
+}
+
+func (f *fakePublicStatsCache) GetStale(ctx context.Context) (*PublicStats, bool) {
+	return f.stale, f.stale != nil
+}
+
+func (f *fakePublicStatsCache) Set(ctx context.Context, stats *PublicStats) {
+	f.fresh = stats
+	f.stale = stats
+}
+
+func publicStatsWithAccounts(n int) *PublicStats {
+	stats := &PublicStats{IsPublic: true}
+	stats.System.Accounts = n
+	return stats
+}
+
+func TestPublicStatsService_Get(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("computes and caches on miss", func(t *testing.T) {
+		source := &fakePublicStatsSource{stats: publicStatsWithAccounts(3)}
+		cache := &fakePublicStatsCache{}
+		svc := NewPublicStatsService(source, cache)
+
+		stats, err := svc.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, stats.System.Accounts)
+
+		_, err = svc.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, source.calls, "second request should be served from cache")
+	})
+
+	t.Run("serves stale stats when the source fails", func(t *testing.T) {
+		source := &fakePublicStatsSource{err: errors.New("db down")}
+		cache := &fakePublicStatsCache{stale: publicStatsWithAccounts(7)}
+		svc := NewPublicStatsService(source, cache)
+
+		stats, err := svc.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 7, stats.System.Accounts)
+	})
+
+	t.Run("fails without a stale copy", func(t *testing.T) {
+		source := &fakePublicStatsSource{err: errors.New("db down")}
+		svc := NewPublicStatsService(source, &fakePublicStatsCache{})
+
+		_, err := svc.Get(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("works without a cache", func(t *testing.T) {
+		source := &fakePublicStatsSource{stats: publicStatsWithAccounts(1)}
+		svc := NewPublicStatsService(source, nil)
+
+		_, err := svc.Get(ctx)
+		require.NoError(t, err)
+		_, err = svc.Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, source.calls)
+	})
+}
+
+func TestRedisPublicStatsCache(t *testing.T) {
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	defer client.Close()
+	ctx := context.Background()
+	client.Del(ctx, publicStatsKey, publicStatsStaleKey)
+
+	cache := NewRedisPublicStatsCache(client, 50*time.Millisecond)
+
+	_, ok := cache.Get(ctx)
+	assert.False(t, ok)
+
+	cache.Set(ctx, publicStatsWithAccounts(5))
+
+	stats, ok := cache.Get(ctx)
+	require.True(t, ok)
+	assert.Equal(t, 5, stats.System.Accounts)
+	assert.True(t, stats.IsPublic)
+
+	freshTTL := client.PTTL(ctx, publicStatsKey).Val()
+	assert.LessOrEqual(t, freshTTL, 50*time.Millisecond)
+	staleTTL := client.PTTL(ctx, publicStatsStaleKey).Val()
+	assert.Greater(t, staleTTL, time.Hour)
+
+	stale, ok := cache.GetStale(ctx)
+	require.True(t, ok)
+	assert.Equal(t, 5, stale.System.Accounts)
+}
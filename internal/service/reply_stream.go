@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+const (
+	// ReplyStreamTTL bounds how long streamed output is kept. It outlasts the
+	// callback URL, after which the reply can no longer be sent anyway.
+	ReplyStreamTTL = 2 * time.Minute
+	// MaxReplyStreamBytes caps the output collected for one message.
+	MaxReplyStreamBytes = 64 * 1024
+)
+
+var (
+	// ErrReplyStreamClosed is returned for parts arriving after the reply
+	// was sent.
+	ErrReplyStreamClosed = errors.New("reply stream is already finished")
+	// ErrReplyStreamTooLarge is returned when a part would exceed
+	// MaxReplyStreamBytes.
+	ErrReplyStreamTooLarge = errors.New("reply stream is too large")
+)
+
+// ReplyStreamBuffer collects the output an agent streams for one inbound
+// message. Kakao accepts a single callback per webhook, so the parts are
+// joined and sent once the stream is closed.
+type ReplyStreamBuffer interface {
+	// Append adds a part and returns the size of the output so far.
+	Append(ctx context.Context, messageID, part string) (int, error)
+	// Close ends the stream and returns its output. Only the first caller
+	// gets ok, so concurrent finishers send one callback.
+	Close(ctx context.Context, messageID string) (output string, ok bool, err error)
+}
+
+// appendReplyStreamScript appends unless the stream was closed or would grow
+// past the limit; it returns -1 when closed and -2 when too large.
+var appendReplyStreamScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then
+    return -1
+end
+if redis.call('STRLEN', KEYS[1]) + string.len(ARGV[1]) > tonumber(ARGV[2]) then
+    return -2
+end
+local size = redis.call('APPEND', KEYS[1], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return size
+`)
+
+// closeReplyStreamScript marks the stream closed and takes its output, or
+// returns false when it was closed already.
+var closeReplyStreamScript = redis.NewScript(`
+if redis.call('SET', KEYS[2], '1', 'NX', 'PX', ARGV[1]) == false then
+    return false
+end
+local output = redis.call('GET', KEYS[1]) or ''
+redis.call('DEL', KEYS[1])
+return output
+`)
+
+type redisReplyStreamBuffer struct {
+	client *redisclient.Client
+	ttl    time.Duration
+}
+
+// NewRedisReplyStreamBuffer returns a buffer shared by all instances, so the
+// parts of one reply may arrive at different ones.
+func NewRedisReplyStreamBuffer(client *redisclient.Client) ReplyStreamBuffer {
+	return &redisReplyStreamBuffer{client: client, ttl: ReplyStreamTTL}
+}
+
+func replyStreamKeys(messageID string) []string {
+	return []string{
+		fmt.Sprintf("reply_stream:%s", messageID),
+		fmt.Sprintf("reply_stream_closed:%s", messageID),
+	}
+}
+
+func (b *redisReplyStreamBuffer) Append(ctx context.Context, messageID, part string) (int, error) {
+	size, err := appendReplyStreamScript.Run(ctx, b.client, replyStreamKeys(messageID),
+		part, MaxReplyStreamBytes, b.ttl.Milliseconds()).Int()
+	if err != nil {
+		return 0, fmt.Errorf("append reply stream: %w", err)
+	}
+	switch size {
+	case -1:
+		return 0, ErrReplyStreamClosed
+	case -2:
+		return 0, ErrReplyStreamTooLarge
+	}
+	return size, nil
+}
+
+func (b *redisReplyStreamBuffer) Close(ctx context.Context, messageID string) (string, bool, error) {
+	output, err := closeReplyStreamScript.Run(ctx, b.client, replyStreamKeys(messageID), b.ttl.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("close reply stream: %w", err)
+	}
+	return output, true, nil
+}
+
+// MemoryReplyStreamBuffer keeps streams within this process only. It is
+// suitable for a single instance and for tests.
+type MemoryReplyStreamBuffer struct {
+	mu      sync.Mutex
+	streams map[string]*memoryReplyStream
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type memoryReplyStream struct {
+	output    []byte
+	closed    bool
+	expiresAt time.Time
+}
+
+func NewMemoryReplyStreamBuffer() *MemoryReplyStreamBuffer {
+	return &MemoryReplyStreamBuffer{
+		streams: make(map[string]*memoryReplyStream),
+		ttl:     ReplyStreamTTL,
+		now:     time.Now,
+	}
+}
+
+// stream returns the live stream of a message, dropping expired ones.
+func (b *MemoryReplyStreamBuffer) stream(messageID string) *memoryReplyStream {
+	now := b.now()
+	for id, s := range b.streams {
+		if now.After(s.expiresAt) {
+			delete(b.streams, id)
+		}
+	}
+	s, ok := b.streams[messageID]
+	if !ok {
+		s = &memoryReplyStream{}
+		b.streams[messageID] = s
+	}
+	return s
+}
+
+func (b *MemoryReplyStreamBuffer) Append(ctx context.Context, messageID, part string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.stream(messageID)
+	if s.closed {
+		return 0, ErrReplyStreamClosed
+	}
+	if len(s.output)+len(part) > MaxReplyStreamBytes {
+		return 0, ErrReplyStreamTooLarge
+	}
+	s.output = append(s.output, part...)
+	s.expiresAt = b.now().Add(b.ttl)
+	return len(s.output), nil
+}
+
+func (b *MemoryReplyStreamBuffer) Close(ctx context.Context, messageID string) (string, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.stream(messageID)
+	if s.closed {
+		return "", false, nil
+	}
+	output := string(s.output)
+	s.output = nil
+	s.closed = true
+	s.expiresAt = b.now().Add(b.ttl)
+	return output, true, nil
+}
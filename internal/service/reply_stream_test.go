@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+// testReplyStreamBuffer runs the behavior every buffer must share.
+func testReplyStreamBuffer(t *testing.T, buffer ReplyStreamBuffer, messageID string) {
+	ctx := context.Background()
+
+	size, err := buffer.Append(ctx, messageID, "안녕")
+	require.NoError(t, err)
+	assert.Equal(t, len("안녕"), size)
+	size, err = buffer.Append(ctx, messageID, "하세요")
+	require.NoError(t, err)
+	assert.Equal(t, len("안녕하세요"), size)
+
+	_, err = buffer.Append(ctx, messageID, strings.Repeat("x", MaxReplyStreamBytes))
+	assert.ErrorIs(t, err, ErrReplyStreamTooLarge)
+
+	output, ok, err := buffer.Close(ctx, messageID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "안녕하세요", output)
+
+	// Only the first close sends the reply
+	_, ok, err = buffer.Close(ctx, messageID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = buffer.Append(ctx, messageID, "late")
+	assert.ErrorIs(t, err, ErrReplyStreamClosed)
+}
+
+func TestMemoryReplyStreamBuffer(t *testing.T) {
+	testReplyStreamBuffer(t, NewMemoryReplyStreamBuffer(), "msg-1")
+
+	t.Run("streams expire", func(t *testing.T) {
+		now := time.Now()
+		buffer := NewMemoryReplyStreamBuffer()
+		buffer.now = func() time.Time { return now }
+
+		_, err := buffer.Append(context.Background(), "msg-2", "stale")
+		require.NoError(t, err)
+		now = now.Add(ReplyStreamTTL + time.Second)
+
+		output, ok, err := buffer.Close(context.Background(), "msg-2")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, output)
+	})
+}
+
+func TestRedisReplyStreamBuffer(t *testing.T) {
+	client, err := redisclient.NewClient("redis://localhost:6379/15")
+	if err != nil {
+		t.Skip("Redis not available for testing")
+	}
+	defer client.Close()
+	client.Del(context.Background(), replyStreamKeys("msg-1")...)
+
+	testReplyStreamBuffer(t, NewRedisReplyStreamBuffer(client), "msg-1")
+}
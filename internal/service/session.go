@@ -3,9 +3,10 @@ package service
 import (
 	"context"
 	"crypto/rand"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/openclaw/relay-server-go/internal/database"
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
 	"github.com/openclaw/relay-server-go/internal/model"
 	"github.com/openclaw/relay-server-go/internal/repository"
 	"github.com/openclaw/relay-server-go/internal/sse"
@@ -39,6 +41,16 @@ type SessionStatusResult struct {
 	AccountID   *string             `json:"accountId,omitempty"`
 }
 
+// PairingLinkResult is a Kakao channel chat deep link. For a pending session
+// Message holds the pairing command the user should send.
+type PairingLinkResult struct {
+	URL         string     `json:"url"`
+	ChannelID   string     `json:"channelId"`
+	Message     string     `json:"message,omitempty"`
+	PairingCode string     `json:"pairingCode,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
 type SessionPairResult struct {
 	Success   bool
 	SessionID string
@@ -47,27 +59,56 @@ type SessionPairResult struct {
 }
 
 type SessionService struct {
-	db          *database.DB
-	sessionRepo repository.SessionRepository
-	accountRepo repository.AccountRepository
-	broker      *sse.Broker
+	db              *database.DB
+	sessionRepo     repository.SessionRepository
+	accountRepo     repository.AccountRepository
+	convRepo        repository.ConversationRepository
+	convLocker      ConversationLocker
+	broker          sse.EventBus
+	events          *SessionEvents
+	maxPendingPerIP int
+	kakaoChannelID  string
 }
 
+// NewSessionService creates a session service. maxPendingPerIP caps the number
+// of concurrent pending sessions a single client IP may hold; 0 disables the cap.
+// kakaoChannelID is the deployment's channel for pairing links, may be empty.
 func NewSessionService(
 	db *database.DB,
 	sessionRepo repository.SessionRepository,
 	accountRepo repository.AccountRepository,
-	broker *sse.Broker,
+	convRepo repository.ConversationRepository,
+	convLocker ConversationLocker,
+	broker sse.EventBus,
+	maxPendingPerIP int,
+	kakaoChannelID string,
 ) *SessionService {
 	return &SessionService{
-		db:          db,
-		sessionRepo: sessionRepo,
-		accountRepo: accountRepo,
-		broker:      broker,
+		db:              db,
+		sessionRepo:     sessionRepo,
+		accountRepo:     accountRepo,
+		convRepo:        convRepo,
+		convLocker:      convLocker,
+		broker:          broker,
+		events:          NewSessionEvents(broker),
+		maxPendingPerIP: maxPendingPerIP,
+		kakaoChannelID:  kakaoChannelID,
 	}
 }
 
-func (s *SessionService) CreateSession(ctx context.Context) (*CreateSessionResult, error) {
+// CreateSession creates a pending session for the plugin. It returns an
+// apperrors.ErrCodeTooManyPendingSessions error when clientIP already holds
+// the maximum number of pending sessions. pluginVersion is the version the
+// plugin reported, if any.
+// CreateSession starts a pending session. relayToken is optional: a plugin
+// that already has an account passes its token so the session's pairing link
+// uses the account's Kakao channel.
+func (s *SessionService) CreateSession(ctx context.Context, clientIP, pluginVersion, relayToken string) (*CreateSessionResult, error) {
+	channelID, err := s.accountChannel(ctx, relayToken)
+	if err != nil {
+		return nil, err
+	}
+
 	token, err := util.GenerateToken()
 	if err != nil {
 		return nil, fmt.Errorf("generate token: %w", err)
@@ -77,18 +118,35 @@ func (s *SessionService) CreateSession(ctx context.Context) (*CreateSessionResul
 	pairingCode := generateSessionPairingCode()
 	expiresAt := time.Now().Add(sessionPairingExpiryMins * time.Minute)
 
-	session, err := s.sessionRepo.Create(ctx, model.CreateSessionParams{
+	params := model.CreateSessionParams{
 		SessionTokenHash: tokenHash,
 		PairingCode:      pairingCode,
 		ExpiresAt:        expiresAt,
-	})
+		ClientIP:         optionalString(clientIP),
+		PluginVersion:    optionalString(pluginVersion),
+		KakaoChannelID:   channelID,
+	}
+	var session *model.Session
+	if s.maxPendingPerIP > 0 && clientIP != "" {
+		since := time.Now().Add(-sessionPairingExpiryMins * time.Minute)
+		session, err = s.sessionRepo.CreateWithinIPLimit(ctx, params, s.maxPendingPerIP, since)
+	} else {
+		session, err = s.sessionRepo.Create(ctx, params)
+	}
+	if errors.Is(err, repository.ErrPendingSessionLimit) {
+		log.Warn().
+			Str("ip", clientIP).
+			Int("limit", s.maxPendingPerIP).
+			Msg("pending session limit reached")
+		return nil, apperrors.TooManyPendingSessions(s.maxPendingPerIP)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("create session: %w", err)
 	}
 
 	log.Info().
 		Str("sessionId", session.ID).
-		Str("pairingCode", util.MaskCode(pairingCode)).
+		Str("pairingCode", pairingCode).
 		Time("expiresAt", expiresAt).
 		Msg("session created")
 
@@ -113,12 +171,139 @@ func (s *SessionService) GetStatus(ctx context.Context, tokenHash string) (*Sess
 
 	// Check if pending session has expired
 	if session.Status == model.SessionStatusPendingPairing && time.Now().After(session.ExpiresAt) {
-		s.sessionRepo.MarkExpired(ctx, session.ID)
+		if err := s.sessionRepo.MarkExpired(ctx, session.ID); err != nil {
+			log.Warn().Err(err).Str("sessionId", session.ID).Msg("failed to mark session expired")
+		} else {
+			s.events.SessionExpired(ctx, session)
+		}
 		return &SessionStatusResult{
 			Status: model.SessionStatusExpired,
 		}, nil
 	}
 
+	return sessionStatusResult(session), nil
+}
+
+// accountChannel returns the Kakao channel of the active account relayToken
+// belongs to, or nil without a token, for an unknown or disabled account, or
+// when the account has no channel of its own.
+func (s *SessionService) accountChannel(ctx context.Context, relayToken string) (*string, error) {
+	if relayToken == "" {
+		return nil, nil
+	}
+	account, err := s.accountRepo.FindByTokenHash(ctx, util.HashToken(relayToken))
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		// A stale token must not keep the plugin from pairing again
+		return nil, nil
+	}
+	return account.KakaoChannelID, nil
+}
+
+// PairingLink builds a Kakao channel chat link for the session. The channel is
+// the paired account's own channel when set; for a pending session, the
+// channel of the account that created it. Otherwise it is the deployment's.
+// For a pending session the link pre-fills the pairing command.
+func (s *SessionService) PairingLink(ctx context.Context, tokenHash string) (*PairingLinkResult, error) {
+	session, err := s.sessionRepo.FindByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("find session: %w", err)
+	}
+	if session == nil {
+		return nil, nil
+	}
+
+	channelID := s.kakaoChannelID
+	if session.KakaoChannelID != nil {
+		channelID = *session.KakaoChannelID
+	}
+	if session.AccountID != nil {
+		account, err := s.accountRepo.FindByID(ctx, *session.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("find account: %w", err)
+		}
+		if account != nil && account.KakaoChannelID != nil {
+			channelID = *account.KakaoChannelID
+		}
+	}
+	if channelID == "" {
+		return nil, apperrors.New(apperrors.ErrCodeNotFound, "Kakao channel is not configured")
+	}
+
+	result := &PairingLinkResult{
+		URL:       kakaoChatURL(channelID, ""),
+		ChannelID: channelID,
+	}
+
+	if session.Status == model.SessionStatusPendingPairing {
+		if time.Now().After(session.ExpiresAt) {
+			return nil, apperrors.PairingExpired()
+		}
+		result.Message = "/pair " + session.PairingCode
+		result.PairingCode = session.PairingCode
+		result.ExpiresAt = &session.ExpiresAt
+		result.URL = kakaoChatURL(channelID, result.Message)
+	}
+
+	return result, nil
+}
+
+// kakaoChatURL returns the channel's chat link, pre-filling text when given.
+func kakaoChatURL(channelID, text string) string {
+	chatURL := "https://pf.kakao.com/" + url.PathEscape(channelID) + "/chat"
+	if text == "" {
+		return chatURL
+	}
+	return chatURL + "?" + url.Values{"text": {text}}.Encode()
+}
+
+// WaitForPairing blocks until the session leaves pending_pairing, the timeout
+// elapses or ctx is done, and returns the session's status at that point. A
+// result still pending_pairing means the wait timed out.
+func (s *SessionService) WaitForPairing(ctx context.Context, sessionID string, timeout time.Duration) (*SessionStatusResult, error) {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("find session: %w", err)
+	}
+	if session == nil {
+		return nil, nil
+	}
+	if session.Status != model.SessionStatusPendingPairing {
+		return sessionStatusResult(session), nil
+	}
+
+	client := s.broker.Subscribe("session:" + sessionID)
+	defer s.broker.Unsubscribe(client)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Re-check after subscribing so a transition in between is not missed
+	for {
+		session, err = s.sessionRepo.FindByID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("find session: %w", err)
+		}
+		if session == nil {
+			return nil, nil
+		}
+		if session.Status != model.SessionStatusPendingPairing {
+			return sessionStatusResult(session), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return sessionStatusResult(session), nil
+		case <-client.Events:
+		}
+	}
+}
+
+func sessionStatusResult(session *model.Session) *SessionStatusResult {
 	result := &SessionStatusResult{
 		Status:    session.Status,
 		PairedAt:  session.PairedAt,
@@ -133,7 +318,21 @@ func (s *SessionService) GetStatus(ctx context.Context, tokenHash string) (*Sess
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// ExpirePendingSessions expires every pending session past its expiry time and
+// notifies each one's session channel. It returns the number expired.
+func (s *SessionService) ExpirePendingSessions(ctx context.Context) (int64, error) {
+	sessions, err := s.sessionRepo.ExpirePending(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("expire pending sessions: %w", err)
+	}
+
+	for i := range sessions {
+		s.events.PairingExpired(ctx, &sessions[i])
+	}
+	return int64(len(sessions)), nil
 }
 
 func (s *SessionService) FindByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
@@ -144,6 +343,12 @@ func (s *SessionService) FindByID(ctx context.Context, id string) (*model.Sessio
 	return s.sessionRepo.FindByID(ctx, id)
 }
 
+// FindPairedSession returns the plugin session a conversation is paired
+// with, or nil when it was paired another way.
+func (s *SessionService) FindPairedSession(ctx context.Context, conversationKey string) (*model.Session, error) {
+	return s.sessionRepo.FindPairedByConversationKey(ctx, conversationKey)
+}
+
 func (s *SessionService) VerifyPairingCode(ctx context.Context, code, conversationKey string) SessionPairResult {
 	normalizedCode := strings.ToUpper(strings.TrimSpace(code))
 
@@ -155,16 +360,113 @@ func (s *SessionService) VerifyPairingCode(ctx context.Context, code, conversati
 	}
 
 	if session == nil {
-		log.Warn().Str("code", util.MaskCode(normalizedCode)).Msg("invalid session pairing code")
+		log.Warn().Str("code", normalizedCode).Msg("invalid session pairing code")
 		return SessionPairResult{Success: false, Error: "INVALID_CODE"}
 	}
 
+	account, err := s.pairSession(ctx, session, conversationKey)
+	if err != nil {
+		log.Error().Err(err).Msg("verify pairing code: transaction failed")
+		return SessionPairResult{Success: false, Error: "INTERNAL_ERROR"}
+	}
+
+	log.Info().
+		Str("sessionId", session.ID).
+		Str("accountId", account.ID).
+		Str("conversationKey", conversationKey).
+		Msg("session paired successfully")
+
+	return SessionPairResult{
+		Success:   true,
+		SessionID: session.ID,
+		AccountID: account.ID,
+	}
+}
+
+// ForcePair pairs a pending session to an existing conversation without the
+// user sending the pairing code, for support when they cannot type it. The
+// pairing_complete event is published as for a normal pairing.
+func (s *SessionService) ForcePair(ctx context.Context, sessionID, conversationKey string) (*model.Session, error) {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("find session: %w", err)
+	}
+	if session == nil {
+		return nil, apperrors.NotFound("Session")
+	}
+	if session.Status == model.SessionStatusPaired {
+		return nil, apperrors.AlreadyPaired()
+	}
+	if session.Status != model.SessionStatusPendingPairing || !session.ExpiresAt.After(time.Now()) {
+		return nil, apperrors.PairingExpired()
+	}
+
+	unlock := lockConversation(ctx, s.convLocker, conversationKey)
+	defer unlock()
+
+	conv, err := s.convRepo.FindByKey(ctx, conversationKey)
+	if err != nil {
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+	if conv == nil {
+		return nil, apperrors.NotFound("Conversation")
+	}
+	if conv.State == model.PairingStateBlocked {
+		return nil, apperrors.InvalidInput("conversationKey", "conversation is blocked")
+	}
+	if conv.State == model.PairingStatePaired || conv.State == model.PairingStateArchived {
+		return nil, apperrors.New(apperrors.ErrCodeAlreadyPaired, "Conversation is already paired")
+	}
+
+	if _, err := s.pairSession(ctx, session, conversationKey); err != nil {
+		return nil, err
+	}
+
+	paired, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("reload session: %w", err)
+	}
+	if paired == nil {
+		return nil, apperrors.NotFound("Session")
+	}
+	if err := s.PublishPairingComplete(ctx, paired, conversationKey); err != nil {
+		log.Warn().Err(err).Msg("failed to publish pairing_complete event")
+	}
+
+	log.Info().
+		Str("sessionId", sessionID).
+		Str("conversationKey", conversationKey).
+		Msg("session force paired")
+
+	return paired, nil
+}
+
+// RevokePendingSession expires a pending session before its pairing code is
+// used and notifies the plugin waiting on it.
+func (s *SessionService) RevokePendingSession(ctx context.Context, sessionID string) error {
+	session, err := s.sessionRepo.RevokePending(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("revoke pending session: %w", err)
+	}
+	if session == nil {
+		return apperrors.NotFound("Pending session")
+	}
+
+	s.events.PairingExpired(ctx, session)
+	return nil
+}
+
+// pairSession creates the session's account and pairs both the session and
+// the conversation to it in one transaction.
+func (s *SessionService) pairSession(ctx context.Context, session *model.Session, conversationKey string) (*model.Account, error) {
 	var account *model.Account
 
-	// Use transaction to ensure atomicity of account creation + session pairing
-	err = s.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+	// Use transaction to ensure atomicity of account creation, session pairing
+	// and the conversation state update
+	err := s.db.WithTx(ctx, func(tx *sqlx.Tx) error {
 		txAccountRepo := s.accountRepo.WithTx(tx)
 		txSessionRepo := s.sessionRepo.WithTx(tx)
+		txConvRepo := s.convRepo.WithTx(tx)
 
 		// Create account for this session within transaction
 		var createErr error
@@ -178,25 +480,17 @@ func (s *SessionService) VerifyPairingCode(ctx context.Context, code, conversati
 			return fmt.Errorf("mark paired: %w", markErr)
 		}
 
+		// Mark the conversation paired to the new account within the same transaction
+		if stateErr := txConvRepo.UpdateState(ctx, conversationKey, model.PairingStatePaired, &account.ID); stateErr != nil {
+			return fmt.Errorf("update conversation state: %w", stateErr)
+		}
+
 		return nil
 	})
-
 	if err != nil {
-		log.Error().Err(err).Msg("verify pairing code: transaction failed")
-		return SessionPairResult{Success: false, Error: "INTERNAL_ERROR"}
-	}
-
-	log.Info().
-		Str("sessionId", session.ID).
-		Str("accountId", account.ID).
-		Str("conversationKey", conversationKey).
-		Msg("session paired successfully")
-
-	return SessionPairResult{
-		Success:   true,
-		SessionID: session.ID,
-		AccountID: account.ID,
+		return nil, err
 	}
+	return account, nil
 }
 
 func (s *SessionService) createAccountForSession(ctx context.Context, sessionID string) (*model.Account, error) {
@@ -235,18 +529,13 @@ func (s *SessionService) PublishPairingComplete(ctx context.Context, session *mo
 		kakaoUserID = parts[1]
 	}
 
-	eventDataBytes, err := json.Marshal(map[string]string{
-		"kakaoUserId": kakaoUserID,
-		"pairedAt":    time.Now().Format(time.RFC3339),
-		"accountId":   *session.AccountID,
+	event, err := sse.NewEvent(EventPairingComplete, *session.AccountID, conversationKey, PairingCompleteEvent{
+		KakaoUserID: kakaoUserID,
+		PairedAt:    eventTime(time.Now()),
+		AccountID:   *session.AccountID,
 	})
 	if err != nil {
-		return fmt.Errorf("marshal pairing complete event: %w", err)
-	}
-
-	event := sse.Event{
-		Type: "pairing_complete",
-		Data: eventDataBytes,
+		return err
 	}
 
 	// Publish to session channel (for pending SSE connections)
@@ -269,17 +558,14 @@ func (s *SessionService) PublishPairingExpired(ctx context.Context, sessionID st
 		return nil // No account to notify
 	}
 
-	eventDataBytes, err := json.Marshal(map[string]string{
-		"reason": reason,
+	event, err := sse.NewEvent(EventPairingExpired, *session.AccountID, "", PairingExpiredEvent{
+		Reason: reason,
 	})
 	if err != nil {
-		return fmt.Errorf("marshal pairing expired event: %w", err)
+		return err
 	}
 
-	return s.broker.Publish(ctx, *session.AccountID, sse.Event{
-		Type: "pairing_expired",
-		Data: eventDataBytes,
-	})
+	return s.broker.Publish(ctx, *session.AccountID, event)
 }
 
 func generateSessionPairingCode() string {
@@ -298,3 +584,11 @@ func generateSessionPairingCode() string {
 
 	return fmt.Sprintf("%s-%s", string(part1), string(part2))
 }
+
+// optionalString returns nil for an empty string so it is stored as NULL.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/ratelimit"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// Session lifecycle event types published over SSE
+const (
+	EventSessionExpired      = "session_expired"
+	EventPairingExpired      = "pairing_expired"
+	EventSessionDisconnected = "session_disconnected"
+	EventTokenRegenerated    = "token_regenerated"
+	EventPairingComplete     = "pairing_complete"
+
+	// EventUpgradeRequired is sent on connect when the plugin is older than
+	// the recommended version.
+	EventUpgradeRequired = "upgrade_required"
+
+	// Conversation archive events are sent when the Kakao user pauses the
+	// relay with /archive and when a new message resumes it.
+	EventConversationArchived    = "conversation_archived"
+	EventConversationReactivated = "conversation_reactivated"
+
+	// Delivery pause events are sent when the agent pauses or resumes a
+	// conversation, so other connections of the account see the change.
+	EventConversationPaused  = "conversation_paused"
+	EventConversationResumed = "conversation_resumed"
+
+	// EventRoutesRebalanced is sent when a routed plugin session leaves and
+	// its conversations move to the remaining sessions.
+	EventRoutesRebalanced = "routes_rebalanced"
+
+	// EventReplyDelivered is the delivery receipt of a reply posted to a
+	// Kakao callback.
+	EventReplyDelivered = "reply_delivered"
+
+	// EventRateLimitWarning is sent when the account has used most of its
+	// rate limit, before requests start failing with 429.
+	EventRateLimitWarning = ratelimit.WarningCode
+
+	// Delivery mode events are sent when the account's webhook failed too
+	// often and its messages fall back to the event stream, and when a probe
+	// of the endpoint succeeded and webhook delivery is restored.
+	EventDeliveryModeDegraded = "delivery_mode_degraded"
+	EventDeliveryModeRestored = "delivery_mode_restored"
+)
+
+// Event payloads. Times are sent in RFC 3339 with second precision.
+
+type PairingCompleteEvent struct {
+	KakaoUserID string    `json:"kakaoUserId"`
+	PairedAt    time.Time `json:"pairedAt"`
+	AccountID   string    `json:"accountId"`
+}
+
+// PairingExpiredEvent is sent by the expiry job (SessionID, ExpiredAt) and
+// when a session is expired for a reason such as an admin revoke (Reason).
+type PairingExpiredEvent struct {
+	SessionID string    `json:"sessionId,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	ExpiredAt time.Time `json:"expiredAt,omitzero"`
+}
+
+type SessionExpiredEvent struct {
+	SessionID string    `json:"sessionId"`
+	ExpiredAt time.Time `json:"expiredAt"`
+}
+
+type SessionDisconnectedEvent struct {
+	SessionID      string    `json:"sessionId"`
+	Reason         string    `json:"reason"`
+	DisconnectedAt time.Time `json:"disconnectedAt"`
+}
+
+type TokenRegeneratedEvent struct {
+	AccountID     string    `json:"accountId"`
+	Source        string    `json:"source"`
+	RegeneratedAt time.Time `json:"regeneratedAt"`
+}
+
+type ConversationArchivedEvent struct {
+	ConversationKey string    `json:"conversationKey"`
+	ArchivedAt      time.Time `json:"archivedAt"`
+}
+
+type ConversationReactivatedEvent struct {
+	ConversationKey string    `json:"conversationKey"`
+	ReactivatedAt   time.Time `json:"reactivatedAt"`
+}
+
+type ConversationPausedEvent struct {
+	ConversationKey string    `json:"conversationKey"`
+	PausedAt        time.Time `json:"pausedAt"`
+	AutoReply       *string   `json:"autoReply"`
+}
+
+type RoutesRebalancedEvent struct {
+	// The session that left
+	SessionID string `json:"sessionId"`
+	// Conversations with queued messages that were sent again to their new
+	// sessions after this event
+	MovedConversations []string  `json:"movedConversations"`
+	RebalancedAt       time.Time `json:"rebalancedAt"`
+}
+
+type ReplyDeliveredEvent struct {
+	OutboundID      string                      `json:"outboundId"`
+	MessageID       *string                     `json:"messageId,omitempty"`
+	ConversationKey string                      `json:"conversationKey"`
+	Status          model.OutboundMessageStatus `json:"status"`
+	Error           string                      `json:"error,omitempty"`
+	// Kakao's HTTP status and task ID; absent when Kakao did not answer
+	CallbackStatus int       `json:"callbackStatus,omitempty"`
+	CallbackTaskID string    `json:"callbackTaskId,omitempty"`
+	DeliveredAt    time.Time `json:"deliveredAt"`
+}
+
+type ConversationResumedEvent struct {
+	ConversationKey string    `json:"conversationKey"`
+	ResumedAt       time.Time `json:"resumedAt"`
+	// Held messages delivered again after this event
+	HeldCount int `json:"heldCount"`
+}
+
+type DeliveryModeDegradedEvent struct {
+	// Error of the last failed webhook post
+	Reason      string    `json:"reason"`
+	DegradedAt  time.Time `json:"degradedAt"`
+	NextProbeAt time.Time `json:"nextProbeAt"`
+	// Queued messages sent as message events after this event
+	QueuedCount int `json:"queuedCount"`
+}
+
+type DeliveryModeRestoredEvent struct {
+	RestoredAt time.Time `json:"restoredAt"`
+}
+
+// eventTime drops sub-second precision so event timestamps keep the
+// RFC 3339 format plugins already parse.
+func eventTime(t time.Time) time.Time {
+	return t.Truncate(time.Second)
+}
+
+// SessionEvents publishes session lifecycle events so that the plugin can react
+// (re-pair, refresh its token) instead of discovering the change on its next
+// failing request. Publishing is best effort: failures are logged, not returned.
+type SessionEvents struct {
+	broker sse.EventPublisher
+}
+
+func NewSessionEvents(broker sse.EventPublisher) *SessionEvents {
+	return &SessionEvents{broker: broker}
+}
+
+// SessionExpired notifies that a pending session expired before it was paired.
+func (e *SessionEvents) SessionExpired(ctx context.Context, session *model.Session) {
+	e.publishSession(ctx, session, EventSessionExpired, SessionExpiredEvent{
+		SessionID: session.ID,
+		ExpiredAt: eventTime(time.Now()),
+	})
+}
+
+// PairingExpired notifies a plugin waiting on the session channel that its
+// pairing code expired unused. It is published by the expiry job as soon as
+// expires_at passes, or when an admin revokes the session, so the plugin does
+// not have to poll to find out.
+func (e *SessionEvents) PairingExpired(ctx context.Context, session *model.Session) {
+	e.publishSession(ctx, session, EventPairingExpired, PairingExpiredEvent{
+		SessionID: session.ID,
+		ExpiredAt: eventTime(session.ExpiresAt),
+	})
+}
+
+// SessionDisconnected notifies that a session was disconnected, e.g. by an admin.
+func (e *SessionEvents) SessionDisconnected(ctx context.Context, session *model.Session, reason string) {
+	e.publishSession(ctx, session, EventSessionDisconnected, SessionDisconnectedEvent{
+		SessionID:      session.ID,
+		Reason:         reason,
+		DisconnectedAt: eventTime(time.Now()),
+	})
+}
+
+// TokenRegenerated notifies that the account's relay token was replaced.
+// The new token is never included; source says who regenerated it.
+func (e *SessionEvents) TokenRegenerated(ctx context.Context, accountID, source string) {
+	e.publish(ctx, accountID, EventTokenRegenerated, TokenRegeneratedEvent{
+		AccountID:     accountID,
+		Source:        source,
+		RegeneratedAt: eventTime(time.Now()),
+	})
+}
+
+// RateLimitWarning notifies that the account is close to its rate limit so
+// the agent can slow down.
+func (e *SessionEvents) RateLimitWarning(ctx context.Context, accountID string, warning ratelimit.Warning) {
+	e.publish(ctx, accountID, EventRateLimitWarning, warning)
+}
+
+// ConversationArchived notifies that the Kakao user paused the conversation;
+// messages to it are refused until it is reactivated.
+func (e *SessionEvents) ConversationArchived(ctx context.Context, accountID, conversationKey string) {
+	e.publish(ctx, accountID, EventConversationArchived, ConversationArchivedEvent{
+		ConversationKey: conversationKey,
+		ArchivedAt:      eventTime(time.Now()),
+	})
+}
+
+// ConversationReactivated notifies that an archived conversation is relayed
+// again because the Kakao user sent a message.
+func (e *SessionEvents) ConversationReactivated(ctx context.Context, accountID, conversationKey string) {
+	e.publish(ctx, accountID, EventConversationReactivated, ConversationReactivatedEvent{
+		ConversationKey: conversationKey,
+		ReactivatedAt:   eventTime(time.Now()),
+	})
+}
+
+// ConversationPaused notifies that the conversation's messages are held back
+// until it is resumed.
+func (e *SessionEvents) ConversationPaused(ctx context.Context, accountID string, conv *model.ConversationMapping) {
+	e.publish(ctx, accountID, EventConversationPaused, ConversationPausedEvent{
+		ConversationKey: conv.ConversationKey,
+		PausedAt:        eventTime(*conv.DeliveryPausedAt),
+		AutoReply:       conv.PauseAutoReply,
+	})
+}
+
+// ConversationResumed notifies that a paused conversation is delivered
+// again; its heldCount held messages follow as message events.
+func (e *SessionEvents) ConversationResumed(ctx context.Context, accountID, conversationKey string, heldCount int) {
+	e.publish(ctx, accountID, EventConversationResumed, ConversationResumedEvent{
+		ConversationKey: conversationKey,
+		ResumedAt:       eventTime(time.Now()),
+		HeldCount:       heldCount,
+	})
+}
+
+// RoutesRebalanced notifies that a routed session left and its
+// conversations moved to the remaining sessions.
+func (e *SessionEvents) RoutesRebalanced(ctx context.Context, accountID, sessionID string, moved []string) {
+	if moved == nil {
+		moved = []string{}
+	}
+	e.publish(ctx, accountID, EventRoutesRebalanced, RoutesRebalancedEvent{
+		SessionID:          sessionID,
+		MovedConversations: moved,
+		RebalancedAt:       eventTime(time.Now()),
+	})
+}
+
+// ReplyDelivered reports the outcome of posting a reply to Kakao, with what
+// Kakao answered when it did.
+func (e *SessionEvents) ReplyDelivered(ctx context.Context, msg *model.OutboundMessage, resp *model.CallbackResponse, sendErr error) {
+	event := ReplyDeliveredEvent{
+		OutboundID:      msg.ID,
+		MessageID:       msg.InboundMessageID,
+		ConversationKey: msg.ConversationKey,
+		Status:          model.OutboundStatusSent,
+		DeliveredAt:     eventTime(time.Now()),
+	}
+	if sendErr != nil {
+		event.Status = model.OutboundStatusFailed
+		event.Error = sendErr.Error()
+	}
+	if resp != nil {
+		event.CallbackStatus = resp.Status
+		event.CallbackTaskID = resp.TaskID
+	}
+	e.publish(ctx, msg.AccountID, EventReplyDelivered, event)
+}
+
+// DeliveryModeDegraded notifies that messages of the account are no longer
+// posted to its failing webhook; its queuedCount queued messages follow as
+// message events.
+func (e *SessionEvents) DeliveryModeDegraded(ctx context.Context, account *model.Account, reason string, queuedCount int) {
+	e.publish(ctx, account.ID, EventDeliveryModeDegraded, DeliveryModeDegradedEvent{
+		Reason:      reason,
+		DegradedAt:  eventTime(*account.WebhookDegradedAt),
+		NextProbeAt: eventTime(*account.WebhookProbeAt),
+		QueuedCount: queuedCount,
+	})
+}
+
+// DeliveryModeRestored notifies that the account's webhook answered a probe
+// and messages are posted to it again.
+func (e *SessionEvents) DeliveryModeRestored(ctx context.Context, accountID string) {
+	e.publish(ctx, accountID, EventDeliveryModeRestored, DeliveryModeRestoredEvent{
+		RestoredAt: eventTime(time.Now()),
+	})
+}
+
+// publishSession sends to the account channel for paired sessions and to the
+// session channel for pending ones, matching where the plugin is subscribed.
+func (e *SessionEvents) publishSession(ctx context.Context, session *model.Session, eventType string, data any) {
+	if session.AccountID != nil {
+		e.publish(ctx, *session.AccountID, eventType, data)
+		return
+	}
+	e.publishTo(ctx, "session:"+session.ID, "", eventType, data)
+}
+
+func (e *SessionEvents) publish(ctx context.Context, accountID, eventType string, data any) {
+	e.publishTo(ctx, accountID, accountID, eventType, data)
+}
+
+func (e *SessionEvents) publishTo(ctx context.Context, channel, accountID, eventType string, data any) {
+	if e == nil || e.broker == nil {
+		return
+	}
+
+	event, err := sse.NewEvent(eventType, accountID, "", data)
+	if err != nil {
+		log.Error().Err(err).Str("type", eventType).Msg("failed to marshal session event")
+		return
+	}
+
+	if err := e.broker.Publish(ctx, channel, event); err != nil {
+		log.Warn().Err(err).Str("type", eventType).Msg("failed to publish session event")
+	}
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/sse"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+type mockSessionRepo struct {
+	mock.Mock
+}
+
+func (m *mockSessionRepo) FindByID(ctx context.Context, id string) (*model.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) FindByPairingCode(ctx context.Context, code string) (*model.Session, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) Create(ctx context.Context, params model.CreateSessionParams) (*model.Session, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) MarkPaired(ctx context.Context, id string, accountID string, conversationKey string) error {
+	args := m.Called(ctx, id, accountID, conversationKey)
+	return args.Error(0)
+}
+
+func (m *mockSessionRepo) FindPairedByConversationKey(ctx context.Context, conversationKey string) (*model.Session, error) {
+	args := m.Called(ctx, conversationKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) MarkExpired(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockSessionRepo) ExpirePending(ctx context.Context) ([]model.Session, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) RevokePending(ctx context.Context, id string) (*model.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) MarkDisconnected(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockSessionRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockSessionRepo) CountPendingByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	args := m.Called(ctx, ip, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockSessionRepo) CreateWithinIPLimit(ctx context.Context, params model.CreateSessionParams, limit int, since time.Time) (*model.Session, error) {
+	args := m.Called(ctx, params, limit, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *mockSessionRepo) WithTx(tx *sqlx.Tx) repository.SessionRepository {
+	return m
+}
+
+func (m *mockSessionRepo) UpdatePluginVersion(ctx context.Context, id string, version string) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *mockSessionRepo) CountByPluginVersion(ctx context.Context) ([]model.PluginVersionCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PluginVersionCount), args.Error(1)
+}
+
+func TestSessionService_CreateSession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores client IP and plugin version on the session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("CreateWithinIPLimit", ctx, mock.MatchedBy(func(p model.CreateSessionParams) bool {
+			return p.ClientIP != nil && *p.ClientIP == "203.0.113.1" &&
+				p.PluginVersion != nil && *p.PluginVersion == "1.4.0"
+		}), 5, mock.AnythingOfType("time.Time")).Return(&model.Session{ID: "session-1"}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 5, "")
+		result, err := svc.CreateSession(ctx, "203.0.113.1", "1.4.0", "")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.SessionToken)
+		assert.Equal(t, string(model.SessionStatusPendingPairing), result.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects when pending cap is reached", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("CreateWithinIPLimit", ctx, mock.Anything, 5, mock.AnythingOfType("time.Time")).Return(nil, repository.ErrPendingSessionLimit)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 5, "")
+		result, err := svc.CreateSession(ctx, "203.0.113.1", "", "")
+
+		assert.Nil(t, result)
+		assert.Equal(t, apperrors.ErrCodeTooManyPendingSessions, apperrors.GetCode(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips the check when cap is disabled", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("Create", ctx, mock.Anything).Return(&model.Session{ID: "session-1"}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		_, err := svc.CreateSession(ctx, "203.0.113.1", "", "")
+
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "CreateWithinIPLimit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSessionService_CreateSession_AccountChannel(t *testing.T) {
+	ctx := context.Background()
+	channelID := "_acct"
+	tokenHash := util.HashToken("rt_existing")
+	accounts := newMockAccountRepo()
+	accounts.accounts["acc-1"] = &model.Account{ID: "acc-1", RelayTokenHash: &tokenHash, KakaoChannelID: &channelID}
+
+	t.Run("records the channel of the account whose token created the session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("Create", ctx, mock.MatchedBy(func(p model.CreateSessionParams) bool {
+			return p.KakaoChannelID != nil && *p.KakaoChannelID == "_acct"
+		})).Return(&model.Session{ID: "session-1"}, nil)
+
+		svc := NewSessionService(nil, repo, accounts, nil, nil, nil, 0, "_deploy")
+		_, err := svc.CreateSession(ctx, "203.0.113.1", "", "rt_existing")
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("ignores an unknown token", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("Create", ctx, mock.MatchedBy(func(p model.CreateSessionParams) bool {
+			return p.KakaoChannelID == nil
+		})).Return(&model.Session{ID: "session-1"}, nil)
+
+		svc := NewSessionService(nil, repo, accounts, nil, nil, nil, 0, "_deploy")
+		_, err := svc.CreateSession(ctx, "203.0.113.1", "", "rt_unknown")
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestSessionService_ExpirePendingSessions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the number of expired sessions", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("ExpirePending", ctx).Return([]model.Session{{ID: "s1"}, {ID: "s2"}}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		count, err := svc.ExpirePendingSessions(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("ExpirePending", ctx).Return(nil, errors.New("db down"))
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		_, err := svc.ExpirePendingSessions(ctx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSessionService_WaitForPairing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns immediately when the session is no longer pending", func(t *testing.T) {
+		accountID := "account-1"
+		key := "channel:user-1"
+		repo := new(mockSessionRepo)
+		repo.On("FindByID", ctx, "s1").Return(&model.Session{
+			ID:                    "s1",
+			Status:                model.SessionStatusPaired,
+			AccountID:             &accountID,
+			PairedConversationKey: &key,
+		}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		result, err := svc.WaitForPairing(ctx, "s1", time.Second)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.SessionStatusPaired, result.Status)
+		assert.Equal(t, "user-1", *result.KakaoUserID)
+	})
+
+	t.Run("returns nil for unknown session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByID", ctx, "missing").Return(nil, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		result, err := svc.WaitForPairing(ctx, "missing", time.Second)
+
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestSessionService_PairingLink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pre-fills the pairing command for a pending session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByTokenHash", ctx, "hash").Return(&model.Session{
+			ID:          "s1",
+			Status:      model.SessionStatusPendingPairing,
+			PairingCode: "ABCD-2345",
+			ExpiresAt:   time.Now().Add(time.Minute),
+		}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "_xkAbC")
+		result, err := svc.PairingLink(ctx, "hash")
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://pf.kakao.com/_xkAbC/chat?text=%2Fpair+ABCD-2345", result.URL)
+		assert.Equal(t, "/pair ABCD-2345", result.Message)
+	})
+
+	t.Run("links a pending session to the channel of the account that created it", func(t *testing.T) {
+		channelID := "_acct"
+		repo := new(mockSessionRepo)
+		repo.On("FindByTokenHash", ctx, "hash").Return(&model.Session{
+			ID:             "s1",
+			Status:         model.SessionStatusPendingPairing,
+			PairingCode:    "ABCD-2345",
+			ExpiresAt:      time.Now().Add(time.Minute),
+			KakaoChannelID: &channelID,
+		}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "_xkAbC")
+		result, err := svc.PairingLink(ctx, "hash")
+
+		require.NoError(t, err)
+		assert.Equal(t, "_acct", result.ChannelID)
+		assert.Equal(t, "https://pf.kakao.com/_acct/chat?text=%2Fpair+ABCD-2345", result.URL)
+	})
+
+	t.Run("rejects expired pending session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByTokenHash", ctx, "hash").Return(&model.Session{
+			Status:    model.SessionStatusPendingPairing,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "_xkAbC")
+		_, err := svc.PairingLink(ctx, "hash")
+
+		assert.Equal(t, apperrors.ErrCodePairingExpired, apperrors.GetCode(err))
+	})
+
+	t.Run("fails when no channel is configured", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByTokenHash", ctx, "hash").Return(&model.Session{
+			Status:    model.SessionStatusPendingPairing,
+			ExpiresAt: time.Now().Add(time.Minute),
+		}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		_, err := svc.PairingLink(ctx, "hash")
+
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+}
+
+func TestSessionService_RevokePendingSession(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revokes a pending session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("RevokePending", ctx, "s1").Return(&model.Session{ID: "s1"}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		err := svc.RevokePendingSession(ctx, "s1")
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("returns not found when the session is not pending", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("RevokePending", ctx, "s1").Return(nil, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		err := svc.RevokePendingSession(ctx, "s1")
+
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+}
+
+func TestSessionService_ForcePair(t *testing.T) {
+	ctx := context.Background()
+	pending := &model.Session{
+		ID:        "s1",
+		Status:    model.SessionStatusPendingPairing,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	t.Run("rejects an expired session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByID", ctx, "s1").Return(&model.Session{
+			ID:        "s1",
+			Status:    model.SessionStatusPendingPairing,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		_, err := svc.ForcePair(ctx, "s1", "ch:user")
+
+		assert.Equal(t, apperrors.ErrCodePairingExpired, apperrors.GetCode(err))
+	})
+
+	t.Run("rejects an already paired session", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByID", ctx, "s1").Return(&model.Session{ID: "s1", Status: model.SessionStatusPaired}, nil)
+
+		svc := NewSessionService(nil, repo, nil, nil, nil, nil, 0, "")
+		_, err := svc.ForcePair(ctx, "s1", "ch:user")
+
+		assert.Equal(t, apperrors.ErrCodeAlreadyPaired, apperrors.GetCode(err))
+	})
+
+	t.Run("requires an existing conversation", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByID", ctx, "s1").Return(pending, nil)
+		convRepo := new(mockConversationRepo)
+		convRepo.On("FindByKey", ctx, "ch:user").Return(nil, nil)
+
+		svc := NewSessionService(nil, repo, nil, convRepo, nil, nil, 0, "")
+		_, err := svc.ForcePair(ctx, "s1", "ch:user")
+
+		assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+	})
+
+	t.Run("rejects a blocked conversation", func(t *testing.T) {
+		repo := new(mockSessionRepo)
+		repo.On("FindByID", ctx, "s1").Return(pending, nil)
+		convRepo := new(mockConversationRepo)
+		convRepo.On("FindByKey", ctx, "ch:user").Return(&model.ConversationMapping{State: model.PairingStateBlocked}, nil)
+
+		svc := NewSessionService(nil, repo, nil, convRepo, nil, nil, 0, "")
+		_, err := svc.ForcePair(ctx, "s1", "ch:user")
+
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err))
+	})
+}
+
+func TestSessionEventPayloads(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	t.Run("escapes values", func(t *testing.T) {
+		data, err := json.Marshal(SessionDisconnectedEvent{
+			SessionID:      "sess-1",
+			Reason:         `revoked by "admin"`,
+			DisconnectedAt: eventTime(at),
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"sessionId":"sess-1","reason":"revoked by \"admin\"","disconnectedAt":"2026-01-02T03:04:05Z"}`, string(data))
+	})
+
+	t.Run("omits unset pairing expired fields", func(t *testing.T) {
+		data, err := json.Marshal(PairingExpiredEvent{Reason: "revoked"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"reason":"revoked"}`, string(data))
+	})
+}
+
+func TestSessionEvents_Publish(t *testing.T) {
+	ctx := context.Background()
+	broker := sse.NewMemoryBroker(sse.BrokerOptions{})
+	defer broker.Close()
+	events := NewSessionEvents(broker)
+
+	accountID := "account-1"
+	accountClient := broker.Subscribe(accountID)
+	sessionClient := broker.Subscribe("session:s2")
+
+	t.Run("sends to the account channel for paired sessions", func(t *testing.T) {
+		events.SessionDisconnected(ctx, &model.Session{ID: "s1", AccountID: &accountID}, "admin")
+
+		event := <-accountClient.Events
+		assert.Equal(t, EventSessionDisconnected, event.Type)
+		assert.Equal(t, accountID, event.AccountID)
+		assert.NotEmpty(t, event.ID)
+
+		var data SessionDisconnectedEvent
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		assert.Equal(t, "s1", data.SessionID)
+		assert.Equal(t, "admin", data.Reason)
+	})
+
+	t.Run("sends to the session channel for pending sessions", func(t *testing.T) {
+		events.PairingExpired(ctx, &model.Session{ID: "s2", ExpiresAt: time.Now()})
+
+		event := <-sessionClient.Events
+		assert.Equal(t, EventPairingExpired, event.Type)
+		assert.Empty(t, event.AccountID)
+		assert.Empty(t, accountClient.Events)
+	})
+}
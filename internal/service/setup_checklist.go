@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+)
+
+// SetupChecklistService computes how far an account has come in setting up
+// the relay, so that the portal can guide new users through the remaining
+// steps.
+type SetupChecklistService struct {
+	repo repository.AccountSetupRepository
+	now  func() time.Time
+}
+
+func NewSetupChecklistService(repo repository.AccountSetupRepository) *SetupChecklistService {
+	return &SetupChecklistService{repo: repo, now: time.Now}
+}
+
+// Get returns the account's checklist. Steps shown by existing records are
+// recorded as flags, so they stay done once those records are cleaned up.
+func (s *SetupChecklistService) Get(ctx context.Context, accountID string) (*model.SetupChecklist, error) {
+	flags, err := s.repo.FindFlags(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find setup flags: %w", err)
+	}
+	derived, err := s.repo.FindDerived(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find setup progress: %w", err)
+	}
+
+	for step, doneAt := range derived {
+		if _, ok := flags[step]; ok {
+			continue
+		}
+		flags[step] = doneAt
+		if err := s.repo.SetFlag(ctx, accountID, step, doneAt); err != nil {
+			log.Warn().Err(err).Str("accountId", accountID).Str("step", step).Msg("failed to record setup step")
+		}
+	}
+
+	checklist := &model.SetupChecklist{Steps: make([]model.SetupChecklistStep, 0, len(model.SetupSteps)), Completed: true}
+	for _, step := range model.SetupSteps {
+		item := model.SetupChecklistStep{Name: step}
+		if doneAt, ok := flags[step]; ok {
+			item.Done = true
+			item.DoneAt = &doneAt
+		} else {
+			checklist.Completed = false
+		}
+		checklist.Steps = append(checklist.Steps, item)
+	}
+	return checklist, nil
+}
+
+// Record marks a step no record shows as done. Failures are only logged,
+// since the checklist is a guide and must not fail what it observes.
+func (s *SetupChecklistService) Record(ctx context.Context, accountID, step string) {
+	if s == nil {
+		return
+	}
+	if err := s.repo.SetFlag(ctx, accountID, step, s.now()); err != nil {
+		log.Warn().Err(err).Str("accountId", accountID).Str("step", step).Msg("failed to record setup step")
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+type mockAccountSetupRepo struct {
+	flags   map[string]time.Time
+	derived map[string]time.Time
+}
+
+func (m *mockAccountSetupRepo) FindFlags(ctx context.Context, accountID string) (map[string]time.Time, error) {
+	flags := make(map[string]time.Time, len(m.flags))
+	for step, at := range m.flags {
+		flags[step] = at
+	}
+	return flags, nil
+}
+
+func (m *mockAccountSetupRepo) SetFlag(ctx context.Context, accountID, step string, doneAt time.Time) error {
+	if _, ok := m.flags[step]; !ok {
+		m.flags[step] = doneAt
+	}
+	return nil
+}
+
+func (m *mockAccountSetupRepo) FindDerived(ctx context.Context, accountID string) (map[string]time.Time, error) {
+	return m.derived, nil
+}
+
+func TestSetupChecklistService(t *testing.T) {
+	tokenAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pairedAt := tokenAt.Add(time.Hour)
+	repo := &mockAccountSetupRepo{
+		flags:   map[string]time.Time{model.SetupStepTokenRetrieved: tokenAt},
+		derived: map[string]time.Time{model.SetupStepPaired: pairedAt},
+	}
+	svc := NewSetupChecklistService(repo)
+	ctx := context.Background()
+
+	checklist, err := svc.Get(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.False(t, checklist.Completed)
+	require.Len(t, checklist.Steps, len(model.SetupSteps))
+	assert.Equal(t, model.SetupChecklistStep{Name: model.SetupStepTokenRetrieved, Done: true, DoneAt: &tokenAt}, checklist.Steps[0])
+	assert.False(t, checklist.Steps[1].Done)
+	assert.Equal(t, model.SetupChecklistStep{Name: model.SetupStepPaired, Done: true, DoneAt: &pairedAt}, checklist.Steps[2])
+
+	t.Run("keeps derived steps after their records are gone", func(t *testing.T) {
+		repo.derived = map[string]time.Time{}
+		checklist, err := svc.Get(ctx, "acc-1")
+		require.NoError(t, err)
+		assert.True(t, checklist.Steps[2].Done)
+	})
+
+	t.Run("completes once every step is done", func(t *testing.T) {
+		for _, step := range model.SetupSteps {
+			svc.Record(ctx, "acc-1", step)
+		}
+		checklist, err := svc.Get(ctx, "acc-1")
+		require.NoError(t, err)
+		assert.True(t, checklist.Completed)
+		assert.Equal(t, tokenAt, *checklist.Steps[0].DoneAt)
+	})
+}
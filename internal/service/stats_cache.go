@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+// ConversationStatsTTL bounds how stale cached conversation stats can get.
+// New messages invalidate the entry right away; the TTL covers status
+// changes (e.g. an outbound message failing) and the day rollover.
+const ConversationStatsTTL = 30 * time.Second
+
+// ConversationStatsCache stores computed conversation stats. Implementations
+// are best-effort: a failed lookup is reported as a miss.
+type ConversationStatsCache interface {
+	Get(ctx context.Context, conversationKey string) (*ConversationStats, bool)
+	Set(ctx context.Context, stats *ConversationStats)
+	Invalidate(ctx context.Context, conversationKey string)
+}
+
+type redisConversationStatsCache struct {
+	client *redisclient.Client
+	ttl    time.Duration
+}
+
+func NewRedisConversationStatsCache(client *redisclient.Client, ttl time.Duration) ConversationStatsCache {
+	return &redisConversationStatsCache{client: client, ttl: ttl}
+}
+
+func conversationStatsKey(conversationKey string) string {
+	return fmt.Sprintf("conversation_stats:%s", conversationKey)
+}
+
+func (c *redisConversationStatsCache) Get(ctx context.Context, conversationKey string) (*ConversationStats, bool) {
+	data, err := c.client.Get(ctx, conversationStatsKey(conversationKey)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to read cached conversation stats")
+		}
+		return nil, false
+	}
+
+	var stats ConversationStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("discarding malformed cached conversation stats")
+		return nil, false
+	}
+	return &stats, true
+}
+
+func (c *redisConversationStatsCache) Set(ctx context.Context, stats *ConversationStats) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	if err := c.client.Set(ctx, conversationStatsKey(stats.ConversationKey), data, c.ttl).Err(); err != nil {
+		log.Warn().Err(err).Str("conversationKey", stats.ConversationKey).Msg("failed to cache conversation stats")
+	}
+}
+
+func (c *redisConversationStatsCache) Invalidate(ctx context.Context, conversationKey string) {
+	if err := c.client.Del(ctx, conversationStatsKey(conversationKey)).Err(); err != nil {
+		log.Warn().Err(err).Str("conversationKey", conversationKey).Msg("failed to invalidate cached conversation stats")
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+// TestConversationPrefix marks the conversation of synthetic test messages.
+// It is not a Kakao channel ID, so replies to it never reach Kakao.
+const TestConversationPrefix = "relay-test:"
+
+const (
+	// TestMessageText is the text of a synthetic test message.
+	TestMessageText = "[Relay test message] Sent from the portal to check delivery; no reply is needed."
+
+	testMessageTimeout      = 10 * time.Second
+	testMessagePollInterval = 250 * time.Millisecond
+)
+
+// Smoke test stages, in the order they run.
+const (
+	TestStageInject  = "inject"
+	TestStagePublish = "publish"
+	TestStageDeliver = "deliver"
+)
+
+// IsTestConversation reports whether the conversation is the one of
+// synthetic test messages.
+func IsTestConversation(conversationKey string) bool {
+	return strings.HasPrefix(conversationKey, TestConversationPrefix)
+}
+
+type TestMessageStage struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+type TestMessageResult struct {
+	OK        bool               `json:"ok"`
+	MessageID string             `json:"messageId,omitempty"`
+	Stages    []TestMessageStage `json:"stages"`
+}
+
+// TestMessageService sends a synthetic inbound message through the same path
+// as Kakao webhooks and reports how far it got, so that users can tell
+// whether their plugin receives messages at all.
+type TestMessageService struct {
+	messageService *MessageService
+	broker         sse.EventPublisher
+	timeout        time.Duration
+}
+
+func NewTestMessageService(messageService *MessageService, broker sse.EventPublisher) *TestMessageService {
+	return &TestMessageService{messageService: messageService, broker: broker, timeout: testMessageTimeout}
+}
+
+// Run stores and publishes a test message for the account and waits until an
+// event stream has sent it to a plugin. Later stages are skipped once one
+// fails. The message is acknowledged afterwards so it is not replayed.
+func (s *TestMessageService) Run(ctx context.Context, accountID string) *TestMessageResult {
+	result := &TestMessageResult{Stages: []TestMessageStage{}}
+	stage := func(name string, start time.Time, err error, detail string) bool {
+		st := TestMessageStage{Name: name, OK: err == nil, Detail: detail, DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			st.Detail = err.Error()
+		}
+		result.Stages = append(result.Stages, st)
+		return st.OK
+	}
+
+	start := time.Now()
+	msg, err := s.inject(ctx, accountID)
+	if !stage(TestStageInject, start, err, "") {
+		return result
+	}
+	result.MessageID = msg.ID
+	defer s.close(ctx, msg.ID)
+
+	start = time.Now()
+	event := sse.NewRawEvent("message", accountID, msg.ConversationKey, msg.ToSSEEventData(nil))
+	if !stage(TestStagePublish, start, s.broker.Publish(ctx, accountID, event), "") {
+		return result
+	}
+
+	start = time.Now()
+	delivered, err := s.waitDelivered(ctx, msg.ID)
+	detail := "received by a connected plugin"
+	if err == nil && !delivered {
+		err = errNotDelivered
+	}
+	result.OK = stage(TestStageDeliver, start, err, detail)
+	return result
+}
+
+var errNotDelivered = errors.New("no connected plugin received the message in time; check that the plugin is running and connected")
+
+func (s *TestMessageService) inject(ctx context.Context, accountID string) (*model.InboundMessage, error) {
+	conversationKey := TestConversationPrefix + accountID
+	sender := model.MessageSender{UserID: strings.TrimSuffix(TestConversationPrefix, ":"), ChannelID: accountID}
+	normalized, err := model.NewTextMessage(sender, TestMessageText, "").Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return s.messageService.CreateInbound(ctx, CreateInboundParams{
+		AccountID:         accountID,
+		ConversationKey:   conversationKey,
+		KakaoPayload:      json.RawMessage(`{"relayTest":true}`),
+		NormalizedMessage: normalized,
+	})
+}
+
+// waitDelivered polls until an event stream marked the message delivered.
+func (s *TestMessageService) waitDelivered(ctx context.Context, messageID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(testMessagePollInterval)
+	defer ticker.Stop()
+	for {
+		msg, err := s.messageService.FindInboundByID(ctx, messageID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, nil
+			}
+			return false, err
+		}
+		if msg != nil && msg.Status != model.InboundStatusQueued {
+			return true, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TestMessageService) close(ctx context.Context, messageID string) {
+	if err := s.messageService.MarkAcked(context.WithoutCancel(ctx), messageID); err != nil {
+		log.Warn().Err(err).Str("messageId", messageID).Msg("failed to acknowledge test message")
+	}
+}
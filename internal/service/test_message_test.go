@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+type fakeTestPublisher struct {
+	err    error
+	events []sse.Event
+}
+
+func (f *fakeTestPublisher) Publish(ctx context.Context, accountID string, event sse.Event) error {
+	f.events = append(f.events, event)
+	return f.err
+}
+
+func TestTestMessageService_Run(t *testing.T) {
+	created := &model.InboundMessage{ID: "msg-1", AccountID: "acc-1", ConversationKey: TestConversationPrefix + "acc-1", Status: model.InboundStatusQueued}
+	newRepo := func(status model.InboundMessageStatus) *mockInboundRepo {
+		repo := new(mockInboundRepo)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(p model.CreateInboundMessageParams) bool {
+			return p.AccountID == "acc-1" && IsTestConversation(p.ConversationKey)
+		})).Return(created, nil)
+		repo.On("FindByID", mock.Anything, "msg-1").Return(&model.InboundMessage{ID: "msg-1", Status: status}, nil)
+		repo.On("MarkAcked", mock.Anything, "msg-1").Return(nil)
+		return repo
+	}
+
+	t.Run("passes when a plugin receives the message", func(t *testing.T) {
+		repo := newRepo(model.InboundStatusDelivered)
+		publisher := &fakeTestPublisher{}
+		svc := NewTestMessageService(NewMessageService(repo, nil, nil, nil, nil), publisher)
+
+		result := svc.Run(context.Background(), "acc-1")
+
+		assert.True(t, result.OK)
+		assert.Equal(t, "msg-1", result.MessageID)
+		assert.Len(t, result.Stages, 3)
+		assert.Len(t, publisher.events, 1)
+		assert.Equal(t, "message", publisher.events[0].Type)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("fails the deliver stage when nothing receives it", func(t *testing.T) {
+		repo := newRepo(model.InboundStatusQueued)
+		svc := NewTestMessageService(NewMessageService(repo, nil, nil, nil, nil), &fakeTestPublisher{})
+		svc.timeout = 50 * time.Millisecond
+
+		result := svc.Run(context.Background(), "acc-1")
+
+		assert.False(t, result.OK)
+		assert.Equal(t, TestStageDeliver, result.Stages[2].Name)
+		assert.False(t, result.Stages[2].OK)
+		repo.AssertCalled(t, "MarkAcked", mock.Anything, "msg-1")
+	})
+
+	t.Run("stops at a failed publish", func(t *testing.T) {
+		repo := newRepo(model.InboundStatusQueued)
+		svc := NewTestMessageService(NewMessageService(repo, nil, nil, nil, nil), &fakeTestPublisher{err: errors.New("redis down")})
+
+		result := svc.Run(context.Background(), "acc-1")
+
+		assert.False(t, result.OK)
+		assert.Len(t, result.Stages, 2)
+		assert.Equal(t, "redis down", result.Stages[1].Detail)
+	})
+}
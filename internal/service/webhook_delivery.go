@@ -0,0 +1,578 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/repository"
+	"github.com/openclaw/relay-server-go/internal/sse"
+	"github.com/openclaw/relay-server-go/internal/util"
+)
+
+// Headers of webhook requests. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the account's secret. While
+// a rotated secret is still valid, a second, comma-separated signature made
+// with it follows.
+const (
+	WebhookSignatureHeader = "X-Relay-Signature"
+	WebhookTimestampHeader = "X-Relay-Timestamp"
+	WebhookDeliveryHeader  = "X-Relay-Delivery"
+)
+
+// WebhookProbeEvent is the type of the event posted to a degraded webhook to
+// find out whether it answers again.
+const WebhookProbeEvent = "delivery_probe"
+
+// DefaultWebhookMaxAttempts is how often a message is posted before webhook
+// delivery gives up on it.
+const DefaultWebhookMaxAttempts = 8
+
+const (
+	webhookTimeout = 10 * time.Second
+	// webhookBatch caps how many due messages one run claims.
+	webhookBatch = 20
+	// webhookLease holds claimed messages for the longest a run can take,
+	// since an account's messages are posted one after another.
+	webhookLease = webhookBatch * webhookTimeout
+	// Retries back off exponentially between these bounds.
+	webhookMinBackoff = 5 * time.Second
+	webhookMaxBackoff = 5 * time.Minute
+	// webhookSecretOverlap is how long a rotated secret keeps signing
+	// requests next to the new one.
+	webhookSecretOverlap = 24 * time.Hour
+	// After webhookDegradeAfter consecutive failed posts the account's
+	// messages fall back to the event stream, and the endpoint is probed
+	// with a backoff between these bounds until it answers again.
+	webhookDegradeAfter     = 5
+	webhookMinProbeInterval = time.Minute
+	webhookMaxProbeInterval = 30 * time.Minute
+
+	maxWebhookURLLen          = 2048
+	webhookErrorMaxLen        = 500
+	webhookSecretPrefix       = "whsec_"
+	webhookResponseDrainBytes = 4096
+)
+
+// WebhookSettings is an account's webhook configuration. Secret is only set
+// right after it was generated. PreviousSecretExpiresAt is set while a
+// rotated secret still signs requests. DegradedAt and NextProbeAt are set
+// while messages fall back to the event stream because the webhook failed.
+type WebhookSettings struct {
+	URL                     *string    `json:"url"`
+	Secret                  string     `json:"secret,omitempty"`
+	PreviousSecretExpiresAt *time.Time `json:"previousSecretExpiresAt,omitempty"`
+	DegradedAt              *time.Time `json:"degradedAt,omitempty"`
+	NextProbeAt             *time.Time `json:"nextProbeAt,omitempty"`
+}
+
+// WebhookDeliveryService posts inbound messages to the webhook URL of
+// accounts that configured one, for agents that cannot keep an event stream
+// open. Messages are claimed from the queue, so they are delivered once even
+// with several instances, and retried with backoff until the endpoint answers
+// with a 2xx status. An endpoint that keeps failing is degraded: its messages
+// are published to the event stream instead until a probe succeeds.
+type WebhookDeliveryService struct {
+	inboundRepo   repository.InboundMessageRepository
+	accountRepo   repository.AccountRepository
+	broker        sse.EventPublisher
+	events        *SessionEvents
+	client        *http.Client
+	encryptionKey string
+	maxAttempts   int
+	now           func() time.Time
+}
+
+// NewWebhookDeliveryService creates the service. Secrets are encrypted with
+// encryptionKey when it is set; maxAttempts <= 0 uses
+// DefaultWebhookMaxAttempts.
+func NewWebhookDeliveryService(
+	inboundRepo repository.InboundMessageRepository,
+	accountRepo repository.AccountRepository,
+	broker sse.EventPublisher,
+	events *SessionEvents,
+	encryptionKey string,
+	maxAttempts int,
+) *WebhookDeliveryService {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+	return &WebhookDeliveryService{
+		inboundRepo:   inboundRepo,
+		accountRepo:   accountRepo,
+		broker:        broker,
+		events:        events,
+		client:        newPublicHTTPClient(webhookTimeout),
+		encryptionKey: encryptionKey,
+		maxAttempts:   maxAttempts,
+		now:           time.Now,
+	}
+}
+
+// Get returns the account's webhook configuration.
+func (s *WebhookDeliveryService) Get(ctx context.Context, accountID string) (*WebhookSettings, error) {
+	account, err := s.findAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.settings(account, ""), nil
+}
+
+func (s *WebhookDeliveryService) settings(account *model.Account, secret string) *WebhookSettings {
+	settings := &WebhookSettings{URL: account.WebhookURL, Secret: secret}
+	if account.PreviousWebhookSecret(s.now()) != nil {
+		settings.PreviousSecretExpiresAt = account.WebhookPreviousSecretExpiresAt
+	}
+	if account.WebhookDegraded() {
+		settings.DegradedAt = account.WebhookDegradedAt
+		settings.NextProbeAt = account.WebhookProbeAt
+	}
+	return settings
+}
+
+// Set turns webhook delivery on with rawURL and a newly generated secret,
+// which is returned only this once.
+func (s *WebhookDeliveryService) Set(ctx context.Context, accountID, rawURL string) (*WebhookSettings, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	secret, stored, err := s.newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.UpdateWebhook(ctx, accountID, &rawURL, &stored)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+
+	log.Info().Str("accountId", accountID).Msg("webhook delivery enabled")
+	return &WebhookSettings{URL: account.WebhookURL, Secret: secret}, nil
+}
+
+// RotateSecret replaces the signing secret of the account's webhook. The new
+// secret is returned only this once; requests are signed with both secrets
+// until the old one expires, so the receiver can switch over in between.
+func (s *WebhookDeliveryService) RotateSecret(ctx context.Context, accountID string) (*WebhookSettings, error) {
+	secret, stored, err := s.newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.RotateWebhookSecret(ctx, accountID, stored, s.now().Add(webhookSecretOverlap))
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		if _, err := s.findAccount(ctx, accountID); err != nil {
+			return nil, err
+		}
+		return nil, apperrors.New(apperrors.ErrCodeConflict, "Webhook is not configured")
+	}
+
+	log.Info().Str("accountId", accountID).Msg("webhook secret rotated")
+	return s.settings(account, secret), nil
+}
+
+// newSecret generates a webhook secret and returns it with the form it is
+// stored in.
+func (s *WebhookDeliveryService) newSecret() (secret, stored string, err error) {
+	secret = webhookSecretPrefix + rand.Text()
+	if s.encryptionKey == "" {
+		return secret, secret, nil
+	}
+	stored, err = util.Encrypt(s.encryptionKey, secret)
+	if err != nil {
+		return "", "", fmt.Errorf("encrypt webhook secret: %w", err)
+	}
+	return secret, stored, nil
+}
+
+// Remove turns webhook delivery off; messages are consumed over the event
+// stream again.
+func (s *WebhookDeliveryService) Remove(ctx context.Context, accountID string) error {
+	account, err := s.accountRepo.UpdateWebhook(ctx, accountID, nil, nil)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return apperrors.NotFound("Account")
+	}
+
+	log.Info().Str("accountId", accountID).Msg("webhook delivery disabled")
+	return nil
+}
+
+// Handles reports whether the account's messages are delivered by webhook
+// rather than published to its event stream. It reports false while the
+// webhook is degraded and when the account cannot be loaded, so messages
+// still reach a connected plugin.
+func (s *WebhookDeliveryService) Handles(ctx context.Context, accountID string) bool {
+	if s == nil {
+		return false
+	}
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		log.Warn().Err(err).Str("accountId", accountID).Msg("failed to load account for webhook delivery")
+		return false
+	}
+	return account != nil && account.UsesWebhook() && !account.WebhookDegraded()
+}
+
+// DeliverDue posts the queued messages that are due and returns how many
+// were delivered. An account's messages are posted in order; after a failure
+// the rest of them are released and wait until the failed one was retried.
+func (s *WebhookDeliveryService) DeliverDue(ctx context.Context) (int, error) {
+	now := s.now()
+	msgs, err := s.inboundRepo.ClaimWebhookDue(ctx, now, now.Add(webhookLease), s.maxAttempts, webhookBatch)
+	if err != nil {
+		return 0, fmt.Errorf("claim webhook messages: %w", err)
+	}
+
+	accounts := map[string]*model.Account{}
+	failed := map[string]bool{}
+	delivered := 0
+	for i := range msgs {
+		msg := &msgs[i]
+		if failed[msg.AccountID] {
+			if err := s.inboundRepo.ReleaseWebhookLease(ctx, msg.ID); err != nil {
+				log.Warn().Err(err).Str("messageId", msg.ID).Msg("failed to release webhook message")
+			}
+			continue
+		}
+
+		account, ok := accounts[msg.AccountID]
+		if !ok {
+			if account, err = s.accountRepo.FindByID(ctx, msg.AccountID); err != nil {
+				log.Warn().Err(err).Str("accountId", msg.AccountID).Msg("failed to load account for webhook delivery")
+			}
+			accounts[msg.AccountID] = account
+		}
+
+		if err := s.deliver(ctx, account, msg); err != nil {
+			failed[msg.AccountID] = true
+			s.recordFailure(ctx, msg, err)
+			s.countFailure(ctx, account, err)
+			continue
+		}
+		if err := s.inboundRepo.MarkDelivered(ctx, msg.ID); err != nil {
+			log.Warn().Err(err).Str("messageId", msg.ID).Msg("failed to mark webhook message as delivered")
+		}
+		if account.WebhookFailures > 0 {
+			s.resetFailures(ctx, account)
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// ProbeDegraded posts a probe event to the degraded webhooks whose probe is
+// due and returns how many of them were restored. A failed probe schedules
+// the next one with a longer delay.
+func (s *WebhookDeliveryService) ProbeDegraded(ctx context.Context) (int, error) {
+	now := s.now()
+	accounts, err := s.accountRepo.ClaimWebhookProbes(ctx, now, now.Add(webhookLease), webhookBatch)
+	if err != nil {
+		return 0, fmt.Errorf("claim webhook probes: %w", err)
+	}
+
+	restored := 0
+	for i := range accounts {
+		account := &accounts[i]
+		if err := s.probe(ctx, account); err != nil {
+			log.Warn().Err(err).Str("accountId", account.ID).Int("failures", account.WebhookFailures+1).Msg("webhook probe failed")
+			s.countFailure(ctx, account, err)
+			continue
+		}
+		if s.resetFailures(ctx, account) {
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+func (s *WebhookDeliveryService) deliver(ctx context.Context, account *model.Account, msg *model.InboundMessage) error {
+	body, err := webhookBody(msg)
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, account, msg.ID, body)
+}
+
+type webhookProbe struct {
+	ProbedAt time.Time `json:"probedAt"`
+}
+
+func (s *WebhookDeliveryService) probe(ctx context.Context, account *model.Account) error {
+	event, err := sse.NewEvent(WebhookProbeEvent, account.ID, "", webhookProbe{ProbedAt: eventTime(s.now())})
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(event.Envelope())
+	if err != nil {
+		return fmt.Errorf("marshal webhook probe: %w", err)
+	}
+	return s.post(ctx, account, event.ID, body)
+}
+
+// post sends a signed webhook request and fails unless the endpoint answers
+// with a 2xx status.
+func (s *WebhookDeliveryService) post(ctx context.Context, account *model.Account, deliveryID string, body []byte) error {
+	if account == nil || !account.UsesWebhook() || account.WebhookSecret == nil {
+		return fmt.Errorf("webhook is not configured")
+	}
+	secrets, err := s.signingSecrets(account)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *account.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, deliveryID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, webhookSignatures(secrets, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused
+	_, _ = io.CopyN(io.Discard, resp.Body, webhookResponseDrainBytes)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookBody returns the body of the webhook request posting msg.
+func webhookBody(msg *model.InboundMessage) ([]byte, error) {
+	event := sse.NewRawEvent("message", msg.AccountID, msg.ConversationKey, msg.ToSSEEventData(nil))
+	body, err := json.Marshal(event.Envelope())
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook body: %w", err)
+	}
+	return body, nil
+}
+
+// signingSecrets returns the account's current secret, followed by the
+// rotated one while it is still valid.
+func (s *WebhookDeliveryService) signingSecrets(account *model.Account) ([]string, error) {
+	secret, err := s.secret(*account.WebhookSecret)
+	if err != nil {
+		return nil, err
+	}
+	secrets := []string{secret}
+	if previous := account.PreviousWebhookSecret(s.now()); previous != nil {
+		previousSecret, err := s.secret(*previous)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, previousSecret)
+	}
+	return secrets, nil
+}
+
+func (s *WebhookDeliveryService) secret(stored string) (string, error) {
+	if s.encryptionKey == "" {
+		return stored, nil
+	}
+	secret, err := util.Decrypt(s.encryptionKey, stored)
+	if err != nil {
+		return "", fmt.Errorf("decrypt webhook secret: %w", err)
+	}
+	return secret, nil
+}
+
+func (s *WebhookDeliveryService) recordFailure(ctx context.Context, msg *model.InboundMessage, deliverErr error) {
+	attempts := msg.WebhookAttempts + 1
+	errMsg := deliverErr.Error()
+	if len(errMsg) > webhookErrorMaxLen {
+		errMsg = errMsg[:webhookErrorMaxLen]
+	}
+
+	logEvent := log.Warn()
+	if attempts >= s.maxAttempts {
+		// The message stays queued, so a plugin connecting to the event
+		// stream still receives it until it expires
+		logEvent = log.Error()
+	}
+	logEvent.
+		Err(deliverErr).
+		Str("messageId", msg.ID).
+		Str("accountId", msg.AccountID).
+		Int("attempts", attempts).
+		Int("maxAttempts", s.maxAttempts).
+		Msg("webhook delivery failed")
+
+	nextAt := s.now().Add(webhookBackoff(attempts))
+	if err := s.inboundRepo.RecordWebhookFailure(ctx, msg.ID, errMsg, nextAt); err != nil {
+		log.Error().Err(err).Str("messageId", msg.ID).Msg("failed to record webhook failure")
+	}
+}
+
+// countFailure counts a failed post to the account's webhook and degrades it
+// when the failures reached webhookDegradeAfter.
+func (s *WebhookDeliveryService) countFailure(ctx context.Context, account *model.Account, postErr error) {
+	if account == nil || !account.UsesWebhook() {
+		return
+	}
+	probes := account.WebhookFailures + 1 - webhookDegradeAfter
+	updated, err := s.accountRepo.RecordWebhookFailure(ctx, account.ID, webhookDegradeAfter, s.now().Add(webhookProbeBackoff(probes)))
+	if err != nil {
+		log.Error().Err(err).Str("accountId", account.ID).Msg("failed to record webhook failure")
+		return
+	}
+	if updated == nil {
+		return
+	}
+	*account = *updated
+	if updated.WebhookDegradedAt != nil && updated.WebhookFailures == webhookDegradeAfter {
+		s.degrade(ctx, updated, postErr)
+	}
+}
+
+// degrade switches the account over to the event stream: connected plugins
+// are told about it and get the messages that were waiting for the webhook.
+func (s *WebhookDeliveryService) degrade(ctx context.Context, account *model.Account, postErr error) {
+	log.Error().
+		Err(postErr).
+		Str("accountId", account.ID).
+		Int("failures", account.WebhookFailures).
+		Time("nextProbeAt", *account.WebhookProbeAt).
+		Msg("webhook degraded, delivering messages over the event stream")
+
+	queued, err := s.inboundRepo.FindQueuedByAccountID(ctx, account.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("accountId", account.ID).Msg("failed to load messages queued for webhook")
+	}
+	s.events.DeliveryModeDegraded(ctx, account, postErr.Error(), len(queued))
+	if s.broker == nil {
+		return
+	}
+	for _, msg := range queued {
+		event := sse.NewRawEvent("message", msg.AccountID, msg.ConversationKey, msg.ToSSEEventData(nil))
+		if err := s.broker.Publish(ctx, account.ID, event); err != nil {
+			log.Warn().Err(err).Str("messageId", msg.ID).Msg("failed to publish message of degraded webhook")
+		}
+	}
+}
+
+// resetFailures clears the account's failure count after a successful post
+// and reports whether that restored a degraded webhook.
+func (s *WebhookDeliveryService) resetFailures(ctx context.Context, account *model.Account) bool {
+	updated, err := s.accountRepo.ResetWebhookFailures(ctx, account.ID)
+	if err != nil {
+		log.Error().Err(err).Str("accountId", account.ID).Msg("failed to reset webhook failures")
+		return false
+	}
+	wasDegraded := account.WebhookDegradedAt != nil
+	if updated != nil {
+		*account = *updated
+	}
+	if !wasDegraded {
+		return false
+	}
+
+	log.Info().Str("accountId", account.ID).Msg("webhook answered probe, webhook delivery restored")
+	s.events.DeliveryModeRestored(ctx, account.ID)
+	return true
+}
+
+func (s *WebhookDeliveryService) findAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("find account: %w", err)
+	}
+	if account == nil {
+		return nil, apperrors.NotFound("Account")
+	}
+	return account, nil
+}
+
+// webhookBackoff returns the delay before the next attempt after the given
+// number of failed ones.
+func webhookBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, webhookMinBackoff, webhookMaxBackoff)
+}
+
+// webhookProbeBackoff returns the delay before the next probe of a degraded
+// webhook after the given number of failed probes.
+func webhookProbeBackoff(probes int) time.Duration {
+	return exponentialBackoff(probes+1, webhookMinProbeInterval, webhookMaxProbeInterval)
+}
+
+func exponentialBackoff(attempts int, minDelay, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// SignWebhook returns the signature header value of a webhook request body
+// sent at timestamp (Unix seconds).
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookSignatures returns the signature header value with one signature
+// per secret.
+func webhookSignatures(secrets []string, timestamp int64, body []byte) string {
+	signatures := make([]string, len(secrets))
+	for i, secret := range secrets {
+		signatures[i] = SignWebhook(secret, timestamp, body)
+	}
+	return strings.Join(signatures, ",")
+}
+
+// validateWebhookURL accepts https URLs of hosts that may be public; where a
+// host name actually resolves to is checked when connecting.
+func validateWebhookURL(rawURL string) error {
+	if len(rawURL) > maxWebhookURLLen {
+		return apperrors.InvalidInput("url", fmt.Sprintf("must be at most %d characters", maxWebhookURLLen))
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return apperrors.InvalidInput("url", "must be an https URL")
+	}
+	if u.User != nil {
+		return apperrors.InvalidInput("url", "must not contain credentials")
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !isPublicAddr(addr) {
+		return apperrors.InvalidInput("url", "must not point to a private address")
+	}
+	return nil
+}
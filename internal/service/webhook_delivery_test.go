@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/openclaw/relay-server-go/internal/errors"
+	"github.com/openclaw/relay-server-go/internal/model"
+	"github.com/openclaw/relay-server-go/internal/sse"
+)
+
+func TestWebhookDeliveryService_DeliverDue(t *testing.T) {
+	type request struct {
+		delivery, signature string
+		timestamp           int64
+		body                []byte
+	}
+	var requests []request
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		requests = append(requests, request{r.Header.Get(WebhookDeliveryHeader), r.Header.Get(WebhookSignatureHeader), ts, body})
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	accountRepo := newMockAccountRepo()
+	up, down := server.URL+"/up", server.URL+"/down"
+	upSecret, downSecret := "whsec_up", "whsec_down"
+	accountRepo.accounts["acc-up"] = &model.Account{ID: "acc-up", WebhookURL: &up, WebhookSecret: &upSecret}
+	accountRepo.accounts["acc-down"] = &model.Account{ID: "acc-down", WebhookURL: &down, WebhookSecret: &downSecret}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("ClaimWebhookDue", mock.Anything, now, now.Add(webhookLease), 3, webhookBatch).Return([]model.InboundMessage{
+		{ID: "in-1", AccountID: "acc-up", ConversationKey: "ch:u1", KakaoPayload: json.RawMessage(`{}`)},
+		{ID: "in-2", AccountID: "acc-down", ConversationKey: "ch:u2", KakaoPayload: json.RawMessage(`{}`), WebhookAttempts: 1},
+		{ID: "in-3", AccountID: "acc-down", ConversationKey: "ch:u2", KakaoPayload: json.RawMessage(`{}`)},
+	}, nil)
+	inboundRepo.On("MarkDelivered", mock.Anything, "in-1").Return(nil)
+	inboundRepo.On("RecordWebhookFailure", mock.Anything, "in-2", "webhook returned status 503", now.Add(10*time.Second)).Return(nil)
+	inboundRepo.On("ReleaseWebhookLease", mock.Anything, "in-3").Return(nil)
+
+	svc := NewWebhookDeliveryService(inboundRepo, accountRepo, nil, nil, "", 3)
+	svc.client = server.Client()
+	svc.now = func() time.Time { return now }
+
+	delivered, err := svc.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	inboundRepo.AssertExpectations(t)
+
+	// The failing account's later message is released to wait for the retry
+	require.Len(t, requests, 2)
+	assert.Equal(t, "in-1", requests[0].delivery)
+	assert.Equal(t, SignWebhook(upSecret, now.Unix(), requests[0].body), requests[0].signature)
+	var envelope sse.Envelope
+	require.NoError(t, json.Unmarshal(requests[0].body, &envelope))
+	assert.Equal(t, "message", envelope.Type)
+	assert.Equal(t, "ch:u1", envelope.ConversationKey)
+	assert.Equal(t, "in-2", requests[1].delivery)
+}
+
+func TestWebhookDeliveryService_Set(t *testing.T) {
+	key := hex.EncodeToString(make([]byte, 32))
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+	svc := NewWebhookDeliveryService(new(mockInboundRepo), accountRepo, nil, nil, key, 0)
+	ctx := context.Background()
+
+	for _, raw := range []string{"http://agent.example.com/hook", "https://user:pw@agent.example.com", "https://10.0.0.1/hook", "not a url"} {
+		_, err := svc.Set(ctx, "acc-1", raw)
+		assert.Equal(t, apperrors.ErrCodeInvalidInput, apperrors.GetCode(err), raw)
+	}
+
+	settings, err := svc.Set(ctx, "acc-1", " https://agent.example.com/hook ")
+	require.NoError(t, err)
+	assert.Equal(t, "https://agent.example.com/hook", *settings.URL)
+	assert.Contains(t, settings.Secret, webhookSecretPrefix)
+
+	// The secret is stored encrypted and only returned by Set
+	stored := *accountRepo.accounts["acc-1"].WebhookSecret
+	assert.NotEqual(t, settings.Secret, stored)
+	decrypted, err := svc.secret(stored)
+	require.NoError(t, err)
+	assert.Equal(t, settings.Secret, decrypted)
+	assert.True(t, svc.Handles(ctx, "acc-1"))
+
+	got, err := svc.Get(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.Empty(t, got.Secret)
+
+	require.NoError(t, svc.Remove(ctx, "acc-1"))
+	assert.False(t, svc.Handles(ctx, "acc-1"))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, webhookBackoff(1))
+	assert.Equal(t, 40*time.Second, webhookBackoff(4))
+	assert.Equal(t, webhookMaxBackoff, webhookBackoff(20))
+}
+
+func TestWebhookDeliveryService_Failover(t *testing.T) {
+	up := false
+	var probes []sse.Envelope
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var envelope sse.Envelope
+		_ = json.NewDecoder(r.Body).Decode(&envelope)
+		if envelope.Type == WebhookProbeEvent {
+			probes = append(probes, envelope)
+		}
+		if !up {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	accountRepo := newMockAccountRepo()
+	url, secret := server.URL, "whsec_1"
+	accountRepo.accounts["acc-1"] = &model.Account{
+		ID: "acc-1", WebhookURL: &url, WebhookSecret: &secret, WebhookFailures: webhookDegradeAfter - 1,
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	queued := []model.InboundMessage{{ID: "in-1", AccountID: "acc-1", ConversationKey: "ch:u1", KakaoPayload: json.RawMessage(`{}`)}}
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("ClaimWebhookDue", mock.Anything, now, now.Add(webhookLease), DefaultWebhookMaxAttempts, webhookBatch).Return(queued, nil)
+	inboundRepo.On("RecordWebhookFailure", mock.Anything, "in-1", "webhook returned status 502", mock.Anything).Return(nil)
+	inboundRepo.On("FindQueuedByAccountID", mock.Anything, "acc-1").Return(queued, nil)
+
+	broker := sse.NewMemoryBroker(sse.BrokerOptions{})
+	defer broker.Close()
+	client := broker.Subscribe("acc-1")
+
+	svc := NewWebhookDeliveryService(inboundRepo, accountRepo, broker, NewSessionEvents(broker), "", 0)
+	svc.client = server.Client()
+	svc.now = func() time.Time { return now }
+
+	t.Run("repeated failures fall back to the event stream", func(t *testing.T) {
+		require.True(t, svc.Handles(ctx, "acc-1"))
+
+		_, err := svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.False(t, svc.Handles(ctx, "acc-1"), "new messages are published to the event stream")
+
+		event := <-client.Events
+		assert.Equal(t, EventDeliveryModeDegraded, event.Type)
+		var data DeliveryModeDegradedEvent
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		assert.Equal(t, "webhook returned status 502", data.Reason)
+		assert.Equal(t, 1, data.QueuedCount)
+		assert.Equal(t, now.Add(webhookMinProbeInterval), data.NextProbeAt)
+
+		event = <-client.Events
+		assert.Equal(t, "message", event.Type)
+		assert.Equal(t, "ch:u1", event.ConversationKey)
+
+		settings, err := svc.Get(ctx, "acc-1")
+		require.NoError(t, err)
+		assert.NotNil(t, settings.DegradedAt)
+		assert.Equal(t, now.Add(webhookMinProbeInterval), *settings.NextProbeAt)
+	})
+
+	t.Run("failed probes back off", func(t *testing.T) {
+		restored, err := svc.ProbeDegraded(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, restored)
+		assert.Empty(t, probes, "the probe is not due yet")
+
+		now = now.Add(webhookMinProbeInterval)
+		restored, err = svc.ProbeDegraded(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, restored)
+		assert.Len(t, probes, 1)
+		assert.Equal(t, now.Add(2*webhookMinProbeInterval), *accountRepo.accounts["acc-1"].WebhookProbeAt)
+		assert.False(t, svc.Handles(ctx, "acc-1"))
+	})
+
+	t.Run("a successful probe restores webhook delivery", func(t *testing.T) {
+		up = true
+		now = now.Add(2 * webhookMinProbeInterval)
+		restored, err := svc.ProbeDegraded(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, restored)
+		require.Len(t, probes, 2)
+		assert.Equal(t, "acc-1", probes[1].AccountID)
+
+		event := <-client.Events
+		assert.Equal(t, EventDeliveryModeRestored, event.Type)
+		assert.True(t, svc.Handles(ctx, "acc-1"))
+		assert.Zero(t, accountRepo.accounts["acc-1"].WebhookFailures)
+	})
+}
+
+func TestWebhookProbeBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, webhookProbeBackoff(0))
+	assert.Equal(t, 4*time.Minute, webhookProbeBackoff(2))
+	assert.Equal(t, webhookMaxProbeInterval, webhookProbeBackoff(10))
+}
+
+func TestWebhookDeliveryService_RotateSecret(t *testing.T) {
+	var signatures []string
+	var body []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get(WebhookSignatureHeader))
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	accountRepo := newMockAccountRepo()
+	accountRepo.accounts["acc-1"] = &model.Account{ID: "acc-1"}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inboundRepo := new(mockInboundRepo)
+	inboundRepo.On("ClaimWebhookDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.InboundMessage{
+		{ID: "in-1", AccountID: "acc-1", ConversationKey: "ch:u1", KakaoPayload: json.RawMessage(`{}`)},
+	}, nil)
+	inboundRepo.On("MarkDelivered", mock.Anything, "in-1").Return(nil)
+	svc := NewWebhookDeliveryService(inboundRepo, accountRepo, nil, nil, "", 0)
+	svc.client = server.Client()
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := svc.RotateSecret(ctx, "acc-1")
+	assert.Equal(t, apperrors.ErrCodeConflict, apperrors.GetCode(err), "no webhook to rotate the secret of")
+	_, err = svc.RotateSecret(ctx, "missing")
+	assert.Equal(t, apperrors.ErrCodeNotFound, apperrors.GetCode(err))
+
+	// The test server is on a loopback address, which Set rejects
+	url, original := server.URL, "whsec_original"
+	accountRepo.accounts["acc-1"].WebhookURL = &url
+	accountRepo.accounts["acc-1"].WebhookSecret = &original
+	rotated, err := svc.RotateSecret(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.NotEqual(t, original, rotated.Secret)
+	require.NotNil(t, rotated.PreviousSecretExpiresAt)
+	assert.Equal(t, now.Add(webhookSecretOverlap), *rotated.PreviousSecretExpiresAt)
+
+	got, err := svc.Get(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.Empty(t, got.Secret)
+	assert.Equal(t, rotated.PreviousSecretExpiresAt, got.PreviousSecretExpiresAt)
+
+	deliver := func() []string {
+		t.Helper()
+		signatures = nil
+		_, err := svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		require.Len(t, signatures, 1)
+		return strings.Split(signatures[0], ",")
+	}
+
+	// Both secrets sign requests during the overlap, the new one first
+	signed := deliver()
+	assert.Equal(t, []string{
+		SignWebhook(rotated.Secret, now.Unix(), body),
+		SignWebhook(original, now.Unix(), body),
+	}, signed)
+
+	now = now.Add(webhookSecretOverlap)
+	signed = deliver()
+	assert.Equal(t, []string{SignWebhook(rotated.Secret, now.Unix(), body)}, signed, "the old secret expired")
+	got, err = svc.Get(ctx, "acc-1")
+	require.NoError(t, err)
+	assert.Nil(t, got.PreviousSecretExpiresAt)
+
+	t.Run("a new URL drops the old secret", func(t *testing.T) {
+		_, err := svc.RotateSecret(ctx, "acc-1")
+		require.NoError(t, err)
+		_, err = svc.Set(ctx, "acc-1", "https://agent.example.com/hook")
+		require.NoError(t, err)
+		assert.Nil(t, accountRepo.accounts["acc-1"].WebhookPreviousSecret)
+	})
+}
+
+func TestWebhookDeliveryService_SignatureSample(t *testing.T) {
+	svc := NewWebhookDeliveryService(new(mockInboundRepo), newMockAccountRepo(), nil, nil, "", 0)
+
+	sample, err := svc.SignatureSample()
+	require.NoError(t, err)
+
+	timestamp, err := strconv.ParseInt(sample.Headers[WebhookTimestampHeader], 10, 64)
+	require.NoError(t, err)
+	body := []byte(sample.Body)
+	assert.Equal(t,
+		SignWebhook(sample.Secret, timestamp, body)+","+SignWebhook(sample.PreviousSecret, timestamp, body),
+		sample.Headers[WebhookSignatureHeader])
+
+	var envelope sse.Envelope
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, "message", envelope.Type)
+	assert.Contains(t, sample.Code, "typescript")
+	assert.Contains(t, sample.Code, "python")
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/openclaw/relay-server-go/internal/model"
+)
+
+// Example secrets of the signature sample. They only sign the sample.
+const (
+	sampleWebhookSecret         = "whsec_example_current"
+	samplePreviousWebhookSecret = "whsec_example_previous"
+)
+
+// WebhookSignatureSample is a webhook request signed the way deliveries are
+// during a secret rotation, with code that verifies it. Receivers can check
+// their verification against it before real messages arrive.
+type WebhookSignatureSample struct {
+	Secret         string            `json:"secret"`
+	PreviousSecret string            `json:"previousSecret"`
+	Headers        map[string]string `json:"headers"`
+	Body           string            `json:"body"`
+	// Verification code by language
+	Code map[string]string `json:"code"`
+}
+
+// SignatureSample builds a sample request for a text message received now.
+func (s *WebhookDeliveryService) SignatureSample() (*WebhookSignatureSample, error) {
+	now := s.now()
+	normalized, err := model.NewTextMessage(
+		model.MessageSender{UserID: "sample-user", ChannelID: "sample-channel"}, "안녕하세요", "ko",
+	).Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal sample message: %w", err)
+	}
+	msg := &model.InboundMessage{
+		ID:                "sample-delivery",
+		AccountID:         "sample-account",
+		ConversationKey:   BuildConversationKey("sample-channel", "sample-user"),
+		KakaoPayload:      json.RawMessage(`{}`),
+		NormalizedMessage: &normalized,
+		CreatedAt:         now.UTC(),
+	}
+	body, err := webhookBody(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := now.Unix()
+	return &WebhookSignatureSample{
+		Secret:         sampleWebhookSecret,
+		PreviousSecret: samplePreviousWebhookSecret,
+		Headers: map[string]string{
+			"Content-Type":         "application/json",
+			WebhookDeliveryHeader:  msg.ID,
+			WebhookTimestampHeader: strconv.FormatInt(timestamp, 10),
+			WebhookSignatureHeader: webhookSignatures([]string{sampleWebhookSecret, samplePreviousWebhookSecret}, timestamp, body),
+		},
+		Body: string(body),
+		Code: map[string]string{
+			"typescript": webhookVerifyTypeScript,
+			"python":     webhookVerifyPython,
+		},
+	}, nil
+}
+
+const webhookVerifyTypeScript = `import crypto from 'node:crypto';
+
+// rawBody is the request body as received, before any JSON parsing.
+export function verifyRelaySignature(secret: string, headers: Headers, rawBody: string, toleranceSec = 300): boolean {
+  const timestamp = headers.get('X-Relay-Timestamp') ?? '';
+  const signatures = (headers.get('X-Relay-Signature') ?? '').split(',');
+  if (Math.abs(Date.now() / 1000 - Number(timestamp)) > toleranceSec) {
+    return false;
+  }
+  const expected = Buffer.from(
+    'sha256=' + crypto.createHmac('sha256', secret).update(` + "`${timestamp}.${rawBody}`" + `).digest('hex'),
+  );
+  // During a secret rotation the request carries one signature per secret
+  return signatures.some((signature) => {
+    const actual = Buffer.from(signature.trim());
+    return actual.length === expected.length && crypto.timingSafeEqual(actual, expected);
+  });
+}
+`
+
+const webhookVerifyPython = `import hashlib
+import hmac
+import time
+
+
+def verify_relay_signature(secret: str, headers: dict, raw_body: bytes, tolerance_sec: int = 300) -> bool:
+    """raw_body is the request body as received, before any JSON parsing."""
+    timestamp = headers.get("X-Relay-Timestamp", "")
+    signatures = headers.get("X-Relay-Signature", "").split(",")
+    if not timestamp.isdigit() or abs(time.time() - int(timestamp)) > tolerance_sec:
+        return False
+    digest = hmac.new(secret.encode(), timestamp.encode() + b"." + raw_body, hashlib.sha256).hexdigest()
+    expected = "sha256=" + digest
+    # During a secret rotation the request carries one signature per secret
+    return any(hmac.compare_digest(signature.strip(), expected) for signature in signatures)
+`
@@ -0,0 +1,80 @@
+package sse
+
+// DefaultClientBufferSize is the number of events buffered per client before
+// the backpressure policy applies.
+const DefaultClientBufferSize = 100
+
+// BackpressurePolicy decides what happens when a client's buffer is full.
+type BackpressurePolicy string
+
+const (
+	// BackpressureDropOldest discards the oldest buffered event to make room
+	// and tells the client how many events it missed.
+	BackpressureDropOldest BackpressurePolicy = "drop_oldest"
+	// BackpressureDisconnect closes the stream with a cursor of the last
+	// delivered event so the client can reconnect and catch up.
+	BackpressureDisconnect BackpressurePolicy = "disconnect"
+)
+
+// EventEventsDropped is sent before the next delivered event after drops, or
+// as the last event of a stream closed by BackpressureDisconnect.
+const EventEventsDropped = "events_dropped"
+
+type EventsDroppedEvent struct {
+	Dropped int64              `json:"dropped"`
+	Policy  BackpressurePolicy `json:"policy"`
+	// ID of the last event delivered on this stream (disconnect only)
+	ResumeCursor string `json:"resumeCursor,omitempty"`
+}
+
+// BrokerOptions configures per-client buffering. Zero values use the
+// defaults. History, when set, numbers and records every published event.
+type BrokerOptions struct {
+	ClientBufferSize int
+	Policy           BackpressurePolicy
+	History          EventHistory
+	// Router, when set, assigns conversation events to one plugin session
+	Router Router
+}
+
+func (o BrokerOptions) withDefaults() BrokerOptions {
+	if o.ClientBufferSize <= 0 {
+		o.ClientBufferSize = DefaultClientBufferSize
+	}
+	if o.Policy == "" {
+		o.Policy = BackpressureDropOldest
+	}
+	return o
+}
+
+// deliver hands event to client, applying the policy when its buffer is
+// full. It reports whether the event was queued and how many events were
+// dropped.
+func (b *Broker) deliver(client *Client, event Event) (queued bool, dropped int64) {
+	select {
+	case client.Events <- event:
+		return true, 0
+	default:
+	}
+
+	if b.opts.Policy == BackpressureDisconnect {
+		client.dropped.Add(1)
+		client.overflowOnce.Do(func() { close(client.Overflow) })
+		return false, 1
+	}
+
+	select {
+	case <-client.Events:
+		dropped = 1
+	default:
+	}
+	select {
+	case client.Events <- event:
+		queued = true
+	default:
+		// Another publisher refilled the buffer first
+		dropped++
+	}
+	client.dropped.Add(dropped)
+	return queued, dropped
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -11,50 +12,128 @@ import (
 	redisclient "github.com/openclaw/relay-server-go/internal/redis"
 )
 
+// Heartbeat intervals. Clients may request an interval within the bounds;
+// the maximum stays below common proxy idle timeouts.
 const (
-	HeartbeatInterval = 30 * time.Second
+	HeartbeatInterval    = 30 * time.Second
+	MinHeartbeatInterval = 5 * time.Second
+	MaxHeartbeatInterval = 60 * time.Second
 )
 
+// WriteTimeout bounds a single write to a client. A client that stops reading
+// fails the write instead of holding its subscription open indefinitely.
+const WriteTimeout = 10 * time.Second
+
+// ClampHeartbeat returns the requested interval within the allowed bounds,
+// or HeartbeatInterval when none was requested.
+func ClampHeartbeat(requested time.Duration) time.Duration {
+	switch {
+	case requested <= 0:
+		return HeartbeatInterval
+	case requested < MinHeartbeatInterval:
+		return MinHeartbeatInterval
+	case requested > MaxHeartbeatInterval:
+		return MaxHeartbeatInterval
+	default:
+		return requested
+	}
+}
+
+// Event is an SSE event as published through Redis. Build events with
+// NewEvent so that every event carries an ID and timestamp.
 type Event struct {
-	Type string          `json:"type"`
-	Data json.RawMessage `json:"data"`
+	ID              string          `json:"id,omitempty"`
+	Type            string          `json:"type"`
+	OccurredAt      time.Time       `json:"occurredAt"`
+	AccountID       string          `json:"accountId,omitempty"`
+	ConversationKey string          `json:"conversationKey,omitempty"`
+	Data            json.RawMessage `json:"data"`
+	// Plugin session the Router assigned the conversation to; empty events
+	// go to every client of the account
+	Session string `json:"session,omitempty"`
+	// Position of the event on its channel, assigned on publish when event
+	// history is enabled; sent as the SSE event ID so clients can resume
+	Seq int64 `json:"seq,omitempty"`
 }
 
 type Client struct {
 	AccountID string
+	// Set for clients subscribed with SubscribeRouted, which only receive
+	// the events routed to their session and unrouted ones
+	SessionID string
 	Events    chan Event
 	Done      chan struct{}
+	// Overflow is closed when the client fell behind under
+	// BackpressureDisconnect; the stream should be closed.
+	Overflow chan struct{}
+
+	dropped      atomic.Int64
+	overflowOnce sync.Once
 }
 
+// TakeDropped returns the number of events dropped for the client since the
+// last call.
+func (c *Client) TakeDropped() int64 {
+	return c.dropped.Swap(0)
+}
+
+// Broker fans events out to the SSE clients of this instance. With Redis,
+// events are published through Redis pub/sub so that clients connected to any
+// instance receive them; without it (NewMemoryBroker) they are delivered
+// directly, which only reaches clients of the same process.
 type Broker struct {
-	redis   *redisclient.Client
-	clients map[string]map[*Client]bool // accountID -> set of clients
-	mu      sync.RWMutex
-	ctx     context.Context
-	cancel  context.CancelFunc
+	redis    *redisclient.Client
+	clients  map[string]map[*Client]bool // accountID -> set of clients
+	channels map[string]*channelCounters // same keys as clients
+	publish  publishCounters
+	opts     BrokerOptions
+	mu       sync.RWMutex
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
-func NewBroker(redisClient *redisclient.Client) *Broker {
+var _ EventBus = (*Broker)(nil)
+
+// NewBroker returns a Redis-backed broker.
+func NewBroker(redisClient *redisclient.Client, opts BrokerOptions) *Broker {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Broker{
-		redis:   redisClient,
-		clients: make(map[string]map[*Client]bool),
-		ctx:     ctx,
-		cancel:  cancel,
+		redis:    redisClient,
+		opts:     opts.withDefaults(),
+		clients:  make(map[string]map[*Client]bool),
+		channels: make(map[string]*channelCounters),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
+// NewMemoryBroker returns a broker for a single instance, such as tests and
+// local development.
+func NewMemoryBroker(opts BrokerOptions) *Broker {
+	return NewBroker(nil, opts)
+}
+
 func (b *Broker) Subscribe(accountID string) *Client {
+	return b.SubscribeRouted(accountID, "")
+}
+
+// SubscribeRouted subscribes a plugin session that takes part in routing.
+func (b *Broker) SubscribeRouted(accountID, sessionID string) *Client {
 	client := &Client{
 		AccountID: accountID,
-		Events:    make(chan Event, 100),
+		SessionID: sessionID,
+		Events:    make(chan Event, b.opts.ClientBufferSize),
 		Done:      make(chan struct{}),
+		Overflow:  make(chan struct{}),
 	}
 
 	b.mu.Lock()
 	if b.clients[accountID] == nil {
 		b.clients[accountID] = make(map[*Client]bool)
-		go b.subscribeToRedis(accountID)
+		b.channels[accountID] = &channelCounters{subscribedSince: time.Now()}
+		if b.redis != nil {
+			go b.subscribeToRedis(accountID)
+		}
 	}
 	b.clients[accountID][client] = true
 	clientCount := len(b.clients[accountID])
@@ -78,6 +157,7 @@ func (b *Broker) Unsubscribe(client *Client) {
 
 		if len(clients) == 0 {
 			delete(b.clients, client.AccountID)
+			delete(b.channels, client.AccountID)
 		}
 
 		log.Info().
@@ -88,6 +168,37 @@ func (b *Broker) Unsubscribe(client *Client) {
 }
 
 func (b *Broker) Publish(ctx context.Context, accountID string, event Event) error {
+	if b.opts.Router != nil && event.ConversationKey != "" && isAccountChannel(accountID) {
+		event.Session = b.opts.Router.Route(ctx, accountID, event.ConversationKey)
+	}
+
+	if b.opts.History != nil {
+		seq, err := b.opts.History.NextSeq(ctx, accountID)
+		if err != nil {
+			// Published without a sequence number, the event cannot be
+			// resumed from but is still delivered live
+			log.Warn().Err(err).Str("accountId", accountID).Str("type", event.Type).Msg("failed to assign event sequence")
+		}
+		event.Seq = seq
+	}
+
+	start := time.Now()
+	err := b.publishEvent(ctx, accountID, event)
+	b.publish.record(time.Since(start), err)
+	if err == nil && b.opts.History != nil {
+		if err := b.opts.History.Append(ctx, accountID, event); err != nil {
+			log.Warn().Err(err).Str("accountId", accountID).Str("type", event.Type).Msg("failed to record event history")
+		}
+	}
+	return err
+}
+
+func (b *Broker) publishEvent(ctx context.Context, accountID string, event Event) error {
+	if b.redis == nil {
+		b.broadcast(accountID, event)
+		return nil
+	}
+
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
@@ -132,15 +243,25 @@ func (b *Broker) subscribeToRedis(accountID string) {
 
 func (b *Broker) broadcast(accountID string, event Event) {
 	b.mu.RLock()
+	defer b.mu.RUnlock()
 	clients := b.clients[accountID]
-	b.mu.RUnlock()
+	counters := b.channels[accountID]
 
 	for client := range clients {
-		select {
-		case client.Events <- event:
-		default:
+		if event.Session != "" && client.SessionID != "" && client.SessionID != event.Session {
+			continue
+		}
+		queued, dropped := b.deliver(client, event)
+		if queued {
+			counters.delivered.Add(1)
+		}
+		if dropped > 0 {
+			counters.dropped.Add(dropped)
+			b.publish.dropped.Add(dropped)
 			log.Warn().
 				Str("accountId", accountID).
+				Str("policy", string(b.opts.Policy)).
+				Int64("dropped", dropped).
 				Msg("client event buffer full, dropping event")
 		}
 	}
@@ -158,6 +279,7 @@ func (b *Broker) Close() {
 		}
 	}
 	b.clients = make(map[string]map[*Client]bool)
+	b.channels = make(map[string]*channelCounters)
 }
 
 func (b *Broker) ClientCount(accountID string) int {
@@ -0,0 +1,232 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers to subscribers of the channel", func(t *testing.T) {
+		broker := NewMemoryBroker(BrokerOptions{})
+		defer broker.Close()
+
+		first := broker.Subscribe("acc-1")
+		second := broker.Subscribe("acc-1")
+		other := broker.Subscribe("acc-2")
+
+		event, err := NewEvent("message", "acc-1", "conv-1", map[string]string{"text": "hi"})
+		require.NoError(t, err)
+		require.NoError(t, broker.Publish(ctx, "acc-1", event))
+
+		assert.Equal(t, event, <-first.Events)
+		assert.Equal(t, event, <-second.Events)
+		assert.Empty(t, other.Events)
+	})
+
+	t.Run("stops delivering after unsubscribe", func(t *testing.T) {
+		broker := NewMemoryBroker(BrokerOptions{})
+		defer broker.Close()
+
+		client := broker.Subscribe("acc-1")
+		broker.Unsubscribe(client)
+
+		require.NoError(t, broker.Publish(ctx, "acc-1", NewRawEvent("message", "acc-1", "", nil)))
+		assert.Empty(t, client.Events)
+		assert.Equal(t, 0, broker.ClientCount("acc-1"))
+	})
+
+	t.Run("routes conversation events to one session", func(t *testing.T) {
+		broker := NewMemoryBroker(BrokerOptions{Router: routeTo{"conv-1": "s1"}})
+		defer broker.Close()
+
+		s1 := broker.SubscribeRouted("acc-1", "s1")
+		s2 := broker.SubscribeRouted("acc-1", "s2")
+		unrouted := broker.Subscribe("acc-1")
+
+		require.NoError(t, broker.Publish(ctx, "acc-1", NewRawEvent("message", "acc-1", "conv-1", nil)))
+		assert.Equal(t, "s1", (<-s1.Events).Session)
+		assert.Empty(t, s2.Events)
+		assert.Len(t, unrouted.Events, 1)
+
+		// Events no session owns reach everyone
+		require.NoError(t, broker.Publish(ctx, "acc-1", NewRawEvent("message", "acc-1", "conv-2", nil)))
+		assert.Len(t, s1.Events, 1)
+		assert.Len(t, s2.Events, 1)
+	})
+}
+
+type routeTo map[string]string
+
+func (r routeTo) Route(ctx context.Context, accountID, conversationKey string) string {
+	return r[conversationKey]
+}
+
+func TestBrokerStats(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker(BrokerOptions{})
+	defer broker.Close()
+
+	client := broker.Subscribe("acc-1")
+	broker.Subscribe("acc-1")
+	broker.Subscribe("session:s1")
+
+	// Fill the first client's buffer so the next event evicts its oldest one
+	for i := 0; i < cap(client.Events); i++ {
+		client.Events <- Event{}
+	}
+	require.NoError(t, broker.Publish(ctx, "acc-1", NewRawEvent("message", "acc-1", "", nil)))
+
+	stats := broker.Stats()
+	assert.Equal(t, 3, stats.TotalClients)
+	require.Len(t, stats.Channels, 2)
+
+	acc := stats.Channels[0]
+	assert.Equal(t, "acc-1", acc.Channel)
+	assert.Equal(t, 2, acc.Subscribers)
+	assert.Equal(t, int64(2), acc.Delivered)
+	assert.Equal(t, int64(1), acc.Dropped)
+	assert.False(t, acc.SubscribedSince.IsZero())
+	assert.Equal(t, "session:s1", stats.Channels[1].Channel)
+
+	assert.Equal(t, int64(1), stats.DroppedTotal)
+	assert.Equal(t, int64(1), stats.Publish.Count)
+	assert.Equal(t, int64(0), stats.Publish.Errors)
+
+	broker.Unsubscribe(client)
+	assert.Equal(t, int64(1), broker.Stats().DroppedTotal)
+}
+
+func TestBrokerBackpressure(t *testing.T) {
+	ctx := context.Background()
+	publish := func(broker *Broker, id string) {
+		event := NewRawEvent("message", "acc-1", "", nil)
+		event.ID = id
+		require.NoError(t, broker.Publish(ctx, "acc-1", event))
+	}
+
+	t.Run("drop oldest keeps the newest events", func(t *testing.T) {
+		broker := NewMemoryBroker(BrokerOptions{ClientBufferSize: 2})
+		defer broker.Close()
+		client := broker.Subscribe("acc-1")
+
+		publish(broker, "e1")
+		publish(broker, "e2")
+		publish(broker, "e3")
+
+		assert.Equal(t, "e2", (<-client.Events).ID)
+		assert.Equal(t, "e3", (<-client.Events).ID)
+		assert.Equal(t, int64(1), client.TakeDropped())
+		assert.Equal(t, int64(0), client.TakeDropped())
+		select {
+		case <-client.Overflow:
+			t.Fatal("drop oldest must not signal overflow")
+		default:
+		}
+	})
+
+	t.Run("disconnect signals overflow and keeps buffered events", func(t *testing.T) {
+		broker := NewMemoryBroker(BrokerOptions{ClientBufferSize: 1, Policy: BackpressureDisconnect})
+		defer broker.Close()
+		client := broker.Subscribe("acc-1")
+
+		publish(broker, "e1")
+		publish(broker, "e2")
+		publish(broker, "e3")
+
+		<-client.Overflow
+		assert.Equal(t, "e1", (<-client.Events).ID)
+		assert.Equal(t, int64(2), client.TakeDropped())
+	})
+}
+
+// quietLogs disables logging for a benchmark; a subscriber that falls behind
+// logs every dropped event.
+func quietLogs(b *testing.B) {
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	b.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+}
+
+func BenchmarkMemoryBroker_Publish(b *testing.B) {
+	quietLogs(b)
+	ctx := context.Background()
+	event, err := NewEvent("message", "acc-1", "conv-1", map[string]string{"text": "hi"})
+	require.NoError(b, err)
+
+	for _, subscribers := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("subscribers=%d", subscribers), func(b *testing.B) {
+			broker := NewMemoryBroker(BrokerOptions{})
+
+			var wg sync.WaitGroup
+			for range subscribers {
+				client := broker.Subscribe("acc-1")
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-client.Events:
+						case <-client.Done:
+							return
+						}
+					}
+				}()
+			}
+
+			for b.Loop() {
+				if err := broker.Publish(ctx, "acc-1", event); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+			broker.Close()
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkMemoryBroker_PublishParallel(b *testing.B) {
+	quietLogs(b)
+	ctx := context.Background()
+	broker := NewMemoryBroker(BrokerOptions{})
+	defer broker.Close()
+
+	// One draining subscriber on each of many accounts, as with many
+	// connected plugins
+	const accounts = 64
+	for i := range accounts {
+		client := broker.Subscribe(fmt.Sprintf("acc-%d", i))
+		go func() {
+			for {
+				select {
+				case <-client.Events:
+				case <-client.Done:
+					return
+				}
+			}
+		}()
+	}
+
+	var next atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		accountID := fmt.Sprintf("acc-%d", next.Add(1)%accounts)
+		event := NewRawEvent("message", accountID, "conv-1", json.RawMessage(`{"text":"hi"}`))
+		for pb.Next() {
+			if err := broker.Publish(ctx, accountID, event); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
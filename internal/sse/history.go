@@ -0,0 +1,222 @@
+package sse
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	redisclient "github.com/openclaw/relay-server-go/internal/redis"
+)
+
+// ErrHistoryCursorExpired is returned for an event ID cursor that is no longer
+// in the history; the client has to resynchronize from the message queue.
+var ErrHistoryCursorExpired = errors.New("event history cursor expired")
+
+// EventResumeExpired is sent to a client that reconnected with a
+// Last-Event-ID whose following events are no longer kept; it has to
+// resynchronize, e.g. from GET /v1/events/history with a time.
+const EventResumeExpired = "resume_expired"
+
+type ResumeExpiredEvent struct {
+	LastEventID string `json:"lastEventId"`
+}
+
+// HistoryCursor selects events after a sequence number, after an event ID,
+// or after a point in time, whichever is set first.
+type HistoryCursor struct {
+	Seq     int64
+	EventID string
+	Time    time.Time
+}
+
+// EventHistory numbers the events published to a channel and keeps the
+// recent ones, so that plugins can catch up after downtime and resume their
+// stream. Channels are account IDs or "session:<id>" for pending sessions.
+type EventHistory interface {
+	// NextSeq returns the sequence number of the channel's next event;
+	// numbers increase by one per event.
+	NextSeq(ctx context.Context, channel string) (int64, error)
+	Append(ctx context.Context, channel string, event Event) error
+	// Since returns up to limit events after cursor, oldest first, and
+	// whether more follow. A sequence cursor fails with
+	// ErrHistoryCursorExpired when events after it are no longer kept.
+	Since(ctx context.Context, channel string, cursor HistoryCursor, limit int) ([]Event, bool, error)
+}
+
+// isAccountChannel reports whether channel is an account's rather than a
+// pending session's.
+func isAccountChannel(channel string) bool {
+	return !strings.HasPrefix(channel, "session:")
+}
+
+// eventsSince filters events (oldest first) by cursor and limit. latest is
+// the channel's last assigned sequence number.
+func eventsSince(events []Event, cursor HistoryCursor, limit int, latest int64) ([]Event, bool, error) {
+	start := 0
+	if cursor.Seq > 0 {
+		var err error
+		if events, err = eventsAfterSeq(events, cursor.Seq, latest); err != nil {
+			return nil, false, err
+		}
+	} else if cursor.EventID != "" {
+		start = -1
+		for i, event := range events {
+			if event.ID == cursor.EventID {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, false, ErrHistoryCursorExpired
+		}
+	} else {
+		for start < len(events) && !events[start].OccurredAt.After(cursor.Time) {
+			start++
+		}
+	}
+
+	result := events[start:]
+	hasMore := false
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+		hasMore = true
+	}
+	return result, hasMore, nil
+}
+
+// eventsAfterSeq returns the events after seq in sequence order, or
+// ErrHistoryCursorExpired when some of them were evicted or the sequence
+// started over.
+func eventsAfterSeq(events []Event, seq, latest int64) ([]Event, error) {
+	if seq > latest {
+		return nil, ErrHistoryCursorExpired
+	}
+	var result []Event
+	for _, event := range events {
+		if event.Seq > seq {
+			result = append(result, event)
+		}
+	}
+	// Concurrent publishers may append slightly out of order
+	slices.SortFunc(result, func(a, b Event) int { return cmp.Compare(a.Seq, b.Seq) })
+	if latest > seq && (len(result) == 0 || result[0].Seq != seq+1) {
+		return nil, ErrHistoryCursorExpired
+	}
+	return result, nil
+}
+
+type redisEventHistory struct {
+	client *redisclient.Client
+	size   int
+	ttl    time.Duration
+}
+
+// NewRedisEventHistory keeps the last size events per account in a Redis
+// list that expires ttl after the last append.
+func NewRedisEventHistory(client *redisclient.Client, size int, ttl time.Duration) EventHistory {
+	return &redisEventHistory{client: client, size: size, ttl: ttl}
+}
+
+func eventHistoryKey(channel string) string {
+	return fmt.Sprintf("event_history:%s", channel)
+}
+
+func eventSeqKey(channel string) string {
+	return fmt.Sprintf("event_seq:%s", channel)
+}
+
+// NextSeq increments the channel's counter. Account counters are kept for
+// good so that numbers never repeat; those of pending sessions expire with
+// their history.
+func (h *redisEventHistory) NextSeq(ctx context.Context, channel string) (int64, error) {
+	pipe := h.client.TxPipeline()
+	incr := pipe.Incr(ctx, eventSeqKey(channel))
+	if !isAccountChannel(channel) {
+		pipe.Expire(ctx, eventSeqKey(channel), h.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (h *redisEventHistory) Append(ctx context.Context, channel string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	key := eventHistoryKey(channel)
+	pipe := h.client.TxPipeline()
+	pipe.RPush(ctx, key, data)
+	pipe.LTrim(ctx, key, int64(-h.size), -1)
+	pipe.Expire(ctx, key, h.ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (h *redisEventHistory) Since(ctx context.Context, channel string, cursor HistoryCursor, limit int) ([]Event, bool, error) {
+	pipe := h.client.Pipeline()
+	list := pipe.LRange(ctx, eventHistoryKey(channel), 0, -1)
+	seq := pipe.Get(ctx, eventSeqKey(channel))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, false, err
+	}
+	items := list.Val()
+	latest, _ := seq.Int64()
+	events := make([]Event, 0, len(items))
+	for _, item := range items {
+		var event Event
+		if err := json.Unmarshal([]byte(item), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return eventsSince(events, cursor, limit, latest)
+}
+
+type memoryEventHistory struct {
+	mu     sync.Mutex
+	size   int
+	events map[string][]Event
+	seqs   map[string]int64
+}
+
+// NewMemoryEventHistory keeps the last size events per account in process
+// memory, for use with NewMemoryBroker.
+func NewMemoryEventHistory(size int) EventHistory {
+	return &memoryEventHistory{size: size, events: make(map[string][]Event), seqs: make(map[string]int64)}
+}
+
+func (h *memoryEventHistory) NextSeq(_ context.Context, channel string) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seqs[channel]++
+	return h.seqs[channel], nil
+}
+
+func (h *memoryEventHistory) Append(_ context.Context, channel string, event Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	events := append(h.events[channel], event)
+	if len(events) > h.size {
+		events = events[len(events)-h.size:]
+	}
+	h.events[channel] = events
+	return nil
+}
+
+func (h *memoryEventHistory) Since(_ context.Context, channel string, cursor HistoryCursor, limit int) ([]Event, bool, error) {
+	h.mu.Lock()
+	events := append([]Event(nil), h.events[channel]...)
+	latest := h.seqs[channel]
+	h.mu.Unlock()
+	return eventsSince(events, cursor, limit, latest)
+}
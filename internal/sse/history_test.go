@@ -0,0 +1,132 @@
+package sse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryEventHistory(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := NewMemoryEventHistory(3)
+	for i, id := range []string{"evt_1", "evt_2", "evt_3", "evt_4"} {
+		require.NoError(t, history.Append(ctx, "acc-1", Event{ID: id, OccurredAt: base.Add(time.Duration(i) * time.Minute)}))
+	}
+
+	ids := func(events []Event) []string {
+		result := make([]string, len(events))
+		for i, e := range events {
+			result[i] = e.ID
+		}
+		return result
+	}
+
+	t.Run("keeps the most recent events", func(t *testing.T) {
+		events, hasMore, err := history.Since(ctx, "acc-1", HistoryCursor{}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_2", "evt_3", "evt_4"}, ids(events))
+		assert.False(t, hasMore)
+	})
+
+	t.Run("returns events after an event ID", func(t *testing.T) {
+		events, _, err := history.Since(ctx, "acc-1", HistoryCursor{EventID: "evt_2"}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_3", "evt_4"}, ids(events))
+	})
+
+	t.Run("returns events after a time", func(t *testing.T) {
+		events, _, err := history.Since(ctx, "acc-1", HistoryCursor{Time: base.Add(2 * time.Minute)}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_4"}, ids(events))
+	})
+
+	t.Run("limits and reports more", func(t *testing.T) {
+		events, hasMore, err := history.Since(ctx, "acc-1", HistoryCursor{}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"evt_2", "evt_3"}, ids(events))
+		assert.True(t, hasMore)
+	})
+
+	t.Run("rejects an evicted event ID", func(t *testing.T) {
+		_, _, err := history.Since(ctx, "acc-1", HistoryCursor{EventID: "evt_1"}, 10)
+		assert.ErrorIs(t, err, ErrHistoryCursorExpired)
+	})
+}
+
+func TestMemoryEventHistory_SeqCursor(t *testing.T) {
+	ctx := context.Background()
+	history := NewMemoryEventHistory(3)
+	publish := func(channel string) int64 {
+		seq, err := history.NextSeq(ctx, channel)
+		require.NoError(t, err)
+		require.NoError(t, history.Append(ctx, channel, Event{ID: "evt", Seq: seq}))
+		return seq
+	}
+	for range 5 {
+		publish("acc-1")
+	}
+
+	seqs := func(events []Event) []int64 {
+		result := make([]int64, len(events))
+		for i, e := range events {
+			result[i] = e.Seq
+		}
+		return result
+	}
+
+	t.Run("returns events after the sequence number", func(t *testing.T) {
+		events, _, err := history.Since(ctx, "acc-1", HistoryCursor{Seq: 3}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{4, 5}, seqs(events))
+	})
+
+	t.Run("returns nothing when caught up", func(t *testing.T) {
+		events, _, err := history.Since(ctx, "acc-1", HistoryCursor{Seq: 5}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("rejects a cursor whose next events were evicted", func(t *testing.T) {
+		_, _, err := history.Since(ctx, "acc-1", HistoryCursor{Seq: 1}, 0)
+		assert.ErrorIs(t, err, ErrHistoryCursorExpired)
+	})
+
+	t.Run("rejects a cursor ahead of the sequence", func(t *testing.T) {
+		_, _, err := history.Since(ctx, "acc-1", HistoryCursor{Seq: 9}, 0)
+		assert.ErrorIs(t, err, ErrHistoryCursorExpired)
+	})
+
+	t.Run("numbers channels separately", func(t *testing.T) {
+		assert.Equal(t, int64(1), publish("session:s1"))
+	})
+}
+
+func TestBrokerRecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	history := NewMemoryEventHistory(10)
+	broker := NewMemoryBroker(BrokerOptions{History: history})
+	defer broker.Close()
+
+	client := broker.Subscribe("acc-1")
+	defer broker.Unsubscribe(client)
+
+	require.NoError(t, broker.Publish(ctx, "acc-1", NewRawEvent("message", "acc-1", "", nil)))
+	require.NoError(t, broker.Publish(ctx, "acc-1", NewRawEvent("message", "acc-1", "", nil)))
+	require.NoError(t, broker.Publish(ctx, "session:s1", NewRawEvent("pairing_expired", "", "", nil)))
+
+	// Live events carry the sequence number they were recorded with
+	assert.Equal(t, int64(1), (<-client.Events).Seq)
+	assert.Equal(t, int64(2), (<-client.Events).Seq)
+
+	events, _, err := history.Since(ctx, "acc-1", HistoryCursor{}, 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+	events, _, err = history.Since(ctx, "session:s1", HistoryCursor{}, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(1), events[0].Seq)
+}
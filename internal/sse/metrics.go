@@ -0,0 +1,100 @@
+package sse
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// ChannelStats describes one channel with connected clients on this instance.
+type ChannelStats struct {
+	Channel         string    `json:"channel"`
+	Subscribers     int       `json:"subscribers"`
+	Delivered       int64     `json:"delivered"`
+	Dropped         int64     `json:"dropped"`
+	SubscribedSince time.Time `json:"subscribedSince"`
+}
+
+// PublishStats summarizes Publish calls since process start. Latency is the
+// time to hand the event to Redis (or to local clients without Redis).
+type PublishStats struct {
+	Count        int64   `json:"count"`
+	Errors       int64   `json:"errors"`
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+	MaxLatencyMs float64 `json:"maxLatencyMs"`
+}
+
+// BrokerStats is a snapshot of the broker for this instance.
+type BrokerStats struct {
+	Channels     []ChannelStats `json:"channels"`
+	TotalClients int            `json:"totalClients"`
+	// Events dropped because a client buffer was full, including channels
+	// that have since disconnected
+	DroppedTotal int64        `json:"droppedTotal"`
+	Publish      PublishStats `json:"publish"`
+}
+
+// channelCounters lives as long as the channel has clients.
+type channelCounters struct {
+	delivered       atomic.Int64
+	dropped         atomic.Int64
+	subscribedSince time.Time
+}
+
+type publishCounters struct {
+	count   atomic.Int64
+	errors  atomic.Int64
+	totalNs atomic.Int64
+	maxNs   atomic.Int64
+	dropped atomic.Int64
+}
+
+func (p *publishCounters) record(elapsed time.Duration, err error) {
+	p.count.Add(1)
+	if err != nil {
+		p.errors.Add(1)
+	}
+	ns := elapsed.Nanoseconds()
+	p.totalNs.Add(ns)
+	for {
+		current := p.maxNs.Load()
+		if ns <= current || p.maxNs.CompareAndSwap(current, ns) {
+			return
+		}
+	}
+}
+
+// Stats returns per-channel counters sorted by channel, and publish metrics.
+func (b *Broker) Stats() BrokerStats {
+	b.mu.RLock()
+	channels := make([]ChannelStats, 0, len(b.clients))
+	total := 0
+	for channel, clients := range b.clients {
+		stats := ChannelStats{Channel: channel, Subscribers: len(clients)}
+		if c := b.channels[channel]; c != nil {
+			stats.Delivered = c.delivered.Load()
+			stats.Dropped = c.dropped.Load()
+			stats.SubscribedSince = c.subscribedSince
+		}
+		channels = append(channels, stats)
+		total += len(clients)
+	}
+	b.mu.RUnlock()
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Channel < channels[j].Channel })
+
+	publish := PublishStats{
+		Count:        b.publish.count.Load(),
+		Errors:       b.publish.errors.Load(),
+		MaxLatencyMs: float64(b.publish.maxNs.Load()) / float64(time.Millisecond),
+	}
+	if publish.Count > 0 {
+		publish.AvgLatencyMs = float64(b.publish.totalNs.Load()) / float64(publish.Count) / float64(time.Millisecond)
+	}
+
+	return BrokerStats{
+		Channels:     channels,
+		TotalClients: total,
+		DroppedTotal: b.publish.dropped.Load(),
+		Publish:      publish,
+	}
+}
@@ -0,0 +1,31 @@
+package sse
+
+import "context"
+
+// EventPublisher sends an event to everyone subscribed to a channel. The
+// channel is an account ID, or "session:<id>" for pending plugin sessions.
+type EventPublisher interface {
+	Publish(ctx context.Context, channel string, event Event) error
+}
+
+// EventSubscriber delivers a channel's events to a Client until it is
+// unsubscribed.
+type EventSubscriber interface {
+	Subscribe(channel string) *Client
+	// SubscribeRouted subscribes a plugin session that only receives the
+	// conversations routed to it.
+	SubscribeRouted(channel, sessionID string) *Client
+	Unsubscribe(client *Client)
+}
+
+// Router picks the plugin session that receives a conversation's events
+// when an account runs several. It returns "" to send them to every session.
+type Router interface {
+	Route(ctx context.Context, accountID, conversationKey string) string
+}
+
+// EventBus publishes and subscribes; Broker implements it.
+type EventBus interface {
+	EventPublisher
+	EventSubscriber
+}
@@ -0,0 +1,130 @@
+package sse
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SignatureAlgorithm is the JWS algorithm of event signatures.
+const SignatureAlgorithm = "EdDSA"
+
+// signatureMember is how the signature is appended to a signed envelope. It
+// is always the last member, so removing it yields the signed bytes.
+const signatureMember = `,"signature":"`
+
+// Signer signs v2 event envelopes with an Ed25519 key so that plugins relaying
+// events further can prove they came from this relay. The signature is a
+// detached compact JWS ("<header>..<signature>") over the envelope JSON as
+// sent, without its signature member.
+type Signer struct {
+	key ed25519.PrivateKey
+	kid string
+}
+
+// NewSigner derives the signing key from secret, so a deployment can keep its
+// key as an ordinary secret string.
+func NewSigner(secret string) *Signer {
+	seed := sha256.Sum256([]byte("sse-signing:" + secret))
+	key := ed25519.NewKeyFromSeed(seed[:])
+	s := &Signer{key: key}
+	s.kid = s.thumbprint()
+	return s
+}
+
+// KeyID returns the JWK thumbprint (RFC 7638) of the verification key.
+func (s *Signer) KeyID() string {
+	return s.kid
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+}
+
+// JWKSet is the body of the JWKS endpoint.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the verification key set.
+func (s *Signer) JWKS() JWKSet {
+	return JWKSet{Keys: []JWK{{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         b64(s.key.Public().(ed25519.PublicKey)),
+		KeyID:     s.kid,
+		Algorithm: SignatureAlgorithm,
+		Use:       "sig",
+	}}}
+}
+
+func (s *Signer) thumbprint() string {
+	// Required members in lexicographic order, without whitespace
+	pub := b64(s.key.Public().(ed25519.PublicKey))
+	sum := sha256.Sum256([]byte(`{"crv":"Ed25519","kty":"OKP","x":"` + pub + `"}`))
+	return b64(sum[:])
+}
+
+// Sign returns the detached JWS of payload.
+func (s *Signer) Sign(payload []byte) string {
+	header := b64([]byte(`{"alg":"` + SignatureAlgorithm + `","kid":"` + s.kid + `"}`))
+	signingInput := header + "." + b64(payload)
+	return header + ".." + b64(ed25519.Sign(s.key, []byte(signingInput)))
+}
+
+// SignEnvelope marshals e and appends its signature as the last member.
+func (s *Signer) SignEnvelope(e Envelope) ([]byte, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s envelope: %w", e.Type, err)
+	}
+	signed := make([]byte, 0, len(payload)+len(signatureMember)+160)
+	signed = append(signed, payload[:len(payload)-1]...)
+	signed = append(signed, signatureMember...)
+	signed = append(signed, s.Sign(payload)...)
+	signed = append(signed, `"}`...)
+	return signed, nil
+}
+
+// VerifyEnvelope checks a signed envelope against the public key, the way a
+// plugin does with the key from the JWKS endpoint.
+func VerifyEnvelope(pub ed25519.PublicKey, signed []byte) bool {
+	i := bytes.LastIndex(signed, []byte(signatureMember))
+	if i < 0 || !bytes.HasSuffix(signed, []byte(`"}`)) {
+		return false
+	}
+	payload := append(append([]byte{}, signed[:i]...), '}')
+	jws := string(signed[i+len(signatureMember) : len(signed)-2])
+
+	header, sig, ok := splitDetachedJWS(jws)
+	if !ok {
+		return false
+	}
+	rawSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, []byte(header+"."+b64(payload)), rawSig)
+}
+
+func splitDetachedJWS(jws string) (header, sig string, ok bool) {
+	header, sig, found := strings.Cut(jws, "..")
+	if !found || header == "" || sig == "" {
+		return "", "", false
+	}
+	return header, sig, true
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,71 @@
+package sse
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publicKey(t *testing.T, s *Signer) ed25519.PublicKey {
+	t.Helper()
+	keys := s.JWKS().Keys
+	require.Len(t, keys, 1)
+	pub, err := base64.RawURLEncoding.DecodeString(keys[0].X)
+	require.NoError(t, err)
+	return ed25519.PublicKey(pub)
+}
+
+func TestSigner_SignEnvelope(t *testing.T) {
+	signer := NewSigner("a-long-enough-deployment-secret")
+	event := NewRawEvent("message", "acc-1", "conv-1", json.RawMessage(`{"text":"hi"}`))
+
+	signed, err := signer.SignEnvelope(event.Envelope())
+	require.NoError(t, err)
+
+	t.Run("stays a valid envelope with a signature member", func(t *testing.T) {
+		var got struct {
+			Envelope
+			Signature string `json:"signature"`
+		}
+		require.NoError(t, json.Unmarshal(signed, &got))
+		assert.Equal(t, event.ID, got.ID)
+		assert.Contains(t, got.Signature, "..")
+	})
+
+	t.Run("verifies with the published key", func(t *testing.T) {
+		assert.True(t, VerifyEnvelope(publicKey(t, signer), signed))
+	})
+
+	t.Run("rejects a tampered envelope", func(t *testing.T) {
+		tampered := bytes.Replace(signed, []byte(`"hi"`), []byte(`"ho"`), 1)
+		assert.False(t, VerifyEnvelope(publicKey(t, signer), tampered))
+	})
+
+	t.Run("rejects another deployment's key", func(t *testing.T) {
+		other := NewSigner("another-deployment-secret-value")
+		assert.False(t, VerifyEnvelope(publicKey(t, other), signed))
+	})
+
+	t.Run("rejects an unsigned envelope", func(t *testing.T) {
+		unsigned, err := json.Marshal(event.Envelope())
+		require.NoError(t, err)
+		assert.False(t, VerifyEnvelope(publicKey(t, signer), unsigned))
+	})
+}
+
+func TestSigner_KeyID(t *testing.T) {
+	a := NewSigner("deployment-secret-one")
+	assert.Equal(t, a.KeyID(), NewSigner("deployment-secret-one").KeyID(), "key is derived from the secret")
+	assert.NotEqual(t, a.KeyID(), NewSigner("deployment-secret-two").KeyID())
+
+	jwk := a.JWKS().Keys[0]
+	assert.Equal(t, "OKP", jwk.KeyType)
+	assert.Equal(t, "Ed25519", jwk.Curve)
+	assert.Equal(t, SignatureAlgorithm, jwk.Algorithm)
+	assert.Equal(t, a.KeyID(), jwk.KeyID)
+}
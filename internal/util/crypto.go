@@ -12,6 +12,9 @@ import (
 
 const tokenBytes = 32
 
+// passwordHashCost matches scripts/hash-password.go
+const passwordHashCost = 12
+
 func GenerateToken() (string, error) {
 	bytes := make([]byte, tokenBytes)
 	if _, err := rand.Read(bytes); err != nil {
@@ -35,6 +38,14 @@ func ConstantTimeEqual(a, b string) bool {
 	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
 func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
@@ -95,3 +95,12 @@ func TestConstantTimeEqual(t *testing.T) {
 		assert.True(t, ConstantTimeEqual("", ""))
 	})
 }
+
+func TestHashPassword(t *testing.T) {
+	t.Run("hash verifies with CheckPasswordHash", func(t *testing.T) {
+		hash, err := HashPassword("correct horse battery")
+		assert.NoError(t, err)
+		assert.True(t, CheckPasswordHash("correct horse battery", hash))
+		assert.False(t, CheckPasswordHash("wrong", hash))
+	})
+}
@@ -6,6 +6,9 @@ import (
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
+// Kakao channel public profile IDs look like "_xkAbC"
+var kakaoChannelIDRegex = regexp.MustCompile(`^_[A-Za-z0-9]+$`)
+
 func IsValidUUID(s string) bool {
 	if s == "" {
 		return false
@@ -13,6 +16,10 @@ func IsValidUUID(s string) bool {
 	return uuidRegex.MatchString(s)
 }
 
+func IsValidKakaoChannelID(s string) bool {
+	return kakaoChannelIDRegex.MatchString(s)
+}
+
 func IsValidEnum(value string, validValues []string) bool {
 	if value == "" {
 		return true
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Version
+	}{
+		{"1.2.3", Version{1, 2, 3}},
+		{"v1.2.3", Version{1, 2, 3}},
+		{"1.2", Version{1, 2, 0}},
+		{"2", Version{2, 0, 0}},
+		{"1.4.0-beta.2", Version{1, 4, 0}},
+		{"1.4.0+build.7", Version{1, 4, 0}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseVersion(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+
+	for _, invalid := range []string{"", "v", "1.x", "1.2.3.4", "-1.0.0", "latest"} {
+		t.Run("rejects "+invalid, func(t *testing.T) {
+			_, err := ParseVersion(invalid)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := func(s string) Version {
+		parsed, err := ParseVersion(s)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	assert.True(t, v("1.2.3").Less(v("1.2.4")))
+	assert.True(t, v("1.2.3").Less(v("1.10.0")))
+	assert.True(t, v("1.9.9").Less(v("2.0.0")))
+	assert.False(t, v("1.2.3").Less(v("1.2.3")))
+	assert.Equal(t, 0, v("v1.2").Compare(v("1.2.0")))
+	assert.Equal(t, 1, v("3.0.0").Compare(v("2.9.9")))
+	assert.Equal(t, "1.2.0", v("1.2").String())
+}
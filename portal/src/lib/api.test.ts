@@ -1,5 +1,5 @@
 import { describe, test, expect, beforeEach, mock } from 'bun:test';
-import { api } from './api';
+import { api, ApiRequestError } from './api';
 
 // Mock fetch globally
 const mockFetch = mock(() => Promise.resolve(new Response()));
@@ -49,6 +49,26 @@ describe('Portal API', () => {
     });
   });
 
+  describe('getDashboard', () => {
+    test('should call /portal/api/dashboard', async () => {
+      const mockDashboard = {
+        user: { id: '1', email: 'test@example.com', accountId: 'acc1', createdAt: '2024-01-01T00:00:00Z' },
+        connections: { total: 2, paired: 1, blocked: 0, queued: 3, recentFailures: 0, consumerConnected: true },
+        recentMessages: [],
+      };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify(mockDashboard), { status: 200 })
+      );
+
+      const result = await api.getDashboard();
+
+      expect(mockFetch).toHaveBeenCalledTimes(1);
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/dashboard');
+      expect(result).toEqual(mockDashboard);
+    });
+  });
+
   describe('generatePairingCode', () => {
     test('should call /portal/api/pairing/generate', async () => {
       const mockCode = { code: 'ABC123', expiresAt: '2024-01-01T00:00:00Z' };
@@ -78,6 +98,38 @@ describe('Portal API', () => {
     });
   });
 
+  describe('getPairingCodes', () => {
+    test('should call /portal/api/pairing/codes', async () => {
+      const mockCodes = {
+        codes: [{ code: 'ABCD-EFGH', expiresAt: '2024-01-01T00:10:00Z', createdAt: '2024-01-01T00:00:00Z', metadata: null }],
+        total: 1,
+      };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify(mockCodes), { status: 200 })
+      );
+
+      const result = await api.getPairingCodes();
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/pairing/codes');
+      expect(result).toEqual(mockCodes);
+    });
+  });
+
+  describe('revokePairingCode', () => {
+    test('should DELETE /portal/api/pairing/codes/:code', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true }), { status: 200 })
+      );
+
+      await api.revokePairingCode('ABCD-EFGH');
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/pairing/codes/ABCD-EFGH');
+      expect(options.method).toBe('DELETE');
+    });
+  });
+
   describe('getConnections', () => {
     test('should call /portal/api/connections', async () => {
       const mockConnections = {
@@ -97,6 +149,45 @@ describe('Portal API', () => {
       expect(url).toBe('/portal/api/connections');
       expect(result).toEqual(mockConnections);
     });
+
+    test('should pass search query', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ connections: [], total: 0 }), { status: 200 })
+      );
+
+      await api.getConnections({ q: 'mom' });
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/connections?q=mom');
+    });
+
+    test('should pass filters, sort and paging', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ connections: [], total: 0 }), { status: 200 })
+      );
+
+      await api.getConnections({ state: 'blocked', label: 'Mom', sort: 'last_seen_at', order: 'asc', limit: 20, offset: 40 });
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe(
+        '/portal/api/connections?state=blocked&label=Mom&sort=last_seen_at&order=asc&limit=20&offset=40'
+      );
+    });
+  });
+
+  describe('updateConnection', () => {
+    test('should PATCH nickname and notes', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ success: true }), { status: 200 })
+      );
+
+      await api.updateConnection('ch:user', { nickname: 'Mom', notes: 'weekdays only' });
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/connections/ch%3Auser');
+      expect(options.method).toBe('PATCH');
+      expect(JSON.parse(options.body)).toEqual({ nickname: 'Mom', notes: 'weekdays only' });
+    });
   });
 
   describe('unpairConnection', () => {
@@ -175,17 +266,246 @@ describe('Portal API', () => {
     });
   });
 
+  describe('exportConfig', () => {
+    test('should call /portal/api/account/config', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ version: 1, labels: [] }), { status: 200 })
+      );
+
+      const result = await api.exportConfig();
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/config');
+      expect(result.version).toBe(1);
+    });
+  });
+
+  describe('importConfig', () => {
+    test('should PUT the config to /portal/api/account/config', async () => {
+      const config = {
+        version: 1,
+        exportedAt: '2026-01-01T00:00:00Z',
+        settings: { mode: 'relay' as const, rateLimitPerMinute: 60, kakaoChannelId: null },
+        labels: [],
+      };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ settingsApplied: false, labelsApplied: 0, labelsSkipped: [] }), { status: 200 })
+      );
+
+      await api.importConfig(config);
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/config');
+      expect(options.method).toBe('PUT');
+      expect(JSON.parse(options.body)).toEqual(config);
+    });
+  });
+
+  describe('getTimezone', () => {
+    test('should call /portal/api/account/timezone', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ timezone: 'Asia/Seoul' }), { status: 200 })
+      );
+
+      const result = await api.getTimezone();
+
+      expect(mockFetch.mock.calls[0][0]).toBe('/portal/api/account/timezone');
+      expect(result.timezone).toBe('Asia/Seoul');
+    });
+  });
+
+  describe('updateTimezone', () => {
+    test('should PUT the timezone', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ timezone: 'America/New_York' }), { status: 200 })
+      );
+
+      await api.updateTimezone('America/New_York');
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/timezone');
+      expect(options.method).toBe('PUT');
+      expect(JSON.parse(options.body)).toEqual({ timezone: 'America/New_York' });
+    });
+  });
+
+  describe('updateProfileSharing', () => {
+    test('should PUT the sharing flag', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ shareKakaoProfile: false }), { status: 200 })
+      );
+
+      const result = await api.updateProfileSharing(false);
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/profile-sharing');
+      expect(options.method).toBe('PUT');
+      expect(JSON.parse(options.body)).toEqual({ shareKakaoProfile: false });
+      expect(result.shareKakaoProfile).toBe(false);
+    });
+  });
+
+  describe('updateOnboarding', () => {
+    test('should PUT the onboarding settings', async () => {
+      const onboarding = {
+        enabled: true,
+        intro: 'Ask me anything',
+        examplePrompts: ['Summarize my day'],
+        privacyNotice: '',
+      };
+      mockFetch.mockResolvedValueOnce(new Response(JSON.stringify(onboarding), { status: 200 }));
+
+      const result = await api.updateOnboarding(onboarding);
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/onboarding');
+      expect(options.method).toBe('PUT');
+      expect(JSON.parse(options.body)).toEqual(onboarding);
+      expect(result.examplePrompts).toEqual(['Summarize my day']);
+    });
+  });
+
+  describe('updateAccountSettings', () => {
+    test('should PATCH the changed settings', async () => {
+      const settings = [
+        { key: 'locale', value: 'en', default: '', isDefault: false, adminOnly: false },
+      ];
+      mockFetch.mockResolvedValueOnce(new Response(JSON.stringify({ settings }), { status: 200 }));
+
+      const result = await api.updateAccountSettings({ locale: 'en', autoReplyText: null });
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/settings');
+      expect(options.method).toBe('PATCH');
+      expect(JSON.parse(options.body)).toEqual({ locale: 'en', autoReplyText: null });
+      expect(result.settings[0].value).toBe('en');
+    });
+  });
+
+  describe('getChangeHistory', () => {
+    test('should pass the filters as query parameters', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ entries: [], total: 0, hasMore: false }), { status: 200 })
+      );
+
+      await api.getChangeHistory({ entityType: 'conversation_state', entityKey: 'ch:u1' });
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/history?entityType=conversation_state&entityKey=ch%3Au1');
+    });
+  });
+
+  describe('getSetupChecklist', () => {
+    test('should call /portal/api/setup-checklist', async () => {
+      const checklist = {
+        steps: [
+          { name: 'token_retrieved', done: true, doneAt: '2026-01-01T00:00:00Z' },
+          { name: 'session_created', done: false },
+        ],
+        completed: false,
+      };
+      mockFetch.mockResolvedValueOnce(new Response(JSON.stringify(checklist), { status: 200 }));
+
+      const result = await api.getSetupChecklist();
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/setup-checklist');
+      expect(result.steps[0].done).toBe(true);
+      expect(result.completed).toBe(false);
+    });
+  });
+
+  describe('updateWebhook', () => {
+    test('should PUT the webhook URL and return the secret', async () => {
+      const settings = { url: 'https://agent.example.com/hook', secret: 'whsec_abc' };
+      mockFetch.mockResolvedValueOnce(new Response(JSON.stringify(settings), { status: 200 }));
+
+      const result = await api.updateWebhook('https://agent.example.com/hook');
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/webhook');
+      expect(options.method).toBe('PUT');
+      expect(JSON.parse(options.body)).toEqual({ url: 'https://agent.example.com/hook' });
+      expect(result.secret).toBe('whsec_abc');
+    });
+  });
+
+  describe('rotateWebhookSecret', () => {
+    test('should POST and return the new secret with the overlap end', async () => {
+      const settings = {
+        url: 'https://agent.example.com/hook',
+        secret: 'whsec_new',
+        previousSecretExpiresAt: '2026-01-02T00:00:00Z',
+      };
+      mockFetch.mockResolvedValueOnce(new Response(JSON.stringify(settings), { status: 200 }));
+
+      const result = await api.rotateWebhookSecret();
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/webhook/rotate-secret');
+      expect(options.method).toBe('POST');
+      expect(result.secret).toBe('whsec_new');
+      expect(result.previousSecretExpiresAt).toBe('2026-01-02T00:00:00Z');
+    });
+  });
+
+  describe('getDeletionPreview', () => {
+    test('should call /portal/api/account/deletion-preview', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ conversations: 2, previewToken: 'tok' }), { status: 200 })
+      );
+
+      const result = await api.getDeletionPreview();
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/deletion-preview');
+      expect(result.previewToken).toBe('tok');
+    });
+  });
+
   describe('deleteAccount', () => {
-    test('should call /portal/api/account with confirm', async () => {
-      mockFetch.mockResolvedValueOnce(new Response(null, { status: 204 }));
+    test('should call /portal/api/account with confirm and preview token', async () => {
+      const mockResponse = { scheduled: true, requestedAt: '2026-01-01T00:00:00Z', deletionScheduledAt: '2026-01-08T00:00:00Z' };
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify(mockResponse), { status: 202 })
+      );
 
-      await api.deleteAccount();
+      const result = await api.deleteAccount('tok');
 
       expect(mockFetch).toHaveBeenCalledTimes(1);
       const [url, options] = mockFetch.mock.calls[0];
       expect(url).toBe('/portal/api/account');
       expect(options.method).toBe('DELETE');
-      expect(JSON.parse(options.body)).toEqual({ confirm: 'DELETE' });
+      expect(JSON.parse(options.body)).toEqual({ confirm: 'DELETE', previewToken: 'tok' });
+      expect(result.deletionScheduledAt).toBe('2026-01-08T00:00:00Z');
+    });
+  });
+
+  describe('getDeletionStatus', () => {
+    test('should call /portal/api/account/deletion', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ scheduled: false, requestedAt: null, deletionScheduledAt: null }), { status: 200 })
+      );
+
+      const result = await api.getDeletionStatus();
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/deletion');
+      expect(result.scheduled).toBe(false);
+    });
+  });
+
+  describe('cancelDeletion', () => {
+    test('should POST to /portal/api/account/deletion/cancel', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ scheduled: false, requestedAt: null, deletionScheduledAt: null }), { status: 200 })
+      );
+
+      await api.cancelDeletion();
+
+      const [url, options] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/account/deletion/cancel');
+      expect(options.method).toBe('POST');
     });
   });
 
@@ -215,6 +535,17 @@ describe('Portal API', () => {
       const [url] = mockFetch.mock.calls[0];
       expect(url).toBe('/portal/api/messages?type=inbound&limit=10&offset=20');
     });
+
+    test('should pass status and conversation filters', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ messages: [], total: 0, hasMore: false }), { status: 200 })
+      );
+
+      await api.getMessages({ type: 'outbound', status: 'failed', conversationKey: 'conv-1' });
+
+      const [url] = mockFetch.mock.calls[0];
+      expect(url).toBe('/portal/api/messages?type=outbound&status=failed&conversationKey=conv-1');
+    });
   });
 
   describe('OAuth endpoints', () => {
@@ -246,4 +577,28 @@ describe('Portal API', () => {
       expect(result).toEqual({ success: true });
     });
   });
+
+  describe('errors', () => {
+    test('should expose the localized message and stable code', async () => {
+      mockFetch.mockResolvedValueOnce(
+        new Response(JSON.stringify({ error: '연결을 찾을 수 없습니다.', code: 'NOT_FOUND' }), { status: 404 })
+      );
+
+      const err = await api.unpairConnection('conv1').catch((e) => e);
+
+      expect(err).toBeInstanceOf(ApiRequestError);
+      expect(err.message).toBe('연결을 찾을 수 없습니다.');
+      expect(err.code).toBe('NOT_FOUND');
+    });
+
+    test('should fall back to a generic message for non-JSON bodies', async () => {
+      mockFetch.mockResolvedValueOnce(new Response('Bad Gateway', { status: 502 }));
+
+      const err = await api.unpairConnection('conv1').catch((e) => e);
+
+      expect(err).toBeInstanceOf(ApiRequestError);
+      expect(err.message).toBe('An error occurred');
+      expect(err.code).toBeUndefined();
+    });
+  });
 });
@@ -9,10 +9,54 @@ interface MeResponse {
   user: User;
 }
 
+export interface ConnectionHealth {
+  lastInboundAt: string | null;
+  lastOutboundAt: string | null;
+  queuedCount: number;
+  recentFailureCount: number;
+  consumerConnected: boolean;
+}
+
+export interface KakaoProfile {
+  nickname: string | null;
+  profileImageUrl: string | null;
+  fetchedAt: string;
+}
+
 export interface Connection {
   conversationKey: string;
-  state: 'paired' | 'blocked' | 'active';
+  state: 'paired' | 'archived' | 'blocked' | 'active';
+  nickname: string | null;
+  notes: string | null;
   lastSeenAt: string;
+  kakaoProfile?: KakaoProfile | null;
+  contentConsent?: 'granted' | 'denied' | null;
+  // Set while the agent has paused delivery
+  deliveryPausedAt?: string | null;
+  health?: ConnectionHealth;
+}
+
+export interface ConnectionListParams {
+  q?: string;
+  state?: 'paired' | 'archived' | 'blocked' | 'all';
+  label?: string;
+  sort?: 'last_seen_at' | 'paired_at';
+  order?: 'asc' | 'desc';
+  limit?: number;
+  offset?: number;
+}
+
+export interface ConnectionListResponse {
+  connections: Connection[];
+  total: number;
+  limit: number;
+  offset: number;
+  hasMore: boolean;
+}
+
+export interface ConnectionDetails {
+  nickname: string;
+  notes: string;
 }
 
 export interface UnpairResponse {
@@ -29,15 +73,143 @@ export interface PairingCode {
   expiresAt: string;
 }
 
+export interface ActivePairingCode extends PairingCode {
+  createdAt: string;
+  metadata: Record<string, unknown> | null;
+}
+
 export interface TokenResponse {
   token: string;
   createdAt: string;
 }
 
+export type TestMessageStageName = 'inject' | 'publish' | 'deliver';
+
+export interface TestMessageStage {
+  name: TestMessageStageName;
+  ok: boolean;
+  detail?: string;
+  durationMs: number;
+}
+
+export interface TestMessageResult {
+  ok: boolean;
+  messageId?: string;
+  stages: TestMessageStage[];
+}
+
+export interface AccountDeletionPreview {
+  accountId: string;
+  conversations: number;
+  inboundMessages: number;
+  outboundMessages: number;
+  pairingCodes: number;
+  sessions: number;
+  portalUsers: number;
+  oauthProviders: string[];
+  legalHold: boolean;
+  previewToken: string;
+  expiresAt: string;
+}
+
+export interface AccountConfig {
+  version: number;
+  exportedAt: string;
+  settings: {
+    mode: 'direct' | 'relay';
+    rateLimitPerMinute: number;
+    kakaoChannelId: string | null;
+  };
+  labels: { conversationKey: string; nickname?: string; notes?: string }[];
+}
+
+export interface AccountConfigImportResult {
+  settingsApplied: boolean;
+  labelsApplied: number;
+  labelsSkipped: string[];
+}
+
+export interface AccountTimezone {
+  timezone: string;
+}
+
+export interface ProfileSharing {
+  shareKakaoProfile: boolean;
+}
+
+export interface Onboarding {
+  enabled: boolean;
+  intro: string;
+  examplePrompts: string[];
+  privacyNotice: string;
+}
+
+export interface AccountSetting {
+  key: string;
+  value: unknown;
+  default: unknown;
+  isDefault: boolean;
+  // Only admins can change these settings
+  adminOnly: boolean;
+}
+
+export interface ChangeHistoryEntry {
+  id: string;
+  accountId: string | null;
+  entityType: 'account_setting' | 'conversation_state';
+  // Setting key or conversation key
+  entityKey: string;
+  // null: the setting had its default, or the conversation did not exist
+  oldValue: unknown;
+  newValue: unknown;
+  // "system", "admin", "kakao_user", "portal_user:<id>" or "plugin_session:<id>"
+  actor: string;
+  changedAt: string;
+}
+
+export interface WebhookSettings {
+  url: string | null;
+  // Only returned right after the webhook was saved or its secret rotated
+  secret?: string;
+  // Set while the secret replaced by a rotation still signs requests
+  previousSecretExpiresAt?: string;
+  // Set while the webhook kept failing and messages go to the event stream
+  degradedAt?: string;
+  nextProbeAt?: string;
+}
+
+export interface WebhookSignatureSample {
+  secret: string;
+  previousSecret: string;
+  headers: Record<string, string>;
+  body: string;
+  // Verification code by language
+  code: Record<string, string>;
+}
+
+export interface AccountDeletionStatus {
+  scheduled: boolean;
+  requestedAt: string | null;
+  deletionScheduledAt: string | null;
+}
+
+export type InboundMessageStatus = 'queued' | 'delivered' | 'acked' | 'expired';
+export type OutboundMessageStatus = 'pending' | 'sent' | 'failed' | 'cancelled';
+export type MessageStatus = InboundMessageStatus | OutboundMessageStatus;
+
+export interface MessageFilterParams {
+  type?: 'inbound' | 'outbound';
+  status?: MessageStatus;
+  conversationKey?: string;
+  limit?: number;
+  offset?: number;
+}
+
 export interface Message {
   id: string;
   conversationKey: string;
   direction: 'inbound' | 'outbound';
+  status?: MessageStatus;
   content: string;
   createdAt: string;
 }
@@ -90,6 +262,33 @@ export interface UserStats {
   lastActivity: string | null;
 }
 
+export type SetupStepName = 'token_retrieved' | 'session_created' | 'paired' | 'first_message' | 'first_reply';
+
+export interface SetupChecklist {
+  steps: { name: SetupStepName; done: boolean; doneAt?: string }[];
+  completed: boolean;
+}
+
+export interface DashboardResponse {
+  user: User;
+  account: {
+    id: string;
+    hasToken: boolean;
+    kakaoChannelId: string | null;
+    createdAt: string;
+  };
+  stats: UserStats;
+  recentMessages: Message[];
+  connections: {
+    total: number;
+    paired: number;
+    blocked: number;
+    queued: number;
+    recentFailures: number;
+    consumerConnected: boolean;
+  };
+}
+
 export interface ConversationStats {
   conversationKey: string;
   messages: {
@@ -134,6 +333,20 @@ function getCSRFToken(): string | null {
   return match ? decodeURIComponent(match[1]) : null;
 }
 
+/**
+ * 서버가 Accept-Language에 맞춰 번역한 메시지와, 언어와 무관하게 유지되는
+ * 오류 코드를 함께 담는다. 분기는 message가 아니라 code로 한다.
+ */
+export class ApiRequestError extends Error {
+  code?: string;
+
+  constructor(message: string, code?: string) {
+    super(message);
+    this.name = 'ApiRequestError';
+    this.code = code;
+  }
+}
+
 async function request<T>(path: string, options: RequestOptions = {}): Promise<T> {
   const { silent401, ...fetchOptions } = options;
 
@@ -164,14 +377,18 @@ async function request<T>(path: string, options: RequestOptions = {}): Promise<T
     }
 
     let errorMessage = 'An error occurred';
+    let errorCode: string | undefined;
     try {
       const text = await res.text();
       const json = JSON.parse(text);
       errorMessage = json.error || json.message || errorMessage;
+      if (typeof json.code === 'string') {
+        errorCode = json.code;
+      }
     } catch {
       // JSON 파싱 실패 시 기본 메시지 사용 (raw 텍스트 노출 방지)
     }
-    throw new Error(errorMessage);
+    throw new ApiRequestError(errorMessage, errorCode);
   }
 
   // Handle 204 No Content
@@ -195,6 +412,10 @@ export const api = {
 
   getStats: () => request<UserStats>('/portal/api/stats'),
 
+  getDashboard: () => request<DashboardResponse>('/portal/api/dashboard'),
+
+  getSetupChecklist: () => request<SetupChecklist>('/portal/api/setup-checklist'),
+
   getPublicStats: () => request<PublicStats>('/portal/api/stats/public'),
 
   generatePairingCode: (expirySeconds?: number) =>
@@ -203,8 +424,32 @@ export const api = {
       body: JSON.stringify({ expirySeconds }),
     }),
 
-  getConnections: () =>
-    request<{ connections: Connection[]; total: number }>('/portal/api/connections'),
+  getPairingCodes: () =>
+    request<{ codes: ActivePairingCode[]; total: number }>('/portal/api/pairing/codes'),
+
+  revokePairingCode: (code: string) =>
+    request<{ success: boolean }>(`/portal/api/pairing/codes/${encodeURIComponent(code)}`, {
+      method: 'DELETE',
+    }),
+
+  getConnections: (params: ConnectionListParams = {}) => {
+    const searchParams = new URLSearchParams();
+    if (params.q) searchParams.set('q', params.q);
+    if (params.state) searchParams.set('state', params.state);
+    if (params.label) searchParams.set('label', params.label);
+    if (params.sort) searchParams.set('sort', params.sort);
+    if (params.order) searchParams.set('order', params.order);
+    if (params.limit) searchParams.set('limit', String(params.limit));
+    if (params.offset) searchParams.set('offset', String(params.offset));
+    const qs = searchParams.toString();
+    return request<ConnectionListResponse>(`/portal/api/connections${qs ? `?${qs}` : ''}`);
+  },
+
+  updateConnection: (conversationKey: string, details: ConnectionDetails) =>
+    request<{ success: boolean }>(`/portal/api/connections/${encodeURIComponent(conversationKey)}`, {
+      method: 'PATCH',
+      body: JSON.stringify(details),
+    }),
 
   unpairConnection: (conversationKey: string) =>
     request<UnpairResponse>(
@@ -219,6 +464,11 @@ export const api = {
       method: 'PATCH',
     }),
 
+  sendTestMessage: () =>
+    request<TestMessageResult>('/portal/api/test-message', {
+      method: 'POST',
+    }),
+
   getToken: () => request<TokenResponse>('/portal/api/token'),
 
   regenerateToken: () =>
@@ -226,15 +476,95 @@ export const api = {
       method: 'POST',
     }),
 
-  deleteAccount: () =>
-    request<void>('/portal/api/account', {
+  exportConfig: () => request<AccountConfig>('/portal/api/account/config'),
+
+  importConfig: (config: AccountConfig) =>
+    request<AccountConfigImportResult>('/portal/api/account/config', {
+      method: 'PUT',
+      body: JSON.stringify(config),
+    }),
+
+  getTimezone: () => request<AccountTimezone>('/portal/api/account/timezone'),
+
+  updateTimezone: (timezone: string) =>
+    request<AccountTimezone>('/portal/api/account/timezone', {
+      method: 'PUT',
+      body: JSON.stringify({ timezone }),
+    }),
+
+  getProfileSharing: () => request<ProfileSharing>('/portal/api/account/profile-sharing'),
+
+  updateProfileSharing: (shareKakaoProfile: boolean) =>
+    request<ProfileSharing>('/portal/api/account/profile-sharing', {
+      method: 'PUT',
+      body: JSON.stringify({ shareKakaoProfile }),
+    }),
+
+  getOnboarding: () => request<Onboarding>('/portal/api/account/onboarding'),
+
+  updateOnboarding: (onboarding: Onboarding) =>
+    request<Onboarding>('/portal/api/account/onboarding', {
+      method: 'PUT',
+      body: JSON.stringify(onboarding),
+    }),
+
+  getAccountSettings: () =>
+    request<{ settings: AccountSetting[] }>('/portal/api/account/settings'),
+
+  // A null value resets the setting to its default
+  updateAccountSettings: (changes: Record<string, unknown>) =>
+    request<{ settings: AccountSetting[] }>('/portal/api/account/settings', {
+      method: 'PATCH',
+      body: JSON.stringify(changes),
+    }),
+
+  getChangeHistory: (params?: { entityType?: string; entityKey?: string; limit?: number; offset?: number }) => {
+    const searchParams = new URLSearchParams();
+    if (params?.entityType) searchParams.set('entityType', params.entityType);
+    if (params?.entityKey) searchParams.set('entityKey', params.entityKey);
+    if (params?.limit) searchParams.set('limit', String(params.limit));
+    if (params?.offset) searchParams.set('offset', String(params.offset));
+    const query = searchParams.toString();
+    return request<{ entries: ChangeHistoryEntry[]; total: number; hasMore: boolean }>(
+      `/portal/api/account/history${query ? `?${query}` : ''}`
+    );
+  },
+
+  getWebhook: () => request<WebhookSettings>('/portal/api/account/webhook'),
+
+  updateWebhook: (url: string) =>
+    request<WebhookSettings>('/portal/api/account/webhook', {
+      method: 'PUT',
+      body: JSON.stringify({ url }),
+    }),
+
+  deleteWebhook: () =>
+    request<WebhookSettings>('/portal/api/account/webhook', { method: 'DELETE' }),
+
+  rotateWebhookSecret: () =>
+    request<WebhookSettings>('/portal/api/account/webhook/rotate-secret', { method: 'POST' }),
+
+  getWebhookSignatureSample: () =>
+    request<WebhookSignatureSample>('/portal/api/account/webhook/signature-sample'),
+
+  getDeletionPreview: () => request<AccountDeletionPreview>('/portal/api/account/deletion-preview'),
+
+  deleteAccount: (previewToken: string) =>
+    request<AccountDeletionStatus>('/portal/api/account', {
       method: 'DELETE',
-      body: JSON.stringify({ confirm: 'DELETE' }),
+      body: JSON.stringify({ confirm: 'DELETE', previewToken }),
     }),
 
-  getMessages: (params?: { type?: 'inbound' | 'outbound'; limit?: number; offset?: number }) => {
+  getDeletionStatus: () => request<AccountDeletionStatus>('/portal/api/account/deletion'),
+
+  cancelDeletion: () =>
+    request<AccountDeletionStatus>('/portal/api/account/deletion/cancel', { method: 'POST' }),
+
+  getMessages: (params?: MessageFilterParams) => {
     const searchParams = new URLSearchParams();
     if (params?.type) searchParams.set('type', params.type);
+    if (params?.status) searchParams.set('status', params.status);
+    if (params?.conversationKey) searchParams.set('conversationKey', params.conversationKey);
     if (params?.limit) searchParams.set('limit', String(params.limit));
     if (params?.offset) searchParams.set('offset', String(params.offset));
     const query = searchParams.toString();
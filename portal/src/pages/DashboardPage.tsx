@@ -1,28 +1,39 @@
 import React, { useEffect, useState, useMemo } from 'react';
-import { useOutletContext } from 'react-router-dom';
-import { Unlink, ShieldBan, ShieldCheck, RefreshCw, AlertCircle, CheckCircle2, MessageSquare, ArrowDownToLine, ArrowUpFromLine, Shield } from 'lucide-react';
+import { Link, useOutletContext } from 'react-router-dom';
+import { Unlink, ShieldBan, ShieldCheck, RefreshCw, Pencil, Trash2, AlertCircle, CheckCircle2, Circle, MessageSquare, ArrowDownToLine, ArrowUpFromLine, Shield } from 'lucide-react';
 import { Button } from '../components/ui/button';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '../components/ui/card';
 import { Badge } from '../components/ui/badge';
+import { Input } from '../components/ui/input';
 import { Tabs, TabsList, TabsTrigger } from '../components/ui/tabs';
-import { api, type Connection, type UserStats, type ConversationStats } from '../lib/api';
+import { api, type ActivePairingCode, type Connection, type UserStats, type ConversationStats, type SetupChecklist, type SetupStepName, type WebhookSettings } from '../lib/api';
 
-type FilterType = 'all' | 'paired' | 'blocked';
+type FilterType = 'all' | 'paired' | 'archived' | 'blocked';
 
 export default function DashboardPage() {
   const { isCodeSession } = useOutletContext<{ isCodeSession?: boolean }>();
   const [connections, setConnections] = useState<Connection[]>([]);
   const [pairingCode, setPairingCode] = useState<string | null>(null);
+  const [activeCodes, setActiveCodes] = useState<ActivePairingCode[]>([]);
   const [loading, setLoading] = useState(true);
   const [filter, setFilter] = useState<FilterType>('all');
+  const [search, setSearch] = useState('');
+  const [total, setTotal] = useState(0);
+  const [hasMore, setHasMore] = useState(false);
   const [actionLoading, setActionLoading] = useState<string | null>(null);
   const [stats, setStats] = useState<UserStats | ConversationStats | null>(null);
   const [statsLoading, setStatsLoading] = useState(true);
 
-  const loadConnections = async () => {
+  const loadConnections = async (offset = 0) => {
     try {
-      const { connections: data } = await api.getConnections();
-      setConnections(data);
+      const result = await api.getConnections({
+        q: search.trim() || undefined,
+        state: filter,
+        offset,
+      });
+      setConnections((prev) => (offset === 0 ? result.connections : [...prev, ...result.connections]));
+      setTotal(result.total);
+      setHasMore(result.hasMore);
     } catch (error) {
       console.error('Failed to load connections', error);
     } finally {
@@ -41,12 +52,30 @@ export default function DashboardPage() {
     }
   };
 
+  const loadPairingCodes = async () => {
+    try {
+      const { codes } = await api.getPairingCodes();
+      setActiveCodes(codes);
+    } catch (error) {
+      console.error('Failed to load pairing codes', error);
+    }
+  };
+
+  useEffect(() => {
+    if (!isCodeSession) {
+      loadPairingCodes();
+    }
+  }, [isCodeSession]);
+
   useEffect(() => {
     if (!isCodeSession) {
       loadConnections();
     } else {
       setLoading(false);
     }
+  }, [isCodeSession, filter]);
+
+  useEffect(() => {
     loadStats();
 
     const interval = setInterval(loadStats, 30000);
@@ -57,6 +86,7 @@ export default function DashboardPage() {
     if (filter === 'all') return connections;
     return connections.filter((conn) => {
       if (filter === 'blocked') return conn.state === 'blocked';
+      if (filter === 'archived') return conn.state === 'archived';
       return conn.state === 'paired' || conn.state === 'active';
     });
   }, [connections, filter]);
@@ -65,11 +95,25 @@ export default function DashboardPage() {
     try {
       const { code } = await api.generatePairingCode();
       setPairingCode(code);
+      loadPairingCodes();
     } catch (error) {
       console.error('Failed to generate code', error);
     }
   };
 
+  const revokeCode = async (code: string) => {
+    if (!confirm(`페어링 코드 ${code}를 폐기하시겠습니까?`)) return;
+
+    try {
+      await api.revokePairingCode(code);
+      setActiveCodes((prev) => prev.filter((c) => c.code !== code));
+      if (pairingCode === code) setPairingCode(null);
+    } catch (error) {
+      console.error('Failed to revoke code', error);
+      alert('코드 폐기에 실패했습니다');
+    }
+  };
+
   const copyCode = async () => {
     if (pairingCode) {
       await navigator.clipboard.writeText(pairingCode);
@@ -93,6 +137,36 @@ export default function DashboardPage() {
     }
   };
 
+  const handleSearch = (e: React.FormEvent) => {
+    e.preventDefault();
+    setLoading(true);
+    loadConnections();
+  };
+
+  const handleEdit = async (conn: Connection) => {
+    const nickname = prompt('이 연결의 별명을 입력하세요', conn.nickname ?? '');
+    if (nickname === null) return;
+    const notes = prompt('메모를 입력하세요', conn.notes ?? '');
+    if (notes === null) return;
+
+    setActionLoading(conn.conversationKey);
+    try {
+      await api.updateConnection(conn.conversationKey, { nickname, notes });
+      setConnections((prev) =>
+        prev.map((c) =>
+          c.conversationKey === conn.conversationKey
+            ? { ...c, nickname: nickname.trim() || null, notes: notes.trim() || null }
+            : c
+        )
+      );
+    } catch (error) {
+      console.error('Failed to update connection', error);
+      alert(error instanceof Error ? error.message : '작업에 실패했습니다.');
+    } finally {
+      setActionLoading(null);
+    }
+  };
+
   const handleBlock = async (conversationKey: string, currentState: Connection['state']) => {
     const isBlocking = currentState !== 'blocked';
     const message = isBlocking
@@ -123,6 +197,8 @@ export default function DashboardPage() {
     switch (state) {
       case 'blocked':
         return <Badge variant="destructive">차단됨</Badge>;
+      case 'archived':
+        return <Badge variant="outline">보관됨</Badge>;
       case 'active':
         return <Badge variant="default">활성</Badge>;
       default:
@@ -192,6 +268,9 @@ export default function DashboardPage() {
         )}
       </div>
 
+      {!isCodeSession && <WebhookDegradedAlert />}
+      {!isCodeSession && <SetupChecklistCard />}
+
       <div className="grid gap-4 md:grid-cols-4">
         <Card>
           <CardContent className="pt-6">
@@ -343,6 +422,24 @@ export default function DashboardPage() {
                 새 코드 생성
               </Button>
             )}
+            {activeCodes.length > 0 && (
+              <div className="space-y-2">
+                <div className="text-sm font-medium">활성 코드</div>
+                {activeCodes.map((c) => (
+                  <div key={c.code} className="flex items-center justify-between rounded-lg border p-2">
+                    <div>
+                      <div className="font-mono text-sm">{c.code}</div>
+                      <div className="text-xs text-muted-foreground">
+                        만료: {new Date(c.expiresAt).toLocaleString('ko-KR')}
+                      </div>
+                    </div>
+                    <Button variant="ghost" size="icon" onClick={() => revokeCode(c.code)} title="코드 폐기">
+                      <Trash2 className="h-4 w-4 text-destructive" />
+                    </Button>
+                  </div>
+                ))}
+              </div>
+            )}
           </CardContent>
         </Card>
 
@@ -353,22 +450,30 @@ export default function DashboardPage() {
               <div>
                 <CardTitle>연결 관리</CardTitle>
                 <CardDescription>
-                  연결된 카카오톡 대화 목록 ({filteredConnections.length}개)
+                  연결된 카카오톡 대화 목록 ({total}개)
                 </CardDescription>
               </div>
               <Button
                 variant="ghost"
                 size="icon"
-                onClick={loadConnections}
+                onClick={() => loadConnections()}
                 disabled={loading}
               >
                 <RefreshCw className="h-4 w-4" />
               </Button>
             </div>
+            <form onSubmit={handleSearch}>
+              <Input
+                value={search}
+                onChange={(e) => setSearch(e.target.value)}
+                placeholder="별명, 메모, 대화 키로 검색"
+              />
+            </form>
             <Tabs defaultValue="all" value={filter} onValueChange={(v) => setFilter(v as FilterType)}>
-              <TabsList className="grid w-full grid-cols-3">
+              <TabsList className="grid w-full grid-cols-4">
                 <TabsTrigger value="all">전체</TabsTrigger>
                 <TabsTrigger value="paired">활성</TabsTrigger>
+                <TabsTrigger value="archived">보관됨</TabsTrigger>
                 <TabsTrigger value="blocked">차단됨</TabsTrigger>
               </TabsList>
             </Tabs>
@@ -380,7 +485,9 @@ export default function DashboardPage() {
                   ? '연결된 대화가 없습니다'
                   : filter === 'blocked'
                     ? '차단된 연결이 없습니다'
-                    : '활성 연결이 없습니다'}
+                    : filter === 'archived'
+                      ? '보관된 연결이 없습니다'
+                      : '활성 연결이 없습니다'}
               </div>
             ) : (
               <div className="space-y-3">
@@ -396,15 +503,64 @@ export default function DashboardPage() {
                       <div className="mr-4 min-w-0 flex-1">
                         <div className="flex items-center gap-2">
                           <span className="truncate font-medium">
-                            {conn.conversationKey}
+                            {conn.nickname || conn.conversationKey}
                           </span>
                           {getStateBadge(conn.state)}
+                          {conn.deliveryPausedAt && (
+                            <Badge
+                              variant="outline"
+                              title={`일시 중지: ${new Date(conn.deliveryPausedAt).toLocaleString('ko-KR')}`}
+                            >
+                              전달 일시 중지
+                            </Badge>
+                          )}
                         </div>
+                        {conn.nickname && (
+                          <div className="truncate text-xs text-muted-foreground">
+                            {conn.conversationKey}
+                          </div>
+                        )}
+                        {conn.kakaoProfile?.nickname && (
+                          <div className="truncate text-xs text-muted-foreground">
+                            카카오 프로필: {conn.kakaoProfile.nickname}
+                          </div>
+                        )}
+                        {conn.contentConsent === 'denied' && (
+                          <div className="text-xs text-muted-foreground">메시지 내용 저장 안 함</div>
+                        )}
+                        {conn.notes && (
+                          <div className="truncate text-sm text-muted-foreground">{conn.notes}</div>
+                        )}
                         <div className="text-xs text-muted-foreground">
                           마지막 활동: {new Date(conn.lastSeenAt).toLocaleString('ko-KR')}
                         </div>
+                        {conn.health && (
+                          <div className="flex flex-wrap gap-x-3 text-xs text-muted-foreground">
+                            <span className={conn.health.consumerConnected ? 'text-green-600' : 'text-yellow-600'}>
+                              {conn.health.consumerConnected ? 'OpenClaw 연결됨' : 'OpenClaw 미연결'}
+                            </span>
+                            {conn.health.queuedCount > 0 && (
+                              <span className="text-yellow-600">대기 {conn.health.queuedCount}개</span>
+                            )}
+                            {conn.health.recentFailureCount > 0 && (
+                              <span className="text-red-500">24시간 실패 {conn.health.recentFailureCount}개</span>
+                            )}
+                            {conn.health.lastOutboundAt && (
+                              <span>마지막 응답: {new Date(conn.health.lastOutboundAt).toLocaleString('ko-KR')}</span>
+                            )}
+                          </div>
+                        )}
                       </div>
                       <div className="flex items-center gap-1">
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          onClick={() => handleEdit(conn)}
+                          disabled={isLoading}
+                          title="별명/메모 편집"
+                        >
+                          <Pencil className="h-4 w-4" />
+                        </Button>
                         <Button
                           variant="ghost"
                           size="icon"
@@ -431,6 +587,16 @@ export default function DashboardPage() {
                     </div>
                   );
                 })}
+                {hasMore && (
+                  <Button
+                    variant="outline"
+                    className="w-full"
+                    onClick={() => loadConnections(connections.length)}
+                    disabled={loading}
+                  >
+                    더 보기
+                  </Button>
+                )}
               </div>
             )}
           </CardContent>
@@ -440,3 +606,89 @@ export default function DashboardPage() {
     </div>
   );
 }
+
+const SETUP_STEP_LABELS: Record<SetupStepName, { title: string; hint: string }> = {
+  token_retrieved: { title: 'API 토큰 발급', hint: 'API 토큰 화면에서 토큰을 발급받아 플러그인에 설정하세요.' },
+  session_created: { title: '플러그인 연결', hint: '플러그인을 실행해 릴레이에 연결하세요.' },
+  paired: { title: '카카오 사용자 페어링', hint: '페어링 코드를 생성해 카카오톡 채널에서 /pair 코드를 보내세요.' },
+  first_message: { title: '첫 메시지 수신', hint: '카카오톡 채널에서 메시지를 보내 보세요.' },
+  first_reply: { title: '첫 답장 전송', hint: '플러그인이 받은 메시지에 답장하면 완료됩니다.' },
+};
+
+// Shown while the webhook kept failing and messages fall back to the event
+// stream, so the user notices before looking at the settings page.
+function WebhookDegradedAlert() {
+  const [webhook, setWebhook] = useState<WebhookSettings | null>(null);
+
+  useEffect(() => {
+    api
+      .getWebhook()
+      .then(setWebhook)
+      .catch((error) => console.error('Failed to load webhook settings', error));
+  }, []);
+
+  if (!webhook?.degradedAt) return null;
+
+  return (
+    <div className="flex items-start gap-2 rounded-lg border border-yellow-500/50 bg-yellow-500/10 p-3 text-sm text-yellow-700 dark:text-yellow-400">
+      <AlertCircle className="mt-0.5 h-4 w-4 flex-shrink-0" />
+      <div>
+        <p className="font-medium">웹훅 전송이 계속 실패해 SSE 이벤트 스트림으로 전달하고 있습니다</p>
+        <p className="mt-1 text-yellow-600 dark:text-yellow-500">
+          웹훅 주소가 다시 응답하면 자동으로 웹훅 전송으로 돌아갑니다.{' '}
+          <Link to="/settings" className="underline">
+            설정에서 확인
+          </Link>
+        </p>
+      </div>
+    </div>
+  );
+}
+
+function SetupChecklistCard() {
+  const [checklist, setChecklist] = useState<SetupChecklist | null>(null);
+
+  useEffect(() => {
+    api
+      .getSetupChecklist()
+      .then(setChecklist)
+      .catch((error) => console.error('Failed to load setup checklist', error));
+  }, []);
+
+  if (!checklist || checklist.completed) return null;
+
+  const doneCount = checklist.steps.filter((step) => step.done).length;
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>시작하기</CardTitle>
+        <CardDescription>
+          릴레이 설정 {doneCount}/{checklist.steps.length}단계 완료
+        </CardDescription>
+      </CardHeader>
+      <CardContent>
+        <ol className="space-y-3">
+          {checklist.steps.map((step) => {
+            const label = SETUP_STEP_LABELS[step.name];
+            return (
+              <li key={step.name} className="flex items-start gap-3">
+                {step.done ? (
+                  <CheckCircle2 className="mt-0.5 h-4 w-4 text-green-500" />
+                ) : (
+                  <Circle className="mt-0.5 h-4 w-4 text-muted-foreground" />
+                )}
+                <div>
+                  <div className={step.done ? 'text-sm text-muted-foreground line-through' : 'text-sm font-medium'}>
+                    {label?.title ?? step.name}
+                  </div>
+                  {!step.done && label && <div className="text-xs text-muted-foreground">{label.hint}</div>}
+                </div>
+              </li>
+            );
+          })}
+        </ol>
+      </CardContent>
+    </Card>
+  );
+}
@@ -4,39 +4,66 @@ import { RefreshCw, ArrowDownLeft, ArrowUpRight, ChevronLeft, ChevronRight, Shie
 import { Button } from '../components/ui/button';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '../components/ui/card';
 import { Badge } from '../components/ui/badge';
+import { Input } from '../components/ui/input';
 import { Tabs, TabsList, TabsTrigger } from '../components/ui/tabs';
-import { api, type Message } from '../lib/api';
+import { api, type Message, type MessageFilterParams, type MessageStatus } from '../lib/api';
 
 type MessageType = 'all' | 'inbound' | 'outbound';
 
 const LIMIT = 20;
 
+const STATUS_LABELS: Record<MessageStatus, string> = {
+  queued: '대기',
+  delivered: '전달됨',
+  acked: '확인됨',
+  expired: '만료',
+  pending: '전송 중',
+  sent: '전송됨',
+  failed: '실패',
+  cancelled: '취소됨',
+};
+
+const STATUSES_BY_TYPE: Record<MessageType, MessageStatus[]> = {
+  all: ['queued', 'delivered', 'acked', 'expired', 'pending', 'sent', 'failed', 'cancelled'],
+  inbound: ['queued', 'delivered', 'acked', 'expired'],
+  outbound: ['pending', 'sent', 'failed', 'cancelled'],
+};
+
 export default function MessagesPage() {
   const { isCodeSession } = useOutletContext<{ isCodeSession?: boolean }>();
   const [messages, setMessages] = useState<Message[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [type, setType] = useState<MessageType>('all');
+  const [status, setStatus] = useState<MessageStatus | ''>('');
+  const [conversationKey, setConversationKey] = useState('');
   const [offset, setOffset] = useState(0);
   const [total, setTotal] = useState(0);
   const [hasMore, setHasMore] = useState(false);
 
   useEffect(() => {
     loadMessages();
-  }, [type, offset]);
+  }, [type, status, conversationKey, offset]);
 
   const loadMessages = async () => {
     setLoading(true);
     try {
       setError(null);
-      const params: { type?: 'inbound' | 'outbound'; limit: number; offset: number } = {
+      const params: MessageFilterParams = {
         limit: LIMIT,
         offset,
       };
       if (type !== 'all') {
         params.type = type;
       }
-      const data = isCodeSession ? await api.getCodeMessages(params) : await api.getMessages(params);
+      let data;
+      if (isCodeSession) {
+        data = await api.getCodeMessages(params);
+      } else {
+        if (status) params.status = status;
+        if (conversationKey) params.conversationKey = conversationKey;
+        data = await api.getMessages(params);
+      }
       setMessages(data.messages);
       setTotal(data.total);
       setHasMore(data.hasMore);
@@ -48,7 +75,18 @@ export default function MessagesPage() {
   };
 
   const handleTypeChange = (newType: string) => {
-    setType(newType as MessageType);
+    const next = newType as MessageType;
+    setType(next);
+    if (status && !STATUSES_BY_TYPE[next].includes(status)) {
+      setStatus('');
+    }
+    setOffset(0);
+  };
+
+  const handleConversationKeySubmit = (e: React.FormEvent<HTMLFormElement>) => {
+    e.preventDefault();
+    const value = new FormData(e.currentTarget).get('conversationKey');
+    setConversationKey(typeof value === 'string' ? value.trim() : '');
     setOffset(0);
   };
 
@@ -111,6 +149,36 @@ export default function MessagesPage() {
               </TabsTrigger>
             </TabsList>
           </Tabs>
+          {!isCodeSession && (
+            <div className="flex flex-wrap gap-2">
+              <select
+                className="h-9 rounded-md border border-input bg-background px-3 text-sm"
+                value={status}
+                onChange={(e) => {
+                  setStatus(e.target.value as MessageStatus | '');
+                  setOffset(0);
+                }}
+              >
+                <option value="">모든 상태</option>
+                {STATUSES_BY_TYPE[type].map((value) => (
+                  <option key={value} value={value}>
+                    {STATUS_LABELS[value]}
+                  </option>
+                ))}
+              </select>
+              <form onSubmit={handleConversationKeySubmit} className="flex gap-2">
+                <Input
+                  name="conversationKey"
+                  placeholder="대화 키로 필터"
+                  defaultValue={conversationKey}
+                  className="h-9 w-64 font-mono text-xs"
+                />
+                <Button type="submit" variant="outline" size="sm">
+                  적용
+                </Button>
+              </form>
+            </div>
+          )}
         </CardHeader>
         <CardContent>
           {error ? (
@@ -155,6 +223,11 @@ export default function MessagesPage() {
                             </>
                           )}
                         </Badge>
+                        {message.status && (
+                          <Badge variant={message.status === 'failed' ? 'destructive' : 'outline'}>
+                            {STATUS_LABELS[message.status]}
+                          </Badge>
+                        )}
                         <span className="truncate font-mono text-xs text-muted-foreground">
                           {message.conversationKey}
                         </span>
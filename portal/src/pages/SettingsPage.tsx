@@ -1,17 +1,16 @@
-import { useState, useEffect } from 'react';
-import { useNavigate, useOutletContext } from 'react-router-dom';
-import { AlertTriangle, Link2, Trash2, Unlink } from 'lucide-react';
+import { useState, useEffect, useRef } from 'react';
+import { useOutletContext } from 'react-router-dom';
+import { AlertTriangle, Clock, Download, Globe, Link2, MessageSquare, Trash2, Unlink, Upload, UserRound, Webhook } from 'lucide-react';
 import { Button } from '../components/ui/button';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '../components/ui/card';
 import { Input } from '../components/ui/input';
-import { api, type User, type OAuthProvider } from '../lib/api';
+import { api, type AccountConfig, type AccountDeletionStatus, type Onboarding, type User, type OAuthProvider, type WebhookSettings, type WebhookSignatureSample } from '../lib/api';
 
 interface LayoutContext {
   user: User | null;
 }
 
 export default function SettingsPage() {
-  const navigate = useNavigate();
   const { user } = useOutletContext<LayoutContext>();
 
   return (
@@ -39,20 +38,582 @@ export default function SettingsPage() {
         </CardContent>
       </Card>
 
+      {/* Timezone */}
+      <TimezoneCard />
+
+      {/* Kakao profile sharing */}
+      <ProfileSharingCard />
+
+      {/* Onboarding messages */}
+      <OnboardingCard />
+
+      {/* Webhook delivery */}
+      <WebhookCard />
+
       {/* Linked Accounts */}
       <LinkedAccountsCard />
 
+      {/* Configuration Export/Import */}
+      <ConfigTransferCard />
+
       {/* Account Deletion */}
-      <AccountDeletionCard onDeleted={() => navigate('/auth')} />
+      <AccountDeletionCard />
     </div>
   );
 }
 
-function AccountDeletionCard({ onDeleted }: { onDeleted: () => void }) {
+const COMMON_TIMEZONES = [
+  'Asia/Seoul',
+  'Asia/Tokyo',
+  'Asia/Shanghai',
+  'Asia/Singapore',
+  'Europe/London',
+  'Europe/Berlin',
+  'America/New_York',
+  'America/Los_Angeles',
+  'UTC',
+];
+
+function TimezoneCard() {
+  const [timezone, setTimezone] = useState<string | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [saved, setSaved] = useState(false);
+
+  useEffect(() => {
+    api.getTimezone().then((res) => setTimezone(res.timezone)).catch(() => setTimezone(null));
+  }, []);
+
+  const handleChange = async (e: React.ChangeEvent<HTMLSelectElement>) => {
+    const next = e.target.value;
+    setError(null);
+    setSaved(false);
+    setLoading(true);
+    try {
+      const res = await api.updateTimezone(next);
+      setTimezone(res.timezone);
+      setSaved(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '시간대 변경에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const options = timezone && !COMMON_TIMEZONES.includes(timezone)
+    ? [timezone, ...COMMON_TIMEZONES]
+    : COMMON_TIMEZONES;
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <Globe className="h-5 w-5" />
+          시간대
+        </CardTitle>
+        <CardDescription>
+          통계의 &quot;오늘&quot; 기준이 되는 하루의 시작 시각을 정합니다.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        {error && (
+          <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+            {error}
+          </div>
+        )}
+        {saved && (
+          <div className="rounded-lg border p-3 text-sm">시간대를 변경했습니다.</div>
+        )}
+
+        <select
+          className="flex h-10 w-full rounded-md border border-input bg-background px-3 py-2 text-sm"
+          value={timezone ?? ''}
+          onChange={handleChange}
+          disabled={loading || timezone === null}
+        >
+          {options.map((tz) => (
+            <option key={tz} value={tz}>
+              {tz}
+            </option>
+          ))}
+        </select>
+      </CardContent>
+    </Card>
+  );
+}
+
+function ProfileSharingCard() {
+  const [share, setShare] = useState<boolean | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    api.getProfileSharing().then((res) => setShare(res.shareKakaoProfile)).catch(() => setShare(null));
+  }, []);
+
+  const handleChange = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const next = e.target.checked;
+    setError(null);
+    setLoading(true);
+    try {
+      const res = await api.updateProfileSharing(next);
+      setShare(res.shareKakaoProfile);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '설정 변경에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <UserRound className="h-5 w-5" />
+          카카오 프로필 공유
+        </CardTitle>
+        <CardDescription>
+          카카오 사용자의 닉네임과 프로필 사진을 OpenClaw 플러그인에 전달할지 정합니다.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        {error && (
+          <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+            {error}
+          </div>
+        )}
+        <label className="flex items-center gap-2 text-sm">
+          <input
+            type="checkbox"
+            checked={share ?? false}
+            onChange={handleChange}
+            disabled={loading || share === null}
+          />
+          메시지 이벤트와 대화 API에 카카오 프로필 포함
+        </label>
+      </CardContent>
+    </Card>
+  );
+}
+
+const TEXTAREA_CLASS =
+  'flex min-h-20 w-full rounded-md border border-input bg-background px-3 py-2 text-sm';
+
+function OnboardingCard() {
+  const [onboarding, setOnboarding] = useState<Onboarding | null>(null);
+  const [prompts, setPrompts] = useState('');
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [saved, setSaved] = useState(false);
+
+  useEffect(() => {
+    api
+      .getOnboarding()
+      .then((res) => {
+        setOnboarding(res);
+        setPrompts(res.examplePrompts.join('\n'));
+      })
+      .catch(() => setOnboarding(null));
+  }, []);
+
+  const update = (changes: Partial<Onboarding>) => {
+    if (onboarding) {
+      setOnboarding({ ...onboarding, ...changes });
+      setSaved(false);
+    }
+  };
+
+  const handleSave = async () => {
+    if (!onboarding) return;
+    setError(null);
+    setSaved(false);
+    setLoading(true);
+    try {
+      const res = await api.updateOnboarding({
+        ...onboarding,
+        examplePrompts: prompts.split('\n'),
+      });
+      setOnboarding(res);
+      setPrompts(res.examplePrompts.join('\n'));
+      setSaved(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '온보딩 설정 저장에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const disabled = loading || onboarding === null;
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <MessageSquare className="h-5 w-5" />
+          온보딩 메시지
+        </CardTitle>
+        <CardDescription>
+          카카오 사용자가 페어링에 성공하면 시작 안내, 예시 질문, 개인정보 안내를 함께 보냅니다. 비워 둔 문구는 기본 문구로 보냅니다.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        {error && (
+          <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+            {error}
+          </div>
+        )}
+        {saved && (
+          <div className="rounded-lg border p-3 text-sm">온보딩 설정을 저장했습니다.</div>
+        )}
+
+        <label className="flex items-center gap-2 text-sm">
+          <input
+            type="checkbox"
+            checked={onboarding?.enabled ?? false}
+            onChange={(e) => update({ enabled: e.target.checked })}
+            disabled={disabled}
+          />
+          페어링 후 온보딩 메시지 보내기
+        </label>
+
+        <div className="space-y-2">
+          <label className="text-sm font-medium">시작 안내 (최대 400자)</label>
+          <textarea
+            className={TEXTAREA_CLASS}
+            value={onboarding?.intro ?? ''}
+            onChange={(e) => update({ intro: e.target.value })}
+            maxLength={400}
+            disabled={disabled}
+          />
+        </div>
+
+        <div className="space-y-2">
+          <label className="text-sm font-medium">예시 질문 (한 줄에 하나, 최대 5개)</label>
+          <textarea
+            className={TEXTAREA_CLASS}
+            value={prompts}
+            onChange={(e) => {
+              setPrompts(e.target.value);
+              setSaved(false);
+            }}
+            disabled={disabled}
+          />
+        </div>
+
+        <div className="space-y-2">
+          <label className="text-sm font-medium">개인정보 안내 (최대 1000자)</label>
+          <textarea
+            className={TEXTAREA_CLASS}
+            value={onboarding?.privacyNotice ?? ''}
+            onChange={(e) => update({ privacyNotice: e.target.value })}
+            maxLength={1000}
+            disabled={disabled}
+          />
+        </div>
+
+        <Button onClick={handleSave} disabled={disabled}>
+          저장
+        </Button>
+      </CardContent>
+    </Card>
+  );
+}
+
+function WebhookCard() {
+  const [settings, setSettings] = useState<WebhookSettings | null>(null);
+  const [url, setUrl] = useState('');
+  const [secret, setSecret] = useState<string | null>(null);
+  const [sample, setSample] = useState<WebhookSignatureSample | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    api
+      .getWebhook()
+      .then((res) => {
+        setSettings(res);
+        setUrl(res.url ?? '');
+      })
+      .catch(() => setSettings(null));
+  }, []);
+
+  const handleSave = async () => {
+    setError(null);
+    setSecret(null);
+    setLoading(true);
+    try {
+      const res = await api.updateWebhook(url.trim());
+      setSettings(res);
+      setSecret(res.secret ?? null);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '웹훅 설정 저장에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleDelete = async () => {
+    if (!confirm('웹훅 전송을 끄고 SSE 이벤트 스트림으로 메시지를 받으시겠습니까?')) return;
+    setError(null);
+    setSecret(null);
+    setLoading(true);
+    try {
+      const res = await api.deleteWebhook();
+      setSettings(res);
+      setUrl('');
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '웹훅 설정 저장에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleRotate = async () => {
+    if (!confirm('새 서명 시크릿을 발급하시겠습니까? 기존 시크릿은 24시간 동안 함께 사용됩니다.')) return;
+    setError(null);
+    setSecret(null);
+    setLoading(true);
+    try {
+      const res = await api.rotateWebhookSecret();
+      setSettings(res);
+      setSecret(res.secret ?? null);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '시크릿 교체에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleSample = async () => {
+    setError(null);
+    try {
+      setSample(await api.getWebhookSignatureSample());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '서명 검증 예제를 불러오지 못했습니다.');
+    }
+  };
+
+  const disabled = loading || settings === null;
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <Webhook className="h-5 w-5" />
+          웹훅 전송
+        </CardTitle>
+        <CardDescription>
+          SSE 연결을 유지하는 대신, 받은 메시지를 지정한 HTTPS 주소로 POST 받습니다. 요청에는 서명 시크릿으로 만든
+          X-Relay-Signature 헤더가 붙고, 2xx로 응답하지 않으면 간격을 늘려 가며 다시 보냅니다.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        {error && (
+          <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+            {error}
+          </div>
+        )}
+        {secret && (
+          <div className="space-y-1 rounded-lg border p-3 text-sm">
+            <p>새 서명 시크릿이 발급되었습니다. 지금만 표시되니 안전한 곳에 보관하세요.</p>
+            <code className="block break-all font-mono">{secret}</code>
+          </div>
+        )}
+        {settings?.degradedAt && (
+          <div className="flex items-start gap-2 rounded-lg border border-yellow-500/50 bg-yellow-500/10 p-3 text-sm text-yellow-700 dark:text-yellow-400">
+            <AlertTriangle className="mt-0.5 h-4 w-4 flex-shrink-0" />
+            <div>
+              <p className="font-medium">웹훅 전송이 계속 실패해 SSE 이벤트 스트림으로 전달하고 있습니다</p>
+              <p className="mt-1 text-yellow-600 dark:text-yellow-500">
+                {new Date(settings.degradedAt).toLocaleString('ko-KR')}부터 웹훅 대신 SSE로 메시지를 전달합니다. 웹훅 주소를
+                주기적으로 확인해 응답하면 웹훅 전송으로 돌아갑니다.
+                {settings.nextProbeAt && ` 다음 확인: ${new Date(settings.nextProbeAt).toLocaleString('ko-KR')}`}
+              </p>
+            </div>
+          </div>
+        )}
+        {settings?.previousSecretExpiresAt && (
+          <p className="text-sm text-muted-foreground">
+            {new Date(settings.previousSecretExpiresAt).toLocaleString('ko-KR')}까지는 이전 시크릿으로 만든 서명도
+            X-Relay-Signature 헤더에 쉼표로 구분해 함께 보냅니다. 그 전에 수신 서버의 시크릿을 교체하세요.
+          </p>
+        )}
+
+        <div className="space-y-2">
+          <label className="text-sm font-medium">웹훅 URL</label>
+          <Input
+            type="url"
+            placeholder="https://example.com/kakao/webhook"
+            value={url}
+            onChange={(e) => setUrl(e.target.value)}
+            disabled={disabled}
+          />
+          <p className="text-xs text-muted-foreground">
+            {settings?.url ? '웹훅으로 메시지를 전송하고 있습니다. 다시 저장하면 새 시크릿이 발급됩니다.' : '웹훅이 꺼져 있어 SSE 이벤트 스트림으로 메시지를 전달합니다.'}
+          </p>
+        </div>
+
+        <div className="flex gap-2">
+          <Button onClick={handleSave} disabled={disabled || url.trim() === ''}>
+            저장
+          </Button>
+          {settings?.url && (
+            <Button variant="outline" onClick={handleRotate} disabled={disabled}>
+              시크릿 교체
+            </Button>
+          )}
+          {settings?.url && (
+            <Button variant="outline" onClick={handleDelete} disabled={disabled}>
+              웹훅 끄기
+            </Button>
+          )}
+          <Button variant="ghost" onClick={handleSample} disabled={loading}>
+            서명 검증 예제
+          </Button>
+        </div>
+
+        {sample && (
+          <div className="space-y-2 text-sm">
+            <p className="text-muted-foreground">
+              예제 시크릿 <code className="font-mono">{sample.secret}</code>과 이전 시크릿{' '}
+              <code className="font-mono">{sample.previousSecret}</code>으로 서명한 요청입니다. 검증 코드가 이 요청을
+              통과시키는지 확인해 보세요.
+            </p>
+            <pre className="overflow-x-auto rounded-lg bg-muted p-3 text-xs">
+              {Object.entries(sample.headers)
+                .map(([name, value]) => `${name}: ${value}`)
+                .join('\n')}
+              {'\n\n'}
+              {sample.body}
+            </pre>
+            {Object.entries(sample.code).map(([language, code]) => (
+              <div key={language} className="space-y-1">
+                <p className="font-medium">{language}</p>
+                <pre className="overflow-x-auto rounded-lg bg-muted p-3 text-xs">{code}</pre>
+              </div>
+            ))}
+          </div>
+        )}
+      </CardContent>
+    </Card>
+  );
+}
+
+function ConfigTransferCard() {
+  const fileInputRef = useRef<HTMLInputElement>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [result, setResult] = useState<string | null>(null);
+
+  const handleExport = async () => {
+    setError(null);
+    setResult(null);
+    setLoading(true);
+    try {
+      const config = await api.exportConfig();
+      const blob = new Blob([JSON.stringify(config, null, 2)], { type: 'application/json' });
+      const url = URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = 'relay-config.json';
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '설정 내보내기에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleImport = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    e.target.value = '';
+    if (!file) return;
+
+    setError(null);
+    setResult(null);
+    setLoading(true);
+    try {
+      const config = JSON.parse(await file.text()) as AccountConfig;
+      const imported = await api.importConfig(config);
+      const skipped = imported.labelsSkipped.length;
+      setResult(
+        `연결 별칭 ${imported.labelsApplied}개를 가져왔습니다.` +
+          (skipped > 0 ? ` 이 계정에 연결되지 않은 대화 ${skipped}개는 건너뛰었습니다.` : '')
+      );
+    } catch (err) {
+      setError(
+        err instanceof SyntaxError
+          ? '올바른 설정 파일이 아닙니다.'
+          : err instanceof Error ? err.message : '설정 가져오기에 실패했습니다.'
+      );
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>설정 내보내기/가져오기</CardTitle>
+        <CardDescription>
+          연결 별칭과 메모 등 계정 설정을 JSON 파일로 옮길 수 있습니다. 메시지는 포함되지 않습니다.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        {error && (
+          <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+            {error}
+          </div>
+        )}
+        {result && (
+          <div className="rounded-lg border p-3 text-sm">{result}</div>
+        )}
+
+        <div className="flex gap-2">
+          <Button variant="outline" onClick={handleExport} disabled={loading}>
+            <Download className="h-4 w-4 mr-1" />
+            내보내기
+          </Button>
+          <Button variant="outline" onClick={() => fileInputRef.current?.click()} disabled={loading}>
+            <Upload className="h-4 w-4 mr-1" />
+            가져오기
+          </Button>
+          <input
+            ref={fileInputRef}
+            type="file"
+            accept="application/json,.json"
+            className="hidden"
+            onChange={handleImport}
+          />
+        </div>
+      </CardContent>
+    </Card>
+  );
+}
+
+function AccountDeletionCard() {
   const [confirmText, setConfirmText] = useState('');
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
   const [showForm, setShowForm] = useState(false);
+  const [status, setStatus] = useState<AccountDeletionStatus | null>(null);
+
+  useEffect(() => {
+    api.getDeletionStatus().then(setStatus).catch(() => setStatus(null));
+  }, []);
+
+  const handleCancel = async () => {
+    setError(null);
+    setLoading(true);
+    try {
+      setStatus(await api.cancelDeletion());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : '계정 삭제 취소에 실패했습니다.');
+    } finally {
+      setLoading(false);
+    }
+  };
 
   const handleDelete = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -65,8 +626,22 @@ function AccountDeletionCard({ onDeleted }: { onDeleted: () => void }) {
 
     setLoading(true);
     try {
-      await api.deleteAccount();
-      onDeleted();
+      const preview = await api.getDeletionPreview();
+      if (preview.legalHold) {
+        setError('법적 보존 조치 중인 데이터가 있어 지금은 계정을 삭제할 수 없습니다.');
+        return;
+      }
+      const summary = [
+        `연결된 대화 ${preview.conversations}개 (연결 해제)`,
+        `수신 메시지 ${preview.inboundMessages}개, 발신 메시지 ${preview.outboundMessages}개`,
+        `페어링 코드 ${preview.pairingCodes}개, 플러그인 세션 ${preview.sessions}개`,
+        `로그인 연동: ${preview.oauthProviders.length > 0 ? preview.oauthProviders.join(', ') : '없음'}`,
+      ].join('\n');
+      if (!confirm(`다음 데이터가 삭제됩니다. 계속하시겠습니까?\n\n${summary}`)) return;
+
+      setStatus(await api.deleteAccount(preview.previewToken));
+      setShowForm(false);
+      setConfirmText('');
     } catch (err) {
       setError(err instanceof Error ? err.message : '계정 삭제에 실패했습니다.');
     } finally {
@@ -82,11 +657,34 @@ function AccountDeletionCard({ onDeleted }: { onDeleted: () => void }) {
           계정 삭제
         </CardTitle>
         <CardDescription>
-          계정을 삭제하면 모든 데이터가 영구적으로 삭제되며 복구할 수 없습니다.
+          삭제를 요청하면 계정 사용이 중지되고, 7일 후 모든 데이터가 영구적으로 삭제됩니다.
         </CardDescription>
       </CardHeader>
       <CardContent>
-        {!showForm ? (
+        {status?.scheduled && status.deletionScheduledAt ? (
+          <div className="space-y-4">
+            <div className="flex items-start gap-2 rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+              <Clock className="mt-0.5 h-4 w-4 flex-shrink-0" />
+              <div>
+                <p className="font-medium">계정 삭제가 예약되었습니다.</p>
+                <p className="mt-1">
+                  {new Date(status.deletionScheduledAt).toLocaleString('ko-KR')}에 계정이 영구 삭제됩니다.
+                  그때까지 계정 사용이 중지됩니다.
+                </p>
+              </div>
+            </div>
+
+            {error && (
+              <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
+                {error}
+              </div>
+            )}
+
+            <Button variant="outline" onClick={handleCancel} disabled={loading}>
+              {loading ? '취소 중...' : '삭제 요청 취소'}
+            </Button>
+          </div>
+        ) : !showForm ? (
           <Button
             variant="destructive"
             onClick={() => setShowForm(true)}
@@ -98,9 +696,10 @@ function AccountDeletionCard({ onDeleted }: { onDeleted: () => void }) {
             <div className="flex items-start gap-2 rounded-lg border border-destructive/50 bg-destructive/10 p-3 text-sm text-destructive">
               <AlertTriangle className="mt-0.5 h-4 w-4 flex-shrink-0" />
               <div>
-                <p className="font-medium">주의: 이 작업은 되돌릴 수 없습니다!</p>
+                <p className="font-medium">주의: 7일 후에는 되돌릴 수 없습니다!</p>
                 <p className="mt-1">
-                  계정 삭제 시 모든 연결, 메시지 기록, API 토큰이 영구적으로 삭제됩니다.
+                  유예 기간이 지나면 모든 연결, 메시지 기록, API 토큰이 영구적으로 삭제됩니다.
+                  그 전까지는 이 페이지에서 삭제 요청을 취소할 수 있습니다.
                 </p>
               </div>
             </div>
@@ -142,7 +741,7 @@ function AccountDeletionCard({ onDeleted }: { onDeleted: () => void }) {
                 variant="destructive"
                 disabled={loading || confirmText !== '계정 삭제'}
               >
-                {loading ? '삭제 중...' : '계정 영구 삭제'}
+                {loading ? '요청 중...' : '계정 삭제 요청'}
               </Button>
             </div>
           </form>
@@ -1,8 +1,19 @@
 import React, { useEffect, useState } from 'react';
-import { Copy, RefreshCw, Eye, EyeOff, AlertTriangle } from 'lucide-react';
+import { Copy, RefreshCw, Eye, EyeOff, AlertTriangle, CheckCircle2, XCircle, Send } from 'lucide-react';
 import { Button } from '../components/ui/button';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '../components/ui/card';
-import { api, type TokenResponse } from '../lib/api';
+import {
+  api,
+  type TestMessageResult,
+  type TestMessageStageName,
+  type TokenResponse,
+} from '../lib/api';
+
+const testStageLabels: Record<TestMessageStageName, string> = {
+  inject: '테스트 메시지 생성',
+  publish: '이벤트 발행',
+  deliver: '플러그인 수신 확인',
+};
 
 export default function TokenPage() {
   const [tokenData, setTokenData] = useState<TokenResponse | null>(null);
@@ -11,6 +22,9 @@ export default function TokenPage() {
   const [showToken, setShowToken] = useState(false);
   const [copied, setCopied] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  const [testing, setTesting] = useState(false);
+  const [testResult, setTestResult] = useState<TestMessageResult | null>(null);
+  const [testError, setTestError] = useState<string | null>(null);
 
   useEffect(() => {
     loadToken();
@@ -46,6 +60,19 @@ export default function TokenPage() {
     }
   };
 
+  const handleTestMessage = async () => {
+    setTesting(true);
+    setTestError(null);
+    setTestResult(null);
+    try {
+      setTestResult(await api.sendTestMessage());
+    } catch (err) {
+      setTestError(err instanceof Error ? err.message : '연결 테스트에 실패했습니다.');
+    } finally {
+      setTesting(false);
+    }
+  };
+
   const copyToken = async () => {
     if (!tokenData?.token) return;
 
@@ -159,6 +186,48 @@ export default function TokenPage() {
         </CardContent>
       </Card>
 
+      <Card>
+        <CardHeader>
+          <CardTitle>연결 테스트</CardTitle>
+          <CardDescription>
+            테스트 메시지를 보내 플러그인이 메시지를 받는지 확인합니다. 답장할 필요는 없습니다.
+          </CardDescription>
+        </CardHeader>
+        <CardContent className="space-y-4">
+          <Button onClick={handleTestMessage} disabled={testing} variant="outline" className="w-full">
+            <Send className={`mr-2 h-4 w-4 ${testing ? 'animate-pulse' : ''}`} />
+            {testing ? '테스트 중... (최대 10초)' : '테스트 메시지 보내기'}
+          </Button>
+
+          {testError && (
+            <div className="rounded-lg border border-destructive/50 bg-destructive/10 p-4 text-destructive">
+              {testError}
+            </div>
+          )}
+
+          {testResult && (
+            <ul className="space-y-2">
+              {testResult.stages.map((stage) => (
+                <li key={stage.name} className="flex items-start gap-2 text-sm">
+                  {stage.ok ? (
+                    <CheckCircle2 className="mt-0.5 h-4 w-4 flex-shrink-0 text-green-600" />
+                  ) : (
+                    <XCircle className="mt-0.5 h-4 w-4 flex-shrink-0 text-destructive" />
+                  )}
+                  <div>
+                    <p className="font-medium">
+                      {testStageLabels[stage.name] ?? stage.name}
+                      <span className="ml-2 text-xs text-muted-foreground">{stage.durationMs}ms</span>
+                    </p>
+                    {stage.detail && <p className="text-muted-foreground">{stage.detail}</p>}
+                  </div>
+                </li>
+              ))}
+            </ul>
+          )}
+        </CardContent>
+      </Card>
+
       <Card>
         <CardHeader>
           <CardTitle>사용 방법</CardTitle>
@@ -0,0 +1,8 @@
+// Package public embeds the compiled admin and portal UIs so the server
+// binary does not depend on static files next to it.
+package public
+
+import "embed"
+
+//go:embed admin portal
+var FS embed.FS